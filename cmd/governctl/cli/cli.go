@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+// Package cli assembles the command tree of governctl.
+package cli
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/unikraft/governance/cmd/governctl/compat"
+	"github.com/unikraft/governance/cmd/governctl/discord"
+	"github.com/unikraft/governance/cmd/governctl/docs"
+	"github.com/unikraft/governance/cmd/governctl/doctor"
+	"github.com/unikraft/governance/cmd/governctl/issue"
+	"github.com/unikraft/governance/cmd/governctl/label"
+	"github.com/unikraft/governance/cmd/governctl/pr"
+	"github.com/unikraft/governance/cmd/governctl/repo"
+	"github.com/unikraft/governance/cmd/governctl/report"
+	"github.com/unikraft/governance/cmd/governctl/team"
+	"github.com/unikraft/governance/cmd/governctl/templates"
+	versioncmd "github.com/unikraft/governance/cmd/governctl/version"
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/version"
+)
+
+type GovernCtl struct{}
+
+// New returns the root command of governctl with every subcommand.  The global
+// flags of config.Config are attributed separately, with
+// cmdfactory.AttributeFlags.
+func New() *cobra.Command {
+	cmd, err := cmdutils.New(&GovernCtl{}, cobra.Command{
+		Use:   "governctl COMMAND",
+		Short: `Govern the Unikraft Open-Source Project GitHub Organization`,
+		Long: heredoc.Docf(`
+		Govern the Unikraft Open-Source Project GitHub Organization
+
+		The utility program governctl is intended to be used by maintainers,
+		reviewers, team members, staff and contributors to ease repetitive
+		maintenance tasks within the Unikraft Open-Source Project.
+
+		VERSION
+		  %s`, version.String()),
+		CompletionOptions: cobra.CompletionOptions{
+			HiddenDefaultCmd: true,
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Subcommands
+	cmd.AddGroup(&cobra.Group{ID: "pr", Title: "PULL REQUEST COMMANDS"})
+	cmd.AddCommand(pr.New())
+
+	cmd.AddGroup(&cobra.Group{ID: "issue", Title: "ISSUE COMMANDS"})
+	cmd.AddCommand(issue.New())
+
+	cmd.AddGroup(&cobra.Group{ID: "team", Title: "TEAM COMMANDS"})
+	cmd.AddCommand(team.New())
+
+	cmd.AddGroup(&cobra.Group{ID: "label", Title: "LABEL COMMANDS"})
+	cmd.AddCommand(label.New())
+
+	cmd.AddGroup(&cobra.Group{ID: "repo", Title: "REPOSITORY COMMANDS"})
+	cmd.AddCommand(repo.New())
+
+	cmd.AddGroup(&cobra.Group{ID: "discord", Title: "DISCORD COMMANDS"})
+	cmd.AddCommand(discord.New())
+
+	cmd.AddGroup(&cobra.Group{ID: "report", Title: "REPORT COMMANDS"})
+	cmd.AddCommand(report.New())
+
+	cmd.AddGroup(&cobra.Group{ID: "templates", Title: "TEMPLATE COMMANDS"})
+	cmd.AddCommand(templates.New())
+
+	cmd.AddGroup(&cobra.Group{ID: "docs", Title: "DOCUMENTATION COMMANDS"})
+	cmd.AddCommand(docs.New())
+
+	cmd.AddCommand(doctor.New())
+	cmd.AddCommand(versioncmd.New())
+
+	// Deprecated aliases of the previous command tree
+	cmd.AddCommand(compat.NewSyncPR())
+	cmd.AddCommand(compat.NewSyncTeams())
+
+	return cmd
+}
+
+func (*GovernCtl) Run(_ context.Context, _ []string) error {
+	return pflag.ErrHelp
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package compat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"kraftkit.sh/log"
+)
+
+// forward warns that the deprecated command is going to be removed and runs
+// the equivalent invocation, given as the arguments of the root command, in
+// its place.
+func forward(ctx context.Context, root *cobra.Command, deprecated string, argv []string) error {
+	log.G(ctx).Warnf(
+		"governctl %s is deprecated and will be removed after two releases, use instead: governctl %s",
+		deprecated,
+		strings.Join(argv, " "),
+	)
+
+	target, rest, err := root.Find(argv)
+	if err != nil {
+		return fmt.Errorf("could not find the replacement of %s: %w", deprecated, err)
+	}
+
+	if err := target.ParseFlags(rest); err != nil {
+		return err
+	}
+
+	args := target.Flags().Args()
+	if err := target.ValidateArgs(args); err != nil {
+		return err
+	}
+
+	target.SetContext(ctx)
+
+	if target.PreRunE != nil {
+		if err := target.PreRunE(target, args); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case target.RunE != nil:
+		return target.RunE(target, args)
+	case target.Run != nil:
+		target.Run(target, args)
+		return nil
+	}
+
+	return fmt.Errorf("replacement of %s is not runnable", deprecated)
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package compat
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSyncPRTranslate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    SyncPR
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "every repository",
+			opts: SyncPR{NumMaintainers: 1, NumReviewers: 1, Org: "unikraft"},
+			want: []string{"pr", "sync", "reviewers", "--all", "--org=unikraft", "--num-maintainers=1", "--num-reviewers=1"},
+		},
+		{
+			name: "every repository of the global organisation",
+			opts: SyncPR{NumMaintainers: 1, NumReviewers: 1},
+			want: []string{"pr", "sync", "reviewers", "--all", "--num-maintainers=1", "--num-reviewers=1"},
+		},
+		{
+			name: "single repository",
+			opts: SyncPR{NumMaintainers: 2, NumReviewers: 3, Org: "unikraft"},
+			args: []string{"app-nginx"},
+			want: []string{"pr", "sync", "reviewers", "--all", "--org=unikraft", "--num-maintainers=2", "--num-reviewers=3", "app-nginx"},
+		},
+		{
+			name: "single pull request",
+			opts: SyncPR{NumMaintainers: 1, NumReviewers: 2, NoLabels: true},
+			args: []string{"unikraft", "1078"},
+			want: []string{"pr", "sync", "reviewers", "--num-maintainers=1", "--num-reviewers=2", "unikraft/unikraft/1078"},
+		},
+		{
+			name:    "non-numeric pull request",
+			opts:    SyncPR{},
+			args:    []string{"unikraft", "latest"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.opts.translate("unikraft", tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("translate() = %v, want error", got)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("translate() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("translate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncTeamsTranslate(t *testing.T) {
+	got := (&SyncTeams{Org: "unikraft"}).translate()
+
+	if want := []string{"team", "sync", "--org=unikraft"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("translate() = %v, want %v", got, want)
+	}
+
+	got = (&SyncTeams{}).translate()
+
+	if want := []string{"team", "sync"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("translate() = %v, want %v", got, want)
+	}
+}
+
+func TestHelpShowsMapping(t *testing.T) {
+	for _, cmd := range []*cobra.Command{NewSyncPR(), NewSyncTeams()} {
+		if !strings.Contains(cmd.Long, "Deprecated alias") || !strings.Contains(cmd.Long, "removed after two") {
+			t.Errorf("%s: help does not announce the deprecation:\n%s", cmd.Name(), cmd.Long)
+		}
+	}
+
+	if long := NewSyncPR().Long; !strings.Contains(long, "sync-pr REPO PRID             pr sync reviewers ORG/REPO/PRID") {
+		t.Errorf("sync-pr: help does not show the mapping of arguments:\n%s", long)
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package compat_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+
+	"github.com/unikraft/governance/cmd/governctl/cli"
+	"github.com/unikraft/governance/internal/config"
+)
+
+// newRoot returns the command tree of governctl with its global flags, whose
+// replacements of the aliases record the arguments and flags they are run with
+// instead of running.
+func newRoot(t *testing.T, cfg *config.Config, ran *[]string) *cobra.Command {
+	t.Helper()
+
+	root := cli.New()
+	if err := cmdfactory.AttributeFlags(root, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	record := func(cmd *cobra.Command, args []string) error {
+		var flags []string
+		cmd.Flags().Visit(func(f *pflag.Flag) {
+			flags = append(flags, "--"+f.Name+"="+f.Value.String())
+		})
+
+		*ran = append(append([]string{cmd.CommandPath()}, flags...), args...)
+		return nil
+	}
+
+	for _, path := range [][]string{{"pr", "sync", "reviewers"}, {"team", "sync"}} {
+		cmd, _, err := root.Find(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cmd.Run = nil
+		cmd.RunE = record
+	}
+
+	return root
+}
+
+func TestForward(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "sync-pr of a repository",
+			args: []string{"sync-pr", "-A", "2", "--no-labels", "app-nginx"},
+			want: []string{"governctl pr sync reviewers", "--all=true", "--num-maintainers=2", "--num-reviewers=1", "app-nginx"},
+		},
+		{
+			name: "sync-pr of a repository of an organisation",
+			args: []string{"sync-pr", "--org", "kraftkit", "app-nginx"},
+			want: []string{"governctl pr sync reviewers", "--all=true", "--num-maintainers=1", "--num-reviewers=1", "--org=kraftkit", "app-nginx"},
+		},
+		{
+			name: "sync-pr of a pull request",
+			args: []string{"sync-pr", "-R", "3", "unikraft", "1078"},
+			want: []string{"governctl pr sync reviewers", "--num-maintainers=1", "--num-reviewers=3", "unikraft/unikraft/1078"},
+		},
+		{
+			name: "sync-pr of a pull request of the global organisation",
+			args: []string{"sync-pr", "--github-org", "kraftkit", "kraftkit", "42"},
+			want: []string{"governctl pr sync reviewers", "--num-maintainers=1", "--num-reviewers=1", "kraftkit/kraftkit/42"},
+		},
+		{
+			name: "sync-teams",
+			args: []string{"sync-teams", "--org", "kraftkit"},
+			want: []string{"governctl team sync", "--org=kraftkit"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran []string
+
+			cfg := &config.Config{}
+			root := newRoot(t, cfg, &ran)
+
+			// team sync reads the definitions of the teams before it is run.
+			root.SetArgs(append([]string{"--teams-dir", t.TempDir()}, tt.args...))
+
+			cfgm, err := kitcfg.NewConfigManager(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := root.ExecuteContext(kitcfg.WithConfigManager(context.Background(), cfgm)); err != nil {
+				t.Fatalf("Execute() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(ran, tt.want) {
+				t.Errorf("ran %v, want %v", ran, tt.want)
+			}
+		})
+	}
+}
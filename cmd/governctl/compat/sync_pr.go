@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package compat
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+)
+
+type SyncPR struct {
+	NoLabels       bool   `long:"no-labels" usage:"Do not warn that labels are no longer synchronised"`
+	NumMaintainers int    `long:"num-maintainers" short:"A" usage:"Number of maintainers for the PR" default:"1"`
+	NumReviewers   int    `long:"num-reviewers" short:"R" usage:"Number of reviewers for the PR" default:"1"`
+	Org            string `long:"org" usage:"Set the GitHub organisation whose pull requests are synchronised (deprecated, use --github-org)"`
+
+	root *cobra.Command
+}
+
+func NewSyncPR() *cobra.Command {
+	cmd, err := cmdutils.New(&SyncPR{}, cobra.Command{
+		Use:    "sync-pr [OPTIONS] [REPO [PRID]]",
+		Short:  "Deprecated alias of pr sync reviewers",
+		Args:   cobra.MaximumNArgs(2),
+		Hidden: true,
+		Long: heredoc.Doc(`
+		Deprecated alias of pr sync reviewers, which will be removed after two
+		releases.  The arguments and flags are translated as follows:
+
+		  sync-pr                       pr sync reviewers --all
+		  sync-pr REPO                  pr sync reviewers --all REPO
+		  sync-pr REPO PRID             pr sync reviewers ORG/REPO/PRID
+		  --org=ORG                     --org=ORG, deprecated by --github-org
+		  -A, --num-maintainers=N       --num-maintainers=N
+		  -R, --num-reviewers=N         --num-reviewers=N
+		  --no-labels                   dropped, as pr sync reviewers never
+		                                synchronises labels
+
+		Labels are synchronised with pr sync labels instead.
+		`),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+// Pre records the root command, from which the replacement is run.
+func (opts *SyncPR) Pre(cmd *cobra.Command, _ []string) error {
+	opts.root = cmd.Root()
+	return nil
+}
+
+// translate returns the arguments of the root command which are equivalent to
+// invoking sync-pr with the provided arguments, where org is the organisation
+// of a single pull request.
+func (opts *SyncPR) translate(org string, args []string) ([]string, error) {
+	argv := []string{"pr", "sync", "reviewers"}
+
+	if len(args) < 2 {
+		argv = append(argv, "--all")
+
+		if opts.Org != "" {
+			argv = append(argv, "--org="+opts.Org)
+		}
+	}
+
+	argv = append(argv,
+		"--num-maintainers="+strconv.Itoa(opts.NumMaintainers),
+		"--num-reviewers="+strconv.Itoa(opts.NumReviewers),
+	)
+
+	switch len(args) {
+	case 0:
+	case 1:
+		argv = append(argv, args[0])
+	case 2:
+		if _, err := strconv.Atoi(args[1]); err != nil {
+			return nil, fmt.Errorf("PR ID is not numeric")
+		}
+
+		argv = append(argv, fmt.Sprintf("%s/%s/%s", org, args[0], args[1]))
+	default:
+		return nil, fmt.Errorf("expected at most REPO and PRID")
+	}
+
+	return argv, nil
+}
+
+func (opts *SyncPR) Run(ctx context.Context, args []string) error {
+	argv, err := opts.translate(kitcfg.G[config.Config](ctx).EffectiveGithubOrg(opts.Org), args)
+	if err != nil {
+		return err
+	}
+
+	if !opts.NoLabels {
+		log.G(ctx).Warn("governctl sync-pr no longer synchronises labels, use governctl pr sync labels instead")
+	}
+
+	return forward(ctx, opts.root, "sync-pr", argv)
+}
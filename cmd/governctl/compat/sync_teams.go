@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package compat
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+)
+
+type SyncTeams struct {
+	Org string `long:"org" usage:"Set the GitHub organisation that should have teams managed (deprecated, use --github-org)"`
+
+	root *cobra.Command
+}
+
+func NewSyncTeams() *cobra.Command {
+	cmd, err := cmdutils.New(&SyncTeams{}, cobra.Command{
+		Use:    "sync-teams [OPTIONS]",
+		Short:  "Deprecated alias of team sync",
+		Args:   cobra.NoArgs,
+		Hidden: true,
+		Long: heredoc.Doc(`
+		Deprecated alias of team sync, which will be removed after two releases.
+		The flags are translated as follows:
+
+		  sync-teams                    team sync
+		  --org=ORG                     --org=ORG, deprecated by --github-org
+		`),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+// Pre records the root command, from which the replacement is run.
+func (opts *SyncTeams) Pre(cmd *cobra.Command, _ []string) error {
+	opts.root = cmd.Root()
+	return nil
+}
+
+// translate returns the arguments of the root command which are equivalent to
+// invoking sync-teams.
+func (opts *SyncTeams) translate() []string {
+	argv := []string{"team", "sync"}
+
+	if opts.Org != "" {
+		argv = append(argv, "--org="+opts.Org)
+	}
+
+	return argv
+}
+
+func (opts *SyncTeams) Run(ctx context.Context, _ []string) error {
+	return forward(ctx, opts.root, "sync-teams", opts.translate())
+}
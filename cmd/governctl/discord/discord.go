@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package discord
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"kraftkit.sh/cmdfactory"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+)
+
+type Discord struct{}
+
+func New() *cobra.Command {
+	cmd, err := cmdutils.New(&Discord{}, cobra.Command{
+		Use:    "discord SUBCOMMAND",
+		Short:  "Manage the Discord guild",
+		Hidden: true,
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "discord",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	cmd.AddCommand(NewSync())
+
+	return cmd
+}
+
+func (opts *Discord) Run(_ context.Context, args []string) error {
+	return pflag.ErrHelp
+}
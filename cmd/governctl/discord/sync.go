@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package discord
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/discord"
+	"github.com/unikraft/governance/internal/team"
+)
+
+type Sync struct {
+	Output string `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+
+	client *discord.Client
+	teams  []*team.Team
+}
+
+func NewSync() *cobra.Command {
+	cmd, err := cmdutils.New(&Sync{}, cobra.Command{
+		Use:   "sync",
+		Short: "Synchronise the roles and channels of SIGs on Discord",
+		Args:  cobra.NoArgs,
+		Long: heredoc.Doc(`
+		Create a role as well as a text and a voice channel for every SIG on the
+		Discord guild and set the topic of the text channel to the description of
+		the SIG.
+
+		The role is assigned to the maintainers, reviewers and members of the SIG
+		by the Discord username of their user definition and removed from every
+		other member of the guild.
+		`),
+		Example: heredoc.Doc(`
+		# Preview the changes to the Discord guild
+		governctl --dry-run discord sync
+
+		# Synchronise the roles and channels of every SIG
+		governctl discord sync --discord-token $TOKEN --discord-guild $GUILD
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "discord",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+// Validate rejects unknown output formats of the plan.
+func (opts *Sync) Validate(ctx context.Context) error {
+	return cmdutils.ValidatePlanOutput(ctx, opts.Output)
+}
+
+func (opts *Sync) Pre(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	client, err := discord.NewClient(
+		kitcfg.G[config.Config](ctx).DiscordToken,
+		kitcfg.G[config.Config](ctx).DiscordGuildID,
+	)
+	if err != nil {
+		return err
+	}
+
+	opts.client = client
+
+	opts.teams, err = team.NewListOfTeamsFromPath(
+		nil,
+		kitcfg.G[config.Config](ctx).EffectiveGithubOrg(""),
+		kitcfg.G[config.Config](ctx).TeamsDir,
+	)
+	if err != nil {
+		return fmt.Errorf("could not populate teams: %s", err)
+	}
+
+	return nil
+}
+
+func (opts *Sync) Run(ctx context.Context, args []string) error {
+	sigs, err := discord.SIGsFromTeams(opts.teams)
+	if err != nil {
+		return err
+	}
+
+	plan, err := opts.client.Plan(ctx, sigs)
+	if err != nil {
+		return err
+	}
+
+	if !kitcfg.G[config.Config](ctx).DryRun {
+		return opts.client.Apply(ctx, plan)
+	}
+
+	if opts.Output != cmdutils.PlanOutputJSON {
+		writeReport(iostreams.G(ctx).Out, plan)
+	}
+
+	return cmdutils.WritePlan(ctx, opts.Output, plan)
+}
+
+// writeReport writes the changes to the roles, their members and the channels
+// of the guild to the writer such that they can be reviewed before being
+// applied.
+func writeReport(w io.Writer, plan *discord.Plan) {
+	for _, change := range plan.Roles {
+		if change.Empty() && len(change.Unknown) == 0 {
+			fmt.Fprintf(w, "@%s: up to date\n", change.Role)
+			continue
+		}
+
+		if change.Create {
+			fmt.Fprintf(w, "+ @%s\n", change.Role)
+		} else {
+			fmt.Fprintf(w, "@%s:\n", change.Role)
+		}
+
+		for _, username := range change.Add {
+			fmt.Fprintf(w, "  + %s\n", username)
+		}
+
+		for _, username := range change.Remove {
+			fmt.Fprintf(w, "  - %s\n", username)
+		}
+
+		for _, username := range change.Unknown {
+			fmt.Fprintf(w, "  ? %s (not a member of the guild)\n", username)
+		}
+	}
+
+	for _, change := range plan.Channels {
+		if change.Create {
+			fmt.Fprintf(w, "+ #%s (%s)\n", change.Channel, change.Type)
+		} else {
+			fmt.Fprintf(w, "~ #%s (%s): topic %q\n", change.Channel, change.Type, change.Topic)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package discord
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/discord"
+	"github.com/unikraft/governance/internal/team"
+	"github.com/unikraft/governance/internal/user"
+)
+
+func TestSyncRunDryRun(t *testing.T) {
+	var writes []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writes = append(writes, r.Method+" "+r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/guilds/42/roles":
+			fmt.Fprint(w, `[{"id":"1","name":"sig-arch"}]`)
+		case "/guilds/42/channels":
+			fmt.Fprint(w, `[
+				{"id":"10","name":"sig-arch","type":0,"topic":"Old topic"},
+				{"id":"11","name":"sig-arch","type":2}
+			]`)
+		case "/guilds/42/members":
+			fmt.Fprint(w, `[
+				{"user":{"id":"100","username":"jane"},"roles":["1"]},
+				{"user":{"id":"101","username":"bob"},"roles":[]},
+				{"user":{"id":"102","username":"eve"},"roles":["1"]}
+			]`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+	ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+	client, err := discord.NewClient("token", "42", discord.WithEndpoint(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &Sync{
+		Output: "text",
+		client: client,
+		teams: []*team.Team{
+			{
+				Name:        "arch",
+				Type:        team.SIGTeam,
+				Description: "Architecture support",
+				Maintainers: []user.User{{Github: "jane", Discord: "jane"}},
+				Members:     []user.User{{Github: "bob", Discord: "bob"}, {Github: "ghost", Discord: "ghost"}},
+			},
+			{
+				Name: "net",
+				Type: team.SIGTeam,
+			},
+		},
+	}
+
+	if err := opts.Run(ctx, nil); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := "@sig-arch:\n" +
+		"  + bob\n" +
+		"  - eve\n" +
+		"  ? ghost (not a member of the guild)\n" +
+		"+ @sig-net\n" +
+		"~ #sig-arch (text): topic \"Architecture support\"\n" +
+		"+ #sig-net (text)\n" +
+		"+ #sig-net (voice)\n"
+	if got := out.String(); got != want {
+		t.Errorf("unexpected report:\n%s\nwant:\n%s", got, want)
+	}
+
+	if len(writes) != 0 {
+		t.Errorf("dry-run performed writes: %q", writes)
+	}
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package docs
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+)
+
+// EnvVar is a single environment variable which can be used in place of a
+// command-line flag.
+type EnvVar struct {
+	Name     string
+	Flag     string
+	Default  string
+	Usage    string
+	Commands []string
+}
+
+// EnvReference collects every environment variable declared via the `env:`
+// tag on the options struct of the root command, any of its subcommands and
+// the provided global options.  Each variable is listed once, together with
+// all commands which accept it, and the result is sorted by name.
+func EnvReference(root *cobra.Command, global any) []EnvVar {
+	vars := make(map[string]*EnvVar)
+
+	add := func(path string, obj any) {
+		for _, v := range structEnvVars(obj) {
+			existing, ok := vars[v.Name]
+			if !ok {
+				v := v
+				existing = &v
+				vars[v.Name] = existing
+			}
+
+			existing.Commands = append(existing.Commands, path)
+		}
+	}
+
+	if global != nil {
+		add(root.Name(), global)
+	}
+
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		if obj := cmdutils.Options(cmd); obj != nil {
+			add(cmd.CommandPath(), obj)
+		}
+
+		for _, c := range cmd.Commands() {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	ret := make([]EnvVar, 0, len(vars))
+	for _, v := range vars {
+		sort.Strings(v.Commands)
+		ret = append(ret, *v)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+
+	return ret
+}
+
+// structEnvVars returns the environment variables declared by the fields of
+// the provided struct or pointer to a struct.
+func structEnvVars(obj any) []EnvVar {
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var vars []EnvVar
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		env := field.Tag.Get("env")
+		if env == "" {
+			continue
+		}
+
+		v := EnvVar{
+			Name:    env,
+			Default: field.Tag.Get("default"),
+			Usage:   field.Tag.Get("usage"),
+		}
+
+		if long := field.Tag.Get("long"); long != "" {
+			v.Flag = "--" + long
+		}
+
+		vars = append(vars, v)
+	}
+
+	return vars
+}
+
+// WriteEnvReference renders the provided environment variables as a markdown
+// table.
+func WriteEnvReference(w io.Writer, vars []EnvVar) error {
+	var b strings.Builder
+
+	b.WriteString("# Environment variables\n\n")
+	b.WriteString("Every environment variable can be used in place of the equivalent flag.\n\n")
+	b.WriteString("| Variable | Flag | Default | Commands | Description |\n")
+	b.WriteString("|----------|------|---------|----------|-------------|\n")
+
+	for _, v := range vars {
+		var commands []string
+		for _, c := range v.Commands {
+			commands = append(commands, "`"+c+"`")
+		}
+
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n",
+			v.Name,
+			code(v.Flag),
+			code(v.Default),
+			strings.Join(commands, ", "),
+			strings.ReplaceAll(v.Usage, "|", "\\|"),
+		)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// code formats a non-empty value as inline code.
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "`" + s + "`"
+}
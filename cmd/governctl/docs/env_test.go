@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package docs_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+
+	"github.com/unikraft/governance/cmd/governctl/cli"
+	"github.com/unikraft/governance/cmd/governctl/docs"
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+)
+
+// newRoot returns the command tree of governctl with its global flags.
+func newRoot(t *testing.T) *cobra.Command {
+	t.Helper()
+
+	root := cli.New()
+	if err := cmdfactory.AttributeFlags(root, &config.Config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	return root
+}
+
+// envTags returns the `env:` tags of all fields of the provided struct.
+func envTags(obj any) []string {
+	var tags []string
+
+	t := reflect.TypeOf(obj).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		if env := t.Field(i).Tag.Get("env"); env != "" {
+			tags = append(tags, env)
+		}
+	}
+
+	return tags
+}
+
+func TestEnvReferenceListsEveryVariableOnce(t *testing.T) {
+	root := newRoot(t)
+
+	var b bytes.Buffer
+	if err := docs.WriteEnvReference(&b, docs.EnvReference(root, &config.Config{})); err != nil {
+		t.Fatal(err)
+	}
+
+	out := b.String()
+
+	want := envTags(&config.Config{})
+
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		if obj := cmdutils.Options(cmd); obj != nil {
+			want = append(want, envTags(obj)...)
+		}
+
+		for _, c := range cmd.Commands() {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	if len(want) == 0 {
+		t.Fatal("expected at least one environment variable")
+	}
+
+	for _, env := range want {
+		if n := strings.Count(out, "| `"+env+"` |"); n != 1 {
+			t.Errorf("expected %s to appear exactly once, got %d", env, n)
+		}
+	}
+}
+
+func TestEnvReferenceDeterministic(t *testing.T) {
+	var first, second bytes.Buffer
+
+	if err := docs.WriteEnvReference(&first, docs.EnvReference(newRoot(t), &config.Config{})); err != nil {
+		t.Fatal(err)
+	}
+	if err := docs.WriteEnvReference(&second, docs.EnvReference(newRoot(t), &config.Config{})); err != nil {
+		t.Fatal(err)
+	}
+
+	if first.String() != second.String() {
+		t.Errorf("expected identical output across runs:\n%s\n---\n%s", first.String(), second.String())
+	}
+}
+
+func TestEnvReferenceSharedVariable(t *testing.T) {
+	var shared *docs.EnvVar
+	for _, v := range docs.EnvReference(newRoot(t), nil) {
+		if v.Name == "GOVERN_COMMITTER_GLOBAL" {
+			v := v
+			shared = &v
+		}
+	}
+
+	if shared == nil {
+		t.Fatal("expected GOVERN_COMMITTER_GLOBAL in the reference")
+	}
+
+	want := []string{"governctl pr check commits", "governctl pr check license", "governctl pr check mergable", "governctl pr check patch", "governctl pr diff-stat", "governctl pr merge"}
+	if !reflect.DeepEqual(shared.Commands, want) {
+		t.Errorf("Commands = %v, want %v", shared.Commands, want)
+	}
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package docs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cpuguy83/go-md2man/v2/md2man"
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+)
+
+type Generate struct {
+	Format    string `long:"format" short:"f" usage:"Output format (man, markdown)" default:"markdown"`
+	OutputDir string `long:"output-dir" short:"o" usage:"Directory to write the generated documentation to" default:"docs"`
+
+	root *cobra.Command
+}
+
+func NewGenerate() *cobra.Command {
+	cmd, err := cmdutils.New(&Generate{}, cobra.Command{
+		Use:   "generate [OPTIONS]",
+		Short: "Generate the command and environment variable reference",
+		Args:  cobra.NoArgs,
+		Long: heredoc.Doc(`
+		Generate the reference documentation of every command, its flags and
+		a consolidated reference of all environment variables which can be
+		used in place of flags.
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "docs",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *Generate) Pre(cmd *cobra.Command, _ []string) error {
+	switch opts.Format {
+	case "man", "markdown":
+	default:
+		return fmt.Errorf("unsupported format '%s': expected 'man' or 'markdown'", opts.Format)
+	}
+
+	opts.root = cmd.Root()
+
+	return nil
+}
+
+func (opts *Generate) Run(ctx context.Context, _ []string) error {
+	if err := os.MkdirAll(opts.OutputDir, 0o755); err != nil {
+		return fmt.Errorf("could not create output directory: %w", err)
+	}
+
+	// Command groups are hidden from the help output as they are listed
+	// separately, but they should be documented.
+	unhideGroups(opts.root)
+
+	// Omit the generation date so that the output is reproducible.
+	opts.root.DisableAutoGenTag = true
+
+	var ref bytes.Buffer
+	if err := WriteEnvReference(&ref, EnvReference(opts.root, &config.Config{})); err != nil {
+		return fmt.Errorf("could not generate environment variable reference: %w", err)
+	}
+
+	var envFile string
+	var envContents []byte
+
+	switch opts.Format {
+	case "markdown":
+		if err := doc.GenMarkdownTree(opts.root, opts.OutputDir); err != nil {
+			return fmt.Errorf("could not generate markdown reference: %w", err)
+		}
+
+		envFile = filepath.Join(opts.OutputDir, "environment.md")
+		envContents = ref.Bytes()
+
+	case "man":
+		if err := doc.GenManTree(opts.root, &doc.GenManHeader{
+			Title:   "GOVERNCTL",
+			Section: "1",
+		}, opts.OutputDir); err != nil {
+			return fmt.Errorf("could not generate man pages: %w", err)
+		}
+
+		envFile = filepath.Join(opts.OutputDir, "governctl-environment.7")
+		envContents = md2man.Render(append(
+			[]byte("% GOVERNCTL-ENVIRONMENT 7\n\n"),
+			ref.Bytes()...,
+		))
+	}
+
+	if err := os.WriteFile(envFile, envContents, 0o644); err != nil {
+		return fmt.Errorf("could not write environment variable reference: %w", err)
+	}
+
+	log.G(ctx).
+		WithField("dir", opts.OutputDir).
+		WithField("format", opts.Format).
+		Info("generated reference documentation")
+
+	return nil
+}
+
+// unhideGroups makes all hidden commands with subcommands visible.
+func unhideGroups(cmd *cobra.Command) {
+	if cmd.HasSubCommands() {
+		cmd.Hidden = false
+	}
+
+	for _, c := range cmd.Commands() {
+		unhideGroups(c)
+	}
+}
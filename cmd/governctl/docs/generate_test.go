@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package docs_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// ensureRunnable provides a no-op Run to every command without one, since
+// cobra only documents runnable commands.
+func ensureRunnable(cmd *cobra.Command) {
+	if cmd.Run == nil && cmd.RunE == nil {
+		cmd.Run = func(*cobra.Command, []string) {}
+	}
+
+	for _, c := range cmd.Commands() {
+		ensureRunnable(c)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{
+			format: "markdown",
+			want:   []string{"governctl.md", "governctl_pr_merge.md", "environment.md"},
+		},
+		{
+			format: "man",
+			want:   []string{"governctl.1", "governctl-pr-merge.1", "governctl-environment.7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			dir := t.TempDir()
+			root := newRoot(t)
+			ensureRunnable(root)
+
+			root.SetArgs([]string{"docs", "generate", "--format", tt.format, "--output-dir", dir})
+			if err := root.ExecuteContext(context.Background()); err != nil {
+				t.Fatalf("Execute() unexpected error: %v", err)
+			}
+
+			for _, name := range tt.want {
+				if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+					t.Errorf("expected %s to be generated: %v", name, err)
+				}
+			}
+
+			// The global flags are documented alongside those of the commands.
+			page, err := os.ReadFile(filepath.Join(dir, tt.want[0]))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(page), "github-token") {
+				t.Errorf("expected %s to document the global --github-token", tt.want[0])
+			}
+		})
+	}
+}
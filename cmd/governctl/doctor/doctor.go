@@ -0,0 +1,454 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/label"
+	"github.com/unikraft/governance/internal/repo"
+	"github.com/unikraft/governance/internal/team"
+)
+
+type Doctor struct {
+	Org    string `long:"org" usage:"Set the GitHub organisation the definitions belong to (deprecated, use --github-org)"`
+	Output string `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of choice [text, json]" default:"text"`
+}
+
+// Status is the outcome of a single diagnosis.
+type Status string
+
+const (
+	StatusPass = Status("pass")
+	StatusWarn = Status("warn")
+	StatusFail = Status("fail")
+)
+
+const (
+	// ExitCodeWarn is the exit code used when no check has failed but at least
+	// one has warned.
+	ExitCodeWarn = 1
+
+	// ExitCodeFail is the exit code used when at least one check has failed.
+	ExitCodeFail = 2
+)
+
+// severity returns the rank of the status, where worse statuses have a higher
+// rank.
+func (status Status) severity() int {
+	switch status {
+	case StatusWarn:
+		return 1
+	case StatusFail:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// Result is the outcome of a single diagnosis along with a hint on how to
+// remedy it.
+type Result struct {
+	Check  string `json:"check"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+func New() *cobra.Command {
+	cmd, err := cmdutils.New(&Doctor{}, cobra.Command{
+		Use:   "doctor [OPTIONS]",
+		Short: "Diagnose common problems with the environment of governctl",
+		Long: heredoc.Docf(`
+		Diagnose common problems with the environment of governctl
+
+		Checks the binaries which governctl depends on, the GitHub credentials
+		and endpoint, the definition directories, the temporary directory and,
+		when running in GitHub Actions, the variables it provides.  Each check
+		passes, warns or fails with a hint on how to remedy it.
+
+		The exit code reflects the worst result: %d if a check warned and %d if a
+		check failed.`, ExitCodeWarn, ExitCodeFail),
+		Args: cobra.NoArgs,
+		Example: heredoc.Doc(`
+		# Diagnose the environment
+		governctl doctor
+
+		# Use as a pre-flight step of a workflow
+		governctl doctor --output=json
+		`),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	cmdutils.DeprecateOrg(cmd)
+
+	return cmd
+}
+
+// Validate rejects unknown output formats.
+func (opts *Doctor) Validate(_ context.Context) error {
+	if opts.Output != "text" && opts.Output != "json" {
+		return fmt.Errorf("unknown output format '%s': expected text or json", opts.Output)
+	}
+
+	return nil
+}
+
+func (opts *Doctor) Run(ctx context.Context, _ []string) error {
+	opts.Org = kitcfg.G[config.Config](ctx).EffectiveGithubOrg(opts.Org)
+
+	results := opts.diagnose(ctx)
+
+	if opts.Output == "json" {
+		buffer := &bytes.Buffer{}
+		encoder := json.NewEncoder(buffer)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return fmt.Errorf("could not marshal JSON response: %w", err)
+		}
+
+		fmt.Fprint(iostreams.G(ctx).Out, buffer.String())
+	} else {
+		render(ctx, results)
+	}
+
+	return diagnosisError(results)
+}
+
+// DiagnosisError is returned when at least one check has not passed.  It
+// carries ExitCodeFail or ExitCodeWarn as the exit code of the program,
+// depending on the worst status.
+type DiagnosisError struct {
+	Status Status
+	Count  int
+}
+
+func (e *DiagnosisError) Error() string {
+	verb := "warned"
+	if e.Status == StatusFail {
+		verb = "failed"
+	}
+
+	if e.Count == 1 {
+		return fmt.Sprintf("1 check %s", verb)
+	}
+
+	return fmt.Sprintf("%d checks %s", e.Count, verb)
+}
+
+// ExitCode implements cmdutils.ExitCoder.
+func (e *DiagnosisError) ExitCode() int {
+	if e.Status == StatusFail {
+		return ExitCodeFail
+	}
+
+	return ExitCodeWarn
+}
+
+// diagnosisError returns a DiagnosisError for the worst status of the results
+// or nil if every check has passed.
+func diagnosisError(results []Result) error {
+	status := worst(results)
+	if status == StatusPass {
+		return nil
+	}
+
+	err := &DiagnosisError{Status: status}
+	for _, result := range results {
+		if result.Status == status {
+			err.Count++
+		}
+	}
+
+	return err
+}
+
+// render prints the results for humans.
+func render(ctx context.Context, results []Result) {
+	cs := iostreams.G(ctx).ColorScheme()
+	out := iostreams.G(ctx).Out
+
+	for _, result := range results {
+		icon := cs.Green("✔")
+		switch result.Status {
+		case StatusWarn:
+			icon = cs.Yellow("!")
+		case StatusFail:
+			icon = cs.Red("✘")
+		}
+
+		fmt.Fprintf(out, "%s %s: %s\n", icon, result.Check, result.Detail)
+		if result.Hint != "" {
+			fmt.Fprintf(out, "  %s\n", result.Hint)
+		}
+	}
+}
+
+// worst returns the worst status of the provided results.
+func worst(results []Result) Status {
+	status := StatusPass
+	for _, result := range results {
+		if result.Status.severity() > status.severity() {
+			status = result.Status
+		}
+	}
+
+	return status
+}
+
+// diagnose runs every check in order.
+func (opts *Doctor) diagnose(ctx context.Context) []Result {
+	cfg := kitcfg.G[config.Config](ctx)
+
+	results := []Result{
+		checkBinary(ctx, "git", cfg.GitBinary, StatusFail, "install git or point --git-binary at it"),
+		checkBinary(ctx, "perl", "perl", StatusWarn, "install perl, which checkpatch requires"),
+		checkEndpoint(ctx, cfg),
+		checkToken(ctx, cfg),
+		checkDefinitions("teams", cfg.TeamsDir, "--teams-dir", func(path string) (int, error) {
+			teams, err := team.NewListOfTeamsFromPath(nil, opts.Org, path)
+			return len(teams), err
+		}),
+		checkDefinitions("repos", cfg.ReposDir, "--repos-dir", func(path string) (int, error) {
+			repos, err := repo.NewListOfReposFromPath(nil, opts.Org, path)
+			return len(repos), err
+		}),
+		checkDefinitions("labels", cfg.LabelsDir, "--labels-dir", func(path string) (int, error) {
+			labels, err := label.NewListOfLabelsFromPath(nil, opts.Org, path)
+			return len(labels), err
+		}),
+		checkTempDir(cfg.TempDir),
+		checkGithubActions(os.Getenv),
+	}
+
+	return results
+}
+
+// checkBinary checks that the binary can be executed and reports its version.
+// A missing binary results in the provided status.
+func checkBinary(ctx context.Context, name, binary string, missing Status, hint string) Result {
+	result := Result{Check: name}
+
+	out, err := exec.CommandContext(ctx, binary, "--version").Output()
+	if err != nil {
+		result.Status = missing
+		result.Detail = fmt.Sprintf("could not run %s: %s", binary, err)
+		result.Hint = hint
+
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Detail = binaryVersion(string(out))
+
+	return result
+}
+
+// binaryVersion returns the first non-empty line of the output of --version.
+func binaryVersion(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+
+	return "unknown version"
+}
+
+// checkEndpoint checks that the GitHub API is reachable.
+func checkEndpoint(ctx context.Context, cfg *config.Config) Result {
+	result := Result{Check: "github endpoint"}
+
+	endpoint := cfg.GithubEndpoint
+	if endpoint == "" {
+		endpoint = "https://api.github.com"
+	}
+
+	if err := ghapi.CheckEndpoint(ctx, cfg.GithubEndpoint, cfg.GithubSkipSSL, cfg.EffectiveGithubTimeout()); err != nil {
+		result.Status = StatusFail
+		result.Detail = err.Error()
+		result.Hint = "check --github-endpoint and the network, or --github-skip-ssl for self-signed certificates"
+
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Detail = endpoint + " is reachable"
+
+	return result
+}
+
+// checkToken checks that the GitHub credentials are accepted and that a
+// classic token has the scopes which governctl requires.
+func checkToken(ctx context.Context, cfg *config.Config) Result {
+	result := Result{Check: "github token"}
+
+	if cfg.GithubToken == "" && !cfg.HasGithubApp() {
+		result.Status = StatusWarn
+		result.Detail = "no credentials provided, requests are unauthenticated and heavily rate-limited"
+		result.Hint = "set GOVERN_GITHUB_TOKEN or the credentials of a GitHub App"
+
+		return result
+	}
+
+	ghClient, err := cmdutils.NewGithubClient(ctx, ghapi.WithReadOnly(true))
+	if err == nil {
+		var info *ghapi.AuthInfo
+		if info, err = ghClient.Authenticate(ctx); err == nil {
+			return tokenResult(result, info)
+		}
+	}
+
+	result.Status = StatusFail
+	result.Detail = err.Error()
+	result.Hint = "check that the token or the GitHub App credentials are valid and have not expired"
+
+	return result
+}
+
+// tokenResult reports the identity of the credentials and any missing scope.
+func tokenResult(result Result, info *ghapi.AuthInfo) Result {
+	result.Status = StatusPass
+
+	switch {
+	case info.App:
+		result.Detail = "authenticated as a GitHub App installation"
+		return result
+	case !info.ScopesKnown:
+		result.Detail = fmt.Sprintf("authenticated as %s with a fine-grained token", info.Login)
+		return result
+	}
+
+	result.Detail = fmt.Sprintf("authenticated as %s with scopes: %s", info.Login, strings.Join(info.Scopes, ", "))
+
+	var missing []string
+	if !info.HasScope("repo") {
+		missing = append(missing, "repo")
+	}
+	if !info.HasScope("admin:org", "write:org", "read:org") {
+		missing = append(missing, "read:org")
+	}
+
+	if len(missing) > 0 {
+		result.Status = StatusWarn
+		result.Hint = fmt.Sprintf("grant the token the %s scopes, which most commands require", strings.Join(missing, ", "))
+	}
+
+	return result
+}
+
+// checkDefinitions loads the definitions at the provided path with the
+// provided loader and reports how many there are.
+func checkDefinitions(kind, path, flag string, load func(string) (int, error)) Result {
+	result := Result{Check: kind}
+
+	if _, err := os.Stat(path); err != nil {
+		result.Status = StatusWarn
+		result.Detail = fmt.Sprintf("%s not found at %s", kind, path)
+		result.Hint = fmt.Sprintf("run from the root of the governance repository or set %s", flag)
+
+		return result
+	}
+
+	n, err := load(path)
+	if err != nil {
+		result.Status = StatusFail
+		result.Detail = err.Error()
+		result.Hint = fmt.Sprintf("fix the definitions at %s", path)
+
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Detail = fmt.Sprintf("%d %s defined at %s", n, kind, path)
+
+	return result
+}
+
+// checkTempDir checks that intermediate clones can be written to the
+// temporary directory.
+func checkTempDir(tempDir string) Result {
+	result := Result{Check: "temp dir"}
+
+	if tempDir == "" {
+		tempDir = os.TempDir()
+	}
+
+	dir, err := os.MkdirTemp(tempDir, "governctl-doctor-")
+	if err != nil {
+		result.Status = StatusFail
+		result.Detail = fmt.Sprintf("%s is not writable: %s", tempDir, err)
+		result.Hint = "set --temp-dir to a writable directory"
+
+		return result
+	}
+
+	os.RemoveAll(dir)
+
+	result.Status = StatusPass
+	result.Detail = tempDir + " is writable"
+
+	return result
+}
+
+// githubActionsVariables are the variables which GitHub Actions provides and
+// governctl relies upon in workflows.
+var githubActionsVariables = []string{
+	"GITHUB_REPOSITORY",
+	"GITHUB_EVENT_NAME",
+	"GITHUB_EVENT_PATH",
+	"GITHUB_WORKSPACE",
+}
+
+// checkGithubActions checks that the expected variables are set when running
+// in GitHub Actions.
+func checkGithubActions(getenv func(string) string) Result {
+	result := Result{Check: "github actions"}
+
+	if getenv("GITHUB_ACTIONS") != "true" {
+		result.Status = StatusPass
+		result.Detail = "not running in GitHub Actions"
+
+		return result
+	}
+
+	var missing []string
+	for _, name := range githubActionsVariables {
+		if getenv(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		result.Status = StatusWarn
+		result.Detail = "running in GitHub Actions without " + strings.Join(missing, ", ")
+		result.Hint = "do not override or unset the variables provided by GitHub Actions"
+
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Detail = "running in GitHub Actions for " + getenv("GITHUB_REPOSITORY")
+
+	return result
+}
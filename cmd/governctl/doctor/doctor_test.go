@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/team"
+)
+
+func TestWorst(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []Status
+		want     Status
+		wantCode int
+		wantErr  string
+	}{
+		{
+			name: "no checks",
+			want: StatusPass,
+		},
+		{
+			name:     "all pass",
+			statuses: []Status{StatusPass, StatusPass},
+			want:     StatusPass,
+		},
+		{
+			name:     "warning",
+			statuses: []Status{StatusPass, StatusWarn, StatusPass},
+			want:     StatusWarn,
+			wantCode: ExitCodeWarn,
+			wantErr:  "1 check warned",
+		},
+		{
+			name:     "failure outweighs warning",
+			statuses: []Status{StatusFail, StatusWarn, StatusFail},
+			want:     StatusFail,
+			wantCode: ExitCodeFail,
+			wantErr:  "2 checks failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var results []Result
+			for _, status := range tt.statuses {
+				results = append(results, Result{Status: status})
+			}
+
+			if got := worst(results); got != tt.want {
+				t.Errorf("worst() = %s, want %s", got, tt.want)
+			}
+
+			err := diagnosisError(results)
+			if got := cmdutils.ExitCode(err); got != tt.wantCode {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.wantCode)
+			}
+
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("diagnosisError() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckDefinitions(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid")
+	if err := os.MkdirAll(valid, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(valid, "teams.yaml"), []byte(teamsFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("name: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	load := func(path string) (int, error) {
+		teams, err := team.NewListOfTeamsFromPath(nil, "unikraft", path)
+		return len(teams), err
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus Status
+		wantDetail string
+	}{
+		{
+			name:       "valid",
+			path:       valid,
+			wantStatus: StatusPass,
+			wantDetail: "2 teams defined",
+		},
+		{
+			name:       "unparseable",
+			path:       invalid,
+			wantStatus: StatusFail,
+			wantDetail: "could not parse teams file",
+		},
+		{
+			name:       "missing",
+			path:       filepath.Join(dir, "missing"),
+			wantStatus: StatusWarn,
+			wantDetail: "teams not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checkDefinitions("teams", tt.path, "--teams-dir", load)
+
+			if result.Status != tt.wantStatus || !strings.Contains(result.Detail, tt.wantDetail) {
+				t.Errorf("checkDefinitions() = %s: %s, want %s: %s", result.Status, result.Detail, tt.wantStatus, tt.wantDetail)
+			}
+
+			if result.Status != StatusPass && result.Hint == "" {
+				t.Errorf("expected a remediation hint")
+			}
+		})
+	}
+}
+
+const teamsFixture = `name: maintainers-lib-foo
+maintainers:
+  - name: Jane Doe
+    github: jane
+---
+name: reviewers-lib-foo
+reviewers:
+  - name: Bob
+    github: bob
+`
+
+func TestTokenResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		info       ghapi.AuthInfo
+		wantStatus Status
+		wantHint   string
+	}{
+		{
+			name:       "app",
+			info:       ghapi.AuthInfo{App: true},
+			wantStatus: StatusPass,
+		},
+		{
+			name:       "fine-grained token",
+			info:       ghapi.AuthInfo{Login: "jane"},
+			wantStatus: StatusPass,
+		},
+		{
+			name:       "sufficient scopes",
+			info:       ghapi.AuthInfo{Login: "jane", ScopesKnown: true, Scopes: []string{"repo", "admin:org"}},
+			wantStatus: StatusPass,
+		},
+		{
+			name:       "missing scopes",
+			info:       ghapi.AuthInfo{Login: "jane", ScopesKnown: true, Scopes: []string{"public_repo"}},
+			wantStatus: StatusWarn,
+			wantHint:   "repo, read:org",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tokenResult(Result{Check: "github token"}, &tt.info)
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Detail)
+			}
+
+			if !strings.Contains(result.Hint, tt.wantHint) {
+				t.Errorf("Hint = %q, want it to mention %q", result.Hint, tt.wantHint)
+			}
+		})
+	}
+}
+
+func TestCheckGithubActions(t *testing.T) {
+	env := map[string]string{
+		"GITHUB_ACTIONS":    "true",
+		"GITHUB_REPOSITORY": "unikraft/unikraft",
+		"GITHUB_EVENT_NAME": "pull_request",
+		"GITHUB_EVENT_PATH": "/github/workflow/event.json",
+	}
+
+	result := checkGithubActions(func(name string) string { return env[name] })
+	if result.Status != StatusWarn || !strings.Contains(result.Detail, "GITHUB_WORKSPACE") {
+		t.Errorf("checkGithubActions() = %s: %s, want a warning about GITHUB_WORKSPACE", result.Status, result.Detail)
+	}
+
+	env["GITHUB_WORKSPACE"] = "/github/workspace"
+	if result := checkGithubActions(func(name string) string { return env[name] }); result.Status != StatusPass {
+		t.Errorf("checkGithubActions() = %s: %s, want pass", result.Status, result.Detail)
+	}
+
+	if result := checkGithubActions(func(string) string { return "" }); result.Status != StatusPass {
+		t.Errorf("checkGithubActions() outside of GitHub Actions = %s, want pass", result.Status)
+	}
+}
+
+func TestCheckTempDir(t *testing.T) {
+	if result := checkTempDir(t.TempDir()); result.Status != StatusPass {
+		t.Errorf("checkTempDir() = %s: %s, want pass", result.Status, result.Detail)
+	}
+
+	if result := checkTempDir(filepath.Join(t.TempDir(), "missing")); result.Status != StatusFail {
+		t.Errorf("checkTempDir() of a missing directory = %s, want fail", result.Status)
+	}
+}
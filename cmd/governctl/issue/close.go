@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package issue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+type Close struct {
+	Comment string `long:"comment" short:"c" usage:"Leave a comment before closing the issue"`
+	Output  string `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+	Reason  string `long:"reason" usage:"Set the reason for closing the issue [completed, not_planned]" default:"completed"`
+}
+
+// ClosePlan is the issue which would be closed.
+type ClosePlan struct {
+	Issue   string `json:"issue"`
+	Reason  string `json:"reason"`
+	Comment string `json:"comment,omitempty"`
+}
+
+func NewClose() *cobra.Command {
+	cmd, err := cmdutils.New(&Close{}, cobra.Command{
+		Use:   "close [OPTIONS] ORG/REPO/ID",
+		Short: "Close an issue",
+		Args:  cobra.ExactArgs(1),
+		Example: heredoc.Doc(`
+		# Close an issue which will not be addressed
+		governctl issue close --reason not_planned --comment "Out of scope." unikraft/unikraft#123
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "issue",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *Close) Validate(ctx context.Context) error {
+	if err := cmdutils.ValidatePlanOutput(ctx, opts.Output); err != nil {
+		return err
+	}
+
+	for _, reason := range ghapi.CloseReasons {
+		if opts.Reason == reason {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown reason '%s': expected one of [%s]", opts.Reason, strings.Join(ghapi.CloseReasons, ", "))
+}
+
+func (opts *Close) Run(ctx context.Context, args []string) error {
+	ghOrg, ghRepo, ghIssueId, err := cmdutils.ParseOrgRepoAndIssueArgs(args)
+	if err != nil {
+		return err
+	}
+
+	plan := &ClosePlan{
+		Issue:   fmt.Sprintf("%s/%s#%d", ghOrg, ghRepo, ghIssueId),
+		Reason:  opts.Reason,
+		Comment: opts.Comment,
+	}
+
+	if kitcfg.G[config.Config](ctx).DryRun {
+		if opts.Output != cmdutils.PlanOutputJSON {
+			fmt.Fprintf(iostreams.G(ctx).Out, "%s: close as %s\n", plan.Issue, plan.Reason)
+		}
+
+		return cmdutils.WritePlan(ctx, opts.Output, plan)
+	}
+
+	if kitcfg.G[config.Config](ctx).ReadOnly {
+		return fmt.Errorf("cannot close issue: %w", ghapi.ErrReadOnly)
+	}
+
+	ghClient, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := ghClient.CloseIssue(ctx, ghOrg, ghRepo, ghIssueId, opts.Reason, opts.Comment); err != nil {
+		return err
+	}
+
+	log.G(ctx).
+		WithField("issue", plan.Issue).
+		WithField("reason", plan.Reason).
+		Info("closed issue")
+
+	return nil
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package issue
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"kraftkit.sh/cmdfactory"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+)
+
+type Issue struct{}
+
+func New() *cobra.Command {
+	cmd, err := cmdutils.New(&Issue{}, cobra.Command{
+		Use:    "issue SUBCOMMAND",
+		Short:  "Manage GitHub issues",
+		Hidden: true,
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "issue",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	cmd.AddCommand(NewClose())
+	cmd.AddCommand(NewLabel())
+	cmd.AddCommand(NewLock())
+
+	return cmd
+}
+
+func (opts *Issue) Run(_ context.Context, args []string) error {
+	return pflag.ErrHelp
+}
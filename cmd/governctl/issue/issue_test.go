@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package issue
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+// newIssueEnv returns a context whose GitHub endpoint records every request
+// made to the unikraft/unikraft repository.
+func newIssueEnv(t *testing.T, cfg config.Config) (context.Context, *[]string, *bytes.Buffer) {
+	t.Helper()
+
+	var requests []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/unikraft/unikraft")
+		requests = append(requests, strings.TrimSpace(r.Method+" "+path+" "+strings.TrimSpace(string(body))))
+
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if strings.HasSuffix(path, "/labels") {
+			w.Write([]byte(`[]`))
+			return
+		}
+
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg.GithubEndpoint = srv.URL
+
+	cfgm, err := kitcfg.NewConfigManager(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+	ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+	return ctx, &requests, out
+}
+
+func TestIssueCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(context.Context) error
+		want []string
+	}{
+		{
+			name: "close",
+			run: func(ctx context.Context) error {
+				return (&Close{Reason: "not_planned", Comment: "Out of scope."}).Run(ctx, []string{"unikraft/unikraft#7"})
+			},
+			want: []string{
+				`POST /issues/7/comments {"body":"Out of scope."}`,
+				`PATCH /issues/7 {"state":"closed","state_reason":"not_planned"}`,
+			},
+		},
+		{
+			name: "lock",
+			run: func(ctx context.Context) error {
+				return (&Lock{Reason: "spam"}).Run(ctx, []string{"https://github.com/unikraft/unikraft/issues/7"})
+			},
+			want: []string{
+				`PUT /issues/7/lock {"lock_reason":"spam"}`,
+			},
+		},
+		{
+			name: "unlock",
+			run: func(ctx context.Context) error {
+				return (&Lock{Unlock: true}).Run(ctx, []string{"unikraft/unikraft/7"})
+			},
+			want: []string{
+				`DELETE /issues/7/lock`,
+			},
+		},
+		{
+			name: "label",
+			run: func(ctx context.Context) error {
+				return (&Label{Add: []string{"kind/bug"}, Remove: []string{"triage/needed"}}).Run(ctx, []string{"unikraft/unikraft#7"})
+			},
+			want: []string{
+				`POST /issues/7/labels ["kind/bug"]`,
+				`DELETE /issues/7/labels/triage/needed`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, requests, _ := newIssueEnv(t, config.Config{})
+
+			if err := tt.run(ctx); err != nil {
+				t.Fatalf("Run() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(*requests, tt.want) {
+				t.Errorf("unexpected requests:\ngot:  %q\nwant: %q", *requests, tt.want)
+			}
+
+			// Neither dry-run nor read-only mode may modify the issue.
+			for _, cfg := range []config.Config{{DryRun: true}, {ReadOnly: true}} {
+				ctx, requests, _ := newIssueEnv(t, cfg)
+
+				err := tt.run(ctx)
+				if cfg.ReadOnly && !errors.Is(err, ghapi.ErrReadOnly) {
+					t.Errorf("Run() in read-only mode error = %v, want ErrReadOnly", err)
+				} else if cfg.DryRun && err != nil {
+					t.Errorf("Run() in dry-run mode unexpected error: %v", err)
+				}
+
+				if len(*requests) > 0 {
+					t.Errorf("unexpected requests: %q", *requests)
+				}
+			}
+		})
+	}
+}
+
+func TestIssueValidate(t *testing.T) {
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+	tests := []struct {
+		name    string
+		opts    interface{ Validate(context.Context) error }
+		wantErr string
+	}{
+		{
+			name: "close",
+			opts: &Close{Reason: "completed"},
+		},
+		{
+			name:    "close with unknown reason",
+			opts:    &Close{Reason: "duplicate"},
+			wantErr: "unknown reason",
+		},
+		{
+			name: "lock",
+			opts: &Lock{Reason: "resolved"},
+		},
+		{
+			name:    "lock with unknown reason",
+			opts:    &Lock{Reason: "boring"},
+			wantErr: "unknown reason",
+		},
+		{
+			name:    "unlock with reason",
+			opts:    &Lock{Unlock: true, Reason: "spam"},
+			wantErr: "unlock",
+		},
+		{
+			name:    "label without changes",
+			opts:    &Label{},
+			wantErr: "expected at least one of --add or --remove",
+		},
+		{
+			name:    "label added and removed",
+			opts:    &Label{Add: []string{"kind/bug"}, Remove: []string{"kind/bug"}},
+			wantErr: "cannot both add and remove",
+		},
+		{
+			name:    "json plan without dry-run",
+			opts:    &Label{Add: []string{"kind/bug"}, Output: "json"},
+			wantErr: "dry-run",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate(ctx)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIssueCommandsWithGlobalFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"issue", "close", "--dry-run", "--reason", "not_planned", "unikraft/unikraft#7"},
+		{"issue", "lock", "--dry-run", "--reason", "spam", "unikraft/unikraft#7"},
+		{"issue", "label", "--dry-run", "--add", "kind/bug", "--remove", "triage/needed", "unikraft/unikraft#7"},
+	} {
+		t.Run(args[1], func(t *testing.T) {
+			cfg := config.Config{}
+
+			root := &cobra.Command{Use: "governctl"}
+			root.AddCommand(New())
+
+			// The flags of the subcommands are merged with the global ones when
+			// the command is executed, which fails on conflicting shorthands.
+			if err := cmdfactory.AttributeFlags(root, &cfg, args...); err != nil {
+				t.Fatal(err)
+			}
+
+			cfgm, err := kitcfg.NewConfigManager(&cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+			ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: &bytes.Buffer{}})
+
+			root.SetArgs(args)
+
+			if err := root.ExecuteContext(ctx); err != nil {
+				t.Fatalf("Execute() unexpected error: %v", err)
+			}
+
+			if !cfg.DryRun {
+				t.Error("global --dry-run was not attributed")
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package issue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+type Label struct {
+	Add    []string `long:"add" short:"a" usage:"Add this label to the issue (may be repeated)"`
+	Output string   `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+	Remove []string `long:"remove" usage:"Remove this label from the issue (may be repeated)"`
+}
+
+// LabelPlan is the set of labels which would be added to and removed from the
+// issue.
+type LabelPlan struct {
+	Issue  string   `json:"issue"`
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+func NewLabel() *cobra.Command {
+	cmd, err := cmdutils.New(&Label{}, cobra.Command{
+		Use:   "label [OPTIONS] ORG/REPO/ID",
+		Short: "Add or remove labels of an issue",
+		Args:  cobra.ExactArgs(1),
+		Example: heredoc.Doc(`
+		# Mark an issue as a confirmed bug
+		governctl issue label --add kind/bug --remove triage/needed unikraft/unikraft#123
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "issue",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *Label) Validate(ctx context.Context) error {
+	if err := cmdutils.ValidatePlanOutput(ctx, opts.Output); err != nil {
+		return err
+	}
+
+	if len(opts.Add) == 0 && len(opts.Remove) == 0 {
+		return fmt.Errorf("expected at least one of --add or --remove")
+	}
+
+	for _, add := range opts.Add {
+		for _, remove := range opts.Remove {
+			if add == remove {
+				return fmt.Errorf("cannot both add and remove label '%s'", add)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (opts *Label) Run(ctx context.Context, args []string) error {
+	ghOrg, ghRepo, ghIssueId, err := cmdutils.ParseOrgRepoAndIssueArgs(args)
+	if err != nil {
+		return err
+	}
+
+	plan := &LabelPlan{
+		Issue:  fmt.Sprintf("%s/%s#%d", ghOrg, ghRepo, ghIssueId),
+		Add:    opts.Add,
+		Remove: opts.Remove,
+	}
+
+	if kitcfg.G[config.Config](ctx).DryRun {
+		if opts.Output != cmdutils.PlanOutputJSON {
+			if len(plan.Add) > 0 {
+				fmt.Fprintf(iostreams.G(ctx).Out, "%s: add %s\n", plan.Issue, strings.Join(plan.Add, ", "))
+			}
+			if len(plan.Remove) > 0 {
+				fmt.Fprintf(iostreams.G(ctx).Out, "%s: remove %s\n", plan.Issue, strings.Join(plan.Remove, ", "))
+			}
+		}
+
+		return cmdutils.WritePlan(ctx, opts.Output, plan)
+	}
+
+	if kitcfg.G[config.Config](ctx).ReadOnly {
+		return fmt.Errorf("cannot update labels: %w", ghapi.ErrReadOnly)
+	}
+
+	ghClient, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.Add) > 0 {
+		if err := ghClient.AddPullRequestLabels(ctx, ghOrg, ghRepo, ghIssueId, opts.Add); err != nil {
+			return fmt.Errorf("could not add labels to %s: %w", plan.Issue, err)
+		}
+	}
+
+	if len(opts.Remove) > 0 {
+		if err := ghClient.RemovePullRequestLabels(ctx, ghOrg, ghRepo, ghIssueId, opts.Remove); err != nil {
+			return fmt.Errorf("could not remove labels from %s: %w", plan.Issue, err)
+		}
+	}
+
+	log.G(ctx).
+		WithField("issue", plan.Issue).
+		WithField("add", opts.Add).
+		WithField("remove", opts.Remove).
+		Info("updated labels")
+
+	return nil
+}
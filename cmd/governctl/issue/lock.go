@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package issue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+type Lock struct {
+	Output string `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+	Reason string `long:"reason" usage:"Set the reason for locking the conversation [off-topic, too heated, resolved, spam]"`
+	Unlock bool   `long:"unlock" usage:"Unlock the conversation instead"`
+}
+
+// LockPlan is the issue whose conversation would be locked or unlocked.
+type LockPlan struct {
+	Issue  string `json:"issue"`
+	Unlock bool   `json:"unlock,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func NewLock() *cobra.Command {
+	cmd, err := cmdutils.New(&Lock{}, cobra.Command{
+		Use:   "lock [OPTIONS] ORG/REPO/ID",
+		Short: "Lock or unlock the conversation of an issue",
+		Args:  cobra.ExactArgs(1),
+		Long: heredoc.Doc(`
+		Lock the conversation of an issue such that only collaborators can
+		comment on it, or unlock it again with --unlock.
+		`),
+		Example: heredoc.Doc(`
+		# Lock a heated discussion
+		governctl issue lock --reason "too heated" unikraft/unikraft#123
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "issue",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *Lock) Validate(ctx context.Context) error {
+	if err := cmdutils.ValidatePlanOutput(ctx, opts.Output); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("unlock", opts.Unlock, "reason", opts.Reason != ""); err != nil {
+		return err
+	}
+
+	if opts.Reason == "" {
+		return nil
+	}
+
+	for _, reason := range ghapi.LockReasons {
+		if opts.Reason == reason {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown reason '%s': expected one of [%s]", opts.Reason, strings.Join(ghapi.LockReasons, ", "))
+}
+
+func (opts *Lock) Run(ctx context.Context, args []string) error {
+	ghOrg, ghRepo, ghIssueId, err := cmdutils.ParseOrgRepoAndIssueArgs(args)
+	if err != nil {
+		return err
+	}
+
+	plan := &LockPlan{
+		Issue:  fmt.Sprintf("%s/%s#%d", ghOrg, ghRepo, ghIssueId),
+		Unlock: opts.Unlock,
+		Reason: opts.Reason,
+	}
+
+	action := "lock conversation"
+	if opts.Unlock {
+		action = "unlock conversation"
+	}
+
+	if kitcfg.G[config.Config](ctx).DryRun {
+		if opts.Output != cmdutils.PlanOutputJSON {
+			fmt.Fprintf(iostreams.G(ctx).Out, "%s: %s\n", plan.Issue, action)
+		}
+
+		return cmdutils.WritePlan(ctx, opts.Output, plan)
+	}
+
+	if kitcfg.G[config.Config](ctx).ReadOnly {
+		return fmt.Errorf("cannot %s: %w", action, ghapi.ErrReadOnly)
+	}
+
+	ghClient, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if opts.Unlock {
+		err = ghClient.UnlockConversation(ctx, ghOrg, ghRepo, ghIssueId)
+	} else {
+		err = ghClient.LockConversation(ctx, ghOrg, ghRepo, ghIssueId, opts.Reason)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.G(ctx).
+		WithField("issue", plan.Issue).
+		Info(action)
+
+	return nil
+}
@@ -0,0 +1,446 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package label
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/google/go-github/v63/github"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/label"
+	"github.com/unikraft/governance/internal/repo"
+	"github.com/unikraft/governance/internal/yamledit"
+)
+
+type Rename struct {
+	AllRepos    bool     `long:"all-repos" usage:"Rename the label in every repository of the repos definition directory"`
+	Color       string   `long:"color" usage:"Set the color of the renamed label instead of preserving it"`
+	Description string   `long:"description" usage:"Set the description of the renamed label instead of preserving it"`
+	Org         string   `long:"org" usage:"Set the GitHub organisation whose repositories have their labels renamed (deprecated, use --github-org)"`
+	Output      string   `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+	Repos       []string `long:"repo" usage:"Rename the label in this repository (may be repeated)"`
+}
+
+func NewRename() *cobra.Command {
+	cmd, err := cmdutils.New(&Rename{}, cobra.Command{
+		Use:   "rename [OPTIONS] OLD NEW",
+		Short: "Rename a label and migrate its usage across repositories",
+		Args:  cobra.ExactArgs(2),
+		Long: heredoc.Doc(`
+		Rename a label in the labels definition directory and in every repository
+		in scope.  When a repository already has a label with the new name, open
+		issues and pull requests are moved from the old label to the new one and
+		the old label is deleted.
+
+		Every step is skipped when it has already been performed, such that the
+		command can simply be re-run to resume after a failure.
+		`),
+		Example: heredoc.Doc(`
+		# Preview renaming "bug" to "kind/bug" across the organisation
+		governctl --dry-run label rename --all-repos bug kind/bug
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "label",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	cmdutils.DeprecateOrg(cmd)
+
+	return cmd
+}
+
+// Validate rejects unknown output formats of the plan and --all-repos combined
+// with an explicit --repo.
+func (opts *Rename) Validate(ctx context.Context) error {
+	if err := cmdutils.ValidatePlanOutput(ctx, opts.Output); err != nil {
+		return err
+	}
+
+	return config.Exclusive("all-repos", opts.AllRepos, "repo", len(opts.Repos) > 0)
+}
+
+// renameAction is what needs to happen to a label in a single repository.
+type renameAction string
+
+const (
+	// renameActionNone is used when the repository has neither label.
+	renameActionNone = renameAction("none")
+
+	// renameActionDone is used when the label has already been renamed.
+	renameActionDone = renameAction("done")
+
+	// renameActionRename is used when only the old label exists, which is
+	// renamed in place.
+	renameActionRename = renameAction("rename")
+
+	// renameActionMigrate is used when both labels exist, in which case open
+	// issues and pull requests are moved to the new label before the old one is
+	// deleted.
+	renameActionMigrate = renameAction("migrate")
+)
+
+// repoRename is the plan for renaming a label in a single repository.
+type repoRename struct {
+	Repo   string       `json:"repo"`
+	Action renameAction `json:"action"`
+
+	// Issues are the open issues and pull requests carrying the old label.
+	Issues []int `json:"issues,omitempty"`
+}
+
+// fileRename is the change to a single file of the labels definition
+// directory.
+type fileRename struct {
+	File string `json:"file"`
+	Diff string `json:"diff"`
+}
+
+// RenamePlan is the set of changes to the labels definition directory and to
+// every repository in scope which renaming a label involves.
+type RenamePlan struct {
+	From  string       `json:"from"`
+	To    string       `json:"to"`
+	Files []fileRename `json:"files"`
+	Repos []repoRename `json:"repos"`
+}
+
+// String describes the plan in a human-readable form.
+func (p repoRename) String() string {
+	switch p.Action {
+	case renameActionDone:
+		return "label has already been renamed"
+	case renameActionRename:
+		return fmt.Sprintf("rename label (carried by %d open issues and pull requests)", len(p.Issues))
+	case renameActionMigrate:
+		return fmt.Sprintf("move %d open issues and pull requests to the existing label and delete the old label", len(p.Issues))
+	}
+
+	return "label does not exist"
+}
+
+func (opts *Rename) Run(ctx context.Context, args []string) error {
+	opts.Org = kitcfg.G[config.Config](ctx).EffectiveGithubOrg(opts.Org)
+
+	from, to := args[0], args[1]
+	if from == to {
+		return fmt.Errorf("old and new label names are identical")
+	}
+
+	dryRun := kitcfg.G[config.Config](ctx).DryRun
+
+	if kitcfg.G[config.Config](ctx).ReadOnly && !dryRun {
+		return fmt.Errorf("cannot rename label: %w", ghapi.ErrReadOnly)
+	}
+
+	ghClient, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	plan := &RenamePlan{
+		From:  from,
+		To:    to,
+		Repos: make([]repoRename, 0),
+	}
+
+	if plan.Files, err = opts.renameDefinitions(ctx, from, to); err != nil {
+		return err
+	}
+
+	repos := opts.Repos
+	if opts.AllRepos {
+		all, err := repo.NewListOfReposFromPath(ghClient, opts.Org, kitcfg.G[config.Config](ctx).ReposDir)
+		if err != nil {
+			return fmt.Errorf("could not populate repos: %w", err)
+		}
+
+		for _, r := range all {
+			repos = append(repos, r.Fullname())
+		}
+	}
+
+	var errs []error
+
+	for i, name := range repos {
+		logger := log.G(ctx).
+			WithField("repo", name).
+			WithField("progress", fmt.Sprintf("%d/%d", i+1, len(repos)))
+
+		repoPlan, err := planRepoRename(ctx, ghClient, opts.Org, name, from, to)
+		if err != nil {
+			logger.Errorf("could not plan label rename: %s", err)
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+
+		plan.Repos = append(plan.Repos, repoPlan)
+
+		if dryRun {
+			if opts.Output != cmdutils.PlanOutputJSON {
+				fmt.Fprintf(iostreams.G(ctx).Out, "%s/%s: %s\n", opts.Org, name, repoPlan)
+			}
+			continue
+		}
+
+		logger.Info(repoPlan.String())
+
+		if err := opts.applyRepoRename(ctx, ghClient, repoPlan, from, to); err != nil {
+			logger.Errorf("could not rename label: %s", err)
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf(
+			"could not rename label in %d of %d repositories, re-run the command to resume: %w",
+			len(errs),
+			len(repos),
+			errors.Join(errs...),
+		)
+	}
+
+	return cmdutils.WritePlan(ctx, opts.Output, plan)
+}
+
+// renameDefinitions renames the label in the labels definition directory and
+// returns the changes to each file.  It is not an error if the definition has
+// already been renamed.
+func (opts *Rename) renameDefinitions(ctx context.Context, from, to string) ([]fileRename, error) {
+	labelsDir := kitcfg.G[config.Config](ctx).LabelsDir
+
+	entries, err := os.ReadDir(labelsDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read directory: %w", err)
+	}
+
+	files := make(map[string][]byte)
+	var names []string
+	var numFrom, numTo int
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := filepath.Join(labelsDir, entry.Name())
+
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("could not read labels file: %w", err)
+		}
+
+		files[name] = src
+		names = append(names, name)
+		numFrom += label.Defines(src, from)
+		numTo += label.Defines(src, to)
+	}
+
+	switch {
+	case numFrom == 0 && numTo == 0:
+		return nil, fmt.Errorf("label '%s' is not defined in '%s'", from, labelsDir)
+	case numFrom > 0 && numTo > 0:
+		return nil, fmt.Errorf("both '%s' and '%s' are defined in '%s': remove one of the definitions first", from, to, labelsDir)
+	case numFrom == 0:
+		log.G(ctx).
+			WithField("label", to).
+			Info("label definition has already been renamed")
+		return []fileRename{}, nil
+	}
+
+	color := opts.Color
+	if color != "" && !strings.HasPrefix(color, "#") {
+		color = "#" + color
+	}
+
+	changes := make([]fileRename, 0)
+
+	for _, name := range names {
+		src := files[name]
+		if label.Defines(src, from) == 0 {
+			continue
+		}
+
+		renamed := label.RenameDefinition(src, from, to, color, opts.Description)
+
+		var parsed label.Labels
+		if err := yaml.Unmarshal(renamed, &parsed); err != nil {
+			return nil, fmt.Errorf("renaming label in '%s' produced invalid YAML: %w", name, err)
+		}
+
+		for _, l := range parsed.Labels {
+			for _, ref := range l.DoNotRemoveIfLabelsExist {
+				if ref == from {
+					log.G(ctx).
+						WithField("file", name).
+						WithField("label", l.Name).
+						Warnf("label still refers to '%s'", from)
+				}
+			}
+		}
+
+		changes = append(changes, fileRename{
+			File: name,
+			Diff: yamledit.Diff(name, src, renamed),
+		})
+
+		if kitcfg.G[config.Config](ctx).DryRun {
+			if opts.Output != cmdutils.PlanOutputJSON {
+				fmt.Fprint(iostreams.G(ctx).Out, changes[len(changes)-1].Diff)
+			}
+			continue
+		}
+
+		log.G(ctx).
+			WithField("file", name).
+			Info("renaming label definition")
+
+		if err := os.WriteFile(name, renamed, 0o644); err != nil {
+			return nil, fmt.Errorf("could not write labels file: %w", err)
+		}
+	}
+
+	return changes, nil
+}
+
+// planRepoRename determines what needs to happen to rename the label in the
+// repository based on which of the two labels currently exist.
+func planRepoRename(ctx context.Context, ghClient *ghapi.GithubClient, org, name, from, to string) (repoRename, error) {
+	plan := repoRename{Repo: name}
+
+	oldLabel, err := ghClient.GetRepoLabel(ctx, org, name, from)
+	if err != nil {
+		return plan, fmt.Errorf("could not get label '%s': %w", from, err)
+	}
+
+	newLabel, err := ghClient.GetRepoLabel(ctx, org, name, to)
+	if err != nil {
+		return plan, fmt.Errorf("could not get label '%s': %w", to, err)
+	}
+
+	switch {
+	case oldLabel == nil && newLabel == nil:
+		plan.Action = renameActionNone
+		return plan, nil
+	case oldLabel == nil:
+		plan.Action = renameActionDone
+		return plan, nil
+	case newLabel == nil:
+		plan.Action = renameActionRename
+	default:
+		plan.Action = renameActionMigrate
+	}
+
+	plan.Issues, err = ghClient.ListOpenIssuesWithLabel(ctx, org, name, from)
+	if err != nil {
+		return plan, fmt.Errorf("could not list issues with label '%s': %w", from, err)
+	}
+
+	return plan, nil
+}
+
+// applyRepoRename performs the planned rename of the label in the repository.
+func (opts *Rename) applyRepoRename(ctx context.Context, ghClient *ghapi.GithubClient, plan repoRename, from, to string) error {
+	edit := &github.Label{}
+	if opts.Color != "" {
+		edit.Color = github.String(strings.TrimPrefix(opts.Color, "#"))
+	}
+	if opts.Description != "" {
+		edit.Description = github.String(opts.Description)
+	}
+
+	switch plan.Action {
+	case renameActionRename:
+		edit.Name = github.String(to)
+
+		if err := ghClient.EditRepoLabel(ctx, opts.Org, plan.Repo, from, edit); err != nil {
+			return fmt.Errorf("could not rename label: %w", err)
+		}
+
+		// GitHub renames the label in place, verify that this is the case for
+		// every open issue and pull request which carried the old label.
+		carrying, err := ghClient.ListOpenIssuesWithLabel(ctx, opts.Org, plan.Repo, to)
+		if err != nil {
+			return fmt.Errorf("could not list issues with label '%s': %w", to, err)
+		}
+
+		for _, number := range subtractInts(plan.Issues, carrying) {
+			log.G(ctx).
+				WithField("repo", plan.Repo).
+				WithField("number", number).
+				Warn("issue did not carry the renamed label, adding it")
+
+			if err := ghClient.AddLabelsToPr(ctx, opts.Org, plan.Repo, number, []string{to}); err != nil {
+				return err
+			}
+		}
+
+	case renameActionMigrate:
+		for _, number := range plan.Issues {
+			log.G(ctx).
+				WithField("repo", plan.Repo).
+				WithField("number", number).
+				Info("moving issue to the new label")
+
+			if err := ghClient.AddLabelsToPr(ctx, opts.Org, plan.Repo, number, []string{to}); err != nil {
+				return err
+			}
+		}
+
+		if edit.Color != nil || edit.Description != nil {
+			if err := ghClient.EditRepoLabel(ctx, opts.Org, plan.Repo, to, edit); err != nil {
+				return fmt.Errorf("could not update label: %w", err)
+			}
+		}
+
+		// Deleting the old label also removes it from every issue and pull
+		// request.  Since issues are only moved before deletion, an interrupted
+		// migration is simply resumed on the next run.
+		if err := ghClient.DeleteRepoLabel(ctx, opts.Org, plan.Repo, from); err != nil {
+			return fmt.Errorf("could not delete label: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// subtractInts returns the elements of a which are not in b.
+func subtractInts(a, b []int) []int {
+	var diff []int
+
+	for _, x := range a {
+		found := false
+		for _, y := range b {
+			if x == y {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			diff = append(diff, x)
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,294 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package label
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+)
+
+// fakeLabels is a fake GitHub API which keeps track of the labels of every
+// repository and of the labels carried by every open issue.
+type fakeLabels struct {
+	labels map[string]map[string]bool
+	issues map[string]map[int][]string
+	writes []string
+}
+
+func (f *fakeLabels) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v3/repos/unikraft/"), "/")
+
+	if r.Method != http.MethodGet {
+		f.writes = append(f.writes, fmt.Sprintf("%s %s/%s", r.Method, name, rest))
+	}
+
+	switch {
+	case strings.HasPrefix(rest, "labels/"):
+		label := strings.TrimPrefix(rest, "labels/")
+
+		if !f.labels[name][label] {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPatch:
+			var edit struct {
+				Name string `json:"name"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&edit)
+
+			if edit.Name != "" {
+				delete(f.labels[name], label)
+				f.labels[name][edit.Name] = true
+
+				for number, labels := range f.issues[name] {
+					for i, l := range labels {
+						if l == label {
+							f.issues[name][number][i] = edit.Name
+						}
+					}
+				}
+			}
+		case http.MethodDelete:
+			delete(f.labels[name], label)
+
+			for number, labels := range f.issues[name] {
+				var kept []string
+				for _, l := range labels {
+					if l != label {
+						kept = append(kept, l)
+					}
+				}
+				f.issues[name][number] = kept
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		fmt.Fprintf(w, `{"name":%q}`, label)
+
+	case rest == "issues":
+		want := r.URL.Query().Get("labels")
+		var issues []string
+
+		for number, labels := range f.issues[name] {
+			for _, l := range labels {
+				if l == want {
+					issues = append(issues, fmt.Sprintf(`{"number":%d}`, number))
+				}
+			}
+		}
+
+		fmt.Fprint(w, "["+strings.Join(issues, ",")+"]")
+
+	case strings.HasPrefix(rest, "issues/") && strings.HasSuffix(rest, "/labels"):
+		number, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rest, "issues/"), "/labels"))
+
+		var labels []string
+		_ = json.NewDecoder(r.Body).Decode(&labels)
+
+		f.issues[name][number] = append(f.issues[name][number], labels...)
+
+		fmt.Fprint(w, `[]`)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newRenameEnv(t *testing.T, dryRun bool) (*fakeLabels, string, context.Context, *bytes.Buffer) {
+	t.Helper()
+
+	fake := &fakeLabels{
+		labels: map[string]map[string]bool{
+			"app-rename":  {"bug": true},
+			"app-migrate": {"bug": true, "kind/bug": true},
+			"app-none":    {},
+		},
+		issues: map[string]map[int][]string{
+			"app-rename":  {1: {"bug"}},
+			"app-migrate": {2: {"bug"}, 3: {"kind/bug"}},
+			"app-none":    {},
+		},
+	}
+
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	labelsDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(labelsDir, "bug.yaml"), []byte(`labels:
+  - name: bug
+    description: Something is broken
+    color: "#ed5139"
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{
+		DryRun:         dryRun,
+		GithubEndpoint: srv.URL,
+		LabelsDir:      labelsDir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+	ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+	return fake, labelsDir, ctx, out
+}
+
+func TestRename(t *testing.T) {
+	fake, labelsDir, ctx, _ := newRenameEnv(t, false)
+
+	opts := &Rename{
+		Org:   "unikraft",
+		Repos: []string{"app-rename", "app-migrate", "app-none"},
+	}
+
+	if err := opts.Run(ctx, []string{"bug", "kind/bug"}); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	wantWrites := []string{
+		"PATCH app-rename/labels/bug",
+		"POST app-migrate/issues/2/labels",
+		"DELETE app-migrate/labels/bug",
+	}
+	if !reflect.DeepEqual(fake.writes, wantWrites) {
+		t.Errorf("unexpected writes:\ngot:  %v\nwant: %v", fake.writes, wantWrites)
+	}
+
+	for repo, issues := range fake.issues {
+		for number, labels := range issues {
+			if !reflect.DeepEqual(labels, []string{"kind/bug"}) {
+				t.Errorf("%s#%d carries labels %v, want [kind/bug]", repo, number, labels)
+			}
+		}
+	}
+
+	got, err := os.ReadFile(filepath.Join(labelsDir, "bug.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(got), "- name: kind/bug\n") {
+		t.Errorf("label definition was not renamed:\n%s", got)
+	}
+
+	// Running the command again resumes from where it left off, which in this
+	// case means that there is nothing left to do.
+	fake.writes = nil
+
+	if err := opts.Run(ctx, []string{"bug", "kind/bug"}); err != nil {
+		t.Fatalf("Run() unexpected error on re-run: %v", err)
+	}
+
+	if len(fake.writes) != 0 {
+		t.Errorf("unexpected writes on re-run: %v", fake.writes)
+	}
+}
+
+func TestRenameDryRun(t *testing.T) {
+	fake, labelsDir, ctx, out := newRenameEnv(t, true)
+
+	opts := &Rename{
+		Org:   "unikraft",
+		Repos: []string{"app-rename", "app-migrate", "app-none"},
+	}
+
+	if err := opts.Run(ctx, []string{"bug", "kind/bug"}); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	if len(fake.writes) != 0 {
+		t.Errorf("unexpected writes in dry-run: %v", fake.writes)
+	}
+
+	for _, want := range []string{
+		"-  - name: bug\n+  - name: kind/bug\n",
+		"unikraft/app-rename: rename label (carried by 1 open issues and pull requests)\n",
+		"unikraft/app-migrate: move 1 open issues and pull requests to the existing label and delete the old label\n",
+		"unikraft/app-none: label does not exist\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("dry-run output does not contain %q:\n%s", want, out.String())
+		}
+	}
+
+	got, err := os.ReadFile(filepath.Join(labelsDir, "bug.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(got), "kind/bug") {
+		t.Errorf("label definition was modified in dry-run:\n%s", got)
+	}
+}
+
+func TestRenameDryRunPlan(t *testing.T) {
+	fake, labelsDir, ctx, out := newRenameEnv(t, true)
+
+	opts := &Rename{
+		Org:    "unikraft",
+		Output: cmdutils.PlanOutputJSON,
+		Repos:  []string{"app-rename", "app-migrate", "app-none"},
+	}
+
+	if err := opts.Run(ctx, []string{"bug", "kind/bug"}); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	if len(fake.writes) != 0 {
+		t.Errorf("unexpected writes in dry-run: %v", fake.writes)
+	}
+
+	var got RenamePlan
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("could not parse plan: %v\n%s", err, out.String())
+	}
+
+	// The plan must describe exactly the operations which TestRename expects.
+	wantRepos := []repoRename{
+		{Repo: "app-rename", Action: renameActionRename, Issues: []int{1}},
+		{Repo: "app-migrate", Action: renameActionMigrate, Issues: []int{2}},
+		{Repo: "app-none", Action: renameActionNone},
+	}
+	if !reflect.DeepEqual(got.Repos, wantRepos) {
+		t.Errorf("unexpected repos in plan:\ngot:  %+v\nwant: %+v", got.Repos, wantRepos)
+	}
+
+	if got.From != "bug" || got.To != "kind/bug" {
+		t.Errorf("unexpected labels in plan: %s -> %s", got.From, got.To)
+	}
+
+	if len(got.Files) != 1 || got.Files[0].File != filepath.Join(labelsDir, "bug.yaml") ||
+		!strings.Contains(got.Files[0].Diff, "-  - name: bug\n+  - name: kind/bug\n") {
+		t.Errorf("unexpected files in plan: %+v", got.Files)
+	}
+}
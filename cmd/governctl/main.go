@@ -9,67 +9,28 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 
-	"github.com/MakeNowJust/heredoc"
 	"github.com/rancher/wrangler/pkg/signals"
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/cobra"
-	"github.com/spf13/pflag"
 	"kraftkit.sh/cmdfactory"
 	kitcfg "kraftkit.sh/config"
 	"kraftkit.sh/iostreams"
 	"kraftkit.sh/log"
 
-	"github.com/unikraft/governance/cmd/governctl/pr"
-	"github.com/unikraft/governance/cmd/governctl/team"
+	"github.com/unikraft/governance/cmd/governctl/cli"
+	"github.com/unikraft/governance/internal/cmdutils"
 	"github.com/unikraft/governance/internal/config"
-	"github.com/unikraft/governance/internal/version"
+	"github.com/unikraft/governance/internal/ghapi"
 )
 
-type GovernCtl struct{}
-
-func New() *cobra.Command {
-	cmd, err := cmdfactory.New(&GovernCtl{}, cobra.Command{
-		Use:   "governctl COMMAND",
-		Short: `Govern the Unikraft Open-Source Project GitHub Organization`,
-		Long: heredoc.Docf(`
-		Govern the Unikraft Open-Source Project GitHub Organization
-
-		The utility program governctl is intended to be used by maintainers,
-		reviewers, team members, staff and contributors to ease repetitive
-		maintenance tasks within the Unikraft Open-Source Project.
-
-		VERSION
-		  %s`, version.String()),
-		CompletionOptions: cobra.CompletionOptions{
-			HiddenDefaultCmd: true,
-		},
-	})
-	if err != nil {
-		panic(err)
-	}
-
-	// Subcommands
-	cmd.AddGroup(&cobra.Group{ID: "pr", Title: "PULL REQUEST COMMANDS"})
-	cmd.AddCommand(pr.New())
-
-	cmd.AddGroup(&cobra.Group{ID: "team", Title: "TEAM COMMANDS"})
-	cmd.AddCommand(team.New())
-
-	return cmd
-}
-
-func (*GovernCtl) Run(_ context.Context, _ []string) error {
-	return pflag.ErrHelp
-}
-
 func main() {
 	cfg := config.Config{}
 	cfgm, err := kitcfg.NewConfigManager(&cfg)
 	if err != nil {
 		panic(err)
 	}
-	cmd := New()
+	cmd := cli.New()
 
 	// Set up the global context
 	ctx := signals.SetupSignalContext()
@@ -80,6 +41,19 @@ func main() {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	if cfg.ConfigFile != "" {
+		if err := cfg.LoadFile(cfg.ConfigFile, func(name string) bool {
+			flag := cmd.PersistentFlags().Lookup(name)
+			return flag != nil && flag.Changed
+		}); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 	if err := cmd.ParseFlags(os.Args[1:]); err == nil {
 		cmd.DisableFlagParsing = true
 	}
@@ -93,13 +67,78 @@ func main() {
 	formatter.DisableTimestamp = true
 	logger.Formatter = formatter
 
-	if lvl, err := logrus.ParseLevel(cfgm.Config.LogLevel); err == nil {
+	if lvl, err := logrus.ParseLevel(cfgm.Config.EffectiveLogLevel()); err == nil {
 		logger.SetLevel(lvl)
 	}
 
 	ctx = log.WithLogger(ctx, logger)
 	ctx = iostreams.WithIOStreams(ctx, iostreams.System())
 
+	// Catch a misconfigured GitHub Enterprise endpoint early.  This is only a
+	// warning as not every command talks to the GitHub API.
+	if cfg.GithubEndpoint != "" {
+		if err := ghapi.CheckEndpoint(ctx, cfg.GithubEndpoint, cfg.GithubSkipSSL, cfg.EffectiveGithubTimeout()); err != nil {
+			log.G(ctx).Warn(err)
+		}
+	}
+
+	var usage *ghapi.APIUsage
+	if cfg.APIUsageReport != "" {
+		usage = ghapi.NewAPIUsage()
+		ctx = ghapi.WithAPIUsage(ctx, usage)
+	}
+
 	// Execute the main command
-	os.Exit(cmdfactory.Main(ctx, cmd))
+	code := 0
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(iostreams.G(ctx).ErrOut, err)
+		code = cmdutils.ExitCode(err)
+	}
+
+	if usage != nil {
+		if err := reportAPIUsage(ctx, usage, cfg.APIUsageReport); err != nil {
+			log.G(ctx).Error(err)
+		}
+	}
+
+	os.Exit(code)
+}
+
+// reportAPIUsage logs the number of GitHub API calls per operation and pull
+// request and writes the full breakdown to the provided CSV file.
+func reportAPIUsage(ctx context.Context, usage *ghapi.APIUsage, path string) error {
+	for _, totals := range []struct {
+		field  string
+		counts map[string]int
+	}{
+		{"operation", usage.ByOperation()},
+		{"pr", usage.ByPullRequest()},
+	} {
+		keys := make([]string, 0, len(totals.counts))
+		for key := range totals.counts {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			log.G(ctx).
+				WithField(totals.field, key).
+				WithField("calls", totals.counts[key]).
+				Info("api usage")
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create API usage report: %w", err)
+	}
+
+	defer f.Close()
+
+	if err := usage.WriteCSV(f); err != nil {
+		return fmt.Errorf("could not write API usage report: %w", err)
+	}
+
+	return nil
 }
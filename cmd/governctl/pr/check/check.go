@@ -11,12 +11,14 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"kraftkit.sh/cmdfactory"
+
+	"github.com/unikraft/governance/internal/cmdutils"
 )
 
 type Check struct{}
 
 func New() *cobra.Command {
-	cmd, err := cmdfactory.New(&Check{}, cobra.Command{
+	cmd, err := cmdutils.New(&Check{}, cobra.Command{
 		Use:   "check SUBCOMMAND",
 		Short: "Check information about a pull request",
 		Annotations: map[string]string{
@@ -28,6 +30,8 @@ func New() *cobra.Command {
 		panic(err)
 	}
 
+	cmd.AddCommand(NewCommits())
+	cmd.AddCommand(NewLicense())
 	cmd.AddCommand(NewMergable())
 	cmd.AddCommand(NewPatch())
 
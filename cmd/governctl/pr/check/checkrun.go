@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v63/github"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+// validateCheckRun fails fast when a check run is requested without the
+// credentials of a GitHub App, as check runs cannot be created with a personal
+// access token.
+func validateCheckRun(ctx context.Context, checkRun bool) error {
+	if !checkRun {
+		return nil
+	}
+
+	if !kitcfg.G[config.Config](ctx).HasGithubApp() {
+		return errors.New("--check-run requires the credentials of a GitHub App (--github-app-id, --github-app-installation-id and --github-app-private-key) as check runs cannot be created with a personal access token; report a commit status instead")
+	}
+
+	return config.Exclusive("check-run", checkRun, "read-only", kitcfg.G[config.Config](ctx).ReadOnly)
+}
+
+// checkRun reports the progress and the result of a check of a pull request
+// as a GitHub check run on the head of the pull request.
+type checkRun struct {
+	client *ghapi.GithubClient
+	org    string
+	repo   string
+	name   string
+	id     int64
+	dryRun bool
+}
+
+// startCheckRun creates a check run with the provided name which is in
+// progress on the head of the pull request.  In dry-run mode, nothing is
+// created and the result is only logged once completed.
+func startCheckRun(ctx context.Context, client *ghapi.GithubClient, org, repo string, prId int, name string) (*checkRun, error) {
+	run := &checkRun{
+		client: client,
+		org:    org,
+		repo:   repo,
+		name:   name,
+		dryRun: kitcfg.G[config.Config](ctx).DryRun,
+	}
+
+	pull, err := client.GetPullRequest(ctx, org, repo, prId)
+	if err != nil {
+		return nil, fmt.Errorf("could not get pull request: %w", err)
+	}
+
+	if run.dryRun {
+		log.G(ctx).
+			WithField("name", name).
+			WithField("sha", pull.GetHead().GetSHA()).
+			Info("would create check run")
+
+		return run, nil
+	}
+
+	run.id, err = client.CreateCheckRun(ctx, org, repo, name, pull.GetHead().GetSHA())
+	if err != nil {
+		return nil, err
+	}
+
+	log.G(ctx).
+		WithField("name", name).
+		WithField("id", run.id).
+		Info("created check run")
+
+	return run, nil
+}
+
+// complete concludes the check run with the provided outcome.  The summary
+// is rendered as markdown.
+func (run *checkRun) complete(ctx context.Context, conclusion, title, summary string, annotations []*github.CheckRunAnnotation) error {
+	if run.dryRun {
+		log.G(ctx).
+			WithField("name", run.name).
+			WithField("conclusion", conclusion).
+			WithField("annotations", len(annotations)).
+			Infof("would complete check run: %s", title)
+
+		return nil
+	}
+
+	if len(annotations) > ghapi.MaxCheckRunAnnotations {
+		log.G(ctx).
+			WithField("name", run.name).
+			Warnf("only attaching the first %d of %d annotations", ghapi.MaxCheckRunAnnotations, len(annotations))
+	}
+
+	return run.client.UpdateCheckRun(ctx, run.org, run.repo, run.id, run.name, conclusion, title, summary, annotations)
+}
+
+// fail concludes the check run as failed because of the provided error, which
+// is returned such that it can be propagated.
+func (run *checkRun) fail(ctx context.Context, err error) error {
+	if cerr := run.complete(ctx, "failure", "error", err.Error(), nil); cerr != nil {
+		log.G(ctx).Errorf("could not complete check run: %s", cerr)
+	}
+
+	return err
+}
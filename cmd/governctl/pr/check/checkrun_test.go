@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	kitcfg "kraftkit.sh/config"
+
+	"github.com/unikraft/governance/internal/checkpatch"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+func TestValidateCheckRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       config.Config
+		checkRun  bool
+		wantInErr string
+	}{
+		{
+			name: "not requested",
+		},
+		{
+			name:      "without app credentials",
+			checkRun:  true,
+			wantInErr: "--github-app-id",
+		},
+		{
+			name:     "with app credentials",
+			checkRun: true,
+			cfg:      config.Config{GithubAppID: 1, GithubAppInstallationID: 2, GithubAppPrivateKey: "app.pem"},
+		},
+		{
+			name:      "read-only",
+			checkRun:  true,
+			cfg:       config.Config{GithubAppID: 1, GithubAppInstallationID: 2, GithubAppPrivateKey: "app.pem", ReadOnly: true},
+			wantInErr: "--read-only",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgm, err := kitcfg.NewConfigManager(&tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+			err = validateCheckRun(ctx, tt.checkRun)
+			if tt.wantInErr == "" && err != nil {
+				t.Errorf("validateCheckRun() error = %v", err)
+			} else if tt.wantInErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantInErr)) {
+				t.Errorf("validateCheckRun() error = %v, want error containing %q", err, tt.wantInErr)
+			}
+		})
+	}
+}
+
+func TestCheckRunSummary(t *testing.T) {
+	var rows []string
+	for i := 0; i < maxCheckRunRows+5; i++ {
+		rows = append(rows, fmt.Sprintf("| abcdef%d | warning | LONG_LINE | too long | foo.c:%d |", i, i))
+	}
+
+	got := checkRunSummary("0 errors, 105 warnings and 0 checks", checkpatch.NoteLevelError, rows)
+
+	if !strings.HasPrefix(got, "checkpatch reported 0 errors, 105 warnings and 0 checks, failing on error level and above.") {
+		t.Errorf("unexpected summary:\n%s", got)
+	}
+
+	if strings.Count(got, "LONG_LINE") != maxCheckRunRows || !strings.Contains(got, "... and 5 more.") {
+		t.Errorf("expected %d rows and a note about the rest:\n%s", maxCheckRunRows, got)
+	}
+}
+
+// TestCheckRunDryRun checks that neither creating nor completing a check run
+// reaches GitHub in dry-run mode.
+func TestCheckRunDryRun(t *testing.T) {
+	var writes []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writes = append(writes, r.Method+" "+r.URL.Path)
+			fmt.Fprint(w, `{"id":1}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"number":1,"head":{"sha":"abc123"}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+	client, err := ghapi.NewGithubClient(ctx, "", false, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := startCheckRun(ctx, client, "unikraft", "unikraft", 1, "checkpatch")
+	if err != nil {
+		t.Fatalf("startCheckRun() unexpected error: %v", err)
+	}
+
+	if err := run.complete(ctx, "failure", "1 error", "", nil); err != nil {
+		t.Fatalf("complete() unexpected error: %v", err)
+	}
+
+	if len(writes) > 0 {
+		t.Errorf("unexpected writes in dry-run mode: %v", writes)
+	}
+}
@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/google/go-github/v63/github"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/commitmsg"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghpr"
+	"github.com/unikraft/governance/internal/tableprinter"
+)
+
+type Commits struct {
+	BaseBranch       string   `long:"base" env:"GOVERN_BASE_BRANCH" usage:"Set the base branch name that the PR will be rebased onto"`
+	CommitterEmail   string   `long:"committer-email" short:"e" env:"GOVERN_COMMITTER_EMAIL" usage:"Set the Git committer author's email" default:"monkey@unikraft.org"`
+	CommiterGlobal   bool     `long:"committer-global" env:"GOVERN_COMMITTER_GLOBAL" usage:"Set the Git committer author's email/name globally" default:"true"`
+	CommitterName    string   `long:"committer-name" short:"n" env:"GOVERN_COMMITTER_NAME" usage:"Set the Git committer author's name" default:"Unikraft Bot"`
+	MaxPatches       int      `long:"max-patches" env:"GOVERN_MAX_PATCHES" usage:"Maximum number of patches to generate for the PR" default:"500"`
+	MaxSubjectLength int      `long:"max-subject-length" env:"GOVERN_MAX_SUBJECT_LENGTH" usage:"Maximum number of characters of the subject of each commit" default:"72"`
+	NoAnnotations    bool     `long:"no-annotations" env:"GOVERN_NO_ANNOTATIONS" usage:"Do not annotate the PR with workflow commands when running in GitHub Actions"`
+	NoTable          bool     `long:"no-table" env:"GOVERN_NO_TABLE" usage:"Do not render the table of violations, e.g. when the annotations suffice"`
+	Output           string   `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of choice [table, csv, html, json, yaml]" default:"table"`
+	Columns          []string `long:"columns" env:"GOVERN_COLUMNS" usage:"Comma-separated columns of the table to render, in order [commit, level, rule, message]"`
+	SubjectPattern   string   `long:"subject-pattern" env:"GOVERN_SUBJECT_PATTERN" usage:"Regular expression which the subject of each commit must match (default: an area such as lib/ukboot, a colon and a capitalised summary)"`
+	Warnings         []string `long:"warnings" env:"GOVERN_WARNINGS" usage:"Rules whose violations are reported as warnings without failing the check [subject-prefix, subject-length, body, signoff, merge-commit]"`
+}
+
+// commitsColumns are the columns of the table of violations.
+var commitsColumns = []string{"COMMIT", "LEVEL", "RULE", "MESSAGE"}
+
+func NewCommits() *cobra.Command {
+	cmd, err := cmdutils.New(&Commits{}, cobra.Command{
+		Use:   "commits [OPTIONS] ORG/REPO/PRID",
+		Short: "Check the commit messages of a pull request",
+		Long: heredoc.Doc(`
+		Check the commit messages of a pull request
+
+		The subject of each commit must name the area of the change followed
+		by a capitalised summary, e.g. "lib/ukboot: Initialise the console",
+		and must not be too long.  The message must have a body and carry a
+		Signed-off-by trailer of the author of the commit.  The pull request
+		must not contain merge commits.
+		`),
+		Args: cobra.MaximumNArgs(2),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "pr",
+		},
+		Example: heredoc.Doc(`
+		# Check the commit messages of PR #1000
+		governctl pr check commits unikraft/unikraft/1000
+
+		# Only warn about long subjects and missing bodies
+		governctl pr check commits --warnings=subject-length,body unikraft/unikraft/1000
+		`),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+// Validate rejects invalid patterns and rules before the pull request is
+// prepared.
+func (opts *Commits) Validate(_ context.Context) error {
+	if err := config.ValidateCommitter(opts.CommitterName, opts.CommitterEmail, opts.CommiterGlobal); err != nil {
+		return err
+	}
+
+	if _, err := opts.checker(); err != nil {
+		return err
+	}
+
+	if err := tableprinter.ValidateColumns(commitsColumns, opts.Columns); err != nil {
+		return fmt.Errorf("invalid --columns: %w", err)
+	}
+
+	if err := config.NotNegative("max-subject-length", opts.MaxSubjectLength); err != nil {
+		return err
+	}
+
+	return config.NotNegative("max-patches", opts.MaxPatches)
+}
+
+// checker returns the commit message checker configured by the flags.
+func (opts *Commits) checker() (*commitmsg.Checker, error) {
+	return commitmsg.NewChecker(
+		commitmsg.WithSubjectPattern(opts.SubjectPattern),
+		commitmsg.WithMaxSubjectLength(opts.MaxSubjectLength),
+		commitmsg.WithWarnings(opts.Warnings...),
+	)
+}
+
+// mergeCommits returns the hashes of the merge commits amongst the provided
+// commits.
+func mergeCommits(commits []*github.RepositoryCommit) []string {
+	var hashes []string
+
+	for _, commit := range commits {
+		if len(commit.Parents) > 1 {
+			hashes = append(hashes, commit.GetSHA())
+		}
+	}
+
+	return hashes
+}
+
+func (opts *Commits) Run(ctx context.Context, args []string) error {
+	ghOrg, ghRepo, ghPrId, err := cmdutils.ParseOrgRepoAndPullRequestArgs(args)
+	if err != nil {
+		return err
+	}
+
+	checker, err := opts.checker()
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	annotate, render := reportModes(cmdutils.InGithubActions(), opts.NoAnnotations, opts.NoTable)
+
+	// Merge commits are dropped when the pull request is rebased onto its base
+	// and are therefore determined from the original commits.
+	commits, err := ghClient.GetPullRequestCommits(ctx, ghOrg, ghRepo, ghPrId)
+	if err != nil {
+		return fmt.Errorf("could not list commits: %w", err)
+	}
+
+	pull, err := ghpr.NewPullRequestFromID(ctx,
+		ghClient,
+		ghOrg,
+		ghRepo,
+		opts.CommitterName,
+		opts.CommitterEmail,
+		ghPrId,
+		opts.CommiterGlobal,
+		ghpr.WithBaseBranch(opts.BaseBranch),
+		ghpr.WithWorkdir(kitcfg.G[config.Config](ctx).TempDir),
+		ghpr.WithCredentials(kitcfg.G[config.Config](ctx).GithubUser, kitcfg.G[config.Config](ctx).GithubToken),
+		ghpr.WithGitBinary(kitcfg.G[config.Config](ctx).GitBinary),
+		ghpr.WithMaxPatches(opts.MaxPatches),
+		ghpr.WithGitHubActionsAnnotations(annotate),
+	)
+	if err != nil {
+		return fmt.Errorf("could not prepare pull request: %w", err)
+	}
+
+	// If the user has not specified a temporary directory which will have been
+	// passed as the working directory, a temporary one will have been generated.
+	if kitcfg.G[config.Config](ctx).TempDir == "" {
+		defer func() {
+			log.G(ctx).WithField("path", pull.Workdir()).Info("removing")
+			os.RemoveAll(pull.Workdir())
+		}()
+	}
+
+	violations := append(
+		checker.CheckMergeCommits(mergeCommits(commits)...),
+		checker.Check(pull.Patches()...)...,
+	)
+
+	cs := iostreams.G(ctx).ColorScheme()
+
+	if len(violations) == 0 {
+		fmt.Fprintf(iostreams.G(ctx).Out, "%s commit messages of all commits are valid\n", cs.Green("✔"))
+		return nil
+	}
+
+	topts := []tableprinter.TablePrinterOption{
+		tableprinter.WithOutputFormatFromString(opts.Output),
+		tableprinter.WithColumns(opts.Columns...),
+	}
+
+	if kitcfg.G[config.Config](ctx).NoRender {
+		topts = append(topts, tableprinter.WithMaxWidth(10000))
+	} else {
+		topts = append(topts, tableprinter.WithMaxWidth(iostreams.G(ctx).TerminalWidth()))
+	}
+
+	table, err := tableprinter.NewTablePrinter(ctx, topts...)
+	if err != nil {
+		return err
+	}
+
+	table.AddHeader(cs.Bold, commitsColumns...)
+
+	failed := 0
+	for _, violation := range violations {
+		level, annotation := cs.Red, ghpr.AnnotationError
+		if violation.Level == commitmsg.LevelWarning {
+			level, annotation = cs.Yellow, ghpr.AnnotationWarning
+		} else {
+			failed++
+		}
+
+		table.AddField(violation.Commit[0:7], nil)
+		table.AddField(violation.Level, level)
+		table.AddField(violation.Rule, nil)
+		table.AddField(violation.Message, nil)
+		table.EndRow()
+
+		// Annotate the PR if run in a GitHub Actions context.
+		pull.Annotate(iostreams.G(ctx).Out, annotation, "", 0, violation.Rule, violation.String())
+	}
+
+	if render {
+		if err := table.Render(iostreams.G(ctx).Out); err != nil {
+			return err
+		}
+	}
+
+	if commitmsg.Failed(violations) {
+		return fmt.Errorf("commit message check failed with %d errors and %d warnings", failed, len(violations)-failed)
+	}
+
+	fmt.Fprintf(iostreams.G(ctx).Out, "%s commit message check passed with %d warnings\n", cs.Green("✔"), len(violations))
+
+	return nil
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package check
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+)
+
+func TestMergeCommits(t *testing.T) {
+	commit := func(sha string, parents int) *github.RepositoryCommit {
+		c := &github.RepositoryCommit{SHA: github.String(sha)}
+		for i := 0; i < parents; i++ {
+			c.Parents = append(c.Parents, &github.Commit{})
+		}
+		return c
+	}
+
+	got := mergeCommits([]*github.RepositoryCommit{
+		commit("aaaaaaa", 1),
+		commit("bbbbbbb", 2),
+		commit("ccccccc", 1),
+	})
+
+	if want := []string{"bbbbbbb"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeCommits() = %v, want %v", got, want)
+	}
+}
+
+func TestCommitsValidate(t *testing.T) {
+	valid := func() *Commits {
+		return &Commits{
+			CommitterName:    "Unikraft Bot",
+			CommitterEmail:   "monkey@unikraft.org",
+			MaxSubjectLength: 72,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Commits)
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			modify: func(*Commits) {},
+		},
+		{
+			name:   "warnings",
+			modify: func(c *Commits) { c.Warnings = []string{"body", "subject-length"} },
+		},
+		{
+			name:    "unknown warning",
+			modify:  func(c *Commits) { c.Warnings = []string{"spelling"} },
+			wantErr: true,
+		},
+		{
+			name:    "invalid subject pattern",
+			modify:  func(c *Commits) { c.SubjectPattern = "(" },
+			wantErr: true,
+		},
+		{
+			name:    "negative subject length",
+			modify:  func(c *Commits) { c.MaxSubjectLength = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "unknown column",
+			modify:  func(c *Commits) { c.Columns = []string{"file"} },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := valid()
+			tt.modify(opts)
+
+			if err := opts.Validate(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
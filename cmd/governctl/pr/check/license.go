@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghpr"
+	"github.com/unikraft/governance/internal/license"
+	"github.com/unikraft/governance/internal/tableprinter"
+)
+
+type License struct {
+	BaseBranch       string   `long:"base" env:"GOVERN_BASE_BRANCH" usage:"Set the base branch name that the PR will be rebased onto"`
+	CommitterEmail   string   `long:"committer-email" short:"e" env:"GOVERN_COMMITTER_EMAIL" usage:"Set the Git committer author's email" default:"monkey@unikraft.org"`
+	CommiterGlobal   bool     `long:"committer-global" env:"GOVERN_COMMITTER_GLOBAL" usage:"Set the Git committer author's email/name globally" default:"true"`
+	CommitterName    string   `long:"committer-name" short:"n" env:"GOVERN_COMMITTER_NAME" usage:"Set the Git committer author's name" default:"Unikraft Bot"`
+	CopyrightPattern string   `long:"copyright-pattern" env:"GOVERN_COPYRIGHT_PATTERN" usage:"Regular expression which the copyright line of new files must match"`
+	Exclude          []string `long:"exclude" env:"GOVERN_EXCLUDE" usage:"Globs of files to skip in addition to vendored code, binary assets and data files"`
+	HeaderLines      int      `long:"header-lines" env:"GOVERN_HEADER_LINES" usage:"Number of leading lines of new files in which the header is expected" default:"10"`
+	Licenses         []string `long:"licenses" env:"GOVERN_LICENSES" usage:"SPDX identifiers which new files may carry" default:"BSD-3-Clause"`
+	MaxPatches       int      `long:"max-patches" env:"GOVERN_MAX_PATCHES" usage:"Maximum number of patches to generate for the PR" default:"500"`
+	NoAnnotations    bool     `long:"no-annotations" env:"GOVERN_NO_ANNOTATIONS" usage:"Do not annotate the PR with workflow commands when running in GitHub Actions"`
+	NoTable          bool     `long:"no-table" env:"GOVERN_NO_TABLE" usage:"Do not render the table of violations, e.g. when the annotations suffice"`
+	Output           string   `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of choice [table, csv, html, json, yaml]" default:"table"`
+	Columns          []string `long:"columns" env:"GOVERN_COLUMNS" usage:"Comma-separated columns of the table to render, in order [commit, file, reason]"`
+}
+
+// licenseColumns are the columns of the table of violations.
+var licenseColumns = []string{"COMMIT", "FILE", "REASON"}
+
+func NewLicense() *cobra.Command {
+	cmd, err := cmdutils.New(&License{}, cobra.Command{
+		Use:   "license [OPTIONS] ORG/REPO/PRID",
+		Short: "Check the license header of files added by a pull request",
+		Long: heredoc.Doc(`
+		Check the license header of files added by a pull request
+
+		Each file added by a commit of the pull request must carry one of the
+		allowed SPDX identifiers and a copyright line within its first lines.
+		Vendored code, binary assets and data files are skipped.
+		`),
+		Args: cobra.MaximumNArgs(2),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "pr",
+		},
+		Example: heredoc.Doc(`
+		# Check the headers of the files added by PR #1000
+		governctl pr check license unikraft/unikraft/1000
+
+		# Additionally allow MIT-licensed files and skip the test fixtures
+		governctl pr check license --licenses=BSD-3-Clause,MIT --exclude='tests/fixtures/' unikraft/unikraft/1000
+		`),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+// Validate rejects invalid patterns before the pull request is prepared.
+func (opts *License) Validate(_ context.Context) error {
+	if err := config.ValidateCommitter(opts.CommitterName, opts.CommitterEmail, opts.CommiterGlobal); err != nil {
+		return err
+	}
+
+	if _, err := opts.checker(); err != nil {
+		return err
+	}
+
+	if err := tableprinter.ValidateColumns(licenseColumns, opts.Columns); err != nil {
+		return fmt.Errorf("invalid --columns: %w", err)
+	}
+
+	return config.NotNegative("max-patches", opts.MaxPatches)
+}
+
+// checker returns the license checker configured by the flags.
+func (opts *License) checker() (*license.Checker, error) {
+	return license.NewChecker(
+		license.WithAllowedLicenses(opts.Licenses...),
+		license.WithCopyrightPattern(opts.CopyrightPattern),
+		license.WithHeaderLines(opts.HeaderLines),
+		license.WithExclude(opts.Exclude...),
+	)
+}
+
+func (opts *License) Run(ctx context.Context, args []string) error {
+	ghOrg, ghRepo, ghPrId, err := cmdutils.ParseOrgRepoAndPullRequestArgs(args)
+	if err != nil {
+		return err
+	}
+
+	checker, err := opts.checker()
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	annotate, render := reportModes(cmdutils.InGithubActions(), opts.NoAnnotations, opts.NoTable)
+
+	pull, err := ghpr.NewPullRequestFromID(ctx,
+		ghClient,
+		ghOrg,
+		ghRepo,
+		opts.CommitterName,
+		opts.CommitterEmail,
+		ghPrId,
+		opts.CommiterGlobal,
+		ghpr.WithBaseBranch(opts.BaseBranch),
+		ghpr.WithWorkdir(kitcfg.G[config.Config](ctx).TempDir),
+		ghpr.WithCredentials(kitcfg.G[config.Config](ctx).GithubUser, kitcfg.G[config.Config](ctx).GithubToken),
+		ghpr.WithGitBinary(kitcfg.G[config.Config](ctx).GitBinary),
+		ghpr.WithMaxPatches(opts.MaxPatches),
+		ghpr.WithGitHubActionsAnnotations(annotate),
+	)
+	if err != nil {
+		return fmt.Errorf("could not prepare pull request: %w", err)
+	}
+
+	// If the user has not specified a temporary directory which will have been
+	// passed as the working directory, a temporary one will have been generated.
+	if kitcfg.G[config.Config](ctx).TempDir == "" {
+		defer func() {
+			log.G(ctx).WithField("path", pull.Workdir()).Info("removing")
+			os.RemoveAll(pull.Workdir())
+		}()
+	}
+
+	violations := checker.Check(pull.Patches()...)
+
+	cs := iostreams.G(ctx).ColorScheme()
+
+	if len(violations) == 0 {
+		fmt.Fprintf(iostreams.G(ctx).Out, "%s license headers of all new files are valid\n", cs.Green("✔"))
+		return nil
+	}
+
+	topts := []tableprinter.TablePrinterOption{
+		tableprinter.WithOutputFormatFromString(opts.Output),
+		tableprinter.WithColumns(opts.Columns...),
+	}
+
+	if kitcfg.G[config.Config](ctx).NoRender {
+		topts = append(topts, tableprinter.WithMaxWidth(10000))
+	} else {
+		topts = append(topts, tableprinter.WithMaxWidth(iostreams.G(ctx).TerminalWidth()))
+	}
+
+	table, err := tableprinter.NewTablePrinter(ctx, topts...)
+	if err != nil {
+		return err
+	}
+
+	table.AddHeader(cs.Bold, licenseColumns...)
+
+	for _, violation := range violations {
+		table.AddField(violation.Patch[0:7], nil)
+		table.AddField(violation.File, nil)
+		table.AddField(violation.Reason, cs.Red)
+		table.EndRow()
+
+		// Annotate the PR if run in a GitHub Actions context.
+		pull.Annotate(iostreams.G(ctx).Out, ghpr.AnnotationError, violation.File, 1, "license", violation.Reason)
+	}
+
+	if render {
+		if err := table.Render(iostreams.G(ctx).Out); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("license check failed for: %s", strings.Join(license.Files(violations), ", "))
+}
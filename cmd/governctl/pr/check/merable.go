@@ -11,43 +11,74 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
 	"kraftkit.sh/cmdfactory"
 	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
 	"kraftkit.sh/log"
 
 	"github.com/unikraft/governance/internal/cmdutils"
 	"github.com/unikraft/governance/internal/config"
 	"github.com/unikraft/governance/internal/ghapi"
 	"github.com/unikraft/governance/internal/ghpr"
+	"github.com/unikraft/governance/internal/hook"
+	"github.com/unikraft/governance/internal/tableprinter"
 )
 
 type Mergable struct {
-	ApproverComments   []string `long:"approver-comments" env:"GOVERN_APPROVER_COMMENTS" usage:"Regular expression that an approver writes"`
-	ApproverTeams      []string `long:"approver-teams" env:"GOVERN_APPROVER_TEAMS" usage:"The GitHub team that the approver must be a part of to be considered an approver"`
-	ApproveStates      []string `long:"approve-states" env:"GOVERN_APPROVE_STATES" usage:"The state of the GitHub approval from the assignee" default:"approve"`
-	CommitterEmail     string   `long:"committer-email" short:"e" env:"GOVERN_COMMITTER_EMAIL" usage:"Set the Git committer author's email"`
-	CommitterGlobal    bool     `long:"committer-global" env:"GOVERN_COMMITTER_GLOBAL" usage:"Set the Git committer author's email/name globally"`
-	CommitterName      string   `long:"committer-name" short:"n" env:"GOVERN_COMMITTER_NAME" usage:"Set the Git committer author's name"`
-	IgnoreLabels       []string `long:"ignore-labels" env:"GOVERN_IGNORE_LABELS" usage:"Ignore the PR if it has any of these labels"`
-	IgnoreStates       []string `long:"ignore-states" env:"GOVERN_IGNORE_STATES" usage:"Ignore the PR if it has any of these states"`
-	Labels             []string `long:"labels" env:"GOVERN_LABELS" usage:"The PR must have these labels to be considered mergable"`
-	MinApprovals       int      `long:"min-approvals" env:"GOVERN_MIN_APPROVALS" usage:"Minimum number of approvals required to be considered mergable" default:"1"`
-	MinReviews         int      `long:"min-reviews" env:"GOVERN_MIN_REVIEWS" usage:"Minimum number of reviews a PR requires to be considered mergable" default:"1"`
-	NoConflicts        bool     `long:"no-conflicts" env:"GOVERN_NO_CONFLICTS" usage:"Pull request must not have any conflicts"`
-	NoDraft            bool     `long:"no-draft" env:"GOVERN_NO_DRAFT" usage:"Pull request must not be in a draft state"`
-	NoRespectAssignees bool     `long:"no-respect-assignees" env:"GOVERN_NO_RESPECT_ASSIGNEES" usage:"Whether the PR's assignees should be not considered approvers even if they are not part of a team/codeowner"`
-	NoRespectReviewers bool     `long:"no-respect-reviewers" env:"GOVERN_NO_RESPECT_REVIEWERS" usage:"Whether the PR's requested reviewers review should not be considered even if they are not part of a team/codeowner"`
-	ReviewerComments   []string `long:"reviewer-comments" env:"GOVERN_REVIEWER_COMMENTS" usage:"Regular expression that a reviewer writes"`
-	ReviewerTeams      []string `long:"reviewer-teams" env:"GOVERN_REVIEWER_TEAMS" usage:"The GitHub team that the reviewer must be a part to be considered a reviewer"`
-	ReviewStates       []string `long:"review-states" env:"GOVERN_REVIEW_STATES" usage:"The state of the GitHub approval from the reivewer"`
-	States             []string `long:"states" env:"GOVERN_STATES" usage:"Consider the PR mergable if it has one of these supplied states"`
+	AllowedBaseBranches     []string `long:"allowed-base-branches" env:"GOVERN_ALLOWED_BASE_BRANCHES" usage:"The PR must be based on one of these branches to be considered mergable (default: any)"`
+	ApproverComments        []string `long:"approver-comments" env:"GOVERN_APPROVER_COMMENTS" usage:"Regular expression that an approver writes"`
+	ApproverTeams           []string `long:"approver-teams" env:"GOVERN_APPROVER_TEAMS" usage:"The GitHub team that the approver must be a part of to be considered an approver"`
+	ApproveStates           []string `long:"approve-states" env:"GOVERN_APPROVE_STATES" usage:"The review states of approvals from the assignee [approved (approve), changes_requested (request_changes), commented, dismissed, pending, comment]; comments always count" default:"approve"`
+	BlockOnChangesRequested bool     `long:"block-on-changes-requested" env:"GOVERN_BLOCK_ON_CHANGES_REQUESTED" usage:"Block the PR whilst the most recent review of anyone, not only of an eligible reviewer, requests changes"`
+	BotLabels               []string `long:"bot-labels" env:"GOVERN_BOT_LABELS" usage:"Labels which mark a PR as an automated dependency update (default: dependencies)"`
+	BotLogins               []string `long:"bot-logins" env:"GOVERN_BOT_LOGINS" usage:"Authors whose PRs are automated dependency updates (default: dependabot[bot], renovate[bot])"`
+	BotPolicy               string   `long:"bot-policy" env:"GOVERN_BOT_POLICY" usage:"Merge requirements of automated dependency updates [review, checks]" default:"review"`
+	CheckRun                bool     `long:"check-run" env:"GOVERN_CHECK_RUN" usage:"Report the result as a check run on the PR (requires a GitHub App)"`
+	As                      string   `long:"as" env:"GOVERN_AS" usage:"Preview whether the PR would be mergable if this GitHub user approved it"`
+	At                      string   `long:"at" env:"GOVERN_AT" usage:"Evaluate the PR as it was at this RFC 3339 timestamp or commit SHA, for audits"`
+	AsState                 string   `long:"as-state" env:"GOVERN_AS_STATE" usage:"The review state of the previewed approval, as for --approve-states" default:"approve"`
+	CommitterEmail          string   `long:"committer-email" short:"e" env:"GOVERN_COMMITTER_EMAIL" usage:"Set the Git committer author's email"`
+	CommitterGlobal         bool     `long:"committer-global" env:"GOVERN_COMMITTER_GLOBAL" usage:"Set the Git committer author's email/name globally"`
+	CommitterName           string   `long:"committer-name" short:"n" env:"GOVERN_COMMITTER_NAME" usage:"Set the Git committer author's name"`
+	IgnoreAuthors           []string `long:"ignore-authors" env:"GOVERN_IGNORE_AUTHORS" usage:"Logins, which may contain *, whose comments and reviews are not counted (default: *[bot])"`
+	IgnoreChangesRequested  bool     `long:"ignore-changes-requested" env:"GOVERN_IGNORE_CHANGES_REQUESTED" usage:"Do not block the PR whilst a reviewer's most recent review requests changes"`
+	IgnoreLabels            []string `long:"ignore-labels" env:"GOVERN_IGNORE_LABELS" usage:"Ignore the PR if it has any of these labels"`
+	IgnoreStates            []string `long:"ignore-states" env:"GOVERN_IGNORE_STATES" usage:"Ignore the PR if it has any of these states"`
+	IgnoreUnreadableTeams   bool     `long:"ignore-unreadable-teams" env:"GOVERN_IGNORE_UNREADABLE_TEAMS" usage:"Skip approver and reviewer teams which are not visible to the token instead of failing"`
+	Labels                  []string `long:"labels" env:"GOVERN_LABELS" usage:"The PR must have these labels to be considered mergable"`
+	MinApprovals            int      `long:"min-approvals" env:"GOVERN_MIN_APPROVALS" usage:"Minimum number of approvals required to be considered mergable" default:"1"`
+	MinReviews              int      `long:"min-reviews" env:"GOVERN_MIN_REVIEWS" usage:"Minimum number of reviews a PR requires to be considered mergable" default:"1"`
+	NoConflicts             bool     `long:"no-conflicts" env:"GOVERN_NO_CONFLICTS" usage:"Pull request must not have any conflicts"`
+	NoDraft                 bool     `long:"no-draft" env:"GOVERN_NO_DRAFT" usage:"Pull request must not be in a draft state"`
+	NoRespectAssignees      bool     `long:"no-respect-assignees" env:"GOVERN_NO_RESPECT_ASSIGNEES" usage:"Whether the PR's assignees should be not considered approvers even if they are not part of a team/codeowner"`
+	NoRespectReviewers      bool     `long:"no-respect-reviewers" env:"GOVERN_NO_RESPECT_REVIEWERS" usage:"Whether the PR's requested reviewers review should not be considered even if they are not part of a team/codeowner"`
+	Output                  string   `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of choice [json, table, csv, yaml, markdown]" default:"json"`
+	RequireSignoff          bool     `long:"require-signoff" env:"GOVERN_REQUIRE_SIGNOFF" usage:"Every commit must be signed off by its author (DCO)"`
+	RequiredApprovers       []string `long:"required-approvers" env:"GOVERN_REQUIRED_APPROVERS" usage:"Logins of users who must each approve the PR in addition to the minimum number of approvals"`
+	RequiredChecks          []string `long:"required-checks" env:"GOVERN_REQUIRED_CHECKS" usage:"Statuses and check runs which must have succeeded on the head of the PR"`
+	ResultFile              string   `long:"result-file" env:"GOVERN_RESULT_FILE" usage:"Write the evaluation result to this file for pr merge --from-result"`
+	ReviewerComments        []string `long:"reviewer-comments" env:"GOVERN_REVIEWER_COMMENTS" usage:"Regular expression that a reviewer writes"`
+	ReviewerTeams           []string `long:"reviewer-teams" env:"GOVERN_REVIEWER_TEAMS" usage:"The GitHub team that the reviewer must be a part to be considered a reviewer"`
+	ReviewStates            []string `long:"review-states" env:"GOVERN_REVIEW_STATES" usage:"The review states of reviews from the reviewer, as for --approve-states (default: all)"`
+	Rules                   string   `long:"rules" env:"GOVERN_RULES" usage:"YAML file describing the merge requirements, which flags override"`
+	SetStatus               bool     `long:"set-status" env:"GOVERN_SET_STATUS" usage:"Report the result as the governance/mergable commit status on the head of the PR"`
+	States                  []string `long:"states" env:"GOVERN_STATES" usage:"Consider the PR mergable if it has one of these supplied states"`
+	TeamMinApprovals        []string `long:"team-min-approvals" env:"GOVERN_TEAM_MIN_APPROVALS" usage:"Minimum number of approvals from members of a team, as TEAM=N"`
+
+	// teamMinApprovals are the per-team minimums from the ruleset file, which
+	// --team-min-approvals overrides.
+	teamMinApprovals map[string]int
 }
 
 func NewMergable() *cobra.Command {
-	cmd, err := cmdfactory.New(&Mergable{}, cobra.Command{
+	cmd, err := cmdutils.New(&Mergable{}, cobra.Command{
 		Use:   "mergable [OPTIONS] ORG/REPO/PRID",
 		Short: "Check whether a PR satisfies the provided merge requirements",
 		Args:  cobra.MaximumNArgs(2),
@@ -63,6 +94,39 @@ func NewMergable() *cobra.Command {
 			--review-states=approved \
 			--ignore-labels="ci/wait" \
 			unikraft/unikraft/1078
+
+		# Report the requirements of the PR as a check run
+		governctl pr check mergable \
+			--github-app-id=1234 \
+			--github-app-installation-id=5678 \
+			--github-app-private-key=app.pem \
+			--check-run \
+			unikraft/unikraft/1078
+
+		# Preview whether the PR would be mergable if octocat approved it
+		governctl pr check mergable --as octocat unikraft/unikraft/1078
+
+		# Audit whether the PR was mergable when its merge commit was created
+		governctl pr check mergable --at 5f3c2a1 --states closed unikraft/unikraft/1078
+
+		# Summarise the requirements of the PR as a markdown table, e.g. to post
+		# it as a comment
+		governctl pr check mergable --output=markdown unikraft/unikraft/1078
+
+		# Report the requirements of the PR as a commit status, which branch
+		# protection can require
+		governctl pr check mergable --set-status unikraft/unikraft/1078
+
+		# Record the evaluation such that pr merge does not evaluate it again
+		governctl pr check mergable --result-file=result.json unikraft/unikraft/1078
+		governctl pr merge --from-result=result.json unikraft/unikraft/1078
+
+		# Check the PR against the requirements in a ruleset file, requiring
+		# two approvals regardless of the file
+		governctl pr check mergable \
+			--rules=.github/mergable.yaml \
+			--min-approvals=2 \
+			unikraft/unikraft/1078
 		`),
 	})
 	if err != nil {
@@ -72,22 +136,386 @@ func NewMergable() *cobra.Command {
 	return cmd
 }
 
+// Validate rejects invalid settings before the pull request is evaluated, e.g.
+// simulating an attestation with --as together with --at, --check-run,
+// --result-file or --set-status, blocking on and ignoring requested changes at
+// once, malformed comment expressions or review states and negative minimums.
+func (opts *Mergable) Validate(ctx context.Context) error {
+	if err := config.ValidateCommitter(opts.CommitterName, opts.CommitterEmail, opts.CommitterGlobal); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("check-run", opts.CheckRun, "as", opts.As != ""); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("at", opts.At != "", "check-run", opts.CheckRun); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("at", opts.At != "", "as", opts.As != ""); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("result-file", opts.ResultFile != "", "as", opts.As != ""); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("result-file", opts.ResultFile != "", "at", opts.At != ""); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("set-status", opts.SetStatus, "as", opts.As != ""); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("set-status", opts.SetStatus, "at", opts.At != ""); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("set-status", opts.SetStatus, "read-only", kitcfg.G[config.Config](ctx).ReadOnly); err != nil {
+		return err
+	}
+
+	if err := validateMergableOutput(opts.Output); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("as", opts.As != "", "output="+opts.Output, opts.Output != string(tableprinter.OutputFormatJSON)); err != nil {
+		return err
+	}
+
+	if err := validateCheckRun(ctx, opts.CheckRun); err != nil {
+		return err
+	}
+
+	if err := ghpr.ValidateBotPolicy(opts.BotPolicy); err != nil {
+		return err
+	}
+
+	if err := ghpr.ValidateCommentExpressions(opts.ApproverComments); err != nil {
+		return fmt.Errorf("invalid --approver-comments: %w", err)
+	}
+
+	if err := ghpr.ValidateCommentExpressions(opts.ReviewerComments); err != nil {
+		return fmt.Errorf("invalid --reviewer-comments: %w", err)
+	}
+
+	if err := config.Exclusive("block-on-changes-requested", opts.BlockOnChangesRequested, "ignore-changes-requested", opts.IgnoreChangesRequested); err != nil {
+		return err
+	}
+
+	if err := ghpr.ValidateReviewStates(opts.ApproveStates); err != nil {
+		return fmt.Errorf("invalid --approve-states: %w", err)
+	}
+
+	if err := ghpr.ValidateReviewStates(opts.ReviewStates); err != nil {
+		return fmt.Errorf("invalid --review-states: %w", err)
+	}
+
+	if _, err := ghpr.ParseReviewState(opts.AsState); opts.As != "" && err != nil {
+		return fmt.Errorf("invalid --as-state: %w", err)
+	}
+
+	if err := config.NotNegative("min-approvals", opts.MinApprovals); err != nil {
+		return err
+	}
+
+	if err := config.NotNegative("min-reviews", opts.MinReviews); err != nil {
+		return err
+	}
+
+	_, err := parseTeamMinApprovals(opts.TeamMinApprovals)
+	return err
+}
+
+// mergableOutputs are the output formats of the verdict, where JSON is the
+// historical, machine-readable map of the collected trailers and all other
+// formats render the requirements as a table.
+var mergableOutputs = []tableprinter.TableOutputFormat{
+	tableprinter.OutputFormatJSON,
+	tableprinter.OutputFormatTable,
+	tableprinter.OutputFormatCSV,
+	tableprinter.OutputFormatYAML,
+	tableprinter.OutputFormatMarkdown,
+}
+
+// validateMergableOutput rejects output formats other than mergableOutputs.
+func validateMergableOutput(output string) error {
+	for _, format := range mergableOutputs {
+		if output == string(format) {
+			return nil
+		}
+	}
+
+	formats := make([]string, 0, len(mergableOutputs))
+	for _, format := range mergableOutputs {
+		formats = append(formats, string(format))
+	}
+
+	return fmt.Errorf("unknown output format '%s': expected one of [%s]", output, strings.Join(formats, ", "))
+}
+
+// mergableColumns are the columns of the table of requirements.
+var mergableColumns = []string{"REQUIREMENT", "STATUS", "DETAILS"}
+
+// mergableRow is a single requirement of the verdict, a collected trailer or
+// the verdict itself.
+type mergableRow struct {
+	Requirement string
+	Status      string
+	Details     string
+}
+
+// mergableRows returns the requirements of the verdict, followed by the
+// collected trailers and the overall verdict.
+func mergableRows(verdict *ghpr.MergeVerdict) []mergableRow {
+	met := func(ok bool) string {
+		if ok {
+			return "met"
+		}
+
+		return "unmet"
+	}
+
+	var rows []mergableRow
+
+	if verdict.At != nil {
+		rows = append(rows, mergableRow{"Evaluated as of", "", fmt.Sprintf("%s at %s", verdict.At.Format(time.RFC3339), verdict.HeadSHA)})
+	}
+
+	if verdict.Bot {
+		rows = append(rows, mergableRow{"Bot policy", met(true), "automated dependency update"})
+	}
+
+	rows = append(rows,
+		mergableRow{"Approvals", met(verdict.Approvals >= verdict.MinApprovals), fmt.Sprintf("%d/%d", verdict.Approvals, verdict.MinApprovals)},
+		mergableRow{"Reviews", met(verdict.Reviews >= verdict.MinReviews), fmt.Sprintf("%d/%d", verdict.Reviews, verdict.MinReviews)},
+	)
+
+	teams := make([]string, 0, len(verdict.TeamApprovals))
+	for team := range verdict.TeamApprovals {
+		teams = append(teams, team)
+	}
+
+	sort.Strings(teams)
+
+	for _, team := range teams {
+		row := mergableRow{"Approvals from " + team, met(true), strconv.Itoa(verdict.TeamApprovals[team])}
+		for _, short := range verdict.ShortTeams {
+			if strings.HasPrefix(short, team+" ") {
+				row.Status, row.Details = met(false), strings.TrimPrefix(short, team+" ")
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(verdict.MissingApprovers) > 0 {
+		rows = append(rows, mergableRow{"Required approvers", met(false), strings.Join(verdict.MissingApprovers, ", ")})
+	}
+
+	if len(verdict.ChangesRequested) > 0 {
+		rows = append(rows, mergableRow{"No changes requested", met(false), strings.Join(verdict.ChangesRequested, ", ")})
+	}
+
+	if len(verdict.FailingChecks) > 0 {
+		rows = append(rows, mergableRow{"Checks", met(false), strings.Join(verdict.FailingChecks, ", ")})
+	}
+
+	if len(verdict.UnsignedCommits) > 0 {
+		rows = append(rows, mergableRow{"Sign-off", met(false), strings.Join(verdict.UnsignedCommits, ", ")})
+	}
+
+	trailers := make([]string, 0, len(verdict.Result))
+	for trailer := range verdict.Result {
+		trailers = append(trailers, trailer)
+	}
+
+	sort.Strings(trailers)
+
+	for _, trailer := range trailers {
+		rows = append(rows, mergableRow{"Trailer " + trailer, "", strings.Join(verdict.Result[trailer], ", ")})
+	}
+
+	verdictRow := mergableRow{"Mergable", met(true), ""}
+	if err := verdict.Err(); err != nil {
+		verdictRow.Status, verdictRow.Details = met(false), err.Error()
+	}
+
+	return append(rows, verdictRow)
+}
+
+// renderVerdict renders the requirements of the verdict as a table in any
+// output format other than JSON.
+func (opts *Mergable) renderVerdict(ctx context.Context, verdict *ghpr.MergeVerdict) error {
+	topts := []tableprinter.TablePrinterOption{
+		tableprinter.WithOutputFormatFromString(opts.Output),
+	}
+
+	if kitcfg.G[config.Config](ctx).NoRender {
+		topts = append(topts, tableprinter.WithMaxWidth(10000))
+	} else {
+		topts = append(topts, tableprinter.WithMaxWidth(iostreams.G(ctx).TerminalWidth()))
+	}
+
+	table, err := tableprinter.NewTablePrinter(ctx, topts...)
+	if err != nil {
+		return err
+	}
+
+	cs := iostreams.G(ctx).ColorScheme()
+
+	table.AddHeader(cs.Bold, mergableColumns...)
+
+	for _, row := range mergableRows(verdict) {
+		var color func(string) string
+		switch row.Status {
+		case "met":
+			color = cs.Green
+		case "unmet":
+			color = cs.Red
+		}
+
+		table.AddField(row.Requirement, nil)
+		table.AddField(row.Status, color)
+		table.AddField(row.Details, nil)
+		table.EndRow()
+	}
+
+	return table.Render(iostreams.G(ctx).Out)
+}
+
+// writeResult writes the verdict to the --result-file, if any, such that `pr
+// merge --from-result` can reuse it.
+func (opts *Mergable) writeResult(pull *ghpr.PullRequest, verdict *ghpr.MergeVerdict) error {
+	if opts.ResultFile == "" {
+		return nil
+	}
+
+	return pull.NewMergeResult(verdict, time.Now()).WriteFile(opts.ResultFile)
+}
+
+// parseTeamMinApprovals parses the provided TEAM=N pairs.
+func parseTeamMinApprovals(pairs []string) (map[string]int, error) {
+	mins := make(map[string]int)
+
+	for _, pair := range pairs {
+		team, n, ok := strings.Cut(pair, "=")
+		if !ok || team == "" {
+			return nil, fmt.Errorf("invalid --team-min-approvals '%s': expected TEAM=N", pair)
+		}
+
+		min, err := strconv.Atoi(n)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --team-min-approvals '%s': %w", pair, err)
+		}
+
+		if err := config.NotNegative("team-min-approvals", min); err != nil {
+			return nil, err
+		}
+
+		mins[team] = min
+	}
+
+	return mins, nil
+}
+
+// Pre loads the ruleset file, if any, and uses its values for every flag
+// which has not been explicitly set.
+func (opts *Mergable) Pre(cmd *cobra.Command, _ []string) error {
+	if opts.Rules == "" {
+		return nil
+	}
+
+	rules, err := ghpr.NewRulesetFromFile(opts.Rules)
+	if err != nil {
+		return err
+	}
+
+	opts.applyRules(rules, cmd.Flags().Changed)
+
+	return opts.Validate(cmd.Context())
+}
+
+// applyRules sets the options from the provided ruleset unless the respective
+// flag has been changed.
+func (opts *Mergable) applyRules(rules *ghpr.Ruleset, changed func(flag string) bool) {
+	strs := func(flag string, dst *[]string, src []string) {
+		if !changed(flag) && len(src) > 0 {
+			*dst = src
+		}
+	}
+	str := func(flag string, dst *string, src string) {
+		if !changed(flag) && src != "" {
+			*dst = src
+		}
+	}
+	boolean := func(flag string, dst *bool, src bool) {
+		if !changed(flag) && src {
+			*dst = src
+		}
+	}
+	integer := func(flag string, dst *int, src *int) {
+		if !changed(flag) && src != nil {
+			*dst = *src
+		}
+	}
+
+	strs("allowed-base-branches", &opts.AllowedBaseBranches, rules.AllowedBaseBranches)
+	strs("approver-comments", &opts.ApproverComments, rules.ApproverComments)
+	strs("approver-teams", &opts.ApproverTeams, rules.ApproverTeams)
+	strs("approve-states", &opts.ApproveStates, rules.ApproveStates)
+	boolean("block-on-changes-requested", &opts.BlockOnChangesRequested, rules.BlockOnChangesRequested)
+	strs("bot-labels", &opts.BotLabels, rules.BotLabels)
+	strs("bot-logins", &opts.BotLogins, rules.BotLogins)
+	str("bot-policy", &opts.BotPolicy, rules.BotPolicy)
+	strs("ignore-authors", &opts.IgnoreAuthors, rules.IgnoreAuthors)
+	boolean("ignore-changes-requested", &opts.IgnoreChangesRequested, rules.IgnoreChangesRequested)
+	strs("ignore-labels", &opts.IgnoreLabels, rules.IgnoreLabels)
+	strs("ignore-states", &opts.IgnoreStates, rules.IgnoreStates)
+	boolean("ignore-unreadable-teams", &opts.IgnoreUnreadableTeams, rules.IgnoreUnreadableTeams)
+	strs("labels", &opts.Labels, rules.Labels)
+	integer("min-approvals", &opts.MinApprovals, rules.MinApprovals)
+	integer("min-reviews", &opts.MinReviews, rules.MinReviews)
+	boolean("no-conflicts", &opts.NoConflicts, rules.NoConflicts)
+	boolean("no-draft", &opts.NoDraft, rules.NoDraft)
+	boolean("no-respect-assignees", &opts.NoRespectAssignees, rules.NoRespectAssignees)
+	boolean("no-respect-reviewers", &opts.NoRespectReviewers, rules.NoRespectReviewers)
+	boolean("require-signoff", &opts.RequireSignoff, rules.RequireSignoff)
+	strs("required-approvers", &opts.RequiredApprovers, rules.RequiredApprovers)
+	strs("required-checks", &opts.RequiredChecks, rules.RequiredChecks)
+	strs("reviewer-comments", &opts.ReviewerComments, rules.ReviewerComments)
+	strs("reviewer-teams", &opts.ReviewerTeams, rules.ReviewerTeams)
+	strs("review-states", &opts.ReviewStates, rules.ReviewStates)
+	strs("states", &opts.States, rules.States)
+
+	if !changed("team-min-approvals") && len(rules.TeamMinApprovals) > 0 {
+		opts.teamMinApprovals = rules.TeamMinApprovals
+	}
+}
+
 func (opts *Mergable) Run(ctx context.Context, args []string) error {
 	ghOrg, ghRepo, ghPrId, err := cmdutils.ParseOrgRepoAndPullRequestArgs(args)
 	if err != nil {
 		return err
 	}
 
-	ghClient, err := ghapi.NewGithubClient(
-		ctx,
-		kitcfg.G[config.Config](ctx).GithubToken,
-		kitcfg.G[config.Config](ctx).GithubSkipSSL,
-		kitcfg.G[config.Config](ctx).GithubEndpoint,
-	)
+	ctx = ghapi.WithOperation(ghapi.WithAPIUsagePullRequest(ctx, ghOrg, ghRepo, ghPrId), ghapi.OperationMergability)
+
+	ghClient, err := cmdutils.NewGithubClient(ctx)
 	if err != nil {
 		return err
 	}
 
+	var run *checkRun
+	if opts.CheckRun {
+		run, err = startCheckRun(ctx, ghClient, ghOrg, ghRepo, ghPrId, "governctl / mergable")
+		if err != nil {
+			return err
+		}
+	}
+
 	pull, err := ghpr.NewPullRequestFromID(ctx,
 		ghClient,
 		ghOrg,
@@ -98,17 +526,46 @@ func (opts *Mergable) Run(ctx context.Context, args []string) error {
 		opts.CommitterGlobal,
 		// ghpr.WithBaseBranch(opts.BaseBranch),
 		ghpr.WithWorkdir(kitcfg.G[config.Config](ctx).TempDir),
+		ghpr.WithCredentials(kitcfg.G[config.Config](ctx).GithubUser, kitcfg.G[config.Config](ctx).GithubToken),
+		ghpr.WithGitBinary(kitcfg.G[config.Config](ctx).GitBinary),
 	)
 	if err != nil {
-		return fmt.Errorf("could not prepare pull request: %w", err)
+		err = fmt.Errorf("could not prepare pull request: %w", err)
+		if run != nil {
+			return run.fail(ctx, err)
+		}
+
+		return err
+	}
+
+	hooks, err := cmdutils.NewHookRunner(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload := hook.NewPayload(ghOrg, ghRepo, pull.Metadata(), kitcfg.G[config.Config](ctx).DryRun)
+
+	teamMinApprovals := opts.teamMinApprovals
+	if len(opts.TeamMinApprovals) > 0 {
+		if teamMinApprovals, err = parseTeamMinApprovals(opts.TeamMinApprovals); err != nil {
+			return err
+		}
 	}
 
-	_, result, err := pull.SatisfiesMergeRequirements(ctx,
+	mopts := []ghpr.PullRequestMergableOption{
+		ghpr.WithAllowedBaseBranches(opts.AllowedBaseBranches...),
 		ghpr.WithApproverComments(opts.ApproverComments...),
 		ghpr.WithApproverTeams(opts.ApproverTeams...),
 		ghpr.WithApproveStates(opts.ApproveStates...),
+		ghpr.WithBlockOnChangesRequested(opts.BlockOnChangesRequested),
+		ghpr.WithBotLabels(opts.BotLabels...),
+		ghpr.WithBotLogins(opts.BotLogins...),
+		ghpr.WithBotPolicy(opts.BotPolicy),
+		ghpr.WithIgnoreAuthors(opts.IgnoreAuthors...),
+		ghpr.WithIgnoreChangesRequested(opts.IgnoreChangesRequested),
 		ghpr.WithIgnoreLabels(opts.IgnoreLabels...),
 		ghpr.WithIgnoreStates(opts.IgnoreStates...),
+		ghpr.WithIgnoreUnreadableTeams(opts.IgnoreUnreadableTeams),
 		ghpr.WithLabels(opts.Labels...),
 		ghpr.WithMinApprovals(opts.MinApprovals),
 		ghpr.WithMinReviews(opts.MinReviews),
@@ -116,24 +573,180 @@ func (opts *Mergable) Run(ctx context.Context, args []string) error {
 		ghpr.WithNoDraft(opts.NoDraft),
 		ghpr.WithNoRespectAssignees(opts.NoRespectAssignees),
 		ghpr.WithNoRespectReviewers(opts.NoRespectReviewers),
+		ghpr.WithRequireSignoff(opts.RequireSignoff),
+		ghpr.WithRequiredApprovers(opts.RequiredApprovers...),
+		ghpr.WithRequiredChecks(opts.RequiredChecks...),
 		ghpr.WithReviewerComments(opts.ReviewerComments...),
 		ghpr.WithReviewerTeams(opts.ReviewerTeams...),
 		ghpr.WithReviewStates(opts.ReviewStates...),
 		ghpr.WithStates(opts.States...),
-	)
-	if err != nil {
-		return fmt.Errorf("pull request is not mergable: %w", err)
+		ghpr.WithPerTeamMinApprovals(teamMinApprovals),
 	}
 
-	buffer := &bytes.Buffer{}
-	encoder := json.NewEncoder(buffer)
-	encoder.SetEscapeHTML(false)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(&result); err != nil {
-		return fmt.Errorf("could not marshal JSON response: %w", err)
+	// output is printed as JSON, whereas verdict is rendered as a table in any
+	// other output format.
+	var output any
+	var verdict *ghpr.MergeVerdict
+
+	// renderUnmet renders the verdict of a pull request which is not mergable
+	// before its error is returned, such that CI can still post it.
+	renderUnmet := func(verdict *ghpr.MergeVerdict) error {
+		if opts.Output == string(tableprinter.OutputFormatJSON) {
+			return nil
+		}
+
+		return opts.renderVerdict(ctx, verdict)
+	}
+
+	// historical is set when the verdict of a historical evaluation is printed
+	// in full even if the pull request was not mergable.
+	var historical error
+
+	if opts.At != "" {
+		at, err := pull.ResolveAt(ctx, opts.At)
+		if err != nil {
+			return err
+		}
+
+		verdict, err = pull.Verdict(ctx, append(mopts, ghpr.WithAt(at))...)
+		if err != nil {
+			return fmt.Errorf("pull request was not mergable: %w", err)
+		}
+
+		skipped := "none"
+		if len(verdict.Skipped) > 0 {
+			skipped = strings.Join(verdict.Skipped, ", ")
+		}
+
+		log.G(ctx).
+			WithField("at", at.Format(time.RFC3339)).
+			WithField("head", verdict.HeadSHA).
+			Warnf("historical evaluation: team membership, assignees and requested reviewers are as of now, skipped: %s", skipped)
+
+		if err := verdict.Err(); err != nil {
+			historical = fmt.Errorf("pull request was not mergable: %w", err)
+		}
+
+		output = verdict
+	} else if opts.As != "" {
+		current, simulated, err := pull.SimulateAttestation(ctx, opts.As, opts.AsState, mopts...)
+		if err != nil {
+			return fmt.Errorf("could not simulate approval: %w", err)
+		}
+
+		output = map[string]*ghpr.MergeVerdict{
+			"current":   current,
+			"simulated": simulated,
+		}
+	} else if run != nil {
+		payload.Point = hook.PointPreMergability
+		if err := hooks.Run(ctx, payload); err != nil {
+			return run.fail(ctx, fmt.Errorf("pull request is not mergable: %w", err))
+		}
+
+		verdict, err = pull.Verdict(ctx, mopts...)
+		if err != nil {
+			return run.fail(ctx, fmt.Errorf("pull request is not mergable: %w", err))
+		}
+
+		conclusion, title := "success", "pull request is mergable"
+		unmet := verdict.Err()
+		if unmet != nil {
+			conclusion, title = "failure", unmet.Error()
+		}
+
+		payload.Point = hook.PointPostMergability
+		_ = hooks.Run(ctx, payload.WithMergability(unmet == nil, verdict.Result, unmet))
+
+		if err := opts.writeResult(pull, verdict); err != nil {
+			return err
+		}
+
+		if opts.SetStatus {
+			if err := setMergableStatus(ctx, ghClient, ghOrg, ghRepo, verdict); err != nil {
+				return err
+			}
+		}
+
+		if err := run.complete(ctx, conclusion, title, verdict.Markdown(), nil); err != nil {
+			return err
+		}
+
+		if unmet != nil {
+			if err := renderUnmet(verdict); err != nil {
+				return err
+			}
+
+			return fmt.Errorf("pull request is not mergable: %w", unmet)
+		}
+
+		output = verdict.Result
+	} else {
+		payload.Point = hook.PointPreMergability
+		if err := hooks.Run(ctx, payload); err != nil {
+			return fmt.Errorf("pull request is not mergable: %w", err)
+		}
+
+		verdict, err = pull.Verdict(ctx, mopts...)
+		if err != nil {
+			payload.Point = hook.PointPostMergability
+			_ = hooks.Run(ctx, payload.WithMergability(false, nil, err))
+
+			return fmt.Errorf("pull request is not mergable: %w", err)
+		}
+
+		// Standard output only carries the JSON document.
+		if opts.Output == string(tableprinter.OutputFormatJSON) {
+			log.G(ctx).Infof("approvers (%d/%d) and reviewers (%d/%d)",
+				verdict.Approvals,
+				verdict.MinApprovals,
+				verdict.Reviews,
+				verdict.MinReviews)
+		}
+
+		// Hooks only see the result of mergable pull requests.
+		unmet, result := verdict.Err(), verdict.Result
+		if unmet != nil {
+			result = nil
+		}
+
+		payload.Point = hook.PointPostMergability
+		_ = hooks.Run(ctx, payload.WithMergability(unmet == nil, result, unmet))
+
+		if err := opts.writeResult(pull, verdict); err != nil {
+			return err
+		}
+
+		if opts.SetStatus {
+			if err := setMergableStatus(ctx, ghClient, ghOrg, ghRepo, verdict); err != nil {
+				return err
+			}
+		}
+
+		if unmet != nil {
+			if err := renderUnmet(verdict); err != nil {
+				return err
+			}
+
+			return fmt.Errorf("pull request is not mergable: %w", unmet)
+		}
+
+		output = verdict.Result
 	}
 
-	fmt.Print(buffer.String())
+	if opts.Output == string(tableprinter.OutputFormatJSON) {
+		buffer := &bytes.Buffer{}
+		encoder := json.NewEncoder(buffer)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(&output); err != nil {
+			return fmt.Errorf("could not marshal JSON response: %w", err)
+		}
+
+		fmt.Print(buffer.String())
+	} else if err := opts.renderVerdict(ctx, verdict); err != nil {
+		return err
+	}
 
 	// If the user has not specified a temporary directory which will have been
 	// passed as the working directory, a temporary one will have been generated.
@@ -143,5 +756,5 @@ func (opts *Mergable) Run(ctx context.Context, args []string) error {
 		os.RemoveAll(pull.Workdir())
 	}
 
-	return nil
+	return historical
 }
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package check
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	kitcfg "kraftkit.sh/config"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghpr"
+)
+
+func TestMergableApplyRules(t *testing.T) {
+	two := 2
+	zero := 0
+	rules := &ghpr.Ruleset{
+		ApproverTeams:  []string{"@unikraft/maintainers"},
+		MinApprovals:   &two,
+		MinReviews:     &zero,
+		RequireSignoff: true,
+		RequiredChecks: []string{"build"},
+	}
+
+	// Flags at their defaults, with only --min-approvals and --approver-teams
+	// explicitly provided.
+	opts := &Mergable{
+		ApproveStates: []string{"approve"},
+		ApproverTeams: []string{"@unikraft/reviewers"},
+		MinApprovals:  1,
+		MinReviews:    1,
+	}
+
+	opts.applyRules(rules, func(flag string) bool {
+		return flag == "min-approvals" || flag == "approver-teams"
+	})
+
+	if opts.MinApprovals != 1 {
+		t.Errorf("MinApprovals = %d, want the flag value 1", opts.MinApprovals)
+	}
+
+	if want := []string{"@unikraft/reviewers"}; !reflect.DeepEqual(opts.ApproverTeams, want) {
+		t.Errorf("ApproverTeams = %v, want the flag value %v", opts.ApproverTeams, want)
+	}
+
+	if opts.MinReviews != 0 {
+		t.Errorf("MinReviews = %d, want the ruleset value 0", opts.MinReviews)
+	}
+
+	if !opts.RequireSignoff || !reflect.DeepEqual(opts.RequiredChecks, []string{"build"}) {
+		t.Errorf("RequireSignoff = %v, RequiredChecks = %v, want ruleset values", opts.RequireSignoff, opts.RequiredChecks)
+	}
+
+	if want := []string{"approve"}; !reflect.DeepEqual(opts.ApproveStates, want) {
+		t.Errorf("ApproveStates = %v, want the default %v", opts.ApproveStates, want)
+	}
+}
+
+func TestMergableValidateChangesRequested(t *testing.T) {
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+	// --block-on-changes-requested on the command line with a ruleset which
+	// ignores requested changes.
+	opts := &Mergable{BlockOnChangesRequested: true, Output: "table"}
+	if err := opts.Validate(ctx); err != nil {
+		t.Fatalf("Validate() unexpected error: %v", err)
+	}
+
+	opts.applyRules(&ghpr.Ruleset{IgnoreChangesRequested: true}, func(flag string) bool {
+		return flag == "block-on-changes-requested"
+	})
+
+	err = opts.Validate(ctx)
+	if err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+		t.Errorf("Validate() error = %v, want --block-on-changes-requested and --ignore-changes-requested to be rejected", err)
+	}
+}
+
+func TestParseTeamMinApprovals(t *testing.T) {
+	got, err := parseTeamMinApprovals([]string{"@unikraft/sig-net=2", "@unikraft/sig-core=1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{"@unikraft/sig-net": 2, "@unikraft/sig-core": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTeamMinApprovals() = %v, want %v", got, want)
+	}
+
+	for _, invalid := range []string{"@unikraft/sig-net", "=1", "@unikraft/sig-net=two", "@unikraft/sig-net=-1"} {
+		if _, err := parseTeamMinApprovals([]string{invalid}); err == nil {
+			t.Errorf("parseTeamMinApprovals(%q) expected an error", invalid)
+		}
+	}
+}
+
+func TestMergableRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		verdict *ghpr.MergeVerdict
+		want    []mergableRow
+	}{
+		{
+			name: "mergable",
+			verdict: &ghpr.MergeVerdict{
+				Approvals:    1,
+				MinApprovals: 1,
+				Reviews:      2,
+				MinReviews:   1,
+				Result: map[string][]string{
+					"reviewed_by": {"Alice <alice@unikraft.org>", "Bob <bob@unikraft.org>"},
+					"approved_by": {"Alice <alice@unikraft.org>"},
+				},
+			},
+			want: []mergableRow{
+				{"Approvals", "met", "1/1"},
+				{"Reviews", "met", "2/1"},
+				{"Trailer approved_by", "", "Alice <alice@unikraft.org>"},
+				{"Trailer reviewed_by", "", "Alice <alice@unikraft.org>, Bob <bob@unikraft.org>"},
+				{"Mergable", "met", ""},
+			},
+		},
+		{
+			name: "not mergable",
+			verdict: &ghpr.MergeVerdict{
+				Approvals:     1,
+				MinApprovals:  2,
+				MinReviews:    0,
+				TeamApprovals: map[string]int{"@unikraft/sig-arch": 0, "@unikraft/maintainers": 1},
+				ShortTeams:    []string{"@unikraft/sig-arch (0/1)"},
+				FailingChecks: []string{"build (failure)"},
+				Unmet:         []string{"approvals"},
+			},
+			want: []mergableRow{
+				{"Approvals", "unmet", "1/2"},
+				{"Reviews", "met", "0/0"},
+				{"Approvals from @unikraft/maintainers", "met", "1"},
+				{"Approvals from @unikraft/sig-arch", "unmet", "(0/1)"},
+				{"Checks", "unmet", "build (failure)"},
+				{"Mergable", "unmet", "pull request has checks which have not succeeded: build (failure)"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergableRows(tt.verdict); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergableRows() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMergableOutput(t *testing.T) {
+	for _, output := range []string{"json", "table", "csv", "yaml", "markdown"} {
+		if err := validateMergableOutput(output); err != nil {
+			t.Errorf("validateMergableOutput(%q) unexpected error: %v", output, err)
+		}
+	}
+
+	if err := validateMergableOutput("html"); err == nil {
+		t.Error("validateMergableOutput(\"html\") expected error")
+	}
+}
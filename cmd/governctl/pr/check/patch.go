@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/google/go-github/v63/github"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"kraftkit.sh/cmdfactory"
@@ -23,20 +24,30 @@ import (
 	"github.com/unikraft/governance/internal/checkpatch"
 	"github.com/unikraft/governance/internal/cmdutils"
 	"github.com/unikraft/governance/internal/config"
-	"github.com/unikraft/governance/internal/ghapi"
 	"github.com/unikraft/governance/internal/ghpr"
 	"github.com/unikraft/governance/internal/tableprinter"
 )
 
 type Patch struct {
-	CommitterEmail   string `long:"committer-email" short:"e" env:"GOVERN_COMMITTER_EMAIL" usage:"Set the Git committer author's email" default:"monkey@unikraft.org"`
-	CommiterGlobal   bool   `long:"committer-global" env:"GOVERN_COMMITTER_GLOBAL" usage:"Set the Git committer author's email/name globally" default:"true"`
-	CommitterName    string `long:"committer-name" short:"n" env:"GOVERN_COMMITTER_NAME" usage:"Set the Git committer author's name" default:"Unikraft Bot"`
-	Output           string `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of choice [table, html, json, yaml]" default:"table"`
-	CheckpatchScript string `long:"checkpatch-script" env:"GOVERN_CHECKPATCH_SCRIPT" usage:"Use an existing checkpatch.pl script"`
-	CheckpatchConf   string `long:"checkpatch-conf" env:"GOVERN_CHECKPATCH_CONF" usage:"Use an existing checkpatch.conf file"`
-	Ignore           string `long:"ignore" env:"GOVERN_IGNORE" usage:"DEPRECATED: Set the types which should be ignored by checkpatch (ignored)"`
-	BaseBranch       string `long:"base" env:"GOVERN_BASE_BRANCH" usage:"Set the base branch name that the PR will be rebased onto"`
+	CommitterEmail   string   `long:"committer-email" short:"e" env:"GOVERN_COMMITTER_EMAIL" usage:"Set the Git committer author's email" default:"monkey@unikraft.org"`
+	CommiterGlobal   bool     `long:"committer-global" env:"GOVERN_COMMITTER_GLOBAL" usage:"Set the Git committer author's email/name globally" default:"true"`
+	CommitterName    string   `long:"committer-name" short:"n" env:"GOVERN_COMMITTER_NAME" usage:"Set the Git committer author's name" default:"Unikraft Bot"`
+	Output           string   `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of choice [table, csv, html, json, yaml]" default:"table"`
+	Columns          []string `long:"columns" env:"GOVERN_COLUMNS" usage:"Comma-separated columns of the table to render, in order [commit, level, type, message, file, line]"`
+	CheckpatchScript string   `long:"checkpatch-script" env:"GOVERN_CHECKPATCH_SCRIPT" usage:"Use an existing checkpatch.pl script"`
+	CheckpatchConf   string   `long:"checkpatch-conf" env:"GOVERN_CHECKPATCH_CONF" usage:"Use an existing checkpatch.conf file"`
+	Ignore           string   `long:"ignore" env:"GOVERN_IGNORE" usage:"DEPRECATED: Set the types which should be ignored by checkpatch (ignored)"`
+	BaseBranch       string   `long:"base" env:"GOVERN_BASE_BRANCH" usage:"Set the base branch name that the PR will be rebased onto"`
+	MaxPatches       int      `long:"max-patches" env:"GOVERN_MAX_PATCHES" usage:"Maximum number of patches to generate for the PR" default:"500"`
+	NoAnnotations    bool     `long:"no-annotations" env:"GOVERN_NO_ANNOTATIONS" usage:"Do not annotate the PR with workflow commands when running in GitHub Actions"`
+	NoTable          bool     `long:"no-table" env:"GOVERN_NO_TABLE" usage:"Do not render the table of findings, e.g. when the annotations suffice"`
+	UseEmbedded      bool     `long:"use-embedded" env:"GOVERN_USE_EMBEDDED" usage:"Always use the checkpatch configuration embedded in governctl"`
+	Strict           bool     `long:"strict" env:"GOVERN_STRICT" usage:"Run checkpatch in strict mode, additionally reporting checks"`
+	FailOn           string   `long:"fail-on" env:"GOVERN_FAIL_ON" usage:"Least severe level of notes which fails the check [error, warning, check]" default:"warning"`
+	LevelOverrides   []string `long:"level-overrides" env:"GOVERN_LEVEL_OVERRIDES" usage:"Reclassify the notes of a checkpatch type, as TYPE=LEVEL where LEVEL is one of error, warning, check or ignore"`
+	CheckRun         bool     `long:"check-run" env:"GOVERN_CHECK_RUN" usage:"Report the result as a check run on the PR with annotations (requires a GitHub App)"`
+	Baseline         string   `long:"baseline" env:"GOVERN_BASELINE" usage:"Suppress the known findings stored in this file and only report new ones"`
+	WriteBaseline    string   `long:"write-baseline" env:"GOVERN_WRITE_BASELINE" usage:"Store all findings in this file to be used with --baseline instead of failing"`
 }
 
 const (
@@ -45,8 +56,11 @@ const (
 	checkpatchIgnore = "Checkpatch-Ignore: "
 )
 
+// patchColumns are the columns of the table of findings.
+var patchColumns = []string{"COMMIT", "LEVEL", "TYPE", "MESSAGE", "FILE", "LINE"}
+
 func NewPatch() *cobra.Command {
-	cmd, err := cmdfactory.New(&Patch{}, cobra.Command{
+	cmd, err := cmdutils.New(&Patch{}, cobra.Command{
 		Use:   "patch [OPTIONS] ORG/REPO/PRID",
 		Short: "Run checkpatch against a pull request",
 		Args:  cobra.MaximumNArgs(2),
@@ -56,6 +70,19 @@ func NewPatch() *cobra.Command {
 		Example: heredoc.Doc(`
 		# Run checkpatch against PR #1000
 		governctl pr check patch unikraft/unikraft/1000
+
+		# Report the result as a check run with annotations on the PR
+		governctl pr check patch --check-run unikraft/unikraft/1000
+
+		# Run checkpatch in strict mode but only fail on errors
+		governctl pr check patch --strict --fail-on=error unikraft/unikraft/1000
+
+		# Only warn about long lines but treat a missing SPDX tag as an error
+		governctl pr check patch --level-overrides=LONG_LINE=warning,SPDX_LICENSE_TAG=error unikraft/unikraft/1000
+
+		# Record the existing findings of PR #1000 and only fail on new ones later
+		governctl pr check patch --write-baseline=.checkpatch-baseline.json unikraft/unikraft/1000
+		governctl pr check patch --baseline=.checkpatch-baseline.json unikraft/unikraft/1000
 		`),
 	})
 	if err != nil {
@@ -65,7 +92,80 @@ func NewPatch() *cobra.Command {
 	return cmd
 }
 
-func (opts *Patch) Run(ctx context.Context, args []string) error {
+// Validate rejects invalid settings before the pull request is prepared, e.g.
+// the embedded checkpatch configuration together with an explicit one, unknown levels or columns and --write-baseline together with --baseline or
+// --check-run.
+func (opts *Patch) Validate(ctx context.Context) error {
+	if err := config.ValidateCommitter(opts.CommitterName, opts.CommitterEmail, opts.CommiterGlobal); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("use-embedded", opts.UseEmbedded, "checkpatch-conf", opts.CheckpatchConf != ""); err != nil {
+		return err
+	}
+
+	if opts.FailOn != "" {
+		if _, err := checkpatch.ParseNoteLevel(opts.FailOn); err != nil {
+			return fmt.Errorf("invalid --fail-on: %w", err)
+		}
+	}
+
+	if _, err := parseLevelOverrides(opts.LevelOverrides); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("baseline", opts.Baseline != "", "write-baseline", opts.WriteBaseline != ""); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("write-baseline", opts.WriteBaseline != "", "check-run", opts.CheckRun); err != nil {
+		return err
+	}
+
+	if err := validateCheckRun(ctx, opts.CheckRun); err != nil {
+		return err
+	}
+
+	if err := tableprinter.ValidateColumns(patchColumns, opts.Columns); err != nil {
+		return fmt.Errorf("invalid --columns: %w", err)
+	}
+
+	return config.NotNegative("max-patches", opts.MaxPatches)
+}
+
+// reportModes returns whether findings are emitted as GitHub Actions
+// annotations and whether they are rendered as a table.  The two are
+// independent such that, in GitHub Actions, the table remains in the log of
+// the job for debugging while the annotations decorate the pull request.
+func reportModes(inActions, noAnnotations, noTable bool) (annotate, render bool) {
+	return inActions && !noAnnotations, !noTable
+}
+
+// parseLevelOverrides parses the provided TYPE=LEVEL pairs.
+func parseLevelOverrides(pairs []string) (map[string]checkpatch.NoteLevel, error) {
+	overrides := make(map[string]checkpatch.NoteLevel)
+
+	for _, pair := range pairs {
+		typ, name, ok := strings.Cut(pair, "=")
+		if !ok || typ == "" {
+			return nil, fmt.Errorf("invalid --level-overrides '%s': expected TYPE=LEVEL", pair)
+		}
+
+		level := checkpatch.NoteLevelIgnore
+		if !strings.EqualFold(name, string(checkpatch.NoteLevelIgnore)) {
+			var err error
+			if level, err = checkpatch.ParseNoteLevel(name); err != nil {
+				return nil, fmt.Errorf("invalid --level-overrides '%s': %w", pair, err)
+			}
+		}
+
+		overrides[strings.ToUpper(typ)] = level
+	}
+
+	return overrides, nil
+}
+
+func (opts *Patch) Run(ctx context.Context, args []string) (err error) {
 	var extraIgnores = []string{"UNKNOWN_COMMIT_ID"}
 
 	ghOrg, ghRepo, ghPrId, err := cmdutils.ParseOrgRepoAndPullRequestArgs(args)
@@ -73,16 +173,28 @@ func (opts *Patch) Run(ctx context.Context, args []string) error {
 		return err
 	}
 
-	ghClient, err := ghapi.NewGithubClient(
-		ctx,
-		kitcfg.G[config.Config](ctx).GithubToken,
-		kitcfg.G[config.Config](ctx).GithubSkipSSL,
-		kitcfg.G[config.Config](ctx).GithubEndpoint,
-	)
+	ghClient, err := cmdutils.NewGithubClient(ctx)
 	if err != nil {
 		return err
 	}
 
+	var run *checkRun
+	if opts.CheckRun {
+		run, err = startCheckRun(ctx, ghClient, ghOrg, ghRepo, ghPrId, "governctl / checkpatch")
+		if err != nil {
+			return err
+		}
+
+		// Conclude the check run if checkpatch could not be run at all.
+		defer func() {
+			if err != nil && run != nil {
+				err = run.fail(ctx, err)
+			}
+		}()
+	}
+
+	annotate, render := reportModes(cmdutils.InGithubActions(), opts.NoAnnotations, opts.NoTable)
+
 	pull, err := ghpr.NewPullRequestFromID(ctx,
 		ghClient,
 		ghOrg,
@@ -93,6 +205,10 @@ func (opts *Patch) Run(ctx context.Context, args []string) error {
 		opts.CommiterGlobal,
 		ghpr.WithBaseBranch(opts.BaseBranch),
 		ghpr.WithWorkdir(kitcfg.G[config.Config](ctx).TempDir),
+		ghpr.WithCredentials(kitcfg.G[config.Config](ctx).GithubUser, kitcfg.G[config.Config](ctx).GithubToken),
+		ghpr.WithGitBinary(kitcfg.G[config.Config](ctx).GitBinary),
+		ghpr.WithMaxPatches(opts.MaxPatches),
+		ghpr.WithGitHubActionsAnnotations(annotate),
 	)
 	if err != nil {
 		return fmt.Errorf("could not prepare pull request: %w", err)
@@ -113,32 +229,36 @@ func (opts *Patch) Run(ctx context.Context, args []string) error {
 		}
 	}
 
-	// Use a well-known path of the checkpatch.pl script contained within the
-	// repository or the user-provided alternative.
-	if opts.CheckpatchScript == "" {
-		opts.CheckpatchScript = filepath.Join(
-			pull.LocalRepo(),
-			"support", "scripts", "checkpatch.pl",
-		)
+	if err := opts.resolveCheckpatch(ctx, pull.LocalRepo(), pull.Workdir()); err != nil {
+		return err
 	}
-	if _, err := os.Stat(opts.CheckpatchScript); err != nil {
-		return fmt.Errorf("could not access checkpatch script at '%s': %w", opts.CheckpatchScript, err)
+
+	failOn := checkpatch.NoteLevelWarning
+	if opts.FailOn != "" {
+		if failOn, err = checkpatch.ParseNoteLevel(opts.FailOn); err != nil {
+			return err
+		}
 	}
 
-	if opts.CheckpatchConf == "" {
-		opts.CheckpatchConf = filepath.Join(
-			pull.LocalRepo(),
-			".checkpatch.conf",
-		)
+	overrides, err := parseLevelOverrides(opts.LevelOverrides)
+	if err != nil {
+		return err
 	}
-	if _, err := os.Stat(opts.CheckpatchConf); err != nil {
-		return fmt.Errorf("could not access checkpatch configuration at '%s': %w", opts.CheckpatchConf, err)
+
+	var baseline, written *checkpatch.Baseline
+	if opts.Baseline != "" {
+		if baseline, err = checkpatch.NewBaselineFromFile(opts.Baseline); err != nil {
+			return err
+		}
+	} else if opts.WriteBaseline != "" {
+		written = checkpatch.NewBaseline()
 	}
 
 	cs := iostreams.G(ctx).ColorScheme()
 
 	topts := []tableprinter.TablePrinterOption{
 		tableprinter.WithOutputFormatFromString(opts.Output),
+		tableprinter.WithColumns(opts.Columns...),
 	}
 
 	if kitcfg.G[config.Config](ctx).NoRender {
@@ -152,16 +272,14 @@ func (opts *Patch) Run(ctx context.Context, args []string) error {
 		return err
 	}
 
-	table.AddField("COMMIT", cs.Bold)
-	table.AddField("LEVEL", cs.Bold)
-	table.AddField("TYPE", cs.Bold)
-	table.AddField("MESSAGE", cs.Bold)
-	table.AddField("FILE", cs.Bold)
-	table.AddField("LINE", cs.Bold)
-	table.EndRow()
+	table.AddHeader(cs.Bold, patchColumns...)
+
+	counts := make(map[checkpatch.NoteLevel]int)
+	failed := false
+	suppressed := 0
 
-	warnings := 0
-	errors := 0
+	var annotations []*github.CheckRunAnnotation
+	var rows []string
 
 	for _, patch := range pull.Patches() {
 		if _, err := os.Stat(patch.Filename); err != nil {
@@ -180,18 +298,61 @@ func (opts *Patch) Run(ctx context.Context, args []string) error {
 			checkpatch.WithCheckpatchScriptPath(opts.CheckpatchScript),
 			checkpatch.WithCheckpatchConfPath(opts.CheckpatchConf),
 			checkpatch.WithStderr(log.G(ctx).WriterLevel(logrus.TraceLevel)),
+			checkpatch.WithStrict(opts.Strict),
+			checkpatch.WithShowTypes(true),
+			checkpatch.WithMaxSeverity(failOn),
+			checkpatch.WithLevelOverrides(overrides),
+			checkpatch.WithBaseline(baseline),
 		)
 		if err != nil {
 			return fmt.Errorf("could not parse patch file: %w", err)
 		}
 
+		suppressed += len(check.Suppressed())
+
+		if written != nil {
+			written.Add(check.Notes()...)
+			continue
+		}
+
+		if check.Failed() {
+			failed = true
+		}
+
+		for level, count := range check.Counts() {
+			counts[level] += count
+		}
+
 		for _, note := range check.Notes() {
 			level := cs.Red
-			if note.Level == checkpatch.NoteLevelWarning {
+			annotation := ghpr.AnnotationError
+			switch note.Level {
+			case checkpatch.NoteLevelWarning:
 				level = cs.Yellow
-				warnings++
-			} else {
-				errors++
+				annotation = ghpr.AnnotationWarning
+			case checkpatch.NoteLevelCheck:
+				level = cs.Cyan
+				annotation = ghpr.AnnotationNotice
+			}
+
+			rows = append(rows, fmt.Sprintf("| %s | %s | %s | %s | %s:%d |",
+				patch.Hash[0:7],
+				note.Level,
+				note.Type,
+				strings.ReplaceAll(note.Message, "|", "\\|"),
+				note.File,
+				note.Line,
+			))
+
+			if note.File != "" && note.Line > 0 {
+				annotations = append(annotations, &github.CheckRunAnnotation{
+					Path:            github.String(note.File),
+					StartLine:       github.Int(note.Line),
+					EndLine:         github.Int(note.Line),
+					AnnotationLevel: github.String(checkRunAnnotationLevel(note.Level)),
+					Title:           github.String(note.Type),
+					Message:         github.String(note.Message),
+				})
 			}
 
 			table.AddField(patch.Hash[0:7], nil)
@@ -202,21 +363,61 @@ func (opts *Patch) Run(ctx context.Context, args []string) error {
 			table.AddField(fmt.Sprintf("%d", note.Line), nil)
 			table.EndRow()
 
-			// Set an annotations on the PR if run in a GitHub Actions context.
-			// See: https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-error-message
-			if os.Getenv("GITHUB_ACTIONS") == "true" {
-				fmt.Printf("::%s file='%s',line='%d',title='%s'::%s\n",
-					note.Level,
-					note.File,
-					note.Line,
-					note.Type,
-					note.Message,
-				)
-			}
+			// Annotate the PR if run in a GitHub Actions context.
+			pull.Annotate(iostreams.G(ctx).Out, annotation, note.File, note.Line, note.Type, note.Message)
+		}
+	}
+
+	if written != nil {
+		if err := written.WriteFile(opts.WriteBaseline); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(iostreams.G(ctx).Out, "%s wrote %d findings to %s\n", cs.Green("✔"), written.Len(), opts.WriteBaseline)
+
+		return nil
+	}
+
+	summary := fmt.Sprintf("%d errors, %d warnings and %d checks",
+		counts[checkpatch.NoteLevelError],
+		counts[checkpatch.NoteLevelWarning],
+		counts[checkpatch.NoteLevelCheck],
+	)
+
+	if baseline != nil {
+		log.G(ctx).
+			WithField("baseline", opts.Baseline).
+			Infof("suppressed %d known findings, only new findings are reported", suppressed)
+
+		for _, finding := range baseline.Stale() {
+			log.G(ctx).
+				WithField("type", finding.Type).
+				WithField("file", finding.File).
+				Info("known finding no longer reported, consider regenerating the baseline")
 		}
 	}
 
-	if errors == 0 && warnings == 0 {
+	if run != nil {
+		conclusion, title := "success", "checkpatch passed with "+summary
+		if failed {
+			conclusion, title = "failure", "checkpatch failed with "+summary
+		}
+
+		text := checkRunSummary(summary, failOn, rows)
+		if baseline != nil {
+			text += fmt.Sprintf("\n%d known findings were suppressed by the baseline.\n", suppressed)
+		}
+
+		cerr := run.complete(ctx, conclusion, title, text, annotations)
+
+		// The check run has been concluded, even if unsuccessfully.
+		run = nil
+		if cerr != nil {
+			return cerr
+		}
+	}
+
+	if len(counts) == 0 {
 		fmt.Fprintf(iostreams.G(ctx).Out, cs.Green("✔")+" checkpatch passed\n")
 
 		return nil
@@ -239,16 +440,129 @@ func (opts *Patch) Run(ctx context.Context, args []string) error {
 		defer iostreams.G(ctx).StopPager()
 	}
 
-	if os.Getenv("GITHUB_ACTIONS") == "" {
+	if render {
 		err = table.Render(iostreams.G(ctx).Out)
 		if err != nil {
 			return err
 		}
 	}
 
-	if errors > 0 || warnings > 0 {
-		return fmt.Errorf("summary: checkpatch failed with %d errors and %d warnings", errors, warnings)
+	if failed {
+		return fmt.Errorf("summary: checkpatch failed with %s", summary)
+	}
+
+	fmt.Fprintf(iostreams.G(ctx).Out, "%s checkpatch passed with %s below the %s threshold\n", cs.Green("✔"), summary, failOn)
+
+	return nil
+}
+
+// maxCheckRunRows is the maximum number of notes listed in the summary of a
+// check run, which is limited in size.
+const maxCheckRunRows = 100
+
+// checkRunSummary renders the notes of all patches as the markdown summary of
+// a check run.
+func checkRunSummary(summary string, failOn checkpatch.NoteLevel, rows []string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "checkpatch reported %s, failing on %s level and above.\n", summary, failOn)
+
+	if len(rows) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("\n| Commit | Level | Type | Message | Location |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for i, row := range rows {
+		if i == maxCheckRunRows {
+			fmt.Fprintf(&b, "\n... and %d more.\n", len(rows)-maxCheckRunRows)
+			break
+		}
+
+		b.WriteString(row + "\n")
+	}
+
+	return b.String()
+}
+
+// checkRunAnnotationLevel returns the level of a check run annotation which
+// corresponds to the level of a checkpatch note.
+func checkRunAnnotationLevel(level checkpatch.NoteLevel) string {
+	switch level {
+	case checkpatch.NoteLevelError:
+		return "failure"
+	case checkpatch.NoteLevelWarning:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// resolveCheckpatch determines which checkpatch.pl script and configuration
+// to use.  User-provided paths must exist.  Otherwise, the well-known paths
+// within the repository are used.  The repository must provide a script,
+// whereas the configuration embedded in governctl, extracted into the working
+// directory, is used as a fallback.
+func (opts *Patch) resolveCheckpatch(ctx context.Context, localRepo, workdir string) error {
+	// Use a well-known path of the checkpatch.pl script contained within the
+	// repository or the user-provided alternative.
+	if opts.CheckpatchScript != "" {
+		if _, err := os.Stat(opts.CheckpatchScript); err != nil {
+			return fmt.Errorf("could not access checkpatch script at '%s': %w", opts.CheckpatchScript, err)
+		}
+
+		log.G(ctx).
+			WithField("path", opts.CheckpatchScript).
+			Info("using provided checkpatch script")
+	} else if script := filepath.Join(localRepo, "support", "scripts", "checkpatch.pl"); fileExists(script) {
+		opts.CheckpatchScript = script
+
+		log.G(ctx).
+			WithField("path", script).
+			Info("using checkpatch script from repository")
+	} else {
+		return fmt.Errorf("repository does not provide a checkpatch script at '%s': use --checkpatch-script", script)
+	}
+
+	if opts.CheckpatchConf != "" {
+		if _, err := os.Stat(opts.CheckpatchConf); err != nil {
+			return fmt.Errorf("could not access checkpatch configuration at '%s': %w", opts.CheckpatchConf, err)
+		}
+
+		log.G(ctx).
+			WithField("path", opts.CheckpatchConf).
+			Info("using provided checkpatch configuration")
+
+		return nil
+	}
+
+	if conf := filepath.Join(localRepo, ".checkpatch.conf"); !opts.UseEmbedded && fileExists(conf) {
+		opts.CheckpatchConf = conf
+
+		log.G(ctx).
+			WithField("path", conf).
+			Info("using checkpatch configuration from repository")
+
+		return nil
+	}
+
+	conf, err := checkpatch.ExtractEmbeddedConf(filepath.Join(workdir, "checkpatch"))
+	if err != nil {
+		return err
 	}
 
+	opts.CheckpatchConf = conf
+
+	log.G(ctx).
+		WithField("path", conf).
+		Info("using embedded checkpatch configuration")
+
 	return nil
 }
+
+// fileExists returns whether the path exists and is a regular file.
+func fileExists(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.Mode().IsRegular()
+}
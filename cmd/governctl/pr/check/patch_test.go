@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package check
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/unikraft/governance/internal/checkpatch"
+	"github.com/unikraft/governance/internal/cmdutils"
+)
+
+func TestParseLevelOverrides(t *testing.T) {
+	got, err := parseLevelOverrides([]string{"LONG_LINE=warning", "spdx_license_tag=Error", "CAMELCASE=ignore"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]checkpatch.NoteLevel{
+		"LONG_LINE":        checkpatch.NoteLevelWarning,
+		"SPDX_LICENSE_TAG": checkpatch.NoteLevelError,
+		"CAMELCASE":        checkpatch.NoteLevelIgnore,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseLevelOverrides() = %v, want %v", got, want)
+	}
+
+	for _, invalid := range []string{"LONG_LINE", "=warning", "LONG_LINE=info"} {
+		if _, err := parseLevelOverrides([]string{invalid}); err == nil {
+			t.Errorf("parseLevelOverrides(%q) expected an error", invalid)
+		}
+	}
+}
+
+func TestReportModes(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           string
+		noAnnotations bool
+		noTable       bool
+		wantAnnotate  bool
+		wantRender    bool
+	}{
+		{
+			name:       "local",
+			env:        "",
+			wantRender: true,
+		},
+		{
+			name:       "local with GITHUB_ACTIONS=false",
+			env:        "false",
+			wantRender: true,
+		},
+		{
+			name:         "actions",
+			env:          "true",
+			wantAnnotate: true,
+			wantRender:   true,
+		},
+		{
+			name:          "actions without annotations",
+			env:           "true",
+			noAnnotations: true,
+			wantRender:    true,
+		},
+		{
+			name:         "actions without table",
+			env:          "true",
+			noTable:      true,
+			wantAnnotate: true,
+		},
+		{
+			name:    "local without table",
+			env:     "",
+			noTable: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_ACTIONS", tt.env)
+
+			annotate, render := reportModes(cmdutils.InGithubActions(), tt.noAnnotations, tt.noTable)
+			if annotate != tt.wantAnnotate {
+				t.Errorf("annotate = %v, want %v", annotate, tt.wantAnnotate)
+			}
+			if render != tt.wantRender {
+				t.Errorf("render = %v, want %v", render, tt.wantRender)
+			}
+		})
+	}
+}
+
+func TestValidateColumns(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      interface{ Validate(context.Context) error }
+		wantInErr string
+	}{
+		{
+			name: "patch",
+			opts: &Patch{CommitterName: "Unikraft Bot", Columns: []string{"file", "Line", "MESSAGE"}},
+		},
+		{
+			name:      "patch unknown column",
+			opts:      &Patch{CommitterName: "Unikraft Bot", Columns: []string{"reason"}},
+			wantInErr: "unknown column 'reason': expected one of commit, level, type, message, file, line",
+		},
+		{
+			name: "license",
+			opts: &License{CommitterName: "Unikraft Bot", Columns: []string{"reason", "file"}},
+		},
+		{
+			name:      "license unknown column",
+			opts:      &License{CommitterName: "Unikraft Bot", Columns: []string{"level"}},
+			wantInErr: "unknown column 'level': expected one of commit, file, reason",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate(context.Background())
+			if tt.wantInErr == "" && err != nil {
+				t.Errorf("Validate() unexpected error: %v", err)
+			} else if tt.wantInErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantInErr)) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantInErr)
+			}
+		})
+	}
+}
+
+func TestResolveCheckpatch(t *testing.T) {
+	ctx := context.Background()
+	repo := t.TempDir()
+	workdir := t.TempDir()
+
+	opts := &Patch{}
+	if err := opts.resolveCheckpatch(ctx, repo, workdir); err == nil || !strings.Contains(err.Error(), "does not provide a checkpatch script") {
+		t.Fatalf("resolveCheckpatch() error = %v, want a missing script to be rejected", err)
+	}
+
+	script := filepath.Join(repo, "support", "scripts", "checkpatch.pl")
+	if err := os.MkdirAll(filepath.Dir(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(script, []byte("#!/usr/bin/env perl\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	opts = &Patch{}
+	if err := opts.resolveCheckpatch(ctx, repo, workdir); err != nil {
+		t.Fatal(err)
+	}
+	if opts.CheckpatchScript != script {
+		t.Errorf("CheckpatchScript = %s, want %s", opts.CheckpatchScript, script)
+	}
+	if want := filepath.Join(workdir, "checkpatch", ".checkpatch.conf"); opts.CheckpatchConf != want {
+		t.Errorf("CheckpatchConf = %s, want the embedded %s", opts.CheckpatchConf, want)
+	}
+
+	conf := filepath.Join(repo, ".checkpatch.conf")
+	if err := os.WriteFile(conf, []byte("--no-tree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts = &Patch{}
+	if err := opts.resolveCheckpatch(ctx, repo, workdir); err != nil {
+		t.Fatal(err)
+	}
+	if opts.CheckpatchConf != conf {
+		t.Errorf("CheckpatchConf = %s, want the repository's %s", opts.CheckpatchConf, conf)
+	}
+
+	opts = &Patch{UseEmbedded: true}
+	if err := opts.resolveCheckpatch(ctx, repo, workdir); err != nil {
+		t.Fatal(err)
+	}
+	if opts.CheckpatchConf == conf {
+		t.Errorf("CheckpatchConf = %s, want the embedded configuration with --use-embedded", opts.CheckpatchConf)
+	}
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package check
+
+import (
+	"context"
+	"fmt"
+
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/ghpr"
+)
+
+// mergableStatusContext is the context of the commit status which reports
+// whether a pull request is mergable, such that branch protection can require
+// it.
+const mergableStatusContext = "governance/mergable"
+
+// mergableStatus returns the state and the description of the commit status
+// which reports the provided verdict, where the description of an unmet
+// verdict is its shortfall.
+func mergableStatus(verdict *ghpr.MergeVerdict) (state, description string) {
+	if err := verdict.Err(); err != nil {
+		return "failure", err.Error()
+	}
+
+	return "success", fmt.Sprintf("approvers (%d/%d) and reviewers (%d/%d)",
+		verdict.Approvals,
+		verdict.MinApprovals,
+		verdict.Reviews,
+		verdict.MinReviews,
+	)
+}
+
+// setMergableStatus reports the provided verdict as the commit status of the
+// head of the pull request.  In dry-run mode, the status is only logged.
+func setMergableStatus(ctx context.Context, client *ghapi.GithubClient, org, repo string, verdict *ghpr.MergeVerdict) error {
+	state, description := mergableStatus(verdict)
+
+	if kitcfg.G[config.Config](ctx).DryRun {
+		log.G(ctx).
+			WithField("context", mergableStatusContext).
+			WithField("sha", verdict.HeadSHA).
+			WithField("state", state).
+			Infof("would set commit status: %s", description)
+
+		return nil
+	}
+
+	return client.SetCommitStatus(ctx, org, repo, verdict.HeadSHA, state, mergableStatusContext, description, "")
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package check
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	kitcfg "kraftkit.sh/config"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/ghpr"
+)
+
+func TestSetMergableStatus(t *testing.T) {
+	tests := []struct {
+		name            string
+		dryRun          bool
+		verdict         *ghpr.MergeVerdict
+		wantState       string
+		wantDescription string
+	}{
+		{
+			name: "mergable",
+			verdict: &ghpr.MergeVerdict{
+				HeadSHA:      "abc123",
+				Approvals:    2,
+				MinApprovals: 2,
+				Reviews:      1,
+				MinReviews:   1,
+			},
+			wantState:       "success",
+			wantDescription: "approvers (2/2) and reviewers (1/1)",
+		},
+		{
+			name: "not enough approvals",
+			verdict: &ghpr.MergeVerdict{
+				HeadSHA:      "abc123",
+				Approvals:    1,
+				MinApprovals: 2,
+				Reviews:      1,
+				MinReviews:   1,
+				Unmet:        []string{"approvers (1/2)"},
+			},
+			wantState:       "failure",
+			wantDescription: "approvers (1/2)",
+		},
+		{
+			name: "changes requested",
+			verdict: &ghpr.MergeVerdict{
+				HeadSHA:          "abc123",
+				ChangesRequested: []string{"alice"},
+			},
+			wantState:       "failure",
+			wantDescription: "changes requested by alice",
+		},
+		{
+			name:   "dry-run",
+			dryRun: true,
+			verdict: &ghpr.MergeVerdict{
+				HeadSHA: "abc123",
+				Unmet:   []string{"approvers (0/1)"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			var status struct {
+				State       string `json:"state"`
+				Context     string `json:"context"`
+				Description string `json:"description"`
+			}
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.Method+" "+r.URL.Path)
+				if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+					t.Errorf("could not decode status: %v", err)
+				}
+
+				w.WriteHeader(http.StatusCreated)
+			}))
+			t.Cleanup(srv.Close)
+
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{DryRun: tt.dryRun})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+			client, err := ghapi.NewGithubClient(ctx, "", false, srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := setMergableStatus(ctx, client, "unikraft", "unikraft", tt.verdict); err != nil {
+				t.Fatalf("setMergableStatus() unexpected error: %v", err)
+			}
+
+			if tt.dryRun {
+				if len(paths) > 0 {
+					t.Errorf("unexpected writes in dry-run mode: %v", paths)
+				}
+				return
+			}
+
+			if len(paths) != 1 || !strings.HasSuffix(paths[0], "/repos/unikraft/unikraft/statuses/abc123") {
+				t.Fatalf("unexpected requests: %v", paths)
+			}
+
+			if status.Context != mergableStatusContext {
+				t.Errorf("status context = %q, want %q", status.Context, mergableStatusContext)
+			}
+
+			if status.State != tt.wantState {
+				t.Errorf("status state = %q, want %q", status.State, tt.wantState)
+			}
+
+			if !strings.Contains(status.Description, tt.wantDescription) {
+				t.Errorf("status description = %q, want it to contain %q", status.Description, tt.wantDescription)
+			}
+		})
+	}
+}
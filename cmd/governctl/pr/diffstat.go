@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package pr
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghpr"
+	"github.com/unikraft/governance/internal/patch"
+	"github.com/unikraft/governance/internal/tableprinter"
+	"github.com/unikraft/governance/internal/team"
+)
+
+// unownedTeam is the name under which files which are not owned by any known
+// team are summarised.
+const unownedTeam = "(unowned)"
+
+type DiffStat struct {
+	BaseBranch     string   `long:"base" env:"GOVERN_BASE_BRANCH" usage:"Set the base branch name that the PR will be rebased onto"`
+	CommitterEmail string   `long:"committer-email" short:"e" env:"GOVERN_COMMITTER_EMAIL" usage:"Set the Git committer author's email" default:"monkey@unikraft.org"`
+	CommiterGlobal bool     `long:"committer-global" env:"GOVERN_COMMITTER_GLOBAL" usage:"Set the Git committer author's email/name globally" default:"true"`
+	CommitterName  string   `long:"committer-name" short:"n" env:"GOVERN_COMMITTER_NAME" usage:"Set the Git committer author's name" default:"Unikraft Bot"`
+	MaxPatches     int      `long:"max-patches" env:"GOVERN_MAX_PATCHES" usage:"Maximum number of patches to generate for the PR" default:"500"`
+	Output         string   `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of choice [table, csv, html, json, yaml]" default:"table"`
+	Columns        []string `long:"columns" env:"GOVERN_COLUMNS" usage:"Comma-separated columns of the table to render, in order [team, files, additions, deletions]"`
+}
+
+// diffStatColumns are the columns of the per-team summary.
+var diffStatColumns = []string{"TEAM", "FILES", "ADDITIONS", "DELETIONS"}
+
+// teamDiffStat is the number of files owned by a team which are changed by a
+// pull request and the number of lines changed in them.
+type teamDiffStat struct {
+	Team      string
+	Files     int
+	Additions int
+	Deletions int
+}
+
+func NewDiffStat() *cobra.Command {
+	cmd, err := cmdutils.New(&DiffStat{}, cobra.Command{
+		Use:   "diff-stat [OPTIONS] ORG/REPO/PRID",
+		Short: "Summarise the changes of a pull request per owning team",
+		Long: heredoc.Doc(`
+		Summarise the changes of a pull request per owning team
+
+		The teams owning each changed file are determined from the repository's
+		CODEOWNERS.  A file owned by multiple teams counts towards each of them
+		and files which are not owned by any known team are summarised as
+		(unowned).
+		`),
+		Args: cobra.MaximumNArgs(2),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "pr",
+		},
+		Example: heredoc.Doc(`
+		# Show which teams own the changes of PR #1000
+		governctl pr diff-stat unikraft/unikraft/1000
+
+		# Print the summary as JSON
+		governctl pr diff-stat --output=json unikraft/unikraft/1000
+		`),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+// Validate rejects unknown columns before the pull request is prepared.
+func (opts *DiffStat) Validate(_ context.Context) error {
+	if err := config.ValidateCommitter(opts.CommitterName, opts.CommitterEmail, opts.CommiterGlobal); err != nil {
+		return err
+	}
+
+	if err := tableprinter.ValidateColumns(diffStatColumns, opts.Columns); err != nil {
+		return fmt.Errorf("invalid --columns: %w", err)
+	}
+
+	return config.NotNegative("max-patches", opts.MaxPatches)
+}
+
+func (opts *DiffStat) Run(ctx context.Context, args []string) error {
+	ghOrg, ghRepo, ghPrId, err := cmdutils.ParseOrgRepoAndPullRequestArgs(args)
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	teams, err := team.NewListOfTeamsFromPath(
+		ghClient,
+		ghOrg,
+		kitcfg.G[config.Config](ctx).TeamsDir,
+	)
+	if err != nil {
+		return err
+	}
+
+	pull, err := ghpr.NewPullRequestFromID(ctx,
+		ghClient,
+		ghOrg,
+		ghRepo,
+		opts.CommitterName,
+		opts.CommitterEmail,
+		ghPrId,
+		opts.CommiterGlobal,
+		ghpr.WithBaseBranch(opts.BaseBranch),
+		ghpr.WithWorkdir(kitcfg.G[config.Config](ctx).TempDir),
+		ghpr.WithCredentials(kitcfg.G[config.Config](ctx).GithubUser, kitcfg.G[config.Config](ctx).GithubToken),
+		ghpr.WithGitBinary(kitcfg.G[config.Config](ctx).GitBinary),
+		ghpr.WithMaxPatches(opts.MaxPatches),
+	)
+	if err != nil {
+		return fmt.Errorf("could not prepare pull request: %w", err)
+	}
+
+	// If the user has not specified a temporary directory which will have been
+	// passed as the working directory, a temporary one will have been generated.
+	if kitcfg.G[config.Config](ctx).TempDir == "" {
+		defer func() {
+			log.G(ctx).WithField("path", pull.Workdir()).Info("removing")
+			os.RemoveAll(pull.Workdir())
+		}()
+	}
+
+	owners, err := pull.OwningTeams(ctx, teams)
+	if err != nil {
+		return err
+	}
+
+	var files []patch.FileStat
+	for _, p := range pull.Patches() {
+		files = append(files, p.Files()...)
+	}
+
+	topts := []tableprinter.TablePrinterOption{
+		tableprinter.WithOutputFormatFromString(opts.Output),
+		tableprinter.WithColumns(opts.Columns...),
+	}
+
+	if kitcfg.G[config.Config](ctx).NoRender {
+		topts = append(topts, tableprinter.WithMaxWidth(10000))
+	} else {
+		topts = append(topts, tableprinter.WithMaxWidth(iostreams.G(ctx).TerminalWidth()))
+	}
+
+	table, err := tableprinter.NewTablePrinter(ctx, topts...)
+	if err != nil {
+		return err
+	}
+
+	cs := iostreams.G(ctx).ColorScheme()
+
+	table.AddHeader(cs.Bold, diffStatColumns...)
+
+	for _, stat := range teamDiffStats(owners, files) {
+		table.AddField(stat.Team, nil)
+		table.AddField(strconv.Itoa(stat.Files), nil)
+		table.AddField(strconv.Itoa(stat.Additions), cs.Green)
+		table.AddField(strconv.Itoa(stat.Deletions), cs.Red)
+		table.EndRow()
+	}
+
+	return table.Render(iostreams.G(ctx).Out)
+}
+
+// teamDiffStats sums the changed lines of the files per owning team.  A file
+// changed by several patches is counted once but all of its changed lines
+// are summed.  Teams are sorted by the number of changed lines, most first,
+// and files without a known owner are summarised last.
+func teamDiffStats(owners map[string][]*team.Team, files []patch.FileStat) []teamDiffStat {
+	stats := make(map[string]*teamDiffStat)
+	counted := make(map[string]map[string]bool)
+
+	add := func(name string, file patch.FileStat) {
+		stat, ok := stats[name]
+		if !ok {
+			stat = &teamDiffStat{Team: name}
+			stats[name] = stat
+			counted[name] = make(map[string]bool)
+		}
+
+		if !counted[name][file.Path] {
+			counted[name][file.Path] = true
+			stat.Files++
+		}
+
+		stat.Additions += file.Additions
+		stat.Deletions += file.Deletions
+	}
+
+	for _, file := range files {
+		if len(owners[file.Path]) == 0 {
+			add(unownedTeam, file)
+			continue
+		}
+
+		for _, t := range owners[file.Path] {
+			add(t.Name, file)
+		}
+	}
+
+	ret := make([]teamDiffStat, 0, len(stats))
+	for _, stat := range stats {
+		ret = append(ret, *stat)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if (ret[i].Team == unownedTeam) != (ret[j].Team == unownedTeam) {
+			return ret[j].Team == unownedTeam
+		}
+
+		li, lj := ret[i].Additions+ret[i].Deletions, ret[j].Additions+ret[j].Deletions
+		if li != lj {
+			return li > lj
+		}
+
+		return ret[i].Team < ret[j].Team
+	})
+
+	return ret
+}
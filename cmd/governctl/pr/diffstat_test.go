@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package pr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/unikraft/governance/internal/ownership"
+	"github.com/unikraft/governance/internal/patch"
+	"github.com/unikraft/governance/internal/team"
+)
+
+func TestTeamDiffStats(t *testing.T) {
+	fixture := filepath.Join("testdata", "diffstat")
+
+	diff, err := os.ReadFile(filepath.Join(fixture, "pr.diff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	co, _, err := ownership.FindCodeowners(fixture)
+	if err != nil {
+		t.Fatalf("could not find CODEOWNERS: %v", err)
+	}
+
+	teams := []*team.Team{
+		{Name: "arch", Type: team.SIGTeam},
+		{Name: "lib", Type: team.SIGTeam},
+		{Name: "net", Type: team.SIGTeam},
+	}
+
+	idx, err := ownership.NewIndex("unikraft", teams, ownership.WithCodeowners(co))
+	if err != nil {
+		t.Fatalf("could not build index: %v", err)
+	}
+
+	// The same file changed by two patches is counted once.
+	files := append(patch.DiffFiles(string(diff)), patch.FileStat{
+		Path:      "arch/x86/entry.S",
+		Additions: 1,
+	})
+
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+
+	want := []teamDiffStat{
+		{Team: "arch", Files: 1, Additions: 3, Deletions: 1},
+		{Team: "lib", Files: 1, Additions: 2, Deletions: 2},
+		{Team: "net", Files: 1, Additions: 2, Deletions: 2},
+		{Team: unownedTeam, Files: 1, Deletions: 2},
+	}
+
+	if got := teamDiffStats(idx.FileOwners(paths), files); !reflect.DeepEqual(got, want) {
+		t.Errorf("teamDiffStats() = %+v, want %+v", got, want)
+	}
+}
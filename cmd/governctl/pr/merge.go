@@ -6,66 +6,125 @@
 package pr
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
-	"regexp"
 	"strings"
-	"unicode"
+	"time"
 
+	"github.com/MakeNowJust/heredoc"
 	git "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/google/go-github/v63/github"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"kraftkit.sh/cmdfactory"
 	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
 	"kraftkit.sh/log"
 
 	"github.com/unikraft/governance/internal/cmdutils"
 	"github.com/unikraft/governance/internal/config"
 	"github.com/unikraft/governance/internal/ghapi"
 	"github.com/unikraft/governance/internal/ghpr"
+	"github.com/unikraft/governance/internal/hook"
+	"github.com/unikraft/governance/internal/license"
 	"github.com/unikraft/governance/internal/patch"
+	"github.com/unikraft/governance/internal/repo"
+	"github.com/unikraft/governance/internal/version"
 )
 
 type Merge struct {
-	ApproverComments   []string `long:"approver-comments" env:"GOVERN_APPROVER_COMMENTS" usage:"Regular expression that an approver writes"`
-	ApproverTeams      []string `long:"approver-teams" env:"GOVERN_APPROVER_TEAMS" usage:"The GitHub team that the approver must be a part of to be considered an approver"`
-	ApproveStates      []string `long:"approve-states" env:"GOVERN_APPROVE_STATES" usage:"The state of the GitHub approval from the assignee" default:"approve"`
-	BaseBranch         string   `long:"base" env:"GOVERN_BASE" usage:"Set the base branch name that the PR will be rebased onto"`
-	Branch             string   `long:"branch" env:"GOVERN_BRANCH" usage:"Set the branch to merge into"`
-	CommitterEmail     string   `long:"committer-email" short:"e" env:"GOVERN_COMMITTER_EMAIL" usage:"Set the Git committer author's email"`
-	CommitterGlobal    bool     `long:"committer-global" env:"GOVERN_COMMITTER_GLOBAL" usage:"Set the Git committer author's email/name globally"`
-	CommitterName      string   `long:"committer-name" short:"n" env:"GOVERN_COMMITTER_NAME" usage:"Set the Git committer author's name"`
-	IgnoreLabels       []string `long:"ignore-labels" env:"GOVERN_IGNORE_LABELS" usage:"Ignore the PR if it has any of these labels"`
-	IgnoreStates       []string `long:"ignore-states" env:"GOVERN_IGNORE_STATES" usage:"Ignore the PR if it has any of these states"`
-	Labels             []string `long:"labels" env:"GOVERN_LABELS" usage:"The PR must have these labels to be considered mergable"`
-	MinApprovals       int      `long:"min-approvals" env:"GOVERN_MIN_APPROVALS" usage:"Minimum number of approvals required to be considered mergable" default:"1"`
-	MinReviews         int      `long:"min-reviews" env:"GOVERN_MIN_REVIEWS" usage:"Minimum number of reviews a PR requires to be considered mergable" default:"1"`
-	NoAutoTrailerPatch bool     `long:"no-auto-trailer-patch" env:"GOVERN_NO_AUTO_TRAILE" usage:"Do not apply inferred trailers from mergability check to each commit"`
-	NoCheckMergable    bool     `long:"no-check-mergable" env:"GOVERN_NO_CHECK_MERGABLE" usage:"Do not run a check to test whether the PR meets merge conditions"`
-	NoConflicts        bool     `long:"no-conflicts" env:"GOVERN_NO_CONFLICTS" usage:"Pull request must not have any conflicts"`
-	NoDraft            bool     `long:"no-draft" env:"GOVERN_NO_DRAFT" usage:"Pull request must not be in a draft state"`
-	NoRespectAssignees bool     `long:"no-respect-assignees" env:"GOVERN_NO_RESPECT_ASSIGNEES" usage:"Whether the PR's assignees should be not considered approvers even if they are not part of a team/codeowner"`
-	NoRespectReviewers bool     `long:"no-respect-reviewers" env:"GOVERN_NO_RESPECT_REVIEWERS" usage:"Whether the PR's requested reviewers review should not be considered even if they are not part of a team/codeowner"`
-	Push               bool     `long:"push" env:"GOVERN_PUSH" usage:"Following the merge push to the remote"`
-	Repo               string   `long:"repo" short:"p" env:"GOVERN_REPO" usage:"Apply patches to the following local repository"`
-	ReviewerComments   []string `long:"reviewer-comments" env:"GOVERN_REVIEWER_COMMENTS" usage:"Regular expression that a reviewer writes"`
-	ReviewerTeams      []string `long:"reviewer-teams" env:"GOVERN_REVIEWER_TEAMS" usage:"The GitHub team that the reviewer must be a part to be considered a reviewer"`
-	ReviewStates       []string `long:"review-states" env:"GOVERN_REVIEW_STATES" usage:"The state of the GitHub approval from the reivewer"`
-	States             []string `long:"states" env:"GOVERN_STATES" usage:"Consider the PR mergable if it has one of these supplied states"`
-	Trailers           []string `long:"trailer" short:"t" env:"GOVERN_TRAILER" usage:"Append additional Git trailers to each git commit message"`
+	AllowProtected          bool     `long:"allow-protected" env:"GOVERN_ALLOW_PROTECTED" usage:"Push to a protected base branch without asking for confirmation"`
+	AllowedBaseBranches     []string `long:"allowed-base-branches" env:"GOVERN_ALLOWED_BASE_BRANCHES" usage:"The PR must be based on one of these branches to be considered mergable (default: any)"`
+	ApproverComments        []string `long:"approver-comments" env:"GOVERN_APPROVER_COMMENTS" usage:"Regular expression that an approver writes"`
+	ApproverTeams           []string `long:"approver-teams" env:"GOVERN_APPROVER_TEAMS" usage:"The GitHub team that the approver must be a part of to be considered an approver"`
+	ApproveStates           []string `long:"approve-states" env:"GOVERN_APPROVE_STATES" usage:"The review states of approvals from the assignee [approved (approve), changes_requested (request_changes), commented, dismissed, pending, comment]; comments always count" default:"approve"`
+	BlockOnChangesRequested bool     `long:"block-on-changes-requested" env:"GOVERN_BLOCK_ON_CHANGES_REQUESTED" usage:"Block the PR whilst the most recent review of anyone, not only of an eligible reviewer, requests changes"`
+	BotLabels               []string `long:"bot-labels" env:"GOVERN_BOT_LABELS" usage:"Labels which mark a PR as an automated dependency update (default: dependencies)"`
+	BotLogins               []string `long:"bot-logins" env:"GOVERN_BOT_LOGINS" usage:"Authors whose PRs are automated dependency updates (default: dependabot[bot], renovate[bot])"`
+	BotPolicy               string   `long:"bot-policy" env:"GOVERN_BOT_POLICY" usage:"Merge requirements of automated dependency updates [review, checks]" default:"review"`
+	BaseBranch              string   `long:"base" env:"GOVERN_BASE" usage:"Set the base branch name that the PR will be rebased onto"`
+	Branch                  string   `long:"branch" env:"GOVERN_BRANCH" usage:"Set the branch to merge into"`
+	CheckLicense            bool     `long:"check-license" env:"GOVERN_CHECK_LICENSE" usage:"Abort unless every file added by the PR carries an allowed SPDX identifier and copyright line"`
+	CloseIssues             bool     `long:"close-issues" env:"GOVERN_CLOSE_ISSUES" usage:"Close issues in the same repository referenced with Closes/Fixes/Resolves" default:"true"`
+	CommitterEmail          string   `long:"committer-email" short:"e" env:"GOVERN_COMMITTER_EMAIL" usage:"Set the Git committer author's email"`
+	CommitterGlobal         bool     `long:"committer-global" env:"GOVERN_COMMITTER_GLOBAL" usage:"Set the Git committer author's email/name globally"`
+	CommitterName           string   `long:"committer-name" short:"n" env:"GOVERN_COMMITTER_NAME" usage:"Set the Git committer author's name"`
+	IgnoreAuthors           []string `long:"ignore-authors" env:"GOVERN_IGNORE_AUTHORS" usage:"Logins, which may contain *, whose comments and reviews are not counted (default: *[bot])"`
+	IgnoreChangesRequested  bool     `long:"ignore-changes-requested" env:"GOVERN_IGNORE_CHANGES_REQUESTED" usage:"Do not block the PR whilst a reviewer's most recent review requests changes"`
+	IgnoreLabels            []string `long:"ignore-labels" env:"GOVERN_IGNORE_LABELS" usage:"Ignore the PR if it has any of these labels"`
+	IgnoreStates            []string `long:"ignore-states" env:"GOVERN_IGNORE_STATES" usage:"Ignore the PR if it has any of these states"`
+	Labels                  []string `long:"labels" env:"GOVERN_LABELS" usage:"The PR must have these labels to be considered mergable"`
+	LicenseCopyright        string   `long:"license-copyright-pattern" env:"GOVERN_LICENSE_COPYRIGHT_PATTERN" usage:"Regular expression which the copyright line of new files must match (with --check-license)"`
+	LicenseExclude          []string `long:"license-exclude" env:"GOVERN_LICENSE_EXCLUDE" usage:"Globs of files not to check in addition to vendored code, binary assets and data files (with --check-license)"`
+	LicenseHeaderLines      int      `long:"license-header-lines" env:"GOVERN_LICENSE_HEADER_LINES" usage:"Number of leading lines of new files in which the header is expected (with --check-license)" default:"10"`
+	Licenses                []string `long:"licenses" env:"GOVERN_LICENSES" usage:"SPDX identifiers which new files may carry (with --check-license)" default:"BSD-3-Clause"`
+	MergeLabel              string   `long:"merge-label" env:"GOVERN_MERGE_LABEL" usage:"Label which the PR must have to be merged and which is removed once merged (empty to disable)" default:"merge"`
+	MergedLabel             string   `long:"merged-label" env:"GOVERN_MERGED_LABEL" usage:"Label which is added to the PR once merged and which is ignored when checking mergability (empty to disable)" default:"ci/merged"`
+	MinApprovals            int      `long:"min-approvals" env:"GOVERN_MIN_APPROVALS" usage:"Minimum number of approvals required to be considered mergable" default:"1"`
+	MinReviews              int      `long:"min-reviews" env:"GOVERN_MIN_REVIEWS" usage:"Minimum number of reviews a PR requires to be considered mergable" default:"1"`
+	NoAutoTrailerPatch      bool     `long:"no-auto-trailer-patch" env:"GOVERN_NO_AUTO_TRAILE" usage:"Do not apply inferred trailers from mergability check to each commit"`
+	NoLicenseTrailer        bool     `long:"no-license-trailer" env:"GOVERN_NO_LICENSE_TRAILER" usage:"Do not append a License-checked trailer to each commit once the license check passed"`
+	FromResult              string   `long:"from-result" env:"GOVERN_FROM_RESULT" usage:"Reuse the result written by pr check mergable --result-file unless the PR has changed since"`
+	NoCheckMergable         bool     `long:"no-check-mergable" env:"GOVERN_NO_CHECK_MERGABLE" usage:"Do not run a check to test whether the PR meets merge conditions"`
+	NoConflicts             bool     `long:"no-conflicts" env:"GOVERN_NO_CONFLICTS" usage:"Pull request must not have any conflicts"`
+	NoDraft                 bool     `long:"no-draft" env:"GOVERN_NO_DRAFT" usage:"Pull request must not be in a draft state"`
+	NoRespectAssignees      bool     `long:"no-respect-assignees" env:"GOVERN_NO_RESPECT_ASSIGNEES" usage:"Whether the PR's assignees should be not considered approvers even if they are not part of a team/codeowner"`
+	NoRespectReviewers      bool     `long:"no-respect-reviewers" env:"GOVERN_NO_RESPECT_REVIEWERS" usage:"Whether the PR's requested reviewers review should not be considered even if they are not part of a team/codeowner"`
+	Output                  string   `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+	ProtectedBranches       []string `long:"protected-branches" env:"GOVERN_PROTECTED_BRANCHES" usage:"Base branches, or patterns thereof, which require confirmation before pushing" default:"stable,release/*"`
+	Push                    bool     `long:"push" env:"GOVERN_PUSH" usage:"Following the merge push to the remote"`
+	Repo                    string   `long:"repo" short:"p" env:"GOVERN_REPO" usage:"Apply patches to the following local repository"`
+	RequiredApprovers       []string `long:"required-approvers" env:"GOVERN_REQUIRED_APPROVERS" usage:"Logins of users who must each approve the PR in addition to the minimum number of approvals"`
+	ReviewerComments        []string `long:"reviewer-comments" env:"GOVERN_REVIEWER_COMMENTS" usage:"Regular expression that a reviewer writes"`
+	ReviewerTeams           []string `long:"reviewer-teams" env:"GOVERN_REVIEWER_TEAMS" usage:"The GitHub team that the reviewer must be a part to be considered a reviewer"`
+	ReviewStates            []string `long:"review-states" env:"GOVERN_REVIEW_STATES" usage:"The review states of reviews from the reviewer, as for --approve-states (default: all)"`
+	States                  []string `long:"states" env:"GOVERN_STATES" usage:"Consider the PR mergable if it has one of these supplied states"`
+	Trailers                []string `long:"trailer" short:"t" env:"GOVERN_TRAILER" usage:"Append additional Git trailers to each git commit message"`
+	VerbatimMessages        bool     `long:"verbatim-messages" env:"GOVERN_VERBATIM_MESSAGES" usage:"Preserve commit messages byte-for-byte instead of rewriting '---' (always the case for bot PRs)"`
+}
+
+// MergePlan describes the commits which would be merged into the base branch
+// together with the changes which would follow a successful push.
+type MergePlan struct {
+	Org          string   `json:"org"`
+	Repo         string   `json:"repo"`
+	PullRequest  int      `json:"pull_request"`
+	Base         string   `json:"base"`
+	Commits      []string `json:"commits"`
+	Trailers     []string `json:"trailers,omitempty"`
+	AddLabels    []string `json:"add_labels"`
+	RemoveLabels []string `json:"remove_labels"`
+	CloseIssues  []int    `json:"close_issues,omitempty"`
+
+	// FreezeExemption is the label which exempted the pull request from the
+	// freeze of the repository, if any.
+	FreezeExemption string `json:"freeze_exemption,omitempty"`
 }
 
 func NewMerge() *cobra.Command {
-	cmd, err := cmdfactory.New(&Merge{}, cobra.Command{
-		Use:   "merge [OPTIONS] ORG/REPO/PRID",
+	cmd, err := cmdutils.New(&Merge{}, cobra.Command{
+		Use:   "merge [OPTIONS] ORG/REPO/PRID|ORG/REPO",
 		Short: "Merge a pull request",
-		Args:  cobra.MaximumNArgs(2),
+		Long: heredoc.Doc(`
+		Merge a pull request
+
+		Given ORG/REPO without the ID of a pull request, every open pull request
+		of the repository which carries the --labels and the --merge-label is
+		merged in ascending order of its number.  A pull request which cannot be
+		merged does not stop the others from being merged and a summary of each
+		is shown at the end.
+		`),
+		Args: cobra.MaximumNArgs(2),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "pr",
 		},
@@ -77,22 +136,179 @@ func NewMerge() *cobra.Command {
 	return cmd
 }
 
-func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
+// Validate rejects invalid settings before the repository is cloned, e.g. an
+// incomplete committer, malformed comment expressions, review states or branch
+// patterns, a --push which lacks its --base or is combined with --dry-run,
+// blocking on and ignoring requested changes at once and clashing merge labels.
+func (opts *Merge) Validate(ctx context.Context) error {
+	if err := cmdutils.ValidatePlanOutput(ctx, opts.Output); err != nil {
+		return err
+	}
+
+	if err := config.ValidateCommitter(opts.CommitterName, opts.CommitterEmail, opts.CommitterGlobal); err != nil {
+		return err
+	}
+
+	if err := ghpr.ValidateCommentExpressions(opts.ApproverComments); err != nil {
+		return fmt.Errorf("invalid --approver-comments: %w", err)
+	}
+
+	if err := ghpr.ValidateCommentExpressions(opts.ReviewerComments); err != nil {
+		return fmt.Errorf("invalid --reviewer-comments: %w", err)
+	}
+
+	if err := config.Exclusive("push", opts.Push, "dry-run", kitcfg.G[config.Config](ctx).DryRun); err != nil {
+		return err
+	}
+
+	if err := config.Requires("push", opts.Push, "base", opts.BaseBranch != ""); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("from-result", opts.FromResult != "", "no-check-mergable", opts.NoCheckMergable); err != nil {
+		return err
+	}
+
+	if err := config.Requires("allow-protected", opts.AllowProtected, "push", opts.Push); err != nil {
+		return err
+	}
+
+	for _, pattern := range opts.ProtectedBranches {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid --protected-branches pattern '%s': %w", pattern, err)
+		}
+	}
+
+	if err := ghpr.ValidateBotPolicy(opts.BotPolicy); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("block-on-changes-requested", opts.BlockOnChangesRequested, "ignore-changes-requested", opts.IgnoreChangesRequested); err != nil {
+		return err
+	}
+
+	if err := ghpr.ValidateReviewStates(opts.ApproveStates); err != nil {
+		return fmt.Errorf("invalid --approve-states: %w", err)
+	}
+
+	if err := ghpr.ValidateReviewStates(opts.ReviewStates); err != nil {
+		return fmt.Errorf("invalid --review-states: %w", err)
+	}
+
+	if err := config.Requires("no-license-trailer", opts.NoLicenseTrailer, "check-license", opts.CheckLicense); err != nil {
+		return err
+	}
+
+	if _, err := opts.licenseChecker(); err != nil {
+		return err
+	}
+
+	if err := config.NotNegative("min-approvals", opts.MinApprovals); err != nil {
+		return err
+	}
+
+	if opts.MergeLabel != "" && opts.MergeLabel == opts.MergedLabel {
+		return fmt.Errorf("--merge-label and --merged-label must differ")
+	}
+
+	for _, label := range opts.IgnoreLabels {
+		if opts.MergeLabel != "" && label == opts.MergeLabel {
+			return fmt.Errorf("--merge-label cannot also be one of --ignore-labels: %s", label)
+		}
+	}
+
+	return config.NotNegative("min-reviews", opts.MinReviews)
+}
+
+// gateLabels returns the labels which the PR must have and those which it
+// must not have to be considered mergable.  Besides the explicitly requested
+// labels, the PR must carry the merge label and must not already carry the
+// merged label.
+func (opts *Merge) gateLabels() (labels, ignoreLabels []string) {
+	labels = append(labels, opts.Labels...)
+	ignoreLabels = append(ignoreLabels, opts.IgnoreLabels...)
+
+	if opts.MergeLabel != "" && !containsString(labels, opts.MergeLabel) {
+		labels = append(labels, opts.MergeLabel)
+	}
+
+	if opts.MergedLabel != "" && !containsString(ignoreLabels, opts.MergedLabel) {
+		ignoreLabels = append(ignoreLabels, opts.MergedLabel)
+	}
+
+	return labels, ignoreLabels
+}
+
+// licenseChecker returns the checker of the headers of new files configured
+// by the flags.
+func (opts *Merge) licenseChecker() (*license.Checker, error) {
+	return license.NewChecker(
+		license.WithAllowedLicenses(opts.Licenses...),
+		license.WithCopyrightPattern(opts.LicenseCopyright),
+		license.WithHeaderLines(opts.LicenseHeaderLines),
+		license.WithExclude(opts.LicenseExclude...),
+	)
+}
+
+// transitionLabels returns the labels which are added to and removed from the
+// PR once it has been merged.
+func (opts *Merge) transitionLabels() (add, remove []string) {
+	add, remove = []string{}, []string{}
+
+	if opts.MergedLabel != "" {
+		add = append(add, opts.MergedLabel)
+	}
+
+	if opts.MergeLabel != "" {
+		remove = append(remove, opts.MergeLabel)
+	}
+
+	return add, remove
+}
+
+// containsString returns whether the list contains the entry.
+func containsString(list []string, entry string) bool {
+	for _, e := range list {
+		if e == entry {
+			return true
+		}
+	}
+
+	return false
+}
+
+// errNotMergable is returned if the pull request does not satisfy the merge
+// requirements.
+var errNotMergable = errors.New("pull request is not mergable")
+
+func (opts *Merge) Run(ctx context.Context, args []string) error {
+	if kitcfg.G[config.Config](ctx).ReadOnly {
+		return fmt.Errorf("cannot merge pull request: %w", ghapi.ErrReadOnly)
+	}
+
+	if len(args) == 1 {
+		if ghOrg, ghRepo, ok := parseBatchArgs(args[0]); ok {
+			return opts.runBatch(ctx, ghOrg, ghRepo)
+		}
+	}
+
 	ghOrg, ghRepo, ghPrId, err := cmdutils.ParseOrgRepoAndPullRequestArgs(args)
 	if err != nil {
 		return err
 	}
 
-	ghClient, err := ghapi.NewGithubClient(
-		ctx,
-		kitcfg.G[config.Config](ctx).GithubToken,
-		kitcfg.G[config.Config](ctx).GithubSkipSSL,
-		kitcfg.G[config.Config](ctx).GithubEndpoint,
-	)
+	ghClient, err := cmdutils.NewGithubClient(ctx)
 	if err != nil {
 		return err
 	}
 
+	return opts.merge(ctx, ghClient, ghOrg, ghRepo, ghPrId)
+}
+
+// merge checks, patches and merges a single pull request.
+func (opts *Merge) merge(ctx context.Context, ghClient *ghapi.GithubClient, ghOrg, ghRepo string, ghPrId int) (ferr error) {
+	ctx = ghapi.WithOperation(ghapi.WithAPIUsagePullRequest(ctx, ghOrg, ghRepo, ghPrId), ghapi.OperationMerge)
+
 	pull, err := ghpr.NewPullRequestFromID(ctx,
 		ghClient,
 		ghOrg,
@@ -103,6 +319,8 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 		opts.CommitterGlobal,
 		ghpr.WithBaseBranch(opts.BaseBranch),
 		ghpr.WithWorkdir(kitcfg.G[config.Config](ctx).TempDir),
+		ghpr.WithCredentials(kitcfg.G[config.Config](ctx).GithubUser, kitcfg.G[config.Config](ctx).GithubToken),
+		ghpr.WithGitBinary(kitcfg.G[config.Config](ctx).GitBinary),
 	)
 	if err != nil {
 		return fmt.Errorf("could not prepare pull request: %w", err)
@@ -118,37 +336,106 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 		}
 	}()
 
+	// Refuse to merge into a branch which is not allowed, i.e. neither the base
+	// of the pull request nor the branch it is rebased onto, even if the merge
+	// requirements are not checked.
+	for _, base := range []string{pull.Metadata().GetBase().GetRef(), pull.BaseBranch()} {
+		if err := ghpr.CheckBaseBranch(opts.AllowedBaseBranches, base); err != nil {
+			return fmt.Errorf("cannot merge pull request: %w", err)
+		}
+	}
+
+	freeze, err := repoFreeze(ctx, ghOrg, ghRepo)
+	if err != nil {
+		return err
+	}
+
+	exemption, err := checkFreeze(freeze, time.Now(), pull.Metadata())
+	if err != nil {
+		return fmt.Errorf("cannot merge pull request: %w", err)
+	} else if exemption != "" {
+		log.G(ctx).
+			WithField("repo", fmt.Sprintf("%s/%s", ghOrg, ghRepo)).
+			WithField("label", exemption).
+			Warn("merging pull request into frozen repository")
+	}
+
+	hooks, err := cmdutils.NewHookRunner(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload := hook.NewPayload(ghOrg, ghRepo, pull.Metadata(), kitcfg.G[config.Config](ctx).DryRun)
+
 	// Check if the pull request is mergable
 	if !opts.NoCheckMergable {
-		log.G(ctx).Info("checking if the pull request satisfies merge requirements")
-		mergable, results, err := pull.SatisfiesMergeRequirements(ctx,
+		labels, ignoreLabels := opts.gateLabels()
+
+		payload.Point = hook.PointPreMergability
+		if err := hooks.Run(ctx, payload); err != nil {
+			return fmt.Errorf("pull request is not mergable: %w", err)
+		}
+
+		mopts := []ghpr.PullRequestMergableOption{
+			ghpr.WithAllowedBaseBranches(opts.AllowedBaseBranches...),
 			ghpr.WithApproverComments(opts.ApproverComments...),
 			ghpr.WithApproverTeams(opts.ApproverTeams...),
 			ghpr.WithApproveStates(opts.ApproveStates...),
-			ghpr.WithIgnoreLabels(opts.IgnoreLabels...),
+			ghpr.WithBlockOnChangesRequested(opts.BlockOnChangesRequested),
+			ghpr.WithBotLabels(opts.BotLabels...),
+			ghpr.WithBotLogins(opts.BotLogins...),
+			ghpr.WithBotPolicy(opts.BotPolicy),
+			ghpr.WithIgnoreAuthors(opts.IgnoreAuthors...),
+			ghpr.WithIgnoreChangesRequested(opts.IgnoreChangesRequested),
+			ghpr.WithIgnoreLabels(ignoreLabels...),
 			ghpr.WithIgnoreStates(opts.IgnoreStates...),
-			ghpr.WithLabels(opts.Labels...),
+			ghpr.WithLabels(labels...),
 			ghpr.WithMinApprovals(opts.MinApprovals),
 			ghpr.WithMinReviews(opts.MinReviews),
 			ghpr.WithNoConflicts(opts.NoConflicts),
 			ghpr.WithNoDraft(opts.NoDraft),
 			ghpr.WithNoRespectAssignees(opts.NoRespectAssignees),
 			ghpr.WithNoRespectReviewers(opts.NoRespectReviewers),
+			ghpr.WithRequiredApprovers(opts.RequiredApprovers...),
 			ghpr.WithReviewerComments(opts.ReviewerComments...),
 			ghpr.WithReviewerTeams(opts.ReviewerTeams...),
 			ghpr.WithReviewStates(opts.ReviewStates...),
 			ghpr.WithStates(opts.States...),
-		)
+		}
+
+		var mergable bool
+		var results map[string][]string
+
+		if opts.FromResult != "" {
+			var result *ghpr.MergeResult
+			if result, err = ghpr.ReadMergeResultFile(opts.FromResult); err != nil {
+				return err
+			}
+
+			log.G(ctx).
+				WithField("evaluated_at", result.EvaluatedAt.Format(time.RFC3339)).
+				Info("checking if the recorded merge result still applies")
+			mergable, results, err = pull.SatisfiesMergeResult(ghapi.WithOperation(ctx, ghapi.OperationMergability), result, mopts...)
+		} else {
+			log.G(ctx).Info("checking if the pull request satisfies merge requirements")
+			mergable, results, err = pull.SatisfiesMergeRequirements(ghapi.WithOperation(ctx, ghapi.OperationMergability), mopts...)
+		}
+
+		payload.Point = hook.PointPostMergability
+		_ = hooks.Run(ctx, payload.WithMergability(mergable, results, err))
+
 		if err != nil {
 			return fmt.Errorf("pull request is not mergable: %w", err)
 		} else if !mergable {
-			return fmt.Errorf("pull request is not mergable")
+			return errNotMergable
 		}
 
 		if !opts.NoAutoTrailerPatch {
 			for k, trailers := range results {
-				r := []rune(k)
-				trailerName := strings.ReplaceAll(string(append([]rune{unicode.ToUpper(r[0])}, r[1:]...)), "_", "-")
+				trailerName, err := ghpr.TrailerName(k)
+				if err != nil {
+					return err
+				}
 
 				for _, trailer := range trailers {
 					opts.Trailers = append(opts.Trailers,
@@ -159,13 +446,46 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 		}
 	}
 
+	// Check the headers of the files added by the pull request
+	if opts.CheckLicense {
+		checker, err := opts.licenseChecker()
+		if err != nil {
+			return err
+		}
+
+		log.G(ctx).Info("checking the license headers of new files")
+		if violations := checker.Check(pull.Patches()...); len(violations) > 0 {
+			for _, violation := range violations {
+				log.G(ctx).
+					WithField("file", violation.File).
+					WithField("commit", violation.Patch).
+					Error(violation.Reason)
+			}
+
+			return fmt.Errorf("pull request is not mergable: fix the license header of: %s", strings.Join(license.Files(violations), ", "))
+		}
+
+		if !opts.NoLicenseTrailer {
+			opts.Trailers = append(opts.Trailers,
+				fmt.Sprintf("License-checked: governctl/%s", version.Version()),
+			)
+		}
+	}
+
+	// Record why the pull request was merged despite the freeze
+	if exemption != "" {
+		opts.Trailers = append(opts.Trailers,
+			fmt.Sprintf("Freeze-exempted-by: %s", exemption),
+		)
+	}
+
 	// Add trailer to close original PR
 	opts.Trailers = append(opts.Trailers,
 		fmt.Sprintf("GitHub-Closes: #%d", ghPrId),
 	)
 
 	// Add tested-by trailer if we're running in GitHub Actions
-	if os.Getenv("GITHUB_ACTIONS") == "yes" {
+	if cmdutils.InGithubActions() {
 		opts.Trailers = append(opts.Trailers,
 			"Tested-by: GitHub Actions <monkey+github-actions@unikraft.io>",
 		)
@@ -205,14 +525,18 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 			copts.ReferenceName = plumbing.ReferenceName(opts.BaseBranch)
 		}
 		if _, err := git.PlainClone(opts.Repo, false, copts); err != nil {
-			log.G(ctx).Fatalf("could not clone repository: %s", err)
-			os.Exit(1)
+			return fmt.Errorf("could not clone repository: %w", err)
 		}
 	}
 
+	gitBinary := kitcfg.G[config.Config](ctx).GitBinary
+	if gitBinary == "" {
+		gitBinary = patch.DefaultGitBinary
+	}
+
 	// Add commiter name
 	if opts.CommitterName != "" {
-		cmd := exec.Command("git", "-C", opts.Repo, "config", "user.name", opts.CommitterName)
+		cmd := exec.Command(gitBinary, "-C", opts.Repo, "config", "user.name", opts.CommitterName)
 		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
 		cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
 		if err := cmd.Run(); err != nil {
@@ -222,7 +546,7 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 
 	// Add commiter email
 	if opts.CommitterEmail != "" {
-		cmd := exec.Command("git", "-C", opts.Repo, "config", "user.email", opts.CommitterEmail)
+		cmd := exec.Command(gitBinary, "-C", opts.Repo, "config", "user.email", opts.CommitterEmail)
 		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
 		cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
 		if err := cmd.Run(); err != nil {
@@ -232,7 +556,7 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 
 	// Create "<base>-PRID" branch and push it to remote
 	// Checkout "<base>" branch
-	cmd := exec.Command("git", "-C", opts.Repo, "checkout", opts.BaseBranch)
+	cmd := exec.Command(gitBinary, "-C", opts.Repo, "checkout", opts.BaseBranch)
 	cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
 	cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
 	if err := cmd.Run(); err != nil {
@@ -243,7 +567,7 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 	tempBranch := fmt.Sprintf("%s-%d", opts.BaseBranch, ghPrId)
 
 	// Create "<base>-PRID" branch
-	cmd = exec.Command("git", "-C", opts.Repo, "checkout", "-b", tempBranch)
+	cmd = exec.Command(gitBinary, "-C", opts.Repo, "checkout", "-b", tempBranch)
 	cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
 	cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
 	if err := cmd.Run(); err != nil {
@@ -252,7 +576,7 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 
 	// Create <base>-PRID" branch remotely also
 	cmd = exec.Command(
-		"git",
+		gitBinary,
 		"-C", opts.Repo,
 		"remote", "add", "patched",
 		fmt.Sprintf("https://%s:%s@github.com/%s/%s.git",
@@ -267,12 +591,34 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 		return fmt.Errorf("could not apply patch: %w", err)
 	}
 
-	var token string
-	var closeableIssues []string
-	regex := regexp.MustCompile(`(Closes|Fixes|Resolves): #[0-9]+`)
 	if !kitcfg.G[config.Config](ctx).DryRun {
+		// Decide whether the base branch may be pushed to before anything is
+		// merged into "<base>-PRID", as a refusal afterwards would leave the
+		// PR merged into the temporary branch.
+		if opts.Push {
+			if err := ensureFastForward(ctx, gitBinary, opts.Repo, "patched", opts.BaseBranch); err != nil {
+				return err
+			}
+
+			override, err := opts.confirmPush(ghOrg, ghRepo, len(pull.Patches()), iostreams.G(ctx).CanPrompt(), func(question string) (bool, error) {
+				return promptConfirm(ctx, question)
+			})
+			if err != nil {
+				return err
+			}
+
+			if override != "" {
+				log.G(ctx).
+					WithField("repo", fmt.Sprintf("%s/%s", ghOrg, ghRepo)).
+					WithField("branch", opts.BaseBranch).
+					WithField("commits", len(pull.Patches())).
+					WithField("override", override).
+					Warn("pushing to protected branch")
+			}
+		}
+
 		// Push "<base>-PRID" branch to given repo
-		cmd = exec.Command("git", "-C", opts.Repo, "push", "-u", "patched", tempBranch)
+		cmd = exec.Command(gitBinary, "-C", opts.Repo, "push", "-u", "patched", tempBranch)
 		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
 		cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
 		if err := cmd.Run(); err != nil {
@@ -287,7 +633,7 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 
 			// Delete remote "<base>-PRID" branch at the end
 			// Use git and run: git push -d <remote_name> <branchname>
-			cmd = exec.Command("git", "-C", opts.Repo, "push", "-d", "patched", tempBranch)
+			cmd = exec.Command(gitBinary, "-C", opts.Repo, "push", "-d", "patched", tempBranch)
 			cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
 			cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
 			if err := cmd.Run(); err != nil {
@@ -295,71 +641,19 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 			}
 		}()
 
-		// Backup old token to a string
-		// Use gh and run: gh auth token
-		var output []byte
-		cmd = exec.Command("gh", "auth", "token")
-		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
-		if output, err = cmd.Output(); err != nil {
-			log.G(ctx).Warn("no token to back up, skipping")
-			token = ""
-		} else {
-			token = string(output)
-		}
-
-		if token != "" && !strings.HasPrefix(token, "gh") {
-			return fmt.Errorf("could not backup token, invalid format (try running `gh auth token` manually): %w", err)
-		}
-
-		// Login with given token
-		// Use gh and run: gh auth login --with-token < <token>
-		cmd = exec.Command("gh", "auth", "login", "--with-token")
-		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
-		cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
-		cmd.Stdin = bytes.NewReader([]byte(kitcfg.G[config.Config](ctx).GithubToken))
-		if err := cmd.Run(); err != nil {
-			if token == "" {
-				return fmt.Errorf("could not update token and no token already exists: %w", err)
-			}
-		}
-
-		// Save PR body
-		cmd = exec.Command("gh", "pr", "view", fmt.Sprintf("%d", ghPrId),
-			"-R", fmt.Sprintf("%s/%s", ghOrg, ghRepo),
-		)
-		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
-		cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
-		var prBody []byte
-		if prBody, err = cmd.Output(); err != nil {
-			return fmt.Errorf("could not get PR body: %w", err)
-		}
-
-		matches := regex.FindAll(prBody, -1)
-		for _, match := range matches {
-			closeableIssues = append(closeableIssues, strings.Split(string(match), "#")[1])
-		}
-
 		// Change PR base branch to "<base>-PRID"
-		// Use gh and run: gh pr edit <PRID> --base <base-PRID>
-		cmd = exec.Command("gh", "pr", "edit", fmt.Sprintf("%d", ghPrId), "--base", tempBranch, "-R", fmt.Sprintf("%s/%s", ghOrg, ghRepo))
-		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
-		cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
-		if err := cmd.Run(); err != nil {
+		if err := ghClient.UpdatePullRequestBase(ctx, ghOrg, ghRepo, ghPrId, tempBranch); err != nil {
 			return fmt.Errorf("could not change base branch to %s: %w", tempBranch, err)
 		}
 
 		// Rebase & Merge PR on top of "<base>-PRID"
-		// Use gh and run: gh pr merge <PRID> --rebase --delete-branch
-		cmd = exec.Command("gh", "pr", "merge", fmt.Sprintf("%d", ghPrId), "--rebase", "-R", fmt.Sprintf("%s/%s", ghOrg, ghRepo))
-		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
-		cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
-		if err := cmd.Run(); err != nil {
+		if err := ghClient.MergePullRequest(ctx, ghOrg, ghRepo, ghPrId, "rebase"); err != nil {
 			return fmt.Errorf("could not merge with rebase into %s: %w", tempBranch, err)
 		}
 	}
 
 	// Move back to "<base>" branch
-	cmd = exec.Command("git", "-C", opts.Repo, "checkout", opts.BaseBranch)
+	cmd = exec.Command(gitBinary, "-C", opts.Repo, "checkout", opts.BaseBranch)
 	cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
 	cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
 	if err := cmd.Run(); err != nil {
@@ -372,13 +666,12 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 
 	for i, patch := range pull.Patches() {
 		invertedPatches[len(pull.Patches())-1-i] = patch
-
-		matches := regex.FindAllString(patch.Message, -1)
-		for _, match := range matches {
-			closeableIssues = append(closeableIssues, strings.Split(match, "#")[1])
-		}
 	}
 
+	// Bot pull requests, e.g. from dependabot, carry long bodies with YAML
+	// metadata which must survive the merge.
+	verbatim := opts.VerbatimMessages || ghpr.IsBotPullRequest(pull.Metadata(), opts.BotLogins, opts.BotLabels)
+
 	for _, patch := range invertedPatches {
 		log.G(ctx).
 			WithField("title", patch.Title).
@@ -386,25 +679,51 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 
 		patch.Trailers = append(patch.Trailers, opts.Trailers...)
 
-		// Bug in git: it starts reading from triple dashes and discard everything
-		// till it finds "diff", meaning, for example, dependabot PRs will have
-		// truncated messages. This is fine for now.
-		patch.Message = strings.ReplaceAll(patch.Message, "---", "...")
-
-		cmd := exec.Command("git", "-C", opts.Repo, "am", "--3way")
-		cmd.Stdin = bytes.NewReader(patch.Bytes())
-		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
-		cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("could not apply patch: %w", err)
+		if err := applyPatch(ctx, gitBinary, opts.Repo, patch, verbatim); err != nil {
+			return err
 		}
 	}
 
+	addLabels, removeLabels := opts.transitionLabels()
+
+	plan := &MergePlan{
+		Org:          ghOrg,
+		Repo:         ghRepo,
+		PullRequest:  ghPrId,
+		Base:         opts.BaseBranch,
+		Commits:      make([]string, 0, len(invertedPatches)),
+		Trailers:     opts.Trailers,
+		AddLabels:    addLabels,
+		RemoveLabels: removeLabels,
+
+		FreezeExemption: exemption,
+	}
+
+	for _, patch := range invertedPatches {
+		plan.Commits = append(plan.Commits, patch.Title)
+	}
+
+	if opts.CloseIssues {
+		plan.CloseIssues = pull.ClosesIssues()
+	}
+
+	if err := cmdutils.WritePlan(ctx, opts.Output, plan); err != nil {
+		return err
+	}
+
 	if !kitcfg.G[config.Config](ctx).DryRun && opts.Push {
+		payload.Commits = plan.Commits
+		payload.Trailers = plan.Trailers
+
+		payload.Point = hook.PointPreMergePush
+		if err := hooks.Run(ctx, payload); err != nil {
+			return fmt.Errorf("could not push: %w", err)
+		}
+
 		// Add remote with origin "<base>" and push
 		log.G(ctx).Info("pushing to remote")
 		cmd = exec.Command(
-			"git",
+			gitBinary,
 			"-C", opts.Repo,
 			"push", "-u", "patched",
 			opts.BaseBranch,
@@ -415,47 +734,232 @@ func (opts *Merge) Run(ctx context.Context, args []string) (ferr error) {
 			return fmt.Errorf("could not apply patch: %w", err)
 		}
 
-		// Remove 'merge' label from PR and add 'ci/merged' label
-		log.G(ctx).Info("removing 'merge' label and adding 'ci/merged' label")
-		cmd = exec.Command("gh", "pr", "edit", fmt.Sprintf("%d", ghPrId),
-			"--remove-label", "merge",
-			"--add-label", "ci/merged",
-			"-R", fmt.Sprintf("%s/%s", ghOrg, ghRepo),
-		)
-		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
-		cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
-		if err := cmd.Run(); err != nil {
-			log.G(ctx).Errorf("could not change label from 'merge' to 'ci/merged': %s", err)
+		// Remove the merge label from the PR and add the merged label
+		if len(addLabels) > 0 || len(removeLabels) > 0 {
+			log.G(ctx).
+				WithField("add", addLabels).
+				WithField("remove", removeLabels).
+				Info("updating labels")
+
+			if err := relabel(ctx, ghClient, ghOrg, ghRepo, ghPrId, addLabels, removeLabels); err != nil {
+				log.G(ctx).Errorf("could not update labels: %s", err)
+			}
 		}
 
 		// Close related issues
-		log.G(ctx).Info("closing related issues")
-		for _, issue := range closeableIssues {
-			cmd = exec.Command("gh", "issue", "close", issue,
-				"--reason", "completed",
-				"--comment", "This issue was closed by PR number "+fmt.Sprintf("#%d", ghPrId)+" which was merged successfully.",
-				"-R", fmt.Sprintf("%s/%s", ghOrg, ghRepo),
-			)
-			cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
-			cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
-			if err := cmd.Run(); err != nil {
-				log.G(ctx).Errorf("could not close issue %s: %s", issue, err)
+		if opts.CloseIssues {
+			for _, ref := range pull.ClosesForeignIssues() {
+				log.G(ctx).
+					WithField("issue", ref).
+					Warn("not closing issue in another repository")
+			}
+
+			log.G(ctx).Info("closing related issues")
+			for _, issue := range pull.ClosesIssues() {
+				if err := ghClient.CloseIssueWithComment(ctx, ghOrg, ghRepo, issue,
+					fmt.Sprintf("This issue was closed by PR number #%d which was merged successfully.", ghPrId),
+				); err != nil {
+					log.G(ctx).Errorf("could not close issue #%d: %s", issue, err)
+					continue
+				}
+
+				log.G(ctx).
+					WithField("issue", fmt.Sprintf("%s/%s#%d", ghOrg, ghRepo, issue)).
+					Info("closed issue")
 			}
-			log.G(ctx).Info("closed " + issue)
 		}
+
+		payload.Point = hook.PointPostMerge
+		_ = hooks.Run(ctx, payload)
 	}
 
-	if !kitcfg.G[config.Config](ctx).DryRun && token != "" {
-		// Replace token with the original one
-		// Use gh and run: gh auth login --with-token < <token>
-		cmd = exec.Command("gh", "auth", "login", "--with-token")
-		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
-		cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
-		cmd.Stdin = bytes.NewReader([]byte(token))
-		if err := cmd.Run(); err != nil {
-			log.G(ctx).Errorf("could not update token: %s", err)
+	return nil
+}
+
+// repoFreeze returns the freeze of the repository as configured in the repos
+// definition directory, if any.  Unlike other settings of the repository, a
+// definition which cannot be read is an error such that a freeze is not
+// silently ignored.
+func repoFreeze(ctx context.Context, org, name string) (*repo.Freeze, error) {
+	reposDir := kitcfg.G[config.Config](ctx).ReposDir
+	if _, err := os.Stat(reposDir); err != nil {
+		return nil, nil
+	}
+
+	repos, err := repo.NewListOfReposFromPath(nil, org, reposDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read repository definitions: %w", err)
+	}
+
+	if r := repo.FindRepoByName(name, repos); r != nil {
+		return r.Freeze, nil
+	}
+
+	return nil, nil
+}
+
+// checkFreeze rejects the pull request if the freeze is active at the provided
+// time and the pull request does not carry any of its allowed labels.  It
+// returns the label which exempts the pull request, if any.
+func checkFreeze(freeze *repo.Freeze, now time.Time, pr *github.PullRequest) (string, error) {
+	if !freeze.ActiveAt(now) {
+		return "", nil
+	}
+
+	var labels []string
+	for _, label := range pr.Labels {
+		labels = append(labels, label.GetName())
+	}
+
+	exemption := freeze.Exemption(labels)
+	if exemption == "" {
+		return "", fmt.Errorf("repository is frozen: %s", freeze.Notice())
+	}
+
+	return exemption, nil
+}
+
+// isProtectedBranch returns whether the branch matches any of the patterns,
+// e.g. "release/*" matches "release/0.15".
+func isProtectedBranch(branch string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// confirmPush decides whether the commits may be pushed to the base branch.
+// Pushing to a protected branch requires either --allow-protected or, if it is
+// possible to prompt, an interactive confirmation and is refused otherwise.
+// It returns how the protection was overridden, if it was, such that it can be
+// recorded.
+func (opts *Merge) confirmPush(org, repo string, commits int, canPrompt bool, confirm func(string) (bool, error)) (string, error) {
+	if !isProtectedBranch(opts.BaseBranch, opts.ProtectedBranches) {
+		return "", nil
+	}
+
+	if opts.AllowProtected {
+		return "--allow-protected", nil
+	}
+
+	if !canPrompt {
+		return "", fmt.Errorf("refusing to push to protected branch %s of %s/%s without --allow-protected", opts.BaseBranch, org, repo)
+	}
+
+	ok, err := confirm(fmt.Sprintf("Push %d commits to the protected branch %s of %s/%s?", commits, opts.BaseBranch, org, repo))
+	if err != nil {
+		return "", fmt.Errorf("could not confirm push: %w", err)
+	} else if !ok {
+		return "", fmt.Errorf("push to protected branch %s of %s/%s was not confirmed", opts.BaseBranch, org, repo)
+	}
+
+	return "interactive confirmation", nil
+}
+
+// promptConfirm asks the question on the terminal and returns whether it was
+// answered with yes.
+func promptConfirm(ctx context.Context, question string) (bool, error) {
+	fmt.Fprintf(iostreams.G(ctx).ErrOut, "%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(iostreams.G(ctx).In).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// ensureFastForward fetches the branch from the remote and returns an error
+// if the local branch does not contain it, i.e. if pushing would require
+// overwriting the history of the remote.
+func ensureFastForward(ctx context.Context, gitBinary, repo, remote, branch string) error {
+	cmd := exec.Command(gitBinary, "-C", repo, "fetch", remote, branch)
+	cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
+	cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("could not fetch %s from %s: %w", branch, remote, err)
+	}
+
+	cmd = exec.Command(gitBinary, "-C", repo, "merge-base", "--is-ancestor", "FETCH_HEAD", branch)
+	cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
+
+	var exitErr *exec.ExitError
+	if err := cmd.Run(); errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return fmt.Errorf("local %s is not a fast-forward of %s/%s, refusing to push: the remote branch has moved on, rebase onto it (git pull --rebase %s %s) and merge again", branch, remote, branch, remote, branch)
+	} else if err != nil {
+		return fmt.Errorf("could not compare %s with %s/%s: %w", branch, remote, branch, err)
+	}
+
+	return nil
+}
+
+// relabel removes and adds the provided labels of the PR.  Labels which are
+// to be removed but which the PR does not carry are ignored.
+func relabel(ctx context.Context, ghClient *ghapi.GithubClient, org, repo string, prId int, add, remove []string) error {
+	current, err := ghClient.GetPullRequest(ctx, org, repo, prId)
+	if err != nil {
+		return err
+	}
+
+	var carried []string
+	for _, label := range current.Labels {
+		if containsString(remove, label.GetName()) {
+			carried = append(carried, label.GetName())
 		}
 	}
 
+	if err := ghClient.RemovePullRequestLabels(ctx, org, repo, prId, carried); err != nil {
+		return err
+	}
+
+	if len(add) == 0 {
+		return nil
+	}
+
+	return ghClient.AddPullRequestLabels(ctx, org, repo, prId, add)
+}
+
+// applyPatch applies the patch onto the checked out branch of the repository.
+// Since git stops reading the commit message at the first line which consists
+// of triple dashes, the message is either rewritten to not contain any, or,
+// if verbatim is set, the resulting commit is amended with the original
+// message such that it is preserved byte-for-byte.
+func applyPatch(ctx context.Context, gitBinary, repo string, p *patch.Patch, verbatim bool) error {
+	if !verbatim {
+		p.Message = strings.ReplaceAll(p.Message, "---", "...")
+	}
+
+	cmd := exec.Command(gitBinary, "-C", repo, "am", "--3way")
+	cmd.Stdin = bytes.NewReader(p.Bytes())
+	cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
+	cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("could not apply patch: %w", err)
+	}
+
+	if !verbatim {
+		return nil
+	}
+
+	cmd = exec.Command(gitBinary, "-C", repo,
+		"commit", "--amend", "--allow-empty", "--no-verify",
+		"--cleanup=verbatim",
+		"--file=-",
+	)
+	cmd.Stdin = strings.NewReader(p.CommitMessage())
+	cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
+	cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("could not preserve commit message: %w", err)
+	}
+
 	return nil
 }
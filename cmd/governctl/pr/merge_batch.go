@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package pr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/tableprinter"
+)
+
+const (
+	// BatchStatusMerged is the status of a pull request which has been merged,
+	// or which would have been merged in dry-run mode.
+	BatchStatusMerged = "merged"
+
+	// BatchStatusSkipped is the status of a pull request which does not
+	// satisfy the merge requirements.
+	BatchStatusSkipped = "skipped"
+
+	// BatchStatusFailed is the status of a pull request which could not be
+	// merged for any other reason.
+	BatchStatusFailed = "failed"
+)
+
+// BatchResult is the outcome of merging a single pull request of a batch.
+type BatchResult struct {
+	PullRequest int    `json:"pull_request"`
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// batchColumns are the columns of the summary of a batch.
+var batchColumns = []string{"PR", "TITLE", "STATUS", "REASON"}
+
+// parseBatchArgs returns the organization and repository if the argument is
+// of the form ORG/REPO, i.e. without the ID of a pull request.
+func parseBatchArgs(arg string) (string, string, bool) {
+	org, repo, found := strings.Cut(arg, "/")
+	if !found || org == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", false
+	}
+
+	return org, repo, true
+}
+
+// selectBatch returns the pull requests which carry all of the labels and none
+// of the ignored labels in ascending order of their number.
+func selectBatch(prs []*github.PullRequest, labels, ignoreLabels []string) []*github.PullRequest {
+	var selected []*github.PullRequest
+
+	for _, pr := range prs {
+		var names []string
+		for _, label := range pr.Labels {
+			names = append(names, label.GetName())
+		}
+
+		ok := true
+		for _, label := range labels {
+			if !containsString(names, label) {
+				ok = false
+				break
+			}
+		}
+
+		for _, label := range ignoreLabels {
+			if containsString(names, label) {
+				ok = false
+				break
+			}
+		}
+
+		if ok {
+			selected = append(selected, pr)
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].GetNumber() < selected[j].GetNumber()
+	})
+
+	return selected
+}
+
+// runBatch merges every open pull request of the repository which carries the
+// gate labels, one after the other.  A pull request which cannot be merged is
+// reported in the summary without stopping the batch.
+func (opts *Merge) runBatch(ctx context.Context, ghOrg, ghRepo string) error {
+	if opts.Repo != "" {
+		return fmt.Errorf("--repo cannot be used when merging every pull request of a repository")
+	}
+
+	if opts.FromResult != "" {
+		return fmt.Errorf("--from-result cannot be used when merging every pull request of a repository")
+	}
+
+	labels, ignoreLabels := opts.gateLabels()
+	if len(labels) == 0 {
+		return fmt.Errorf("merging every pull request of a repository requires --labels or --merge-label")
+	}
+
+	ghClient, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	prs, err := ghClient.ListOpenPullRequests(ctx, ghOrg, ghRepo)
+	if err != nil {
+		return fmt.Errorf("could not list pull requests: %w", err)
+	}
+
+	prs = selectBatch(prs, labels, ignoreLabels)
+
+	log.G(ctx).
+		WithField("repo", fmt.Sprintf("%s/%s", ghOrg, ghRepo)).
+		WithField("labels", labels).
+		WithField("pull_requests", len(prs)).
+		Info("merging pull requests")
+
+	results := opts.mergeEach(ctx, prs, func(pr *Merge, prId int) error {
+		return pr.merge(ctx, ghClient, ghOrg, ghRepo, prId)
+	})
+
+	// The plans are written to stdout in JSON, which the summary must not
+	// interleave with.
+	out := iostreams.G(ctx).Out
+	if opts.Output == cmdutils.PlanOutputJSON {
+		out = iostreams.G(ctx).ErrOut
+	}
+
+	if err := renderBatch(ctx, out, results); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Status == BatchStatusFailed {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("could not merge %d of %d pull requests", failed, len(results))
+	}
+
+	return nil
+}
+
+// mergeEach merges the pull requests in order with the provided function and
+// returns the outcome of each.  Every pull request is merged with its own copy
+// of the options, since merging amends the trailers and the local repository.
+func (opts *Merge) mergeEach(ctx context.Context, prs []*github.PullRequest, merge func(*Merge, int) error) []*BatchResult {
+	results := make([]*BatchResult, 0, len(prs))
+
+	for _, pr := range prs {
+		prOpts := *opts
+		prOpts.Trailers = append([]string(nil), opts.Trailers...)
+
+		result := &BatchResult{
+			PullRequest: pr.GetNumber(),
+			Title:       pr.GetTitle(),
+			Status:      BatchStatusMerged,
+		}
+
+		if err := merge(&prOpts, pr.GetNumber()); errors.Is(err, errNotMergable) {
+			result.Status, result.Reason = BatchStatusSkipped, err.Error()
+		} else if err != nil {
+			result.Status, result.Reason = BatchStatusFailed, err.Error()
+		}
+
+		log.G(ctx).
+			WithField("pr_id", result.PullRequest).
+			WithField("status", result.Status).
+			WithField("reason", result.Reason).
+			Info("processed pull request")
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// renderBatch renders the outcome of each pull request of a batch as a table.
+func renderBatch(ctx context.Context, out io.Writer, results []*BatchResult) error {
+	topts := []tableprinter.TablePrinterOption{
+		tableprinter.WithOutputFormat(tableprinter.OutputFormatTable),
+	}
+
+	if kitcfg.G[config.Config](ctx).NoRender {
+		topts = append(topts, tableprinter.WithMaxWidth(10000))
+	} else {
+		topts = append(topts, tableprinter.WithMaxWidth(iostreams.G(ctx).TerminalWidth()))
+	}
+
+	table, err := tableprinter.NewTablePrinter(ctx, topts...)
+	if err != nil {
+		return err
+	}
+
+	cs := iostreams.G(ctx).ColorScheme()
+
+	table.AddHeader(cs.Bold, batchColumns...)
+
+	for _, result := range results {
+		var color func(string) string
+		switch result.Status {
+		case BatchStatusMerged:
+			color = cs.Green
+		case BatchStatusSkipped:
+			color = cs.Yellow
+		case BatchStatusFailed:
+			color = cs.Red
+		}
+
+		table.AddField("#"+strconv.Itoa(result.PullRequest), nil)
+		table.AddField(result.Title, nil)
+		table.AddField(result.Status, color)
+		table.AddField(result.Reason, nil)
+		table.EndRow()
+	}
+
+	return table.Render(out)
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package pr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/config"
+)
+
+func TestParseBatchArgs(t *testing.T) {
+	tests := []struct {
+		arg      string
+		wantOrg  string
+		wantRepo string
+		wantOk   bool
+	}{
+		{arg: "unikraft/unikraft", wantOrg: "unikraft", wantRepo: "unikraft", wantOk: true},
+		{arg: "unikraft/unikraft/1000"},
+		{arg: "https://github.com/unikraft/unikraft/pull/1000"},
+		{arg: "unikraft/"},
+		{arg: "/unikraft"},
+		{arg: "unikraft"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			org, repo, ok := parseBatchArgs(tt.arg)
+			if org != tt.wantOrg || repo != tt.wantRepo || ok != tt.wantOk {
+				t.Errorf("parseBatchArgs() = (%q, %q, %v), want (%q, %q, %v)", org, repo, ok, tt.wantOrg, tt.wantRepo, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestSelectBatch(t *testing.T) {
+	pr := func(number int, labels ...string) *github.PullRequest {
+		p := &github.PullRequest{Number: github.Int(number)}
+		for _, label := range labels {
+			p.Labels = append(p.Labels, &github.Label{Name: github.String(label)})
+		}
+		return p
+	}
+
+	prs := []*github.PullRequest{
+		pr(30, "merge"),
+		pr(10, "merge", "area/lib"),
+		pr(20),
+		pr(40, "merge", "ci/merged"),
+		pr(5, "area/lib", "merge"),
+	}
+
+	var got []int
+	for _, p := range selectBatch(prs, []string{"merge"}, []string{"ci/merged"}) {
+		got = append(got, p.GetNumber())
+	}
+
+	if want := []int{5, 10, 30}; !reflect.DeepEqual(got, want) {
+		t.Errorf("selectBatch() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeEach(t *testing.T) {
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+	prs := []*github.PullRequest{
+		{Number: github.Int(1), Title: github.String("lib/ukboot: Fix typo")},
+		{Number: github.Int(2), Title: github.String("lib/posix-time: Add clock_nanosleep")},
+		{Number: github.Int(3), Title: github.String("plat/kvm: Fix interrupt routing")},
+	}
+
+	opts := &Merge{Trailers: []string{"Reviewed-by: Alice <alice@unikraft.io>"}}
+
+	var merged []int
+	results := opts.mergeEach(ctx, prs, func(pr *Merge, prId int) error {
+		// Trailers added whilst merging one pull request must not leak into
+		// the next one.
+		if len(pr.Trailers) != 1 {
+			t.Errorf("merging #%d with trailers %v", prId, pr.Trailers)
+		}
+		pr.Trailers = append(pr.Trailers, fmt.Sprintf("GitHub-Closes: #%d", prId))
+
+		switch prId {
+		case 1:
+			return fmt.Errorf("could not clone repository: connection reset")
+		case 2:
+			return errNotMergable
+		}
+
+		merged = append(merged, prId)
+		return nil
+	})
+
+	want := []*BatchResult{
+		{PullRequest: 1, Title: "lib/ukboot: Fix typo", Status: BatchStatusFailed, Reason: "could not clone repository: connection reset"},
+		{PullRequest: 2, Title: "lib/posix-time: Add clock_nanosleep", Status: BatchStatusSkipped, Reason: "pull request is not mergable"},
+		{PullRequest: 3, Title: "plat/kvm: Fix interrupt routing", Status: BatchStatusMerged},
+	}
+
+	if !reflect.DeepEqual(results, want) {
+		for i := range results {
+			t.Errorf("results[%d] = %+v", i, results[i])
+		}
+		t.Fatalf("want %+v, %+v, %+v", want[0], want[1], want[2])
+	}
+
+	if !reflect.DeepEqual(merged, []int{3}) {
+		t.Errorf("merged = %v, want [3]", merged)
+	}
+
+	out := &bytes.Buffer{}
+	ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+	if err := renderBatch(ctx, out, results); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"#1", "failed", "#2", "skipped", "#3", "merged"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("summary does not contain %q:\n%s", line, out.String())
+		}
+	}
+}
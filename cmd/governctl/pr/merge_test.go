@@ -0,0 +1,565 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package pr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/google/go-github/v63/github"
+	kitcfg "kraftkit.sh/config"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/patch"
+)
+
+func TestMergeValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Merge
+		dryRun  bool
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			opts: Merge{MinApprovals: 1, MinReviews: 1},
+		},
+		{
+			name: "push",
+			opts: Merge{Push: true, BaseBranch: "staging"},
+		},
+		{
+			name:    "push without base",
+			opts:    Merge{Push: true},
+			wantErr: true,
+		},
+		{
+			name:    "push with dry-run",
+			opts:    Merge{Push: true, BaseBranch: "staging"},
+			dryRun:  true,
+			wantErr: true,
+		},
+		{
+			name:    "committer-global without committer",
+			opts:    Merge{CommitterGlobal: true},
+			wantErr: true,
+		},
+		{
+			name: "committer-global with committer",
+			opts: Merge{CommitterGlobal: true, CommitterName: "Unikraft Bot"},
+		},
+		{
+			name:    "negative min-approvals",
+			opts:    Merge{MinApprovals: -1},
+			wantErr: true,
+		},
+		{
+			name:    "same merge and merged label",
+			opts:    Merge{MergeLabel: "ready", MergedLabel: "ready"},
+			wantErr: true,
+		},
+		{
+			name:    "ignored merge label",
+			opts:    Merge{MergeLabel: "ready", IgnoreLabels: []string{"wip", "ready"}},
+			wantErr: true,
+		},
+		{
+			name: "disabled labels",
+			opts: Merge{IgnoreLabels: []string{"wip"}},
+		},
+		{
+			name:    "blocking on and ignoring requested changes",
+			opts:    Merge{BlockOnChangesRequested: true, IgnoreChangesRequested: true},
+			wantErr: true,
+		},
+		{
+			name:    "allow-protected without push",
+			opts:    Merge{AllowProtected: true},
+			wantErr: true,
+		},
+		{
+			name:    "invalid protected branch pattern",
+			opts:    Merge{ProtectedBranches: []string{"release/["}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{DryRun: tt.dryRun})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+			if err := tt.opts.Validate(ctx); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergeConfirmPush(t *testing.T) {
+	tests := []struct {
+		name         string
+		base         string
+		allow        bool
+		canPrompt    bool
+		answer       bool
+		wantOverride string
+		wantPrompt   bool
+		wantErr      bool
+	}{
+		{
+			name: "unprotected branch",
+			base: "staging",
+		},
+		{
+			name:    "protected branch without terminal",
+			base:    "stable",
+			wantErr: true,
+		},
+		{
+			name:         "protected branch allowed",
+			base:         "release/0.15",
+			allow:        true,
+			wantOverride: "--allow-protected",
+		},
+		{
+			name:         "protected branch confirmed",
+			base:         "release/0.15",
+			canPrompt:    true,
+			answer:       true,
+			wantOverride: "interactive confirmation",
+			wantPrompt:   true,
+		},
+		{
+			name:       "protected branch declined",
+			base:       "stable",
+			canPrompt:  true,
+			wantPrompt: true,
+			wantErr:    true,
+		},
+		{
+			name: "nested branch not matched",
+			base: "release/0.15/fix",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Merge{
+				AllowProtected:    tt.allow,
+				BaseBranch:        tt.base,
+				ProtectedBranches: []string{"stable", "release/*"},
+			}
+
+			prompted := false
+			override, err := opts.confirmPush("unikraft", "unikraft", 3, tt.canPrompt, func(question string) (bool, error) {
+				prompted = true
+
+				if want := "Push 3 commits to the protected branch " + tt.base + " of unikraft/unikraft?"; question != want {
+					t.Errorf("question = %q, want %q", question, want)
+				}
+
+				return tt.answer, nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("confirmPush() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if override != tt.wantOverride {
+				t.Errorf("confirmPush() = %q, want %q", override, tt.wantOverride)
+			}
+
+			if prompted != tt.wantPrompt {
+				t.Errorf("prompted = %v, want %v", prompted, tt.wantPrompt)
+			}
+		})
+	}
+}
+
+func TestEnsureFastForward(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	git := func(dir string, args ...string) {
+		t.Helper()
+
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	clone := func(remote string) string {
+		dir := t.TempDir()
+		git(dir, "clone", "-q", "-o", "patched", remote, ".")
+		git(dir, "config", "user.name", "Unikraft Bot")
+		git(dir, "config", "user.email", "monkey@unikraft.io")
+		return dir
+	}
+
+	commit := func(dir, file string) {
+		writeFile(t, filepath.Join(dir, file), file+"\n")
+		git(dir, "add", ".")
+		git(dir, "commit", "-q", "-m", "Add "+file)
+	}
+
+	remote := t.TempDir()
+	git(remote, "init", "-q", "--bare", "-b", "stable")
+
+	upstream := clone(remote)
+	git(upstream, "checkout", "-q", "-b", "stable")
+	commit(upstream, "a.txt")
+	git(upstream, "push", "-q", "patched", "stable")
+
+	local := clone(remote)
+	commit(local, "b.txt")
+
+	ctx := context.Background()
+
+	if err := ensureFastForward(ctx, "git", local, "patched", "stable"); err != nil {
+		t.Fatalf("ensureFastForward() error = %v", err)
+	}
+
+	commit(upstream, "c.txt")
+	git(upstream, "push", "-q", "patched", "stable")
+
+	err := ensureFastForward(ctx, "git", local, "patched", "stable")
+	if err == nil || !strings.Contains(err.Error(), "not a fast-forward") {
+		t.Errorf("ensureFastForward() error = %v, want not a fast-forward", err)
+	}
+}
+
+func TestMergeLabels(t *testing.T) {
+	tests := []struct {
+		name             string
+		opts             Merge
+		wantLabels       []string
+		wantIgnoreLabels []string
+		wantAdd          []string
+		wantRemove       []string
+	}{
+		{
+			name:             "defaults",
+			opts:             Merge{MergeLabel: "merge", MergedLabel: "ci/merged"},
+			wantLabels:       []string{"merge"},
+			wantIgnoreLabels: []string{"ci/merged"},
+			wantRemove:       []string{"merge"},
+			wantAdd:          []string{"ci/merged"},
+		},
+		{
+			name: "custom labels",
+			opts: Merge{
+				Labels:       []string{"ci/tested"},
+				IgnoreLabels: []string{"wip"},
+				MergeLabel:   "ready-to-merge",
+				MergedLabel:  "status/merged",
+			},
+			wantLabels:       []string{"ci/tested", "ready-to-merge"},
+			wantIgnoreLabels: []string{"wip", "status/merged"},
+			wantRemove:       []string{"ready-to-merge"},
+			wantAdd:          []string{"status/merged"},
+		},
+		{
+			name:             "merge label already requested",
+			opts:             Merge{Labels: []string{"merge"}, MergeLabel: "merge", MergedLabel: "ci/merged"},
+			wantLabels:       []string{"merge"},
+			wantIgnoreLabels: []string{"ci/merged"},
+			wantRemove:       []string{"merge"},
+			wantAdd:          []string{"ci/merged"},
+		},
+		{
+			name:             "only merged label",
+			opts:             Merge{MergedLabel: "ci/merged"},
+			wantAdd:          []string{"ci/merged"},
+			wantIgnoreLabels: []string{"ci/merged"},
+		},
+		{
+			name: "disabled",
+			opts: Merge{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, ignoreLabels := tt.opts.gateLabels()
+			if !reflect.DeepEqual(labels, tt.wantLabels) {
+				t.Errorf("gateLabels() labels = %v, want %v", labels, tt.wantLabels)
+			}
+
+			if !reflect.DeepEqual(ignoreLabels, tt.wantIgnoreLabels) {
+				t.Errorf("gateLabels() ignoreLabels = %v, want %v", ignoreLabels, tt.wantIgnoreLabels)
+			}
+
+			add, remove := tt.opts.transitionLabels()
+			if strings.Join(add, ",") != strings.Join(tt.wantAdd, ",") {
+				t.Errorf("transitionLabels() add = %v, want %v", add, tt.wantAdd)
+			}
+
+			if strings.Join(remove, ",") != strings.Join(tt.wantRemove, ",") {
+				t.Errorf("transitionLabels() remove = %v, want %v", remove, tt.wantRemove)
+			}
+		})
+	}
+}
+
+func TestRelabel(t *testing.T) {
+	var requests []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/api/v3/repos/unikraft/unikraft"))
+
+		if r.URL.Path == "/api/v3/repos/unikraft/unikraft/pulls/42" {
+			fmt.Fprint(w, `{"number":42,"labels":[{"name":"merge"},{"name":"kind/bug"}]}`)
+			return
+		}
+
+		fmt.Fprint(w, `[]`)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := ghapi.NewGithubClient(context.Background(), "", false, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := relabel(context.Background(), client, "unikraft", "unikraft", 42, []string{"ci/merged"}, []string{"merge", "ci/tested"}); err != nil {
+		t.Fatalf("relabel() unexpected error: %v", err)
+	}
+
+	want := []string{
+		"GET /pulls/42",
+		"DELETE /issues/42/labels/merge",
+		"POST /issues/42/labels",
+	}
+
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
+
+// commitBody returns the raw message of the commit at HEAD of the repository,
+// i.e. everything following the headers of the commit object.
+func commitBody(t *testing.T, dir string) string {
+	t.Helper()
+
+	out, err := exec.Command("git", "-C", dir, "cat-file", "commit", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, body, _ := strings.Cut(string(out), "\n\n")
+
+	return body
+}
+
+func TestApplyPatchVerbatim(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	fixture, err := os.ReadFile(filepath.Join("testdata", "dependabot.msg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		verbatim bool
+		want     string
+	}{
+		{
+			name:     "verbatim",
+			verbatim: true,
+			want:     string(fixture) + "GitHub-Closes: #42\n",
+		},
+		{
+			name:     "rewritten",
+			verbatim: false,
+			want:     strings.Replace(string(fixture), "\n---\n", "\n...\n", 1) + "GitHub-Closes: #42\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			run := func(stdin []byte, args ...string) {
+				cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+				cmd.Stdin = bytes.NewReader(stdin)
+				cmd.Env = append(os.Environ(),
+					"GIT_AUTHOR_NAME=dependabot[bot]",
+					"GIT_AUTHOR_EMAIL=support@github.com",
+					"GIT_COMMITTER_NAME=Unikraft Bot",
+					"GIT_COMMITTER_EMAIL=monkey@unikraft.io",
+				)
+				if out, err := cmd.CombinedOutput(); err != nil {
+					t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+				}
+			}
+
+			run(nil, "init", "-q")
+			run(nil, "config", "user.name", "Unikraft Bot")
+			run(nil, "config", "user.email", "monkey@unikraft.io")
+
+			writeFile(t, filepath.Join(dir, ".github", "workflows", "ci.yaml"), "uses: actions/checkout@v3\n")
+			run(nil, "add", ".")
+			run(nil, "commit", "-q", "-m", "Initial commit")
+
+			writeFile(t, filepath.Join(dir, ".github", "workflows", "ci.yaml"), "uses: actions/checkout@v4\n")
+			run(nil, "add", ".")
+			run(fixture, "commit", "-q", "--cleanup=verbatim", "--file=-")
+
+			if got := commitBody(t, dir); got != string(fixture) {
+				t.Fatalf("fixture not committed verbatim, got:\n%s", got)
+			}
+
+			repo, err := git.PlainOpen(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ref, err := repo.Head()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			head, err := repo.CommitObject(ref.Hash())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parent, err := head.Parent(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p, err := patch.NewPatchFromCommits(context.Background(), dir, head, parent)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p.Trailers = append(p.Trailers, "GitHub-Closes: #42")
+
+			run(nil, "reset", "-q", "--hard", "HEAD~1")
+
+			if err := applyPatch(context.Background(), "git", dir, p, tt.verbatim); err != nil {
+				t.Fatalf("applyPatch() error = %v", err)
+			}
+
+			if got := commitBody(t, dir); got != tt.want {
+				t.Errorf("commit message mismatch\ngot:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeFreeze(t *testing.T) {
+	reposDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(reposDir, "repos.yaml"), []byte(`---
+name: unikraft
+type: core
+freeze:
+  from: "2024-01-10"
+  until: "2024-01-31"
+  allowed_labels:
+  - release-blocker
+  message: Frozen for the release of v0.16.0.
+---
+name: lib-lwip
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{ReposDir: reposDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+	during := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	after := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		repo          string
+		now           time.Time
+		labels        []string
+		wantExemption string
+		wantErr       string
+	}{
+		{
+			name:    "frozen",
+			repo:    "unikraft",
+			now:     during,
+			labels:  []string{"kind/enhancement"},
+			wantErr: "repository is frozen: Frozen for the release of v0.16.0.",
+		},
+		{
+			name:          "label-exempted",
+			repo:          "unikraft",
+			now:           during,
+			labels:        []string{"kind/bug", "release-blocker"},
+			wantExemption: "release-blocker",
+		},
+		{
+			name:   "freeze ended",
+			repo:   "unikraft",
+			now:    after,
+			labels: []string{"kind/enhancement"},
+		},
+		{
+			name: "unfrozen",
+			repo: "lib-lwip",
+			now:  during,
+		},
+		{
+			name: "undefined",
+			repo: "app-helloworld",
+			now:  during,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			freeze, err := repoFreeze(ctx, "unikraft", tt.repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pr := &github.PullRequest{}
+			for _, label := range tt.labels {
+				pr.Labels = append(pr.Labels, &github.Label{Name: github.String(label)})
+			}
+
+			exemption, err := checkFreeze(freeze, tt.now, pr)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("checkFreeze() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("checkFreeze() unexpected error: %v", err)
+			}
+
+			if exemption != tt.wantExemption {
+				t.Errorf("checkFreeze() = %q, want %q", exemption, tt.wantExemption)
+			}
+		})
+	}
+}
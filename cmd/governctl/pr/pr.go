@@ -14,12 +14,13 @@ import (
 
 	"github.com/unikraft/governance/cmd/governctl/pr/check"
 	"github.com/unikraft/governance/cmd/governctl/pr/sync"
+	"github.com/unikraft/governance/internal/cmdutils"
 )
 
 type PR struct{}
 
 func New() *cobra.Command {
-	cmd, err := cmdfactory.New(&PR{}, cobra.Command{
+	cmd, err := cmdutils.New(&PR{}, cobra.Command{
 		Use:    "pr SUBCOMMAND",
 		Short:  "Manage pull requests",
 		Hidden: true,
@@ -32,7 +33,10 @@ func New() *cobra.Command {
 	}
 	cmd.AddCommand(sync.New())
 	cmd.AddCommand(check.New())
+	cmd.AddCommand(NewDiffStat())
 	cmd.AddCommand(NewMerge())
+	cmd.AddCommand(NewRevert())
+	cmd.AddCommand(NewTriage())
 
 	return cmd
 }
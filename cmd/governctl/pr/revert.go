@@ -0,0 +1,433 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package pr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/cmd/governctl/pr/sync"
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/patch"
+)
+
+type Revert struct {
+	BaseBranch     string `long:"base" env:"GOVERN_BASE" usage:"Set the branch which the pull request was merged into (default: the base of the pull request)"`
+	Branch         string `long:"branch" env:"GOVERN_BRANCH" usage:"Set the name of the branch of the revert (default: revert-PRID)"`
+	Closes         int    `long:"closes" env:"GOVERN_CLOSES" usage:"Number of the issue which reports the breakage and is closed by the revert"`
+	CommitterEmail string `long:"committer-email" short:"e" env:"GOVERN_COMMITTER_EMAIL" usage:"Set the Git committer author's email"`
+	CommitterName  string `long:"committer-name" short:"n" env:"GOVERN_COMMITTER_NAME" usage:"Set the Git committer author's name"`
+	NoReviewers    bool   `long:"no-reviewers" env:"GOVERN_NO_REVIEWERS" usage:"Do not assign maintainers and reviewers to the revert"`
+	NumMaintainers int    `long:"num-maintainers" short:"A" usage:"Number of maintainers for the revert" default:"1"`
+	NumReviewers   int    `long:"num-reviewers" short:"R" usage:"Number of reviewers for the revert" default:"1"`
+	Output         string `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+	Repo           string `long:"repo" short:"p" env:"GOVERN_REPO" usage:"Revert in the following local repository"`
+	SquashReverts  bool   `long:"squash-reverts" env:"GOVERN_SQUASH_REVERTS" usage:"Revert every commit of the pull request in a single commit"`
+}
+
+// RevertPlan describes the commits which would be reverted and the pull
+// request which would be opened with the reverts.
+type RevertPlan struct {
+	Org         string   `json:"org"`
+	Repo        string   `json:"repo"`
+	PullRequest int      `json:"pull_request"`
+	Base        string   `json:"base"`
+	Branch      string   `json:"branch"`
+	Title       string   `json:"title"`
+	Commits     []string `json:"commits"`
+	Squash      bool     `json:"squash,omitempty"`
+	Closes      int      `json:"closes,omitempty"`
+}
+
+// mergedCommit is a commit on the base branch which originates from a merged
+// pull request.
+type mergedCommit struct {
+	SHA     string
+	Subject string
+}
+
+func (c mergedCommit) String() string {
+	sha := c.SHA
+	if len(sha) > 12 {
+		sha = sha[:12]
+	}
+
+	return fmt.Sprintf("%s %s", sha, c.Subject)
+}
+
+func NewRevert() *cobra.Command {
+	cmd, err := cmdutils.New(&Revert{}, cobra.Command{
+		Use:   "revert [OPTIONS] ORG/REPO/PRID",
+		Short: "Open a pull request which reverts a merged pull request",
+		Args:  cobra.MaximumNArgs(2),
+		Long: heredoc.Doc(`
+		Revert a merged pull request by reverting the commits which were merged
+		from it, newest first, on a new branch off the base branch and opening a
+		pull request with the reverts.  Since the merged commits differ from the
+		commits of the pull request, they are found by their GitHub-Closes
+		trailer.
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "pr",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+// Validate rejects unknown output formats of the plan, an incomplete committer
+// and negative numbers of issues, maintainers and reviewers.
+func (opts *Revert) Validate(ctx context.Context) error {
+	if err := cmdutils.ValidatePlanOutput(ctx, opts.Output); err != nil {
+		return err
+	}
+
+	if err := config.ValidateCommitter(opts.CommitterName, opts.CommitterEmail, false); err != nil {
+		return err
+	}
+
+	if err := config.NotNegative("closes", opts.Closes); err != nil {
+		return err
+	}
+
+	if err := config.NotNegative("num-maintainers", opts.NumMaintainers); err != nil {
+		return err
+	}
+
+	return config.NotNegative("num-reviewers", opts.NumReviewers)
+}
+
+func (opts *Revert) Run(ctx context.Context, args []string) error {
+	if kitcfg.G[config.Config](ctx).ReadOnly && !kitcfg.G[config.Config](ctx).DryRun {
+		return fmt.Errorf("cannot revert pull request: %w", ghapi.ErrReadOnly)
+	}
+
+	ghOrg, ghRepo, ghPrId, err := cmdutils.ParseOrgRepoAndPullRequestArgs(args)
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	pull, err := ghClient.GetPullRequest(ctx, ghOrg, ghRepo, ghPrId)
+	if err != nil {
+		return fmt.Errorf("could not get pull request: %w", err)
+	}
+
+	if !pull.GetMerged() {
+		return fmt.Errorf("pull request #%d has not been merged", ghPrId)
+	}
+
+	if opts.BaseBranch == "" {
+		opts.BaseBranch = pull.GetBase().GetRef()
+	}
+
+	if opts.Branch == "" {
+		opts.Branch = fmt.Sprintf("revert-%d", ghPrId)
+	}
+
+	tempDir, cleanup, err := sync.TempDir(ctx, "governctl-pr-revert-*")
+	if err != nil {
+		return err
+	}
+
+	defer cleanup()
+
+	local := opts.Repo != ""
+
+	if !local {
+		opts.Repo = filepath.Join(tempDir, fmt.Sprintf("%s-revert-%d", ghRepo, ghPrId))
+
+		log.G(ctx).
+			WithField("from", pull.GetBase().GetRepo().GetCloneURL()).
+			WithField("to", opts.Repo).
+			Info("cloning fresh repository")
+
+		if _, err := git.PlainClone(opts.Repo, false, &git.CloneOptions{
+			URL: pull.GetBase().GetRepo().GetCloneURL(),
+			Auth: &http.BasicAuth{
+				Username: kitcfg.G[config.Config](ctx).GithubUser,
+				Password: kitcfg.G[config.Config](ctx).GithubToken,
+			},
+			ReferenceName: plumbing.NewBranchReferenceName(opts.BaseBranch),
+		}); err != nil {
+			return fmt.Errorf("could not clone repository: %w", err)
+		}
+	}
+
+	gitBinary := kitcfg.G[config.Config](ctx).GitBinary
+	if gitBinary == "" {
+		gitBinary = patch.DefaultGitBinary
+	}
+
+	// The branch of the revert is based on the base branch of the freshly
+	// cloned repository or, if a local repository is provided, on the base
+	// branch fetched from its origin, since the local branch may be stale.
+	base := opts.BaseBranch
+	if local {
+		base, err = fetchBase(ctx, gitBinary, opts.Repo, opts.BaseBranch)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.CommitterName != "" {
+		if err := runGit(ctx, gitBinary, opts.Repo, "config", "user.name", opts.CommitterName); err != nil {
+			return fmt.Errorf("could not config user: %w", err)
+		}
+	}
+
+	if opts.CommitterEmail != "" {
+		if err := runGit(ctx, gitBinary, opts.Repo, "config", "user.email", opts.CommitterEmail); err != nil {
+			return fmt.Errorf("could not config email: %w", err)
+		}
+	}
+
+	commits, err := findMergedCommits(ctx, gitBinary, opts.Repo, base, ghPrId)
+	if err != nil {
+		return err
+	}
+
+	if len(commits) == 0 {
+		return fmt.Errorf("could not find any commits on %s with the trailer 'GitHub-Closes: #%d'", opts.BaseBranch, ghPrId)
+	}
+
+	if err := runGit(ctx, gitBinary, opts.Repo, "checkout", "-b", opts.Branch, base); err != nil {
+		return fmt.Errorf("could not create branch %s: %w", opts.Branch, err)
+	}
+
+	title := fmt.Sprintf("Revert \"%s\"", pull.GetTitle())
+	body := revertBody(ghPrId, commits, opts.Closes)
+
+	if err := revertCommits(ctx, gitBinary, opts.Repo, commits, opts.SquashReverts, title+"\n\n"+body); err != nil {
+		return err
+	}
+
+	plan := &RevertPlan{
+		Org:         ghOrg,
+		Repo:        ghRepo,
+		PullRequest: ghPrId,
+		Base:        opts.BaseBranch,
+		Branch:      opts.Branch,
+		Title:       title,
+		Commits:     make([]string, 0, len(commits)),
+		Squash:      opts.SquashReverts,
+		Closes:      opts.Closes,
+	}
+
+	for _, commit := range commits {
+		plan.Commits = append(plan.Commits, commit.String())
+	}
+
+	if kitcfg.G[config.Config](ctx).DryRun {
+		if opts.Output != cmdutils.PlanOutputJSON {
+			writeRevertPlan(iostreams.G(ctx).Out, plan)
+		}
+
+		return cmdutils.WritePlan(ctx, opts.Output, plan)
+	}
+
+	if err := runGit(ctx, gitBinary, opts.Repo, "push", fmt.Sprintf("https://%s:%s@github.com/%s/%s.git",
+		kitcfg.G[config.Config](ctx).GithubUser,
+		kitcfg.G[config.Config](ctx).GithubToken,
+		ghOrg,
+		ghRepo,
+	), opts.Branch); err != nil {
+		return fmt.Errorf("could not push branch %s: %w", opts.Branch, err)
+	}
+
+	revert, err := ghClient.CreatePullRequest(ctx, ghOrg, ghRepo, opts.Branch, opts.BaseBranch, title, body)
+	if err != nil {
+		return fmt.Errorf("could not open pull request: %w", err)
+	}
+
+	log.G(ctx).
+		WithField("url", revert.GetHTMLURL()).
+		Info("opened revert")
+
+	if opts.NoReviewers {
+		return nil
+	}
+
+	// The revert touches the same files as the original pull request and is
+	// therefore owned by the same teams.
+	files, err := sync.ChangedFiles(ctx, ghClient, ghOrg, ghRepo, pull, tempDir)
+	if err != nil {
+		return fmt.Errorf("could not assign reviewers to %s: %w", revert.GetHTMLURL(), err)
+	}
+
+	reviewers := &sync.Reviewers{
+		NumMaintainers: opts.NumMaintainers,
+		NumReviewers:   opts.NumReviewers,
+	}
+
+	if _, err := reviewers.Apply(ctx, ghClient, nil, ghOrg, ghRepo, revert, opts.Repo, files); err != nil {
+		return fmt.Errorf("could not assign reviewers to %s: %w", revert.GetHTMLURL(), err)
+	}
+
+	return nil
+}
+
+// runGit runs git with the provided arguments in the repository.
+func runGit(ctx context.Context, gitBinary, repo string, args ...string) error {
+	cmd := exec.Command(gitBinary, append([]string{"-C", repo}, args...)...)
+	cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
+	cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
+	return cmd.Run()
+}
+
+// fetchBase fetches the branch from the origin of the repository into its
+// remote-tracking branch and returns the name of the latter.
+func fetchBase(ctx context.Context, gitBinary, repo, branch string) (string, error) {
+	ref := "refs/remotes/origin/" + branch
+
+	log.G(ctx).
+		WithField("branch", branch).
+		Info("fetching base branch")
+
+	if err := runGit(ctx, gitBinary, repo, "fetch", "origin", fmt.Sprintf("+refs/heads/%s:%s", branch, ref)); err != nil {
+		return "", fmt.Errorf("could not fetch %s from origin: %w", branch, err)
+	}
+
+	return ref, nil
+}
+
+// findMergedCommits returns the commits of the ref which were merged from the
+// pull request, newest first, as identified by their GitHub-Closes trailer.
+func findMergedCommits(ctx context.Context, gitBinary, repo, ref string, prId int) ([]mergedCommit, error) {
+	cmd := exec.Command(gitBinary, "-C", repo,
+		"log",
+		"--format=%H%x00%s",
+		"--extended-regexp",
+		fmt.Sprintf("--grep=^GitHub-Closes: #%d$", prId),
+		ref,
+	)
+	cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not search commits of pull request: %w", err)
+	}
+
+	var commits []mergedCommit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		sha, subject, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+
+		commits = append(commits, mergedCommit{
+			SHA:     sha,
+			Subject: subject,
+		})
+	}
+
+	return commits, nil
+}
+
+// revertCommits reverts the commits in the provided order onto the checked
+// out branch of the repository, either as one revert commit per commit or, if
+// squash is set, as a single commit with the provided message.  A conflicting
+// revert is aborted and reported with the offending commit and files.
+func revertCommits(ctx context.Context, gitBinary, repo string, commits []mergedCommit, squash bool, message string) error {
+	for _, commit := range commits {
+		log.G(ctx).
+			WithField("commit", commit.String()).
+			Info("reverting")
+
+		args := []string{"revert", "--no-edit", "--signoff"}
+		if squash {
+			args = append(args, "--no-commit")
+		}
+
+		if err := runGit(ctx, gitBinary, repo, append(args, commit.SHA)...); err != nil {
+			cmd := exec.Command(gitBinary, "-C", repo, "diff", "--name-only", "--diff-filter=U")
+			conflicts, _ := cmd.Output()
+
+			_ = runGit(ctx, gitBinary, repo, "revert", "--abort")
+
+			if files := strings.Fields(string(conflicts)); len(files) > 0 {
+				return fmt.Errorf("could not revert %s: conflicts in %s", commit, strings.Join(files, ", "))
+			}
+
+			return fmt.Errorf("could not revert %s: %w", commit, err)
+		}
+	}
+
+	if !squash {
+		return nil
+	}
+
+	cmd := exec.Command(gitBinary, "-C", repo, "commit", "--signoff", "--file=-")
+	cmd.Stdin = bytes.NewReader([]byte(message))
+	cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
+	cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("could not commit reverts: %w", err)
+	}
+
+	return nil
+}
+
+// writeRevertPlan writes the plan in a human-readable form.
+func writeRevertPlan(w io.Writer, plan *RevertPlan) {
+	fmt.Fprintf(w, "%s/%s#%d: revert onto %s on branch %s\n", plan.Org, plan.Repo, plan.PullRequest, plan.Base, plan.Branch)
+	fmt.Fprintf(w, "  title: %s\n", plan.Title)
+
+	for _, commit := range plan.Commits {
+		fmt.Fprintf(w, "  revert %s\n", commit)
+	}
+
+	if plan.Squash {
+		fmt.Fprintln(w, "  squashed into a single commit")
+	}
+
+	if plan.Closes > 0 {
+		fmt.Fprintf(w, "  closes #%d\n", plan.Closes)
+	}
+}
+
+// revertBody returns the description of the pull request which reverts the
+// commits of the provided pull request and closes the provided issue, if any.
+func revertBody(prId int, commits []mergedCommit, closes int) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "This reverts the following commits which were merged from #%d:\n\n", prId)
+
+	for _, commit := range commits {
+		fmt.Fprintf(&b, "- %s\n", commit)
+	}
+
+	if closes > 0 {
+		fmt.Fprintf(&b, "\nCloses #%d\n", closes)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package pr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/config"
+)
+
+// newRevertRepo returns a repository whose main branch has two commits merged
+// from pull request #7 surrounded by unrelated commits, one of which closes
+// #70 to ensure that the trailer is matched exactly.
+func newRevertRepo(t *testing.T) (string, func(args ...string) string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+
+	git := func(args ...string) string {
+		t.Helper()
+
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+		}
+
+		return strings.TrimSpace(string(out))
+	}
+
+	commit := func(file, content, message string) {
+		writeFile(t, filepath.Join(dir, file), content)
+		git("add", ".")
+		git("commit", "-q", "-m", message)
+	}
+
+	git("init", "-q", "-b", "main")
+	git("config", "user.name", "Unikraft Bot")
+	git("config", "user.email", "monkey@unikraft.io")
+
+	commit("a.txt", "1\n", "Initial commit")
+	commit("a.txt", "2\n", "lib: Change a\n\nGitHub-Closes: #7")
+	commit("b.txt", "b\n", "lib: Add b\n\nGitHub-Closes: #7")
+	commit("c.txt", "c\n", "lib: Add c\n\nGitHub-Closes: #70")
+
+	return dir, git
+}
+
+func TestFindMergedCommits(t *testing.T) {
+	dir, _ := newRevertRepo(t)
+
+	commits, err := findMergedCommits(context.Background(), "git", dir, "main", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range commits {
+		got = append(got, c.Subject)
+	}
+
+	if want := "lib: Add b,lib: Change a"; strings.Join(got, ",") != want {
+		t.Errorf("findMergedCommits() = %v, want %s", got, want)
+	}
+}
+
+func TestRevertCommits(t *testing.T) {
+	tests := []struct {
+		name        string
+		squash      bool
+		conflict    bool
+		wantErr     string
+		wantCommits int
+	}{
+		{
+			name:        "one revert per commit",
+			wantCommits: 2,
+		},
+		{
+			name:        "squashed",
+			squash:      true,
+			wantCommits: 1,
+		},
+		{
+			name:     "conflict",
+			conflict: true,
+			wantErr:  "lib: Change a: conflicts in a.txt",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, git := newRevertRepo(t)
+
+			if tt.conflict {
+				writeFile(t, filepath.Join(dir, "a.txt"), "3\n")
+				git("commit", "-q", "-am", "lib: Change a again")
+			}
+
+			ctx := context.Background()
+
+			commits, err := findMergedCommits(ctx, "git", dir, "main", 7)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			head := git("rev-parse", "HEAD")
+			git("checkout", "-q", "-b", "revert-7")
+
+			err = revertCommits(ctx, "git", dir, commits, tt.squash, "Revert \"lib: Changes\"\n\nbody\n")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("revertCommits() error = %v, want %q", err, tt.wantErr)
+				}
+
+				if status := git("status", "--porcelain"); status != "" {
+					t.Errorf("expected the revert to be aborted, got:\n%s", status)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("revertCommits() error = %v", err)
+			}
+
+			if got := strings.Count(git("log", "--format=%H", head+"..HEAD"), "\n") + 1; got != tt.wantCommits {
+				t.Errorf("got %d commits, want %d", got, tt.wantCommits)
+			}
+
+			if tt.squash && git("log", "-1", "--format=%s") != "Revert \"lib: Changes\"" {
+				t.Errorf("unexpected message of squashed revert: %s", git("log", "-1", "--format=%B"))
+			}
+
+			if !strings.Contains(git("log", "-1", "--format=%B"), "Signed-off-by: Unikraft Bot <monkey@unikraft.io>") {
+				t.Errorf("expected the revert to be signed off")
+			}
+
+			a, err := os.ReadFile(filepath.Join(dir, "a.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(a) != "1\n" {
+				t.Errorf("a.txt = %q, want %q", a, "1\n")
+			}
+
+			if _, err := os.Stat(filepath.Join(dir, "b.txt")); !os.IsNotExist(err) {
+				t.Errorf("expected b.txt to be removed")
+			}
+
+			if _, err := os.Stat(filepath.Join(dir, "c.txt")); err != nil {
+				t.Errorf("expected c.txt to be kept: %v", err)
+			}
+		})
+	}
+}
+
+// TestRevertDryRun checks that a read-only token can preview a revert and
+// that the commits are found on the base branch of the origin of a provided
+// local repository, even if its own base branch is stale.
+func TestRevertDryRun(t *testing.T) {
+	origin, git := newRevertRepo(t)
+
+	local := filepath.Join(t.TempDir(), "local")
+	if out, err := exec.Command("git", "clone", "-q", origin, local).CombinedOutput(); err != nil {
+		t.Fatalf("git clone: %v: %s", err, out)
+	}
+
+	stale, err := exec.Command("git", "-C", local, "rev-parse", "main").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A further commit of the pull request which only exists on the origin.
+	writeFile(t, filepath.Join(origin, "d.txt"), "d\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "lib: Add d\n\nGitHub-Closes: #7")
+
+	t.Setenv("GITHUB_ACTIONS", "")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected write %s %s", r.Method, r.URL.Path)
+			return
+		}
+
+		if r.URL.Path == "/api/v3/repos/unikraft/app-test/pulls/7" {
+			fmt.Fprint(w, `{"number":7,"merged":true,"title":"lib: Changes","base":{"ref":"main"}}`)
+			return
+		}
+
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{
+		DryRun:         true,
+		ReadOnly:       true,
+		GithubEndpoint: srv.URL,
+		TempDir:        t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+	ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+	opts := &Revert{
+		CommitterName:  "Unikraft Bot",
+		CommitterEmail: "monkey@unikraft.io",
+		Output:         "text",
+		Repo:           local,
+	}
+
+	if err := opts.Run(ctx, []string{"unikraft/app-test/7"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"unikraft/app-test#7: revert onto main on branch revert-7",
+		`title: Revert "lib: Changes"`,
+		"lib: Add d",
+		"lib: Add b",
+		"lib: Change a",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the plan to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	if strings.Contains(out.String(), "lib: Add c") {
+		t.Errorf("expected the plan not to contain commits of other pull requests, got:\n%s", out)
+	}
+
+	if now, err := exec.Command("git", "-C", local, "rev-parse", "main").Output(); err != nil || !bytes.Equal(now, stale) {
+		t.Errorf("expected the local main branch to be left alone, got %s (%v)", now, err)
+	}
+}
+
+func TestRevertBody(t *testing.T) {
+	commits := []mergedCommit{
+		{SHA: "0123456789abcdef", Subject: "lib: Add b"},
+		{SHA: "fedcba9876543210", Subject: "lib: Change a"},
+	}
+
+	want := `This reverts the following commits which were merged from #7:
+
+- 0123456789ab lib: Add b
+- fedcba987654 lib: Change a
+
+Closes #12
+`
+
+	if got := revertBody(7, commits, 12); got != want {
+		t.Errorf("revertBody() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/google/go-github/v63/github"
+	"github.com/waigani/diffparser"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/utils"
+)
+
+// ErrPullRequestTooLarge is returned when the complete list of files changed
+// by a pull request cannot be determined.
+var ErrPullRequestTooLarge = errors.New("pull request too large to evaluate, apply labels and reviewers manually")
+
+// ExitCodePullRequestTooLarge is the exit code used when a pull request is too
+// large to be evaluated, to distinguish it from other failures.
+const ExitCodePullRequestTooLarge = 3
+
+// PullRequestTooLargeError wraps ErrPullRequestTooLarge with the reason why
+// the files of the pull request could not be determined.  It carries
+// ExitCodePullRequestTooLarge as the exit code of the program.
+type PullRequestTooLargeError struct {
+	Reason string
+}
+
+func (e *PullRequestTooLargeError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrPullRequestTooLarge, e.Reason)
+}
+
+func (e *PullRequestTooLargeError) Unwrap() error {
+	return ErrPullRequestTooLarge
+}
+
+// ExitCode implements cmdutils.ExitCoder.
+func (e *PullRequestTooLargeError) ExitCode() int {
+	return ExitCodePullRequestTooLarge
+}
+
+// errDiffTruncated is returned when GitHub has cut the diff short.
+var errDiffTruncated = errors.New("diff is truncated")
+
+// diffTruncatedMarkers are the notices which GitHub serves in place of, or at
+// the end of, a diff which exceeds its limits.
+var diffTruncatedMarkers = []string{
+	"Sorry, the diff exceeded the maximum number of",
+	"Sorry, this diff is taking too long to generate",
+}
+
+// ChangedFiles returns the complete list of files changed by the pull request,
+// including the original names of renamed files.  The files are read from the
+// pull request's diff, which is saved in the temporary directory.
+func ChangedFiles(ctx context.Context, ghClient *ghapi.GithubClient, org, repo string, pr *github.PullRequest, tempDir string) ([]string, error) {
+	localDiffFile := path.Join(
+		tempDir,
+		fmt.Sprintf("%s-%d.diff", repo, pr.GetNumber()),
+	)
+
+	diff, err := loadDiff(ctx, pr, localDiffFile)
+	if err != nil {
+		// Very large diffs are truncated or refused by GitHub altogether, in
+		// which case the files API may still be able to provide the list of
+		// files.
+		log.G(ctx).
+			WithField("pr_id", pr.GetNumber()).
+			Warnf("could not retrieve diff: %s", err)
+	}
+
+	return resolveChangedFiles(ctx, ghClient, org, repo, pr, diff)
+}
+
+// loadDiff downloads the pull request's diff to the provided path, if it does
+// not already exist, and parses it.  It returns errDiffTruncated if the diff
+// carries one of GitHub's truncation notices.
+func loadDiff(ctx context.Context, pr *github.PullRequest, localDiffFile string) (*diffparser.Diff, error) {
+	if _, err := os.Stat(localDiffFile); os.IsNotExist(err) {
+		log.G(ctx).
+			WithField("from", pr.GetDiffURL()).
+			WithField("to", localDiffFile).
+			Infof("saving diff")
+
+		if err = utils.DownloadFile(localDiffFile, pr.GetDiffURL()); err != nil {
+			os.Remove(localDiffFile)
+			return nil, fmt.Errorf("could not download pull request diff: %w", err)
+		}
+	}
+
+	log.G(ctx).
+		WithField("file", localDiffFile).
+		Infof("reading diff")
+
+	d, err := os.ReadFile(localDiffFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read diff file: %w", err)
+	}
+
+	for _, marker := range diffTruncatedMarkers {
+		if bytes.Contains(d, []byte(marker)) {
+			return nil, errDiffTruncated
+		}
+	}
+
+	diff, err := diffparser.Parse(string(d))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse diff from pull request: %w", err)
+	}
+
+	return diff, nil
+}
+
+// resolveChangedFiles returns the files of the provided diff when it is
+// complete.  When the diff is missing or contains fewer files than the pull
+// request reports, the paginated files API is used instead.  If the files API
+// is unable to return every file either, ErrPullRequestTooLarge is returned
+// rather than proceeding with partial data.
+func resolveChangedFiles(ctx context.Context, ghClient *ghapi.GithubClient, org, repo string, pr *github.PullRequest, diff *diffparser.Diff) ([]string, error) {
+	expected := pr.GetChangedFiles()
+
+	if diff != nil && len(diff.Files) >= expected {
+		var files []string
+		for _, f := range diff.Files {
+			log.G(ctx).
+				WithField("file", f.NewName).
+				Info("checking diff")
+
+			if len(f.OrigName) > 0 {
+				files = append(files, f.OrigName)
+			}
+			if len(f.NewName) > 0 && f.NewName != f.OrigName {
+				files = append(files, f.NewName)
+			}
+		}
+
+		return files, nil
+	}
+
+	if diff != nil {
+		log.G(ctx).
+			WithField("pr_id", pr.GetNumber()).
+			WithField("parsed", len(diff.Files)).
+			WithField("changed", expected).
+			Warn("diff is truncated, listing files via the API instead")
+	}
+
+	if expected > ghapi.MaxPullRequestFiles {
+		return nil, &PullRequestTooLargeError{
+			Reason: fmt.Sprintf("%d files changed, at most %d can be listed", expected, ghapi.MaxPullRequestFiles),
+		}
+	}
+
+	list, err := ghClient.ListPullRequestFiles(ctx, org, repo, pr.GetNumber())
+	if err != nil {
+		return nil, fmt.Errorf("could not list pull request files: %w", err)
+	}
+
+	if len(list) < expected {
+		return nil, &PullRequestTooLargeError{
+			Reason: fmt.Sprintf("only %d of %d changed files could be listed", len(list), expected),
+		}
+	}
+
+	var files []string
+	for _, f := range list {
+		if f.GetPreviousFilename() != "" {
+			files = append(files, f.GetPreviousFilename())
+		}
+		if f.GetFilename() != "" {
+			files = append(files, f.GetFilename())
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+	"github.com/waigani/diffparser"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+// testDiff returns a parsed diff which modifies the provided files.
+func testDiff(t *testing.T, files ...string) *diffparser.Diff {
+	t.Helper()
+
+	var b strings.Builder
+	for _, f := range files {
+		fmt.Fprintf(&b, "diff --git a/%s b/%s\n", f, f)
+		fmt.Fprintf(&b, "index 0000001..0000002 100644\n")
+		fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", f, f)
+		fmt.Fprintf(&b, "@@ -1 +1 @@\n-old\n+new\n")
+	}
+
+	diff, err := diffparser.Parse(b.String())
+	if err != nil {
+		t.Fatalf("could not parse diff: %v", err)
+	}
+
+	return diff
+}
+
+func TestResolveChangedFiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		diff         []string
+		nilDiff      bool
+		changed      int
+		apiFiles     []string
+		want         []string
+		wantAPICalls bool
+		wantTooLarge bool
+	}{
+		{
+			name:    "complete diff",
+			diff:    []string{"lib/ukboot/boot.c", "lib/uklibparam/param.c"},
+			changed: 2,
+			want:    []string{"lib/ukboot/boot.c", "lib/uklibparam/param.c"},
+		},
+		{
+			name:         "truncated diff falls back to files api",
+			diff:         []string{"lib/ukboot/boot.c", "lib/uklibparam/param.c"},
+			changed:      4,
+			apiFiles:     []string{"lib/ukboot/boot.c", "lib/uklibparam/param.c", "vendor/a.c", "vendor/b.c"},
+			want:         []string{"lib/ukboot/boot.c", "lib/uklibparam/param.c", "vendor/a.c", "vendor/b.c"},
+			wantAPICalls: true,
+		},
+		{
+			name:         "missing diff falls back to files api",
+			nilDiff:      true,
+			changed:      1,
+			apiFiles:     []string{"vendor/a.c"},
+			want:         []string{"vendor/a.c"},
+			wantAPICalls: true,
+		},
+		{
+			name:         "files api incomplete",
+			diff:         []string{"lib/ukboot/boot.c"},
+			changed:      3,
+			apiFiles:     []string{"lib/ukboot/boot.c", "vendor/a.c"},
+			wantAPICalls: true,
+			wantTooLarge: true,
+		},
+		{
+			name:         "beyond files api limit",
+			diff:         []string{"lib/ukboot/boot.c"},
+			changed:      ghapi.MaxPullRequestFiles + 1,
+			wantTooLarge: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiCalls := 0
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/files", func(w http.ResponseWriter, r *http.Request) {
+				apiCalls++
+
+				var entries []string
+				for _, f := range tt.apiFiles {
+					entries = append(entries, fmt.Sprintf(`{"filename":%q}`, f))
+				}
+
+				fmt.Fprintf(w, "[%s]", strings.Join(entries, ","))
+			})
+
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			client, err := ghapi.NewGithubClient(context.Background(), "token", false, srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var diff *diffparser.Diff
+			if !tt.nilDiff {
+				diff = testDiff(t, tt.diff...)
+			}
+
+			pr := &github.PullRequest{
+				Number:       github.Int(1),
+				ChangedFiles: github.Int(tt.changed),
+			}
+
+			got, err := resolveChangedFiles(context.Background(), client, "unikraft", "unikraft", pr, diff)
+			if tt.wantTooLarge {
+				if !errors.Is(err, ErrPullRequestTooLarge) {
+					t.Fatalf("resolveChangedFiles() expected ErrPullRequestTooLarge, got: %v", err)
+				}
+				if code := cmdutils.ExitCode(err); code != ExitCodePullRequestTooLarge {
+					t.Errorf("cmdutils.ExitCode() = %d, want %d", code, ExitCodePullRequestTooLarge)
+				}
+			} else if err != nil {
+				t.Fatalf("resolveChangedFiles() unexpected error: %v", err)
+			}
+
+			if (apiCalls > 0) != tt.wantAPICalls {
+				t.Errorf("files api called %d times, want called: %v", apiCalls, tt.wantAPICalls)
+			}
+
+			if !tt.wantTooLarge && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveChangedFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDiffTruncated(t *testing.T) {
+	tests := []struct {
+		name          string
+		diff          string
+		wantTruncated bool
+	}{
+		{
+			name: "complete diff",
+			diff: "diff --git a/lib/ukboot/boot.c b/lib/ukboot/boot.c\n" +
+				"index 0000001..0000002 100644\n" +
+				"--- a/lib/ukboot/boot.c\n+++ b/lib/ukboot/boot.c\n" +
+				"@@ -1 +1 @@\n-old\n+new\n",
+		},
+		{
+			name: "refused diff",
+			diff: "Sorry, the diff exceeded the maximum number of files (300). " +
+				"Consider using 'List pull requests files' API or locally cloning the repository instead.\n",
+			wantTruncated: true,
+		},
+		{
+			name: "truncation notice after the diff",
+			diff: "diff --git a/lib/ukboot/boot.c b/lib/ukboot/boot.c\n" +
+				"index 0000001..0000002 100644\n" +
+				"--- a/lib/ukboot/boot.c\n+++ b/lib/ukboot/boot.c\n" +
+				"@@ -1 +1 @@\n-old\n+new\n" +
+				"Sorry, this diff is taking too long to generate.\n",
+			wantTruncated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			localDiffFile := filepath.Join(t.TempDir(), "unikraft-1.diff")
+			if err := os.WriteFile(localDiffFile, []byte(tt.diff), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			diff, err := loadDiff(context.Background(), &github.PullRequest{Number: github.Int(1)}, localDiffFile)
+			if tt.wantTruncated {
+				if !errors.Is(err, errDiffTruncated) {
+					t.Fatalf("loadDiff() expected errDiffTruncated, got: %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("loadDiff() unexpected error: %v", err)
+			}
+			if len(diff.Files) != 1 {
+				t.Errorf("loadDiff() parsed %d files, want 1", len(diff.Files))
+			}
+		})
+	}
+}
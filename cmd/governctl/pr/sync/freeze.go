@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/repo"
+)
+
+// noticeFreeze leaves the message of the freeze on the pull request if the
+// repository is frozen at the provided time.  The message is only left once
+// per freeze, which is identified by its bounds, such that a later freeze is
+// announced again.  It returns the message, which is only planned and not left
+// in dry-run mode, or an empty string if no comment is necessary.
+func noticeFreeze(ctx context.Context, state *ghapi.ActionState, ghOrg, ghRepo string, pr *github.PullRequest, freeze *repo.Freeze, now time.Time) (string, error) {
+	if !freeze.ActiveAt(now) {
+		return "", nil
+	}
+
+	key := ghapi.ActionKey("freeze",
+		actionTarget(ghOrg, ghRepo, pr.GetNumber()),
+		fmt.Sprintf("%t", freeze.Active),
+		freeze.From,
+		freeze.Until,
+	)
+
+	if state.Applied(key) {
+		log.G(ctx).
+			WithField("pr_id", pr.GetNumber()).
+			Info("freeze has already been announced")
+		return "", nil
+	}
+
+	log.G(ctx).
+		WithField("pr_id", pr.GetNumber()).
+		Info("announcing freeze of repository")
+
+	notice := freeze.Notice()
+
+	if kitcfg.G[config.Config](ctx).DryRun {
+		return notice, nil
+	}
+
+	return notice, state.CreateComment(ctx, key, notice)
+}
@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+	kitcfg "kraftkit.sh/config"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/repo"
+)
+
+func TestNoticeFreeze(t *testing.T) {
+	during := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	after := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	freeze := &repo.Freeze{
+		Until:         "2024-01-31",
+		AllowedLabels: []string{"release-blocker"},
+	}
+
+	announced := ghapi.CommentMarker(ghapi.ActionKey("freeze", "unikraft/app-test#1", "false", "", "2024-01-31"))
+
+	tests := []struct {
+		name     string
+		freeze   *repo.Freeze
+		now      time.Time
+		comments string
+		dryRun   bool
+		want     string
+		wantPost bool
+	}{
+		{
+			name:     "frozen",
+			freeze:   freeze,
+			now:      during,
+			comments: `[]`,
+			want:     "This repository is frozen until 2024-01-31 and only pull requests labelled release-blocker are merged.",
+			wantPost: true,
+		},
+		{
+			name:     "frozen in dry-run mode",
+			freeze:   freeze,
+			now:      during,
+			comments: `[]`,
+			dryRun:   true,
+			want:     "This repository is frozen until 2024-01-31 and only pull requests labelled release-blocker are merged.",
+		},
+		{
+			name:     "already announced",
+			freeze:   freeze,
+			now:      during,
+			comments: fmt.Sprintf(`[{"id":1,"user":{"login":"unikraft-bot"},"body":%q}]`, announced+"\nfrozen"),
+		},
+		{
+			name:     "freeze ended",
+			freeze:   freeze,
+			now:      after,
+			comments: `[]`,
+		},
+		{
+			name:     "unfrozen",
+			now:      during,
+			comments: `[]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var posted []string
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v3/repos/unikraft/app-test/issues/1/comments" {
+					http.NotFound(w, r)
+					return
+				}
+
+				if r.Method == http.MethodPost {
+					var comment github.IssueComment
+					if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
+						t.Fatal(err)
+					}
+
+					posted = append(posted, comment.GetBody())
+					w.WriteHeader(http.StatusCreated)
+					fmt.Fprint(w, `{}`)
+					return
+				}
+
+				fmt.Fprint(w, tt.comments)
+			}))
+			defer srv.Close()
+
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{
+				DryRun:         tt.dryRun,
+				GithubEndpoint: srv.URL,
+				GithubUser:     "unikraft-bot",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+			ghClient, err := ghapi.NewGithubClient(ctx, "token", false, srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			state, err := LoadState(ctx, ghClient, "unikraft", "app-test", 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pr := &github.PullRequest{Number: github.Int(1)}
+
+			got, err := noticeFreeze(ctx, state, "unikraft", "app-test", pr, tt.freeze, tt.now)
+			if err != nil {
+				t.Fatalf("noticeFreeze() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("noticeFreeze() = %q, want %q", got, tt.want)
+			}
+
+			if tt.wantPost {
+				if len(posted) != 1 || !strings.HasPrefix(posted[0], announced) || !strings.HasSuffix(posted[0], tt.want) {
+					t.Errorf("posted %q, want a single marked comment with %q", posted, tt.want)
+				}
+			} else if len(posted) > 0 {
+				t.Errorf("posted %q, want nothing", posted)
+			}
+		})
+	}
+}
+
+func TestReviewersFrozen(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/requested_reviewers"):
+			fmt.Fprint(w, `{"users":[],"teams":[]}`)
+		case strings.HasSuffix(r.URL.Path, "/pulls/1"):
+			fmt.Fprint(w, `{"number":1,"state":"open"}`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	defer srv.Close()
+
+	teamsDir := filepath.Join(t.TempDir(), "teams")
+	writeFile(t, filepath.Join(teamsDir, "maintainers-boot.yaml"), `
+name: maintainers-boot
+maintainers:
+  - github: alice
+reviewers:
+  - github: bob
+repos:
+  - name: app-test
+`)
+
+	reposDir := filepath.Join(t.TempDir(), "repos")
+	writeFile(t, filepath.Join(reposDir, "app-test.yaml"), `
+name: app-test
+freeze:
+  active: true
+  message: Frozen for the release.
+`)
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{
+		DryRun:         true,
+		GithubEndpoint: srv.URL,
+		ReposDir:       reposDir,
+		TeamsDir:       teamsDir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+	ghClient, err := ghapi.NewGithubClient(ctx, "token", false, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr := &github.PullRequest{
+		Number: github.Int(1),
+		State:  github.String("open"),
+		User:   &github.User{Login: github.String("author")},
+	}
+
+	opts := &Reviewers{NumMaintainers: 1, NumReviewers: 1}
+
+	plan, err := opts.Apply(ctx, ghClient, nil, "unikraft", "app-test", pr, t.TempDir(), []string{"lib/ukboot/boot.c"})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	// Pull requests of frozen repositories are still reviewed.
+	if len(plan.Maintainers) != 1 || len(plan.Reviewers) != 1 {
+		t.Errorf("plan = %+v, want a maintainer and a reviewer", plan)
+	}
+
+	if plan.Freeze != "Frozen for the release." {
+		t.Errorf("plan.Freeze = %q, want %q", plan.Freeze, "Frozen for the release.")
+	}
+}
@@ -7,14 +7,14 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
 	"path"
+	"time"
 
-	git "github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/MakeNowJust/heredoc"
+	"github.com/google/go-github/v63/github"
 	"github.com/spf13/cobra"
-	"github.com/waigani/diffparser"
 	"kraftkit.sh/cmdfactory"
 	kitcfg "kraftkit.sh/config"
 	"kraftkit.sh/log"
@@ -23,18 +23,40 @@ import (
 	"github.com/unikraft/governance/internal/config"
 	"github.com/unikraft/governance/internal/ghapi"
 	"github.com/unikraft/governance/internal/label"
-	"github.com/unikraft/governance/utils"
+	"github.com/unikraft/governance/internal/repo"
 )
 
 type Labels struct {
-	LabelsDir string `long:"labels-dir" usage:"Path to the labels definition directory." default:".github/labels"`
+	All             bool   `long:"all" usage:"Synchronise every open PR of the provided repository, or of every repository of the repos definition directory"`
+	RepoLabelsDir   string `long:"repo-labels-dir" env:"GOVERN_REPO_LABELS_DIR" usage:"Path to the labels definition directory within the repository." default:".github/labels"`
+	Output          string `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+	RemoveUnmatched bool   `long:"remove-unmatched" env:"GOVERN_REMOVE_UNMATCHED" usage:"Remove automatically applied labels which no longer match the pull request"`
 }
 
 func NewLabels() *cobra.Command {
-	cmd, err := cmdfactory.New(&Labels{}, cobra.Command{
-		Use:   "labels [OPTIONS] ORG/REPO/PRID",
+	cmd, err := cmdutils.New(&Labels{}, cobra.Command{
+		Use:   "labels [OPTIONS] ORG/REPO/PRID|--all [REPO]",
 		Short: "Synchronise a pull request's labels",
-		Args:  cobra.MaximumNArgs(2),
+		Long: heredoc.Doc(`
+		Synchronise a pull request's labels.
+
+		Labels with an apply_after or remove_after timer are only added or
+		removed once the timer has elapsed since the event it is anchored to.
+		Run the command with --all periodically to add and remove these labels
+		once they are due, even if the pull request has not changed since.
+		`),
+		Args: cobra.MaximumNArgs(2),
+		Example: heredoc.Doc(`
+		# Synchronise the labels of a single pull request
+		governctl pr sync labels unikraft/unikraft/1078
+
+		# Synchronise the labels of every open pull request of a repository
+		governctl pr sync labels --all unikraft
+
+		# Synchronise the labels of every open pull request of every repository
+		# of the repos definition directory
+		governctl pr sync labels --all --github-org=unikraft
+		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "pr",
 		},
@@ -46,22 +68,22 @@ func NewLabels() *cobra.Command {
 	return cmd
 }
 
-func (opts *Labels) Run(ctx context.Context, args []string) error {
-	var err error
+// Validate rejects unknown output formats of the plan.
+func (opts *Labels) Validate(ctx context.Context) error {
+	return cmdutils.ValidatePlanOutput(ctx, opts.Output)
+}
 
-	ghOrg, ghRepo, ghPrId, err := cmdutils.ParseOrgRepoAndPullRequestArgs(args)
+func (opts *Labels) Run(ctx context.Context, args []string) error {
+	ghClient, err := cmdutils.NewGithubClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	ghOrigin := fmt.Sprintf("https://github.com/%s/%s.git", ghOrg, ghRepo)
+	if opts.All {
+		return opts.runAll(ctx, ghClient, args)
+	}
 
-	ghClient, err := ghapi.NewGithubClient(
-		ctx,
-		kitcfg.G[config.Config](ctx).GithubToken,
-		kitcfg.G[config.Config](ctx).GithubSkipSSL,
-		kitcfg.G[config.Config](ctx).GithubEndpoint,
-	)
+	ghOrg, ghRepo, ghPrId, err := cmdutils.ParseOrgRepoAndPullRequestArgs(args)
 	if err != nil {
 		return err
 	}
@@ -70,7 +92,7 @@ func (opts *Labels) Run(ctx context.Context, args []string) error {
 		WithField("pr_id", ghPrId).
 		Info("getting pull request details")
 
-	pr, err := ghClient.GetPullRequest(ctx, ghRepo, ghRepo, ghPrId)
+	pr, err := ghClient.GetPullRequest(ctx, ghOrg, ghRepo, ghPrId)
 	if err != nil {
 		return fmt.Errorf("could not get pull request")
 	}
@@ -79,117 +101,416 @@ func (opts *Labels) Run(ctx context.Context, args []string) error {
 		return fmt.Errorf("pull request is closed")
 	}
 
-	tempDir := kitcfg.G[config.Config](ctx).TempDir
-	if tempDir == "" {
-		tempDir, err = os.MkdirTemp("", "governctl-pr-sync-labels-*")
+	tempDir, cleanup, err := TempDir(ctx, "governctl-pr-sync-labels-*")
+	if err != nil {
+		return err
+	}
+
+	defer cleanup()
+
+	localRepo, err := LocalRepo(ctx, tempDir, ghOrg, ghRepo)
+	if err != nil {
+		return err
+	}
+
+	plan, err := opts.syncPullRequest(ctx, ghClient, ghOrg, ghRepo, pr, tempDir, localRepo)
+	if err != nil {
+		return err
+	}
+
+	return cmdutils.WritePlan(ctx, opts.Output, plan)
+}
+
+// runAll synchronises the labels of every open pull request of the repository
+// provided as the only argument, or of every repository of the repos
+// definition directory if none is provided.  A failure to synchronise one pull
+// request does not prevent the remaining ones from being synchronised and pull
+// requests which are too large to evaluate are skipped.
+func (opts *Labels) runAll(ctx context.Context, ghClient *ghapi.GithubClient, args []string) error {
+	ghOrg := kitcfg.G[config.Config](ctx).EffectiveGithubOrg("")
+
+	var repos []string
+
+	switch len(args) {
+	case 0:
+		all, err := repo.NewListOfReposFromPath(ghClient, ghOrg, kitcfg.G[config.Config](ctx).ReposDir)
 		if err != nil {
-			return fmt.Errorf("could not create temporary directory: %w", err)
+			return fmt.Errorf("could not populate repos: %w", err)
 		}
 
-		defer func() {
-			os.RemoveAll(tempDir)
-		}()
+		for _, r := range all {
+			repos = append(repos, r.Fullname())
+		}
+	case 1:
+		repos = args
+	default:
+		return fmt.Errorf("--all accepts at most a single REPO")
 	}
 
-	localRepo := path.Join(tempDir, ghRepo)
+	plans := make([]*LabelsPlan, 0)
+	var errs []error
+	var total int
+
+	for _, ghRepo := range repos {
+		logger := log.G(ctx).WithField("repo", ghRepo)
+
+		prs, err := ghClient.ListOpenPullRequests(ctx, ghOrg, ghRepo)
+		if err != nil {
+			logger.Errorf("could not list open pull requests: %s", err)
+			errs = append(errs, fmt.Errorf("%s: %w", ghRepo, err))
+			continue
+		}
+
+		if len(prs) == 0 {
+			logger.Info("no open pull requests")
+			continue
+		}
 
-	if os.Getenv("GITHUB_ACTIONS") == "yes" {
-		localRepo = os.Getenv("GITHUB_WORKSPACE")
+		total += len(prs)
+
+		repoPlans, repoErrs := opts.syncRepository(ctx, ghClient, ghOrg, ghRepo, prs)
+		plans = append(plans, repoPlans...)
+		errs = append(errs, repoErrs...)
 	}
 
-	// Check if we have a copy of the repo locally, but equally retrieve a copy if
-	// we do not have it so that we can
-	if _, err := os.Stat(localRepo); os.IsNotExist(err) {
-		log.G(ctx).
-			WithField("from", ghOrigin).
-			WithField("to", localRepo).
-			Infof("cloning git repository")
+	if len(errs) > 0 {
+		return fmt.Errorf(
+			"could not synchronise %d of %d pull requests: %w",
+			len(errs),
+			total,
+			errors.Join(errs...),
+		)
+	}
+
+	return cmdutils.WritePlan(ctx, opts.Output, plans)
+}
+
+// syncRepository synchronises the labels of the provided open pull requests
+// of a single repository, which is only cloned once, and returns the plans and
+// errors of every pull request.
+func (opts *Labels) syncRepository(ctx context.Context, ghClient *ghapi.GithubClient, ghOrg, ghRepo string, prs []*github.PullRequest) ([]*LabelsPlan, []error) {
+	tempDir, cleanup, err := TempDir(ctx, "governctl-pr-sync-labels-*")
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", ghRepo, err)}
+	}
+
+	defer cleanup()
+
+	localRepo, err := LocalRepo(ctx, tempDir, ghOrg, ghRepo)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", ghRepo, err)}
+	}
+
+	var plans []*LabelsPlan
+	var errs []error
 
-		if _, err := git.PlainClone(localRepo, false, &git.CloneOptions{
-			URL: ghOrigin,
-			Auth: &http.BasicAuth{
-				Username: kitcfg.G[config.Config](ctx).GithubUser,
-				Password: kitcfg.G[config.Config](ctx).GithubToken,
-			},
-		}); err != nil {
-			return fmt.Errorf("could not clone repository: %s", err)
+	for i, pr := range prs {
+		logger := log.G(ctx).
+			WithField("repo", ghRepo).
+			WithField("pr_id", pr.GetNumber()).
+			WithField("progress", fmt.Sprintf("%d/%d", i+1, len(prs)))
+
+		plan, err := opts.syncPullRequest(ctx, ghClient, ghOrg, ghRepo, pr, tempDir, localRepo)
+		if errors.Is(err, ErrPullRequestTooLarge) {
+			logger.Warn(err)
+			continue
+		} else if err != nil {
+			logger.Errorf("could not synchronise labels: %s", err)
+			errs = append(errs, fmt.Errorf("%s#%d: %w", ghRepo, pr.GetNumber(), err))
+			continue
 		}
+
+		plans = append(plans, plan)
 	}
 
+	return plans, errs
+}
+
+// syncPullRequest synchronises the labels of a single pull request of the
+// repository which has been cloned to localRepo and records the applied
+// actions.
+func (opts *Labels) syncPullRequest(ctx context.Context, ghClient *ghapi.GithubClient, ghOrg, ghRepo string, pr *github.PullRequest, tempDir, localRepo string) (*LabelsPlan, error) {
+	// Retrieve a list of modified files in this PR
+	files, err := ChangedFiles(ctx, ghClient, ghOrg, ghRepo, pr, tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := LoadState(ctx, ghClient, ghOrg, ghRepo, pr.GetNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := opts.Apply(ctx, ghClient, state, ghOrg, ghRepo, pr, localRepo, files)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := SaveState(ctx, state); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// Apply synchronises the labels of the pull request based on the label
+// definitions in the local copy of the repository and the files which the
+// pull request changes.  Labels with a timer are only added or removed once
+// it has elapsed according to the history of the pull request.  Labels which
+// have been added or removed before according to the state are left alone,
+// such that a label which has since been removed by hand is not added again.
+// It returns the changes, which are only planned and not performed in dry-run
+// mode.
+func (opts *Labels) Apply(ctx context.Context, ghClient *ghapi.GithubClient, state *ghapi.ActionState, ghOrg, ghRepo string, pr *github.PullRequest, localRepo string, files []string) (*LabelsPlan, error) {
+	ghPrId := pr.GetNumber()
+	ctx = ghapi.WithOperation(ghapi.WithAPIUsagePullRequest(ctx, ghOrg, ghRepo, ghPrId), ghapi.OperationLabels)
+
 	labels, err := label.NewListOfLabelsFromPath(
 		ghClient,
 		ghOrg,
-		path.Join(localRepo, opts.LabelsDir),
+		path.Join(localRepo, opts.RepoLabelsDir),
 	)
 	if err != nil {
-		return fmt.Errorf("could not populate repos: %s", err)
+		return nil, fmt.Errorf("could not populate repos: %s", err)
 	}
 
-	// Retrieve a list of modified files in this PR
-	localDiffFile := path.Join(
-		tempDir,
-		fmt.Sprintf("%s-%d.diff", ghRepo, ghPrId),
-	)
+	var existing []string
+	for _, l := range pr.Labels {
+		existing = append(existing, l.GetName())
+	}
+
+	src := labelSources{
+		Repo:   ghRepo,
+		Title:  pr.GetTitle(),
+		Base:   pr.GetBase().GetRef(),
+		Head:   pr.GetHead().GetRef(),
+		Files:  files,
+		Labels: existing,
+		Now:    time.Now(),
+	}
+
+	if hasTimers(labels) {
+		src.Snapshot, err = labelSnapshot(ctx, ghClient, ghOrg, ghRepo, pr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plan := planLabels(labels, src, opts.RemoveUnmatched)
+
+	if len(plan.Deferred) > 0 {
+		log.G(ctx).
+			WithField("repo", ghRepo).
+			WithField("pr_id", ghPrId).
+			WithField("labels", plan.Deferred).
+			Info("deferring labels which are not yet due")
+	}
+
+	plan.Org = ghOrg
+	plan.Repo = ghRepo
+	plan.PullRequest = ghPrId
 
-	if _, err := os.Stat(localDiffFile); os.IsNotExist(err) {
+	target := actionTarget(ghOrg, ghRepo, ghPrId)
+	add, addKeys := skipApplied(state, "label", target, plan.Add)
+	remove, removeKeys := skipApplied(state, "unlabel", target, plan.Remove)
+	plan.Add, plan.Remove = add, remove
+
+	if len(plan.Add) > 0 {
 		log.G(ctx).
-			WithField("from", *pr.DiffURL).
-			WithField("to", localDiffFile).
-			Infof("saving diff")
+			WithField("repo", ghRepo).
+			WithField("pr_id", ghPrId).
+			WithField("labels", plan.Add).
+			Infof("setting labels on pull request")
 
-		if err = utils.DownloadFile(localDiffFile, *pr.DiffURL); err != nil {
-			return fmt.Errorf("could not download pull request diff: %s", err)
+		if !kitcfg.G[config.Config](ctx).DryRun {
+			if err := ghClient.AddLabelsToPr(ctx, ghOrg, ghRepo, ghPrId, plan.Add); err != nil {
+				return nil, fmt.Errorf("could not add labels to repo: %w", err)
+			}
+
+			state.Record(addKeys...)
 		}
 	}
 
-	log.G(ctx).
-		WithField("file", localDiffFile).
-		Infof("reading diff")
+	if len(plan.Remove) > 0 {
+		log.G(ctx).
+			WithField("repo", ghRepo).
+			WithField("pr_id", ghPrId).
+			WithField("labels", plan.Remove).
+			Infof("removing labels from pull request")
+
+		if !kitcfg.G[config.Config](ctx).DryRun {
+			if err := ghClient.RemovePullRequestLabels(ctx, ghOrg, ghRepo, ghPrId, plan.Remove); err != nil {
+				return nil, fmt.Errorf("could not remove labels from repo: %w", err)
+			}
 
-	d, err := os.ReadFile(localDiffFile)
+			state.Record(removeKeys...)
+		}
+	}
+
+	return &plan, nil
+}
+
+// hasTimers returns whether any of the labels is added or removed after a
+// delay, such that the history of the pull request is required to plan them.
+func hasTimers(labels []label.Label) bool {
+	for _, l := range labels {
+		if l.ApplyAfter != 0 || l.ApplyAfterEvent != "" || l.RemoveAfter != 0 || l.RemoveAfterEvent != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// labelSnapshot returns the snapshot of the pull request from its commits,
+// reviews and issue timeline against which the timers of labels are
+// evaluated.
+func labelSnapshot(ctx context.Context, ghClient *ghapi.GithubClient, ghOrg, ghRepo string, pr *github.PullRequest) (*label.Snapshot, error) {
+	// Listed pull requests do not carry their mergeable state, which is
+	// required to tell whether they have conflicts.
+	if pr.MergeableState == nil {
+		full, err := ghClient.GetPullRequest(ctx, ghOrg, ghRepo, pr.GetNumber())
+		if err != nil {
+			return nil, fmt.Errorf("could not get pull request: %w", err)
+		}
+
+		pr = full
+	}
+
+	commits, err := ghClient.GetPullRequestCommits(ctx, ghOrg, ghRepo, pr.GetNumber())
 	if err != nil {
-		return fmt.Errorf("could not read diff file diff: %s", err)
+		return nil, fmt.Errorf("could not list commits: %w", err)
 	}
 
-	diff, err := diffparser.Parse(string(d))
+	reviews, err := ghClient.ListPullRequestReviews(ctx, ghOrg, ghRepo, pr.GetNumber())
 	if err != nil {
-		return fmt.Errorf("could not parse diff from pull request: %s", err)
+		return nil, fmt.Errorf("could not list reviews: %w", err)
 	}
 
-	var labelsToAdd []string
+	timeline, err := ghClient.ListIssueTimeline(ctx, ghOrg, ghRepo, pr.GetNumber())
+	if err != nil {
+		return nil, fmt.Errorf("could not list timeline: %w", err)
+	}
 
-	for _, f := range diff.Files {
-		log.G(ctx).
-			WithField("file", f.NewName).
-			Info("checking diff")
+	return label.NewSnapshot(pr, commits, reviews, timeline), nil
+}
 
-		// Determine the labels to add based on the changed files
-		for _, label := range labels {
-			if containsStr(labelsToAdd, label.Name) {
-				continue
+// labelSources are all the attributes of a pull request which are used to
+// determine whether a label applies to it.
+type labelSources struct {
+	Repo   string
+	Title  string
+	Base   string
+	Head   string
+	Files  []string
+	Labels []string
+
+	// Snapshot is the history of the pull request against which the timers
+	// of labels are evaluated at Now.  It is only required if any label has
+	// a timer.
+	Snapshot *label.Snapshot
+	Now      time.Time
+}
+
+// LabelsPlan is the final set of labels which should be added to and removed
+// from a pull request.
+type LabelsPlan struct {
+	Org         string   `json:"org"`
+	Repo        string   `json:"repo"`
+	PullRequest int      `json:"pull_request"`
+	Add         []string `json:"add,omitempty"`
+	Remove      []string `json:"remove,omitempty"`
+
+	// Deferred are the labels which apply to the pull request but whose
+	// timer has not elapsed yet.
+	Deferred []string `json:"deferred,omitempty"`
+}
+
+// planLabels evaluates every label once against all applicability sources of a
+// pull request, i.e. its changed files, its title and its base and head
+// branches.  Labels without any matcher but with an apply_after_event are
+// applied once that event has happened.  Labels which already exist on the
+// pull request are not re-added, labels whose apply_after timer has not
+// elapsed yet are deferred and labels whose remove_after timer has elapsed are
+// removed.  When removeUnmatched is set, labels which can be applied
+// automatically but no longer match any source are scheduled for removal.
+func planLabels(labels []label.Label, src labelSources, removeUnmatched bool) LabelsPlan {
+	var plan LabelsPlan
+	var matched []string
+
+	if src.Snapshot == nil {
+		src.Snapshot = &label.Snapshot{}
+	}
+
+	for _, l := range labels {
+		if containsStr(matched, l.Name) {
+			continue
+		}
+
+		applies := l.AppliesToTitle(src.Repo, src.Title) ||
+			l.AppliesToPullRequest(src.Repo, src.Base, src.Head)
+
+		if !applies && !l.IsAutomatic() && l.ApplyAfterEvent != "" {
+			_, applies = src.Snapshot.Anchor(l.ApplyAfterEvent)
+		}
+
+		for _, f := range src.Files {
+			if applies {
+				break
 			}
 
-			if len(f.OrigName) > 0 && label.AppliesTo(ghRepo, f.OrigName) {
-				labelsToAdd = append(labelsToAdd, label.Name)
+			applies = l.AppliesTo(src.Repo, f)
+		}
+
+		if applies {
+			matched = append(matched, l.Name)
+		}
+	}
+
+	for _, name := range matched {
+		if containsStr(src.Labels, name) {
+			continue
+		}
+
+		l := findLabel(labels, name)
+		if l.RemoveDue(src.Snapshot, src.Now) {
+			continue
+		}
+
+		if !l.ApplyDue(src.Snapshot, src.Now) {
+			plan.Deferred = append(plan.Deferred, name)
+			continue
+		}
+
+		plan.Add = append(plan.Add, name)
+	}
+
+	for _, l := range labels {
+		if containsStr(src.Labels, l.Name) && !containsStr(plan.Remove, l.Name) && l.RemoveDue(src.Snapshot, src.Now) {
+			plan.Remove = append(plan.Remove, l.Name)
+		}
+	}
+
+	if removeUnmatched {
+		for _, l := range labels {
+			if !l.IsAutomatic() || containsStr(matched, l.Name) || containsStr(plan.Remove, l.Name) {
+				continue
 			}
 
-			if !containsStr(labelsToAdd, label.Name) && len(f.NewName) > 0 && label.AppliesTo(ghRepo, f.NewName) {
-				labelsToAdd = append(labelsToAdd, label.Name)
+			if containsStr(src.Labels, l.Name) {
+				plan.Remove = append(plan.Remove, l.Name)
 			}
 		}
 	}
 
-	if len(labelsToAdd) > 0 {
-		log.G(ctx).
-			WithField("repo", ghRepo).
-			WithField("pr_id", ghPrId).
-			WithField("labels", labelsToAdd).
-			Infof("setting labels on pull request")
+	return plan
+}
 
-		if !kitcfg.G[config.Config](ctx).DryRun {
-			if err := ghClient.AddLabelsToPr(ctx, ghOrg, ghRepo, ghPrId, labelsToAdd); err != nil {
-				return fmt.Errorf("could not add labels to repo: %w", err)
-			}
+// findLabel returns the first definition of the label with the provided name.
+func findLabel(labels []label.Label, name string) *label.Label {
+	for i := range labels {
+		if labels[i].Name == name {
+			return &labels[i]
 		}
 	}
 
@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/unikraft/governance/internal/label"
+)
+
+func TestPlanLabels(t *testing.T) {
+	labels := []label.Label{
+		{
+			Name:                "area/arch",
+			ApplyOnPrMatchPaths: []string{"arch/**"},
+		},
+		{
+			Name:              "kind/bug",
+			ApplyOnTitleMatch: []string{`(?i)^fix`},
+		},
+		{
+			Name:                   "stable",
+			ApplyOnBaseBranchMatch: []string{"stable/*"},
+		},
+		{
+			Name:                   "dependencies",
+			ApplyOnHeadBranchMatch: []string{"dependabot/**"},
+		},
+		{
+			Name: "manual",
+		},
+	}
+
+	tests := []struct {
+		name            string
+		src             labelSources
+		removeUnmatched bool
+		want            LabelsPlan
+	}{
+		{
+			name: "all sources combined",
+			src: labelSources{
+				Repo:  "unikraft",
+				Title: "Fix the boot sequence",
+				Base:  "stable/v0.17",
+				Head:  "dependabot/github_actions/foo",
+				Files: []string{"arch/x86/Makefile.uk"},
+			},
+			want: LabelsPlan{
+				Add: []string{"area/arch", "kind/bug", "stable", "dependencies"},
+			},
+		},
+		{
+			name: "empty diff still applies title and branch labels",
+			src: labelSources{
+				Repo:  "unikraft",
+				Title: "fix: Typo",
+				Base:  "stable/v0.17",
+				Head:  "feature",
+			},
+			want: LabelsPlan{
+				Add: []string{"kind/bug", "stable"},
+			},
+		},
+		{
+			name: "existing labels are not re-added",
+			src: labelSources{
+				Repo:   "unikraft",
+				Title:  "Fix the boot sequence",
+				Base:   "staging",
+				Files:  []string{"arch/arm/Makefile.uk"},
+				Labels: []string{"area/arch"},
+			},
+			want: LabelsPlan{
+				Add: []string{"kind/bug"},
+			},
+		},
+		{
+			name: "unmatched automatic labels are removed",
+			src: labelSources{
+				Repo:   "unikraft",
+				Title:  "lib/ukboot: Add feature",
+				Base:   "staging",
+				Files:  []string{"lib/ukboot/boot.c"},
+				Labels: []string{"kind/bug", "manual", "area/arch"},
+			},
+			removeUnmatched: true,
+			want: LabelsPlan{
+				Remove: []string{"area/arch", "kind/bug"},
+			},
+		},
+		{
+			name: "unmatched automatic labels are kept by default",
+			src: labelSources{
+				Repo:   "unikraft",
+				Title:  "lib/ukboot: Add feature",
+				Base:   "staging",
+				Labels: []string{"kind/bug"},
+			},
+			want: LabelsPlan{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planLabels(labels, tt.src, tt.removeUnmatched)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("planLabels() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanLabelsTimers(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	labels := []label.Label{
+		{
+			Name:                "area/boot",
+			ApplyOnPrMatchPaths: []string{"lib/ukboot/**"},
+			ApplyAfter:          label.Duration(2 * day),
+		},
+		{
+			Name:            "needs-rebase",
+			ApplyAfter:      label.Duration(3 * day),
+			ApplyAfterEvent: label.EventConflictDetected,
+		},
+		{
+			Name:             "ci/wait",
+			RemoveAfter:      label.Duration(day),
+			RemoveAfterEvent: "label:ci/wait",
+		},
+	}
+
+	snapshot := &label.Snapshot{
+		CreatedAt:   created,
+		Commits:     []time.Time{created.Add(day)},
+		Conflicting: true,
+		Labels:      []string{"ci/wait"},
+		Labeled:     map[string][]time.Time{"ci/wait": {created.Add(day)}},
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want LabelsPlan
+	}{
+		{
+			name: "deferred labels are not applied early",
+			now:  created.Add(day + time.Hour),
+			want: LabelsPlan{
+				Deferred: []string{"area/boot", "needs-rebase"},
+			},
+		},
+		{
+			name: "due labels are applied and removed",
+			now:  created.Add(2 * day),
+			want: LabelsPlan{
+				Add:      []string{"area/boot"},
+				Remove:   []string{"ci/wait"},
+				Deferred: []string{"needs-rebase"},
+			},
+		},
+		{
+			name: "event labels are applied once due",
+			now:  created.Add(4 * day),
+			want: LabelsPlan{
+				Add:    []string{"area/boot", "needs-rebase"},
+				Remove: []string{"ci/wait"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planLabels(labels, labelSources{
+				Repo:     "unikraft",
+				Files:    []string{"lib/ukboot/boot.c"},
+				Labels:   snapshot.Labels,
+				Snapshot: snapshot,
+				Now:      tt.now,
+			}, false)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("planLabels() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// Without a conflict, the event of needs-rebase has not happened and the
+	// label does not apply at all.
+	resolved := *snapshot
+	resolved.Conflicting = false
+
+	got := planLabels(labels, labelSources{
+		Repo:     "unikraft",
+		Snapshot: &resolved,
+		Now:      created.Add(10 * day),
+	}, false)
+	if len(got.Add) != 0 || len(got.Deferred) != 0 {
+		t.Errorf("planLabels() = %+v, want needs-rebase not to apply without a conflict", got)
+	}
+}
@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+const (
+	// CommandUnassignMe is left as a comment by an assignee or requested
+	// reviewer who wants to be removed from every role they hold on the pull
+	// request.
+	CommandUnassignMe = "/unassign-me"
+
+	// CommandReassignReviewer is left as a comment by a requested reviewer who
+	// wants to hand the review over to someone else.
+	CommandReassignReviewer = "/reassign-reviewer"
+)
+
+// OptOutCommands are all the commands with which a user opts out of a pull
+// request.
+var OptOutCommands = []string{
+	CommandUnassignMe,
+	CommandReassignReviewer,
+}
+
+const (
+	roleMaintainer = "maintainer"
+	roleReviewer   = "reviewer"
+)
+
+// OptOut is the outcome of a command with which a user opted out of a pull
+// request.
+type OptOut struct {
+	Login   string `json:"login"`
+	Command string `json:"command"`
+
+	// Roles are the roles which the user was removed from, either of which
+	// is given to the respective replacement, if any.
+	Roles                 []string `json:"roles,omitempty"`
+	ReplacementMaintainer string   `json:"replacement_maintainer,omitempty"`
+	ReplacementReviewer   string   `json:"replacement_reviewer,omitempty"`
+
+	// Refused is set if the user holds none of the roles which the command
+	// applies to.
+	Refused bool `json:"refused,omitempty"`
+}
+
+// parseOptOutCommand returns the first of OptOutCommands which starts a line
+// of the comment, optionally followed by a reason, or an empty string if there
+// is none.  Quoted lines start with ">" and therefore never match.
+func parseOptOutCommand(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		for _, command := range OptOutCommands {
+			if strings.EqualFold(fields[0], command) {
+				return command
+			}
+		}
+	}
+
+	return ""
+}
+
+// processOptOuts handles the opt-out commands left as comments on the pull
+// request.  The author of a command is removed from the roles it applies to
+// and a replacement is selected amongst the provided candidates according to
+// the workload, excluding anyone who already holds the role or has opted out.
+// Commands from users who hold none of the roles are refused.  Every command
+// is answered with a reply which marks it as handled, such that it is ignored
+// on later runs.  It returns the outcome of every command together with the
+// logins which have opted out, which are not to be assigned again.  Nothing is
+// changed in dry-run mode.
+func (opts *Reviewers) processOptOuts(ctx context.Context, org, repo string, pr *github.PullRequest, maintainers, reviewers []string) ([]*OptOut, []string, error) {
+	prId := pr.GetNumber()
+	target := actionTarget(org, repo, prId)
+
+	comments, err := opts.ghClient.ListPullRequestComments(ctx, org, repo, prId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not list comments: %w", err)
+	}
+
+	var optOuts []*OptOut
+	var optedOut []string
+	var assignees, requested []string
+	loaded := false
+
+	for _, comment := range comments {
+		command := parseOptOutCommand(comment.GetBody())
+		if command == "" {
+			continue
+		}
+
+		key := ghapi.ActionKey("opt-out", target, strconv.FormatInt(comment.GetID(), 10))
+		if opts.state.Applied(key) {
+			continue
+		}
+
+		// The current assignees and reviewers are only retrieved once there is
+		// a command to handle and are then kept up to date locally.
+		if !loaded {
+			if assignees, err = opts.ghClient.GetMaintainersOnPr(ctx, org, repo, prId); err != nil {
+				return nil, nil, fmt.Errorf("could not get maintainers: %w", err)
+			}
+
+			if requested, err = opts.ghClient.GetReviewersOnPr(ctx, org, repo, prId); err != nil {
+				return nil, nil, fmt.Errorf("could not get reviewers: %w", err)
+			}
+
+			loaded = true
+		}
+
+		login := comment.GetUser().GetLogin()
+		optOut := &OptOut{
+			Login:   login,
+			Command: command,
+		}
+
+		if command == CommandUnassignMe && containsStr(assignees, login) {
+			optOut.Roles = append(optOut.Roles, roleMaintainer)
+		}
+
+		if containsStr(requested, login) {
+			optOut.Roles = append(optOut.Roles, roleReviewer)
+		}
+
+		logger := log.G(ctx).
+			WithField("pr_id", prId).
+			WithField("login", login).
+			WithField("command", command)
+
+		if len(optOut.Roles) == 0 {
+			optOut.Refused = true
+			optOuts = append(optOuts, optOut)
+
+			logger.Info("refusing opt-out of user who is not assigned")
+
+			if err := opts.reply(ctx, org, repo, prId, key, refuseOptOutMessage(optOut)); err != nil {
+				return nil, nil, err
+			}
+
+			continue
+		}
+
+		optedOut = append(optedOut, login)
+
+		if containsStr(optOut.Roles, roleMaintainer) {
+			assignees = subtractStr(assignees, []string{login})
+
+			candidates := subtractStr(maintainers, append(assignees, optedOut...))
+			if len(candidates) > 0 {
+				optOut.ReplacementMaintainer = opts.popLeastStressedMaintainer(candidates)
+				assignees = append(assignees, optOut.ReplacementMaintainer)
+			}
+		}
+
+		if containsStr(optOut.Roles, roleReviewer) {
+			requested = subtractStr(requested, []string{login})
+
+			candidates := subtractStr(reviewers, append(append(requested, assignees...), optedOut...))
+			if len(candidates) > 0 {
+				optOut.ReplacementReviewer = opts.popLeastStressedReviewer(candidates)
+				requested = append(requested, optOut.ReplacementReviewer)
+			}
+		}
+
+		optOuts = append(optOuts, optOut)
+
+		logger.
+			WithField("roles", optOut.Roles).
+			WithField("replacement_maintainer", optOut.ReplacementMaintainer).
+			WithField("replacement_reviewer", optOut.ReplacementReviewer).
+			Info("replacing user who opted out")
+
+		if kitcfg.G[config.Config](ctx).DryRun {
+			continue
+		}
+
+		if err := opts.swap(ctx, org, repo, prId, optOut); err != nil {
+			return nil, nil, err
+		}
+
+		if err := opts.reply(ctx, org, repo, prId, key, confirmOptOutMessage(optOut)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return optOuts, optedOut, nil
+}
+
+// swap removes the user who opted out from their roles and assigns the
+// replacements.
+func (opts *Reviewers) swap(ctx context.Context, org, repo string, prId int, optOut *OptOut) error {
+	if containsStr(optOut.Roles, roleMaintainer) {
+		if err := opts.ghClient.RemoveMaintainersFromPr(ctx, org, repo, prId, []string{optOut.Login}); err != nil {
+			return err
+		}
+
+		if optOut.ReplacementMaintainer != "" {
+			result, err := opts.ghClient.AddMaintainersToPr(ctx, org, repo, prId, []string{optOut.ReplacementMaintainer})
+			logAssignmentResult(ctx, "maintainers", result)
+			if err != nil {
+				return fmt.Errorf("could not add maintainer: %w", err)
+			}
+		}
+	}
+
+	if containsStr(optOut.Roles, roleReviewer) {
+		if err := opts.ghClient.RemoveReviewersFromPr(ctx, org, repo, prId, []string{optOut.Login}); err != nil {
+			return err
+		}
+
+		if optOut.ReplacementReviewer != "" {
+			result, err := opts.ghClient.AddReviewersToPr(ctx, org, repo, prId, []string{optOut.ReplacementReviewer})
+			logAssignmentResult(ctx, "reviewers", result)
+			if err != nil {
+				return fmt.Errorf("could not add reviewer: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// reply leaves the comment which answers an opt-out command and marks it as
+// handled.
+func (opts *Reviewers) reply(ctx context.Context, org, repo string, prId int, key, body string) error {
+	if kitcfg.G[config.Config](ctx).DryRun {
+		return nil
+	}
+
+	if opts.state != nil {
+		return opts.state.CreateComment(ctx, key, body)
+	}
+
+	return opts.ghClient.CreatePullRequestComment(ctx, org, repo, prId, ghapi.CommentMarker(key)+"\n"+body)
+}
+
+// confirmOptOutMessage returns the reply which confirms that the user has been
+// replaced.
+func confirmOptOutMessage(optOut *OptOut) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "@%s, as requested with `%s`:\n\n", optOut.Login, optOut.Command)
+
+	if containsStr(optOut.Roles, roleMaintainer) {
+		if optOut.ReplacementMaintainer != "" {
+			fmt.Fprintf(&b, "- @%s has been assigned in your place.\n", optOut.ReplacementMaintainer)
+		} else {
+			b.WriteString("- You have been unassigned, but no other maintainer is available to take over.\n")
+		}
+	}
+
+	if containsStr(optOut.Roles, roleReviewer) {
+		if optOut.ReplacementReviewer != "" {
+			fmt.Fprintf(&b, "- @%s has been requested to review in your place.\n", optOut.ReplacementReviewer)
+		} else {
+			b.WriteString("- Your review request has been withdrawn, but no other reviewer is available to take over.\n")
+		}
+	}
+
+	return b.String()
+}
+
+// refuseOptOutMessage returns the reply to a user who is not assigned to the
+// pull request in a role which the command applies to.
+func refuseOptOutMessage(optOut *OptOut) string {
+	if optOut.Command == CommandReassignReviewer {
+		return fmt.Sprintf("@%s, `%s` can only be used by the requested reviewers of this pull request, so nothing has been changed.", optOut.Login, optOut.Command)
+	}
+
+	return fmt.Sprintf("@%s, `%s` can only be used by the assignees and requested reviewers of this pull request, so nothing has been changed.", optOut.Login, optOut.Command)
+}
@@ -0,0 +1,292 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+	kitcfg "kraftkit.sh/config"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+func TestParseOptOutCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "unassign",
+			body: "/unassign-me",
+			want: CommandUnassignMe,
+		},
+		{
+			name: "reassign with reason",
+			body: "Sorry, conflict of interest.\n\n/reassign-reviewer on leave until May",
+			want: CommandReassignReviewer,
+		},
+		{
+			name: "case-insensitive",
+			body: "  /Unassign-Me",
+			want: CommandUnassignMe,
+		},
+		{
+			name: "quoted",
+			body: "> /unassign-me\n\nWhy?",
+		},
+		{
+			name: "within a sentence",
+			body: "Use /unassign-me if you cannot review this.",
+		},
+		{
+			name: "longer command",
+			body: "/unassign-merge",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseOptOutCommand(tt.body); got != tt.want {
+				t.Errorf("parseOptOutCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewersOptOut(t *testing.T) {
+	handledKey := ghapi.ActionKey("opt-out", "unikraft/app-test#1", "10")
+
+	tests := []struct {
+		name        string
+		dryRun      bool
+		comments    []string
+		wantOptOuts []*OptOut
+		wantWrites  []string
+		wantReply   string
+	}{
+		{
+			name:     "assignee unassigns",
+			comments: []string{`{"id":10,"body":"/unassign-me","user":{"login":"alice"}}`},
+			wantOptOuts: []*OptOut{{
+				Login:                 "alice",
+				Command:               CommandUnassignMe,
+				Roles:                 []string{roleMaintainer},
+				ReplacementMaintainer: "carol",
+			}},
+			wantWrites: []string{
+				`DELETE /issues/1/assignees {"assignees":["alice"]}`,
+				`POST /issues/1/assignees {"assignees":["carol"]}`,
+				`POST /issues/1/comments`,
+			},
+			wantReply: "@carol has been assigned in your place",
+		},
+		{
+			name:     "requested reviewer reassigns to the least stressed",
+			comments: []string{`{"id":10,"body":"Conflict of interest.\n/reassign-reviewer","user":{"login":"bob"}}`},
+			wantOptOuts: []*OptOut{{
+				Login:               "bob",
+				Command:             CommandReassignReviewer,
+				Roles:               []string{roleReviewer},
+				ReplacementReviewer: "dave",
+			}},
+			wantWrites: []string{
+				`DELETE /pulls/1/requested_reviewers {"reviewers":["bob"]}`,
+				`POST /pulls/1/requested_reviewers {"reviewers":["dave"]}`,
+				`POST /issues/1/comments`,
+			},
+			wantReply: "@dave has been requested to review in your place",
+		},
+		{
+			name:     "requested reviewer unassigns",
+			comments: []string{`{"id":10,"body":"/unassign-me","user":{"login":"bob"}}`},
+			wantOptOuts: []*OptOut{{
+				Login:               "bob",
+				Command:             CommandUnassignMe,
+				Roles:               []string{roleReviewer},
+				ReplacementReviewer: "dave",
+			}},
+			wantWrites: []string{
+				`DELETE /pulls/1/requested_reviewers {"reviewers":["bob"]}`,
+				`POST /pulls/1/requested_reviewers {"reviewers":["dave"]}`,
+				`POST /issues/1/comments`,
+			},
+			wantReply: "@dave has been requested to review in your place",
+		},
+		{
+			name:     "unassigned user is refused",
+			comments: []string{`{"id":10,"body":"/unassign-me","user":{"login":"eve"}}`},
+			wantOptOuts: []*OptOut{{
+				Login:   "eve",
+				Command: CommandUnassignMe,
+				Refused: true,
+			}},
+			wantWrites: []string{`POST /issues/1/comments`},
+			wantReply:  "can only be used by the assignees and requested reviewers",
+		},
+		{
+			name:     "assignee cannot reassign review",
+			comments: []string{`{"id":10,"body":"/reassign-reviewer","user":{"login":"alice"}}`},
+			wantOptOuts: []*OptOut{{
+				Login:   "alice",
+				Command: CommandReassignReviewer,
+				Refused: true,
+			}},
+			wantWrites: []string{`POST /issues/1/comments`},
+			wantReply:  "can only be used by the requested reviewers",
+		},
+		{
+			name: "already handled",
+			comments: []string{
+				`{"id":10,"body":"/unassign-me","user":{"login":"alice"}}`,
+				fmt.Sprintf(`{"id":11,"body":%q,"user":{"login":"governctl"}}`, ghapi.CommentMarker(handledKey)+"\n@alice, as requested"),
+			},
+		},
+		{
+			name:     "dry-run",
+			dryRun:   true,
+			comments: []string{`{"id":10,"body":"/unassign-me","user":{"login":"alice"}}`},
+			wantOptOuts: []*OptOut{{
+				Login:                 "alice",
+				Command:               CommandUnassignMe,
+				Roles:                 []string{roleMaintainer},
+				ReplacementMaintainer: "carol",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var writes []string
+			var reply string
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/unikraft/app-test")
+
+				if r.Method != http.MethodGet {
+					body, _ := io.ReadAll(r.Body)
+
+					if path == "/issues/1/comments" {
+						var comment github.IssueComment
+						if err := json.Unmarshal(body, &comment); err != nil {
+							t.Errorf("could not decode comment: %v", err)
+						}
+
+						reply = comment.GetBody()
+						writes = append(writes, r.Method+" "+path)
+					} else {
+						writes = append(writes, r.Method+" "+path+" "+strings.TrimSpace(string(body)))
+					}
+
+					fmt.Fprint(w, `{}`)
+					return
+				}
+
+				switch path {
+				case "/pulls":
+					fmt.Fprint(w, `[{"number":1,"state":"open"},{"number":2,"state":"open"}]`)
+				case "/pulls/1":
+					fmt.Fprint(w, `{"number":1,"state":"open","assignees":[{"login":"alice"}]}`)
+				case "/pulls/1/requested_reviewers":
+					fmt.Fprint(w, `{"users":[{"login":"bob"}],"teams":[]}`)
+				case "/pulls/2/requested_reviewers":
+					// erin is busier than dave
+					fmt.Fprint(w, `{"users":[{"login":"erin"}],"teams":[]}`)
+				case "/issues/1/comments":
+					fmt.Fprintf(w, "[%s]", strings.Join(tt.comments, ","))
+				case "/pulls/2":
+					fmt.Fprint(w, `{"number":2,"state":"open"}`)
+				default:
+					fmt.Fprint(w, `[]`)
+				}
+			}))
+			defer srv.Close()
+
+			teamsDir := filepath.Join(t.TempDir(), "teams")
+			writeFile(t, filepath.Join(teamsDir, "maintainers-boot.yaml"), `
+name: maintainers-boot
+maintainers:
+  - github: alice
+  - github: carol
+reviewers:
+  - github: bob
+  - github: dave
+  - github: erin
+repos:
+  - name: app-test
+`)
+
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{
+				DryRun:         tt.dryRun,
+				GithubEndpoint: srv.URL,
+				TeamsDir:       teamsDir,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+			ghClient, err := ghapi.NewGithubClient(ctx, "token", false, srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			state, err := LoadState(ctx, ghClient, "unikraft", "app-test", 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pr := &github.PullRequest{
+				Number: github.Int(1),
+				State:  github.String("open"),
+				User:   &github.User{Login: github.String("author")},
+			}
+
+			opts := &Reviewers{
+				NumMaintainers: 1,
+				NumReviewers:   1,
+			}
+
+			plan, err := opts.Apply(ctx, ghClient, state, "unikraft", "app-test", pr, t.TempDir(), []string{"lib/ukboot/boot.c"})
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(plan.OptOuts, tt.wantOptOuts) {
+				got, _ := json.Marshal(plan.OptOuts)
+				want, _ := json.Marshal(tt.wantOptOuts)
+				t.Errorf("plan.OptOuts = %s, want %s", got, want)
+			}
+
+			if !reflect.DeepEqual(writes, tt.wantWrites) {
+				t.Errorf("writes = %q, want %q", writes, tt.wantWrites)
+			}
+
+			if tt.wantReply == "" {
+				return
+			}
+
+			if !strings.HasPrefix(reply, ghapi.CommentMarker(handledKey)) {
+				t.Errorf("reply does not mark the command as handled:\n%s", reply)
+			}
+
+			if !strings.Contains(reply, tt.wantReply) {
+				t.Errorf("reply = %q, want it to contain %q", reply, tt.wantReply)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+// newReadOnlyEnv starts a fake GitHub API server which serves an open pull
+// request unikraft/app-test#1 modifying a single file and returns a context
+// configured in read-only mode against it, as well as a pointer to the number
+// of write requests which have reached the server.
+func newReadOnlyEnv(t *testing.T) (context.Context, *int) {
+	t.Helper()
+
+	return newSyncEnv(t, config.Config{ReadOnly: true})
+}
+
+// newSyncEnv is like newReadOnlyEnv but configures the context with the
+// provided configuration, e.g. in dry-run mode.
+func newSyncEnv(t *testing.T, cfg config.Config) (context.Context, *int) {
+	t.Helper()
+
+	t.Setenv("GITHUB_ACTIONS", "")
+
+	writes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			writes++
+			w.WriteHeader(http.StatusCreated)
+			if strings.HasSuffix(r.URL.Path, "/labels") {
+				fmt.Fprint(w, `[]`)
+			} else {
+				fmt.Fprint(w, `{}`)
+			}
+			return
+		}
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/pulls/1"):
+			fmt.Fprint(w, `{"number":1,"state":"open","title":"lib/ukboot: Fix boot","changed_files":1,"user":{"login":"author"}}`)
+		case strings.HasSuffix(r.URL.Path, "/pulls/1/requested_reviewers"):
+			fmt.Fprint(w, `{"users":[],"teams":[]}`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	tempDir := t.TempDir()
+	localRepo := filepath.Join(tempDir, "app-test")
+
+	// Provide the local copy of the repository and the pull request's diff so
+	// that nothing is cloned or downloaded.
+	writeFile(t, filepath.Join(localRepo, ".github", "labels", "labels.yaml"), `
+labels:
+  - name: area/boot
+    apply_on_pr_match_paths:
+      - "lib/ukboot/**"
+`)
+	writeFile(t, filepath.Join(tempDir, "app-test-1.diff"), strings.Join([]string{
+		"diff --git a/lib/ukboot/boot.c b/lib/ukboot/boot.c",
+		"index 0000001..0000002 100644",
+		"--- a/lib/ukboot/boot.c",
+		"+++ b/lib/ukboot/boot.c",
+		"@@ -1 +1 @@",
+		"-old",
+		"+new",
+		"",
+	}, "\n"))
+
+	teamsDir := filepath.Join(t.TempDir(), "teams")
+	writeFile(t, filepath.Join(teamsDir, "maintainers-boot.yaml"), `
+name: maintainers-boot
+maintainers:
+  - github: alice
+reviewers:
+  - github: bob
+repos:
+  - name: app-test
+`)
+
+	cfg.GithubEndpoint = srv.URL
+	cfg.TeamsDir = teamsDir
+	cfg.TempDir = tempDir
+
+	cfgm, err := kitcfg.NewConfigManager(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+	ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: &bytes.Buffer{}})
+
+	return ctx, &writes
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(ctx context.Context) error
+	}{
+		{
+			name: "labels",
+			run: func(ctx context.Context) error {
+				opts := &Labels{RepoLabelsDir: ".github/labels"}
+				return opts.Run(ctx, []string{"unikraft/app-test/1"})
+			},
+		},
+		{
+			name: "reviewers",
+			run: func(ctx context.Context) error {
+				opts := &Reviewers{NumMaintainers: 1, NumReviewers: 1}
+				return opts.Run(ctx, []string{"unikraft/app-test/1"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, writes := newReadOnlyEnv(t)
+
+			err := tt.run(ctx)
+			if !errors.Is(err, ghapi.ErrReadOnly) {
+				t.Fatalf("Run() error = %v, want ErrReadOnly", err)
+			}
+
+			if !strings.Contains(err.Error(), "blocked POST /api/v3/repos/unikraft/app-test/issues/1/") {
+				t.Errorf("expected error to name the blocked call, got: %v", err)
+			}
+
+			if *writes != 0 {
+				t.Errorf("expected no write requests to reach the server, got %d", *writes)
+			}
+		})
+	}
+}
+
+// TestDryRun checks that no command which synchronises a pull request modifies
+// it in dry-run mode, whilst every change is still planned.
+func TestDryRun(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(ctx context.Context) error
+	}{
+		{
+			name: "labels",
+			run: func(ctx context.Context) error {
+				opts := &Labels{RepoLabelsDir: ".github/labels"}
+				return opts.Run(ctx, []string{"unikraft/app-test/1"})
+			},
+		},
+		{
+			name: "reviewers",
+			run: func(ctx context.Context) error {
+				opts := &Reviewers{NumMaintainers: 1, NumReviewers: 1}
+				return opts.Run(ctx, []string{"unikraft/app-test/1"})
+			},
+		},
+		{
+			name: "size",
+			run: func(ctx context.Context) error {
+				return (&Size{}).Run(ctx, []string{"unikraft/app-test/1"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, writes := newSyncEnv(t, config.Config{DryRun: true})
+
+			if err := tt.run(ctx); err != nil {
+				t.Fatalf("Run() unexpected error: %v", err)
+			}
+
+			if *writes != 0 {
+				t.Errorf("expected no write requests in dry-run mode, got %d", *writes)
+			}
+
+			// The same command does modify the pull request outside of dry-run
+			// mode, such that the above is not vacuous.
+			ctx, writes = newSyncEnv(t, config.Config{})
+
+			if err := tt.run(ctx); err != nil {
+				t.Fatalf("Run() unexpected error: %v", err)
+			}
+
+			if *writes == 0 {
+				t.Errorf("expected write requests outside of dry-run mode")
+			}
+		})
+	}
+}
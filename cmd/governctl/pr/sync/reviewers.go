@@ -7,15 +7,15 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"path"
+	"strconv"
+	"time"
 
-	git "github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
-	"github.com/hairyhenderson/go-codeowners"
+	"github.com/MakeNowJust/heredoc"
+	"github.com/google/go-github/v63/github"
 	"github.com/spf13/cobra"
-	"github.com/waigani/diffparser"
 	"kraftkit.sh/cmdfactory"
 	kitcfg "kraftkit.sh/config"
 	"kraftkit.sh/log"
@@ -23,26 +23,85 @@ import (
 	"github.com/unikraft/governance/internal/cmdutils"
 	"github.com/unikraft/governance/internal/config"
 	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/ghpr"
+	"github.com/unikraft/governance/internal/ownership"
 	"github.com/unikraft/governance/internal/pair"
 	"github.com/unikraft/governance/internal/repo"
 	"github.com/unikraft/governance/internal/team"
-	"github.com/unikraft/governance/utils"
 )
 
 type Reviewers struct {
-	NumMaintainers int `long:"num-maintainers" short:"A" usage:"Number of maintainers for the PR" default:"1"`
-	NumReviewers   int `long:"num-reviewers" short:"R" usage:"Number of reviewers for the PR" default:"1"`
-
+	All                  bool     `long:"all" usage:"Synchronise every open PR of the provided repository, or of every repository of the repos definition directory"`
+	BotLabels            []string `long:"bot-labels" env:"GOVERN_BOT_LABELS" usage:"Labels which mark a PR as an automated dependency update (default: dependencies)"`
+	BotLogins            []string `long:"bot-logins" env:"GOVERN_BOT_LOGINS" usage:"Authors whose PRs are automated dependency updates (default: dependabot[bot], renovate[bot])"`
+	BotsNeedMaintainer   bool     `long:"bots-need-maintainer" env:"GOVERN_BOTS_NEED_MAINTAINER" usage:"Assign a single maintainer and no reviewers to automated dependency updates instead of skipping them"`
+	CompletedWeight      string   `long:"completed-weight" usage:"Fraction of a full assignment that a PR adds to the workload of a user who has already reviewed its latest commit" default:"0"`
+	NumMaintainers       int      `long:"num-maintainers" short:"A" usage:"Number of maintainers for the PR" default:"1"`
+	NumReviewers         int      `long:"num-reviewers" short:"R" usage:"Number of reviewers for the PR" default:"1"`
+	NumShadowMaintainers int      `long:"num-shadow-maintainers" usage:"Number of shadow maintainers for the PR (overrides the repository's num_shadow_maintainers)"`
+	Org                  string   `long:"org" usage:"Set the GitHub organisation whose repositories are synchronised with --all (deprecated, use --github-org)"`
+	Output               string   `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+	ShadowWeight         string   `long:"shadow-weight" usage:"Fraction of a full assignment that a shadow assignment adds to a maintainer's workload" default:"0.25"`
+
+	bot                bool
 	ghClient           *ghapi.GithubClient
-	maintainerWorkload map[string]int
-	reviewerWorkload   map[string]int
+	state              *ghapi.ActionState
+	maintainerWorkload map[string]float64
+	reviewerWorkload   map[string]float64
+	numShadows         int
+	shadowWeight       float64
+	completedWeight    float64
+}
+
+// ReviewersPlan is the set of maintainers, shadow maintainers and reviewers
+// which should be assigned to a pull request, together with the labels which
+// mark the shadow maintainers.
+type ReviewersPlan struct {
+	Org               string   `json:"org"`
+	Repo              string   `json:"repo"`
+	PullRequest       int      `json:"pull_request"`
+	Maintainers       []string `json:"maintainers,omitempty"`
+	ShadowMaintainers []string `json:"shadow_maintainers,omitempty"`
+	Labels            []string `json:"labels,omitempty"`
+	Reviewers         []string `json:"reviewers,omitempty"`
+
+	// Bot is set when the pull request is an automated dependency update.
+	Bot bool `json:"bot,omitempty"`
+
+	// Freeze is the message which announces the freeze of the repository on
+	// the pull request, if it has not been announced before.
+	Freeze string `json:"freeze,omitempty"`
+
+	// OptOuts are the commands with which users have opted out of the pull
+	// request since it was last synchronised, see OptOutCommands.
+	OptOuts []*OptOut `json:"opt_outs,omitempty"`
 }
 
 func NewReviewers() *cobra.Command {
-	cmd, err := cmdfactory.New(&Reviewers{}, cobra.Command{
-		Use:   "reviewers [OPTIONS] ORG/REPO/PRID",
+	cmd, err := cmdutils.New(&Reviewers{}, cobra.Command{
+		Use:   "reviewers [OPTIONS] ORG/REPO/PRID|--all [REPO]",
 		Short: "Synchronise a pull request's assignees (maintainers) and reviewers",
-		Args:  cobra.MaximumNArgs(2),
+		Long: heredoc.Doc(`
+		Synchronise a pull request's assignees (maintainers) and reviewers.
+
+		Assignees and requested reviewers can opt out of a pull request by
+		commenting /unassign-me, which removes them from every role they hold,
+		or, as a reviewer, /reassign-reviewer.  They are replaced with the
+		least busy maintainer or reviewer of the owning teams the next time the
+		pull request is synchronised.
+		`),
+		Args: cobra.MaximumNArgs(2),
+		Example: heredoc.Doc(`
+		# Synchronise the assignees and reviewers of a single pull request
+		governctl pr sync reviewers unikraft/unikraft/1078
+
+		# Synchronise every open pull request of a repository
+		governctl pr sync reviewers --all unikraft
+
+		# Synchronise every open pull request of every repository of the repos
+		# definition directory
+		governctl pr sync reviewers --all --github-org=unikraft
+		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "pr",
 		},
@@ -51,37 +110,51 @@ func NewReviewers() *cobra.Command {
 		panic(err)
 	}
 
+	cmdutils.DeprecateOrg(cmd)
+
 	return cmd
 }
 
+// Validate rejects unknown output formats of the plan and negative numbers of
+// maintainers, reviewers and shadow maintainers.
+func (opts *Reviewers) Validate(ctx context.Context) error {
+	if err := cmdutils.ValidatePlanOutput(ctx, opts.Output); err != nil {
+		return err
+	}
+
+	if err := config.NotNegative("num-maintainers", opts.NumMaintainers); err != nil {
+		return err
+	}
+
+	if err := config.NotNegative("num-reviewers", opts.NumReviewers); err != nil {
+		return err
+	}
+
+	return config.NotNegative("num-shadow-maintainers", opts.NumShadowMaintainers)
+}
+
 func (opts *Reviewers) Run(ctx context.Context, args []string) error {
-	var err error
+	opts.Org = kitcfg.G[config.Config](ctx).EffectiveGithubOrg(opts.Org)
 
-	opts.ghClient, err = ghapi.NewGithubClient(
-		ctx,
-		kitcfg.G[config.Config](ctx).GithubToken,
-		kitcfg.G[config.Config](ctx).GithubSkipSSL,
-		kitcfg.G[config.Config](ctx).GithubEndpoint,
-	)
+	ghClient, err := cmdutils.NewGithubClient(ctx)
 	if err != nil {
 		return err
 	}
 
+	if opts.All {
+		return opts.runAll(ctx, ghClient, args)
+	}
+
 	ghOrg, ghRepo, ghPrId, err := cmdutils.ParseOrgRepoAndPullRequestArgs(args)
 	if err != nil {
 		return err
 	}
 
-	repos, err := repo.NewListOfReposFromPath(
-		opts.ghClient,
-		ghOrg,
-		kitcfg.G[config.Config](ctx).ReposDir,
-	)
-	if err != nil {
-		return fmt.Errorf("could not populate repos: %w", err)
-	}
+	log.G(ctx).
+		WithField("pr_id", ghPrId).
+		Info("getting pull request details")
 
-	pr, err := opts.ghClient.GetPullRequest(ctx, ghRepo, ghRepo, ghPrId)
+	pr, err := ghClient.GetPullRequest(ctx, ghOrg, ghRepo, ghPrId)
 	if err != nil {
 		return fmt.Errorf("could not get pull request")
 	}
@@ -90,44 +163,286 @@ func (opts *Reviewers) Run(ctx context.Context, args []string) error {
 		return fmt.Errorf("pull request is closed")
 	}
 
-	ghOrigin := fmt.Sprintf("https://github.com/%s/%s.git", ghOrg, ghRepo)
+	tempDir, cleanup, err := TempDir(ctx, "governctl-pr-sync-reviewers-*")
+	if err != nil {
+		return err
+	}
+
+	defer cleanup()
+
+	// The local copy of the repo is used when checking CODEOWNERS
+	localRepo, err := LocalRepo(ctx, tempDir, ghOrg, ghRepo)
+	if err != nil {
+		return err
+	}
+
+	plan, err := opts.syncPullRequest(ctx, ghClient, ghOrg, ghRepo, pr, tempDir, localRepo, nil)
+	if err != nil {
+		return err
+	}
+
+	return cmdutils.WritePlan(ctx, opts.Output, plan)
+}
+
+// runAll synchronises every open pull request of the repository provided as
+// the only argument, or of every repository of the repos definition directory
+// if none is provided.  A failure to synchronise one pull request does not
+// prevent the remaining ones from being synchronised and pull requests which
+// are too large to evaluate are skipped.
+func (opts *Reviewers) runAll(ctx context.Context, ghClient *ghapi.GithubClient, args []string) error {
+	var repos []string
+
+	switch len(args) {
+	case 0:
+		all, err := repo.NewListOfReposFromPath(ghClient, opts.Org, kitcfg.G[config.Config](ctx).ReposDir)
+		if err != nil {
+			return fmt.Errorf("could not populate repos: %w", err)
+		}
+
+		for _, r := range all {
+			repos = append(repos, r.Fullname())
+		}
+	case 1:
+		repos = args
+	default:
+		return fmt.Errorf("--all accepts at most a single REPO")
+	}
+
+	plans := make([]*ReviewersPlan, 0)
+	var errs []error
+	var total int
+
+	for _, ghRepo := range repos {
+		logger := log.G(ctx).WithField("repo", ghRepo)
+
+		prs, err := ghClient.ListOpenPullRequests(ctx, opts.Org, ghRepo)
+		if err != nil {
+			logger.Errorf("could not list open pull requests: %s", err)
+			errs = append(errs, fmt.Errorf("%s: %w", ghRepo, err))
+			continue
+		}
+
+		if len(prs) == 0 {
+			logger.Info("no open pull requests")
+			continue
+		}
+
+		total += len(prs)
+
+		repoPlans, repoErrs := opts.syncRepository(ctx, ghClient, ghRepo, prs)
+		plans = append(plans, repoPlans...)
+		errs = append(errs, repoErrs...)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf(
+			"could not synchronise %d of %d pull requests: %w",
+			len(errs),
+			total,
+			errors.Join(errs...),
+		)
+	}
+
+	return cmdutils.WritePlan(ctx, opts.Output, plans)
+}
+
+// syncRepository synchronises the provided open pull requests of a single
+// repository, which is only cloned and whose ownership is only determined
+// once, and returns the plans and errors of every pull request.
+func (opts *Reviewers) syncRepository(ctx context.Context, ghClient *ghapi.GithubClient, ghRepo string, prs []*github.PullRequest) ([]*ReviewersPlan, []error) {
+	tempDir, cleanup, err := TempDir(ctx, "governctl-pr-sync-reviewers-*")
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", ghRepo, err)}
+	}
+
+	defer cleanup()
+
+	localRepo, err := LocalRepo(ctx, tempDir, opts.Org, ghRepo)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", ghRepo, err)}
+	}
+
+	own, err := loadOwners(ctx, ghClient, opts.Org, ghRepo, localRepo)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", ghRepo, err)}
+	}
+
+	var plans []*ReviewersPlan
+	var errs []error
+
+	for i, pr := range prs {
+		logger := log.G(ctx).
+			WithField("repo", ghRepo).
+			WithField("pr_id", pr.GetNumber()).
+			WithField("progress", fmt.Sprintf("%d/%d", i+1, len(prs)))
+
+		plan, err := opts.syncPullRequest(ctx, ghClient, opts.Org, ghRepo, pr, tempDir, localRepo, own)
+		if errors.Is(err, ErrPullRequestTooLarge) {
+			logger.Warn(err)
+			continue
+		} else if err != nil {
+			logger.Errorf("could not synchronise reviewers: %s", err)
+			errs = append(errs, fmt.Errorf("%s#%d: %w", ghRepo, pr.GetNumber(), err))
+			continue
+		}
+
+		plans = append(plans, plan)
+	}
 
+	return plans, errs
+}
+
+// syncPullRequest assigns maintainers and reviewers to a single pull request
+// of the repository which has been cloned to localRepo and records the
+// applied actions.  The owners of the repository are read from localRepo if
+// they are not provided.
+func (opts *Reviewers) syncPullRequest(ctx context.Context, ghClient *ghapi.GithubClient, ghOrg, ghRepo string, pr *github.PullRequest, tempDir, localRepo string, own *owners) (*ReviewersPlan, error) {
+	log.G(ctx).Info("retrieving list of modified files")
+
+	files, err := ChangedFiles(ctx, ghClient, ghOrg, ghRepo, pr, tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := LoadState(ctx, ghClient, ghOrg, ghRepo, pr.GetNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := opts.apply(ctx, ghClient, state, ghOrg, ghRepo, pr, localRepo, own, files)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := SaveState(ctx, state); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// Apply assigns maintainers and reviewers to the pull request based on the
+// teams which own the files which the pull request changes and on the current
+// workload of every maintainer and reviewer.  Automated dependency updates are
+// skipped, or only receive a single maintainer if BotsNeedMaintainer is set.
+// Maintainers, shadow maintainers and reviewers are only assigned once
+// according to the state, such that they are not re-assigned after having been
+// removed by hand.  It returns the assignments, which are only planned and not
+// performed in dry-run mode.
+func (opts *Reviewers) Apply(ctx context.Context, ghClient *ghapi.GithubClient, state *ghapi.ActionState, ghOrg, ghRepo string, pr *github.PullRequest, localRepo string, files []string) (*ReviewersPlan, error) {
+	return opts.apply(ctx, ghClient, state, ghOrg, ghRepo, pr, localRepo, nil, files)
+}
+
+// owners are the teams of the organisation together with the index of which of
+// them own the files of a repository.
+type owners struct {
+	teams []*team.Team
+	index *ownership.Index
+
+	// codeowners is the path of the CODEOWNERS file of the repository, if any.
+	codeowners string
+}
+
+// loadOwners reads the teams of the organisation and the CODEOWNERS of the
+// repository which has been cloned to localRepo, if it has one.
+func loadOwners(ctx context.Context, ghClient *ghapi.GithubClient, ghOrg, ghRepo, localRepo string) (*owners, error) {
 	teams, err := team.NewListOfTeamsFromPath(
-		opts.ghClient,
+		ghClient,
 		ghOrg,
 		kitcfg.G[config.Config](ctx).TeamsDir,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	opts.maintainerWorkload = make(map[string]int)
-	opts.reviewerWorkload = make(map[string]int)
+	// Does this repository use CODEOWNERS? If so, the teams are additionally
+	// determined based on the changed files.
+	var idxOpts []ownership.IndexOption
+	co, location, err := ownership.FindCodeowners(localRepo)
+	if err == nil {
+		log.G(ctx).
+			WithField("path", location).
+			Info("parsing repository CODEOWNERS")
+		idxOpts = append(idxOpts, ownership.WithCodeowners(co))
+	} else if !errors.Is(err, ownership.ErrNoCodeowners) {
+		return nil, err
+	}
 
-	log.G(ctx).Info("reversing the relationship between teams and organization repos")
+	idx, err := ownership.NewIndex(ghRepo, teams, idxOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not build ownership index: %w", err)
+	}
 
-	teamMap := make(map[string]*team.Team)
+	return &owners{
+		teams:      teams,
+		index:      idx,
+		codeowners: location,
+	}, nil
+}
 
-	for _, t := range teams {
-		for _, r := range t.Repositories {
-			// Only select teams that are responsible for the input repository.
-			if !r.NameEquals(ghRepo) {
-				continue
-			}
+// apply is Apply with the owners of the repository, which are read from
+// localRepo if they are not provided.
+func (opts *Reviewers) apply(ctx context.Context, ghClient *ghapi.GithubClient, state *ghapi.ActionState, ghOrg, ghRepo string, pr *github.PullRequest, localRepo string, own *owners, files []string) (*ReviewersPlan, error) {
+	var err error
 
-			if _, ok := teamMap[r.Fullname()]; ok {
-				teamMap[t.Fullname()] = t
-			} else {
-				// Use thhe the initialised repository
-				r2 := repo.FindRepoByName(r.Fullname(), repos)
-				if r2 != nil {
-					r = *r2
-				}
+	opts.ghClient = ghClient
+	opts.state = state
+	ghPrId := pr.GetNumber()
+	ctx = ghapi.WithOperation(ghapi.WithAPIUsagePullRequest(ctx, ghOrg, ghRepo, ghPrId), ghapi.OperationReviewers)
 
-				teamMap[r.Fullname()] = t
-			}
+	opts.bot = ghpr.IsBotPullRequest(pr, opts.BotLogins, opts.BotLabels)
+	if opts.bot && !opts.BotsNeedMaintainer {
+		log.G(ctx).
+			WithField("author", pr.GetUser().GetLogin()).
+			WithField("pr_id", ghPrId).
+			Info("skipping assignment of bot pull request")
+
+		return &ReviewersPlan{
+			Org:         ghOrg,
+			Repo:        ghRepo,
+			PullRequest: ghPrId,
+			Bot:         true,
+		}, nil
+	}
+
+	opts.shadowWeight = DefaultShadowWeight
+	if opts.ShadowWeight != "" {
+		opts.shadowWeight, err = strconv.ParseFloat(opts.ShadowWeight, 64)
+		if err != nil || opts.shadowWeight < 0 || opts.shadowWeight > 1 {
+			return nil, fmt.Errorf("invalid shadow weight '%s': expected a fraction between 0 and 1", opts.ShadowWeight)
+		}
+	}
+
+	opts.completedWeight = DefaultCompletedWeight
+	if opts.CompletedWeight != "" {
+		opts.completedWeight, err = strconv.ParseFloat(opts.CompletedWeight, 64)
+		if err != nil || opts.completedWeight < 0 || opts.completedWeight > 1 {
+			return nil, fmt.Errorf("invalid completed weight '%s': expected a fraction between 0 and 1", opts.CompletedWeight)
+		}
+	}
+
+	definition := repoDefinition(ctx, ghOrg, ghRepo)
+
+	opts.numShadows = opts.NumShadowMaintainers
+	if opts.numShadows == 0 && definition != nil {
+		opts.numShadows = definition.NumShadowMaintainers
+	}
+
+	// Bot pull requests only ever receive a single maintainer.
+	if opts.bot {
+		opts.numShadows = 0
+	}
+
+	if own == nil {
+		if own, err = loadOwners(ctx, opts.ghClient, ghOrg, ghRepo, localRepo); err != nil {
+			return nil, err
 		}
+	}
 
+	opts.maintainerWorkload = make(map[string]float64)
+	opts.reviewerWorkload = make(map[string]float64)
+
+	for _, t := range own.teams {
 		// Populate global lists of workloads for both maintainers and reviewers
 		for _, m := range t.Maintainers {
 			if _, ok := opts.maintainerWorkload[m.Github]; !ok {
@@ -150,7 +465,11 @@ func (opts *Reviewers) Run(ctx context.Context, args []string) error {
 		ghRepo,
 	)
 	if err != nil {
-		return fmt.Errorf("could not retrieve pull requests: %w", err)
+		return nil, fmt.Errorf("could not retrieve pull requests: %w", err)
+	}
+
+	if err := ensureWorkloadBudget(ctx, opts.ghClient, len(prs)); err != nil {
+		return nil, err
 	}
 
 	for _, pr := range prs {
@@ -165,15 +484,7 @@ func (opts *Reviewers) Run(ctx context.Context, args []string) error {
 			*pr.Number,
 		)
 		if err != nil {
-			return fmt.Errorf("could not get maintainers on pull requests: %w", err)
-		}
-
-		for _, maintainer := range maintainers {
-			if _, ok := opts.maintainerWorkload[maintainer]; !ok {
-				opts.maintainerWorkload[maintainer] = 0
-			}
-
-			opts.maintainerWorkload[maintainer]++
+			return nil, fmt.Errorf("could not get maintainers on pull requests: %w", err)
 		}
 
 		reviewers, err := opts.ghClient.GetReviewersOnPr(
@@ -183,20 +494,44 @@ func (opts *Reviewers) Run(ctx context.Context, args []string) error {
 			*pr.Number,
 		)
 		if err != nil {
-			return fmt.Errorf("could not get reviewers on pull requests: %w", err)
+			return nil, fmt.Errorf("could not get reviewers on pull requests: %w", err)
 		}
 
-		for _, reviewer := range reviewers {
-			if _, ok := opts.reviewerWorkload[reviewer]; !ok {
-				opts.reviewerWorkload[reviewer] = 0
+		// Users who have already reviewed the latest commit are done with the
+		// pull request until it is updated.
+		var completed []string
+		if len(maintainers) > 0 || len(reviewers) > 0 {
+			reviews, err := opts.ghClient.ListPullRequestReviews(
+				ctx,
+				ghOrg,
+				ghRepo,
+				*pr.Number,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("could not get reviews on pull requests: %w", err)
 			}
 
-			opts.reviewerWorkload[reviewer]++
+			completed = completedReviewers(pr, reviews)
+		}
+
+		// Shadow assignments only count as a fraction of a full assignment.
+		shadows := shadowMaintainers(pr.Labels)
+
+		for _, maintainer := range maintainers {
+			opts.maintainerWorkload[maintainer] += completedWeight(maintainer, completed,
+				assignmentWeight(maintainer, shadows, opts.shadowWeight),
+				opts.completedWeight,
+			)
+		}
+
+		for _, reviewer := range reviewers {
+			opts.reviewerWorkload[reviewer] += completedWeight(reviewer, completed, 1, opts.completedWeight)
 		}
 
 		log.G(ctx).
 			WithField("reviewers", reviewers).
 			WithField("maintainers", maintainers).
+			WithField("completed", completed).
 			WithField("pr_id", *pr.Number).
 			Info("checked open pr")
 	}
@@ -215,113 +550,27 @@ func (opts *Reviewers) Run(ctx context.Context, args []string) error {
 			Info("workload")
 	}
 
-	log.G(ctx).
-		WithField("pr_id", ghPrId).
-		Info("getting pull request details")
-
-	localRepo := path.Join(kitcfg.G[config.Config](ctx).TempDir, ghRepo)
-
-	if os.Getenv("GITHUB_ACTIONS") == "yes" {
-		localRepo = os.Getenv("GITHUB_WORKSPACE")
-	}
-
-	// Check if we have a copy of the repo locally, we'll use it in the next
-	// step when checking CODEOWNERS
-	if _, err := os.Stat(localRepo); os.IsNotExist(err) {
+	// Explain why only the teams responsible for the repository are selected.
+	if !own.index.HasCodeowners() {
 		log.G(ctx).
-			WithField("from", ghOrigin).
-			WithField("to", localRepo).
-			Info("cloning git repository")
-		_, err := git.PlainClone(localRepo, false, &git.CloneOptions{
-			URL: ghOrigin,
-			Auth: &http.BasicAuth{
-				Username: kitcfg.G[config.Config](ctx).GithubUser,
-				Password: kitcfg.G[config.Config](ctx).GithubToken,
-			},
-		})
-		if err != nil {
-			return fmt.Errorf("could not clone repository: %w", err)
-		}
-	}
-
-	tempDir := kitcfg.G[config.Config](ctx).TempDir
-	if tempDir == "" {
-		tempDir, err = os.MkdirTemp("", "governctl-pr-sync-labels-*")
-		if err != nil {
-			return fmt.Errorf("could not create temporary directory: %w", err)
-		}
-
-		defer func() {
-			os.RemoveAll(tempDir)
-		}()
-	}
-
-	log.G(ctx).Info("retrieving list of modified files")
-
-	localDiffFile := path.Join(tempDir, fmt.Sprintf("%s-%d.diff", ghRepo, ghPrId))
-
-	if _, err := os.Stat(localDiffFile); os.IsNotExist(err) {
+			WithField("locations", ownership.CodeownersLocations).
+			Info("no CODEOWNERS found, selecting the teams of the repository")
+	} else if !own.index.MatchesCodeowners(files) {
 		log.G(ctx).
-			WithField("from", *pr.DiffURL).
-			WithField("to", localDiffFile).
-			Info("saving")
-
-		if err = utils.DownloadFile(localDiffFile, *pr.DiffURL); err != nil {
-			return fmt.Errorf("could not download pull request: %w", err)
-		}
-	}
-
-	d, err := os.ReadFile(localDiffFile)
-	if err != nil {
-		return fmt.Errorf("could not read diff file for request: %w", err)
-	}
-
-	log.G(ctx).Info("parsing diff")
-
-	diff, err := diffparser.Parse(string(d))
-	if err != nil {
-		return fmt.Errorf("could not parse diff from pull request: %w", err)
-	}
-
-	// Does this repository use CODEOWNERS? If so, determine the teams based on
-	// the changed file.
-	co, err := codeowners.NewCodeowners(localRepo)
-	if err == nil {
-		log.G(ctx).Info("parsing repository CODEOWNERS")
-
-		for _, f := range diff.Files {
-			var owners []string
-			if len(f.OrigName) > 0 {
-				owners = append(owners, co.Owners(f.OrigName)...)
-			}
-			if len(f.NewName) > 0 {
-				owners = append(owners, co.Owners(f.NewName)...)
-			}
-
-			for _, o := range owners {
-				codeTeam := team.FindTeamByName(o, teams)
-				if codeTeam == nil {
-					continue
-				}
-
-				// Add the team to the repository
-				if _, ok := teamMap[codeTeam.Fullname()]; !ok {
-					log.G(ctx).
-						WithField("team", codeTeam.Fullname()).
-						Info("adding extra team from CODEOWNERS...")
-
-					teamMap[codeTeam.Fullname()] = codeTeam
-				}
-			}
-		}
+			WithField("path", own.codeowners).
+			Info("CODEOWNERS found but no rule matched the changed files, selecting the teams of the repository")
 	}
 
 	var maintainers []string
 	var reviewers []string
 
-	// Go through all calculated teams and add memebers as potential
-	// candidates for reviewers and maintainers
-	for _, t := range teamMap {
+	// Go through all owning teams and add memebers as potential candidates for
+	// reviewers and maintainers
+	for _, t := range own.index.OwningTeams(files) {
+		log.G(ctx).
+			WithField("team", t.Name).
+			Info("owning team")
+
 		for _, m := range t.Maintainers {
 			// Don't add duplicates
 			if containsStr(maintainers, m.Github) {
@@ -351,53 +600,92 @@ func (opts *Reviewers) Run(ctx context.Context, args []string) error {
 		}
 	}
 
-	return opts.updatePrWithPossibleMaintainersAndReviewers(
+	// Users who have opted out are replaced and not considered again.
+	optOuts, optedOut, err := opts.processOptOuts(ctx, ghOrg, ghRepo, pr, maintainers, reviewers)
+	if err != nil {
+		return nil, fmt.Errorf("could not process opt-outs: %w", err)
+	}
+
+	plan, err := opts.updatePrWithPossibleMaintainersAndReviewers(
 		ctx,
 		ghOrg,
 		ghRepo,
 		ghPrId,
-		maintainers,
-		reviewers,
+		shadowMaintainers(pr.Labels),
+		subtractStr(maintainers, optedOut),
+		subtractStr(reviewers, optedOut),
 	)
-}
+	if err != nil {
+		return nil, err
+	}
 
-func (opts *Reviewers) popLeastStressedMaintainer(subset []string) string {
-	maintainers := make(map[string]int)
+	plan.OptOuts = optOuts
 
-	for _, username := range subset {
-		if _, ok := opts.maintainerWorkload[username]; !ok {
-			opts.maintainerWorkload[username] = 0
+	// Frozen repositories still have their pull requests reviewed, but their
+	// authors are told why they are not merged.
+	if definition != nil {
+		if plan.Freeze, err = noticeFreeze(ctx, opts.state, ghOrg, ghRepo, pr, definition.Freeze, time.Now()); err != nil {
+			return nil, fmt.Errorf("could not announce freeze: %w", err)
 		}
+	}
 
-		maintainers[username] = opts.maintainerWorkload[username]
+	return plan, nil
+}
+
+// repoDefinition returns the definition of the repository in the repos
+// definition directory, if any.
+func repoDefinition(ctx context.Context, org, name string) *repo.Repository {
+	reposDir := kitcfg.G[config.Config](ctx).ReposDir
+	if _, err := os.Stat(reposDir); err != nil {
+		return nil
 	}
 
-	sorted := pair.RankByWorkload(maintainers)
-	least := sorted[0].Key
-	opts.maintainerWorkload[least]++
+	repos, err := repo.NewListOfReposFromPath(nil, org, reposDir)
+	if err != nil {
+		log.G(ctx).Warnf("could not read repository definitions: %s", err)
+		return nil
+	}
 
-	return least
+	return repo.FindRepoByName(name, repos)
 }
 
-func (opts *Reviewers) popLeastStressedReviewer(subset []string) string {
-	reviewers := make(map[string]int)
+// popLeastStressed returns the user from the subset with the least workload
+// and increments their workload by the provided weight.
+func popLeastStressed(workload map[string]float64, subset []string, weight float64) string {
+	users := make(map[string]float64)
 
 	for _, username := range subset {
-		if _, ok := opts.reviewerWorkload[username]; !ok {
-			opts.reviewerWorkload[username] = 0
-		}
-
-		reviewers[username] = opts.reviewerWorkload[username]
+		users[username] = workload[username]
 	}
 
-	sorted := pair.RankByWorkload(reviewers)
-
+	sorted := pair.RankByWorkload(users)
 	least := sorted[0].Key
-	opts.reviewerWorkload[least]++
+	workload[least] += weight
+
 	return least
 }
 
-func (opts *Reviewers) updatePrWithPossibleMaintainersAndReviewers(ctx context.Context, org, repo string, prId int, possibleMaintainers []string, possibleReviewers []string) error {
+func (opts *Reviewers) popLeastStressedMaintainer(subset []string) string {
+	return popLeastStressed(opts.maintainerWorkload, subset, 1)
+}
+
+func (opts *Reviewers) popLeastStressedReviewer(subset []string) string {
+	return popLeastStressed(opts.reviewerWorkload, subset, 1)
+}
+
+func (opts *Reviewers) updatePrWithPossibleMaintainersAndReviewers(ctx context.Context, org, repo string, prId int, shadows, possibleMaintainers, possibleReviewers []string) (*ReviewersPlan, error) {
+	plan := &ReviewersPlan{
+		Org:         org,
+		Repo:        repo,
+		PullRequest: prId,
+		Bot:         opts.bot,
+	}
+
+	numMaintainers := opts.NumMaintainers
+	if opts.bot {
+		numMaintainers = 1
+	}
+
 	log.G(ctx).
 		WithField("repo", repo).
 		WithField("pr_id", prId).
@@ -406,20 +694,44 @@ func (opts *Reviewers) updatePrWithPossibleMaintainersAndReviewers(ctx context.C
 		Infof("assigning reviewer(s) and maintainer(s) to pull request...")
 
 	if len(possibleMaintainers) == 0 {
-		return fmt.Errorf("could not assign reviewers as none provided")
+		return nil, fmt.Errorf("could not assign reviewers as none provided")
 	}
-	if len(possibleReviewers) == 0 {
-		return fmt.Errorf("could not assign reviewers as none provided")
+	if len(possibleReviewers) == 0 && !opts.bot {
+		return nil, fmt.Errorf("could not assign reviewers as none provided")
 	}
 
-	maintainers, err := opts.ghClient.GetMaintainersOnPr(ctx, org, repo, prId)
+	assignees, err := opts.ghClient.GetMaintainersOnPr(ctx, org, repo, prId)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// Separate the primary maintainers from the shadow maintainers, who are
+	// only marked as such by a label and otherwise are regular assignees.
+	var maintainers []string
+	var existingShadows []string
+	for _, assignee := range assignees {
+		if containsStr(shadows, assignee) {
+			existingShadows = append(existingShadows, assignee)
+		} else {
+			maintainers = append(maintainers, assignee)
+		}
 	}
 
-	if len(maintainers) == 0 {
-		for i := 0; i < opts.NumMaintainers; i++ {
-			m := opts.popLeastStressedMaintainer(possibleMaintainers)
+	shadows = existingShadows
+
+	target := actionTarget(org, repo, prId)
+	maintainersKey := ghapi.ActionKey("assign", target, "maintainers")
+	shadowsKey := ghapi.ActionKey("assign", target, "shadow-maintainers")
+	reviewersKey := ghapi.ActionKey("review-request", target, "reviewers")
+
+	if len(maintainers) == 0 && !opts.state.Applied(maintainersKey) {
+		candidates := subtractStr(possibleMaintainers, shadows)
+		if len(candidates) == 0 {
+			candidates = possibleMaintainers
+		}
+
+		for i := 0; i < numMaintainers; i++ {
+			m := opts.popLeastStressedMaintainer(candidates)
 			maintainers = append(maintainers, m)
 
 			log.G(ctx).
@@ -427,17 +739,60 @@ func (opts *Reviewers) updatePrWithPossibleMaintainersAndReviewers(ctx context.C
 				Info("assigning maintainer")
 		}
 
+		plan.Maintainers = maintainers
+
 		if !kitcfg.G[config.Config](ctx).DryRun {
-			err := opts.ghClient.AddMaintainersToPr(ctx, org, repo, prId, maintainers)
+			result, err := opts.ghClient.AddMaintainersToPr(ctx, org, repo, prId, maintainers)
+			logAssignmentResult(ctx, "maintainers", result)
 			if err != nil {
-				return fmt.Errorf("could not add maintainers to repo=%s pr_id=%d: %s", repo, prId, err)
+				return nil, fmt.Errorf("could not add maintainers to repo=%s pr_id=%d: %w", repo, prId, err)
 			}
+
+			opts.state.Record(maintainersKey)
 		}
 	}
 
+	if len(shadows) < opts.numShadows && !opts.state.Applied(shadowsKey) {
+		added := opts.selectShadowMaintainers(
+			possibleMaintainers,
+			append(maintainers, shadows...),
+			opts.numShadows-len(shadows),
+		)
+
+		for _, s := range added {
+			log.G(ctx).
+				WithField("shadow_maintainer", s).
+				Info("assigning shadow maintainer")
+		}
+
+		var labels []string
+		for _, s := range added {
+			labels = append(labels, shadowLabel(s))
+		}
+
+		plan.ShadowMaintainers = added
+		plan.Labels = labels
+
+		if !kitcfg.G[config.Config](ctx).DryRun && len(added) > 0 {
+			result, err := opts.ghClient.AddMaintainersToPr(ctx, org, repo, prId, added)
+			logAssignmentResult(ctx, "shadow maintainers", result)
+			if err != nil {
+				return nil, fmt.Errorf("could not add shadow maintainers to repo=%s pr_id=%d: %w", repo, prId, err)
+			}
+
+			if err := opts.ghClient.AddLabelsToPr(ctx, org, repo, prId, labels); err != nil {
+				return nil, fmt.Errorf("could not mark shadow maintainers on repo=%s pr_id=%d: %w", repo, prId, err)
+			}
+
+			opts.state.Record(shadowsKey)
+		}
+
+		shadows = append(shadows, added...)
+	}
+
 	// Remove assigned maintainers from list of possible reviewers (in case there
 	// are any overlaps as we cannot have the same reviewer and approver).
-	for _, maintainer := range maintainers {
+	for _, maintainer := range append(maintainers, shadows...) {
 		for i, reviewer := range possibleReviewers {
 			if reviewer == maintainer {
 				possibleReviewers = append(possibleReviewers[:i], possibleReviewers[i+1:]...)
@@ -449,8 +804,14 @@ func (opts *Reviewers) updatePrWithPossibleMaintainersAndReviewers(ctx context.C
 		WithField("repo", repo).
 		WithField("pr_id", prId).
 		WithField("maintainers", maintainers).
+		WithField("shadow_maintainers", shadows).
 		Info("assigning maintainers")
 
+	// Bot pull requests are not assigned any reviewers.
+	if opts.bot {
+		return plan, nil
+	}
+
 	var reviewers []string
 
 	// Run a check to see if the PR has already received reviews
@@ -461,13 +822,13 @@ func (opts *Reviewers) updatePrWithPossibleMaintainersAndReviewers(ctx context.C
 
 	r, err = opts.ghClient.GetReviewersOnPr(ctx, org, repo, prId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(r) > 0 {
 		reviewers = append(reviewers, r...)
 	}
 
-	if len(reviewers) == 0 {
+	if len(reviewers) == 0 && !opts.state.Applied(reviewersKey) {
 		for i := len(reviewers); i < opts.NumReviewers; i++ {
 			r := opts.popLeastStressedReviewer(possibleReviewers)
 			reviewers = append(reviewers, r)
@@ -477,15 +838,50 @@ func (opts *Reviewers) updatePrWithPossibleMaintainersAndReviewers(ctx context.C
 				Info("assigning reviewer")
 		}
 
+		plan.Reviewers = reviewers
+
 		if !kitcfg.G[config.Config](ctx).DryRun && len(reviewers) > 0 {
-			err := opts.ghClient.AddReviewersToPr(ctx, org, repo, prId, reviewers)
+			result, err := opts.ghClient.AddReviewersToPr(ctx, org, repo, prId, reviewers)
+			logAssignmentResult(ctx, "reviewers", result)
 			if err != nil {
-				return fmt.Errorf("could not add reviewer: %w", err)
+				return nil, fmt.Errorf("could not add reviewer: %w", err)
 			}
+
+			opts.state.Record(reviewersKey)
 		}
 	}
 
-	return nil
+	return plan, nil
+}
+
+// logAssignmentResult outputs the breakdown of who was added, who was already
+// present and who could not be added to the pull request.
+func logAssignmentResult(ctx context.Context, kind string, result *ghapi.AssignmentResult) {
+	if result == nil {
+		return
+	}
+
+	var failed []string
+	for username := range result.Failed {
+		failed = append(failed, username)
+	}
+
+	log.G(ctx).
+		WithField("added", result.Added).
+		WithField("present", result.Present).
+		WithField("failed", failed).
+		Infof("assigned %s", kind)
+}
+
+// subtractStr returns the elements of a which are not in b.
+func subtractStr(a, b []string) []string {
+	var ret []string
+	for _, e := range a {
+		if !containsStr(b, e) {
+			ret = append(ret, e)
+		}
+	}
+	return ret
 }
 
 func containsStr(s []string, e string) bool {
@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+func TestReviewersBots(t *testing.T) {
+	tests := []struct {
+		name            string
+		author          string
+		labels          []string
+		needMaintainer  bool
+		wantBot         bool
+		wantMaintainers int
+		wantReviewers   []string
+		wantWrites      []string
+	}{
+		{
+			name:            "human",
+			author:          "author",
+			wantMaintainers: 2,
+			wantReviewers:   []string{"bob"},
+			wantWrites:      []string{"/issues/1/assignees", "/pulls/1/requested_reviewers"},
+		},
+		{
+			name:    "dependabot skipped",
+			author:  "dependabot[bot]",
+			wantBot: true,
+		},
+		{
+			name:    "dependencies label skipped",
+			author:  "author",
+			labels:  []string{"dependencies"},
+			wantBot: true,
+		},
+		{
+			name:            "renovate with single maintainer",
+			author:          "renovate[bot]",
+			needMaintainer:  true,
+			wantBot:         true,
+			wantMaintainers: 1,
+			wantWrites:      []string{"/issues/1/assignees"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var writes []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/unikraft/app-test")
+
+				if r.Method != http.MethodGet {
+					writes = append(writes, path)
+					fmt.Fprint(w, `{}`)
+					return
+				}
+
+				switch path {
+				case "/pulls/1/requested_reviewers":
+					fmt.Fprint(w, `{"users":[],"teams":[]}`)
+				case "/pulls/1":
+					fmt.Fprint(w, `{"number":1,"state":"open"}`)
+				default:
+					fmt.Fprint(w, `[]`)
+				}
+			}))
+			defer srv.Close()
+
+			teamsDir := filepath.Join(t.TempDir(), "teams")
+			writeFile(t, filepath.Join(teamsDir, "maintainers-boot.yaml"), `
+name: maintainers-boot
+maintainers:
+  - github: alice
+  - github: carol
+reviewers:
+  - github: bob
+repos:
+  - name: app-test
+`)
+
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{
+				GithubEndpoint: srv.URL,
+				TeamsDir:       teamsDir,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+			ghClient, err := ghapi.NewGithubClient(ctx, "token", false, srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pr := &github.PullRequest{
+				Number: github.Int(1),
+				State:  github.String("open"),
+				User:   &github.User{Login: github.String(tt.author)},
+			}
+			for _, label := range tt.labels {
+				pr.Labels = append(pr.Labels, &github.Label{Name: github.String(label)})
+			}
+
+			opts := &Reviewers{
+				BotsNeedMaintainer: tt.needMaintainer,
+				NumMaintainers:     2,
+				NumReviewers:       1,
+			}
+
+			plan, err := opts.Apply(ctx, ghClient, nil, "unikraft", "app-test", pr, t.TempDir(), []string{"lib/ukboot/boot.c"})
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			if plan.Bot != tt.wantBot {
+				t.Errorf("plan.Bot = %v, want %v", plan.Bot, tt.wantBot)
+			}
+
+			if len(plan.Maintainers) != tt.wantMaintainers {
+				t.Errorf("plan.Maintainers = %v, want %d maintainers", plan.Maintainers, tt.wantMaintainers)
+			}
+
+			if !reflect.DeepEqual(plan.Reviewers, tt.wantReviewers) {
+				t.Errorf("plan.Reviewers = %v, want %v", plan.Reviewers, tt.wantReviewers)
+			}
+
+			if !reflect.DeepEqual(writes, tt.wantWrites) {
+				t.Errorf("writes = %v, want %v", writes, tt.wantWrites)
+			}
+		})
+	}
+}
+
+// TestReviewersAll checks that --all synchronises every open pull request of
+// the repository and carries on after a pull request fails.  The teams are
+// only read once for the repository, such that removing them whilst the first
+// pull request is synchronised does not affect the remaining ones.
+func TestReviewersAll(t *testing.T) {
+	synced := make(map[int]bool)
+	teamsDir := filepath.Join(t.TempDir(), "teams")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/unikraft/app-test")
+
+		if r.Method != http.MethodGet {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+
+		var number int
+		switch {
+		case path == "/pulls":
+			fmt.Fprint(w, `[
+				{"number":1,"state":"open","user":{"login":"author"}},
+				{"number":2,"state":"open","user":{"login":"author"}},
+				{"number":3,"state":"open","user":{"login":"author"}}
+			]`)
+		case sscanf(path, "/issues/%d/comments", &number):
+			synced[number] = true
+			if number == 1 {
+				os.RemoveAll(teamsDir)
+			}
+			if number == 2 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprint(w, `[]`)
+		case sscanf(path, "/pulls/%d/requested_reviewers", &number):
+			fmt.Fprint(w, `{"users":[],"teams":[]}`)
+		case sscanf(path, "/pulls/%d", &number):
+			fmt.Fprintf(w, `{"number":%d,"state":"open"}`, number)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	defer srv.Close()
+
+	tempDir := t.TempDir()
+	writeFile(t, filepath.Join(tempDir, "app-test", "README.md"), "")
+
+	writeFile(t, filepath.Join(teamsDir, "maintainers-boot.yaml"), `
+name: maintainers-boot
+maintainers:
+  - github: alice
+reviewers:
+  - github: bob
+repos:
+  - name: app-test
+`)
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{
+		DryRun:            true,
+		GithubEndpoint:    srv.URL,
+		GithubMaxAttempts: 1,
+		TeamsDir:          teamsDir,
+		TempDir:           tempDir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+	ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+	opts := &Reviewers{
+		All:            true,
+		NumMaintainers: 1,
+		NumReviewers:   1,
+		Org:            "unikraft",
+		Output:         "json",
+	}
+
+	err = opts.Run(ctx, []string{"app-test"})
+	if err == nil || !strings.Contains(err.Error(), "could not synchronise 1 of 3 pull requests") || !strings.Contains(err.Error(), "app-test#2") {
+		t.Fatalf("Run() error = %v, want the failure of app-test#2", err)
+	}
+
+	if !synced[1] || !synced[2] || !synced[3] {
+		t.Errorf("synchronised %v, want every pull request", synced)
+	}
+
+	// The plan is only written once every pull request has been synchronised.
+	if out.Len() > 0 {
+		t.Errorf("unexpected plan despite failure: %s", out)
+	}
+
+	if err := opts.Run(ctx, []string{"app-test", "extra"}); err == nil {
+		t.Errorf("Run() expected error with more than a single REPO")
+	}
+}
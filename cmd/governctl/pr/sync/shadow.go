@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// ShadowLabelPrefix is the prefix of the label which marks an assignee of a
+// pull request as a shadow maintainer, i.e. a secondary maintainer who is kept
+// in the loop but who is not required to act.
+const ShadowLabelPrefix = "shadow/"
+
+// DefaultShadowWeight is the fraction of a full assignment which a shadow
+// assignment contributes to a maintainer's workload.
+const DefaultShadowWeight = 0.25
+
+// shadowLabel returns the label which marks the provided user as a shadow
+// maintainer.
+func shadowLabel(login string) string {
+	return ShadowLabelPrefix + login
+}
+
+// shadowMaintainers returns the users which are marked as shadow maintainers
+// by the provided labels of a pull request.
+func shadowMaintainers(labels []*github.Label) []string {
+	var shadows []string
+
+	for _, l := range labels {
+		if login, ok := strings.CutPrefix(l.GetName(), ShadowLabelPrefix); ok && login != "" {
+			shadows = append(shadows, login)
+		}
+	}
+
+	return shadows
+}
+
+// assignmentWeight returns the amount of workload which an assignment of the
+// provided user contributes, where shadow assignments only count as the
+// provided fraction of a full assignment.
+func assignmentWeight(login string, shadows []string, shadowWeight float64) float64 {
+	if containsStr(shadows, login) {
+		return shadowWeight
+	}
+
+	return 1
+}
+
+// selectShadowMaintainers picks up to num users from the candidates with the
+// least workload who are not already excluded, e.g. because they are already
+// assigned.  Each selection increments the user's workload by the shadow
+// weight.
+func (opts *Reviewers) selectShadowMaintainers(candidates, exclude []string, num int) []string {
+	var remaining []string
+	for _, c := range candidates {
+		if !containsStr(exclude, c) && !containsStr(remaining, c) {
+			remaining = append(remaining, c)
+		}
+	}
+
+	var selected []string
+	for len(selected) < num && len(remaining) > 0 {
+		s := popLeastStressed(opts.maintainerWorkload, remaining, opts.shadowWeight)
+		selected = append(selected, s)
+
+		for i, r := range remaining {
+			if r == s {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return selected
+}
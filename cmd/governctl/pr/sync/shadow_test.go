@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+)
+
+func TestShadowMaintainers(t *testing.T) {
+	labels := []*github.Label{
+		{Name: github.String("kind/bug")},
+		{Name: github.String(shadowLabel("alice"))},
+		{Name: github.String("shadow/")},
+		{Name: github.String(shadowLabel("bob"))},
+	}
+
+	want := []string{"alice", "bob"}
+	if got := shadowMaintainers(labels); !reflect.DeepEqual(got, want) {
+		t.Errorf("shadowMaintainers() = %v, want %v", got, want)
+	}
+}
+
+func TestAssignmentWeight(t *testing.T) {
+	shadows := []string{"alice"}
+
+	if got := assignmentWeight("alice", shadows, 0.25); got != 0.25 {
+		t.Errorf("assignmentWeight(shadow) = %v, want 0.25", got)
+	}
+	if got := assignmentWeight("bob", shadows, 0.25); got != 1 {
+		t.Errorf("assignmentWeight(primary) = %v, want 1", got)
+	}
+}
+
+func TestSelectShadowMaintainers(t *testing.T) {
+	tests := []struct {
+		name       string
+		workload   map[string]float64
+		candidates []string
+		exclude    []string
+		num        int
+		want       []string
+		wantLoad   map[string]float64
+	}{
+		{
+			name:       "least loaded first",
+			workload:   map[string]float64{"alice": 3, "bob": 1, "carol": 2},
+			candidates: []string{"alice", "bob", "carol"},
+			num:        2,
+			want:       []string{"bob", "carol"},
+			wantLoad:   map[string]float64{"alice": 3, "bob": 1.25, "carol": 2.25},
+		},
+		{
+			name:       "excludes primaries and existing shadows",
+			workload:   map[string]float64{"alice": 0, "bob": 0, "carol": 5},
+			candidates: []string{"alice", "bob", "carol"},
+			exclude:    []string{"alice", "bob"},
+			num:        1,
+			want:       []string{"carol"},
+			wantLoad:   map[string]float64{"alice": 0, "bob": 0, "carol": 5.25},
+		},
+		{
+			name:       "never selects the same user twice",
+			workload:   map[string]float64{},
+			candidates: []string{"alice", "alice"},
+			num:        2,
+			want:       []string{"alice"},
+			wantLoad:   map[string]float64{"alice": 0.25},
+		},
+		{
+			name:       "no candidates",
+			workload:   map[string]float64{},
+			candidates: []string{"alice"},
+			exclude:    []string{"alice"},
+			num:        1,
+			wantLoad:   map[string]float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Reviewers{
+				maintainerWorkload: tt.workload,
+				shadowWeight:       DefaultShadowWeight,
+			}
+
+			got := opts.selectShadowMaintainers(tt.candidates, tt.exclude, tt.num)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectShadowMaintainers() = %v, want %v", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(opts.maintainerWorkload, tt.wantLoad) {
+				t.Errorf("workload = %v, want %v", opts.maintainerWorkload, tt.wantLoad)
+			}
+		})
+	}
+}
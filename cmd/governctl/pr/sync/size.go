@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+// SizeLabelPrefix is the prefix of all labels which denote the size of a pull
+// request.
+const SizeLabelPrefix = "size/"
+
+// sizeThresholds are the upper bounds (exclusive) of changed lines for each
+// size label, in ascending order.  Anything larger is labelled size/XXL.
+var sizeThresholds = []struct {
+	label string
+	max   int
+}{
+	{"XS", 10},
+	{"S", 30},
+	{"M", 100},
+	{"L", 500},
+	{"XL", 1000},
+}
+
+type Size struct {
+	Output string `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+}
+
+// SizePlan is the set of size labels which should be added to and removed
+// from a pull request.
+type SizePlan struct {
+	Org         string   `json:"org"`
+	Repo        string   `json:"repo"`
+	PullRequest int      `json:"pull_request"`
+	Add         []string `json:"add,omitempty"`
+	Remove      []string `json:"remove,omitempty"`
+}
+
+func NewSize() *cobra.Command {
+	cmd, err := cmdutils.New(&Size{}, cobra.Command{
+		Use:   "size [OPTIONS] ORG/REPO/PRID",
+		Short: "Synchronise a pull request's size label",
+		Args:  cobra.MaximumNArgs(2),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "pr",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+// Validate rejects unknown output formats of the plan.
+func (opts *Size) Validate(ctx context.Context) error {
+	return cmdutils.ValidatePlanOutput(ctx, opts.Output)
+}
+
+func (opts *Size) Run(ctx context.Context, args []string) error {
+	ghOrg, ghRepo, ghPrId, err := cmdutils.ParseOrgRepoAndPullRequestArgs(args)
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	pr, err := ghClient.GetPullRequest(ctx, ghOrg, ghRepo, ghPrId)
+	if err != nil {
+		return fmt.Errorf("could not get pull request: %w", err)
+	}
+
+	state, err := LoadState(ctx, ghClient, ghOrg, ghRepo, ghPrId)
+	if err != nil {
+		return err
+	}
+
+	plan, err := opts.Apply(ctx, ghClient, state, ghOrg, ghRepo, pr)
+	if err != nil {
+		return err
+	}
+
+	if err := SaveState(ctx, state); err != nil {
+		return err
+	}
+
+	return cmdutils.WritePlan(ctx, opts.Output, plan)
+}
+
+// Apply sets the size label of the pull request based on the number of
+// changed lines and removes any other, outdated size labels.  Labels which
+// have been added or removed before according to the state are left alone.  It
+// returns the changes, which are only planned and not performed in dry-run
+// mode.
+func (opts *Size) Apply(ctx context.Context, ghClient *ghapi.GithubClient, state *ghapi.ActionState, ghOrg, ghRepo string, pr *github.PullRequest) (*SizePlan, error) {
+	ctx = ghapi.WithOperation(ghapi.WithAPIUsagePullRequest(ctx, ghOrg, ghRepo, pr.GetNumber()), ghapi.OperationSize)
+
+	want := sizeLabel(pr.GetAdditions() + pr.GetDeletions())
+	target := actionTarget(ghOrg, ghRepo, pr.GetNumber())
+	plan := &SizePlan{
+		Org:         ghOrg,
+		Repo:        ghRepo,
+		PullRequest: pr.GetNumber(),
+	}
+
+	var has bool
+	for _, l := range pr.Labels {
+		if !strings.HasPrefix(l.GetName(), SizeLabelPrefix) {
+			continue
+		}
+
+		if l.GetName() == want {
+			has = true
+		} else {
+			plan.Remove = append(plan.Remove, l.GetName())
+		}
+	}
+
+	var removeKeys []string
+	plan.Remove, removeKeys = skipApplied(state, "unlabel", target, plan.Remove)
+
+	if len(plan.Remove) > 0 {
+		log.G(ctx).
+			WithField("pr_id", pr.GetNumber()).
+			WithField("labels", plan.Remove).
+			Info("removing outdated size labels")
+
+		if !kitcfg.G[config.Config](ctx).DryRun {
+			if err := ghClient.RemovePullRequestLabels(ctx, ghOrg, ghRepo, pr.GetNumber(), plan.Remove); err != nil {
+				return nil, fmt.Errorf("could not remove size labels: %w", err)
+			}
+
+			state.Record(removeKeys...)
+		}
+	}
+
+	if has {
+		return plan, nil
+	}
+
+	var addKeys []string
+	if plan.Add, addKeys = skipApplied(state, "label", target, []string{want}); len(plan.Add) == 0 {
+		return plan, nil
+	}
+
+	log.G(ctx).
+		WithField("pr_id", pr.GetNumber()).
+		WithField("label", want).
+		Info("setting size label")
+
+	if !kitcfg.G[config.Config](ctx).DryRun {
+		if err := ghClient.AddLabelsToPr(ctx, ghOrg, ghRepo, pr.GetNumber(), plan.Add); err != nil {
+			return nil, fmt.Errorf("could not add size label: %w", err)
+		}
+
+		state.Record(addKeys...)
+	}
+
+	return plan, nil
+}
+
+// sizeLabel returns the size label for the provided number of changed lines.
+func sizeLabel(lines int) string {
+	for _, t := range sizeThresholds {
+		if lines < t.max {
+			return SizeLabelPrefix + t.label
+		}
+	}
+
+	return SizeLabelPrefix + "XXL"
+}
@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import "testing"
+
+func TestSizeLabel(t *testing.T) {
+	tests := []struct {
+		lines int
+		want  string
+	}{
+		{0, "size/XS"},
+		{9, "size/XS"},
+		{10, "size/S"},
+		{99, "size/M"},
+		{499, "size/L"},
+		{999, "size/XL"},
+		{1000, "size/XXL"},
+	}
+
+	for _, tt := range tests {
+		if got := sizeLabel(tt.lines); got != tt.want {
+			t.Errorf("sizeLabel(%d) = %s, want %s", tt.lines, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"context"
+	"fmt"
+
+	kitcfg "kraftkit.sh/config"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+// LoadState returns the actions which have already been applied to the pull
+// request by the configured GitHub user, such that they are not applied again
+// unless --force is set.
+func LoadState(ctx context.Context, ghClient *ghapi.GithubClient, ghOrg, ghRepo string, ghPrId int) (*ghapi.ActionState, error) {
+	state, err := ghClient.LoadActionState(
+		ctx,
+		ghOrg,
+		ghRepo,
+		ghPrId,
+		kitcfg.G[config.Config](ctx).GithubUser,
+		kitcfg.G[config.Config](ctx).Force,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not load applied actions: %w", err)
+	}
+
+	return state, nil
+}
+
+// SaveState records the actions which have been applied to the pull request.
+// Nothing is recorded in dry-run mode.
+func SaveState(ctx context.Context, state *ghapi.ActionState) error {
+	if kitcfg.G[config.Config](ctx).DryRun {
+		return nil
+	}
+
+	if err := state.Save(ctx); err != nil {
+		return fmt.Errorf("could not record applied actions: %w", err)
+	}
+
+	return nil
+}
+
+// actionTarget identifies the pull request as the target of an action.
+func actionTarget(ghOrg, ghRepo string, ghPrId int) string {
+	return fmt.Sprintf("%s/%s#%d", ghOrg, ghRepo, ghPrId)
+}
+
+// skipApplied returns the names for which the action has not been applied yet
+// together with their keys.
+func skipApplied(state *ghapi.ActionState, action, target string, names []string) ([]string, []string) {
+	var remaining, keys []string
+
+	for _, name := range names {
+		key := ghapi.ActionKey(action, target, name)
+		if state.Applied(key) {
+			continue
+		}
+
+		remaining = append(remaining, name)
+		keys = append(keys, key)
+	}
+
+	return remaining, keys
+}
@@ -11,12 +11,14 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"kraftkit.sh/cmdfactory"
+
+	"github.com/unikraft/governance/internal/cmdutils"
 )
 
 type Sync struct{}
 
 func New() *cobra.Command {
-	cmd, err := cmdfactory.New(&Sync{}, cobra.Command{
+	cmd, err := cmdutils.New(&Sync{}, cobra.Command{
 		Use:   "sync SUBCOMMAND",
 		Short: "Synchronise a pull request",
 		Annotations: map[string]string{
@@ -30,6 +32,7 @@ func New() *cobra.Command {
 
 	cmd.AddCommand(NewLabels())
 	cmd.AddCommand(NewReviewers())
+	cmd.AddCommand(NewSize())
 
 	return cmd
 }
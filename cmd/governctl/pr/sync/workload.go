@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+// DefaultCompletedWeight is the fraction of a full assignment which a pull
+// request contributes to the workload of a user who has already reviewed its
+// latest commit.
+const DefaultCompletedWeight = 0
+
+const (
+	// workloadRequests is the number of requests made for every open pull
+	// request when determining the workload, i.e. listing its maintainers,
+	// reviewers and reviews.
+	workloadRequests = 3
+
+	// assignmentRequests is a generous estimate of the number of requests made
+	// to assign maintainers and reviewers once the workload is known.
+	assignmentRequests = 10
+)
+
+// ensureWorkloadBudget checks that enough requests remain of the rate limit to
+// determine the workload across the provided number of open pull requests and
+// to assign maintainers and reviewers afterwards, such that the pull request
+// is not left partially assigned.  Failing to read the rate limit is only
+// logged as it should not prevent the assignment.
+func ensureWorkloadBudget(ctx context.Context, ghClient *ghapi.GithubClient, prs int) error {
+	needed := prs*workloadRequests + assignmentRequests
+
+	err := ghClient.EnsureBudget(ctx, needed)
+
+	var rerr *ghapi.InsufficientRateLimitError
+	if errors.As(err, &rerr) {
+		log.G(ctx).
+			WithField("needed", rerr.Needed).
+			WithField("remaining", rerr.Remaining).
+			WithField("reset", rerr.Reset).
+			Errorf("not enough of the rate limit remains to determine the workload across %d open pull requests, retry after the reset", prs)
+
+		return err
+	} else if err != nil {
+		log.G(ctx).Warnf("could not check rate limit before determining the workload: %s", err)
+	}
+
+	return nil
+}
+
+// completedReviewers returns the users who have submitted a review of the
+// head commit of the pull request, i.e. more recently than its latest commit,
+// and who are therefore done with it until it is updated.  Pending and
+// dismissed reviews are not complete.
+func completedReviewers(pr *github.PullRequest, reviews []*github.PullRequestReview) []string {
+	var users []string
+
+	head := pr.GetHead().GetSHA()
+	if head == "" {
+		return users
+	}
+
+	for _, review := range reviews {
+		switch strings.ToUpper(review.GetState()) {
+		case "PENDING", "DISMISSED":
+			continue
+		}
+
+		login := review.GetUser().GetLogin()
+		if review.GetCommitID() == head && login != "" && !containsStr(users, login) {
+			users = append(users, login)
+		}
+	}
+
+	return users
+}
+
+// completedWeight scales the weight of an assignment of the provided user by
+// the provided fraction if they have already completed their review.
+func completedWeight(login string, completed []string, weight, fraction float64) float64 {
+	if containsStr(completed, login) {
+		return weight * fraction
+	}
+
+	return weight
+}
@@ -0,0 +1,275 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+	kitcfg "kraftkit.sh/config"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+func TestCompletedReviewers(t *testing.T) {
+	pr := &github.PullRequest{Head: &github.PullRequestBranch{SHA: github.String("head")}}
+
+	review := func(login, state, commit string) *github.PullRequestReview {
+		return &github.PullRequestReview{
+			User:     &github.User{Login: github.String(login)},
+			State:    github.String(state),
+			CommitID: github.String(commit),
+		}
+	}
+
+	got := completedReviewers(pr, []*github.PullRequestReview{
+		review("alice", "APPROVED", "head"),
+		review("alice", "COMMENTED", "head"),
+		review("bob", "CHANGES_REQUESTED", "head"),
+		review("carol", "APPROVED", "old"),
+		review("dave", "PENDING", "head"),
+		review("erin", "DISMISSED", "head"),
+	})
+
+	if want := []string{"alice", "bob"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("completedReviewers() = %v, want %v", got, want)
+	}
+}
+
+func TestReviewersWorkloadCompleted(t *testing.T) {
+	tests := []struct {
+		name            string
+		completedWeight string
+		wantReviewers   []string
+	}{
+		{
+			// bob is requested on three pull requests but has already reviewed
+			// the latest commit of two of them, whereas dave has two untouched.
+			name:          "completed reviews do not count",
+			wantReviewers: []string{"bob"},
+		},
+		{
+			name:            "completed reviews count in full",
+			completedWeight: "1",
+			wantReviewers:   []string{"dave"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested := map[int]string{1: "bob", 2: "bob", 3: "bob", 4: "dave", 5: "dave"}
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/unikraft/app-test")
+
+				if r.Method != http.MethodGet {
+					fmt.Fprint(w, `{}`)
+					return
+				}
+
+				var number int
+				switch {
+				case path == "/pulls":
+					var pulls []string
+					for n := 1; n <= 5; n++ {
+						pulls = append(pulls, fmt.Sprintf(`{"number":%d,"state":"open","head":{"sha":"head%d"}}`, n, n))
+					}
+					fmt.Fprintf(w, "[%s]", strings.Join(pulls, ","))
+				case sscanf(path, "/pulls/%d/requested_reviewers", &number):
+					if login, ok := requested[number]; ok {
+						fmt.Fprintf(w, `{"users":[{"login":%q}],"teams":[]}`, login)
+					} else {
+						fmt.Fprint(w, `{"users":[],"teams":[]}`)
+					}
+				case sscanf(path, "/pulls/%d/reviews", &number):
+					if number == 1 || number == 2 {
+						fmt.Fprintf(w, `[{"user":{"login":"bob"},"state":"APPROVED","commit_id":"head%d"}]`, number)
+					} else {
+						fmt.Fprint(w, `[]`)
+					}
+				case sscanf(path, "/pulls/%d", &number):
+					fmt.Fprintf(w, `{"number":%d,"state":"open"}`, number)
+				default:
+					fmt.Fprint(w, `[]`)
+				}
+			}))
+			defer srv.Close()
+
+			teamsDir := filepath.Join(t.TempDir(), "teams")
+			writeFile(t, filepath.Join(teamsDir, "maintainers-boot.yaml"), `
+name: maintainers-boot
+maintainers:
+  - github: alice
+reviewers:
+  - github: bob
+  - github: dave
+repos:
+  - name: app-test
+`)
+
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{
+				DryRun:         true,
+				GithubEndpoint: srv.URL,
+				TeamsDir:       teamsDir,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+			ghClient, err := ghapi.NewGithubClient(ctx, "token", false, srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pr := &github.PullRequest{
+				Number: github.Int(6),
+				State:  github.String("open"),
+				User:   &github.User{Login: github.String("author")},
+			}
+
+			opts := &Reviewers{
+				CompletedWeight: tt.completedWeight,
+				NumMaintainers:  1,
+				NumReviewers:    1,
+			}
+
+			plan, err := opts.Apply(ctx, ghClient, nil, "unikraft", "app-test", pr, t.TempDir(), []string{"lib/ukboot/boot.c"})
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(plan.Reviewers, tt.wantReviewers) {
+				t.Errorf("plan.Reviewers = %v, want %v", plan.Reviewers, tt.wantReviewers)
+			}
+		})
+	}
+}
+
+// TestReviewersWorkloadRateLimit checks that the workload is not determined
+// when the rate limit would run out partway through.
+func TestReviewersWorkloadRateLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining int
+		wantErr   error
+	}{
+		{
+			name:      "enough remaining",
+			remaining: 5*workloadRequests + assignmentRequests,
+		},
+		{
+			name:      "too few remaining",
+			remaining: 5 * workloadRequests,
+			wantErr:   ghapi.ErrInsufficientRateLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var perPR int
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/unikraft/app-test")
+
+				if r.Method != http.MethodGet {
+					fmt.Fprint(w, `{}`)
+					return
+				}
+
+				var number int
+				switch {
+				case r.URL.Path == "/api/v3/rate_limit":
+					fmt.Fprintf(w, `{"resources":{"core":{"limit":5000,"remaining":%d,"reset":1714564800}}}`, tt.remaining)
+				case path == "/pulls":
+					var pulls []string
+					for n := 1; n <= 5; n++ {
+						pulls = append(pulls, fmt.Sprintf(`{"number":%d,"state":"open"}`, n))
+					}
+					fmt.Fprintf(w, "[%s]", strings.Join(pulls, ","))
+				case sscanf(path, "/pulls/%d/requested_reviewers", &number):
+					perPR++
+					fmt.Fprint(w, `{"users":[],"teams":[]}`)
+				case sscanf(path, "/pulls/%d", &number):
+					fmt.Fprintf(w, `{"number":%d,"state":"open"}`, number)
+				default:
+					fmt.Fprint(w, `[]`)
+				}
+			}))
+			defer srv.Close()
+
+			teamsDir := filepath.Join(t.TempDir(), "teams")
+			writeFile(t, filepath.Join(teamsDir, "maintainers-boot.yaml"), `
+name: maintainers-boot
+maintainers:
+  - github: alice
+reviewers:
+  - github: bob
+repos:
+  - name: app-test
+`)
+
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{
+				DryRun:         true,
+				GithubEndpoint: srv.URL,
+				TeamsDir:       teamsDir,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+			ghClient, err := ghapi.NewGithubClient(ctx, "token", false, srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pr := &github.PullRequest{
+				Number: github.Int(6),
+				State:  github.String("open"),
+				User:   &github.User{Login: github.String("author")},
+			}
+
+			opts := &Reviewers{
+				NumMaintainers: 1,
+				NumReviewers:   1,
+			}
+
+			_, err = opts.Apply(ctx, ghClient, nil, "unikraft", "app-test", pr, t.TempDir(), []string{"lib/ukboot/boot.c"})
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Apply() error = %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if perPR > 0 {
+				t.Errorf("determined the workload of %d pull requests despite the rate limit", perPR)
+			}
+		})
+	}
+}
+
+// sscanf returns whether the path matches the format exactly.
+func sscanf(path, format string, number *int) bool {
+	n, err := fmt.Sscanf(path, format, number)
+	return err == nil && n == 1 && fmt.Sprintf(format, *number) == path
+}
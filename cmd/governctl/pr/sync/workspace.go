@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package sync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+)
+
+// TempDir returns the configured temporary directory or, if unset, creates a
+// new one with the provided pattern.  The returned function removes the
+// directory again if it was created.
+func TempDir(ctx context.Context, pattern string) (string, func(), error) {
+	tempDir := kitcfg.G[config.Config](ctx).TempDir
+	if tempDir != "" {
+		return tempDir, func() {}, nil
+	}
+
+	tempDir, err := os.MkdirTemp("", pattern)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not create temporary directory: %w", err)
+	}
+
+	return tempDir, func() {
+		os.RemoveAll(tempDir)
+	}, nil
+}
+
+// LocalRepo returns the path to a local copy of the repository, which is
+// cloned into the temporary directory if it does not exist yet.  When running
+// in GitHub Actions, the workspace is used instead.
+func LocalRepo(ctx context.Context, tempDir, org, repo string) (string, error) {
+	localRepo := path.Join(tempDir, repo)
+
+	if cmdutils.InGithubActions() {
+		localRepo = os.Getenv("GITHUB_WORKSPACE")
+	}
+
+	if _, err := os.Stat(localRepo); os.IsNotExist(err) {
+		origin := fmt.Sprintf("https://github.com/%s/%s.git", org, repo)
+
+		log.G(ctx).
+			WithField("from", origin).
+			WithField("to", localRepo).
+			Info("cloning git repository")
+
+		if _, err := git.PlainClone(localRepo, false, &git.CloneOptions{
+			URL: origin,
+			Auth: &http.BasicAuth{
+				Username: kitcfg.G[config.Config](ctx).GithubUser,
+				Password: kitcfg.G[config.Config](ctx).GithubToken,
+			},
+		}); err != nil {
+			return "", fmt.Errorf("could not clone repository: %w", err)
+		}
+	}
+
+	return localRepo, nil
+}
@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package pr
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/google/go-github/v63/github"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/cmd/governctl/pr/sync"
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/comment"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/repo"
+)
+
+// welcomeKey identifies the welcome comment so that it is only left once.  It
+// is not derived from the message such that a changed template does not lead
+// to a second welcome.
+const welcomeKey = "welcome"
+
+type Triage struct {
+	BotLabels          []string `long:"bot-labels" env:"GOVERN_BOT_LABELS" usage:"Labels which mark a PR as an automated dependency update (default: dependencies)"`
+	BotLogins          []string `long:"bot-logins" env:"GOVERN_BOT_LOGINS" usage:"Authors whose PRs are automated dependency updates (default: dependabot[bot], renovate[bot])"`
+	BotsNeedMaintainer bool     `long:"bots-need-maintainer" env:"GOVERN_BOTS_NEED_MAINTAINER" usage:"Assign a single maintainer and no reviewers to automated dependency updates instead of skipping them"`
+	RepoLabelsDir      string   `long:"repo-labels-dir" env:"GOVERN_REPO_LABELS_DIR" usage:"Path to the labels definition directory within the repository." default:".github/labels"`
+	NoLabels           bool     `long:"no-labels" env:"GOVERN_NO_LABELS" usage:"Do not synchronise the pull request's labels"`
+	NoReviewers        bool     `long:"no-reviewers" env:"GOVERN_NO_REVIEWERS" usage:"Do not assign maintainers and reviewers"`
+	NoSize             bool     `long:"no-size" env:"GOVERN_NO_SIZE" usage:"Do not set the pull request's size label"`
+	NoWelcome          bool     `long:"no-welcome" env:"GOVERN_NO_WELCOME" usage:"Do not welcome first-time contributors"`
+	NumMaintainers     int      `long:"num-maintainers" short:"A" usage:"Number of maintainers for the PR" default:"1"`
+	NumReviewers       int      `long:"num-reviewers" short:"R" usage:"Number of reviewers for the PR" default:"1"`
+	Output             string   `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+	WelcomeMessage     string   `long:"welcome-message" env:"GOVERN_WELCOME_MESSAGE" usage:"Comment left on pull requests of first-time contributors instead of the welcome template"`
+}
+
+// TriagePlan is the combination of the changes of every triage step.  Steps
+// which are skipped are omitted.
+type TriagePlan struct {
+	Labels    *sync.LabelsPlan    `json:"labels,omitempty"`
+	Size      *sync.SizePlan      `json:"size,omitempty"`
+	Reviewers *sync.ReviewersPlan `json:"reviewers,omitempty"`
+	Welcome   *WelcomePlan        `json:"welcome,omitempty"`
+}
+
+// WelcomePlan is the comment which should be left on the pull request of a
+// first-time contributor.
+type WelcomePlan struct {
+	User    string `json:"user"`
+	Comment string `json:"comment"`
+}
+
+func NewTriage() *cobra.Command {
+	cmd, err := cmdutils.New(&Triage{}, cobra.Command{
+		Use:   "triage [OPTIONS] ORG/REPO/PRID",
+		Short: "Label, size, assign and welcome a new pull request",
+		Args:  cobra.MaximumNArgs(2),
+		Long: heredoc.Doc(`
+		Triage a new pull request by synchronising its labels, setting its size
+		label, assigning maintainers and reviewers and welcoming first-time
+		contributors.  The pull request, its list of changed files and the local
+		copy of the repository are retrieved once and shared by every step.
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "pr",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+// Validate rejects unknown output formats of the plan, skipping every step,
+// --welcome-message combined with --no-welcome and negative numbers of
+// maintainers and reviewers.
+func (opts *Triage) Validate(ctx context.Context) error {
+	if err := cmdutils.ValidatePlanOutput(ctx, opts.Output); err != nil {
+		return err
+	}
+
+	if opts.NoLabels && opts.NoReviewers && opts.NoSize && opts.NoWelcome {
+		return fmt.Errorf("nothing to do: --no-labels, --no-reviewers, --no-size and --no-welcome are all set")
+	}
+
+	if err := config.Exclusive("welcome-message", opts.WelcomeMessage != "", "no-welcome", opts.NoWelcome); err != nil {
+		return err
+	}
+
+	if err := config.NotNegative("num-maintainers", opts.NumMaintainers); err != nil {
+		return err
+	}
+
+	return config.NotNegative("num-reviewers", opts.NumReviewers)
+}
+
+func (opts *Triage) Run(ctx context.Context, args []string) error {
+	ghOrg, ghRepo, ghPrId, err := cmdutils.ParseOrgRepoAndPullRequestArgs(args)
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	pr, err := ghClient.GetPullRequest(ctx, ghOrg, ghRepo, ghPrId)
+	if err != nil {
+		return fmt.Errorf("could not get pull request: %w", err)
+	}
+
+	if pr.GetState() == "closed" {
+		return fmt.Errorf("pull request is closed")
+	}
+
+	state, err := sync.LoadState(ctx, ghClient, ghOrg, ghRepo, ghPrId)
+	if err != nil {
+		return err
+	}
+
+	var localRepo string
+	var files []string
+
+	if !opts.NoLabels || !opts.NoReviewers {
+		tempDir, cleanup, err := sync.TempDir(ctx, "governctl-pr-triage-*")
+		if err != nil {
+			return err
+		}
+
+		defer cleanup()
+
+		localRepo, err = sync.LocalRepo(ctx, tempDir, ghOrg, ghRepo)
+		if err != nil {
+			return err
+		}
+
+		files, err = sync.ChangedFiles(ctx, ghClient, ghOrg, ghRepo, pr, tempDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	plan := &TriagePlan{}
+
+	if !opts.NoLabels {
+		log.G(ctx).Info("synchronising labels")
+
+		labels := &sync.Labels{RepoLabelsDir: opts.RepoLabelsDir}
+		if plan.Labels, err = labels.Apply(ctx, ghClient, state, ghOrg, ghRepo, pr, localRepo, files); err != nil {
+			return fmt.Errorf("could not synchronise labels: %w", err)
+		}
+	}
+
+	if !opts.NoSize {
+		log.G(ctx).Info("synchronising size label")
+
+		if plan.Size, err = (&sync.Size{}).Apply(ctx, ghClient, state, ghOrg, ghRepo, pr); err != nil {
+			return fmt.Errorf("could not synchronise size label: %w", err)
+		}
+	}
+
+	if !opts.NoReviewers {
+		log.G(ctx).Info("assigning maintainers and reviewers")
+
+		reviewers := &sync.Reviewers{
+			BotLabels:          opts.BotLabels,
+			BotLogins:          opts.BotLogins,
+			BotsNeedMaintainer: opts.BotsNeedMaintainer,
+			NumMaintainers:     opts.NumMaintainers,
+			NumReviewers:       opts.NumReviewers,
+		}
+		if plan.Reviewers, err = reviewers.Apply(ctx, ghClient, state, ghOrg, ghRepo, pr, localRepo, files); err != nil {
+			return fmt.Errorf("could not assign maintainers and reviewers: %w", err)
+		}
+	}
+
+	if !opts.NoWelcome {
+		message := opts.WelcomeMessage
+		if message == "" {
+			message, err = comment.New(kitcfg.G[config.Config](ctx).TemplatesDir).Render(
+				comment.Welcome,
+				repoLanguage(ctx, ghOrg, ghRepo),
+				comment.WelcomeData{
+					Org:         ghOrg,
+					Repo:        ghRepo,
+					PullRequest: ghPrId,
+					User:        pr.GetUser().GetLogin(),
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("could not render welcome message: %w", err)
+			}
+		}
+
+		if plan.Welcome, err = welcome(ctx, state, pr, message); err != nil {
+			return fmt.Errorf("could not welcome contributor: %w", err)
+		}
+	}
+
+	if err := sync.SaveState(ctx, state); err != nil {
+		return err
+	}
+
+	return cmdutils.WritePlan(ctx, opts.Output, plan)
+}
+
+// repoLanguage returns the language of the comments of the repository as
+// configured in the repos definition directory, if any.
+func repoLanguage(ctx context.Context, org, name string) string {
+	reposDir := kitcfg.G[config.Config](ctx).ReposDir
+	if _, err := os.Stat(reposDir); err != nil {
+		return ""
+	}
+
+	repos, err := repo.NewListOfReposFromPath(nil, org, reposDir)
+	if err != nil {
+		log.G(ctx).Warnf("could not read repository definitions: %s", err)
+		return ""
+	}
+
+	if r := repo.FindRepoByName(name, repos); r != nil {
+		return r.Language
+	}
+
+	return ""
+}
+
+// isFirstTimer returns whether the pull request was opened by someone who has
+// not contributed to the repository before.
+func isFirstTimer(pr *github.PullRequest) bool {
+	switch pr.GetAuthorAssociation() {
+	case "FIRST_TIMER", "FIRST_TIME_CONTRIBUTOR":
+		return true
+	}
+
+	return false
+}
+
+// welcome leaves the provided message on the pull request if it was opened by
+// a first-time contributor and has not been welcomed before.  It returns the
+// comment, which is only planned and not left in dry-run mode, or nil if no
+// comment is necessary.
+func welcome(ctx context.Context, state *ghapi.ActionState, pr *github.PullRequest, message string) (*WelcomePlan, error) {
+	if !isFirstTimer(pr) {
+		return nil, nil
+	}
+
+	if state.Applied(welcomeKey) {
+		log.G(ctx).
+			WithField("pr_id", pr.GetNumber()).
+			Info("contributor has already been welcomed")
+		return nil, nil
+	}
+
+	log.G(ctx).
+		WithField("pr_id", pr.GetNumber()).
+		WithField("user", pr.GetUser().GetLogin()).
+		Info("welcoming first-time contributor")
+
+	plan := &WelcomePlan{
+		User:    pr.GetUser().GetLogin(),
+		Comment: ghapi.CommentMarker(welcomeKey) + "\n" + message,
+	}
+
+	if kitcfg.G[config.Config](ctx).DryRun {
+		return plan, nil
+	}
+
+	return plan, state.CreateComment(ctx, welcomeKey, message)
+}
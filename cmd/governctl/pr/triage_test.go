@@ -0,0 +1,344 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package pr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/cmd/governctl/pr/sync"
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+)
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTriageEnv sets up a fake GitHub API with a single pull request by a
+// first-time contributor, together with the local copy of its repository and
+// the teams which own it.  Comments left on the pull request are kept, all
+// other writes are only recorded.  It returns the writes which reached the
+// API, the context to run the command with and its standard output.
+func newTriageEnv(t *testing.T, dryRun bool) (map[string]int, context.Context, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv("GITHUB_ACTIONS", "")
+
+	writes := make(map[string]int)
+	var comments []*github.IssueComment
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/unikraft/app-test")
+
+		if r.Method != http.MethodGet {
+			body, _ := io.ReadAll(r.Body)
+			writes[fmt.Sprintf("%s %s %s", r.Method, path, strings.TrimSpace(string(body)))]++
+
+			switch {
+			case strings.HasSuffix(path, "/labels"):
+				fmt.Fprint(w, `[]`)
+			case path == "/issues/1/comments":
+				comment := &github.IssueComment{}
+				if err := json.Unmarshal(body, comment); err != nil {
+					t.Errorf("could not parse comment: %v", err)
+				}
+
+				comment.ID = github.Int64(int64(len(comments) + 1))
+				comment.User = &github.User{Login: github.String("unikraft-bot")}
+				comments = append(comments, comment)
+
+				json.NewEncoder(w).Encode(comment)
+			case strings.HasPrefix(path, "/issues/comments/"):
+				edit := &github.IssueComment{}
+				if err := json.Unmarshal(body, edit); err != nil {
+					t.Errorf("could not parse comment: %v", err)
+				}
+
+				for _, comment := range comments {
+					if fmt.Sprintf("/issues/comments/%d", comment.GetID()) == path {
+						comment.Body = edit.Body
+						json.NewEncoder(w).Encode(comment)
+					}
+				}
+			default:
+				fmt.Fprint(w, `{}`)
+			}
+			return
+		}
+
+		switch path {
+		case "/issues/1/comments":
+			json.NewEncoder(w).Encode(comments)
+		case "/pulls/1":
+			fmt.Fprint(w, `{
+				"number": 1,
+				"state": "open",
+				"title": "lib/ukboot: Fix boot",
+				"author_association": "FIRST_TIME_CONTRIBUTOR",
+				"additions": 5,
+				"deletions": 3,
+				"changed_files": 1,
+				"user": {"login": "author"}
+			}`)
+		case "/pulls/1/requested_reviewers":
+			fmt.Fprint(w, `{"users":[],"teams":[]}`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	tempDir := t.TempDir()
+	writeFile(t, filepath.Join(tempDir, "app-test", ".github", "labels", "labels.yaml"), `
+labels:
+  - name: area/boot
+    apply_on_pr_match_paths:
+      - "lib/ukboot/**"
+`)
+	writeFile(t, filepath.Join(tempDir, "app-test-1.diff"), strings.Join([]string{
+		"diff --git a/lib/ukboot/boot.c b/lib/ukboot/boot.c",
+		"index 0000001..0000002 100644",
+		"--- a/lib/ukboot/boot.c",
+		"+++ b/lib/ukboot/boot.c",
+		"@@ -1 +1 @@",
+		"-old",
+		"+new",
+		"",
+	}, "\n"))
+
+	teamsDir := filepath.Join(t.TempDir(), "teams")
+	writeFile(t, filepath.Join(teamsDir, "maintainers-boot.yaml"), `
+name: maintainers-boot
+maintainers:
+  - github: alice
+reviewers:
+  - github: bob
+repos:
+  - name: app-test
+`)
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{
+		DryRun:         dryRun,
+		GithubEndpoint: srv.URL,
+		GithubUser:     "unikraft-bot",
+		TeamsDir:       teamsDir,
+		TempDir:        tempDir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+	ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+	return writes, ctx, out
+}
+
+func TestTriage(t *testing.T) {
+	writes, ctx, _ := newTriageEnv(t, false)
+
+	opts := &Triage{
+		RepoLabelsDir:  ".github/labels",
+		NumMaintainers: 1,
+		NumReviewers:   1,
+		WelcomeMessage: "Welcome!",
+	}
+
+	if err := opts.Run(ctx, []string{"unikraft/app-test/1"}); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	if n := takeStateWrites(writes); n != 1 {
+		t.Errorf("expected the applied actions to be recorded once, got %d", n)
+	}
+
+	want := map[string]int{
+		`POST /issues/1/labels ["area/boot"]`:                                     1,
+		`POST /issues/1/labels ["size/XS"]`:                                       1,
+		`POST /issues/1/assignees {"assignees":["alice"]}`:                        1,
+		`POST /pulls/1/requested_reviewers {"reviewers":["bob"]}`:                 1,
+		`POST /issues/1/comments {"body":"<!-- governctl:welcome -->\nWelcome!"}`: 1,
+	}
+
+	if !reflect.DeepEqual(writes, want) {
+		t.Errorf("unexpected writes:\ngot:  %v\nwant: %v", writes, want)
+	}
+}
+
+// takeStateWrites removes the writes of the comment which records the applied
+// actions and returns their number.
+func takeStateWrites(writes map[string]int) int {
+	n := 0
+	for write, count := range writes {
+		if strings.Contains(write, "/comments") && strings.Contains(write, `<!-- governctl-state`) {
+			n += count
+			delete(writes, write)
+		}
+	}
+
+	return n
+}
+
+func TestTriageIdempotent(t *testing.T) {
+	writes, ctx, _ := newTriageEnv(t, false)
+
+	opts := &Triage{
+		RepoLabelsDir:  ".github/labels",
+		NumMaintainers: 1,
+		NumReviewers:   1,
+		WelcomeMessage: "Welcome!",
+	}
+
+	if err := opts.Run(ctx, []string{"unikraft/app-test/1"}); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	if len(writes) == 0 {
+		t.Fatal("expected the first run to write")
+	}
+
+	// The fake API does not reflect the labels and assignments, as if they had
+	// been removed by hand in the meantime, such that only the recorded state
+	// prevents them from being applied again.
+	clear(writes)
+
+	if err := opts.Run(ctx, []string{"unikraft/app-test/1"}); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	if len(writes) != 0 {
+		t.Errorf("expected no writes when run again, got: %v", writes)
+	}
+
+	kitcfg.G[config.Config](ctx).Force = true
+
+	if err := opts.Run(ctx, []string{"unikraft/app-test/1"}); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	if n := takeStateWrites(writes); n != 0 {
+		t.Errorf("expected the recorded state to be unchanged with --force, got %d writes", n)
+	}
+
+	if len(writes) != 5 {
+		t.Errorf("expected all actions to be applied again with --force, got: %v", writes)
+	}
+}
+
+func TestTriageDryRunPlan(t *testing.T) {
+	writes, ctx, out := newTriageEnv(t, true)
+
+	opts := &Triage{
+		RepoLabelsDir:  ".github/labels",
+		NumMaintainers: 1,
+		NumReviewers:   1,
+		Output:         cmdutils.PlanOutputJSON,
+		WelcomeMessage: "Welcome!",
+	}
+
+	if err := opts.Run(ctx, []string{"unikraft/app-test/1"}); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	if len(writes) != 0 {
+		t.Errorf("unexpected writes in dry-run: %v", writes)
+	}
+
+	var got TriagePlan
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("could not parse plan: %v\n%s", err, out.String())
+	}
+
+	// The plan must describe exactly the writes which TestTriage expects.
+	want := TriagePlan{
+		Labels: &sync.LabelsPlan{
+			Org:         "unikraft",
+			Repo:        "app-test",
+			PullRequest: 1,
+			Add:         []string{"area/boot"},
+		},
+		Size: &sync.SizePlan{
+			Org:         "unikraft",
+			Repo:        "app-test",
+			PullRequest: 1,
+			Add:         []string{"size/XS"},
+		},
+		Reviewers: &sync.ReviewersPlan{
+			Org:         "unikraft",
+			Repo:        "app-test",
+			PullRequest: 1,
+			Maintainers: []string{"alice"},
+			Reviewers:   []string{"bob"},
+		},
+		Welcome: &WelcomePlan{
+			User:    "author",
+			Comment: "<!-- governctl:welcome -->\nWelcome!",
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected plan:\n%s", out.String())
+	}
+}
+
+func TestTriageValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Triage
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			opts: Triage{NumMaintainers: 1, NumReviewers: 1},
+		},
+		{
+			name:    "every step disabled",
+			opts:    Triage{NoLabels: true, NoReviewers: true, NoSize: true, NoWelcome: true},
+			wantErr: true,
+		},
+		{
+			name:    "welcome message without welcome",
+			opts:    Triage{NoWelcome: true, WelcomeMessage: "Welcome!"},
+			wantErr: true,
+		},
+		{
+			name:    "negative num-reviewers",
+			opts:    Triage{NumReviewers: -1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package repo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/patch"
+	"github.com/unikraft/governance/internal/repo"
+	"github.com/unikraft/governance/internal/yamledit"
+)
+
+type Freeze struct {
+	AllowLabels []string `long:"allow-label" usage:"Label which exempts a pull request from the freeze (may be repeated)"`
+	From        string   `long:"from" usage:"First day of the freeze as YYYY-MM-DD (default: immediately)"`
+	Message     string   `long:"message" usage:"Message which explains the freeze to contributors"`
+	Org         string   `long:"org" usage:"Set the GitHub organisation of the repository (deprecated, use --github-org)"`
+	Output      string   `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+	PR          bool     `long:"pr" env:"GOVERN_PR" usage:"Open a pull request with the changes to the repository definitions"`
+	PRBase      string   `long:"pr-base" env:"GOVERN_PR_BASE" usage:"Base branch of the pull request opened with --pr" default:"main"`
+	PRRepo      string   `long:"pr-repo" env:"GOVERN_PR_REPO" usage:"Repository of the repository definitions which the pull request is opened against" default:"governance"`
+	Until       string   `long:"until" usage:"Last day of the freeze as YYYY-MM-DD (default: until lifted)"`
+}
+
+// FreezePlan is the change to the repository definitions which freezes a
+// repository.
+type FreezePlan struct {
+	Repo   string       `json:"repo"`
+	File   string       `json:"file"`
+	Freeze *repo.Freeze `json:"freeze"`
+	Diff   string       `json:"diff"`
+
+	// PullRequest is the URL of the pull request with the change if one was
+	// opened.
+	PullRequest string `json:"pull_request,omitempty"`
+}
+
+func NewFreeze() *cobra.Command {
+	cmd, err := cmdutils.New(&Freeze{}, cobra.Command{
+		Use:   "freeze [OPTIONS] REPO",
+		Short: "Freeze a repository such that only exempted pull requests are merged",
+		Args:  cobra.ExactArgs(1),
+		Long: heredoc.Doc(`
+		Set the freeze of a repository in the repos definition directory, e.g.
+		during the stabilisation of a release.  Whilst a repository is frozen,
+		pull requests are only merged if they carry one of the allowed labels and
+		their authors are told about the freeze when reviewers are assigned.
+
+		Any existing freeze of the repository is replaced.  The rest of the
+		definition, including its comments, is left untouched.
+		`),
+		Example: heredoc.Doc(`
+		# Preview freezing unikraft until the end of January
+		governctl --dry-run repo freeze --until 2024-01-31 --allow-label release-blocker unikraft
+
+		# Freeze unikraft and open a pull request with the change
+		governctl repo freeze --until 2024-01-31 --allow-label release-blocker --pr unikraft
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "repo",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	cmdutils.DeprecateOrg(cmd)
+
+	return cmd
+}
+
+// Validate rejects unknown output formats of the plan, a --pr which is either
+// combined with --dry-run or lacks its --pr-repo and an invalid freeze.
+func (opts *Freeze) Validate(ctx context.Context) error {
+	if err := cmdutils.ValidatePlanOutput(ctx, opts.Output); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("pr", opts.PR, "dry-run", kitcfg.G[config.Config](ctx).DryRun); err != nil {
+		return err
+	}
+
+	if err := config.Requires("pr", opts.PR, "pr-repo", opts.PRRepo != ""); err != nil {
+		return err
+	}
+
+	return opts.freeze().Validate()
+}
+
+// freeze returns the freeze described by the flags.  Without any dates, the
+// repository is frozen until the freeze is lifted.
+func (opts *Freeze) freeze() *repo.Freeze {
+	return &repo.Freeze{
+		Active:        opts.From == "" && opts.Until == "",
+		From:          opts.From,
+		Until:         opts.Until,
+		AllowedLabels: opts.AllowLabels,
+		Message:       opts.Message,
+	}
+}
+
+func (opts *Freeze) Run(ctx context.Context, args []string) error {
+	opts.Org = kitcfg.G[config.Config](ctx).EffectiveGithubOrg(opts.Org)
+
+	name := args[0]
+	dryRun := kitcfg.G[config.Config](ctx).DryRun
+	reposDir := kitcfg.G[config.Config](ctx).ReposDir
+
+	file, src, line, err := findDefinition(reposDir, name)
+	if err != nil {
+		return err
+	}
+
+	freeze := opts.freeze()
+
+	frozen, err := repo.SetFreeze(src, line, freeze)
+	if err != nil {
+		return err
+	}
+
+	// Ensure that the edit results in a valid definition before it is written.
+	var parsed []*repo.Repository
+	decoder := yaml.NewDecoder(bytes.NewReader(frozen))
+	for {
+		var r *repo.Repository
+		if err := decoder.Decode(&r); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("freezing repository in '%s' produced invalid YAML: %w", file, err)
+		}
+
+		if r != nil {
+			// Normalise names with a type prefix, e.g. "lib-lwip".
+			r.Fullname()
+			parsed = append(parsed, r)
+		}
+	}
+
+	if r := repo.FindRepoByName(name, parsed); r == nil || r.Freeze == nil {
+		return fmt.Errorf("freezing repository in '%s' did not take effect", file)
+	}
+
+	plan := &FreezePlan{
+		Repo:   name,
+		File:   file,
+		Freeze: freeze,
+		Diff:   yamledit.Diff(file, src, frozen),
+	}
+
+	if dryRun {
+		if opts.Output != cmdutils.PlanOutputJSON {
+			fmt.Fprint(iostreams.G(ctx).Out, plan.Diff)
+		}
+
+		return cmdutils.WritePlan(ctx, opts.Output, plan)
+	}
+
+	log.G(ctx).
+		WithField("file", file).
+		WithField("repo", name).
+		Info("freezing repository")
+
+	if err := os.WriteFile(file, frozen, 0o644); err != nil {
+		return fmt.Errorf("could not write repos file: %w", err)
+	}
+
+	if opts.PR {
+		if kitcfg.G[config.Config](ctx).ReadOnly {
+			return fmt.Errorf("cannot open pull request: %w", ghapi.ErrReadOnly)
+		}
+
+		if plan.PullRequest, err = opts.openPullRequest(ctx, reposDir, plan); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(iostreams.G(ctx).Out, "opened %s\n", plan.PullRequest)
+	}
+
+	return cmdutils.WritePlan(ctx, opts.Output, plan)
+}
+
+// findDefinition returns the file of the repos definition directory which
+// defines the repository, together with its contents and the line of the name
+// of the repository.
+func findDefinition(reposDir, name string) (string, []byte, int, error) {
+	fi, err := os.Stat(reposDir)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("could not read directory: %w", err)
+	}
+
+	var files []string
+	if fi.IsDir() {
+		entries, err := os.ReadDir(reposDir)
+		if err != nil {
+			return "", nil, 0, fmt.Errorf("could not read directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files = append(files, path.Join(reposDir, entry.Name()))
+			}
+		}
+	} else {
+		files = append(files, reposDir)
+	}
+
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return "", nil, 0, fmt.Errorf("could not read repos file: %w", err)
+		}
+
+		if line := repo.FindDefinition(src, name); line >= 0 {
+			return file, src, line, nil
+		}
+	}
+
+	return "", nil, 0, fmt.Errorf("repository '%s' is not defined in %s", name, reposDir)
+}
+
+// openPullRequest commits the change to the repository definitions onto a new
+// branch, pushes it and opens a pull request which describes the freeze.
+func (opts *Freeze) openPullRequest(ctx context.Context, reposDir string, plan *FreezePlan) (string, error) {
+	ghApi, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	gitBinary := kitcfg.G[config.Config](ctx).GitBinary
+	if gitBinary == "" {
+		gitBinary = patch.DefaultGitBinary
+	}
+
+	workdir := reposDir
+	if fi, err := os.Stat(reposDir); err == nil && !fi.IsDir() {
+		workdir = filepath.Dir(reposDir)
+	}
+
+	branch := fmt.Sprintf("freeze-%s", strings.ToLower(plan.Repo))
+	title := fmt.Sprintf("repos: Freeze %s", plan.Repo)
+
+	git := func(args ...string) error {
+		cmd := exec.CommandContext(ctx, gitBinary, append([]string{"-C", workdir}, args...)...)
+		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
+		cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
+		return cmd.Run()
+	}
+
+	if err := git("checkout", "-b", branch); err != nil {
+		return "", fmt.Errorf("could not create branch %s: %w", branch, err)
+	}
+
+	abs, err := filepath.Abs(plan.File)
+	if err != nil {
+		return "", err
+	}
+
+	if err := git("add", abs); err != nil {
+		return "", fmt.Errorf("could not stage repository definition: %w", err)
+	}
+
+	if err := git("commit", "--signoff", "-m", title); err != nil {
+		return "", fmt.Errorf("could not commit repository definition: %w", err)
+	}
+
+	if err := git("push", fmt.Sprintf("https://%s:%s@github.com/%s/%s.git",
+		kitcfg.G[config.Config](ctx).GithubUser,
+		kitcfg.G[config.Config](ctx).GithubToken,
+		opts.Org,
+		opts.PRRepo,
+	), branch); err != nil {
+		return "", fmt.Errorf("could not push branch %s: %w", branch, err)
+	}
+
+	body := fmt.Sprintf("%s\n\n```diff\n%s```\n", plan.Freeze.Notice(), plan.Diff)
+
+	pull, err := ghApi.CreatePullRequest(ctx, opts.Org, opts.PRRepo, branch, opts.PRBase, title, body)
+	if err != nil {
+		return "", fmt.Errorf("could not open pull request: %w", err)
+	}
+
+	return pull.GetHTMLURL(), nil
+}
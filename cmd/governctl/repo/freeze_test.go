@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package repo
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/repo"
+)
+
+const testRepos = `---
+name: unikraft
+type: core
+---
+# The network stack
+name: lib-lwip
+freeze:
+  until: "2023-07-31"
+num_shadow_maintainers: 1
+`
+
+func TestFreezeRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		repo    string
+		dryRun  bool
+		wantErr string
+		want    string
+	}{
+		{
+			name: "freeze",
+			repo: "unikraft",
+			want: `---
+name: unikraft
+type: core
+freeze:
+  until: "2024-01-31"
+  allowed_labels:
+  - release-blocker
+---
+# The network stack
+name: lib-lwip
+freeze:
+  until: "2023-07-31"
+num_shadow_maintainers: 1
+`,
+		},
+		{
+			name: "replace freeze",
+			repo: "lwip",
+			want: `---
+name: unikraft
+type: core
+---
+# The network stack
+name: lib-lwip
+freeze:
+  until: "2024-01-31"
+  allowed_labels:
+  - release-blocker
+num_shadow_maintainers: 1
+`,
+		},
+		{
+			name:   "dry-run",
+			repo:   "unikraft",
+			dryRun: true,
+			want:   testRepos,
+		},
+		{
+			name:    "undefined",
+			repo:    "app-helloworld",
+			wantErr: "repository 'app-helloworld' is not defined",
+			want:    testRepos,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "repos.yaml")
+			if err := os.WriteFile(file, []byte(testRepos), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{
+				DryRun:   tt.dryRun,
+				ReposDir: file,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out := &bytes.Buffer{}
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+			ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+			opts := &Freeze{
+				AllowLabels: []string{"release-blocker"},
+				Output:      "text",
+				Until:       "2024-01-31",
+			}
+
+			err = opts.Run(ctx, []string{tt.repo})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Run() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Run() unexpected error: %v", err)
+			}
+
+			got, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("repos file =\n%s\nwant:\n%s", got, tt.want)
+			}
+
+			if tt.dryRun && !strings.Contains(out.String(), `+  until: "2024-01-31"`) {
+				t.Errorf("dry-run output does not contain the diff:\n%s", out.String())
+			}
+
+			if tt.wantErr != "" || tt.dryRun {
+				return
+			}
+
+			repos, err := repo.NewListOfReposFromPath(nil, "unikraft", file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if r := repo.FindRepoByName(tt.repo, repos); r == nil || r.Freeze.Exemption([]string{"release-blocker"}) == "" {
+				t.Errorf("repository %s is not frozen with the allowed label", tt.repo)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package repo
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"kraftkit.sh/cmdfactory"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+)
+
+type Repo struct{}
+
+func New() *cobra.Command {
+	cmd, err := cmdutils.New(&Repo{}, cobra.Command{
+		Use:    "repo SUBCOMMAND",
+		Short:  "Manage repository definitions",
+		Hidden: true,
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "repo",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	cmd.AddCommand(NewFreeze())
+	cmd.AddCommand(NewSync())
+
+	return cmd
+}
+
+func (opts *Repo) Run(_ context.Context, args []string) error {
+	return pflag.ErrHelp
+}
@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/repo"
+	"github.com/unikraft/governance/internal/team"
+)
+
+type Sync struct {
+	Org    string `long:"org" usage:"Set the GitHub organisation of the repositories (deprecated, use --github-org)"`
+	Output string `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+	Prune  bool   `long:"prune" env:"GOVERN_PRUNE" usage:"Revoke the access of teams to repositories which is not declared by any team definition"`
+
+	ghApi *ghapi.GithubClient
+	repos []*repo.Repository
+	teams []*team.Team
+}
+
+// SyncPlan is the set of changes to the permissions of teams on every
+// repository.
+type SyncPlan struct {
+	Repos []*team.RepoPermissionChange `json:"repos"`
+}
+
+func NewSync() *cobra.Command {
+	cmd, err := cmdutils.New(&Sync{}, cobra.Command{
+		Use:   "sync",
+		Short: "Synchronise the permissions of teams on repositories",
+		Args:  cobra.NoArgs,
+		Long: heredoc.Doc(`
+		Grant every team which references a repository the permission declared
+		for it, either by the reference in the team definition or otherwise by
+		the repository definition.  The maintainers and reviewers sub-teams of a
+		team are granted the same permission.
+
+		Teams which have access to a repository without declaring it are
+		reported and, with --prune, their access is revoked.
+		`),
+		Example: heredoc.Doc(`
+		# Preview the changes to the permissions of every repository
+		governctl --dry-run repo sync
+
+		# Synchronise the permissions and revoke undeclared access
+		governctl repo sync --prune
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "repo",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	cmdutils.DeprecateOrg(cmd)
+
+	return cmd
+}
+
+// Validate rejects unknown output formats of the plan.
+func (opts *Sync) Validate(ctx context.Context) error {
+	return cmdutils.ValidatePlanOutput(ctx, opts.Output)
+}
+
+func (opts *Sync) Pre(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	opts.Org = kitcfg.G[config.Config](ctx).EffectiveGithubOrg(opts.Org)
+
+	// Auditing the changes does not modify any state.
+	if kitcfg.G[config.Config](ctx).ReadOnly && !kitcfg.G[config.Config](ctx).DryRun {
+		return fmt.Errorf("cannot synchronise repository permissions: %w", ghapi.ErrReadOnly)
+	}
+
+	ghApi, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts.ghApi = ghApi
+
+	opts.repos, err = repo.NewListOfReposFromPath(
+		ghApi,
+		opts.Org,
+		kitcfg.G[config.Config](ctx).ReposDir,
+	)
+	if err != nil {
+		return fmt.Errorf("could not populate repos: %s", err)
+	}
+
+	opts.teams, err = team.NewListOfTeamsFromPath(
+		ghApi,
+		opts.Org,
+		kitcfg.G[config.Config](ctx).TeamsDir,
+	)
+	if err != nil {
+		return fmt.Errorf("could not populate teams: %s", err)
+	}
+
+	return nil
+}
+
+func (opts *Sync) Run(ctx context.Context, args []string) error {
+	dryRun := kitcfg.G[config.Config](ctx).DryRun
+	plan := &SyncPlan{
+		Repos: make([]*team.RepoPermissionChange, 0, len(opts.repos)),
+	}
+
+	for _, r := range opts.repos {
+		change, err := opts.sync(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		plan.Repos = append(plan.Repos, change)
+	}
+
+	if dryRun && opts.Output != cmdutils.PlanOutputJSON {
+		writePermissionReport(iostreams.G(ctx).Out, plan.Repos, opts.Prune)
+	}
+
+	return cmdutils.WritePlan(ctx, opts.Output, plan)
+}
+
+// sync determines the changes to the permissions of the teams on the
+// repository and, unless in dry-run mode, performs them.  Undeclared access is
+// only revoked with --prune.
+func (opts *Sync) sync(ctx context.Context, r *repo.Repository) (*team.RepoPermissionChange, error) {
+	dryRun := kitcfg.G[config.Config](ctx).DryRun
+	name := fmt.Sprintf("%s/%s", opts.Org, r.Fullname())
+
+	existing, err := opts.ghApi.ListRepoTeams(ctx, opts.Org, r.Fullname())
+	if err != nil {
+		return nil, err
+	}
+
+	change := team.PlanRepoPermissions(opts.Org, r, opts.teams, existing)
+
+	if change.Empty() {
+		log.G(ctx).WithField("repo", name).Info("permissions up to date")
+		return change, nil
+	}
+
+	for _, grant := range change.Grant {
+		logger := log.G(ctx).
+			WithField("repo", name).
+			WithField("team", grant.Team).
+			WithField("permission", grant.Permission)
+
+		if dryRun {
+			logger.Info("would grant")
+			continue
+		}
+
+		logger.Info("granting")
+
+		if err := opts.ghApi.AddTeamRepoPermission(ctx, opts.Org, grant.Team, r.Fullname(), grant.Permission); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, grant := range change.Undeclared {
+		logger := log.G(ctx).
+			WithField("repo", name).
+			WithField("team", grant.Team).
+			WithField("permission", grant.Permission)
+
+		if !opts.Prune {
+			logger.Warn("undeclared access")
+			continue
+		}
+
+		if dryRun {
+			logger.Info("would revoke")
+			continue
+		}
+
+		logger.Info("revoking")
+
+		if err := opts.ghApi.RemoveTeamRepo(ctx, opts.Org, grant.Team, r.Fullname()); err != nil {
+			return nil, err
+		}
+	}
+
+	return change, nil
+}
+
+// writePermissionReport writes the changes to the permissions of the teams on
+// each repository to the writer, one line per team, such that they can be
+// reviewed before being applied.
+func writePermissionReport(w io.Writer, changes []*team.RepoPermissionChange, prune bool) {
+	for _, change := range changes {
+		if change.Empty() {
+			fmt.Fprintf(w, "%s/%s: up to date\n", change.Org, change.Repo)
+			continue
+		}
+
+		fmt.Fprintf(w, "%s/%s:\n", change.Org, change.Repo)
+
+		for _, grant := range change.Grant {
+			if grant.Current == "" {
+				fmt.Fprintf(w, "  + @%s/%s (%s)\n", change.Org, grant.Team, grant.Permission)
+			} else {
+				fmt.Fprintf(w, "  ~ @%s/%s (%s -> %s)\n", change.Org, grant.Team, grant.Current, grant.Permission)
+			}
+		}
+
+		for _, grant := range change.Undeclared {
+			if prune {
+				fmt.Fprintf(w, "  - @%s/%s (%s)\n", change.Org, grant.Team, grant.Permission)
+			} else {
+				fmt.Fprintf(w, "  ? @%s/%s (%s, undeclared)\n", change.Org, grant.Team, grant.Permission)
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package repo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/repo"
+	"github.com/unikraft/governance/internal/team"
+)
+
+func TestSyncRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		dryRun     bool
+		prune      bool
+		wantOut    string
+		wantWrites []string
+	}{
+		{
+			name: "apply",
+			wantWrites: []string{
+				`PUT /orgs/unikraft/teams/arch/repos/unikraft/unikraft {"permission":"push"}`,
+			},
+		},
+		{
+			name:  "apply with prune",
+			prune: true,
+			wantWrites: []string{
+				`PUT /orgs/unikraft/teams/arch/repos/unikraft/unikraft {"permission":"push"}`,
+				`DELETE /orgs/unikraft/teams/old/repos/unikraft/unikraft `,
+			},
+		},
+		{
+			name:   "dry-run",
+			dryRun: true,
+			wantOut: "unikraft/unikraft:\n" +
+				"  ~ @unikraft/arch (read -> write)\n" +
+				"  ? @unikraft/old (admin, undeclared)\n" +
+				"unikraft/lib-lwip: up to date\n",
+		},
+		{
+			name:   "dry-run with prune",
+			dryRun: true,
+			prune:  true,
+			wantOut: "unikraft/unikraft:\n" +
+				"  ~ @unikraft/arch (read -> write)\n" +
+				"  - @unikraft/old (admin)\n" +
+				"unikraft/lib-lwip: up to date\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var writes []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path := strings.TrimPrefix(r.URL.Path, "/api/v3")
+
+				if r.Method != http.MethodGet {
+					body, _ := io.ReadAll(r.Body)
+					writes = append(writes, fmt.Sprintf("%s %s %s", r.Method, path, strings.TrimSpace(string(body))))
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+
+				switch path {
+				case "/repos/unikraft/unikraft/teams":
+					fmt.Fprint(w, `[
+						{"slug":"arch","permission":"pull","permissions":{"pull":true}},
+						{"slug":"old","permission":"admin","permissions":{"admin":true,"push":true,"pull":true}}
+					]`)
+				default:
+					fmt.Fprint(w, `[]`)
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{
+				DryRun: tt.dryRun,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out := &bytes.Buffer{}
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+			ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+			ghApi, err := ghapi.NewGithubClient(ctx, "", false, srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opts := &Sync{
+				Org:    "unikraft",
+				Output: "text",
+				Prune:  tt.prune,
+				ghApi:  ghApi,
+				repos: []*repo.Repository{
+					{Name: "unikraft", Type: repo.RepoTypeCore, PermissionLevel: repo.RepoPermissionWrite},
+					{Name: "lwip", Type: repo.RepoTypeLib},
+				},
+				teams: []*team.Team{
+					{Name: "arch", Repositories: []repo.Repository{{Name: "unikraft"}}},
+				},
+			}
+
+			if err := opts.Run(ctx, nil); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("unexpected report:\n%s\nwant:\n%s", got, tt.wantOut)
+			}
+
+			if !reflect.DeepEqual(writes, tt.wantWrites) {
+				t.Errorf("writes = %q, want %q", writes, tt.wantWrites)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package report
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"kraftkit.sh/cmdfactory"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+)
+
+type Report struct{}
+
+func New() *cobra.Command {
+	cmd, err := cmdutils.New(&Report{}, cobra.Command{
+		Use:    "report SUBCOMMAND",
+		Short:  "Audit the state of the organization",
+		Hidden: true,
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "report",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	cmd.AddCommand(NewUnreviewedMerges())
+
+	return cmd
+}
+
+func (opts *Report) Run(_ context.Context, args []string) error {
+	return pflag.ErrHelp
+}
@@ -0,0 +1,274 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package report
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/tableprinter"
+)
+
+type UnreviewedMerges struct {
+	From        string   `long:"from" usage:"Reference to start scanning from (exclusive)"`
+	To          string   `long:"to" usage:"Reference to stop scanning at (inclusive)" default:"HEAD"`
+	Repo        string   `long:"repo" usage:"Path to a local clone of the repository"`
+	AllowAuthor []string `long:"allow-author" env:"GOVERN_ALLOW_AUTHORS" usage:"Commit author names or emails which are exempt from the report (e.g. bots)" default:"dependabot[bot]"`
+	Output      string   `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of choice [table, csv, json, yaml]" default:"table"`
+	Columns     []string `long:"columns" env:"GOVERN_COLUMNS" usage:"Comma-separated columns of the table to render, in order [commit, author, date, pr, missing]"`
+}
+
+func NewUnreviewedMerges() *cobra.Command {
+	cmd, err := cmdutils.New(&UnreviewedMerges{}, cobra.Command{
+		Use:   "unreviewed-merges [OPTIONS] ORG/REPO",
+		Short: "Find commits which landed without the required trailers",
+		Long:  heredocUnreviewedMerges,
+		Args:  cobra.ExactArgs(1),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "report",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+const heredocUnreviewedMerges = `Scan the history of a branch between two references and list every commit
+which is missing a review trailer (Reviewed-by or Approved-by) or a
+GitHub-Closes reference.  Each suspect commit is cross-referenced with the
+pull requests which contain it so that it is possible to determine how the
+commit landed.  The command exits with a non-zero status when suspect commits
+are found.`
+
+// unreviewedMergesColumns are the columns of the table of suspect commits.
+var unreviewedMergesColumns = []string{"COMMIT", "AUTHOR", "DATE", "PR", "MISSING"}
+
+// Validate rejects unknown columns before the history is scanned.
+func (opts *UnreviewedMerges) Validate(_ context.Context) error {
+	if err := tableprinter.ValidateColumns(unreviewedMergesColumns, opts.Columns); err != nil {
+		return fmt.Errorf("invalid --columns: %w", err)
+	}
+
+	return nil
+}
+
+// missingTrailers returns the list of governance trailers which are absent
+// from the provided commit message.  A commit must carry at least one review
+// trailer as well as a reference to the pull request which introduced it.
+func missingTrailers(message string) []string {
+	var reviewed, closes bool
+
+	for _, line := range strings.Split(message, "\n") {
+		key, _, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "reviewed-by", "approved-by":
+			reviewed = true
+		case "github-closes":
+			closes = true
+		}
+	}
+
+	var missing []string
+	if !reviewed {
+		missing = append(missing, "Reviewed-by/Approved-by")
+	}
+	if !closes {
+		missing = append(missing, "GitHub-Closes")
+	}
+
+	return missing
+}
+
+// isAllowedAuthor checks whether the author of a commit is in the allowlist
+// either by name or by email address.
+func isAllowedAuthor(allow []string, author object.Signature) bool {
+	for _, a := range allow {
+		if strings.EqualFold(a, author.Name) || strings.EqualFold(a, author.Email) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (opts *UnreviewedMerges) Run(ctx context.Context, args []string) error {
+	ghOrg, ghRepo, found := strings.Cut(args[0], "/")
+	if !found || ghOrg == "" || ghRepo == "" {
+		return fmt.Errorf("expected format ORG/REPO")
+	}
+
+	ghClient, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	localRepo := opts.Repo
+	if localRepo == "" {
+		tempDir := kitcfg.G[config.Config](ctx).TempDir
+		if tempDir == "" {
+			tempDir, err = os.MkdirTemp("", "governctl-report-unreviewed-merges-*")
+			if err != nil {
+				return fmt.Errorf("could not create temporary directory: %w", err)
+			}
+
+			defer func() {
+				os.RemoveAll(tempDir)
+			}()
+		}
+
+		localRepo = path.Join(tempDir, ghRepo)
+	}
+
+	if _, err := os.Stat(localRepo); os.IsNotExist(err) {
+		ghOrigin := fmt.Sprintf("https://github.com/%s/%s.git", ghOrg, ghRepo)
+
+		log.G(ctx).
+			WithField("from", ghOrigin).
+			WithField("to", localRepo).
+			Infof("cloning git repository")
+
+		if _, err := git.PlainClone(localRepo, false, &git.CloneOptions{
+			URL: ghOrigin,
+			Auth: &http.BasicAuth{
+				Username: kitcfg.G[config.Config](ctx).GithubUser,
+				Password: kitcfg.G[config.Config](ctx).GithubToken,
+			},
+		}); err != nil {
+			return fmt.Errorf("could not clone repository: %w", err)
+		}
+	}
+
+	repo, err := git.PlainOpen(localRepo)
+	if err != nil {
+		return fmt.Errorf("could not open repository: %w", err)
+	}
+
+	to, err := repo.ResolveRevision(plumbing.Revision(opts.To))
+	if err != nil {
+		return fmt.Errorf("could not resolve '%s': %w", opts.To, err)
+	}
+
+	var from *plumbing.Hash
+	if opts.From != "" {
+		from, err = repo.ResolveRevision(plumbing.Revision(opts.From))
+		if err != nil {
+			return fmt.Errorf("could not resolve '%s': %w", opts.From, err)
+		}
+	}
+
+	iter, err := repo.Log(&git.LogOptions{From: *to})
+	if err != nil {
+		return fmt.Errorf("could not read history: %w", err)
+	}
+
+	var suspects []*object.Commit
+
+	if err := iter.ForEach(func(commit *object.Commit) error {
+		if from != nil && commit.Hash == *from {
+			return storer.ErrStop
+		}
+
+		// Merge commits are generated by the merge itself, the commits which they
+		// bring in are checked individually.
+		if commit.NumParents() > 1 {
+			return nil
+		}
+
+		if isAllowedAuthor(opts.AllowAuthor, commit.Author) {
+			return nil
+		}
+
+		if len(missingTrailers(commit.Message)) > 0 {
+			suspects = append(suspects, commit)
+		}
+
+		return nil
+	}); err != nil {
+		return fmt.Errorf("could not walk history: %w", err)
+	}
+
+	cs := iostreams.G(ctx).ColorScheme()
+
+	topts := []tableprinter.TablePrinterOption{
+		tableprinter.WithOutputFormatFromString(opts.Output),
+		tableprinter.WithColumns(opts.Columns...),
+	}
+
+	if kitcfg.G[config.Config](ctx).NoRender {
+		topts = append(topts, tableprinter.WithMaxWidth(10000))
+	} else {
+		topts = append(topts, tableprinter.WithMaxWidth(iostreams.G(ctx).TerminalWidth()))
+	}
+
+	table, err := tableprinter.NewTablePrinter(ctx, topts...)
+	if err != nil {
+		return err
+	}
+
+	table.AddHeader(cs.Bold, unreviewedMergesColumns...)
+
+	for _, commit := range suspects {
+		pr := ""
+
+		pulls, err := ghClient.ListPullRequestsWithCommit(ctx, ghOrg, ghRepo, commit.Hash.String())
+		if err != nil {
+			log.G(ctx).
+				WithField("commit", commit.Hash.String()).
+				Warnf("could not list pull requests with commit: %s", err)
+		}
+
+		for _, pull := range pulls {
+			if pull.GetState() == "closed" {
+				pr = fmt.Sprintf("#%d", pull.GetNumber())
+				break
+			}
+		}
+
+		table.AddField(commit.Hash.String()[0:7], nil)
+		table.AddField(fmt.Sprintf("%s <%s>", commit.Author.Name, commit.Author.Email), nil)
+		table.AddField(commit.Author.When.Format("2006-01-02"), nil)
+		table.AddField(pr, nil)
+		table.AddField(strings.Join(missingTrailers(commit.Message), ", "), cs.Red)
+		table.EndRow()
+	}
+
+	if len(suspects) == 0 && opts.Output == "table" {
+		fmt.Fprintf(iostreams.G(ctx).Out, cs.Green("✔")+" no unreviewed merges found\n")
+		return nil
+	}
+
+	if err := table.Render(iostreams.G(ctx).Out); err != nil {
+		return err
+	}
+
+	if len(suspects) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("found %d unreviewed commit(s)", len(suspects))
+}
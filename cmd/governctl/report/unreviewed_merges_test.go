@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package report
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestMissingTrailers(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{
+			name: "fully governed",
+			message: `lib/ukboot: Fix boot order
+
+Signed-off-by: Jane Doe <jane@example.com>
+Reviewed-by: John Doe <john@example.com>
+Approved-by: John Doe <john@example.com>
+GitHub-Closes: #123
+`,
+			want: nil,
+		},
+		{
+			name: "approved only",
+			message: `lib/ukboot: Fix boot order
+
+Approved-by: John Doe <john@example.com>
+GitHub-Closes: #123
+`,
+			want: nil,
+		},
+		{
+			name: "missing review",
+			message: `lib/ukboot: Fix boot order
+
+Signed-off-by: Jane Doe <jane@example.com>
+GitHub-Closes: #123
+`,
+			want: []string{"Reviewed-by/Approved-by"},
+		},
+		{
+			name:    "merged from the ui",
+			message: "Update README.md",
+			want:    []string{"Reviewed-by/Approved-by", "GitHub-Closes"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := missingTrailers(tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("missingTrailers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAllowedAuthor(t *testing.T) {
+	allow := []string{"dependabot[bot]", "bot@unikraft.io"}
+
+	if !isAllowedAuthor(allow, object.Signature{Name: "dependabot[bot]"}) {
+		t.Errorf("expected author name to be allowed")
+	}
+	if !isAllowedAuthor(allow, object.Signature{Name: "Bot", Email: "BOT@unikraft.io"}) {
+		t.Errorf("expected author email to be allowed")
+	}
+	if isAllowedAuthor(allow, object.Signature{Name: "Jane Doe", Email: "jane@example.com"}) {
+		t.Errorf("expected author to not be allowed")
+	}
+}
+
+func TestUnreviewedMergesValidate(t *testing.T) {
+	if err := (&UnreviewedMerges{Columns: []string{"pr", "Commit"}}).Validate(context.Background()); err != nil {
+		t.Errorf("Validate() unexpected error: %v", err)
+	}
+
+	err := (&UnreviewedMerges{Columns: []string{"pr", "reviewer"}}).Validate(context.Background())
+	if want := "invalid --columns: unknown column 'reviewer': expected one of commit, author, date, pr, missing"; err == nil || err.Error() != want {
+		t.Errorf("Validate() error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/MakeNowJust/heredoc"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/team"
+)
+
+type Import struct {
+	Exclude        []string `long:"exclude" usage:"Skip teams whose name matches this glob pattern (may be repeated)"`
+	Org            string   `long:"org" usage:"Set the GitHub organisation whose teams are imported (deprecated, use --github-org)"`
+	OutputDir      string   `long:"output-dir" usage:"Directory to write the team definitions to" default:"teams"`
+	Overwrite      bool     `long:"overwrite" usage:"Overwrite existing definition files"`
+	ReposOutputDir string   `long:"repos-output-dir" usage:"Directory to write the repository definitions to" default:"repos"`
+	SkipSecret     bool     `long:"skip-secret" usage:"Skip teams which are only visible to their members"`
+}
+
+func NewImport() *cobra.Command {
+	cmd, err := cmdutils.New(&Import{}, cobra.Command{
+		Use:   "import [OPTIONS]",
+		Short: "Generate team and repository definitions from GitHub",
+		Args:  cobra.NoArgs,
+		Long: heredoc.Doc(`
+		Generate the definitions of the teams of an organisation and of every
+		repository granted to them from their current state on GitHub, e.g. to
+		bootstrap the definitions of an organisation which starts using governctl.
+
+		Members with the maintainer role, or of the maintainers sub-team if it
+		exists, are listed as maintainers and members of the reviewers sub-team as
+		reviewers.  Mappings which are ambiguous are marked with TODO comments.
+		`),
+		Example: heredoc.Doc(`
+		# Import the teams of an organisation, except for secret and bot teams
+		governctl team import --github-org unikraft --skip-secret --exclude 'bots-*'
+
+		# Preview the definitions without writing them
+		governctl --dry-run team import --github-org unikraft
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "team",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	cmdutils.DeprecateOrg(cmd)
+
+	return cmd
+}
+
+func (opts *Import) Validate(ctx context.Context) error {
+	for _, pattern := range opts.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern '%s': %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+func (opts *Import) Run(ctx context.Context, args []string) error {
+	opts.Org = kitcfg.G[config.Config](ctx).EffectiveGithubOrg(opts.Org)
+
+	ghApi, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	imported, err := team.ImportFromGithub(ctx, ghApi, opts.Org, team.ImportOptions{
+		SkipSecret: opts.SkipSecret,
+		Exclude:    opts.Exclude,
+	})
+	if err != nil {
+		return fmt.Errorf("could not import teams: %w", err)
+	}
+
+	for _, name := range imported.Skipped {
+		log.WithField("team", name).Info("skipped team")
+	}
+
+	todos := 0
+	for _, it := range imported.Teams {
+		todos += len(it.TODOs)
+		for _, todo := range it.TODOs {
+			log.WithField("team", it.Team.Name).Warn(todo)
+		}
+	}
+
+	for _, ir := range imported.Repos {
+		todos += len(ir.TODOs)
+		for _, todo := range ir.TODOs {
+			log.WithField("repo", ir.Repo.Name).Warn(todo)
+		}
+	}
+
+	files, err := imported.Files(opts.OutputDir, opts.ReposOutputDir)
+	if err != nil {
+		return err
+	}
+
+	if kitcfg.G[config.Config](ctx).DryRun {
+		for _, file := range files {
+			fmt.Fprintf(iostreams.G(ctx).Out, "# %s\n%s---\n", file.Path, file.Content)
+		}
+
+		return nil
+	}
+
+	if err := team.WriteImportedFiles(files, opts.Overwrite); err != nil {
+		return err
+	}
+
+	log.
+		WithField("teams", len(imported.Teams)).
+		WithField("repos", len(imported.Repos)).
+		WithField("todos", todos).
+		Info("imported definitions")
+
+	return nil
+}
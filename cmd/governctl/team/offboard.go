@@ -0,0 +1,475 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	prsync "github.com/unikraft/governance/cmd/governctl/pr/sync"
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/patch"
+	"github.com/unikraft/governance/internal/team"
+	"github.com/unikraft/governance/internal/user"
+	"github.com/unikraft/governance/internal/yamledit"
+)
+
+type Offboard struct {
+	Org      string `long:"org" usage:"Set the GitHub organisation that the user is offboarded from (deprecated, use --github-org)"`
+	Output   string `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+	PR       bool   `long:"pr" env:"GOVERN_PR" usage:"Open a pull request with the changes to the team definitions"`
+	PRBase   string `long:"pr-base" env:"GOVERN_PR_BASE" usage:"Base branch of the pull request opened with --pr" default:"main"`
+	PRRepo   string `long:"pr-repo" env:"GOVERN_PR_REPO" usage:"Repository of the team definitions which the pull request is opened against" default:"governance"`
+	Reassign bool   `long:"reassign" env:"GOVERN_REASSIGN" usage:"Unassign the user from open pull requests and assign other maintainers and reviewers in their place"`
+}
+
+// offboardFile is the removal of the user from a single team definition.
+type offboardFile struct {
+	File     string   `json:"file"`
+	Sections []string `json:"sections"`
+	Diff     string   `json:"diff"`
+}
+
+// offboardDiscord is a Discord account of the user which is known from the
+// team definitions together with the teams which grant it roles.
+type offboardDiscord struct {
+	Account string   `json:"account"`
+	Teams   []string `json:"teams"`
+}
+
+// OffboardReport lists the changes to the team definitions which remove the
+// user together with the access which the user still has.
+type OffboardReport struct {
+	User  string         `json:"user"`
+	Files []offboardFile `json:"files"`
+
+	// Teams are the GitHub teams which the user is still a member of.
+	Teams []string `json:"teams"`
+
+	// Collaborations are the repositories which the user has been granted
+	// access to directly rather than through a team.
+	Collaborations []string `json:"collaborations"`
+
+	// PullRequests are the open pull requests which the user is assigned to or
+	// has been requested to review, in the form "org/repo#id".
+	PullRequests []string `json:"pull_requests"`
+
+	Discord []offboardDiscord `json:"discord,omitempty"`
+
+	// PullRequest is the URL of the pull request with the changes to the team
+	// definitions if one was opened.
+	PullRequest string `json:"pull_request,omitempty"`
+}
+
+func NewOffboard() *cobra.Command {
+	cmd, err := cmdutils.New(&Offboard{}, cobra.Command{
+		Use:   "offboard [OPTIONS] USER",
+		Short: "Remove a user from all teams and report their residual access",
+		Args:  cobra.ExactArgs(1),
+		Long: heredoc.Doc(`
+		Remove the GitHub user from every team definition, i.e. from the lists of
+		maintainers, reviewers, members and never_assign, and report the access
+		which the user still has: GitHub teams, direct collaborator grants, open
+		pull requests and Discord accounts.
+
+		Memberships of GitHub teams and Discord roles are not changed by this
+		command.  They are removed by the subsequent synchronisation once the
+		changes to the team definitions have been merged.
+		`),
+		Example: heredoc.Doc(`
+		# Preview offboarding octocat
+		governctl --dry-run team offboard octocat
+
+		# Offboard octocat, reassign their pull requests and open a pull request
+		governctl team offboard --reassign --pr octocat
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "team",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	cmdutils.DeprecateOrg(cmd)
+
+	return cmd
+}
+
+// Validate rejects unknown output formats of the plan and a --pr which is
+// either combined with --dry-run or lacks its --pr-repo.
+func (opts *Offboard) Validate(ctx context.Context) error {
+	if err := cmdutils.ValidatePlanOutput(ctx, opts.Output); err != nil {
+		return err
+	}
+
+	if err := config.Exclusive("pr", opts.PR, "dry-run", kitcfg.G[config.Config](ctx).DryRun); err != nil {
+		return err
+	}
+
+	return config.Requires("pr", opts.PR, "pr-repo", opts.PRRepo != "")
+}
+
+func (opts *Offboard) Run(ctx context.Context, args []string) error {
+	opts.Org = kitcfg.G[config.Config](ctx).EffectiveGithubOrg(opts.Org)
+
+	login := strings.TrimPrefix(args[0], "@")
+	dryRun := kitcfg.G[config.Config](ctx).DryRun
+
+	if kitcfg.G[config.Config](ctx).ReadOnly && !dryRun {
+		return fmt.Errorf("cannot offboard user: %w", ghapi.ErrReadOnly)
+	}
+
+	ghApi, err := cmdutils.NewGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	teamsDir := kitcfg.G[config.Config](ctx).TeamsDir
+
+	// Determine the Discord accounts before the user is removed from the team
+	// definitions.
+	teams, err := team.NewListOfTeamsFromPath(nil, opts.Org, teamsDir)
+	if err != nil {
+		return fmt.Errorf("could not populate teams: %w", err)
+	}
+
+	report := &OffboardReport{
+		User:    login,
+		Discord: discordAccounts(teams, login),
+	}
+
+	if report.Files, err = opts.removeFromDefinitions(ctx, teamsDir, login); err != nil {
+		return err
+	}
+
+	log.Info("auditing residual access")
+
+	if report.Teams, err = ghApi.ListTeamsOfUser(ctx, opts.Org, login); err != nil {
+		return err
+	}
+
+	if report.Collaborations, err = ghApi.ListDirectCollaborations(ctx, opts.Org, login); err != nil {
+		return err
+	}
+
+	if report.PullRequests, err = openPullRequestsOf(ctx, ghApi, opts.Org, login); err != nil {
+		return err
+	}
+
+	if opts.Reassign && !dryRun {
+		for _, pr := range report.PullRequests {
+			if err := reassign(ctx, ghApi, pr, login); err != nil {
+				return fmt.Errorf("could not reassign %s: %w", pr, err)
+			}
+		}
+	}
+
+	if opts.PR && len(report.Files) > 0 {
+		if report.PullRequest, err = opts.openPullRequest(ctx, ghApi, teamsDir, report); err != nil {
+			return err
+		}
+	}
+
+	if opts.Output != cmdutils.PlanOutputJSON {
+		writeOffboardReport(iostreams.G(ctx).Out, report, opts.Reassign, dryRun)
+	}
+
+	return cmdutils.WritePlan(ctx, opts.Output, report)
+}
+
+// removeFromDefinitions removes the user from every team definition at the
+// provided path and returns the changes to each file.  In dry-run mode, the
+// files are left untouched.
+func (opts *Offboard) removeFromDefinitions(ctx context.Context, teamsDir, login string) ([]offboardFile, error) {
+	fi, err := os.Stat(teamsDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read directory: %w", err)
+	}
+
+	var files []string
+	if fi.IsDir() {
+		entries, err := os.ReadDir(teamsDir)
+		if err != nil {
+			return nil, fmt.Errorf("could not read directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files = append(files, path.Join(teamsDir, entry.Name()))
+			}
+		}
+	} else {
+		files = append(files, teamsDir)
+	}
+
+	changes := make([]offboardFile, 0)
+
+	for _, name := range files {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("could not read teams file: %w", err)
+		}
+
+		removed, sections := team.RemoveUser(src, login)
+		if len(sections) == 0 {
+			continue
+		}
+
+		changes = append(changes, offboardFile{
+			File:     name,
+			Sections: sections,
+			Diff:     yamledit.Diff(name, src, removed),
+		})
+
+		if kitcfg.G[config.Config](ctx).DryRun {
+			continue
+		}
+
+		log.WithField("file", name).Info("removing user from team definition")
+
+		if err := os.WriteFile(name, removed, 0o644); err != nil {
+			return nil, fmt.Errorf("could not write teams file: %w", err)
+		}
+	}
+
+	return changes, nil
+}
+
+// discordAccounts returns the Discord accounts of the user which are recorded
+// in the team definitions together with the teams which record them.
+func discordAccounts(teams []*team.Team, login string) []offboardDiscord {
+	byAccount := make(map[string][]string)
+
+	for _, t := range teams {
+		users := append([]user.User{}, t.Maintainers...)
+		users = append(users, t.Reviewers...)
+		users = append(users, t.Members...)
+
+		for _, u := range users {
+			if u.Discord == "" || !strings.EqualFold(u.Github, login) {
+				continue
+			}
+
+			if !containsStr(byAccount[u.Discord], t.Name) {
+				byAccount[u.Discord] = append(byAccount[u.Discord], t.Name)
+			}
+		}
+	}
+
+	var accounts []offboardDiscord
+	for account, teams := range byAccount {
+		accounts = append(accounts, offboardDiscord{
+			Account: account,
+			Teams:   teams,
+		})
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Account < accounts[j].Account
+	})
+
+	return accounts
+}
+
+// openPullRequestsOf returns the open pull requests of the organisation which
+// the user is assigned to or has been requested to review.
+func openPullRequestsOf(ctx context.Context, ghApi *ghapi.GithubClient, org, login string) ([]string, error) {
+	prs := make([]string, 0)
+
+	for _, qualifier := range []string{"assignee", "review-requested"} {
+		issues, err := ghApi.SearchIssues(ctx, fmt.Sprintf("is:pr is:open org:%s %s:%s", org, qualifier, login))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			repo := path.Base(issue.GetRepositoryURL())
+			pr := fmt.Sprintf("%s/%s#%d", org, repo, issue.GetNumber())
+
+			if !containsStr(prs, pr) {
+				prs = append(prs, pr)
+			}
+		}
+	}
+
+	sort.Strings(prs)
+
+	return prs, nil
+}
+
+// reassign removes the user from the pull request, given in the form
+// "org/repo#id", and lets the reviewer synchronisation assign others in their
+// place.
+func reassign(ctx context.Context, ghApi *ghapi.GithubClient, pr, login string) error {
+	repo, id, _ := strings.Cut(pr, "#")
+	org, name, _ := strings.Cut(repo, "/")
+
+	var prId int
+	if _, err := fmt.Sscanf(id, "%d", &prId); err != nil {
+		return fmt.Errorf("invalid pull request: %s", pr)
+	}
+
+	log.WithField("pr", pr).Info("reassigning pull request")
+
+	if err := ghApi.RemoveUserFromPr(ctx, org, name, prId, login); err != nil {
+		return err
+	}
+
+	reviewers := &prsync.Reviewers{
+		NumMaintainers: 1,
+		NumReviewers:   1,
+	}
+
+	return reviewers.Run(ctx, []string{fmt.Sprintf("%s/%s/%d", org, name, prId)})
+}
+
+// openPullRequest commits the changes to the team definitions onto a new
+// branch, pushes it and opens a pull request with the report as its body.
+func (opts *Offboard) openPullRequest(ctx context.Context, ghApi *ghapi.GithubClient, teamsDir string, report *OffboardReport) (string, error) {
+	gitBinary := kitcfg.G[config.Config](ctx).GitBinary
+	if gitBinary == "" {
+		gitBinary = patch.DefaultGitBinary
+	}
+
+	workdir := teamsDir
+	if fi, err := os.Stat(teamsDir); err == nil && !fi.IsDir() {
+		workdir = filepath.Dir(teamsDir)
+	}
+
+	branch := fmt.Sprintf("offboard-%s", strings.ToLower(report.User))
+	title := fmt.Sprintf("teams: Offboard @%s", report.User)
+
+	git := func(args ...string) error {
+		cmd := exec.CommandContext(ctx, gitBinary, append([]string{"-C", workdir}, args...)...)
+		cmd.Stderr = log.StandardLogger().WriterLevel(log.ErrorLevel)
+		cmd.Stdout = log.StandardLogger().WriterLevel(log.DebugLevel)
+		return cmd.Run()
+	}
+
+	if err := git("checkout", "-b", branch); err != nil {
+		return "", fmt.Errorf("could not create branch %s: %w", branch, err)
+	}
+
+	add := []string{"add"}
+	for _, f := range report.Files {
+		abs, err := filepath.Abs(f.File)
+		if err != nil {
+			return "", err
+		}
+
+		add = append(add, abs)
+	}
+
+	if err := git(add...); err != nil {
+		return "", fmt.Errorf("could not stage team definitions: %w", err)
+	}
+
+	if err := git("commit", "--signoff", "-m", title); err != nil {
+		return "", fmt.Errorf("could not commit team definitions: %w", err)
+	}
+
+	if err := git("push", fmt.Sprintf("https://%s:%s@github.com/%s/%s.git",
+		kitcfg.G[config.Config](ctx).GithubUser,
+		kitcfg.G[config.Config](ctx).GithubToken,
+		opts.Org,
+		opts.PRRepo,
+	), branch); err != nil {
+		return "", fmt.Errorf("could not push branch %s: %w", branch, err)
+	}
+
+	var body strings.Builder
+	writeOffboardReport(&body, report, opts.Reassign, false)
+
+	pull, err := ghApi.CreatePullRequest(ctx, opts.Org, opts.PRRepo, branch, opts.PRBase, title, body.String())
+	if err != nil {
+		return "", fmt.Errorf("could not open pull request: %w", err)
+	}
+
+	return pull.GetHTMLURL(), nil
+}
+
+// writeOffboardReport writes the changes to the team definitions and the
+// residual access of the user in a human-readable form.
+func writeOffboardReport(w io.Writer, report *OffboardReport, reassigned, dryRun bool) {
+	fmt.Fprintf(w, "Offboarding @%s\n\n", report.User)
+
+	if len(report.Files) == 0 {
+		fmt.Fprintf(w, "Team definitions: user not found\n")
+	} else {
+		fmt.Fprintf(w, "Team definitions:\n")
+		for _, f := range report.Files {
+			fmt.Fprintf(w, "  %s: removed from %s\n", f.File, strings.Join(f.Sections, ", "))
+		}
+	}
+
+	list := func(title string, items []string) {
+		if len(items) == 0 {
+			fmt.Fprintf(w, "\n%s: none\n", title)
+			return
+		}
+
+		fmt.Fprintf(w, "\n%s:\n", title)
+		for _, item := range items {
+			fmt.Fprintf(w, "  %s\n", item)
+		}
+	}
+
+	list("GitHub teams", report.Teams)
+	list("Direct collaborator access", report.Collaborations)
+
+	prTitle := "Open pull requests (assignee or requested reviewer)"
+	if reassigned && !dryRun {
+		prTitle = "Reassigned pull requests"
+	} else if reassigned {
+		prTitle = "Pull requests which would be reassigned"
+	}
+	list(prTitle, report.PullRequests)
+
+	var discord []string
+	for _, d := range report.Discord {
+		discord = append(discord, fmt.Sprintf("%s (%s)", d.Account, strings.Join(d.Teams, ", ")))
+	}
+	list("Discord accounts", discord)
+
+	if report.PullRequest != "" {
+		fmt.Fprintf(w, "\nPull request: %s\n", report.PullRequest)
+	}
+
+	fmt.Fprint(w, heredoc.Doc(`
+
+	Note: this command does not revoke any access itself.  Memberships of
+	GitHub teams and Discord roles are removed by the subsequent team and
+	Discord synchronisation once the changes to the team definitions have
+	been merged.  Direct collaborator access is not managed by any
+	synchronisation and must be revoked by an organisation owner.
+	`))
+}
+
+func containsStr(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
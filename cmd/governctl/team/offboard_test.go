@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/config"
+)
+
+const offboardTeam = `name: maintainers-boot
+maintainers:
+  - name: Jane Doe
+    github: jane
+    discord: jane#1234
+  - github: bob
+reviewers:
+  - github: alice
+code_review:
+  never_assign:
+    - github: Jane
+`
+
+// newOffboardEnv starts a fake GitHub API in which jane is still a member of
+// a team, a direct collaborator and assigned to pull requests, and returns a
+// context configured against it and the file defining jane's team.
+func newOffboardEnv(t *testing.T, dryRun bool) (context.Context, *bytes.Buffer, string) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/orgs/unikraft/teams", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"slug":"maintainers-boot"},{"slug":"docs"}]`)
+	})
+	mux.HandleFunc("/api/v3/orgs/unikraft/teams/maintainers-boot/memberships/jane", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"state":"active","role":"member"}`)
+	})
+	mux.HandleFunc("/api/v3/orgs/unikraft/teams/docs/memberships/jane", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Not Found"}`)
+	})
+	mux.HandleFunc("/api/v3/orgs/unikraft/repos", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"name":"unikraft"},{"name":"app-test"}]`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/collaborators", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("affiliation"); got != "direct" {
+			t.Errorf("affiliation = %q, want direct", got)
+		}
+		fmt.Fprint(w, `[{"login":"Jane"}]`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/app-test/collaborators", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"login":"bob"}]`)
+	})
+	mux.HandleFunc("/api/v3/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		switch q := r.URL.Query().Get("q"); {
+		case strings.Contains(q, "assignee:jane"):
+			fmt.Fprint(w, `{"items":[{"number":3,"repository_url":"https://api.github.com/repos/unikraft/unikraft"}]}`)
+		case strings.Contains(q, "review-requested:jane"):
+			fmt.Fprint(w, `{"items":[
+				{"number":3,"repository_url":"https://api.github.com/repos/unikraft/unikraft"},
+				{"number":7,"repository_url":"https://api.github.com/repos/unikraft/app-test"}
+			]}`)
+		default:
+			t.Errorf("unexpected query %q", q)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	teamsDir := t.TempDir()
+	teamFile := filepath.Join(teamsDir, "maintainers-boot.yaml")
+	if err := os.WriteFile(teamFile, []byte(offboardTeam), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(teamsDir, "docs.yaml"), []byte("name: docs\nmembers:\n  - github: bob\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{
+		DryRun:         dryRun,
+		GithubEndpoint: srv.URL,
+		TeamsDir:       teamsDir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+	ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+	return ctx, out, teamFile
+}
+
+func TestOffboardDryRun(t *testing.T) {
+	ctx, out, teamFile := newOffboardEnv(t, true)
+
+	opts := &Offboard{Org: "unikraft", Output: "json"}
+	if err := opts.Run(ctx, []string{"@jane"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	src, err := os.ReadFile(teamFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != offboardTeam {
+		t.Errorf("team definition changed in dry-run mode:\n%s", src)
+	}
+
+	var report OffboardReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("could not decode report: %v\n%s", err, out)
+	}
+
+	if len(report.Files) != 1 || report.Files[0].File != teamFile {
+		t.Fatalf("Files = %+v, want only %s", report.Files, teamFile)
+	}
+
+	if want := []string{"maintainers", "never_assign"}; !reflect.DeepEqual(report.Files[0].Sections, want) {
+		t.Errorf("Sections = %v, want %v", report.Files[0].Sections, want)
+	}
+
+	if want := []string{"@unikraft/maintainers-boot"}; !reflect.DeepEqual(report.Teams, want) {
+		t.Errorf("Teams = %v, want %v", report.Teams, want)
+	}
+
+	if want := []string{"unikraft"}; !reflect.DeepEqual(report.Collaborations, want) {
+		t.Errorf("Collaborations = %v, want %v", report.Collaborations, want)
+	}
+
+	if want := []string{"unikraft/app-test#7", "unikraft/unikraft#3"}; !reflect.DeepEqual(report.PullRequests, want) {
+		t.Errorf("PullRequests = %v, want %v", report.PullRequests, want)
+	}
+
+	want := []offboardDiscord{{Account: "jane#1234", Teams: []string{"boot"}}}
+	if !reflect.DeepEqual(report.Discord, want) {
+		t.Errorf("Discord = %+v, want %+v", report.Discord, want)
+	}
+}
+
+func TestOffboard(t *testing.T) {
+	ctx, out, teamFile := newOffboardEnv(t, false)
+
+	opts := &Offboard{Org: "unikraft", Output: "text"}
+	if err := opts.Run(ctx, []string{"jane"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	src, err := os.ReadFile(teamFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `name: maintainers-boot
+maintainers:
+  - github: bob
+reviewers:
+  - github: alice
+code_review:
+  never_assign:
+`
+	if string(src) != want {
+		t.Errorf("team definition =\n%s\nwant:\n%s", src, want)
+	}
+
+	for _, s := range []string{
+		teamFile + ": removed from maintainers, never_assign",
+		"@unikraft/maintainers-boot",
+		"unikraft/app-test#7",
+		"jane#1234 (boot)",
+		"does not revoke any access itself",
+	} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("expected report to contain %q, got:\n%s", s, out)
+		}
+	}
+}
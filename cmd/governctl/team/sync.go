@@ -7,26 +7,53 @@ package team
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
-	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/unikraft/governance/internal/cmdutils"
 	"github.com/unikraft/governance/internal/config"
 	"github.com/unikraft/governance/internal/ghapi"
 	"github.com/unikraft/governance/internal/team"
+	"github.com/unikraft/governance/internal/user"
 	"kraftkit.sh/cmdfactory"
 	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
 )
 
 type Sync struct {
-	Org string `long:"org" env:"GOVERN_GITHUB_ORG" usage:"Set the GitHub organisation that should have teams managed" default:"unikraft"`
+	AggregateMaintainers  string `long:"aggregate-maintainers-team" env:"GOVERN_AGGREGATE_MAINTAINERS_TEAM" usage:"Name of the organization-wide team of all maintainers" default:"maintainers"`
+	AggregateReviewers    string `long:"aggregate-reviewers-team" env:"GOVERN_AGGREGATE_REVIEWERS_TEAM" usage:"Name of the organization-wide team of all reviewers" default:"reviewers"`
+	AggregateTeams        bool   `long:"aggregate-teams" env:"GOVERN_AGGREGATE_TEAMS" usage:"Also synchronise the organization-wide teams of all maintainers and all reviewers"`
+	IgnoreUnreadableTeams bool   `long:"ignore-unreadable-teams" env:"GOVERN_IGNORE_UNREADABLE_TEAMS" usage:"Skip teams which are not visible to the token instead of failing"`
+	MaxRemovals           int    `long:"max-removals" env:"GOVERN_MAX_REMOVALS" usage:"Refuse to remove more than this many members from an aggregate team (0 to disable)" default:"10"`
+	Org                   string `long:"org" usage:"Set the GitHub organisation that should have teams managed (deprecated, use --github-org)"`
+	Output                string `long:"output" short:"o" env:"GOVERN_OUTPUT" usage:"Set the output format of the dry-run plan [text, json]" default:"text"`
+	Prune                 bool   `long:"prune" env:"GOVERN_PRUNE" usage:"Delete the maintainers-* and reviewers-* sub-teams and their parents which are no longer defined"`
 
+	ghApi *ghapi.GithubClient
 	teams []*team.Team
 }
 
+// SyncPlan is the set of changes to the membership of every team, including
+// the maintainers and reviewers sub-teams and the aggregate teams.
+type SyncPlan struct {
+	Teams      []*ghapi.TeamMembershipChange `json:"teams"`
+	Aggregates []*ghapi.TeamMembershipChange `json:"aggregates,omitempty"`
+
+	// Pruned are the teams which are deleted as they are no longer defined.
+	Pruned []string `json:"pruned,omitempty"`
+
+	// Warnings are problems with the teams which do not prevent the remaining
+	// changes from being synchronised, e.g. references to unknown repositories.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
 func NewSync() *cobra.Command {
-	cmd, err := cmdfactory.New(&Sync{}, cobra.Command{
+	cmd, err := cmdutils.New(&Sync{}, cobra.Command{
 		Use:   "sync",
 		Short: "Synchronise teams",
 		Args:  cobra.NoArgs,
@@ -38,21 +65,49 @@ func NewSync() *cobra.Command {
 		panic(err)
 	}
 
+	cmdutils.DeprecateOrg(cmd)
+
 	return cmd
 }
 
+// Validate rejects unknown output formats of the plan, a negative
+// --max-removals and aggregate teams which are missing or identical.
+func (opts *Sync) Validate(ctx context.Context) error {
+	if err := cmdutils.ValidatePlanOutput(ctx, opts.Output); err != nil {
+		return err
+	}
+
+	if err := config.NotNegative("max-removals", opts.MaxRemovals); err != nil {
+		return err
+	}
+
+	if opts.AggregateTeams {
+		if opts.AggregateMaintainers == "" || opts.AggregateReviewers == "" {
+			return fmt.Errorf("--aggregate-teams requires the names of both aggregate teams")
+		}
+
+		if opts.AggregateMaintainers == opts.AggregateReviewers {
+			return fmt.Errorf("--aggregate-maintainers-team and --aggregate-reviewers-team must differ")
+		}
+	}
+
+	return nil
+}
+
 func (opts *Sync) Pre(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
-	ghApi, err := ghapi.NewGithubClient(
-		ctx,
-		kitcfg.G[config.Config](ctx).GithubToken,
-		kitcfg.G[config.Config](ctx).GithubSkipSSL,
-		kitcfg.G[config.Config](ctx).GithubEndpoint,
-	)
+	opts.Org = kitcfg.G[config.Config](ctx).EffectiveGithubOrg(opts.Org)
+
+	if kitcfg.G[config.Config](ctx).ReadOnly {
+		return fmt.Errorf("cannot synchronise teams: %w", ghapi.ErrReadOnly)
+	}
+
+	ghApi, err := cmdutils.NewGithubClient(ctx)
 	if err != nil {
 		return err
 	}
 
+	opts.ghApi = ghApi
 	opts.teams, err = team.NewListOfTeamsFromPath(
 		ghApi,
 		opts.Org,
@@ -65,13 +120,269 @@ func (opts *Sync) Pre(cmd *cobra.Command, args []string) error {
 }
 
 func (opts *Sync) Run(ctx context.Context, args []string) error {
+	unreadable := make(map[string]struct{})
+	dryRun := kitcfg.G[config.Config](ctx).DryRun
+	plan := &SyncPlan{
+		Teams: make([]*ghapi.TeamMembershipChange, 0),
+	}
+
+	unknown, err := opts.unknownRepositories(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range unknown {
+		log.G(ctx).Warn(u.String())
+		plan.Warnings = append(plan.Warnings, u.String())
+	}
+
 	for _, t := range opts.teams {
-		err := t.Sync(ctx)
-		if err != nil {
-			log.Fatalf("could not syncronise team: %s: %s", t.Name, err)
+		var err error
+
+		// In dry-run mode, only determine the changes to the membership of each
+		// team rather than synchronising it.
+		if dryRun {
+			var changes []*ghapi.TeamMembershipChange
+			changes, err = t.Plan(ctx)
+			plan.Teams = append(plan.Teams, changes...)
+		} else {
+			err = t.Sync(ctx)
+		}
+
+		// Report each team which is not visible to the token only once, since
+		// the same parent team may be referenced by many children.
+		var notVisible *ghapi.TeamNotVisibleError
+		if errors.As(err, &notVisible) {
+			name := fmt.Sprintf("@%s/%s", notVisible.Org, notVisible.Team)
+			if _, ok := unreadable[name]; !ok {
+				unreadable[name] = struct{}{}
+				log.G(ctx).Warnf("could not synchronise team: %s: %s", t.Name, notVisible)
+			}
+
+			if opts.IgnoreUnreadableTeams {
+				continue
+			}
+
+			return fmt.Errorf("could not synchronise team: %s: %w", t.Name, err)
+		} else if err != nil {
+			log.G(ctx).Fatalf("could not syncronise team: %s: %s", t.Name, err)
 			os.Exit(1)
 		}
 	}
 
+	if dryRun && opts.Output != cmdutils.PlanOutputJSON {
+		writeChangeReport(iostreams.G(ctx).Out, plan.Teams)
+	}
+
+	if opts.AggregateTeams {
+		var err error
+		if plan.Aggregates, err = opts.syncAggregates(ctx); err != nil {
+			// Still output the plan so that the offending changes can be reviewed.
+			if plan.Aggregates != nil {
+				_ = cmdutils.WritePlan(ctx, opts.Output, plan)
+			}
+
+			return err
+		}
+	}
+
+	if opts.Prune {
+		var err error
+		if plan.Pruned, err = opts.prune(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := cmdutils.WritePlan(ctx, opts.Output, plan); err != nil {
+		return err
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("teams reference %d unknown repositories", len(unknown))
+	}
+
 	return nil
 }
+
+// unknownRepositories returns the repositories referenced by any team which do
+// not exist in the organisation of the team.  The repositories of each
+// organisation are only listed once.
+func (opts *Sync) unknownRepositories(ctx context.Context) ([]team.UnknownRepository, error) {
+	var orgs []string
+	teams := make(map[string][]*team.Team)
+
+	for _, t := range opts.teams {
+		if len(t.Repositories) == 0 {
+			continue
+		}
+
+		if _, ok := teams[t.Org]; !ok {
+			orgs = append(orgs, t.Org)
+		}
+
+		teams[t.Org] = append(teams[t.Org], t)
+	}
+
+	var unknown []team.UnknownRepository
+
+	for _, org := range orgs {
+		known, err := opts.ghApi.ListOrgRepos(ctx, org)
+		if err != nil {
+			return nil, fmt.Errorf("could not verify repositories of teams: %w", err)
+		}
+
+		unknown = append(unknown, team.UnknownRepositories(teams[org], known)...)
+	}
+
+	return unknown, nil
+}
+
+// syncAggregates synchronises the organization-wide teams which contain the
+// union of all maintainers and of all reviewers across every team and returns
+// the changes.  The changes are reported before they are performed and are not
+// performed at all in dry-run mode or if any aggregate team would lose more
+// members than permitted.
+func (opts *Sync) syncAggregates(ctx context.Context) ([]*ghapi.TeamMembershipChange, error) {
+	maintainers, reviewers := team.Aggregate(opts.teams)
+	dryRun := kitcfg.G[config.Config](ctx).DryRun
+
+	aggregates := []struct {
+		name        string
+		description string
+		members     []string
+	}{
+		{
+			name:        opts.AggregateMaintainers,
+			description: fmt.Sprintf("All maintainers of @%s", opts.Org),
+			members:     maintainers,
+		},
+		{
+			name:        opts.AggregateReviewers,
+			description: fmt.Sprintf("All reviewers of @%s", opts.Org),
+			members:     reviewers,
+		},
+	}
+
+	changes := make([]*ghapi.TeamMembershipChange, 0, len(aggregates))
+
+	for _, aggregate := range aggregates {
+		change, err := opts.ghApi.PlanTeamMembers(ctx, opts.Org, aggregate.name, aggregate.members)
+		if err != nil {
+			return nil, fmt.Errorf("could not plan aggregate team: %s: %w", aggregate.name, err)
+		}
+
+		changes = append(changes, change)
+	}
+
+	if opts.Output != cmdutils.PlanOutputJSON {
+		writeChangeReport(iostreams.G(ctx).Out, changes)
+	}
+
+	for _, change := range changes {
+		if opts.MaxRemovals > 0 && len(change.Remove) > opts.MaxRemovals {
+			return changes, fmt.Errorf("refusing to remove %d members from @%s/%s which exceeds --max-removals=%d",
+				len(change.Remove),
+				change.Org,
+				change.Team,
+				opts.MaxRemovals,
+			)
+		}
+	}
+
+	if dryRun {
+		return changes, nil
+	}
+
+	privacy := string(team.TeamClosed)
+
+	for i, aggregate := range aggregates {
+		log.G(ctx).Infof("synchronising @%s/%s...", opts.Org, aggregate.name)
+
+		if _, err := opts.ghApi.CreateOrUpdateTeam(
+			ctx,
+			opts.Org,
+			aggregate.name,
+			ghapi.OptionalString(aggregate.description),
+			-1,
+			&privacy,
+			nil,
+			nil,
+		); err != nil {
+			return nil, fmt.Errorf("could not create or update team: %s: %w", aggregate.name, err)
+		}
+
+		if err := opts.ghApi.ApplyTeamMembers(ctx, changes[i], string(user.Member)); err != nil {
+			return nil, fmt.Errorf("could not synchronise team members: %s: %w", aggregate.name, err)
+		}
+	}
+
+	return changes, nil
+}
+
+// prune deletes the teams of the organisation which follow the naming scheme
+// of the governance but are no longer defined, see team.StaleTeams, and
+// returns them.  Every deletion is logged and reported, but not performed in
+// dry-run mode.
+func (opts *Sync) prune(ctx context.Context) ([]string, error) {
+	dryRun := kitcfg.G[config.Config](ctx).DryRun
+
+	// Without any definitions, every managed team would be deleted, which is
+	// more likely the result of a misconfigured teams directory.
+	if len(opts.teams) == 0 {
+		return nil, fmt.Errorf("refusing to prune teams of @%s without any team definitions", opts.Org)
+	}
+
+	existing, err := opts.ghApi.ListTeams(ctx, opts.Org)
+	if err != nil {
+		return nil, fmt.Errorf("could not prune teams: %w", err)
+	}
+
+	stale := team.StaleTeams(opts.teams, existing)
+
+	if dryRun && opts.Output != cmdutils.PlanOutputJSON {
+		for _, slug := range stale {
+			fmt.Fprintf(iostreams.G(ctx).Out, "@%s/%s: delete\n", opts.Org, slug)
+		}
+	}
+
+	for _, slug := range stale {
+		if dryRun {
+			log.G(ctx).Infof("would delete @%s/%s", opts.Org, slug)
+			continue
+		}
+
+		log.G(ctx).Infof("deleting @%s/%s...", opts.Org, slug)
+
+		if err := opts.ghApi.DeleteTeam(ctx, opts.Org, slug); err != nil {
+			return nil, err
+		}
+	}
+
+	return stale, nil
+}
+
+// writeChangeReport writes the membership changes of each team to the
+// writer, one line per user, such that they can be reviewed before being
+// applied.
+func writeChangeReport(w io.Writer, changes []*ghapi.TeamMembershipChange) {
+	for _, change := range changes {
+		if change.Empty() && len(change.Pending) == 0 {
+			fmt.Fprintf(w, "@%s/%s: up to date\n", change.Org, change.Team)
+			continue
+		}
+
+		fmt.Fprintf(w, "@%s/%s:\n", change.Org, change.Team)
+
+		for _, u := range change.Add {
+			fmt.Fprintf(w, "  + %s\n", u)
+		}
+
+		for _, u := range change.Remove {
+			fmt.Fprintf(w, "  - %s\n", u)
+		}
+
+		for _, u := range change.Pending {
+			fmt.Fprintf(w, "  ~ %s (invitation pending)\n", u)
+		}
+	}
+}
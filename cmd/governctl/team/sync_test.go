@@ -0,0 +1,556 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/team"
+	"github.com/unikraft/governance/internal/user"
+)
+
+func TestSyncReadOnly(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	t.Cleanup(srv.Close)
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{
+		GithubEndpoint: srv.URL,
+		ReadOnly:       true,
+		TeamsDir:       t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(kitcfg.WithConfigManager(context.Background(), cfgm))
+
+	err = (&Sync{Org: "unikraft"}).Pre(cmd, nil)
+	if !errors.Is(err, ghapi.ErrReadOnly) {
+		t.Fatalf("Pre() error = %v, want ErrReadOnly", err)
+	}
+
+	if requests != 0 {
+		t.Errorf("expected no requests to reach the server, got %d", requests)
+	}
+}
+
+// fakeAggregateTeams is a fake GitHub API where the "maintainers" team exists
+// with stale members and a pending invitation and the "reviewers" team does
+// not exist yet.
+type fakeAggregateTeams struct {
+	writes []string
+}
+
+func (f *fakeAggregateTeams) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v3/orgs/unikraft/teams")
+
+	if r.Method != http.MethodGet {
+		f.writes = append(f.writes, fmt.Sprintf("%s %s", r.Method, path))
+	}
+
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		fmt.Fprint(w, `[{"id":1,"name":"maintainers","slug":"maintainers"}]`)
+	case r.Method == http.MethodGet && path == "/maintainers":
+		fmt.Fprint(w, `{"id":1,"name":"maintainers","slug":"maintainers"}`)
+	case r.Method == http.MethodGet && path == "/maintainers/members":
+		fmt.Fprint(w, `[{"login":"alice"},{"login":"stale1"},{"login":"stale2"}]`)
+	case r.Method == http.MethodGet && path == "/maintainers/invitations":
+		fmt.Fprint(w, `[{"login":"bob"}]`)
+	case r.Method == http.MethodGet:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Not Found"}`)
+	case r.Method == http.MethodPost && path == "":
+		fmt.Fprint(w, `{"id":2,"name":"reviewers","slug":"reviewers"}`)
+	case r.Method == http.MethodPatch:
+		fmt.Fprint(w, `{"id":1,"name":"maintainers","slug":"maintainers"}`)
+	case r.Method == http.MethodPut:
+		fmt.Fprint(w, `{"state":"pending"}`)
+	case r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestSyncAggregates(t *testing.T) {
+	teams := []*team.Team{
+		{
+			Name:        "arch",
+			Maintainers: []user.User{{Github: "alice"}, {Github: "bob"}},
+			Reviewers:   []user.User{{Github: "carol"}},
+		},
+		{
+			Name:        "net",
+			Maintainers: []user.User{{Github: "alice"}},
+			Reviewers:   []user.User{{Github: "dave"}, {Github: "bob"}},
+		},
+	}
+
+	report := `@unikraft/maintainers:
+  - stale1
+  - stale2
+  ~ bob (invitation pending)
+@unikraft/reviewers:
+  + bob
+  + carol
+  + dave
+`
+
+	tests := []struct {
+		name        string
+		dryRun      bool
+		maxRemovals int
+		wantErr     bool
+		wantWrites  []string
+	}{
+		{
+			name:        "apply",
+			maxRemovals: 10,
+			wantWrites: []string{
+				"PATCH /maintainers",
+				"DELETE /maintainers/memberships/stale1",
+				"DELETE /maintainers/memberships/stale2",
+				"POST ",
+				"PUT /reviewers/memberships/bob",
+				"PUT /reviewers/memberships/carol",
+				"PUT /reviewers/memberships/dave",
+			},
+		},
+		{
+			name:        "dry-run",
+			dryRun:      true,
+			maxRemovals: 10,
+		},
+		{
+			name:        "too many removals",
+			maxRemovals: 1,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeAggregateTeams{}
+			srv := httptest.NewServer(fake)
+			t.Cleanup(srv.Close)
+
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{
+				DryRun: tt.dryRun,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out := &bytes.Buffer{}
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+			ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+			ghApi, err := ghapi.NewGithubClient(ctx, "", false, srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opts := &Sync{
+				AggregateMaintainers: "maintainers",
+				AggregateReviewers:   "reviewers",
+				AggregateTeams:       true,
+				MaxRemovals:          tt.maxRemovals,
+				Org:                  "unikraft",
+				ghApi:                ghApi,
+				teams:                teams,
+			}
+
+			_, err = opts.syncAggregates(ctx)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("syncAggregates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := out.String(); got != report {
+				t.Errorf("unexpected report:\n%s\nwant:\n%s", got, report)
+			}
+
+			if !reflect.DeepEqual(fake.writes, tt.wantWrites) {
+				t.Errorf("writes = %v, want %v", fake.writes, tt.wantWrites)
+			}
+		})
+	}
+}
+
+func TestSyncAggregatesDryRunPlan(t *testing.T) {
+	fake := &fakeAggregateTeams{}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+	ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+	ghApi, err := ghapi.NewGithubClient(ctx, "", false, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &Sync{
+		AggregateMaintainers: "maintainers",
+		AggregateReviewers:   "reviewers",
+		AggregateTeams:       true,
+		MaxRemovals:          10,
+		Org:                  "unikraft",
+		Output:               cmdutils.PlanOutputJSON,
+		ghApi:                ghApi,
+	}
+
+	teamsFile := filepath.Join(t.TempDir(), "teams.yaml")
+	if err := os.WriteFile(teamsFile, []byte(`name: sig-arch
+maintainers:
+  - github: alice
+  - github: bob
+reviewers:
+  - github: carol
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts.teams, err = team.NewListOfTeamsFromPath(ghApi, "unikraft", teamsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := opts.Run(ctx, nil); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	if len(fake.writes) != 0 {
+		t.Errorf("unexpected writes in dry-run: %v", fake.writes)
+	}
+
+	var got SyncPlan
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("could not parse plan: %v\n%s", err, out.String())
+	}
+
+	// The plan must describe exactly the memberships which would otherwise be
+	// added and removed, cf. TestSyncAggregates.  None of the regular teams
+	// exist yet.
+	want := SyncPlan{
+		Teams: []*ghapi.TeamMembershipChange{
+			{
+				Org:  "unikraft",
+				Team: "arch",
+				Add:  []string{"alice", "bob", "carol"},
+			},
+			{
+				Org:  "unikraft",
+				Team: "maintainers-arch",
+				Add:  []string{"alice", "bob"},
+			},
+			{
+				Org:  "unikraft",
+				Team: "reviewers-arch",
+				Add:  []string{"carol"},
+			},
+		},
+		Aggregates: []*ghapi.TeamMembershipChange{
+			{
+				Org:     "unikraft",
+				Team:    "maintainers",
+				Remove:  []string{"stale1", "stale2"},
+				Pending: []string{"bob"},
+			},
+			{
+				Org:  "unikraft",
+				Team: "reviewers",
+				Add:  []string{"carol"},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected plan:\n%s", out.String())
+	}
+}
+
+func TestSyncUnknownRepositories(t *testing.T) {
+	fake := &fakeAggregateTeams{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v3/orgs/unikraft/repos" {
+			fmt.Fprint(w, `[{"name":"unikraft"},{"name":"lib-lwip"}]`)
+			return
+		}
+
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+	ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+	ghApi, err := ghapi.NewGithubClient(ctx, "", false, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	teamsFile := filepath.Join(t.TempDir(), "sig-net.yaml")
+	if err := os.WriteFile(teamsFile, []byte(`name: sig-net
+members:
+  - github: alice
+repos:
+  - name: unikraft
+  - name: lib-lwpi
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &Sync{
+		Org:    "unikraft",
+		Output: cmdutils.PlanOutputJSON,
+		ghApi:  ghApi,
+	}
+
+	opts.teams, err = team.NewListOfTeamsFromPath(ghApi, "unikraft", teamsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := opts.Run(ctx, nil); err == nil {
+		t.Fatal("Run() expected an error for the unknown repository")
+	}
+
+	var got SyncPlan
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("could not parse plan: %v\n%s", err, out.String())
+	}
+
+	// The valid changes are still planned.
+	if len(got.Teams) != 1 || got.Teams[0].Team != "net" {
+		t.Errorf("unexpected teams in plan:\n%s", out.String())
+	}
+
+	want := []string{teamsFile + ": team net references unknown repository lib-lwpi (did you mean lib-lwip?)"}
+	if !reflect.DeepEqual(got.Warnings, want) {
+		t.Errorf("Warnings = %v, want %v", got.Warnings, want)
+	}
+}
+
+func TestSyncPrune(t *testing.T) {
+	tests := []struct {
+		name       string
+		dryRun     bool
+		teams      []*team.Team
+		wantErr    bool
+		wantOut    string
+		wantWrites []string
+	}{
+		{
+			name:  "apply",
+			teams: []*team.Team{{Name: "arch"}},
+			wantWrites: []string{
+				"DELETE /maintainers-old",
+				"DELETE /old",
+			},
+		},
+		{
+			name:    "dry-run",
+			dryRun:  true,
+			teams:   []*team.Team{{Name: "arch"}},
+			wantOut: "@unikraft/maintainers-old: delete\n@unikraft/old: delete\n",
+		},
+		{
+			name:    "without definitions",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var writes []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path := strings.TrimPrefix(r.URL.Path, "/api/v3/orgs/unikraft/teams")
+
+				if r.Method != http.MethodGet {
+					writes = append(writes, fmt.Sprintf("%s %s", r.Method, path))
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+
+				fmt.Fprint(w, `[
+					{"slug":"arch"},
+					{"slug":"maintainers-arch","parent":{"slug":"arch"}},
+					{"slug":"old"},
+					{"slug":"maintainers-old","parent":{"slug":"old"}},
+					{"slug":"security"}
+				]`)
+			}))
+			t.Cleanup(srv.Close)
+
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{
+				DryRun: tt.dryRun,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out := &bytes.Buffer{}
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+			ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+			ghApi, err := ghapi.NewGithubClient(ctx, "", false, srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opts := &Sync{
+				Org:   "unikraft",
+				ghApi: ghApi,
+				teams: tt.teams,
+			}
+
+			pruned, err := opts.prune(ctx)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("prune() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil {
+				return
+			}
+
+			if want := []string{"maintainers-old", "old"}; !reflect.DeepEqual(pruned, want) {
+				t.Errorf("prune() = %v, want %v", pruned, want)
+			}
+
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("unexpected report:\n%s\nwant:\n%s", got, tt.wantOut)
+			}
+
+			if !reflect.DeepEqual(writes, tt.wantWrites) {
+				t.Errorf("writes = %v, want %v", writes, tt.wantWrites)
+			}
+		})
+	}
+}
+
+func TestSyncPruneAfterPlan(t *testing.T) {
+	var writes []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v3/orgs/unikraft/teams")
+
+		switch {
+		case r.Method != http.MethodGet:
+			writes = append(writes, fmt.Sprintf("%s %s", r.Method, path))
+			w.WriteHeader(http.StatusNoContent)
+		case path == "":
+			fmt.Fprint(w, `[
+				{"slug":"arch"},
+				{"slug":"maintainers-arch","parent":{"slug":"arch"}},
+				{"slug":"reviewers-arch","parent":{"slug":"arch"}},
+				{"slug":"net"},
+				{"slug":"reviewers-net","parent":{"slug":"net"}},
+				{"slug":"old"},
+				{"slug":"maintainers-old","parent":{"slug":"old"}}
+			]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+	ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+	ghApi, err := ghapi.NewGithubClient(ctx, "", false, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	teamsFile := filepath.Join(t.TempDir(), "teams.yaml")
+	if err := os.WriteFile(teamsFile, []byte(`name: sig-arch
+maintainers:
+  - github: alice
+reviewers:
+  - github: bob
+---
+name: sig-net
+type: sig
+reviewers:
+  - github: carol
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	teams, err := team.NewListOfTeamsFromPath(ghApi, "unikraft", teamsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &Sync{
+		Org:    "unikraft",
+		Output: cmdutils.PlanOutputJSON,
+		Prune:  true,
+		ghApi:  ghApi,
+		teams:  teams,
+	}
+
+	// Planning the memberships first determines the types of the teams, which
+	// must not change the names of the sub-teams that are kept.
+	if err := opts.Run(ctx, nil); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	var got SyncPlan
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("could not parse plan: %v\n%s", err, out.String())
+	}
+
+	if want := []string{"maintainers-old", "old"}; !reflect.DeepEqual(got.Pruned, want) {
+		t.Errorf("Pruned = %v, want %v", got.Pruned, want)
+	}
+
+	if len(writes) != 0 {
+		t.Errorf("unexpected writes in dry-run: %v", writes)
+	}
+}
@@ -11,12 +11,14 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"kraftkit.sh/cmdfactory"
+
+	"github.com/unikraft/governance/internal/cmdutils"
 )
 
 type Team struct{}
 
 func New() *cobra.Command {
-	cmd, err := cmdfactory.New(&Team{}, cobra.Command{
+	cmd, err := cmdutils.New(&Team{}, cobra.Command{
 		Use:    "team SUBCOMMAND",
 		Short:  "Manage GitHub teams",
 		Hidden: true,
@@ -28,6 +30,8 @@ func New() *cobra.Command {
 		panic(err)
 	}
 
+	cmd.AddCommand(NewImport())
+	cmd.AddCommand(NewOffboard())
 	cmd.AddCommand(NewSync())
 
 	return cmd
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package templates
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/comment"
+	"github.com/unikraft/governance/internal/config"
+)
+
+type Lint struct{}
+
+func NewLint() *cobra.Command {
+	cmd, err := cmdutils.New(&Lint{}, cobra.Command{
+		Use:   "lint",
+		Short: "Render every comment template in every language",
+		Args:  cobra.NoArgs,
+		Long: heredoc.Doc(`
+		Render every translation of every comment template in the templates
+		directory and of the built-in ones with sample data, such that missing
+		variables and syntax errors are caught before a comment is left.
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "templates",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *Lint) Run(ctx context.Context, _ []string) error {
+	results, err := comment.New(kitcfg.G[config.Config](ctx).TemplatesDir).Lint()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	out := iostreams.G(ctx).Out
+
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Fprintf(out, "%s.%s: %s\n", result.Name, result.Language, result.Err)
+			continue
+		}
+
+		fmt.Fprintf(out, "%s.%s: ok\n", result.Name, result.Language)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d templates could not be rendered", failed, len(results))
+	}
+
+	return nil
+}
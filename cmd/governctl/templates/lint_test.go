@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package templates
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/config"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			files: map[string]string{
+				"welcome.de.md": "Willkommen, @{{ .User }}!",
+			},
+			want: "welcome.de: ok\nwelcome.en: ok\n",
+		},
+		{
+			name: "missing variable",
+			files: map[string]string{
+				"welcome.fr.md": "Bienvenue, @{{ .Author }}!",
+			},
+			want:    "welcome.en: ok\nwelcome.fr: could not render template welcome.fr.md: template: welcome.fr.md:1:15: executing \"welcome.fr.md\" at <.Author>: can't evaluate field Author in type comment.WelcomeData\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{
+				TemplatesDir: dir,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out := &bytes.Buffer{}
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+			ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+			if err := (&Lint{}).Run(ctx, nil); (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := out.String(); got != tt.want {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
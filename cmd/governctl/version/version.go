@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package version
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/cmdutils"
+	"github.com/unikraft/governance/internal/version"
+)
+
+type Version struct{}
+
+func New() *cobra.Command {
+	cmd, err := cmdutils.New(&Version{}, cobra.Command{
+		Use:   "version",
+		Short: "Show the version of governctl",
+		Args:  cobra.NoArgs,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *Version) Run(ctx context.Context, _ []string) error {
+	fmt.Fprintf(iostreams.G(ctx).Out, "governctl: %s", version.String())
+
+	return nil
+}
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package checkpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Finding identifies a note independently of the line it was reported on,
+// such that it is still recognised once the surrounding code has moved.
+type Finding struct {
+	Type    string `json:"type"`
+	File    string `json:"file"`
+	Message string `json:"message"`
+}
+
+// Baseline is a set of known findings which are suppressed, such that only
+// new findings are reported when checkpatch is introduced to a repository with
+// pre-existing ones.
+type Baseline struct {
+	findings map[Finding]bool
+	matched  map[Finding]bool
+}
+
+// baselineFile is the format of a baseline stored on disk.
+type baselineFile struct {
+	Findings []Finding `json:"findings"`
+}
+
+// NewBaseline returns an empty baseline.
+func NewBaseline() *Baseline {
+	return &Baseline{
+		findings: make(map[Finding]bool),
+		matched:  make(map[Finding]bool),
+	}
+}
+
+// NewBaselineFromFile reads a baseline previously written by WriteFile.
+func NewBaselineFromFile(path string) (*Baseline, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read baseline: %w", err)
+	}
+
+	var file baselineFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("could not parse baseline '%s': %w", path, err)
+	}
+
+	baseline := NewBaseline()
+	for _, finding := range file.Findings {
+		baseline.findings[finding] = true
+	}
+
+	return baseline, nil
+}
+
+// findingOf returns the finding which identifies the note.
+func findingOf(note *Note) Finding {
+	return Finding{
+		Type:    note.Type,
+		File:    note.File,
+		Message: note.Message,
+	}
+}
+
+// Add records the findings of the provided notes.
+func (b *Baseline) Add(notes ...*Note) {
+	for _, note := range notes {
+		b.findings[findingOf(note)] = true
+	}
+}
+
+// Len returns the number of findings in the baseline.
+func (b *Baseline) Len() int {
+	return len(b.findings)
+}
+
+// Contains returns whether the note is a known finding.  A nil baseline does
+// not contain any.
+func (b *Baseline) Contains(note *Note) bool {
+	if b == nil {
+		return false
+	}
+
+	finding := findingOf(note)
+	if !b.findings[finding] {
+		return false
+	}
+
+	b.matched[finding] = true
+
+	return true
+}
+
+// Stale returns the findings of the baseline which have not been matched by
+// any note, which indicates that they have been fixed and the baseline can be
+// regenerated.
+func (b *Baseline) Stale() []Finding {
+	var stale []Finding
+	for finding := range b.findings {
+		if !b.matched[finding] {
+			stale = append(stale, finding)
+		}
+	}
+
+	sortFindings(stale)
+
+	return stale
+}
+
+// WriteFile stores the baseline at the provided path.  Findings are sorted
+// such that regenerating the baseline results in a minimal diff.
+func (b *Baseline) WriteFile(path string) error {
+	file := baselineFile{
+		Findings: make([]Finding, 0, len(b.findings)),
+	}
+
+	for finding := range b.findings {
+		file.Findings = append(file.Findings, finding)
+	}
+
+	sortFindings(file.Findings)
+
+	content, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal baseline: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("could not write baseline: %w", err)
+	}
+
+	return nil
+}
+
+// sortFindings orders the findings by file, type and message.
+func sortFindings(findings []Finding) {
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+
+		if findings[i].Type != findings[j].Type {
+			return findings[i].Type < findings[j].Type
+		}
+
+		return findings[i].Message < findings[j].Message
+	})
+}
+
+// suppressBaseline drops the notes which are known findings of the baseline
+// and returns the remaining notes along with the suppressed ones.
+func suppressBaseline(notes []*Note, baseline *Baseline) ([]*Note, []*Note) {
+	if baseline == nil {
+		return notes, nil
+	}
+
+	var suppressed []*Note
+
+	kept := make([]*Note, 0, len(notes))
+	for _, note := range notes {
+		if baseline.Contains(note) {
+			suppressed = append(suppressed, note)
+			continue
+		}
+
+		kept = append(kept, note)
+	}
+
+	return kept, suppressed
+}
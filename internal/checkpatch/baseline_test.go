@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package checkpatch
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBaseline(t *testing.T) {
+	known := []*Note{
+		{Level: NoteLevelWarning, Type: "LONG_LINE", File: "lib/foo/foo.c", Line: 3, Message: "line length of 90 exceeds 80 columns"},
+		{Level: NoteLevelError, Type: "SPACING", File: "lib/bar/bar.c", Line: 7, Message: "space required after that ','"},
+	}
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+
+	written := NewBaseline()
+	written.Add(known...)
+	if err := written.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	baseline, err := NewBaselineFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if baseline.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", baseline.Len())
+	}
+
+	notes := []*Note{
+		// Known, even though the code has moved.
+		{Level: NoteLevelWarning, Type: "LONG_LINE", File: "lib/foo/foo.c", Line: 12, Message: "line length of 90 exceeds 80 columns"},
+		// New, as it is in another file.
+		{Level: NoteLevelWarning, Type: "LONG_LINE", File: "lib/foo/foo.h", Line: 3, Message: "line length of 90 exceeds 80 columns"},
+		// New, as it has another message.
+		{Level: NoteLevelWarning, Type: "LONG_LINE", File: "lib/foo/foo.c", Line: 4, Message: "line length of 95 exceeds 80 columns"},
+	}
+
+	kept, suppressed := suppressBaseline(notes, baseline)
+
+	if !reflect.DeepEqual(kept, notes[1:]) {
+		t.Errorf("suppressBaseline() kept %v, want the new findings %v", kept, notes[1:])
+	}
+
+	if !reflect.DeepEqual(suppressed, notes[:1]) {
+		t.Errorf("suppressBaseline() suppressed %v, want the known finding %v", suppressed, notes[:1])
+	}
+
+	if want := []Finding{findingOf(known[1])}; !reflect.DeepEqual(baseline.Stale(), want) {
+		t.Errorf("Stale() = %v, want %v", baseline.Stale(), want)
+	}
+
+	if kept, _ := suppressBaseline(notes, nil); len(kept) != len(notes) {
+		t.Errorf("expected no notes to be suppressed without a baseline")
+	}
+
+	if err := os.WriteFile(path, []byte("LONG_LINE"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewBaselineFromFile(path); err == nil {
+		t.Errorf("NewBaselineFromFile() expected an error for a malformed baseline")
+	}
+}
+
+func TestNewCheckpatchWithBaseline(t *testing.T) {
+	script := testScript(t)
+	dir := t.TempDir()
+
+	conf, err := ExtractEmbeddedConf(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, "0001-lib-foo-Add-foo.patch")
+	if err := os.WriteFile(file, []byte(fixturePatch), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := []PatchOption{
+		WithCheckpatchScriptPath(script),
+		WithCheckpatchConfPath(conf),
+	}
+
+	all, err := NewCheckpatch(testContext(), file, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The long line is new, all other findings are known.
+	baseline := NewBaseline()
+	for _, note := range all.Notes() {
+		if note.Type != "LONG_LINE" {
+			baseline.Add(note)
+		}
+	}
+
+	patch, err := NewCheckpatch(testContext(), file, append(opts, WithBaseline(baseline))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := patch.Counts(); !reflect.DeepEqual(got, map[NoteLevel]int{NoteLevelWarning: 1}) {
+		t.Errorf("Counts() = %v, want only the new warning", got)
+	}
+
+	if len(patch.Suppressed()) != len(all.Notes())-1 {
+		t.Errorf("Suppressed() = %d notes, want %d", len(patch.Suppressed()), len(all.Notes())-1)
+	}
+
+	if err := WithMaxSeverity(NoteLevelError)(patch); err != nil {
+		t.Fatal(err)
+	}
+
+	if patch.Failed() {
+		t.Errorf("Failed() = true, want false as the known errors are suppressed")
+	}
+}
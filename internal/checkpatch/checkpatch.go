@@ -20,30 +20,64 @@ import (
 	"kraftkit.sh/log"
 )
 
-type Checkpatch struct {
-	NoTree    bool   `flag:"no-tree"`
-	Patch     bool   `flag:"patch"`
-	Ignore    string `flag:"ignore"`
-	ShowTypes bool   `flag:"flag-types"`
-	Color     string `flag:"color"`
-}
-
 type Patch struct {
-	File    string
-	ignores []string
-	notes   []*Note
-	stderr  io.Writer
-	script  string
-	conf    string
+	File        string
+	ignores     []string
+	notes       []*Note
+	stderr      io.Writer
+	script      string
+	conf        string
+	strict      bool
+	showTypes   bool
+	maxSeverity NoteLevel
+	overrides   map[string]NoteLevel
+	baseline    *Baseline
+	suppressed  []*Note
 }
 
 type NoteLevel string
 
 const (
+	NoteLevelCheck   = NoteLevel("check")
 	NoteLevelWarning = NoteLevel("warning")
 	NoteLevelError   = NoteLevel("error")
+
+	// NoteLevelIgnore is not reported by checkpatch but can be used to override
+	// the level of a type of note such that its notes are dropped, see
+	// WithLevelOverrides.
+	NoteLevelIgnore = NoteLevel("ignore")
 )
 
+// NoteLevels are all the levels of notes in increasing order of severity.
+var NoteLevels = []NoteLevel{
+	NoteLevelCheck,
+	NoteLevelWarning,
+	NoteLevelError,
+}
+
+// ParseNoteLevel returns the note level of the provided name.
+func ParseNoteLevel(name string) (NoteLevel, error) {
+	for _, level := range NoteLevels {
+		if strings.EqualFold(name, string(level)) {
+			return level, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown checkpatch level '%s': expected one of error, warning, check", name)
+}
+
+// Severity returns the rank of the level, where more severe levels have a
+// higher rank.  Unknown levels have a rank of -1.
+func (level NoteLevel) Severity() int {
+	for i, l := range NoteLevels {
+		if l == level {
+			return i
+		}
+	}
+
+	return -1
+}
+
 // Note is a result from executing checkpatch.
 type Note struct {
 	Level   NoteLevel `json:"level"`
@@ -75,6 +109,10 @@ func NewCheckpatch(ctx context.Context, file string, opts ...PatchOption) (*Patc
 		patch.conf = ".checkpatch.conf"
 	}
 
+	if patch.maxSeverity == "" {
+		patch.maxSeverity = NoteLevelWarning
+	}
+
 	args := []string{
 		"--patch",
 		"--color=never",
@@ -90,6 +128,14 @@ func NewCheckpatch(ctx context.Context, file string, opts ...PatchOption) (*Patc
 		args = append(args, strings.Split(line, " ")...)
 	}
 
+	if patch.strict {
+		args = append(args, "--strict")
+	}
+
+	if patch.showTypes {
+		args = append(args, "--show-types")
+	}
+
 	// Extra ignores from the commits.
 	if len(patch.ignores) > 0 {
 		args = append(args,
@@ -115,30 +161,25 @@ func NewCheckpatch(ctx context.Context, file string, opts ...PatchOption) (*Patc
 
 	var note *Note
 	for _, line := range strings.Split(strings.TrimSuffix(string(out), "\n"), "\n") {
-		if warning := strings.TrimPrefix(line, "WARNING:"); len(warning) < len(line) {
-			split := strings.SplitN(warning, ":", 2)
-			if len(split) != 2 {
-				return nil, fmt.Errorf("malformed checkpatch line '%s': expected ':'", line)
-			}
-
-			note = &Note{
-				Level:   NoteLevelWarning,
-				Type:    split[0],
-				Message: strings.TrimSpace(split[1]),
-				Excerpt: make([]string, 0),
-			}
-			patch.notes = append(patch.notes, note)
+		if level, rest, ok := parseNoteLevel(line); ok {
+			// The type of the note is only printed with --show-types, in which case
+			// it directly follows the level without a space.
+			var typ, message string
+			if strings.HasPrefix(rest, " ") {
+				message = rest
+			} else {
+				split := strings.SplitN(rest, ":", 2)
+				if len(split) != 2 {
+					return nil, fmt.Errorf("malformed checkpatch line '%s': expected ':'", line)
+				}
 
-		} else if erro := strings.TrimPrefix(line, "ERROR:"); len(erro) < len(line) {
-			split := strings.SplitN(erro, ":", 2)
-			if len(split) != 2 {
-				return nil, fmt.Errorf("malformed checkpatch line '%s': expected ':'", line)
+				typ, message = split[0], split[1]
 			}
 
 			note = &Note{
-				Level:   NoteLevelError,
-				Type:    split[0],
-				Message: strings.TrimSpace(split[1]),
+				Level:   level,
+				Type:    typ,
+				Message: strings.TrimSpace(message),
 				Excerpt: make([]string, 0),
 			}
 			patch.notes = append(patch.notes, note)
@@ -169,10 +210,79 @@ func NewCheckpatch(ctx context.Context, file string, opts ...PatchOption) (*Patc
 		}
 	}
 
+	patch.notes = overrideLevels(patch.notes, patch.overrides)
+	patch.notes, patch.suppressed = suppressBaseline(patch.notes, patch.baseline)
+
 	return &patch, nil
 }
 
+// overrideLevels reclassifies the notes whose type has an overridden level and
+// drops those which are ignored.
+func overrideLevels(notes []*Note, overrides map[string]NoteLevel) []*Note {
+	if len(overrides) == 0 {
+		return notes
+	}
+
+	kept := make([]*Note, 0, len(notes))
+	for _, note := range notes {
+		level, ok := overrides[note.Type]
+		if !ok || note.Type == "" {
+			kept = append(kept, note)
+			continue
+		}
+
+		if level == NoteLevelIgnore {
+			continue
+		}
+
+		note.Level = level
+		kept = append(kept, note)
+	}
+
+	return kept
+}
+
+// parseNoteLevel returns the level of a line which starts a new note and the
+// remainder of the line following the level.
+func parseNoteLevel(line string) (NoteLevel, string, bool) {
+	for _, level := range NoteLevels {
+		if rest, ok := strings.CutPrefix(line, strings.ToUpper(string(level))+":"); ok {
+			return level, rest, true
+		}
+	}
+
+	return "", "", false
+}
+
 // Notes returns the results from the checkpatch.
 func (patch *Patch) Notes() []*Note {
 	return patch.notes
 }
+
+// Suppressed returns the notes which are known findings of the baseline and
+// are therefore neither reported by Notes nor counted, see WithBaseline.
+func (patch *Patch) Suppressed() []*Note {
+	return patch.suppressed
+}
+
+// Counts returns the number of notes per level.
+func (patch *Patch) Counts() map[NoteLevel]int {
+	counts := make(map[NoteLevel]int)
+	for _, note := range patch.notes {
+		counts[note.Level]++
+	}
+
+	return counts
+}
+
+// Failed returns whether any of the notes is at least as severe as the
+// configured threshold, see WithMaxSeverity.
+func (patch *Patch) Failed() bool {
+	for _, note := range patch.notes {
+		if note.Level.Severity() >= patch.maxSeverity.Severity() {
+			return true
+		}
+	}
+
+	return false
+}
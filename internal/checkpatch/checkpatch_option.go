@@ -5,7 +5,11 @@
 
 package checkpatch
 
-import "io"
+import (
+	"fmt"
+	"io"
+	"strings"
+)
 
 type PatchOption func(*Patch) error
 
@@ -45,3 +49,70 @@ func WithCheckpatchConfPath(conf string) PatchOption {
 		return nil
 	}
 }
+
+// WithStrict sets whether checkpatch runs in strict mode, which additionally
+// reports notes at the check level.
+func WithStrict(strict bool) PatchOption {
+	return func(patch *Patch) error {
+		patch.strict = strict
+		return nil
+	}
+}
+
+// WithShowTypes sets whether checkpatch reports the type of each note, which
+// is necessary to ignore individual types.
+func WithShowTypes(showTypes bool) PatchOption {
+	return func(patch *Patch) error {
+		patch.showTypes = showTypes
+		return nil
+	}
+}
+
+// WithMaxSeverity sets the least severe level of notes which fail the check.
+// Less severe notes are still reported.  By default, both warnings and errors
+// fail the check.
+func WithMaxSeverity(level NoteLevel) PatchOption {
+	return func(patch *Patch) error {
+		if level.Severity() < 0 {
+			return fmt.Errorf("unknown checkpatch level '%s'", level)
+		}
+
+		patch.maxSeverity = level
+		return nil
+	}
+}
+
+// WithLevelOverrides reclassifies the notes of the provided types, e.g.
+// LONG_LINE, to the provided level once checkpatch has run, such that the
+// counts and the outcome of the check reflect the policy of the organisation
+// rather than checkpatch's defaults.  Notes of types overridden with
+// NoteLevelIgnore are dropped.  Overrides require the types of notes to be
+// shown, see WithShowTypes.
+func WithLevelOverrides(overrides map[string]NoteLevel) PatchOption {
+	return func(patch *Patch) error {
+		if patch.overrides == nil {
+			patch.overrides = make(map[string]NoteLevel)
+		}
+
+		for typ, level := range overrides {
+			if level != NoteLevelIgnore && level.Severity() < 0 {
+				return fmt.Errorf("unknown checkpatch level '%s' for %s", level, typ)
+			}
+
+			patch.overrides[strings.ToUpper(typ)] = level
+		}
+
+		return nil
+	}
+}
+
+// WithBaseline suppresses the notes which are known findings of the provided
+// baseline, such that only new findings are reported and fail the check.
+// Suppression happens after the levels have been overridden, see
+// WithLevelOverrides.
+func WithBaseline(baseline *Baseline) PatchOption {
+	return func(patch *Patch) error {
+		patch.baseline = baseline
+		return nil
+	}
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package checkpatch
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFailed(t *testing.T) {
+	notes := []*Note{
+		{Level: NoteLevelCheck},
+		{Level: NoteLevelWarning},
+	}
+
+	tests := []struct {
+		name        string
+		notes       []*Note
+		maxSeverity NoteLevel
+		want        bool
+	}{
+		{
+			name:        "fail on errors only",
+			notes:       notes,
+			maxSeverity: NoteLevelError,
+			want:        false,
+		},
+		{
+			name:        "fail on warnings",
+			notes:       notes,
+			maxSeverity: NoteLevelWarning,
+			want:        true,
+		},
+		{
+			name:        "fail on checks",
+			notes:       notes[:1],
+			maxSeverity: NoteLevelCheck,
+			want:        true,
+		},
+		{
+			name:        "no notes",
+			maxSeverity: NoteLevelCheck,
+			want:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch := &Patch{notes: tt.notes}
+			if err := WithMaxSeverity(tt.maxSeverity)(patch); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := patch.Failed(); got != tt.want {
+				t.Errorf("Failed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNoteLevel(t *testing.T) {
+	for _, name := range []string{"error", "WARNING", "Check"} {
+		if _, err := ParseNoteLevel(name); err != nil {
+			t.Errorf("ParseNoteLevel(%q) error = %v", name, err)
+		}
+	}
+
+	if _, err := ParseNoteLevel("info"); err == nil {
+		t.Error("ParseNoteLevel(\"info\") expected an error")
+	}
+}
+
+func TestNewCheckpatchWithoutTypes(t *testing.T) {
+	script := testScript(t)
+	dir := t.TempDir()
+
+	// A configuration which does not show types, such that notes can only be
+	// told apart by their message.
+	conf := filepath.Join(dir, "notypes.conf")
+	if err := os.WriteFile(conf, []byte("--no-tree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, "0001-lib-foo-Add-foo.patch")
+	if err := os.WriteFile(file, []byte(fixturePatch), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	patch, err := NewCheckpatch(testContext(),
+		file,
+		WithCheckpatchScriptPath(script),
+		WithCheckpatchConfPath(conf),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := patch.Counts()
+	if counts[NoteLevelError] != 2 || counts[NoteLevelWarning] != 1 {
+		t.Errorf("Counts() = %v, want 2 errors and 1 warning", counts)
+	}
+
+	for _, note := range patch.Notes() {
+		if note.Type != "" {
+			t.Errorf("expected no type for %q, got %q", note.Message, note.Type)
+		}
+	}
+}
+
+func TestWithLevelOverrides(t *testing.T) {
+	notes := func() []*Note {
+		return []*Note{
+			{Level: NoteLevelWarning, Type: "LONG_LINE"},
+			{Level: NoteLevelWarning, Type: "SPDX_LICENSE_TAG"},
+			{Level: NoteLevelError, Type: "TRAILING_WHITESPACE"},
+			{Level: NoteLevelCheck, Type: "CAMELCASE"},
+			{Level: NoteLevelError, Type: "CODE_INDENT"},
+		}
+	}
+
+	patch := &Patch{}
+	if err := WithLevelOverrides(map[string]NoteLevel{
+		"long_line":           NoteLevelCheck,
+		"SPDX_LICENSE_TAG":    NoteLevelError,
+		"TRAILING_WHITESPACE": NoteLevelIgnore,
+	})(patch); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WithMaxSeverity(NoteLevelError)(patch); err != nil {
+		t.Fatal(err)
+	}
+
+	patch.notes = overrideLevels(notes(), patch.overrides)
+
+	want := map[NoteLevel]int{
+		NoteLevelCheck: 2,
+		NoteLevelError: 2,
+	}
+	if got := patch.Counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Counts() = %v, want %v", got, want)
+	}
+
+	if !patch.Failed() {
+		t.Error("Failed() = false, want true as SPDX_LICENSE_TAG is an error")
+	}
+
+	// Without the overrides, the same notes only count the original levels.
+	if got := overrideLevels(notes(), nil); len(got) != 5 || got[0].Level != NoteLevelWarning {
+		t.Errorf("overrideLevels() without overrides changed the notes")
+	}
+
+	if err := WithLevelOverrides(map[string]NoteLevel{"LONG_LINE": "info"})(&Patch{}); err == nil {
+		t.Error("WithLevelOverrides() expected an error for an unknown level")
+	}
+}
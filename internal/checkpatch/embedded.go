@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package checkpatch
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+//go:embed embedded/checkpatch.conf
+var embeddedConf []byte
+
+// ExtractEmbeddedConf writes the default checkpatch configuration embedded
+// into the binary into the provided directory, using the same layout as a
+// repository which provides its own, and returns the path to the file.
+func ExtractEmbeddedConf(dir string) (string, error) {
+	conf := filepath.Join(dir, ".checkpatch.conf")
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("could not create directory for embedded checkpatch configuration: %w", err)
+	}
+
+	if err := os.WriteFile(conf, embeddedConf, 0o644); err != nil {
+		return "", fmt.Errorf("could not extract embedded checkpatch configuration: %w", err)
+	}
+
+	return conf, nil
+}
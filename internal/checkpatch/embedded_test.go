@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package checkpatch
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"kraftkit.sh/log"
+)
+
+const fixturePatch = `From 0123456789abcdef0123456789abcdef01234567 Mon Sep 17 00:00:00 2001
+From: Jane Doe <jane@example.com>
+Date: Mon, 1 Jan 2024 00:00:00 +0000
+Subject: [PATCH] lib/foo: Add foo
+
+Add foo.
+
+---
+ lib/foo/foo.c | 5 +++++
+ 1 file changed, 5 insertions(+)
+
+diff --git a/lib/foo/foo.c b/lib/foo/foo.c
+new file mode 100644
+index 0000000..1111111
+--- /dev/null
++++ b/lib/foo/foo.c
+@@ -0,0 +1,5 @@
++int foo(void) 
++{
++	return 0; /* a very long comment which goes well beyond the eighty column limit */
++}
++
++
+`
+
+func testContext() context.Context {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	return log.WithLogger(context.Background(), logger)
+}
+
+// testScript returns the path to the stand-in checkpatch.pl script of the
+// tests, skipping the test if it cannot be run.
+func testScript(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("perl"); err != nil {
+		t.Skip("perl is not available")
+	}
+
+	script, err := filepath.Abs(filepath.Join("testdata", "checkpatch.pl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return script
+}
+
+func TestExtractEmbeddedConf(t *testing.T) {
+	script := testScript(t)
+	dir := t.TempDir()
+
+	conf, err := ExtractEmbeddedConf(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := filepath.Join(dir, ".checkpatch.conf"); conf != want {
+		t.Errorf("ExtractEmbeddedConf() = %s, want %s", conf, want)
+	}
+
+	if b, err := os.ReadFile(conf); err != nil || !strings.Contains(string(b), "--show-types") {
+		t.Fatalf("extracted configuration = %q (%v), want the embedded one", b, err)
+	}
+
+	file := filepath.Join(dir, "0001-lib-foo-Add-foo.patch")
+	if err := os.WriteFile(file, []byte(fixturePatch), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		ignores []string
+		strict  bool
+		expect  map[string]NoteLevel
+	}{
+		{
+			name: "all checks",
+			expect: map[string]NoteLevel{
+				"TRAILING_WHITESPACE": NoteLevelError,
+				"LONG_LINE":           NoteLevelWarning,
+				"MISSING_SIGN_OFF":    NoteLevelError,
+			},
+		},
+		{
+			name:   "strict",
+			strict: true,
+			expect: map[string]NoteLevel{
+				"TRAILING_WHITESPACE": NoteLevelError,
+				"LONG_LINE":           NoteLevelWarning,
+				"MISSING_SIGN_OFF":    NoteLevelError,
+				"LINE_SPACING":        NoteLevelCheck,
+			},
+		},
+		{
+			name:    "ignored checks",
+			ignores: []string{"LONG_LINE", "MISSING_SIGN_OFF"},
+			expect: map[string]NoteLevel{
+				"TRAILING_WHITESPACE": NoteLevelError,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := NewCheckpatch(testContext(),
+				file,
+				WithIgnore(tt.ignores...),
+				WithCheckpatchScriptPath(script),
+				WithCheckpatchConfPath(conf),
+				WithStrict(tt.strict),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make(map[string]NoteLevel)
+			for _, note := range patch.Notes() {
+				got[note.Type] = note.Level
+			}
+
+			if len(got) != len(tt.expect) {
+				t.Errorf("expected %d notes but got %v", len(tt.expect), got)
+			}
+
+			for typ, level := range tt.expect {
+				if got[typ] != level {
+					t.Errorf("expected %s to be reported as %s but got %q", typ, level, got[typ])
+				}
+			}
+
+			for _, note := range patch.Notes() {
+				if note.Type == "TRAILING_WHITESPACE" && (note.File != "lib/foo/foo.c" || note.Line != 1) {
+					t.Errorf("expected trailing whitespace at lib/foo/foo.c:1 but got %s:%d", note.File, note.Line)
+				}
+			}
+		})
+	}
+}
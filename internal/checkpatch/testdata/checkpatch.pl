@@ -0,0 +1,215 @@
+#!/usr/bin/env perl
+# SPDX-License-Identifier: BSD-3-Clause
+# Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+# Licensed under the BSD-3-Clause License (the "License").
+# You may not use this file except in compliance with the License.
+#
+# Stand-in for checkpatch.pl used by the tests of governctl.  It accepts the
+# same command-line interface and produces the same output format as the Linux
+# kernel's scripts/checkpatch.pl, but only implements a small subset of its
+# checks.  It is not shipped with governctl.
+
+use strict;
+use warnings;
+
+use Getopt::Long qw(:config no_auto_abbrev);
+
+my $V = '0.2.0';
+
+my $show_types = 0;
+my $max_line_length = 80;
+my $tabsize = 8;
+my $help = 0;
+my $version = 0;
+my $signoff = 1;
+my $strict = 0;
+my $color = 'auto';
+my $root;
+my @ignore = ();
+
+GetOptions(
+	'patch'             => sub {},
+	'no-tree'           => sub {},
+	'tree!'             => sub {},
+	'strict!'           => \$strict,
+	'terse'             => sub {},
+	'summary-file!'     => sub {},
+	'emacs!'            => sub {},
+	'root=s'            => \$root,
+	'color=s'           => \$color,
+	'ignore=s'          => \@ignore,
+	'show-types!'       => \$show_types,
+	'max-line-length=i' => \$max_line_length,
+	'tab-size=i'        => \$tabsize,
+	'signoff!'          => \$signoff,
+	'version'           => \$version,
+	'h|help'            => \$help,
+) or exit(2);
+
+if ($help) {
+	print "Usage: $0 [OPTIONS] patchfile\n";
+	exit(0);
+}
+
+if ($version) {
+	print "Version: $V\n";
+	exit(0);
+}
+
+my %ignore_type = ();
+foreach my $word (map { split(/,/, $_) } @ignore) {
+	$word =~ s/\s+//g;
+	$ignore_type{uc($word)}++ if ($word ne '');
+}
+
+my $errors = 0;
+my $warnings = 0;
+my $checks = 0;
+
+# report prints a single note in the format of the upstream checkpatch.pl.
+sub report {
+	my ($level, $type, $msg, $where) = @_;
+
+	return if ($ignore_type{$type});
+
+	my $out = $level . ':';
+	$out .= $type . ':' if ($show_types);
+	$out .= ' ' . $msg . "\n";
+	$out .= $where if (defined $where);
+	print $out . "\n";
+
+	if ($level eq 'ERROR') {
+		$errors++;
+	} elsif ($level eq 'CHECK') {
+		$checks++;
+	} else {
+		$warnings++;
+	}
+}
+
+# expand_tabs returns the line with tabs replaced by spaces.
+sub expand_tabs {
+	my ($str) = @_;
+	my $res = '';
+	my $n = 0;
+
+	for my $c (split(//, $str)) {
+		if ($c eq "\t") {
+			$res .= ' ';
+			$n++;
+			for (; ($n % $tabsize) != 0; $n++) {
+				$res .= ' ';
+			}
+			next;
+		}
+		$res .= $c;
+		$n++;
+	}
+
+	return $res;
+}
+
+my $exit = 0;
+
+foreach my $filename (@ARGV) {
+	my $fh;
+	if (!open($fh, '<', $filename)) {
+		print STDERR "$0: $filename: open failed - $!\n";
+		$exit = 1;
+		next;
+	}
+
+	my $linenr = 0;
+	my $in_commit_log = 1;
+	my $signed_off = 0;
+	my $realfile = '';
+	my $realline = 0;
+	my $prevline = '';
+	my $prevadded = '';
+
+	while (my $line = <$fh>) {
+		$linenr++;
+		chomp($line);
+
+		if ($in_commit_log && $line =~ /^\s*Signed-off-by:/i) {
+			$signed_off++;
+		}
+
+		if ($line =~ /^diff --git/ || $line =~ /^--- /) {
+			$in_commit_log = 0;
+		}
+
+		if ($line =~ /^\+\+\+ (?:[bw]\/)?(\S+)/) {
+			$realfile = $1;
+			$in_commit_log = 0;
+			next;
+		}
+
+		if ($line =~ /^\@\@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? \@\@/) {
+			$realline = $1 - 1;
+			next;
+		}
+
+		if ($line =~ /^\\ No newline at end of file/ && $prevline =~ /^\+/) {
+			report('WARNING', 'MISSING_EOF_NEWLINE',
+				'adding a line without newline at end of file',
+				"#$linenr: FILE: $realfile:$realline:\n$prevline\n");
+		}
+
+		$prevline = $line;
+
+		next if ($in_commit_log || $realfile eq '');
+
+		$realline++ if ($line =~ /^[ +]/);
+
+		if ($line !~ /^\+/) {
+			$prevadded = '';
+			next;
+		}
+
+		my $here = "#$linenr: FILE: $realfile:$realline:\n$line\n";
+
+		if ($line =~ /\r$/) {
+			report('ERROR', 'DOS_LINE_ENDINGS',
+				'DOS line endings', $here);
+		} elsif ($line =~ /^\+.*\S\s+$/ || $line =~ /^\+\s+$/) {
+			report('ERROR', 'TRAILING_WHITESPACE',
+				'trailing whitespace', $here);
+		}
+
+		if ($line =~ /^\+\s* \t/) {
+			report('WARNING', 'SPACE_BEFORE_TAB',
+				'please, no space before tabs', $here);
+		}
+
+		my $length = length(expand_tabs(substr($line, 1)));
+		if ($length > $max_line_length) {
+			report('WARNING', 'LONG_LINE',
+				"line length of $length exceeds $max_line_length columns", $here);
+		}
+
+		if ($strict && $line =~ /^\+\s*$/ && $prevadded =~ /^\+\s*$/) {
+			report('CHECK', 'LINE_SPACING',
+				"Please don't use multiple blank lines", $here);
+		}
+
+		$prevadded = $line;
+	}
+
+	close($fh);
+
+	if ($signoff && !$signed_off) {
+		report('ERROR', 'MISSING_SIGN_OFF',
+			'Missing Signed-off-by: line(s)');
+	}
+
+	if ($strict) {
+		print "total: $errors errors, $warnings warnings, $checks checks, $linenr lines checked\n";
+	} else {
+		print "total: $errors errors, $warnings warnings, $linenr lines checked\n";
+	}
+
+	$exit = 1 if ($errors || $warnings || $checks);
+}
+
+exit($exit);
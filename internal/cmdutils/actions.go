@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package cmdutils
+
+import "os"
+
+// InGithubActions returns whether governctl is running in a GitHub Actions
+// workflow, which always sets GITHUB_ACTIONS to "true".
+func InGithubActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
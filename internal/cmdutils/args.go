@@ -26,7 +26,7 @@ import (
 func ParseOrgRepoAndPullRequestArgs(args []string) (string, string, int, error) {
 	// If we are in a GitHub actions context and no arguments have been
 	// specified, determine the values of org, repo and prId from the environment.
-	if os.Getenv("GITHUB_ACTIONS") == "true" && len(args) == 0 {
+	if InGithubActions() && len(args) == 0 {
 		split := strings.SplitN(os.Getenv("GITHUB_REPOSITORY"), "/", 0)
 		if len(split) != 2 {
 			return "", "", 0, fmt.Errorf("could not parse environmental variable 'GITHUB_REPOSITORY': invalid format")
@@ -115,3 +115,48 @@ func ParseOrgRepoAndPullRequestArgs(args []string) (string, string, int, error)
 
 	return "", "", 0, fmt.Errorf("could not parse arguments: invalid format: expected ORG/REPO/PRID")
 }
+
+// ParseOrgRepoAndIssueArgs accepts input command-line arguments in the
+// following forms:
+//
+//   - []string{"ORG/REPO/ID"}, e.g. unikraft/unikraft/123
+//   - []string{"ORG/REPO#ID"}, e.g. unikraft/unikraft#123
+//   - []string{"https://github.com/org/repo/issues/123"}
+func ParseOrgRepoAndIssueArgs(args []string) (string, string, int, error) {
+	if len(args) != 1 {
+		return "", "", 0, fmt.Errorf("could not parse arguments: invalid format: expected ORG/REPO/ID")
+	}
+
+	ref := args[0]
+
+	if strings.HasPrefix(ref, "https://") {
+		uri, err := url.ParseRequestURI(ref)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("expected URL: %w", err)
+		}
+		if uri.Host != "github.com" {
+			return "", "", 0, fmt.Errorf("not a GitHub URL")
+		}
+
+		orgRepo, id, ok := strings.Cut(strings.Trim(uri.Path, "/"), "/issues/")
+		if !ok {
+			return "", "", 0, fmt.Errorf("expected GitHub URL to contain issue")
+		}
+
+		ref = orgRepo + "/" + id
+	} else if orgRepo, id, ok := strings.Cut(ref, "#"); ok {
+		ref = orgRepo + "/" + id
+	}
+
+	split := strings.SplitN(ref, "/", 3)
+	if len(split) != 3 || split[0] == "" || split[1] == "" {
+		return "", "", 0, fmt.Errorf("expected format ORG/REPO/ID")
+	}
+
+	id, err := strconv.Atoi(split[2])
+	if err != nil {
+		return "", "", 0, fmt.Errorf("issue ID is not numeric")
+	}
+
+	return split[0], split[1], id, nil
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package cmdutils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseOrgRepoAndIssueArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name: "path",
+			args: []string{"unikraft/unikraft/42"},
+		},
+		{
+			name: "reference",
+			args: []string{"unikraft/unikraft#42"},
+		},
+		{
+			name: "url",
+			args: []string{"https://github.com/unikraft/unikraft/issues/42"},
+		},
+		{
+			name: "url with trailing slash",
+			args: []string{"https://github.com/unikraft/unikraft/issues/42/"},
+		},
+		{
+			name:    "pull request url",
+			args:    []string{"https://github.com/unikraft/unikraft/pull/42"},
+			wantErr: "expected GitHub URL to contain issue",
+		},
+		{
+			name:    "foreign url",
+			args:    []string{"https://gitlab.com/unikraft/unikraft/issues/42"},
+			wantErr: "not a GitHub URL",
+		},
+		{
+			name:    "missing repository",
+			args:    []string{"unikraft#42"},
+			wantErr: "expected format ORG/REPO/ID",
+		},
+		{
+			name:    "not numeric",
+			args:    []string{"unikraft/unikraft/latest"},
+			wantErr: "not numeric",
+		},
+		{
+			name:    "no arguments",
+			wantErr: "invalid format",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org, repo, id, err := ParseOrgRepoAndIssueArgs(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseOrgRepoAndIssueArgs() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseOrgRepoAndIssueArgs() error = %v", err)
+			}
+
+			if org != "unikraft" || repo != "unikraft" || id != 42 {
+				t.Errorf("ParseOrgRepoAndIssueArgs() = %s, %s, %d, want unikraft, unikraft, 42", org, repo, id)
+			}
+		})
+	}
+}
+
+func TestInGithubActions(t *testing.T) {
+	for value, want := range map[string]bool{
+		"true":  true,
+		"yes":   false,
+		"false": false,
+		"":      false,
+	} {
+		t.Setenv("GITHUB_ACTIONS", value)
+
+		if got := InGithubActions(); got != want {
+			t.Errorf("InGithubActions() with GITHUB_ACTIONS=%q = %v, want %v", value, got, want)
+		}
+	}
+}
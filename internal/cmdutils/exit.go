@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package cmdutils
+
+import "errors"
+
+// ExitCoder is implemented by errors which terminate the program with a
+// specific exit code rather than the generic failure.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ExitCode returns the exit code of the program for the error which a command
+// returned: 0 if there is none, the code of the first ExitCoder in the chain of
+// wrapped errors, or 1 otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	return 1
+}
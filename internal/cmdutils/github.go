@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package cmdutils
+
+import (
+	"context"
+
+	kitcfg "kraftkit.sh/config"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+// NewGithubClient returns the client of the GitHub API which is configured by
+// the global flags, e.g. its credentials, endpoint, timeout and retries.  The
+// provided options are applied after those of the configuration.
+func NewGithubClient(ctx context.Context, opts ...ghapi.GithubClientOption) (*ghapi.GithubClient, error) {
+	cfg := kitcfg.G[config.Config](ctx)
+
+	return ghapi.NewGithubClient(
+		ctx,
+		cfg.GithubToken,
+		cfg.GithubSkipSSL,
+		cfg.GithubEndpoint,
+		append([]ghapi.GithubClientOption{
+			ghapi.WithReadOnly(cfg.ReadOnly),
+			ghapi.WithTimeout(cfg.EffectiveGithubTimeout()),
+			ghapi.WithRetry(cfg.EffectiveGithubMaxAttempts(), cfg.EffectiveGithubRetryDelay()),
+			ghapi.WithAPIVersion(cfg.EffectiveGithubAPIVersion()),
+			ghapi.WithApp(
+				int64(cfg.GithubAppID),
+				int64(cfg.GithubAppInstallationID),
+				cfg.GithubAppPrivateKey,
+			),
+		}, opts...)...,
+	)
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package cmdutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kitcfg "kraftkit.sh/config"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+func TestNewGithubClient(t *testing.T) {
+	tests := []struct {
+		name         string
+		readOnly     bool
+		opts         []ghapi.GithubClientOption
+		wantReadOnly bool
+	}{
+		{
+			name: "read-write",
+		},
+		{
+			name:         "read-only from config",
+			readOnly:     true,
+			wantReadOnly: true,
+		},
+		{
+			name:         "read-only from option",
+			opts:         []ghapi.GithubClientOption{ghapi.WithReadOnly(true)},
+			wantReadOnly: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var version string
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				version = r.Header.Get("X-GitHub-Api-Version")
+				fmt.Fprint(w, `[]`)
+			}))
+			t.Cleanup(srv.Close)
+
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{
+				GithubEndpoint:    srv.URL,
+				GithubAPIVersion:  "2026-03-10",
+				GithubMaxAttempts: 1,
+				ReadOnly:          tt.readOnly,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+			client, err := NewGithubClient(ctx, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewGithubClient() unexpected error: %v", err)
+			}
+
+			err = client.AddLabelsToPr(ctx, "unikraft", "unikraft", 1, []string{"kind/bug"})
+			if got := errors.Is(err, ghapi.ErrReadOnly); got != tt.wantReadOnly {
+				t.Errorf("AddLabelsToPr() error = %v, want read-only: %v", err, tt.wantReadOnly)
+			} else if !tt.wantReadOnly && err != nil {
+				t.Errorf("AddLabelsToPr() unexpected error: %v", err)
+			}
+
+			if !tt.wantReadOnly && version != "2026-03-10" {
+				t.Errorf("request sent with API version %q, want the configured one", version)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package cmdutils
+
+import (
+	"context"
+
+	kitcfg "kraftkit.sh/config"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/hook"
+)
+
+// NewHookRunner returns the runner of the --hooks, which is nil when none are
+// set or --no-hooks is.
+func NewHookRunner(ctx context.Context) (*hook.Runner, error) {
+	cfg := kitcfg.G[config.Config](ctx)
+	if cfg.Hooks == "" || cfg.NoHooks {
+		return nil, nil
+	}
+
+	hooks, err := hook.NewListOfHooksFromFile(cfg.Hooks)
+	if err != nil {
+		return nil, err
+	}
+
+	return hook.NewRunner(hooks, hook.WithToken(cfg.GithubToken)), nil
+}
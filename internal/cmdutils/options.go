@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package cmdutils
+
+import (
+	"context"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+)
+
+var (
+	optionsMu sync.Mutex
+	options   = make(map[*cobra.Command]any)
+)
+
+// Validator is implemented by options structs which reject nonsensical
+// combinations of flags before the command is executed.
+type Validator interface {
+	Validate(ctx context.Context) error
+}
+
+// New creates a new command via cmdfactory and records its options struct so
+// that it can later be retrieved with Options, e.g. to generate a reference
+// of all flags and environment variables.  If the options struct implements
+// Validator, it is validated before any other pre-run hook.
+func New(obj any, cmd cobra.Command) (*cobra.Command, error) {
+	c, err := cmdfactory.New(obj, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, ok := obj.(Validator); ok {
+		pre := c.PreRunE
+		c.PreRunE = func(cmd *cobra.Command, args []string) error {
+			if err := v.Validate(cmd.Context()); err != nil {
+				return err
+			}
+
+			if pre != nil {
+				return pre(cmd, args)
+			}
+
+			return nil
+		}
+	}
+
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+
+	options[c] = obj
+
+	return c, nil
+}
+
+// Options returns the options struct which the provided command was created
+// with via New, or nil if it was not.
+func Options(cmd *cobra.Command) any {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+
+	return options[cmd]
+}
+
+// DeprecateOrg marks the --org flag of the command as deprecated in favour of
+// the global --github-org.  The flag still takes precedence if it is set.
+func DeprecateOrg(cmd *cobra.Command) {
+	if err := cmd.Flags().MarkDeprecated("org", "use --github-org instead"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package cmdutils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/config"
+)
+
+const (
+	// PlanOutputText logs the changes which a mutating command would perform
+	// in a human-readable form.
+	PlanOutputText = "text"
+
+	// PlanOutputJSON writes the changes which a mutating command would perform
+	// in dry-run mode as a single JSON document to the standard output.
+	PlanOutputJSON = "json"
+)
+
+// ValidatePlanOutput checks the output format of the plan of a mutating
+// command.  Structured plans are only written in dry-run mode.
+func ValidatePlanOutput(ctx context.Context, output string) error {
+	switch output {
+	case "", PlanOutputText:
+		return nil
+	case PlanOutputJSON:
+		return config.Requires("output="+output, true, "dry-run", kitcfg.G[config.Config](ctx).DryRun)
+	}
+
+	return fmt.Errorf("unknown output format '%s': expected one of [%s, %s]", output, PlanOutputText, PlanOutputJSON)
+}
+
+// WritePlan writes the plan of a mutating command to the standard output if
+// the command is run in dry-run mode with a structured output format.
+func WritePlan(ctx context.Context, output string, plan any) error {
+	if output != PlanOutputJSON || !kitcfg.G[config.Config](ctx).DryRun {
+		return nil
+	}
+
+	enc := json.NewEncoder(iostreams.G(ctx).Out)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(plan); err != nil {
+		return fmt.Errorf("could not write plan: %w", err)
+	}
+
+	return nil
+}
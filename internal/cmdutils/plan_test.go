@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package cmdutils
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	kitcfg "kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+
+	"github.com/unikraft/governance/internal/config"
+)
+
+func planContext(t *testing.T, dryRun bool) (context.Context, *bytes.Buffer) {
+	t.Helper()
+
+	cfgm, err := kitcfg.NewConfigManager(&config.Config{
+		DryRun: dryRun,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+
+	ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+	ctx = iostreams.WithIOStreams(ctx, &iostreams.IOStreams{Out: out})
+
+	return ctx, out
+}
+
+func TestValidatePlanOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		dryRun  bool
+		wantErr bool
+	}{
+		{name: "default", output: ""},
+		{name: "text", output: PlanOutputText},
+		{name: "json in dry-run", output: PlanOutputJSON, dryRun: true},
+		{name: "json without dry-run", output: PlanOutputJSON, wantErr: true},
+		{name: "unknown", output: "yaml", dryRun: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := planContext(t, tt.dryRun)
+
+			if err := ValidatePlanOutput(ctx, tt.output); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePlanOutput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWritePlan(t *testing.T) {
+	plan := struct {
+		Add []string `json:"add"`
+	}{
+		Add: []string{"kind/bug"},
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		dryRun bool
+		want   string
+	}{
+		{
+			name:   "json in dry-run",
+			output: PlanOutputJSON,
+			dryRun: true,
+			want:   "{\n  \"add\": [\n    \"kind/bug\"\n  ]\n}\n",
+		},
+		{
+			name:   "text in dry-run",
+			output: PlanOutputText,
+			dryRun: true,
+		},
+		{
+			name:   "json without dry-run",
+			output: PlanOutputJSON,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, out := planContext(t, tt.dryRun)
+
+			if err := WritePlan(ctx, tt.output, plan); err != nil {
+				t.Fatalf("WritePlan() unexpected error: %v", err)
+			}
+
+			if got := out.String(); got != tt.want {
+				t.Errorf("WritePlan() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
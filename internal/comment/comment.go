@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+// Package comment renders the comments which governctl leaves on pull
+// requests from templates named NAME.LANG.md.  Templates in a directory take
+// precedence over the ones embedded in governctl, and a template which has not
+// been translated into the requested language falls back to English.
+package comment
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// DefaultLanguage is the language of every embedded template and the one
+// which is used when a template has not been translated.
+const DefaultLanguage = "en"
+
+// Extension is the file extension of every template.
+const Extension = ".md"
+
+const (
+	// Welcome is the template of the comment left on pull requests of
+	// first-time contributors, which is rendered with WelcomeData.
+	Welcome = "welcome"
+)
+
+// WelcomeData is the data which the Welcome template is rendered with.
+type WelcomeData struct {
+	Org         string
+	Repo        string
+	PullRequest int
+	User        string
+}
+
+// SampleData holds the sample data of every known template, which is used to
+// render each translation when linting.
+var SampleData = map[string]any{
+	Welcome: WelcomeData{
+		Org:         "unikraft",
+		Repo:        "unikraft",
+		PullRequest: 1,
+		User:        "octocat",
+	},
+}
+
+// ErrUnknownTemplate is returned for a template which exists in no language.
+var ErrUnknownTemplate = errors.New("unknown template")
+
+//go:embed embedded/*.md
+var embedded embed.FS
+
+// Templates resolves templates from a directory and from the ones embedded in
+// governctl.
+type Templates struct {
+	dir string
+}
+
+// New returns the templates in the provided directory on top of the embedded
+// ones.  The directory may be empty or not exist, in which case only the
+// embedded templates are used.
+func New(dir string) *Templates {
+	return &Templates{dir: dir}
+}
+
+// Lookup returns the content of the named template in the provided language
+// together with the language which it was resolved in.  The template is
+// looked up as NAME.LANG.md first in the directory and then amongst the
+// embedded templates, followed by the same for DefaultLanguage.
+func (t *Templates) Lookup(name, lang string) (string, string, error) {
+	langs := []string{DefaultLanguage}
+	if lang != "" && lang != DefaultLanguage {
+		langs = []string{lang, DefaultLanguage}
+	}
+
+	for _, l := range langs {
+		file := name + "." + l + Extension
+
+		if t.dir != "" {
+			if raw, err := os.ReadFile(filepath.Join(t.dir, file)); err == nil {
+				return string(raw), l, nil
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return "", "", fmt.Errorf("could not read template: %w", err)
+			}
+		}
+
+		if raw, err := embedded.ReadFile("embedded/" + file); err == nil {
+			return string(raw), l, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+}
+
+// Render renders the named template in the provided language with the data.
+// Referencing data which does not exist is an error.
+func (t *Templates) Render(name, lang string, data any) (string, error) {
+	content, resolved, err := t.Lookup(name, lang)
+	if err != nil {
+		return "", err
+	}
+
+	return render(name+"."+resolved+Extension, content, data)
+}
+
+// render parses and executes the template with the data.
+func render(name, content string, data any) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("could not parse template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("could not render template %s: %w", name, err)
+	}
+
+	return buf.String(), nil
+}
+
+// Translations returns the languages which each template is available in,
+// either in the directory or embedded, in alphabetical order.
+func (t *Templates) Translations() (map[string][]string, error) {
+	found := make(map[string]map[string]struct{})
+
+	add := func(file string) {
+		base, ok := strings.CutSuffix(file, Extension)
+		if !ok {
+			return
+		}
+
+		name, lang, ok := strings.Cut(base, ".")
+		if !ok || name == "" || lang == "" {
+			return
+		}
+
+		if _, ok := found[name]; !ok {
+			found[name] = make(map[string]struct{})
+		}
+
+		found[name][lang] = struct{}{}
+	}
+
+	entries, err := embedded.ReadDir("embedded")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		add(entry.Name())
+	}
+
+	if t.dir != "" {
+		entries, err := os.ReadDir(t.dir)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("could not read templates: %w", err)
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				add(entry.Name())
+			}
+		}
+	}
+
+	translations := make(map[string][]string, len(found))
+	for name, langs := range found {
+		for lang := range langs {
+			translations[name] = append(translations[name], lang)
+		}
+
+		sort.Strings(translations[name])
+	}
+
+	return translations, nil
+}
+
+// LintResult is the outcome of rendering a single translation of a template.
+type LintResult struct {
+	Name     string
+	Language string
+	Err      error
+}
+
+// Lint renders every translation of every template with its SampleData such
+// that missing variables and syntax errors are caught before a comment is
+// left.  Templates without sample data are reported as unknown.
+func (t *Templates) Lint() ([]LintResult, error) {
+	translations, err := t.Translations()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(translations))
+	for name := range translations {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var results []LintResult
+
+	for _, name := range names {
+		data, known := SampleData[name]
+
+		for _, lang := range translations[name] {
+			result := LintResult{
+				Name:     name,
+				Language: lang,
+			}
+
+			if !known {
+				result.Err = fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+			} else if content, resolved, err := t.Lookup(name, lang); err != nil {
+				result.Err = err
+			} else {
+				_, result.Err = render(name+"."+resolved+Extension, content, data)
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package comment
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestLookup(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"welcome.de.md": "Willkommen",
+		"custom.en.md":  "custom",
+	})
+
+	tests := []struct {
+		name     string
+		dir      string
+		template string
+		lang     string
+		wantLang string
+		wantText string
+		wantErr  error
+	}{
+		{
+			name:     "translation in directory",
+			dir:      dir,
+			template: Welcome,
+			lang:     "de",
+			wantLang: "de",
+			wantText: "Willkommen",
+		},
+		{
+			name:     "missing translation falls back to embedded English",
+			dir:      dir,
+			template: Welcome,
+			lang:     "fr",
+			wantLang: "en",
+			wantText: "Thank you",
+		},
+		{
+			name:     "no language",
+			dir:      dir,
+			template: Welcome,
+			wantLang: "en",
+			wantText: "Thank you",
+		},
+		{
+			name:     "directory overrides embedded",
+			dir:      writeTemplates(t, map[string]string{"welcome.en.md": "Hello"}),
+			template: Welcome,
+			lang:     "de",
+			wantLang: "en",
+			wantText: "Hello",
+		},
+		{
+			name:     "missing directory",
+			dir:      filepath.Join(dir, "missing"),
+			template: Welcome,
+			lang:     "de",
+			wantLang: "en",
+			wantText: "Thank you",
+		},
+		{
+			name:     "only in directory",
+			dir:      dir,
+			template: "custom",
+			lang:     "de",
+			wantLang: "en",
+			wantText: "custom",
+		},
+		{
+			name:     "unknown",
+			dir:      dir,
+			template: "unknown",
+			lang:     "de",
+			wantErr:  ErrUnknownTemplate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, lang, err := New(tt.dir).Lookup(tt.template, tt.lang)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Lookup() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if lang != tt.wantLang {
+				t.Errorf("Lookup() language = %q, want %q", lang, tt.wantLang)
+			}
+
+			if !strings.HasPrefix(text, tt.wantText) {
+				t.Errorf("Lookup() = %q, want prefix %q", text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"welcome.de.md": "Willkommen, @{{ .User }}!",
+		"welcome.fr.md": "Bienvenue, @{{ .Author }}!",
+	})
+
+	templates := New(dir)
+	data := WelcomeData{User: "jane"}
+
+	got, err := templates.Render(Welcome, "de", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != "Willkommen, @jane!" {
+		t.Errorf("Render() = %q", got)
+	}
+
+	if _, err := templates.Render(Welcome, "fr", data); err == nil {
+		t.Errorf("Render() expected an error for a missing variable")
+	}
+}
+
+func TestLint(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"welcome.de.md": "Willkommen, @{{ .User }}!",
+		"welcome.fr.md": "Bienvenue, @{{ .Author }}!",
+		"welcome.it.md": "Benvenuto, @{{ .User }",
+		"custom.en.md":  "custom",
+		"README":        "not a template",
+	})
+
+	results, err := New(dir).Lint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]bool)
+	for _, r := range results {
+		got[r.Name+"."+r.Language] = r.Err == nil
+	}
+
+	want := map[string]bool{
+		"custom.en":  false,
+		"welcome.de": true,
+		"welcome.en": true,
+		"welcome.fr": false,
+		"welcome.it": false,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lint() = %v, want %v", got, want)
+	}
+}
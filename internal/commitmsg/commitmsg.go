@@ -0,0 +1,250 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+// Package commitmsg checks that the messages of commits follow the
+// conventions of Unikraft, i.e. a subject of the form "area/subarea: Summary",
+// a body explaining the change and a sign-off of the author.
+package commitmsg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/unikraft/governance/internal/patch"
+)
+
+const (
+	// DefaultSubjectPattern matches the prefix of the subject naming the area
+	// of the change, followed by a capitalised summary.
+	DefaultSubjectPattern = `^[a-z0-9/_-]+: [A-Z]`
+
+	// DefaultMaxSubjectLength is the maximum number of characters of the
+	// subject.
+	DefaultMaxSubjectLength = 72
+)
+
+const (
+	// RuleSubjectPrefix requires the subject to match the subject pattern.
+	RuleSubjectPrefix = "subject-prefix"
+
+	// RuleSubjectLength limits the length of the subject.
+	RuleSubjectLength = "subject-length"
+
+	// RuleBody requires a body besides the subject and the trailers.
+	RuleBody = "body"
+
+	// RuleSignoff requires a Signed-off-by trailer of the author.
+	RuleSignoff = "signoff"
+
+	// RuleMergeCommit forbids merge commits.
+	RuleMergeCommit = "merge-commit"
+)
+
+// Rules are all the rules which commits are checked against.
+var Rules = []string{
+	RuleSubjectPrefix,
+	RuleSubjectLength,
+	RuleBody,
+	RuleSignoff,
+	RuleMergeCommit,
+}
+
+const (
+	// LevelError is the level of violations which fail the check.
+	LevelError = "error"
+
+	// LevelWarning is the level of violations which are only reported.
+	LevelWarning = "warning"
+)
+
+// trailerLine matches a line of the form "Some-key: value", e.g.
+// "Reviewed-by: Jane Doe <jane@unikraft.io>", which is not part of the body.
+var trailerLine = regexp.MustCompile(`^[A-Za-z0-9]+(-[A-Za-z0-9]+)+: `)
+
+// Violation is a commit whose message does not follow a rule.
+type Violation struct {
+	Commit  string `json:"commit"`
+	Title   string `json:"title,omitempty"`
+	Rule    string `json:"rule"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s (%s): %s", shortHash(v.Commit), v.Rule, v.Message)
+}
+
+// Failed returns whether any of the violations is of the error level.
+func Failed(violations []Violation) bool {
+	for _, violation := range violations {
+		if violation.Level == LevelError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateRules checks that the provided names are known rules.
+func ValidateRules(rules []string) error {
+	for _, rule := range rules {
+		if !contains(Rules, rule) {
+			return fmt.Errorf("unknown rule '%s': expected one of: %s", rule, strings.Join(Rules, ", "))
+		}
+	}
+
+	return nil
+}
+
+// Checker checks the messages of commits.
+type Checker struct {
+	subject          *regexp.Regexp
+	maxSubjectLength int
+	warnings         []string
+}
+
+// NewChecker returns a checker which, unless otherwise specified, expects
+// subjects matching the default pattern of at most the default length and
+// treats every violation as an error.
+func NewChecker(opts ...CheckerOption) (*Checker, error) {
+	c := &Checker{
+		subject:          regexp.MustCompile(DefaultSubjectPattern),
+		maxSubjectLength: DefaultMaxSubjectLength,
+	}
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Check returns the violations of the messages of the provided patches.
+func (c *Checker) Check(patches ...*patch.Patch) []Violation {
+	var violations []Violation
+
+	for _, p := range patches {
+		for _, v := range c.checkMessage(p) {
+			violations = append(violations, c.violation(p.Hash, p.Title, v.rule, v.message))
+		}
+	}
+
+	return violations
+}
+
+// CheckMergeCommits returns a violation for each of the provided hashes of
+// merge commits.
+func (c *Checker) CheckMergeCommits(hashes ...string) []Violation {
+	var violations []Violation
+
+	for _, hash := range hashes {
+		violations = append(violations, c.violation(hash, "", RuleMergeCommit, "merge commits are not allowed, rebase the branch instead"))
+	}
+
+	return violations
+}
+
+// violation returns the violation of the rule at the level configured for it.
+func (c *Checker) violation(hash, title, rule, message string) Violation {
+	level := LevelError
+	if contains(c.warnings, rule) {
+		level = LevelWarning
+	}
+
+	return Violation{
+		Commit:  hash,
+		Title:   title,
+		Rule:    rule,
+		Level:   level,
+		Message: message,
+	}
+}
+
+// checkMessage returns the rules which the message of the patch violates in
+// the order of Rules, each with a description of the violation.
+func (c *Checker) checkMessage(p *patch.Patch) []ruleMessage {
+	var violations []ruleMessage
+
+	if !c.subject.MatchString(p.Title) {
+		violations = append(violations, ruleMessage{RuleSubjectPrefix, fmt.Sprintf("subject does not match '%s'", c.subject)})
+	}
+
+	if length := len([]rune(p.Title)); length > c.maxSubjectLength {
+		violations = append(violations, ruleMessage{RuleSubjectLength, fmt.Sprintf("subject is %d characters long, at most %d are allowed", length, c.maxSubjectLength)})
+	}
+
+	if !hasBody(p.Message) {
+		violations = append(violations, ruleMessage{RuleBody, "message has no body explaining the change"})
+	}
+
+	if !signedOffBy(p.Trailers, p.AuthorEmail) {
+		violations = append(violations, ruleMessage{RuleSignoff, fmt.Sprintf("missing Signed-off-by of the author %s <%s>", p.AuthorName, p.AuthorEmail)})
+	}
+
+	return violations
+}
+
+// ruleMessage is a violated rule together with its description.
+type ruleMessage struct {
+	rule    string
+	message string
+}
+
+// hasBody returns whether the message, without its subject and the known
+// trailers, has any line which is neither empty nor another trailer.
+func hasBody(message string) bool {
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !trailerLine.MatchString(line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// signedOffBy returns whether any of the trailers is a Signed-off-by with the
+// provided email address.
+func signedOffBy(trailers []string, email string) bool {
+	if email == "" {
+		return false
+	}
+
+	for _, trailer := range trailers {
+		key, value, ok := strings.Cut(strings.TrimSpace(trailer), ":")
+		if !ok || !strings.EqualFold(key, "Signed-off-by") {
+			continue
+		}
+
+		if strings.Contains(strings.ToLower(value), "<"+strings.ToLower(email)+">") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contains returns whether the list contains the entry.
+func contains(list []string, entry string) bool {
+	for _, e := range list {
+		if e == entry {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shortHash abbreviates the hash of a commit.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+
+	return hash
+}
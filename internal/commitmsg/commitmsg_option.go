@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package commitmsg
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// CheckerOption is used to customize the checks of a Checker.
+type CheckerOption func(*Checker) error
+
+// WithSubjectPattern sets the regular expression which the subject must
+// match.  When empty, DefaultSubjectPattern is used.
+func WithSubjectPattern(pattern string) CheckerOption {
+	return func(c *Checker) error {
+		if pattern == "" {
+			return nil
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid subject pattern '%s': %w", pattern, err)
+		}
+
+		c.subject = re
+
+		return nil
+	}
+}
+
+// WithMaxSubjectLength sets the maximum number of characters of the subject.
+// When zero, DefaultMaxSubjectLength is used.
+func WithMaxSubjectLength(length int) CheckerOption {
+	return func(c *Checker) error {
+		if length < 0 {
+			return fmt.Errorf("maximum subject length cannot be negative: %d", length)
+		}
+
+		if length > 0 {
+			c.maxSubjectLength = length
+		}
+
+		return nil
+	}
+}
+
+// WithWarnings reports violations of the provided rules as warnings, which do
+// not fail the check, instead of errors.
+func WithWarnings(rules ...string) CheckerOption {
+	return func(c *Checker) error {
+		if err := ValidateRules(rules); err != nil {
+			return err
+		}
+
+		c.warnings = append(append([]string{}, c.warnings...), rules...)
+
+		return nil
+	}
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package commitmsg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/unikraft/governance/internal/patch"
+)
+
+func TestCheck(t *testing.T) {
+	const signoff = "Signed-off-by: Jane Doe <jane@unikraft.io>"
+
+	tests := []struct {
+		name      string
+		title     string
+		message   string
+		trailers  []string
+		opts      []CheckerOption
+		wantRules []string
+		wantLevel string
+	}{
+		{
+			name:     "conforming",
+			title:    "lib/ukboot: Initialise the console before the heap",
+			message:  "\nThe heap may print diagnostics.",
+			trailers: []string{signoff},
+		},
+		{
+			name:      "lowercase summary",
+			title:     "lib/ukboot: initialise the console",
+			message:   "\nThe heap may print diagnostics.",
+			trailers:  []string{signoff},
+			wantRules: []string{RuleSubjectPrefix},
+			wantLevel: LevelError,
+		},
+		{
+			name:      "missing area",
+			title:     "Initialise the console",
+			message:   "\nThe heap may print diagnostics.",
+			trailers:  []string{signoff},
+			wantRules: []string{RuleSubjectPrefix},
+			wantLevel: LevelError,
+		},
+		{
+			name:      "long subject",
+			title:     "lib/ukboot: " + strings.Repeat("A", 61),
+			message:   "\nThe heap may print diagnostics.",
+			trailers:  []string{signoff},
+			wantRules: []string{RuleSubjectLength},
+			wantLevel: LevelError,
+		},
+		{
+			name:      "only other trailers in body",
+			title:     "lib/ukboot: Initialise the console",
+			message:   "\nReviewed-by: John Doe <john@unikraft.io>\n",
+			trailers:  []string{signoff},
+			wantRules: []string{RuleBody},
+			wantLevel: LevelError,
+		},
+		{
+			name:      "signed off by someone else",
+			title:     "lib/ukboot: Initialise the console",
+			message:   "\nThe heap may print diagnostics.",
+			trailers:  []string{"Signed-off-by: John Doe <john@unikraft.io>"},
+			wantRules: []string{RuleSignoff},
+			wantLevel: LevelError,
+		},
+		{
+			name:      "everything wrong",
+			title:     "fix",
+			wantRules: []string{RuleSubjectPrefix, RuleBody, RuleSignoff},
+			wantLevel: LevelError,
+		},
+		{
+			name:      "custom pattern and length",
+			title:     "[ukboot] Initialise the console",
+			message:   "\nThe heap may print diagnostics.",
+			trailers:  []string{signoff},
+			opts:      []CheckerOption{WithSubjectPattern(`^\[[a-z]+\] `), WithMaxSubjectLength(20)},
+			wantRules: []string{RuleSubjectLength},
+			wantLevel: LevelError,
+		},
+		{
+			name:      "downgraded to warning",
+			title:     "lib/ukboot: Initialise the console",
+			trailers:  []string{signoff},
+			opts:      []CheckerOption{WithWarnings(RuleBody)},
+			wantRules: []string{RuleBody},
+			wantLevel: LevelWarning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewChecker(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			violations := c.Check(&patch.Patch{
+				Hash:        "0123456789abcdef",
+				Title:       tt.title,
+				Message:     tt.message,
+				Trailers:    tt.trailers,
+				AuthorName:  "Jane Doe",
+				AuthorEmail: "Jane@unikraft.io",
+			})
+
+			var rules []string
+			for _, v := range violations {
+				rules = append(rules, v.Rule)
+
+				if v.Level != tt.wantLevel {
+					t.Errorf("%s: level = %s, want %s", v.Rule, v.Level, tt.wantLevel)
+				}
+			}
+
+			if !reflect.DeepEqual(rules, tt.wantRules) {
+				t.Errorf("Check() rules = %v, want %v", rules, tt.wantRules)
+			}
+
+			if got, want := Failed(violations), tt.wantLevel == LevelError; got != want {
+				t.Errorf("Failed() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCheckMergeCommits(t *testing.T) {
+	c, err := NewChecker()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	violations := c.CheckMergeCommits("0123456789abcdef")
+	if len(violations) != 1 || violations[0].Rule != RuleMergeCommit || violations[0].Level != LevelError {
+		t.Fatalf("CheckMergeCommits() = %v", violations)
+	}
+
+	if got, want := violations[0].String(), "0123456 (merge-commit)"; !strings.HasPrefix(got, want) {
+		t.Errorf("String() = %q, want prefix %q", got, want)
+	}
+}
+
+func TestNewCheckerInvalid(t *testing.T) {
+	for _, opt := range []CheckerOption{
+		WithSubjectPattern("("),
+		WithMaxSubjectLength(-1),
+		WithWarnings("spelling"),
+	} {
+		if _, err := NewChecker(opt); err == nil {
+			t.Error("NewChecker() expected error")
+		}
+	}
+}
@@ -5,15 +5,138 @@
 
 package config
 
+import "time"
+
+const (
+	// DefaultGithubTimeout is the maximum duration of a single request to the
+	// GitHub API when --github-timeout is not set.
+	DefaultGithubTimeout = 30 * time.Second
+
+	// DefaultGithubMaxAttempts is the number of times a GitHub API request which
+	// failed transiently is attempted when --github-max-attempts is not set.
+	DefaultGithubMaxAttempts = 4
+
+	// DefaultGithubRetryDelay is the delay before the first retry of a GitHub
+	// API request when --github-retry-delay is not set.
+	DefaultGithubRetryDelay = time.Second
+
+	// DefaultGithubAPIVersion is the version of the GitHub REST API which is
+	// requested when --github-api-version is not set.
+	DefaultGithubAPIVersion = "2022-11-28"
+
+	// DefaultGithubOrg is the GitHub organisation which the definitions belong
+	// to when --github-org is not set.
+	DefaultGithubOrg = "unikraft"
+)
+
 type Config struct {
-	DryRun         bool   `long:"dry-run" short:"D" env:"GOVERN_DRY_RUN" usage:"Do not perform any actual change."`
-	GithubUser     string `long:"github-user" env:"GOVERN_GITHUB_USER" usage:"GitHub User account name" default:"unikraft-bot"`
-	GithubToken    string `long:"github-token" env:"GOVERN_GITHUB_TOKEN" usage:"GitHub API token"`
-	GithubEndpoint string `long:"github-endpoint" env:"GOVERN_GITHUB_ENDPOINT" short:"E" usage:"Alternative GitHub API endpoint (usually GitHub enterprise)"`
-	GithubSkipSSL  bool   `long:"github-skip-ssl" short:"S" env:"GOVERN_GITHUB_SKIP_SSL" usage:"Skip SSL check with GitHub API endpoint"`
-	LogLevel       string `long:"log-level" short:"l" env:"GOVERN_LOG_LEVEL" usage:"Log level verbosity" default:"info"`
-	NoRender       bool   `long:"no-render" env:"GOVERN_NO_RENDER" usage:"Do not render the output"`
-	ReposDir       string `long:"repos-dir" short:"r" env:"GOVERN_REPOS_DIR" usage:"Path to the repos definition directory" default:"repos"`
-	TeamsDir       string `long:"teams-dir" short:"T" env:"GOVERN_TEAMS_DIR" usage:"Path to the teams definition directory" default:"teams"`
-	TempDir        string `long:"temp-dir" short:"j" env:"GOVERN_TEMP_DIR" usage:"Temporary directory to store intermediate git clones"`
+	APIUsageReport          string `long:"api-usage-report" env:"GOVERN_API_USAGE_REPORT" usage:"Write the number of GitHub API calls per pull request and operation to this CSV file"`
+	ConfigFile              string `long:"config" env:"GOVERN_CONFIG" usage:"Path to a YAML file of settings keyed by flag name, whose relative paths are resolved against its directory"`
+	DiscordGuildID          string `long:"discord-guild" env:"GOVERN_DISCORD_GUILD" usage:"ID of the Discord guild whose roles and channels are managed"`
+	DiscordToken            string `long:"discord-token" env:"GOVERN_DISCORD_TOKEN" usage:"Token of the Discord bot"`
+	DryRun                  bool   `long:"dry-run" short:"D" env:"GOVERN_DRY_RUN" usage:"Do not perform any actual change."`
+	Force                   bool   `long:"force" env:"GOVERN_FORCE" usage:"Re-apply actions which a previous run has already applied to a pull request"`
+	GitBinary               string `long:"git-binary" env:"GOVERN_GIT_BINARY" usage:"Path to the git executable" default:"git"`
+	GithubUser              string `long:"github-user" env:"GOVERN_GITHUB_USER" usage:"GitHub User account name" default:"unikraft-bot"`
+	GithubToken             string `long:"github-token" env:"GOVERN_GITHUB_TOKEN" usage:"GitHub API token"`
+	GithubEndpoint          string `long:"github-endpoint" env:"GOVERN_GITHUB_ENDPOINT" short:"E" usage:"Alternative GitHub API endpoint (usually GitHub enterprise), with or without the /api/v3 suffix"`
+	GithubSkipSSL           bool   `long:"github-skip-ssl" short:"S" env:"GOVERN_GITHUB_SKIP_SSL" usage:"Skip SSL check with GitHub API endpoint"`
+	GithubTimeout           string `long:"github-timeout" env:"GOVERN_GITHUB_TIMEOUT" usage:"Maximum duration of a single request to the GitHub API, 0 disables it" default:"30s"`
+	GithubMaxAttempts       int    `long:"github-max-attempts" env:"GOVERN_GITHUB_MAX_ATTEMPTS" usage:"Maximum number of attempts of a GitHub API request which was rate limited or failed with a server error, 1 disables retries" default:"4"`
+	GithubRetryDelay        string `long:"github-retry-delay" env:"GOVERN_GITHUB_RETRY_DELAY" usage:"Delay before the first retry of a GitHub API request, doubling with every further attempt" default:"1s"`
+	GithubAppID             int    `long:"github-app-id" env:"GOVERN_GITHUB_APP_ID" usage:"Authenticate as this GitHub App instead of with --github-token"`
+	GithubAppInstallationID int    `long:"github-app-installation-id" env:"GOVERN_GITHUB_APP_INSTALLATION_ID" usage:"Installation of the GitHub App to authenticate as"`
+	GithubAppPrivateKey     string `long:"github-app-private-key" env:"GOVERN_GITHUB_APP_PRIVATE_KEY" usage:"Path to the PEM-encoded private key of the GitHub App"`
+	GithubOrg               string `long:"github-org" env:"GOVERN_GITHUB_ORG" usage:"GitHub organisation which the teams, repositories and labels belong to" default:"unikraft"`
+	GithubAPIVersion        string `long:"github-api-version" env:"GOVERN_GITHUB_API_VERSION" usage:"Version of the GitHub REST API to request, as YYYY-MM-DD" default:"2022-11-28"`
+	Hooks                   string `long:"hooks" env:"GOVERN_HOOKS" usage:"Path to a YAML file whose hooks: section names executables run at points of evaluating and merging pull requests"`
+	LabelsDir               string `long:"labels-dir" env:"GOVERN_LABELS_DIR" usage:"Path to the labels definition directory" default:"labels"`
+	LogLevel                string `long:"log-level" short:"l" env:"GOVERN_LOG_LEVEL" usage:"Log level verbosity" default:"info"`
+	NoHooks                 bool   `long:"no-hooks" env:"GOVERN_NO_HOOKS" usage:"Do not run any of the --hooks"`
+	NoRender                bool   `long:"no-render" env:"GOVERN_NO_RENDER" usage:"Do not render the output"`
+	Quiet                   bool   `long:"quiet" short:"q" env:"GOVERN_QUIET" usage:"Only log errors (overrides --log-level)"`
+	ReadOnly                bool   `long:"read-only" env:"GOVERN_READ_ONLY" usage:"Refuse any request to GitHub which could modify state"`
+	ReposDir                string `long:"repos-dir" short:"r" env:"GOVERN_REPOS_DIR" usage:"Path to the repos definition directory or multi-document YAML file" default:"repos"`
+	TeamsDir                string `long:"teams-dir" short:"T" env:"GOVERN_TEAMS_DIR" usage:"Path to the teams definition directory or multi-document YAML file" default:"teams"`
+	TempDir                 string `long:"temp-dir" short:"j" env:"GOVERN_TEMP_DIR" usage:"Temporary directory to store intermediate git clones"`
+	TemplatesDir            string `long:"templates-dir" env:"GOVERN_TEMPLATES_DIR" usage:"Path to the directory of comment templates named NAME.LANG.md which override the built-in ones" default:"templates"`
+	Verbose                 bool   `long:"verbose" short:"v" env:"GOVERN_VERBOSE" usage:"Log debug messages (overrides --log-level and --quiet)"`
+}
+
+// EffectiveLogLevel returns the log level after applying the --quiet and
+// --verbose convenience flags, where --verbose takes precedence.
+func (c *Config) EffectiveLogLevel() string {
+	if c.Verbose {
+		return "debug"
+	} else if c.Quiet {
+		return "error"
+	}
+
+	return c.LogLevel
+}
+
+// HasGithubApp returns whether credentials of a GitHub App have been provided.
+func (c *Config) HasGithubApp() bool {
+	return c.GithubAppID != 0
+}
+
+// EffectiveGithubTimeout returns the maximum duration of a single request to
+// the GitHub API, falling back to DefaultGithubTimeout if --github-timeout is
+// unset or invalid.
+func (c *Config) EffectiveGithubTimeout() time.Duration {
+	timeout, err := time.ParseDuration(c.GithubTimeout)
+	if err != nil || timeout < 0 {
+		return DefaultGithubTimeout
+	}
+
+	return timeout
+}
+
+// EffectiveGithubMaxAttempts returns the number of times a GitHub API request
+// is attempted, falling back to DefaultGithubMaxAttempts if
+// --github-max-attempts is unset or invalid.
+func (c *Config) EffectiveGithubMaxAttempts() int {
+	if c.GithubMaxAttempts < 1 {
+		return DefaultGithubMaxAttempts
+	}
+
+	return c.GithubMaxAttempts
+}
+
+// EffectiveGithubRetryDelay returns the delay before the first retry of a
+// GitHub API request, falling back to DefaultGithubRetryDelay if
+// --github-retry-delay is unset or invalid.
+func (c *Config) EffectiveGithubRetryDelay() time.Duration {
+	delay, err := time.ParseDuration(c.GithubRetryDelay)
+	if err != nil || delay < 0 {
+		return DefaultGithubRetryDelay
+	}
+
+	return delay
+}
+
+// EffectiveGithubAPIVersion returns the version of the GitHub REST API which
+// is requested, falling back to DefaultGithubAPIVersion if
+// --github-api-version is unset or not a date.
+func (c *Config) EffectiveGithubAPIVersion() string {
+	if _, err := time.Parse("2006-01-02", c.GithubAPIVersion); err != nil {
+		return DefaultGithubAPIVersion
+	}
+
+	return c.GithubAPIVersion
+}
+
+// EffectiveGithubOrg returns the GitHub organisation which a command operates
+// on.  The deprecated --org of the command takes precedence if set, otherwise
+// --github-org is used, falling back to DefaultGithubOrg if it is unset.
+func (c *Config) EffectiveGithubOrg(org string) string {
+	if org != "" {
+		return org
+	}
+
+	if c.GithubOrg != "" {
+		return c.GithubOrg
+	}
+
+	return DefaultGithubOrg
 }
@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"kraftkit.sh/cmdfactory"
+)
+
+func TestEffectiveLogLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "log level",
+			cfg:  Config{LogLevel: "warn"},
+			want: "warn",
+		},
+		{
+			name: "quiet",
+			cfg:  Config{LogLevel: "info", Quiet: true},
+			want: "error",
+		},
+		{
+			name: "verbose",
+			cfg:  Config{LogLevel: "info", Verbose: true},
+			want: "debug",
+		},
+		{
+			name: "verbose wins over quiet",
+			cfg:  Config{LogLevel: "info", Quiet: true, Verbose: true},
+			want: "debug",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.EffectiveLogLevel(); got != tt.want {
+				t.Errorf("EffectiveLogLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffectiveGithubTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want time.Duration
+	}{
+		{
+			name: "unset",
+			cfg:  Config{},
+			want: DefaultGithubTimeout,
+		},
+		{
+			name: "set",
+			cfg:  Config{GithubTimeout: "5s"},
+			want: 5 * time.Second,
+		},
+		{
+			name: "disabled",
+			cfg:  Config{GithubTimeout: "0"},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.EffectiveGithubTimeout(); got != tt.want {
+				t.Errorf("EffectiveGithubTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffectiveGithubAPIVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "unset",
+			cfg:  Config{},
+			want: DefaultGithubAPIVersion,
+		},
+		{
+			name: "set",
+			cfg:  Config{GithubAPIVersion: "2026-03-10"},
+			want: "2026-03-10",
+		},
+		{
+			name: "invalid",
+			cfg:  Config{GithubAPIVersion: "v3"},
+			want: DefaultGithubAPIVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.EffectiveGithubAPIVersion(); got != tt.want {
+				t.Errorf("EffectiveGithubAPIVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffectiveGithubRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		wantAttempts int
+		wantDelay    time.Duration
+	}{
+		{
+			name:         "unset",
+			cfg:          Config{},
+			wantAttempts: DefaultGithubMaxAttempts,
+			wantDelay:    DefaultGithubRetryDelay,
+		},
+		{
+			name:         "set",
+			cfg:          Config{GithubMaxAttempts: 2, GithubRetryDelay: "250ms"},
+			wantAttempts: 2,
+			wantDelay:    250 * time.Millisecond,
+		},
+		{
+			name:         "disabled",
+			cfg:          Config{GithubMaxAttempts: 1, GithubRetryDelay: "0"},
+			wantAttempts: 1,
+			wantDelay:    0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.EffectiveGithubMaxAttempts(); got != tt.wantAttempts {
+				t.Errorf("EffectiveGithubMaxAttempts() = %v, want %v", got, tt.wantAttempts)
+			}
+
+			if got := tt.cfg.EffectiveGithubRetryDelay(); got != tt.wantDelay {
+				t.Errorf("EffectiveGithubRetryDelay() = %v, want %v", got, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestEffectiveGithubOrg(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		args []string
+		org  string
+		want string
+	}{
+		{
+			name: "unset",
+			want: DefaultGithubOrg,
+		},
+		{
+			name: "env",
+			env:  "kraftkit",
+			want: "kraftkit",
+		},
+		{
+			name: "flag",
+			args: []string{"--github-org", "kraftkit"},
+			want: "kraftkit",
+		},
+		{
+			name: "flag wins over env",
+			env:  "unikraft-io",
+			args: []string{"--github-org=kraftkit"},
+			want: "kraftkit",
+		},
+		{
+			name: "deprecated org of the command",
+			args: []string{"--github-org=kraftkit"},
+			org:  "unikraft-io",
+			want: "unikraft-io",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("GOVERN_GITHUB_ORG", tt.env)
+			}
+
+			cfg := Config{}
+			cmd := &cobra.Command{Use: "governctl"}
+			if err := cmdfactory.AttributeFlags(cmd, &cfg, tt.args...); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := cfg.EffectiveGithubOrg(tt.org); got != tt.want {
+				t.Errorf("EffectiveGithubOrg() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// pathSettings are the settings holding a path which, when set in a config
+// file, are relative to the directory of the file rather than to the working
+// directory.
+var pathSettings = []string{
+	"github-app-private-key",
+	"hooks",
+	"labels-dir",
+	"repos-dir",
+	"teams-dir",
+	"temp-dir",
+	"templates-dir",
+}
+
+// LoadFile sets the settings from the YAML file at the provided path, whose
+// keys are the names of the flags, e.g. teams-dir.  Settings which have been
+// set by their environment variable or, as reported by the provided function,
+// on the command line take precedence over the file.  Relative paths in the
+// file are resolved against the directory of the file, such that a file can
+// be used from any working directory.
+func (c *Config) LoadFile(path string, explicit func(flag string) bool) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read config file: %w", err)
+	}
+
+	settings := make(map[string]interface{})
+	if err := yaml.Unmarshal(raw, &settings); err != nil {
+		return fmt.Errorf("could not parse config file '%s': %w", path, err)
+	}
+
+	fields := make(map[string]reflect.StructField)
+	typ := reflect.TypeOf(*c)
+	for i := 0; i < typ.NumField(); i++ {
+		if name := typ.Field(i).Tag.Get("long"); name != "" && name != "config" {
+			fields[name] = typ.Field(i)
+		}
+	}
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		field, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("unknown setting '%s' in config file '%s'", name, path)
+		}
+
+		if explicit != nil && explicit(name) {
+			continue
+		}
+
+		if env := field.Tag.Get("env"); env != "" {
+			if _, ok := os.LookupEnv(env); ok {
+				continue
+			}
+		}
+
+		// Round-trip the value through YAML to decode it as the type of the
+		// field, which rejects e.g. a list for a string setting.
+		value, err := yaml.Marshal(settings[name])
+		if err != nil {
+			return fmt.Errorf("invalid setting '%s' in config file '%s': %w", name, path, err)
+		}
+
+		ptr := reflect.New(field.Type)
+		if err := yaml.Unmarshal(value, ptr.Interface()); err != nil {
+			return fmt.Errorf("invalid setting '%s' in config file '%s': %w", name, path, err)
+		}
+
+		if s, ok := ptr.Interface().(*string); ok && *s != "" && !filepath.IsAbs(*s) && contains(pathSettings, name) {
+			*s = filepath.Join(filepath.Dir(path), *s)
+		}
+
+		reflect.ValueOf(c).Elem().FieldByIndex(field.Index).Set(ptr.Elem())
+	}
+
+	return nil
+}
+
+// contains returns whether the list contains the entry.
+func contains(list []string, entry string) bool {
+	for _, e := range list {
+		if e == entry {
+			return true
+		}
+	}
+
+	return false
+}
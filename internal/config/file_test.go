@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "governance", "ci")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		cfg      Config
+		explicit []string
+		env      map[string]string
+		want     Config
+		wantErr  string
+	}{
+		{
+			name: "relative directories",
+			content: `teams-dir: ../teams
+repos-dir: ./repos
+labels-dir: labels
+templates-dir: /etc/governctl/templates
+`,
+			want: Config{
+				TeamsDir:     filepath.Join(dir, "..", "teams"),
+				ReposDir:     filepath.Join(dir, "repos"),
+				LabelsDir:    filepath.Join(dir, "labels"),
+				TemplatesDir: "/etc/governctl/templates",
+			},
+		},
+		{
+			name:    "other settings",
+			content: "dry-run: true\ngithub-timeout: 1m\ngithub-app-id: 42\n",
+			want:    Config{DryRun: true, GithubTimeout: "1m", GithubAppID: 42},
+		},
+		{
+			name:     "flags take precedence",
+			content:  "teams-dir: teams\nrepos-dir: repos\n",
+			cfg:      Config{TeamsDir: "flag", ReposDir: "default"},
+			explicit: []string{"teams-dir"},
+			want:     Config{TeamsDir: "flag", ReposDir: filepath.Join(dir, "repos")},
+		},
+		{
+			name:    "environment takes precedence",
+			content: "teams-dir: teams\n",
+			cfg:     Config{TeamsDir: "env"},
+			env:     map[string]string{"GOVERN_TEAMS_DIR": "env"},
+			want:    Config{TeamsDir: "env"},
+		},
+		{
+			name:    "unknown setting",
+			content: "team-dir: teams\n",
+			wantErr: "unknown setting 'team-dir'",
+		},
+		{
+			name:    "invalid value",
+			content: "teams-dir: [teams]\n",
+			wantErr: "invalid setting 'teams-dir'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			path := filepath.Join(dir, "governctl.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := tt.cfg
+			err := cfg.LoadFile(path, func(name string) bool {
+				return contains(tt.explicit, name)
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadFile() error = %v, want %s", err, tt.wantErr)
+				}
+
+				return
+			} else if err != nil {
+				t.Fatalf("LoadFile() unexpected error: %v", err)
+			}
+
+			if cfg != tt.want {
+				t.Errorf("LoadFile() = %+v, want %+v", cfg, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Validate rejects global flags whose values are invalid before any command
+// is executed.
+func (c *Config) Validate() error {
+	if _, err := logrus.ParseLevel(c.LogLevel); c.LogLevel != "" && err != nil {
+		return fmt.Errorf("invalid --log-level '%s': %w", c.LogLevel, err)
+	}
+
+	if c.GithubTimeout != "" {
+		if timeout, err := time.ParseDuration(c.GithubTimeout); err != nil {
+			return fmt.Errorf("invalid --github-timeout '%s': %w", c.GithubTimeout, err)
+		} else if timeout < 0 {
+			return fmt.Errorf("--github-timeout must not be negative, got %s", c.GithubTimeout)
+		}
+	}
+
+	if c.GithubMaxAttempts < 0 {
+		return fmt.Errorf("--github-max-attempts must not be negative, got %d", c.GithubMaxAttempts)
+	}
+
+	if c.GithubRetryDelay != "" {
+		if delay, err := time.ParseDuration(c.GithubRetryDelay); err != nil {
+			return fmt.Errorf("invalid --github-retry-delay '%s': %w", c.GithubRetryDelay, err)
+		} else if delay < 0 {
+			return fmt.Errorf("--github-retry-delay must not be negative, got %s", c.GithubRetryDelay)
+		}
+	}
+
+	if c.GithubEndpoint != "" {
+		if endpoint, err := url.Parse(c.GithubEndpoint); err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return fmt.Errorf("invalid --github-endpoint '%s': expected an absolute http(s) URL, e.g. https://ghe.example.com", c.GithubEndpoint)
+		}
+	}
+
+	if c.GithubAppID < 0 || c.GithubAppInstallationID < 0 {
+		return errors.New("--github-app-id and --github-app-installation-id must not be negative")
+	}
+
+	if c.HasGithubApp() && (c.GithubAppInstallationID == 0 || c.GithubAppPrivateKey == "") {
+		return errors.New("--github-app-id requires --github-app-installation-id and --github-app-private-key")
+	}
+
+	if !c.HasGithubApp() && (c.GithubAppInstallationID != 0 || c.GithubAppPrivateKey != "") {
+		return errors.New("--github-app-installation-id and --github-app-private-key require --github-app-id")
+	}
+
+	return nil
+}
+
+// Exclusive returns an error if both of the provided flags are set.
+func Exclusive(flag string, set bool, other string, otherSet bool) error {
+	if set && otherSet {
+		return fmt.Errorf("--%s and --%s cannot be used together", flag, other)
+	}
+
+	return nil
+}
+
+// Requires returns an error if the provided flag is set without the flag it
+// depends on.
+func Requires(flag string, set bool, dep string, depSet bool) error {
+	if set && !depSet {
+		return fmt.Errorf("--%s requires --%s", flag, dep)
+	}
+
+	return nil
+}
+
+// NotNegative returns an error if the value of the provided numeric flag is
+// negative.
+func NotNegative(flag string, value int) error {
+	if value < 0 {
+		return fmt.Errorf("--%s must not be negative, got %d", flag, value)
+	}
+
+	return nil
+}
+
+// ValidateCommitter checks the combination of the committer flags which are
+// shared by commands which rebase or apply pull requests.  Setting the
+// committer globally is meaningless without a name or email to set.
+func ValidateCommitter(name, email string, global bool) error {
+	if global && name == "" && email == "" {
+		return errors.New("--committer-global requires --committer-name or --committer-email")
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package config
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{
+			name: "default log level",
+			cfg:  Config{LogLevel: "info"},
+		},
+		{
+			name: "empty log level",
+			cfg:  Config{},
+		},
+		{
+			name:    "unknown log level",
+			cfg:     Config{LogLevel: "chatty"},
+			wantErr: true,
+		},
+		{
+			name: "github timeout",
+			cfg:  Config{GithubTimeout: "1m30s"},
+		},
+		{
+			name:    "invalid github timeout",
+			cfg:     Config{GithubTimeout: "soon"},
+			wantErr: true,
+		},
+		{
+			name:    "negative github timeout",
+			cfg:     Config{GithubTimeout: "-1s"},
+			wantErr: true,
+		},
+		{
+			name:    "negative github max attempts",
+			cfg:     Config{GithubMaxAttempts: -1},
+			wantErr: true,
+		},
+		{
+			name:    "invalid github retry delay",
+			cfg:     Config{GithubRetryDelay: "later"},
+			wantErr: true,
+		},
+		{
+			name: "github endpoint",
+			cfg:  Config{GithubEndpoint: "https://ghe.example.com/api/v3"},
+		},
+		{
+			name:    "github endpoint without scheme",
+			cfg:     Config{GithubEndpoint: "ghe.example.com"},
+			wantErr: true,
+		},
+		{
+			name: "github app",
+			cfg:  Config{GithubAppID: 1, GithubAppInstallationID: 2, GithubAppPrivateKey: "app.pem"},
+		},
+		{
+			name:    "github app without private key",
+			cfg:     Config{GithubAppID: 1, GithubAppInstallationID: 2},
+			wantErr: true,
+		},
+		{
+			name:    "github app installation without app",
+			cfg:     Config{GithubAppInstallationID: 2},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCommitter(t *testing.T) {
+	tests := []struct {
+		name    string
+		cname   string
+		email   string
+		global  bool
+		wantErr bool
+	}{
+		{
+			name: "nothing set",
+		},
+		{
+			name:   "global with name",
+			cname:  "Unikraft Bot",
+			global: true,
+		},
+		{
+			name:   "global with email",
+			email:  "monkey@unikraft.org",
+			global: true,
+		},
+		{
+			name:    "global without name or email",
+			global:  true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCommitter(tt.cname, tt.email, tt.global); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCommitter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExclusive(t *testing.T) {
+	if err := Exclusive("push", true, "dry-run", false); err != nil {
+		t.Errorf("Exclusive() unexpected error: %v", err)
+	}
+
+	err := Exclusive("push", true, "dry-run", true)
+	if err == nil || err.Error() != "--push and --dry-run cannot be used together" {
+		t.Errorf("Exclusive() error = %v, want mutually exclusive error", err)
+	}
+}
+
+func TestRequires(t *testing.T) {
+	if err := Requires("as-state", false, "as", false); err != nil {
+		t.Errorf("Requires() unexpected error: %v", err)
+	}
+
+	err := Requires("as-state", true, "as", false)
+	if err == nil || err.Error() != "--as-state requires --as" {
+		t.Errorf("Requires() error = %v, want dependency error", err)
+	}
+}
@@ -0,0 +1,340 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+// Package discord manages the roles, channels and role memberships of the
+// Discord guild of the project through the Discord REST API.
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChannelType is the type of a channel of a guild.
+type ChannelType int
+
+const (
+	ChannelText  ChannelType = 0
+	ChannelVoice ChannelType = 2
+)
+
+// String returns the name of the channel type.
+func (t ChannelType) String() string {
+	switch t {
+	case ChannelText:
+		return "text"
+	case ChannelVoice:
+		return "voice"
+	}
+
+	return strconv.Itoa(int(t))
+}
+
+// MarshalText encodes the channel type by its name, e.g. in plans.
+func (t ChannelType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// Role is a role of a guild.
+type Role struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Channel is a channel of a guild.
+type Channel struct {
+	ID    string      `json:"id"`
+	Name  string      `json:"name"`
+	Type  ChannelType `json:"type"`
+	Topic string      `json:"topic,omitempty"`
+}
+
+// User is a Discord account.
+type User struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	Discriminator string `json:"discriminator,omitempty"`
+}
+
+// Tag returns the name which identifies the account, i.e. its username or,
+// for accounts which have not migrated to unique usernames yet, its username
+// followed by its discriminator, e.g. "jane#1234".
+func (u User) Tag() string {
+	if u.Discriminator == "" || u.Discriminator == "0" {
+		return u.Username
+	}
+
+	return u.Username + "#" + u.Discriminator
+}
+
+// Member is a member of a guild.
+type Member struct {
+	User  User     `json:"user"`
+	Roles []string `json:"roles"`
+}
+
+// HasRole returns whether the member has the role with the provided ID.
+func (m Member) HasRole(id string) bool {
+	for _, role := range m.Roles {
+		if role == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Error is an error response of the Discord API.
+type Error struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// Client is a client of the Discord API acting as a bot on a single guild.
+type Client struct {
+	token    string
+	guildID  string
+	endpoint string
+	http     *http.Client
+}
+
+// NewClient returns a client which authenticates with the token of a bot and
+// manages the guild with the provided ID.
+func NewClient(token, guildID string, opts ...ClientOption) (*Client, error) {
+	if token == "" {
+		return nil, errors.New("missing Discord bot token")
+	}
+
+	if guildID == "" {
+		return nil, errors.New("missing Discord guild ID")
+	}
+
+	c := &Client{
+		token:    token,
+		guildID:  guildID,
+		endpoint: DefaultEndpoint,
+		http:     &http.Client{Timeout: DefaultTimeout},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// GuildID returns the ID of the guild which the client manages.
+func (c *Client) GuildID() string {
+	return c.guildID
+}
+
+// do performs the request with the JSON encoding of the body, if any, and
+// decodes the response into out, if set.  Requests which were rate limited
+// are retried after the delay requested by Discord.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Authorization", "Bot "+c.token)
+		req.Header.Set("User-Agent", "governctl (https://github.com/unikraft/governance)")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return err
+		}
+
+		raw, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < DefaultMaxAttempts {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(retryAfter(resp.Header)):
+			}
+
+			continue
+		}
+
+		if resp.StatusCode >= 300 {
+			var e struct {
+				Message string `json:"message"`
+			}
+			_ = json.Unmarshal(raw, &e)
+
+			return &Error{
+				Method:     method,
+				Path:       path,
+				StatusCode: resp.StatusCode,
+				Message:    e.Message,
+			}
+		}
+
+		if out == nil || len(raw) == 0 {
+			return nil
+		}
+
+		return json.Unmarshal(raw, out)
+	}
+}
+
+// retryAfter returns the delay which Discord requested before a rate limited
+// request is retried, in seconds with a fractional part.
+func retryAfter(header http.Header) time.Duration {
+	for _, key := range []string{"Retry-After", "X-RateLimit-Reset-After"} {
+		if seconds, err := strconv.ParseFloat(header.Get(key), 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds * float64(time.Second))
+		}
+	}
+
+	return time.Second
+}
+
+// ListRoles returns every role of the guild.
+func (c *Client) ListRoles(ctx context.Context) ([]Role, error) {
+	var roles []Role
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/guilds/%s/roles", c.guildID), nil, &roles); err != nil {
+		return nil, fmt.Errorf("could not list roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// CreateRole creates a role with the provided name which can be mentioned by
+// everyone.
+func (c *Client) CreateRole(ctx context.Context, name string) (*Role, error) {
+	role := &Role{}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/guilds/%s/roles", c.guildID), map[string]any{
+		"name":        name,
+		"mentionable": true,
+	}, role); err != nil {
+		return nil, fmt.Errorf("could not create role: %s: %w", name, err)
+	}
+
+	return role, nil
+}
+
+// ListChannels returns every channel of the guild.
+func (c *Client) ListChannels(ctx context.Context) ([]Channel, error) {
+	var channels []Channel
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/guilds/%s/channels", c.guildID), nil, &channels); err != nil {
+		return nil, fmt.Errorf("could not list channels: %w", err)
+	}
+
+	return channels, nil
+}
+
+// CreateChannel creates a channel of the provided type.  The topic is only
+// set on text channels.
+func (c *Client) CreateChannel(ctx context.Context, name string, typ ChannelType, topic string) (*Channel, error) {
+	body := map[string]any{
+		"name": name,
+		"type": int(typ),
+	}
+
+	if typ == ChannelText && topic != "" {
+		body["topic"] = topic
+	}
+
+	channel := &Channel{}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/guilds/%s/channels", c.guildID), body, channel); err != nil {
+		return nil, fmt.Errorf("could not create %s channel: %s: %w", typ, name, err)
+	}
+
+	return channel, nil
+}
+
+// SetChannelTopic sets the topic of the text channel with the provided ID.
+func (c *Client) SetChannelTopic(ctx context.Context, id, topic string) error {
+	if err := c.do(ctx, http.MethodPatch, fmt.Sprintf("/channels/%s", id), map[string]any{
+		"topic": topic,
+	}, nil); err != nil {
+		return fmt.Errorf("could not set topic of channel: %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// ListMembers returns every member of the guild.  The bot requires the
+// privileged intent of server members to list them.
+func (c *Client) ListMembers(ctx context.Context) ([]Member, error) {
+	var members []Member
+	after := "0"
+
+	for {
+		var more []Member
+
+		query := url.Values{"limit": {"1000"}, "after": {after}}
+		if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/guilds/%s/members?%s", c.guildID, query.Encode()), nil, &more); err != nil {
+			return nil, fmt.Errorf("could not list members: %w", err)
+		}
+
+		members = append(members, more...)
+
+		if len(more) < 1000 {
+			break
+		}
+
+		after = more[len(more)-1].User.ID
+	}
+
+	return members, nil
+}
+
+// AddMemberRole assigns the role to the member with the provided user ID.
+func (c *Client) AddMemberRole(ctx context.Context, userID, roleID string) error {
+	if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/guilds/%s/members/%s/roles/%s", c.guildID, userID, roleID), nil, nil); err != nil {
+		return fmt.Errorf("could not add role to member: %s: %w", userID, err)
+	}
+
+	return nil
+}
+
+// RemoveMemberRole removes the role from the member with the provided user ID.
+func (c *Client) RemoveMemberRole(ctx context.Context, userID, roleID string) error {
+	if err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/guilds/%s/members/%s/roles/%s", c.guildID, userID, roleID), nil, nil); err != nil {
+		return fmt.Errorf("could not remove role from member: %s: %w", userID, err)
+	}
+
+	return nil
+}
+
+// equalName returns whether the names of two roles or channels are equal,
+// ignoring case since Discord lowercases the names of text channels.
+func equalName(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package discord
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultEndpoint is the base URL of the Discord REST API unless otherwise
+// specified with WithEndpoint.
+const DefaultEndpoint = "https://discord.com/api/v10"
+
+// DefaultTimeout is the maximum duration of a single request to the Discord
+// API unless otherwise specified with WithTimeout.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultMaxAttempts is how often a request which was rate limited is
+// attempted in total before giving up.
+const DefaultMaxAttempts = 4
+
+type ClientOption func(*Client)
+
+// WithEndpoint sets the base URL of the Discord REST API, e.g. of a test
+// server.
+func WithEndpoint(endpoint string) ClientOption {
+	return func(c *Client) {
+		c.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithTimeout sets the maximum duration of a single request to the Discord
+// API.  A timeout of zero disables it.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.http = &http.Client{Timeout: timeout}
+	}
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient("", "guild"); err == nil {
+		t.Error("NewClient() without token did not fail")
+	}
+
+	if _, err := NewClient("token", ""); err == nil {
+		t.Error("NewClient() without guild did not fail")
+	}
+}
+
+func TestClientApply(t *testing.T) {
+	var requests []string
+	attempts := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bot token" {
+			t.Errorf("Authorization = %q, want %q", got, "Bot token")
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, strings.TrimSpace(fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, body)))
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/guilds/42/roles":
+			attempts++
+			if attempts == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+
+			fmt.Fprint(w, `{"id":"7","name":"sig-net"}`)
+		case r.Method == http.MethodPost:
+			fmt.Fprint(w, `{"id":"8"}`)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient("token", "42", WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := plan(
+		[]SIG{{Name: "sig-net", Topic: "Networking", Members: []string{"jane"}}},
+		nil,
+		[]Channel{{ID: "9", Name: "sig-net", Type: ChannelVoice}},
+		[]Member{{User: User{ID: "100", Username: "jane"}}},
+	)
+
+	if err := c.Apply(context.Background(), p); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	want := []string{
+		`POST /guilds/42/roles {"mentionable":true,"name":"sig-net"}`,
+		`POST /guilds/42/roles {"mentionable":true,"name":"sig-net"}`,
+		`PUT /guilds/42/members/100/roles/7`,
+		`POST /guilds/42/channels {"name":"sig-net","topic":"Networking","type":0}`,
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+}
+
+func TestClientError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"Missing Access","code":50001}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient("token", "42", WithEndpoint(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.ListRoles(context.Background())
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("ListRoles() error = %v, want *Error", err)
+	}
+
+	if apiErr.StatusCode != http.StatusForbidden || apiErr.Message != "Missing Access" {
+		t.Errorf("ListRoles() error = %+v", apiErr)
+	}
+}
@@ -0,0 +1,310 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package discord
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/team"
+	"github.com/unikraft/governance/internal/user"
+)
+
+// SIG is the desired state of the role and channels of a Special Interest
+// Group on Discord.
+type SIG struct {
+	// Name is the name of the role and of the text and voice channels, e.g.
+	// "sig-arch".
+	Name string `json:"name"`
+
+	// Topic is the topic of the text channel.
+	Topic string `json:"topic,omitempty"`
+
+	// Members are the Discord usernames of the members of the SIG, which
+	// include its maintainers and reviewers.
+	Members []string `json:"members,omitempty"`
+}
+
+// SIGsFromTeams returns the SIG of each team of the SIG type, sorted by name.
+// Members of the teams without a Discord username are skipped.
+func SIGsFromTeams(teams []*team.Team) ([]SIG, error) {
+	var sigs []SIG
+
+	for _, t := range teams {
+		if t.Type != team.SIGTeam {
+			continue
+		}
+
+		topic, err := t.RenderDescription()
+		if err != nil {
+			return nil, err
+		}
+
+		sig := SIG{
+			Name:  t.Fullname(),
+			Topic: topic,
+		}
+
+		seen := make(map[string]struct{})
+		for _, users := range [][]string{discordUsernames(t.Maintainers), discordUsernames(t.Reviewers), discordUsernames(t.Members)} {
+			for _, username := range users {
+				if _, ok := seen[strings.ToLower(username)]; ok {
+					continue
+				}
+
+				seen[strings.ToLower(username)] = struct{}{}
+				sig.Members = append(sig.Members, username)
+			}
+		}
+
+		sort.Strings(sig.Members)
+		sigs = append(sigs, sig)
+	}
+
+	sort.Slice(sigs, func(i, j int) bool {
+		return sigs[i].Name < sigs[j].Name
+	})
+
+	return sigs, nil
+}
+
+// RoleChange is the set of changes to the role of a SIG and to the members
+// which have been assigned it.
+type RoleChange struct {
+	Role string `json:"role"`
+
+	// Create is set if the role does not exist yet.
+	Create bool `json:"create,omitempty"`
+
+	// Add and Remove are the Discord usernames of the members of the guild
+	// which are assigned or removed the role.
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+
+	// Unknown are the Discord usernames of the members of the SIG which are
+	// not members of the guild and therefore cannot be assigned the role.
+	Unknown []string `json:"unknown,omitempty"`
+
+	roleID string
+	users  map[string]string
+}
+
+// Empty returns whether the role and its members are already as desired.
+func (c *RoleChange) Empty() bool {
+	return !c.Create && len(c.Add) == 0 && len(c.Remove) == 0
+}
+
+// ChannelChange is a channel of a SIG which is created or whose topic is
+// updated.
+type ChannelChange struct {
+	Channel string      `json:"channel"`
+	Type    ChannelType `json:"type"`
+	Create  bool        `json:"create,omitempty"`
+	Topic   string      `json:"topic,omitempty"`
+
+	channelID string
+}
+
+// Plan is the set of changes to the roles, channels and role memberships of
+// the guild which bring it in line with the SIGs.
+type Plan struct {
+	Roles    []*RoleChange    `json:"roles"`
+	Channels []*ChannelChange `json:"channels,omitempty"`
+}
+
+// Plan determines the changes to the guild necessary to bring it in line with
+// the provided SIGs without performing them.
+func (c *Client) Plan(ctx context.Context, sigs []SIG) (*Plan, error) {
+	roles, err := c.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := c.ListChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := c.ListMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan(sigs, roles, channels, members), nil
+}
+
+// plan determines the changes to the provided state of the guild necessary to
+// bring it in line with the SIGs.
+func plan(sigs []SIG, roles []Role, channels []Channel, members []Member) *Plan {
+	p := &Plan{
+		Roles: make([]*RoleChange, 0, len(sigs)),
+	}
+
+	byTag := make(map[string]Member, len(members))
+	for _, m := range members {
+		byTag[strings.ToLower(m.User.Tag())] = m
+	}
+
+	for _, sig := range sigs {
+		change := &RoleChange{
+			Role:  sig.Name,
+			users: make(map[string]string),
+		}
+
+		for _, r := range roles {
+			if equalName(r.Name, sig.Name) {
+				change.roleID = r.ID
+				break
+			}
+		}
+
+		change.Create = change.roleID == ""
+
+		desired := make(map[string]struct{}, len(sig.Members))
+		for _, username := range sig.Members {
+			m, ok := byTag[strings.ToLower(username)]
+			if !ok {
+				change.Unknown = append(change.Unknown, username)
+				continue
+			}
+
+			desired[m.User.ID] = struct{}{}
+
+			if change.roleID == "" || !m.HasRole(change.roleID) {
+				change.Add = append(change.Add, m.User.Tag())
+				change.users[m.User.Tag()] = m.User.ID
+			}
+		}
+
+		if change.roleID != "" {
+			for _, m := range members {
+				if _, ok := desired[m.User.ID]; ok || !m.HasRole(change.roleID) {
+					continue
+				}
+
+				change.Remove = append(change.Remove, m.User.Tag())
+				change.users[m.User.Tag()] = m.User.ID
+			}
+		}
+
+		sort.Strings(change.Add)
+		sort.Strings(change.Remove)
+
+		p.Roles = append(p.Roles, change)
+
+		for _, typ := range []ChannelType{ChannelText, ChannelVoice} {
+			var existing *Channel
+			for i, ch := range channels {
+				if ch.Type == typ && equalName(ch.Name, sig.Name) {
+					existing = &channels[i]
+					break
+				}
+			}
+
+			topic := ""
+			if typ == ChannelText {
+				topic = sig.Topic
+			}
+
+			switch {
+			case existing == nil:
+				p.Channels = append(p.Channels, &ChannelChange{
+					Channel: sig.Name,
+					Type:    typ,
+					Create:  true,
+					Topic:   topic,
+				})
+			case typ == ChannelText && existing.Topic != topic:
+				p.Channels = append(p.Channels, &ChannelChange{
+					Channel:   existing.Name,
+					Type:      typ,
+					Topic:     topic,
+					channelID: existing.ID,
+				})
+			}
+		}
+	}
+
+	return p
+}
+
+// Apply performs the changes of the plan.  Roles are created before their
+// members are assigned them.
+func (c *Client) Apply(ctx context.Context, p *Plan) error {
+	for _, change := range p.Roles {
+		if change.Create {
+			log.G(ctx).WithField("role", change.Role).Info("creating role")
+
+			role, err := c.CreateRole(ctx, change.Role)
+			if err != nil {
+				return err
+			}
+
+			change.roleID = role.ID
+		}
+
+		for _, tag := range change.Add {
+			log.G(ctx).
+				WithField("role", change.Role).
+				WithField("user", tag).
+				Info("adding")
+
+			if err := c.AddMemberRole(ctx, change.users[tag], change.roleID); err != nil {
+				return err
+			}
+		}
+
+		for _, tag := range change.Remove {
+			log.G(ctx).
+				WithField("role", change.Role).
+				WithField("user", tag).
+				Info("removing")
+
+			if err := c.RemoveMemberRole(ctx, change.users[tag], change.roleID); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, change := range p.Channels {
+		logger := log.G(ctx).
+			WithField("channel", change.Channel).
+			WithField("type", change.Type.String())
+
+		if change.Create {
+			logger.Info("creating channel")
+
+			if _, err := c.CreateChannel(ctx, change.Channel, change.Type, change.Topic); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		logger.Info("updating topic")
+
+		if err := c.SetChannelTopic(ctx, change.channelID, change.Topic); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// discordUsernames returns the Discord usernames of the users which have one.
+func discordUsernames(users []user.User) []string {
+	var usernames []string
+	for _, u := range users {
+		if u.Discord != "" {
+			usernames = append(usernames, u.Discord)
+		}
+	}
+
+	return usernames
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package discord
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/unikraft/governance/internal/team"
+	"github.com/unikraft/governance/internal/user"
+)
+
+func TestSIGsFromTeams(t *testing.T) {
+	teams := []*team.Team{
+		{
+			Name:        "arch",
+			Type:        team.SIGTeam,
+			Description: "Architecture support",
+			Maintainers: []user.User{{Github: "jane", Discord: "jane"}},
+			Reviewers:   []user.User{{Github: "bob", Discord: "Bob"}, {Github: "nodiscord"}},
+			Members:     []user.User{{Github: "jane", Discord: "Jane"}, {Github: "alice", Discord: "alice#1234"}},
+		},
+		{
+			Name: "bots",
+			Type: team.MiscTeam,
+		},
+	}
+
+	got, err := SIGsFromTeams(teams)
+	if err != nil {
+		t.Fatalf("SIGsFromTeams() error = %v", err)
+	}
+
+	want := []SIG{{
+		Name:    "sig-arch",
+		Topic:   "Architecture support",
+		Members: []string{"Bob", "alice#1234", "jane"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SIGsFromTeams() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	sigs := []SIG{
+		{Name: "sig-arch", Topic: "Architecture support", Members: []string{"jane", "bob", "ghost"}},
+		{Name: "sig-net", Topic: "Networking", Members: []string{"alice#1234"}},
+	}
+
+	roles := []Role{{ID: "1", Name: "SIG-Arch"}}
+	channels := []Channel{
+		{ID: "10", Name: "sig-arch", Type: ChannelText, Topic: "Old topic"},
+		{ID: "11", Name: "sig-arch", Type: ChannelVoice},
+		{ID: "12", Name: "sig-net", Type: ChannelText, Topic: "Networking"},
+	}
+	members := []Member{
+		{User: User{ID: "100", Username: "jane", Discriminator: "0"}, Roles: []string{"1"}},
+		{User: User{ID: "101", Username: "Bob"}},
+		{User: User{ID: "102", Username: "eve"}, Roles: []string{"1"}},
+		{User: User{ID: "103", Username: "alice", Discriminator: "1234"}},
+	}
+
+	got := plan(sigs, roles, channels, members)
+
+	wantRoles := []RoleChange{
+		{Role: "sig-arch", Add: []string{"Bob"}, Remove: []string{"eve"}, Unknown: []string{"ghost"}},
+		{Role: "sig-net", Create: true, Add: []string{"alice#1234"}},
+	}
+	if len(got.Roles) != len(wantRoles) {
+		t.Fatalf("plan() returned %d roles, want %d", len(got.Roles), len(wantRoles))
+	}
+
+	for i, want := range wantRoles {
+		change := got.Roles[i]
+		if change.Role != want.Role || change.Create != want.Create ||
+			!reflect.DeepEqual(change.Add, want.Add) ||
+			!reflect.DeepEqual(change.Remove, want.Remove) ||
+			!reflect.DeepEqual(change.Unknown, want.Unknown) {
+			t.Errorf("plan() role %d = %+v, want %+v", i, change, want)
+		}
+	}
+
+	if id := got.Roles[0].users["eve"]; id != "102" {
+		t.Errorf("plan() resolved eve to %q, want %q", id, "102")
+	}
+
+	wantChannels := []ChannelChange{
+		{Channel: "sig-arch", Type: ChannelText, Topic: "Architecture support", channelID: "10"},
+		{Channel: "sig-net", Type: ChannelVoice, Create: true},
+	}
+	if len(got.Channels) != len(wantChannels) {
+		t.Fatalf("plan() returned %d channels, want %d", len(got.Channels), len(wantChannels))
+	}
+
+	for i, want := range wantChannels {
+		if *got.Channels[i] != want {
+			t.Errorf("plan() channel %d = %+v, want %+v", i, *got.Channels[i], want)
+		}
+	}
+}
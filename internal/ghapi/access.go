@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// isNotFound returns whether the error is a 404 response from the API.
+func isNotFound(err error) bool {
+	var ghErr *github.ErrorResponse
+	return errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound
+}
+
+// ListTeamsOfUser returns every team of the organisation which the user is a
+// member of or has been invited to, in the form "@org/team".
+func (c *GithubClient) ListTeamsOfUser(ctx context.Context, org, username string) ([]string, error) {
+	var teams []string
+	opts := &github.ListOptions{
+		PerPage: 100,
+	}
+
+	for {
+		more, resp, err := c.client.Teams.ListTeams(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list teams: %w", err)
+		}
+
+		for _, team := range more {
+			_, _, err := c.client.Teams.GetTeamMembershipBySlug(ctx, org, team.GetSlug(), username)
+			if isNotFound(err) {
+				continue
+			} else if err != nil {
+				return nil, teamError(org, team.GetSlug(), err)
+			}
+
+			teams = append(teams, fmt.Sprintf("@%s/%s", org, team.GetSlug()))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	sort.Strings(teams)
+
+	return teams, nil
+}
+
+// ListDirectCollaborations returns every repository of the organisation which
+// the user has been granted access to directly as an outside or organisation
+// collaborator, as opposed to through a team.
+func (c *GithubClient) ListDirectCollaborations(ctx context.Context, org, username string) ([]string, error) {
+	var repos []string
+	opts := &github.RepositoryListByOrgOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	for {
+		more, resp, err := c.client.Repositories.ListByOrg(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list repositories: %w", err)
+		}
+
+		for _, repo := range more {
+			collaborator, err := c.isDirectCollaborator(ctx, org, repo.GetName(), username)
+			if err != nil {
+				return nil, fmt.Errorf("could not list collaborators of %s/%s: %w", org, repo.GetName(), err)
+			}
+
+			if collaborator {
+				repos = append(repos, repo.GetName())
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	sort.Strings(repos)
+
+	return repos, nil
+}
+
+// isDirectCollaborator returns whether the user has been granted access to
+// the repository directly.
+func (c *GithubClient) isDirectCollaborator(ctx context.Context, org, repo, username string) (bool, error) {
+	opts := &github.ListCollaboratorsOptions{
+		Affiliation: "direct",
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	for {
+		more, resp, err := c.client.Repositories.ListCollaborators(ctx, org, repo, opts)
+		if err != nil {
+			return false, err
+		}
+
+		for _, user := range more {
+			if strings.EqualFold(user.GetLogin(), username) {
+				return true, nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return false, nil
+		}
+
+		opts.Page = resp.NextPage
+	}
+}
+
+// SearchIssues returns every issue and pull request matching the query, e.g.
+// "is:pr is:open org:unikraft assignee:octocat".
+func (c *GithubClient) SearchIssues(ctx context.Context, query string) ([]*github.Issue, error) {
+	var issues []*github.Issue
+	opts := &github.SearchOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	for {
+		result, resp, err := c.client.Search.Issues(ctx, query, opts)
+		if err != nil {
+			return nil, fmt.Errorf("could not search issues: %w", err)
+		}
+
+		issues = append(issues, result.Issues...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return issues, nil
+}
+
+// RemoveUserFromPr removes the user from the assignees and the requested
+// reviewers of the pull request.
+func (c *GithubClient) RemoveUserFromPr(ctx context.Context, org, repo string, prId int, username string) error {
+	if _, _, err := c.client.Issues.RemoveAssignees(ctx, org, repo, prId, []string{username}); err != nil {
+		return fmt.Errorf("could not remove assignee: %w", err)
+	}
+
+	if _, err := c.client.PullRequests.RemoveReviewers(ctx, org, repo, prId, github.ReviewersRequest{
+		Reviewers: []string{username},
+	}); err != nil && !isNotFound(err) {
+		return fmt.Errorf("could not remove requested reviewer: %w", err)
+	}
+
+	return nil
+}
+
+// CreatePullRequest opens a pull request from the head branch onto the base
+// branch of the repository and returns it.
+func (c *GithubClient) CreatePullRequest(ctx context.Context, org, repo, head, base, title, body string) (*github.PullRequest, error) {
+	pr, _, err := c.client.PullRequests.Create(ctx, org, repo, &github.NewPullRequest{
+		Title: &title,
+		Head:  &head,
+		Base:  &base,
+		Body:  &body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// ErrAppRequired is returned by endpoints which are only available to GitHub
+// Apps, such as check runs, when the client authenticates with a personal
+// access token.
+var ErrAppRequired = errors.New("only available when authenticated as a GitHub App")
+
+// githubApp are the credentials of a GitHub App installation.
+type githubApp struct {
+	id             int64
+	installationID int64
+	privateKey     string
+}
+
+// loadPrivateKey parses the PEM-encoded RSA private key of the app, either in
+// PKCS#1 or PKCS#8 form, from the provided file.
+func loadPrivateKey(file string) (*rsa.PrivateKey, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read GitHub App private key: %w", err)
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("could not decode GitHub App private key %s: not PEM-encoded", file)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse GitHub App private key %s: %w", file, err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("GitHub App private key %s is not an RSA key", file)
+	}
+
+	return key, nil
+}
+
+// appJWT returns the JSON Web Token which authenticates as the app itself.
+// The token is backdated by a minute to allow for clock drift and expires
+// after nine minutes, just below the maximum of ten allowed by GitHub.
+func appJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("could not sign GitHub App token: %w", err)
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// installationToken exchanges a token of the app for an access token of its
+// installation using the provided client, which must not be authenticated
+// otherwise.
+func (app *githubApp) installationToken(ctx context.Context, client *github.Client) (string, error) {
+	key, err := loadPrivateKey(app.privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	jwt, err := appJWT(app.id, key, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	token, _, err := client.WithAuthToken(jwt).Apps.CreateInstallationToken(ctx, app.installationID, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not create GitHub App installation token: %w", err)
+	}
+
+	return token.GetToken(), nil
+}
@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// writeTestKey generates an RSA key and writes it PEM-encoded to a temporary
+// file.
+func writeTestKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(t.TempDir(), "app.pem")
+	raw := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+	if err := os.WriteFile(file, raw, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return key, file
+}
+
+func TestAppJWT(t *testing.T) {
+	key, _ := writeTestKey(t)
+	now := time.Unix(1700000000, 0)
+
+	jwt, err := appJWT(1234, key, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected three parts, got %d", len(parts))
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Errorf("invalid signature: %v", err)
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims struct {
+		Iat int64  `json:"iat"`
+		Exp int64  `json:"exp"`
+		Iss string `json:"iss"`
+	}
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Iss != "1234" || claims.Iat != now.Unix()-60 || claims.Exp != now.Unix()+540 {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestNewGithubClientWithApp(t *testing.T) {
+	_, keyFile := writeTestKey(t)
+
+	var created bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/app/installations/5678/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+
+		if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "Bearer ") || strings.Count(auth, ".") != 2 {
+			t.Errorf("expected the app's token, got %q", auth)
+		}
+
+		fmt.Fprint(w, `{"token":"ghs_installation"}`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer ghs_installation" {
+			t.Errorf("expected the installation token, got %q", auth)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"status":"in_progress"`) {
+			t.Errorf("expected check run in progress, got %s", body)
+		}
+
+		created = true
+		fmt.Fprint(w, `{"id":42}`)
+	})
+
+	// Read-only mode does not prevent the exchange of the app's token.
+	client := newTestClient(t, mux, WithApp(1234, 5678, keyFile), WithReadOnly(true))
+
+	_, err := client.CreateCheckRun(context.Background(), "unikraft", "unikraft", "governctl", "abc123")
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("CreateCheckRun() error = %v, want ErrReadOnly", err)
+	}
+
+	client = newTestClient(t, mux, WithApp(1234, 5678, keyFile))
+
+	id, err := client.CreateCheckRun(context.Background(), "unikraft", "unikraft", "governctl", "abc123")
+	if err != nil {
+		t.Fatalf("CreateCheckRun() error = %v", err)
+	}
+
+	if id != 42 || !created {
+		t.Errorf("CreateCheckRun() = %d, want 42", id)
+	}
+}
+
+func TestCheckRunRequiresApp(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+
+	if _, err := client.CreateCheckRun(context.Background(), "unikraft", "unikraft", "governctl", "abc123"); !errors.Is(err, ErrAppRequired) {
+		t.Errorf("CreateCheckRun() error = %v, want ErrAppRequired", err)
+	}
+
+	if err := client.UpdateCheckRun(context.Background(), "unikraft", "unikraft", 42, "governctl", "success", "ok", "", nil); !errors.Is(err, ErrAppRequired) {
+		t.Errorf("UpdateCheckRun() error = %v, want ErrAppRequired", err)
+	}
+}
+
+func TestUpdateCheckRunLimitsAnnotations(t *testing.T) {
+	_, keyFile := writeTestKey(t)
+
+	var got struct {
+		Conclusion string `json:"conclusion"`
+		Output     struct {
+			Annotations []json.RawMessage `json:"annotations"`
+		} `json:"output"`
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/app/installations/5678/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"token":"ghs_installation"}`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/check-runs/42", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+
+		fmt.Fprint(w, `{"id":42}`)
+	})
+
+	client := newTestClient(t, mux, WithApp(1234, 5678, keyFile))
+
+	annotations := make([]*github.CheckRunAnnotation, MaxCheckRunAnnotations+10)
+	for i := range annotations {
+		annotations[i] = &github.CheckRunAnnotation{}
+	}
+
+	if err := client.UpdateCheckRun(context.Background(), "unikraft", "unikraft", 42, "governctl", "failure", "failed", "summary", annotations); err != nil {
+		t.Fatalf("UpdateCheckRun() error = %v", err)
+	}
+
+	if got.Conclusion != "failure" || len(got.Output.Annotations) != MaxCheckRunAnnotations {
+		t.Errorf("got conclusion %q with %d annotations, want failure with %d", got.Conclusion, len(got.Output.Annotations), MaxCheckRunAnnotations)
+	}
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+const (
+	// MaxReviewersPerRequest is the maximum number of reviewers which GitHub
+	// accepts in a single review request before responding with a 422.
+	MaxReviewersPerRequest = 15
+
+	// MaxAssigneesPerRequest is the maximum number of assignees which GitHub
+	// accepts in a single request before responding with a 422.
+	MaxAssigneesPerRequest = 10
+)
+
+// AssignmentResult is the breakdown of a request to add reviewers or assignees
+// to a pull request.
+type AssignmentResult struct {
+	// Added is the list of usernames which were successfully added.
+	Added []string
+
+	// Present is the list of usernames which were already requested or
+	// assigned and were therefore skipped.
+	Present []string
+
+	// Failed is the list of usernames which could not be added along with the
+	// error returned by GitHub for the batch they were part of.
+	Failed map[string]error
+}
+
+// newAssignmentResult prepares a result by filtering out empty, duplicate and
+// already present usernames from the wanted list, returning the usernames which
+// still need to be added.
+func newAssignmentResult(wanted, existing []string) (*AssignmentResult, []string) {
+	result := &AssignmentResult{
+		Failed: make(map[string]error),
+	}
+
+	present := make(map[string]struct{}, len(existing))
+	for _, username := range existing {
+		present[username] = struct{}{}
+	}
+
+	seen := make(map[string]struct{}, len(wanted))
+	var toAdd []string
+
+	for _, username := range wanted {
+		if username == "" {
+			continue
+		}
+		if _, ok := seen[username]; ok {
+			continue
+		}
+
+		seen[username] = struct{}{}
+
+		if _, ok := present[username]; ok {
+			result.Present = append(result.Present, username)
+			continue
+		}
+
+		toAdd = append(toAdd, username)
+	}
+
+	return result, toAdd
+}
+
+// fail marks all usernames in the batch as failed with the provided error.
+func (result *AssignmentResult) fail(usernames []string, err error) {
+	for _, username := range usernames {
+		result.Failed[username] = err
+	}
+}
+
+// chunkStrings splits the provided list into batches of at most size entries.
+func chunkStrings(s []string, size int) [][]string {
+	var chunks [][]string
+
+	for size < len(s) {
+		s, chunks = s[size:], append(chunks, s[0:size:size])
+	}
+
+	if len(s) > 0 {
+		chunks = append(chunks, s)
+	}
+
+	return chunks
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func usernames(prefix string, n int) []string {
+	var ret []string
+	for i := 0; i < n; i++ {
+		ret = append(ret, fmt.Sprintf("%s%d", prefix, i))
+	}
+	return ret
+}
+
+func TestChunkStrings(t *testing.T) {
+	tests := []struct {
+		name string
+		in   int
+		size int
+		want []int
+	}{
+		{name: "empty", in: 0, size: 15, want: nil},
+		{name: "single partial chunk", in: 3, size: 15, want: []int{3}},
+		{name: "exact chunk", in: 15, size: 15, want: []int{15}},
+		{name: "multiple chunks", in: 32, size: 15, want: []int{15, 15, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chunkStrings(usernames("u", tt.in), tt.size)
+			if len(got) != len(tt.want) {
+				t.Fatalf("chunkStrings() returned %d chunks, want %d", len(got), len(tt.want))
+			}
+			for i, chunk := range got {
+				if len(chunk) != tt.want[i] {
+					t.Errorf("chunk %d has %d entries, want %d", i, len(chunk), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAddReviewersToPrChunksAndSkipsExisting(t *testing.T) {
+	var mu sync.Mutex
+	var requests [][]string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/requested_reviewers", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"users":[{"login":"r0"},{"login":"r1"}]}`)
+		case http.MethodPost:
+			var req struct {
+				Reviewers []string `json:"reviewers"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("could not decode request: %v", err)
+			}
+
+			mu.Lock()
+			requests = append(requests, req.Reviewers)
+			mu.Unlock()
+
+			fmt.Fprint(w, `{"number":1}`)
+		}
+	})
+
+	client := newTestClient(t, mux)
+
+	wanted := append(usernames("r", 20), "r3", "")
+	result, err := client.AddReviewersToPr(context.Background(), "unikraft", "unikraft", 1, wanted)
+	if err != nil {
+		t.Fatalf("AddReviewersToPr() unexpected error: %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	if len(requests[0]) != MaxReviewersPerRequest || len(requests[1]) != 3 {
+		t.Errorf("unexpected chunk sizes: %d and %d", len(requests[0]), len(requests[1]))
+	}
+	if len(result.Added) != 18 {
+		t.Errorf("expected 18 added, got %d", len(result.Added))
+	}
+	if len(result.Present) != 2 {
+		t.Errorf("expected 2 present, got %v", result.Present)
+	}
+	if len(result.Failed) != 0 {
+		t.Errorf("expected 0 failed, got %v", result.Failed)
+	}
+}
+
+func TestAddMaintainersToPrPartialFailure(t *testing.T) {
+	calls := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number":1,"assignees":[{"login":"m0"}]}`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/assignees", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		// Reject the second batch
+		if calls == 2 {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"message":"Validation Failed"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"number":1}`)
+	})
+
+	client := newTestClient(t, mux)
+
+	result, err := client.AddMaintainersToPr(context.Background(), "unikraft", "unikraft", 1, usernames("m", 15))
+	if err == nil {
+		t.Fatalf("AddMaintainersToPr() expected error")
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected 2 requests, got %d", calls)
+	}
+	if len(result.Present) != 1 || result.Present[0] != "m0" {
+		t.Errorf("expected m0 to be present, got %v", result.Present)
+	}
+	if len(result.Added) != MaxAssigneesPerRequest {
+		t.Errorf("expected %d added, got %d", MaxAssigneesPerRequest, len(result.Added))
+	}
+	if len(result.Failed) != 4 {
+		t.Errorf("expected 4 failed, got %d", len(result.Failed))
+	}
+	for _, username := range usernames("m", 15)[11:] {
+		if _, ok := result.Failed[username]; !ok {
+			t.Errorf("expected %s to have failed", username)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// AuthInfo describes the identity which the client authenticates as.
+type AuthInfo struct {
+	// Login is the user the token belongs to.  It is empty for GitHub Apps.
+	Login string `json:"login,omitempty"`
+
+	// App is set when the client authenticates as a GitHub App installation.
+	App bool `json:"app,omitempty"`
+
+	// Scopes are the OAuth scopes of a classic token.  They are only known if
+	// ScopesKnown is set, as fine-grained tokens and GitHub Apps have
+	// permissions instead.
+	Scopes      []string `json:"scopes,omitempty"`
+	ScopesKnown bool     `json:"scopes_known,omitempty"`
+}
+
+// HasScope returns whether the token has any of the provided scopes.  Scopes
+// which cannot be listed are assumed to be present.
+func (info *AuthInfo) HasScope(scopes ...string) bool {
+	if !info.ScopesKnown {
+		return true
+	}
+
+	for _, scope := range scopes {
+		if contains(info.Scopes, scope) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Authenticate checks that the credentials of the client are accepted by the
+// API and returns the identity they belong to.
+func (c *GithubClient) Authenticate(ctx context.Context) (*AuthInfo, error) {
+	if c.app {
+		if _, _, err := c.client.Apps.ListRepos(ctx, &github.ListOptions{PerPage: 1}); err != nil {
+			return nil, fmt.Errorf("could not authenticate as GitHub App: %w", err)
+		}
+
+		return &AuthInfo{App: true}, nil
+	}
+
+	user, resp, err := c.client.Users.Get(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("could not authenticate: %w", err)
+	}
+
+	info := &AuthInfo{
+		Login: user.GetLogin(),
+	}
+
+	if header, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		info.ScopesKnown = true
+
+		for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				info.Scopes = append(info.Scopes, scope)
+			}
+		}
+	}
+
+	return info, nil
+}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name        string
+		scopes      *string
+		wantScopes  []string
+		wantKnown   bool
+		wantHasRepo bool
+	}{
+		{
+			name:        "classic token",
+			scopes:      strPtr("repo, read:org"),
+			wantScopes:  []string{"repo", "read:org"},
+			wantKnown:   true,
+			wantHasRepo: true,
+		},
+		{
+			name:        "classic token without scopes",
+			scopes:      strPtr(""),
+			wantKnown:   true,
+			wantHasRepo: false,
+		},
+		{
+			name:        "fine-grained token",
+			wantHasRepo: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/user", func(w http.ResponseWriter, r *http.Request) {
+				if tt.scopes != nil {
+					w.Header().Set("X-OAuth-Scopes", *tt.scopes)
+				}
+
+				fmt.Fprint(w, `{"login":"unikraft-bot"}`)
+			})
+
+			info, err := newTestClient(t, mux).Authenticate(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if info.Login != "unikraft-bot" {
+				t.Errorf("Login = %s, want unikraft-bot", info.Login)
+			}
+
+			if info.ScopesKnown != tt.wantKnown || !reflect.DeepEqual(info.Scopes, tt.wantScopes) {
+				t.Errorf("Scopes = %v (known: %v), want %v (known: %v)", info.Scopes, info.ScopesKnown, tt.wantScopes, tt.wantKnown)
+			}
+
+			if got := info.HasScope("repo"); got != tt.wantHasRepo {
+				t.Errorf("HasScope(repo) = %v, want %v", got, tt.wantHasRepo)
+			}
+		})
+	}
+}
+
+// strPtr returns a pointer to the provided string.
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// MaxCheckRunAnnotations is the maximum number of annotations which GitHub
+// accepts with a single update of a check run.
+const MaxCheckRunAnnotations = 50
+
+// maxCommitStatusDescription is the maximum length of the description of a
+// commit status which GitHub accepts.
+const maxCommitStatusDescription = 140
+
+// CommitStates are the states which GitHub accepts for a commit status.
+var CommitStates = []string{
+	"error",
+	"failure",
+	"pending",
+	"success",
+}
+
+// ListFailingChecks returns the names of all commit statuses and check runs
+// of the provided ref which have not (yet) succeeded.  Check runs which were
+// skipped or were neutral are considered successful.  An empty list means
+// that every check is green.
+func (c *GithubClient) ListFailingChecks(ctx context.Context, org, repo, ref string) ([]string, error) {
+	states, err := c.ListCheckStates(ctx, org, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var failing []string
+
+	for name, state := range states {
+		if state != "success" {
+			failing = append(failing, fmt.Sprintf("%s (%s)", name, state))
+		}
+	}
+
+	sort.Strings(failing)
+
+	return failing, nil
+}
+
+// ListCheckStates returns the state of every commit status and check run of
+// the provided ref keyed by its context or name.  Completed check runs are
+// reported by their conclusion, where skipped and neutral runs are reported
+// as "success", and all other check runs by their status.
+func (c *GithubClient) ListCheckStates(ctx context.Context, org, repo, ref string) (map[string]string, error) {
+	states := make(map[string]string)
+
+	statusOpts := &github.ListOptions{
+		PerPage: 100,
+	}
+
+	for {
+		combined, resp, err := c.client.Repositories.GetCombinedStatus(ctx, org, repo, ref, statusOpts)
+		if err != nil {
+			return nil, fmt.Errorf("could not get combined status: %w", err)
+		}
+
+		for _, status := range combined.Statuses {
+			states[status.GetContext()] = status.GetState()
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		statusOpts.Page = resp.NextPage
+	}
+
+	runOpts := &github.ListCheckRunsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	for {
+		runs, resp, err := c.client.Checks.ListCheckRunsForRef(ctx, org, repo, ref, runOpts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list check runs: %w", err)
+		}
+
+		for _, run := range runs.CheckRuns {
+			if run.GetStatus() != "completed" {
+				states[run.GetName()] = run.GetStatus()
+				continue
+			}
+
+			switch run.GetConclusion() {
+			case "success", "neutral", "skipped":
+				states[run.GetName()] = "success"
+			default:
+				states[run.GetName()] = run.GetConclusion()
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		runOpts.Page = resp.NextPage
+	}
+
+	return states, nil
+}
+
+// CreateCheckRun creates a check run with the provided name on the provided
+// commit which is in progress and returns its ID.  Check runs can only be
+// created when authenticated as a GitHub App.
+func (c *GithubClient) CreateCheckRun(ctx context.Context, org, repo, name, headSHA string) (int64, error) {
+	if !c.app {
+		return 0, fmt.Errorf("could not create check run: %w", ErrAppRequired)
+	}
+
+	run, _, err := c.client.Checks.CreateCheckRun(ctx, org, repo, github.CreateCheckRunOptions{
+		Name:      name,
+		HeadSHA:   headSHA,
+		Status:    github.String("in_progress"),
+		StartedAt: &github.Timestamp{Time: time.Now()},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not create check run: %w", err)
+	}
+
+	return run.GetID(), nil
+}
+
+// UpdateCheckRun completes the check run with the provided conclusion, e.g.
+// "success" or "failure", and output.  The summary is rendered as markdown.
+// Only the first MaxCheckRunAnnotations annotations are attached.
+func (c *GithubClient) UpdateCheckRun(ctx context.Context, org, repo string, id int64, name, conclusion, title, summary string, annotations []*github.CheckRunAnnotation) error {
+	if !c.app {
+		return fmt.Errorf("could not update check run: %w", ErrAppRequired)
+	}
+
+	if len(annotations) > MaxCheckRunAnnotations {
+		annotations = annotations[:MaxCheckRunAnnotations]
+	}
+
+	_, _, err := c.client.Checks.UpdateCheckRun(ctx, org, repo, id, github.UpdateCheckRunOptions{
+		Name:        name,
+		Status:      github.String("completed"),
+		Conclusion:  github.String(conclusion),
+		CompletedAt: &github.Timestamp{Time: time.Now()},
+		Output: &github.CheckRunOutput{
+			Title:       github.String(title),
+			Summary:     github.String(summary),
+			Annotations: annotations,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not update check run: %w", err)
+	}
+
+	return nil
+}
+
+// SetCommitStatus sets the commit status with the provided context, e.g.
+// "governance/mergable", of the provided commit to one of CommitStates.  Unlike
+// check runs, commit statuses can be set with an access token and can
+// therefore be required by branch protection regardless of how governance
+// authenticates.  Descriptions longer than GitHub accepts are truncated and
+// the target URL may be empty.
+func (c *GithubClient) SetCommitStatus(ctx context.Context, org, repo, sha, state, statusContext, description, targetURL string) error {
+	if !contains(CommitStates, state) {
+		return fmt.Errorf("invalid commit state '%s': expected one of %s", state, strings.Join(CommitStates, ", "))
+	}
+
+	if runes := []rune(description); len(runes) > maxCommitStatusDescription {
+		description = string(runes[:maxCommitStatusDescription-1]) + "…"
+	}
+
+	status := &github.RepoStatus{
+		State:       github.String(state),
+		Context:     github.String(statusContext),
+		Description: github.String(description),
+	}
+
+	if targetURL != "" {
+		status.TargetURL = github.String(targetURL)
+	}
+
+	if _, _, err := c.client.Repositories.CreateStatus(ctx, org, repo, sha, status); err != nil {
+		return fmt.Errorf("could not set commit status '%s' of %s/%s@%s: %w", statusContext, org, repo, sha, err)
+	}
+
+	return nil
+}
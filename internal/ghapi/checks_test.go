@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSetCommitStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		state       string
+		description string
+		targetURL   string
+		want        string
+		wantErr     string
+	}{
+		{
+			name:        "pending",
+			state:       "pending",
+			description: "Evaluating",
+			want:        `POST /statuses/abc123 {"state":"pending","description":"Evaluating","context":"governance/mergable"}`,
+		},
+		{
+			name:        "success",
+			state:       "success",
+			description: "Ready to merge",
+			targetURL:   "https://example.com/runs/1",
+			want:        `POST /statuses/abc123 {"state":"success","target_url":"https://example.com/runs/1","description":"Ready to merge","context":"governance/mergable"}`,
+		},
+		{
+			name:        "failure",
+			state:       "failure",
+			description: "Missing approvals",
+			want:        `POST /statuses/abc123 {"state":"failure","description":"Missing approvals","context":"governance/mergable"}`,
+		},
+		{
+			name:        "error",
+			state:       "error",
+			description: "Could not evaluate",
+			want:        `POST /statuses/abc123 {"state":"error","description":"Could not evaluate","context":"governance/mergable"}`,
+		},
+		{
+			name:        "long description",
+			state:       "failure",
+			description: strings.Repeat("x", 200),
+			want:        `POST /statuses/abc123 {"state":"failure","description":"` + strings.Repeat("x", 139) + `…","context":"governance/mergable"}`,
+		},
+		{
+			name:    "invalid state",
+			state:   "neutral",
+			wantErr: "invalid commit state",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/unikraft/unikraft")
+				requests = append(requests, r.Method+" "+path+" "+strings.TrimSpace(string(body)))
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{}`))
+			})
+
+			client := newTestClient(t, handler)
+
+			err := client.SetCommitStatus(context.Background(), "unikraft", "unikraft", "abc123", tt.state, "governance/mergable", tt.description, tt.targetURL)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SetCommitStatus() error = %v, want %q", err, tt.wantErr)
+				}
+
+				if len(requests) > 0 {
+					t.Errorf("expected no request, got: %v", requests)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("SetCommitStatus() error = %v", err)
+			}
+
+			if len(requests) != 1 || requests[0] != tt.want {
+				t.Errorf("requests = %v, want [%s]", requests, tt.want)
+			}
+		})
+	}
+}
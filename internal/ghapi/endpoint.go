@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultBaseURL and defaultUploadURL are the URLs of the REST API of
+	// github.com.
+	defaultBaseURL   = "https://api.github.com/"
+	defaultUploadURL = "https://uploads.github.com/"
+)
+
+// EnterpriseURLs returns the base URL of the REST API and the URL for uploads
+// of the GitHub instance at the provided endpoint.  The endpoint may either be
+// the address of a GitHub Enterprise Server, e.g. "https://ghe.example.com",
+// or that of its API, e.g. "https://ghe.example.com/api/v3".  An endpoint of
+// github.com refers to its public API.
+func EnterpriseURLs(githubEndpoint string) (*url.URL, *url.URL, error) {
+	endpoint, err := url.Parse(strings.TrimSpace(githubEndpoint))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid GitHub endpoint '%s': %w", githubEndpoint, err)
+	}
+
+	if (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, nil, fmt.Errorf("invalid GitHub endpoint '%s': expected an absolute http(s) URL, e.g. https://ghe.example.com", githubEndpoint)
+	}
+
+	if host := strings.ToLower(endpoint.Hostname()); host == "github.com" || host == "api.github.com" {
+		base, _ := url.Parse(defaultBaseURL)
+		upload, _ := url.Parse(defaultUploadURL)
+		return base, upload, nil
+	}
+
+	root := strings.TrimRight(endpoint.Path, "/")
+	for _, suffix := range []string{"/api/v3", "/api/uploads", "/api"} {
+		if strings.HasSuffix(root, suffix) {
+			root = strings.TrimSuffix(root, suffix)
+			break
+		}
+	}
+
+	base := &url.URL{
+		Scheme: endpoint.Scheme,
+		User:   endpoint.User,
+		Host:   endpoint.Host,
+		Path:   root + "/api/v3/",
+	}
+
+	upload := *base
+	upload.Path = root + "/api/uploads/"
+
+	return base, &upload, nil
+}
+
+// CheckEndpoint verifies that the REST API of the GitHub instance at the
+// provided endpoint, or of github.com if none is provided, is reachable.  Any
+// response other than a server error or a missing API is accepted, since the
+// root of the API may require authentication.
+func CheckEndpoint(ctx context.Context, githubEndpoint string, skipSSL bool, timeout time.Duration) error {
+	base, _ := url.Parse(defaultBaseURL)
+	if githubEndpoint != "" {
+		var err error
+		if base, _, err = EnterpriseURLs(githubEndpoint); err != nil {
+			return err
+		}
+	}
+
+	client := &http.Client{
+		Transport: baseTransport(skipSSL),
+		Timeout:   timeout,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub API at %s is not reachable: %w", base.Redacted(), err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("no GitHub API found at %s: check the endpoint", base.Redacted())
+	} else if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("GitHub API at %s is not available: %s", base.Redacted(), resp.Status)
+	}
+
+	return nil
+}
+
+// baseTransport returns the transport which every request to the GitHub API
+// is ultimately performed with.
+func baseTransport(skipSSL bool) http.RoundTripper {
+	if !skipSSL {
+		return http.DefaultTransport
+	}
+
+	return &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+		},
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEnterpriseURLs(t *testing.T) {
+	tests := []struct {
+		name       string
+		endpoint   string
+		wantBase   string
+		wantUpload string
+		wantErr    bool
+	}{
+		{
+			name:       "server",
+			endpoint:   "https://ghe.example.com",
+			wantBase:   "https://ghe.example.com/api/v3/",
+			wantUpload: "https://ghe.example.com/api/uploads/",
+		},
+		{
+			name:       "server with trailing slash",
+			endpoint:   "https://ghe.example.com/",
+			wantBase:   "https://ghe.example.com/api/v3/",
+			wantUpload: "https://ghe.example.com/api/uploads/",
+		},
+		{
+			name:       "api",
+			endpoint:   "https://ghe.example.com/api/v3",
+			wantBase:   "https://ghe.example.com/api/v3/",
+			wantUpload: "https://ghe.example.com/api/uploads/",
+		},
+		{
+			name:       "api with trailing slash",
+			endpoint:   "https://ghe.example.com/api/v3/",
+			wantBase:   "https://ghe.example.com/api/v3/",
+			wantUpload: "https://ghe.example.com/api/uploads/",
+		},
+		{
+			name:       "uploads",
+			endpoint:   "https://ghe.example.com/api/uploads",
+			wantBase:   "https://ghe.example.com/api/v3/",
+			wantUpload: "https://ghe.example.com/api/uploads/",
+		},
+		{
+			name:       "server under a path with port",
+			endpoint:   "http://proxy.example.com:8080/github/api/v3",
+			wantBase:   "http://proxy.example.com:8080/github/api/v3/",
+			wantUpload: "http://proxy.example.com:8080/github/api/uploads/",
+		},
+		{
+			name:       "github.com",
+			endpoint:   "https://github.com",
+			wantBase:   "https://api.github.com/",
+			wantUpload: "https://uploads.github.com/",
+		},
+		{
+			name:     "no scheme",
+			endpoint: "ghe.example.com",
+			wantErr:  true,
+		},
+		{
+			name:     "unsupported scheme",
+			endpoint: "ftp://ghe.example.com",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, upload, err := EnterpriseURLs(tt.endpoint)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EnterpriseURLs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if base.String() != tt.wantBase {
+				t.Errorf("EnterpriseURLs() base = %s, want %s", base, tt.wantBase)
+			}
+
+			if upload.String() != tt.wantUpload {
+				t.Errorf("EnterpriseURLs() upload = %s, want %s", upload, tt.wantUpload)
+			}
+		})
+	}
+}
+
+func TestNewGithubClientEndpointForms(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number":1}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	for _, endpoint := range []string{srv.URL, srv.URL + "/api/v3", srv.URL + "/api/v3/"} {
+		t.Run(endpoint, func(t *testing.T) {
+			client, err := NewGithubClient(context.Background(), "token", false, endpoint)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := client.client.UploadURL.String(), srv.URL+"/api/uploads/"; got != want {
+				t.Errorf("upload URL = %s, want %s", got, want)
+			}
+
+			pull, err := client.GetPullRequest(context.Background(), "unikraft", "unikraft", 1)
+			if err != nil {
+				t.Fatalf("GetPullRequest() error = %v", err)
+			}
+
+			if pull.GetNumber() != 1 {
+				t.Errorf("GetPullRequest() = %d, want 1", pull.GetNumber())
+			}
+		})
+	}
+}
+
+func TestCheckEndpoint(t *testing.T) {
+	reachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(reachable.Close)
+
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(unavailable.Close)
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name     string
+		endpoint string
+		wantErr  bool
+	}{
+		{name: "server", endpoint: reachable.URL},
+		{name: "api", endpoint: reachable.URL + "/api/v3"},
+		{name: "wrong path", endpoint: reachable.URL + "/github", wantErr: true},
+		{name: "unavailable", endpoint: unavailable.URL, wantErr: true},
+		{name: "unreachable", endpoint: closed.URL, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckEndpoint(context.Background(), tt.endpoint, false, time.Second)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
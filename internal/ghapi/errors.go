@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// ErrTeamNotVisible is the classification of errors returned by team
+// endpoints which respond with either 403 or 404.  This typically happens when
+// the team is secret, lives in another organization or when the token lacks
+// the necessary scopes.
+var ErrTeamNotVisible = errors.New("team not visible to this token")
+
+// TeamNotVisibleError is returned when a team cannot be read with the current
+// token.  It matches ErrTeamNotVisible when used with errors.Is.
+type TeamNotVisibleError struct {
+	Org  string
+	Team string
+	Err  error
+}
+
+// Error implements error
+func (e *TeamNotVisibleError) Error() string {
+	return fmt.Sprintf("@%s/%s: %s (does the token have the 'read:org' scope and access to the organization?)",
+		e.Org,
+		e.Team,
+		ErrTeamNotVisible,
+	)
+}
+
+// Is implements errors.Is
+func (e *TeamNotVisibleError) Is(target error) bool {
+	return target == ErrTeamNotVisible
+}
+
+// Unwrap implements errors.Unwrap
+func (e *TeamNotVisibleError) Unwrap() error {
+	return e.Err
+}
+
+// teamError classifies an error returned from a team endpoint.  Responses with
+// 403 or 404 are wrapped as a TeamNotVisibleError, all other errors are
+// returned as-is.
+func teamError(org, team string, err error) error {
+	var ghErr *github.ErrorResponse
+	if !errors.As(err, &ghErr) || ghErr.Response == nil {
+		return err
+	}
+
+	switch ghErr.Response.StatusCode {
+	case http.StatusForbidden, http.StatusNotFound:
+		return &TeamNotVisibleError{
+			Org:  org,
+			Team: team,
+			Err:  err,
+		}
+	}
+
+	return err
+}
+
+// ErrReadOnly is the classification of errors returned when a request which
+// could modify state is attempted whilst the client is in read-only mode.
+var ErrReadOnly = errors.New("refusing to modify state in read-only mode")
+
+// ReadOnlyError is returned when a request was blocked by a read-only client.
+// It matches ErrReadOnly when used with errors.Is.
+type ReadOnlyError struct {
+	Method string
+	Path   string
+}
+
+// Error implements error
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("%s: blocked %s %s", ErrReadOnly, e.Method, e.Path)
+}
+
+// Is implements errors.Is
+func (e *ReadOnlyError) Is(target error) bool {
+	return target == ErrReadOnly
+}
+
+// ErrInsufficientRateLimit is the classification of errors returned when
+// fewer requests remain of the rate limit than an operation needs.
+var ErrInsufficientRateLimit = errors.New("insufficient rate limit")
+
+// InsufficientRateLimitError is returned by EnsureBudget when fewer requests
+// than needed remain until the rate limit is reset.  It matches
+// ErrInsufficientRateLimit when used with errors.Is.
+type InsufficientRateLimitError struct {
+	Needed    int
+	Remaining int
+	Reset     time.Time
+}
+
+// Error implements error
+func (e *InsufficientRateLimitError) Error() string {
+	return fmt.Sprintf("%s: %d requests needed but only %d remain until %s",
+		ErrInsufficientRateLimit,
+		e.Needed,
+		e.Remaining,
+		e.Reset.Format(time.RFC3339),
+	)
+}
+
+// Is implements errors.Is
+func (e *InsufficientRateLimitError) Is(target error) bool {
+	return target == ErrInsufficientRateLimit
+}
@@ -7,12 +7,13 @@ package ghapi
 
 import (
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/google/go-github/v63/github"
 	"golang.org/x/oauth2"
@@ -25,55 +26,129 @@ import (
 // actions against the REST API.
 type GithubClient struct {
 	client *github.Client
-}
 
-var (
-	userCache     map[string]*github.User
-	userTeamCache map[string][]string
-)
+	// app is set when the client authenticates as a GitHub App installation.
+	app bool
+
+	// mu guards the caches below such that the client can be used
+	// concurrently.
+	mu sync.RWMutex
+
+	// userCache holds each user which has been looked up, keyed by login.
+	userCache map[string]*github.User
+
+	// teamMembersCache holds the members of each team which has been listed,
+	// keyed by "org/team".
+	teamMembersCache map[string][]string
+
+	// orgReposCache holds the names of the repositories of each organisation
+	// which has been listed.
+	orgReposCache map[string][]string
+}
 
 // NewGitHubClient for creating a new instance of the client.
-func NewGithubClient(ctx context.Context, accessToken string, skipSSL bool, githubEndpoint string) (*GithubClient, error) {
-	if skipSSL {
-		insecureClient := &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: true,
-				},
-			},
+func NewGithubClient(ctx context.Context, accessToken string, skipSSL bool, githubEndpoint string, opts ...GithubClientOption) (*GithubClient, error) {
+	gopts := githubClientOptions{
+		timeout:       DefaultTimeout,
+		retryAttempts: DefaultRetryAttempts,
+		retryDelay:    DefaultRetryDelay,
+		apiVersion:    DefaultAPIVersion,
+	}
+	for _, opt := range opts {
+		opt(&gopts)
+	}
+
+	var inner http.RoundTripper = &apiVersionTransport{
+		base:    baseTransport(skipSSL),
+		version: gopts.apiVersion,
+	}
+
+	// Every attempt counts towards the quota, including retries, so API usage
+	// is recorded below the retries of transiently failed requests.
+	if usage, ok := ctx.Value(usageKey{}).(*APIUsage); ok && usage != nil {
+		inner = &usageTransport{base: inner, usage: usage}
+	}
+
+	var transport http.RoundTripper = &retryTransport{
+		base:        inner,
+		maxAttempts: gopts.retryAttempts,
+		baseDelay:   gopts.retryDelay,
+		timeout:     gopts.timeout,
+	}
+	base := transport
+
+	if gopts.readOnly {
+		transport = &readOnlyTransport{base: transport}
+	}
+
+	if gopts.app != nil {
+		// The exchange of the app's token is not subject to read-only mode as it
+		// does not modify any state of the organization.
+		exchange, err := newClient(&http.Client{
+			Transport: base,
+		}, githubEndpoint)
+		if err != nil {
+			return nil, err
 		}
 
-		ctx = context.WithValue(ctx, oauth2.HTTPClient, insecureClient)
+		accessToken, err = gopts.app.installationToken(ctx, exchange)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	var client *github.Client
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{
+		Transport: transport,
+	})
+
 	oauth2Client := oauth2.NewClient(ctx, oauth2.StaticTokenSource(
 		&oauth2.Token{
 			AccessToken: accessToken,
 		},
 	))
 
-	if githubEndpoint != "" {
-		endpoint, err := url.Parse(githubEndpoint)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse v3 endpoint: %s", err)
-		}
+	client, err := newClient(oauth2Client, githubEndpoint)
+	if err != nil {
+		return nil, err
+	}
 
-		client, err = github.NewEnterpriseClient(
-			endpoint.String(),
-			endpoint.String(),
-			oauth2Client,
-		)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		client = github.NewClient(oauth2Client)
+	return &GithubClient{
+		client:           client,
+		app:              gopts.app != nil,
+		userCache:        make(map[string]*github.User),
+		teamMembersCache: make(map[string][]string),
+		orgReposCache:    make(map[string][]string),
+	}, nil
+}
+
+// ClearCaches drops every cached user, team and repository such that they are
+// looked up again, e.g. by a long-running process which must observe changes.
+func (c *GithubClient) ClearCaches() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.userCache = make(map[string]*github.User)
+	c.teamMembersCache = make(map[string][]string)
+	c.orgReposCache = make(map[string][]string)
+}
+
+// newClient returns a GitHub client which uses the provided HTTP client and
+// talks to the provided endpoint, or to github.com if none is provided.
+func newClient(httpClient *http.Client, githubEndpoint string) (*github.Client, error) {
+	client := github.NewClient(httpClient)
+	if githubEndpoint == "" {
+		return client, nil
+	}
+
+	baseURL, uploadURL, err := EnterpriseURLs(githubEndpoint)
+	if err != nil {
+		return nil, err
 	}
 
-	userCache = make(map[string]*github.User)
+	client.BaseURL = baseURL
+	client.UploadURL = uploadURL
 
-	return &GithubClient{client}, nil
+	return client, nil
 }
 
 // FindTeam takes an organization name and team name and returns a detailed
@@ -84,7 +159,7 @@ func (c *GithubClient) FindTeam(ctx context.Context, org string, team string) (*
 	for {
 		teams, resp, err := c.client.Teams.ListTeams(ctx, org, opts)
 		if err != nil {
-			return nil, err
+			return nil, teamError(org, team, err)
 		}
 
 		for _, t := range teams {
@@ -106,24 +181,44 @@ func (c *GithubClient) FindTeam(ctx context.Context, org string, team string) (*
 // FindUser takes a Github username and returns a detaled object with
 // information about the user.
 func (c *GithubClient) FindUser(ctx context.Context, username string) (*github.User, error) {
-	if user, ok := userCache[username]; ok {
+	c.mu.RLock()
+	user, ok := c.userCache[username]
+	c.mu.RUnlock()
+
+	if ok {
 		return user, nil
 	}
 
 	user, _, err := c.client.Users.Get(ctx, username)
 	if err != nil {
-		return nil, fmt.Errorf("could not find user: %s: %s", username, err)
+		return nil, fmt.Errorf("could not find user: %s: %w", username, err)
 	}
 
-	userCache[username] = user
+	c.mu.Lock()
+	c.userCache[username] = user
+	c.mu.Unlock()
 
 	return user, nil
 }
 
-func (c *GithubClient) CreateOrUpdateTeam(ctx context.Context, org, name, description string, parentTeamID int64, privacy *string, maintainers, repos []string) (*github.Team, error) {
+// OptionalString returns a pointer to the string, or nil if it is empty, such
+// that an unset attribute is omitted from a request rather than cleared.
+func OptionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+// CreateOrUpdateTeam creates the team or, if it already exists, updates it.
+// The description and the privacy of the team are only set when provided,
+// such that the existing values of a team are preserved otherwise.
+func (c *GithubClient) CreateOrUpdateTeam(ctx context.Context, org, name string, description *string, parentTeamID int64, privacy *string, maintainers, repos []string) (*github.Team, error) {
 	newTeam := github.NewTeam{
 		Name:        name,
-		Description: &description,
+		Description: description,
+		Privacy:     privacy,
 		Maintainers: maintainers,
 		RepoNames:   repos,
 	}
@@ -132,16 +227,14 @@ func (c *GithubClient) CreateOrUpdateTeam(ctx context.Context, org, name, descri
 		newTeam.ParentTeamID = &parentTeamID
 	}
 
-	if privacy != nil {
-		newTeam.Privacy = privacy
-	}
-
 	var err error
 	var team *github.Team
 
 	// Check if the team already exists
 	_, err = c.FindTeam(ctx, org, name)
-	if err != nil {
+	if errors.Is(err, ErrTeamNotVisible) {
+		return nil, err
+	} else if err != nil {
 		team, _, err = c.client.Teams.CreateTeam(ctx, org, newTeam)
 	} else {
 		removeParent := false
@@ -175,17 +268,63 @@ func (c *GithubClient) ListOrgMembers(ctx context.Context, org, role string) ([]
 		},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("could not list org members: %s", err)
+		return nil, fmt.Errorf("could not list org members: %w", err)
 	}
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	for _, user := range users {
-		userCache[*user.Login] = user
+		c.userCache[*user.Login] = user
 		members = append(members, *user.Login)
 	}
 
 	return members, nil
 }
 
+// ListOrgRepos returns the names of every repository of the organisation.  The
+// repositories of each organisation are only listed once and cached for
+// subsequent calls.
+func (c *GithubClient) ListOrgRepos(ctx context.Context, org string) ([]string, error) {
+	key := strings.ToLower(org)
+	c.mu.RLock()
+	repos, ok := c.orgReposCache[key]
+	c.mu.RUnlock()
+
+	if ok {
+		return repos, nil
+	}
+
+	opts := &github.RepositoryListByOrgOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	for {
+		more, resp, err := c.client.Repositories.ListByOrg(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list repositories: %w", err)
+		}
+
+		for _, repo := range more {
+			repos = append(repos, repo.GetName())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	c.mu.Lock()
+	c.orgReposCache[key] = repos
+	c.mu.Unlock()
+
+	return repos, nil
+}
+
 func (c *GithubClient) SyncTeamMembers(ctx context.Context, org, team, role string, members []string) error {
 	var allCurrentUsernames []string
 	opts := github.ListOptions{}
@@ -201,7 +340,7 @@ func (c *GithubClient) SyncTeamMembers(ctx context.Context, org, team, role stri
 			},
 		)
 		if err != nil {
-			return err
+			return teamError(org, team, err)
 		}
 
 		for _, user := range more {
@@ -220,16 +359,14 @@ func (c *GithubClient) SyncTeamMembers(ctx context.Context, org, team, role stri
 	if len(usernamesToRemove) > 0 {
 		for _, user := range usernamesToRemove {
 			log.G(ctx).Infof("removing: %s...", user)
-			resp, err := c.client.Teams.RemoveTeamMembershipBySlug(
+			_, err := c.client.Teams.RemoveTeamMembershipBySlug(
 				ctx,
 				org,
 				team,
 				user,
 			)
 			if err != nil {
-				fmt.Printf("%#v\n\n", resp.Request)
-
-				return fmt.Errorf("could not remove user: %s: %s", user, err)
+				return fmt.Errorf("could not remove user: %s: %w", user, err)
 			}
 		}
 	}
@@ -252,7 +389,7 @@ func (c *GithubClient) SyncTeamMembers(ctx context.Context, org, team, role stri
 				},
 			)
 			if err != nil {
-				return fmt.Errorf("could not add user: %s: %s", user, err)
+				return fmt.Errorf("could not add user: %s: %w", user, err)
 			}
 		}
 	}
@@ -308,6 +445,37 @@ func (c *GithubClient) GetPullRequest(ctx context.Context, org, repo string, prI
 	return pull, nil
 }
 
+// ListPullRequestsWithCommit returns the list of pull requests which contain
+// the provided commit SHA, which can be used to determine how a commit landed
+// in a branch.
+func (c *GithubClient) ListPullRequestsWithCommit(ctx context.Context, org, repo, sha string) ([]*github.PullRequest, error) {
+	var pulls []*github.PullRequest
+	opts := &github.ListOptions{}
+
+	for {
+		more, resp, err := c.client.PullRequests.ListPullRequestsWithCommit(
+			ctx,
+			org,
+			repo,
+			sha,
+			opts,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		pulls = append(pulls, more...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return pulls, nil
+}
+
 // GetMaintainersOnPr retrieves a list of GitHub usernames attached as the
 // "assignee" (or maintainer) of a particular PR
 func (c *GithubClient) GetMaintainersOnPr(ctx context.Context, org, repo string, prId int) ([]string, error) {
@@ -325,17 +493,43 @@ func (c *GithubClient) GetMaintainersOnPr(ctx context.Context, org, repo string,
 	return maintainers, nil
 }
 
-// AddMaintainersToPr adds a list of GitHub usernames as "assignee" to a PR
-func (c *GithubClient) AddMaintainersToPr(ctx context.Context, org, repo string, prId int, maintainers []string) error {
-	_, _, err := c.client.Issues.AddAssignees(
-		ctx,
-		org,
-		repo,
-		prId,
-		maintainers,
-	)
+// AddMaintainersToPr adds a list of GitHub usernames as "assignee" to a PR.
+// Usernames which are already assigned are skipped and the remainder are sent
+// in batches which respect GitHub's limit of assignees per request.
+func (c *GithubClient) AddMaintainersToPr(ctx context.Context, org, repo string, prId int, maintainers []string) (*AssignmentResult, error) {
+	existing, err := c.GetMaintainersOnPr(ctx, org, repo, prId)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("could not get existing assignees: %w", err)
+	}
+
+	result, toAdd := newAssignmentResult(maintainers, existing)
+
+	var errs []error
+	for _, chunk := range chunkStrings(toAdd, MaxAssigneesPerRequest) {
+		_, _, err := c.client.Issues.AddAssignees(
+			ctx,
+			org,
+			repo,
+			prId,
+			chunk,
+		)
+		if err != nil {
+			result.fail(chunk, err)
+			errs = append(errs, fmt.Errorf("could not add assignees %v: %w", chunk, err))
+			continue
+		}
+
+		result.Added = append(result.Added, chunk...)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// RemoveMaintainersFromPr removes a list of GitHub usernames from the
+// "assignee" of a PR.
+func (c *GithubClient) RemoveMaintainersFromPr(ctx context.Context, org, repo string, prId int, maintainers []string) error {
+	if _, _, err := c.client.Issues.RemoveAssignees(ctx, org, repo, prId, maintainers); err != nil {
+		return fmt.Errorf("could not remove assignees %v: %w", maintainers, err)
 	}
 
 	return nil
@@ -387,21 +581,47 @@ func (c *GithubClient) GetReviewUsersOnPr(ctx context.Context, org, repo string,
 	return reviewers, err
 }
 
-// AddReviewersToPr adds a list of GitHub usernames as reviewers to a PR
-func (c *GithubClient) AddReviewersToPr(ctx context.Context, org, repo string, prId int, reviewers []string) error {
-	_, _, err := c.client.PullRequests.RequestReviewers(
-		ctx,
-		org,
-		repo,
-		prId,
-		github.ReviewersRequest{
-			// NodeID: ,
-			Reviewers: reviewers,
-		},
-	)
-
+// AddReviewersToPr adds a list of GitHub usernames as reviewers to a PR.
+// Usernames which have already been requested are skipped and the remainder
+// are sent in batches which respect GitHub's limit of reviewers per request.
+func (c *GithubClient) AddReviewersToPr(ctx context.Context, org, repo string, prId int, reviewers []string) (*AssignmentResult, error) {
+	existing, err := c.GetReviewersOnPr(ctx, org, repo, prId)
 	if err != nil {
-		return fmt.Errorf("could not add assignees to PR: %s", err)
+		return nil, fmt.Errorf("could not get existing reviewers: %w", err)
+	}
+
+	result, toAdd := newAssignmentResult(reviewers, existing)
+
+	var errs []error
+	for _, chunk := range chunkStrings(toAdd, MaxReviewersPerRequest) {
+		_, _, err := c.client.PullRequests.RequestReviewers(
+			ctx,
+			org,
+			repo,
+			prId,
+			github.ReviewersRequest{
+				Reviewers: chunk,
+			},
+		)
+		if err != nil {
+			result.fail(chunk, err)
+			errs = append(errs, fmt.Errorf("could not add reviewers %v to PR: %w", chunk, err))
+			continue
+		}
+
+		result.Added = append(result.Added, chunk...)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// RemoveReviewersFromPr withdraws the review requests of a list of GitHub
+// usernames from a PR.  Usernames which are not requested are ignored.
+func (c *GithubClient) RemoveReviewersFromPr(ctx context.Context, org, repo string, prId int, reviewers []string) error {
+	if _, err := c.client.PullRequests.RemoveReviewers(ctx, org, repo, prId, github.ReviewersRequest{
+		Reviewers: reviewers,
+	}); err != nil && !isNotFound(err) {
+		return fmt.Errorf("could not remove reviewers %v: %w", reviewers, err)
 	}
 
 	return nil
@@ -418,7 +638,7 @@ func (c *GithubClient) AddLabelsToPr(ctx context.Context, org, repo string, prId
 	)
 
 	if err != nil {
-		return fmt.Errorf("could not add labels to PR: %s", err)
+		return fmt.Errorf("could not add labels to PR: %w", err)
 	}
 
 	return nil
@@ -520,6 +740,75 @@ func (c *GithubClient) ListPullRequestReviews(ctx context.Context, org, repo str
 	return reviews, nil
 }
 
+// MaxPullRequestFiles is the maximum number of files GitHub returns when
+// listing the files of a pull request.
+const MaxPullRequestFiles = 3000
+
+// ListPullRequestFiles returns the files changed by a pull request.  GitHub
+// returns at most MaxPullRequestFiles files.
+func (c *GithubClient) ListPullRequestFiles(ctx context.Context, org, repo string, prId int) ([]*github.CommitFile, error) {
+	opts := &github.ListOptions{
+		PerPage: 100,
+	}
+	var files []*github.CommitFile
+
+	for {
+		more, resp, err := c.client.PullRequests.ListFiles(
+			ctx,
+			org,
+			repo,
+			prId,
+			opts,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, more...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return files, nil
+}
+
+// GetPullRequestCommits returns the list of commits of a pull request
+// including their messages and authors.  This is useful for lightweight checks
+// which do not require a full clone of the repository.
+func (c *GithubClient) GetPullRequestCommits(ctx context.Context, org, repo string, prId int) ([]*github.RepositoryCommit, error) {
+	opts := &github.ListOptions{
+		PerPage: 100,
+	}
+	var commits []*github.RepositoryCommit
+
+	for {
+		more, resp, err := c.client.PullRequests.ListCommits(
+			ctx,
+			org,
+			repo,
+			prId,
+			opts,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, more...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return commits, nil
+}
+
 // GetPulLRequestComment returns the specific comment given its unique Github ID
 func (c *GithubClient) GetPullRequestComment(ctx context.Context, org, repo string, commentID int64) (*github.IssueComment, error) {
 	comment, _, err := c.client.Issues.GetComment(
@@ -576,6 +865,47 @@ func (c *GithubClient) SetPullRequestState(ctx context.Context, org, repo string
 	return err
 }
 
+// MergeMethods are the methods which GitHub accepts for merging a pull
+// request.
+var MergeMethods = []string{
+	"merge",
+	"rebase",
+	"squash",
+}
+
+// UpdatePullRequestBase changes the branch which the pull request is to be
+// merged into.
+func (c *GithubClient) UpdatePullRequestBase(ctx context.Context, org, repo string, prID int, base string) error {
+	if _, _, err := c.client.PullRequests.Edit(ctx, org, repo, prID, &github.PullRequest{
+		Base: &github.PullRequestBranch{
+			Ref: &base,
+		},
+	}); err != nil {
+		return fmt.Errorf("could not change base of %s/%s#%d to %s: %w", org, repo, prID, base, err)
+	}
+
+	return nil
+}
+
+// MergePullRequest merges the pull request into its base branch with the
+// provided method, which is one of MergeMethods.
+func (c *GithubClient) MergePullRequest(ctx context.Context, org, repo string, prID int, method string) error {
+	if !contains(MergeMethods, method) {
+		return fmt.Errorf("invalid merge method '%s': expected one of %s", method, strings.Join(MergeMethods, ", "))
+	}
+
+	result, _, err := c.client.PullRequests.Merge(ctx, org, repo, prID, "", &github.PullRequestOptions{
+		MergeMethod: method,
+	})
+	if err != nil {
+		return fmt.Errorf("could not merge %s/%s#%d: %w", org, repo, prID, err)
+	} else if !result.GetMerged() {
+		return fmt.Errorf("could not merge %s/%s#%d: %s", org, repo, prID, result.GetMessage())
+	}
+
+	return nil
+}
+
 func (c *GithubClient) DeleteLastPullRequestComment(ctx context.Context, org, repo string, prID int) error {
 	comments, err := c.ListPullRequestComments(ctx, org, repo, prID)
 	if err != nil {
@@ -679,7 +1009,7 @@ func (c *GithubClient) CreatePullRequestComment(ctx context.Context, org, repo s
 func (c *GithubClient) ListTeamMembers(ctx context.Context, orgTeam string) ([]string, error) {
 	org, team, err := parseTeam(orgTeam)
 	if err != nil {
-		return nil, fmt.Errorf("could not find team: %s", err)
+		return nil, fmt.Errorf("could not find team: %w", err)
 	}
 
 	opts := github.ListOptions{}
@@ -695,7 +1025,7 @@ func (c *GithubClient) ListTeamMembers(ctx context.Context, orgTeam string) ([]s
 			},
 		)
 		if err != nil {
-			return nil, err
+			return nil, teamError(org, team, err)
 		}
 
 		members = append(members, more...)
@@ -715,32 +1045,46 @@ func (c *GithubClient) ListTeamMembers(ctx context.Context, orgTeam string) ([]s
 	return usernames, nil
 }
 
-func (c *GithubClient) UserMemberOfTeam(ctx context.Context, username, team string) (bool, error) {
-	if teams, ok := userTeamCache[username]; ok {
-		for _, t := range teams {
-			if team == t {
-				return true, nil
-			}
-		}
+// MembersOfTeam returns the logins of the members of the provided team, e.g.
+// "@unikraft/maintainers".  The members of each team are only listed once and
+// cached for subsequent calls.
+func (c *GithubClient) MembersOfTeam(ctx context.Context, orgTeam string) ([]string, error) {
+	org, team, err := parseTeam(orgTeam)
+	if err != nil {
+		return nil, fmt.Errorf("could not find team: %w", err)
+	}
 
-		return false, nil
+	key := strings.ToLower(org + "/" + team)
+	c.mu.RLock()
+	members, ok := c.teamMembersCache[key]
+	c.mu.RUnlock()
+
+	if ok {
+		return members, nil
 	}
 
-	members, err := c.ListTeamMembers(ctx, team)
+	members, err = c.ListTeamMembers(ctx, orgTeam)
 	if err != nil {
-		return false, nil
+		return nil, err
 	}
 
-	// Cache request
-	for _, member := range members {
-		userTeamCache[member] = append(userTeamCache[member], team)
+	c.mu.Lock()
+	c.teamMembersCache[key] = members
+	c.mu.Unlock()
+
+	return members, nil
+}
+
+// UserMemberOfTeam checks whether the user is a member of the provided team.
+func (c *GithubClient) UserMemberOfTeam(ctx context.Context, username, team string) (bool, error) {
+	members, err := c.MembersOfTeam(ctx, team)
+	if err != nil {
+		return false, err
 	}
 
-	if teams, ok := userTeamCache[username]; ok {
-		for _, t := range teams {
-			if team == t {
-				return true, nil
-			}
+	for _, member := range members {
+		if strings.EqualFold(member, username) {
+			return true, nil
 		}
 	}
 
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import "time"
+
+// DefaultTimeout is the maximum duration of a single request to the GitHub API
+// unless otherwise specified with WithTimeout.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultAPIVersion is the version of the GitHub REST API which is requested
+// unless otherwise specified with WithAPIVersion.
+const DefaultAPIVersion = "2022-11-28"
+
+// githubClientOptions are the optional settings which can be applied when
+// instantiating a new GithubClient.
+type githubClientOptions struct {
+	app           *githubApp
+	readOnly      bool
+	timeout       time.Duration
+	retryAttempts int
+	retryDelay    time.Duration
+	apiVersion    string
+}
+
+type GithubClientOption func(*githubClientOptions)
+
+// WithReadOnly rejects every request which could modify state on GitHub, i.e.
+// anything other than GET or HEAD, before it leaves the process.  This is
+// enforced at the transport level and is therefore independent of any dry-run
+// checks performed by the caller.
+func WithReadOnly(readOnly bool) GithubClientOption {
+	return func(opts *githubClientOptions) {
+		opts.readOnly = readOnly
+	}
+}
+
+// WithTimeout sets the maximum duration of every attempt of a request to the
+// GitHub API, including reading its response.  Waiting to retry a request,
+// e.g. until a rate limit resets, is not subject to it.  A timeout of zero
+// disables it.
+func WithTimeout(timeout time.Duration) GithubClientOption {
+	return func(opts *githubClientOptions) {
+		opts.timeout = timeout
+	}
+}
+
+// WithRetry sets how often a request which failed transiently, i.e. was rate
+// limited or failed with a server error, is attempted before giving up and the
+// delay before its first retry, which doubles with every further attempt.  A
+// delay requested by GitHub through Retry-After or the reset time of the rate
+// limit takes precedence.  A maximum of one attempt disables retries.
+func WithRetry(maxAttempts int, baseDelay time.Duration) GithubClientOption {
+	return func(opts *githubClientOptions) {
+		opts.retryAttempts = maxAttempts
+		opts.retryDelay = baseDelay
+	}
+}
+
+// WithAPIVersion sets the version of the GitHub REST API which every request
+// asks for, e.g. "2022-11-28", such that responses do not change unexpectedly
+// when GitHub releases a new version.  An empty version leaves DefaultAPIVersion
+// in use.
+func WithAPIVersion(version string) GithubClientOption {
+	return func(opts *githubClientOptions) {
+		if version != "" {
+			opts.apiVersion = version
+		}
+	}
+}
+
+// WithApp authenticates as the installation of a GitHub App instead of with
+// the provided access token.  The private key is the path to the PEM-encoded
+// key of the app.  An app ID of zero leaves the access token in use.
+func WithApp(appID, installationID int64, privateKey string) GithubClientOption {
+	return func(opts *githubClientOptions) {
+		if appID == 0 {
+			return
+		}
+
+		opts.app = &githubApp{
+			id:             appID,
+			installationID: installationID,
+			privateKey:     privateKey,
+		}
+	}
+}
@@ -0,0 +1,482 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newTestClient returns a GithubClient which is pointed at a fake GitHub API
+// server serving the provided handler.  Paths registered on the handler must
+// be prefixed with "/api/v3".
+func newTestClient(t *testing.T, handler http.Handler, opts ...GithubClientOption) *GithubClient {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := NewGithubClient(context.Background(), "token", false, srv.URL, opts...)
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	return client
+}
+
+func TestGetPullRequestCommitsPaginates(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/commits", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "", "1":
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, r.URL.Path))
+			fmt.Fprint(w, `[{"sha":"a","commit":{"message":"first"}},{"sha":"b","commit":{"message":"second"}}]`)
+		case "2":
+			fmt.Fprint(w, `[{"sha":"c","commit":{"message":"third"}}]`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	})
+
+	client := newTestClient(t, mux)
+
+	commits, err := client.GetPullRequestCommits(context.Background(), "unikraft", "unikraft", 1)
+	if err != nil {
+		t.Fatalf("GetPullRequestCommits() unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, commit := range commits {
+		got = append(got, commit.GetSHA()+":"+commit.GetCommit().GetMessage())
+	}
+
+	want := []string{"a:first", "b:second", "c:third"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("GetPullRequestCommits() = %v, want %v", got, want)
+	}
+}
+
+func TestUserMemberOfTeamNotVisible(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{name: "not found", status: http.StatusNotFound, wantErr: ErrTeamNotVisible},
+		{name: "forbidden", status: http.StatusForbidden, wantErr: ErrTeamNotVisible},
+		{name: "server error", status: http.StatusInternalServerError, wantErr: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/orgs/unikraft/teams/secret/members", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{"message":"error"}`)
+			})
+
+			client := newTestClient(t, mux)
+
+			_, err := client.UserMemberOfTeam(context.Background(), "jane", "@unikraft/secret")
+			if err == nil {
+				t.Fatalf("UserMemberOfTeam() expected error")
+			}
+
+			if got := errors.Is(err, ErrTeamNotVisible); got != (tt.wantErr != nil) {
+				t.Errorf("errors.Is(err, ErrTeamNotVisible) = %v, err: %v", got, err)
+			}
+
+			var notVisible *TeamNotVisibleError
+			if tt.wantErr != nil && (!errors.As(err, &notVisible) || notVisible.Team != "secret") {
+				t.Errorf("expected error to name the team, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestMembersOfTeamListedOnce(t *testing.T) {
+	var listed int
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/orgs/unikraft/teams/reviewers/members", func(w http.ResponseWriter, r *http.Request) {
+		listed++
+		fmt.Fprint(w, `[{"login":"jane"},{"login":"john"}]`)
+	})
+
+	client := newTestClient(t, mux)
+
+	for _, tt := range []struct {
+		user string
+		want bool
+	}{
+		{user: "jane", want: true},
+		{user: "John", want: true},
+		{user: "alice", want: false},
+		{user: "jane", want: true},
+	} {
+		got, err := client.UserMemberOfTeam(context.Background(), tt.user, "@unikraft/reviewers")
+		if err != nil {
+			t.Fatalf("UserMemberOfTeam(%s) error = %v", tt.user, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("UserMemberOfTeam(%s) = %v, want %v", tt.user, got, tt.want)
+		}
+	}
+
+	members, err := client.MembersOfTeam(context.Background(), "unikraft/reviewers")
+	if err != nil {
+		t.Fatalf("MembersOfTeam() error = %v", err)
+	}
+
+	if strings.Join(members, ",") != "jane,john" {
+		t.Errorf("MembersOfTeam() = %v, want [jane john]", members)
+	}
+
+	if listed != 1 {
+		t.Errorf("team listed %d times, want 1", listed)
+	}
+}
+
+func TestCachesConcurrentUse(t *testing.T) {
+	var mu sync.Mutex
+	var listed int
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/orgs/unikraft/teams/maintainers/members", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		listed++
+		mu.Unlock()
+
+		fmt.Fprint(w, `[{"login":"jane"}]`)
+	})
+	mux.HandleFunc("/api/v3/orgs/unikraft/members", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"login":"jane"},{"login":"john"}]`)
+	})
+	mux.HandleFunc("/api/v3/users/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"login":%q}`, strings.TrimPrefix(r.URL.Path, "/api/v3/users/"))
+	})
+
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			if _, err := client.UserMemberOfTeam(ctx, "jane", "@unikraft/maintainers"); err != nil {
+				t.Errorf("UserMemberOfTeam() unexpected error: %v", err)
+			}
+
+			if _, err := client.FindUser(ctx, fmt.Sprintf("user%d", i%4)); err != nil {
+				t.Errorf("FindUser() unexpected error: %v", err)
+			}
+
+			if _, err := client.ListOrgMembers(ctx, "unikraft", "all"); err != nil {
+				t.Errorf("ListOrgMembers() unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	client.ClearCaches()
+
+	before := listed
+	if _, err := client.MembersOfTeam(ctx, "@unikraft/maintainers"); err != nil {
+		t.Fatalf("MembersOfTeam() unexpected error: %v", err)
+	}
+
+	if listed != before+1 {
+		t.Errorf("team listed %d times after ClearCaches(), want %d", listed, before+1)
+	}
+
+	if _, err := client.MembersOfTeam(ctx, "@unikraft/maintainers"); err != nil {
+		t.Fatalf("MembersOfTeam() unexpected error: %v", err)
+	}
+
+	if listed != before+1 {
+		t.Errorf("team listed %d times, want it cached at %d", listed, before+1)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	var writes int
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writes++
+		}
+		fmt.Fprint(w, `{"number":1,"state":"open"}`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/labels", func(w http.ResponseWriter, r *http.Request) {
+		writes++
+		fmt.Fprint(w, `[]`)
+	})
+
+	client := newTestClient(t, mux, WithReadOnly(true))
+
+	if _, err := client.GetPullRequest(context.Background(), "unikraft", "unikraft", 1); err != nil {
+		t.Fatalf("GetPullRequest() unexpected error: %v", err)
+	}
+
+	err := client.AddLabelsToPr(context.Background(), "unikraft", "unikraft", 1, []string{"kind/bug"})
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("AddLabelsToPr() error = %v, want ErrReadOnly", err)
+	}
+
+	if !strings.Contains(err.Error(), "POST /api/v3/repos/unikraft/unikraft/issues/1/labels") {
+		t.Errorf("expected error to name the blocked call, got: %v", err)
+	}
+
+	if writes != 0 {
+		t.Errorf("expected no write requests to reach the server, got %d", writes)
+	}
+}
+
+func TestCreateOrUpdateTeamDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		exists      bool
+		description *string
+		privacy     *string
+		want        map[string]string
+	}{
+		{
+			name:   "update preserves description and privacy",
+			exists: true,
+		},
+		{
+			name:        "update with description",
+			exists:      true,
+			description: OptionalString("Architecture SIG"),
+			privacy:     OptionalString("closed"),
+			want:        map[string]string{"description": "Architecture SIG", "privacy": "closed"},
+		},
+		{
+			name:        "update with empty description",
+			exists:      true,
+			description: OptionalString(""),
+		},
+		{
+			name:        "create with description",
+			description: OptionalString("Architecture SIG"),
+			want:        map[string]string{"description": "Architecture SIG"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var method string
+			var body map[string]interface{}
+
+			record := func(w http.ResponseWriter, r *http.Request) {
+				method = r.Method
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("could not decode request: %v", err)
+				}
+
+				fmt.Fprint(w, `{"id":1,"name":"sig-arch","slug":"sig-arch"}`)
+			}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/orgs/unikraft/teams", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					record(w, r)
+					return
+				}
+
+				if tt.exists {
+					fmt.Fprint(w, `[{"id":1,"name":"sig-arch","slug":"sig-arch"}]`)
+				} else {
+					fmt.Fprint(w, `[]`)
+				}
+			})
+			mux.HandleFunc("/api/v3/orgs/unikraft/teams/sig-arch", record)
+
+			client := newTestClient(t, mux)
+
+			if _, err := client.CreateOrUpdateTeam(context.Background(), "unikraft", "sig-arch", tt.description, -1, tt.privacy, nil, nil); err != nil {
+				t.Fatalf("CreateOrUpdateTeam() unexpected error: %v", err)
+			}
+
+			wantMethod := http.MethodPost
+			if tt.exists {
+				wantMethod = http.MethodPatch
+			}
+
+			if method != wantMethod {
+				t.Errorf("method = %s, want %s", method, wantMethod)
+			}
+
+			for _, key := range []string{"description", "privacy"} {
+				got, sent := body[key]
+				want, ok := tt.want[key]
+				if !ok && sent {
+					t.Errorf("%s = %v, want it to be omitted", key, got)
+				} else if ok && got != want {
+					t.Errorf("%s = %v, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdatePullRequestBase(t *testing.T) {
+	var body map[string]interface{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/42", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("method = %s, want %s", r.Method, http.MethodPatch)
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("could not decode request: %v", err)
+		}
+
+		fmt.Fprint(w, `{"number":42}`)
+	})
+
+	client := newTestClient(t, mux)
+
+	if err := client.UpdatePullRequestBase(context.Background(), "unikraft", "unikraft", 42, "staging-42"); err != nil {
+		t.Fatalf("UpdatePullRequestBase() unexpected error: %v", err)
+	}
+
+	if body["base"] != "staging-42" {
+		t.Errorf("base = %v, want staging-42", body["base"])
+	}
+}
+
+func TestMergePullRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		status   int
+		response string
+		wantErr  string
+	}{
+		{
+			name:     "rebase",
+			method:   "rebase",
+			status:   http.StatusOK,
+			response: `{"merged":true,"message":"Pull Request successfully merged"}`,
+		},
+		{
+			name:     "not merged",
+			method:   "rebase",
+			status:   http.StatusOK,
+			response: `{"merged":false,"message":"Base branch was modified"}`,
+			wantErr:  "Base branch was modified",
+		},
+		{
+			name:     "not mergeable",
+			method:   "rebase",
+			status:   http.StatusMethodNotAllowed,
+			response: `{"message":"Pull Request is not mergeable"}`,
+			wantErr:  "could not merge unikraft/unikraft#42",
+		},
+		{
+			name:    "invalid method",
+			method:  "fast-forward",
+			wantErr: "invalid merge method",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var method string
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/42/merge", func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("could not decode request: %v", err)
+				}
+
+				method, _ = body["merge_method"].(string)
+
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.response)
+			})
+
+			client := newTestClient(t, mux)
+
+			err := client.MergePullRequest(context.Background(), "unikraft", "unikraft", 42, tt.method)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("MergePullRequest() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("MergePullRequest() unexpected error: %v", err)
+			}
+
+			if method != tt.method {
+				t.Errorf("merge_method = %q, want %q", method, tt.method)
+			}
+		})
+	}
+}
+
+func TestRemoveReviewersFromPr(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:   "removed",
+			status: http.StatusOK,
+		},
+		{
+			name:   "not requested",
+			status: http.StatusNotFound,
+		},
+		{
+			name:    "forbidden",
+			status:  http.StatusForbidden,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string][]string
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/42/requested_reviewers", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					t.Errorf("method = %s, want %s", r.Method, http.MethodDelete)
+				}
+
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("could not decode request: %v", err)
+				}
+
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{}`)
+			})
+
+			client := newTestClient(t, mux)
+
+			err := client.RemoveReviewersFromPr(context.Background(), "unikraft", "unikraft", 42, []string{"bob"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RemoveReviewersFromPr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if strings.Join(body["reviewers"], ",") != "bob" {
+				t.Errorf("reviewers = %v, want [bob]", body["reviewers"])
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// CloseReasons are the reasons which GitHub accepts for closing an issue.
+var CloseReasons = []string{
+	"completed",
+	"not_planned",
+}
+
+// CloseIssue closes the issue with the provided reason, which is one of
+// CloseReasons or empty, in which case GitHub defaults to "completed".  If a
+// comment is provided, it is posted before the issue is closed.
+func (c *GithubClient) CloseIssue(ctx context.Context, org, repo string, number int, reason, comment string) error {
+	if reason != "" && !contains(CloseReasons, reason) {
+		return fmt.Errorf("invalid close reason '%s': expected one of %s", reason, strings.Join(CloseReasons, ", "))
+	}
+
+	if comment != "" {
+		if _, _, err := c.client.Issues.CreateComment(ctx, org, repo, number, &github.IssueComment{
+			Body: &comment,
+		}); err != nil {
+			return fmt.Errorf("could not comment on %s/%s#%d: %w", org, repo, number, err)
+		}
+	}
+
+	req := &github.IssueRequest{
+		State: github.String("closed"),
+	}
+	if reason != "" {
+		req.StateReason = &reason
+	}
+
+	if _, _, err := c.client.Issues.Edit(ctx, org, repo, number, req); err != nil {
+		return fmt.Errorf("could not close %s/%s#%d: %w", org, repo, number, err)
+	}
+
+	return nil
+}
+
+// CloseIssueWithComment posts the comment on the issue and closes it as
+// completed.
+func (c *GithubClient) CloseIssueWithComment(ctx context.Context, org, repo string, number int, comment string) error {
+	return c.CloseIssue(ctx, org, repo, number, "completed", comment)
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCloseIssue(t *testing.T) {
+	tests := []struct {
+		name    string
+		reason  string
+		comment string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "with reason",
+			reason: "not_planned",
+			want: []string{
+				`PATCH /issues/7 {"state":"closed","state_reason":"not_planned"}`,
+			},
+		},
+		{
+			name: "without reason",
+			want: []string{
+				`PATCH /issues/7 {"state":"closed"}`,
+			},
+		},
+		{
+			name:    "with comment",
+			reason:  "completed",
+			comment: "Fixed in #8.",
+			want: []string{
+				`POST /issues/7/comments {"body":"Fixed in #8."}`,
+				`PATCH /issues/7 {"state":"closed","state_reason":"completed"}`,
+			},
+		},
+		{
+			name:    "invalid reason",
+			reason:  "duplicate",
+			wantErr: "invalid close reason",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/unikraft/unikraft")
+				requests = append(requests, r.Method+" "+path+" "+strings.TrimSpace(string(body)))
+				w.Write([]byte(`{}`))
+			})
+
+			client := newTestClient(t, handler)
+
+			err := client.CloseIssue(context.Background(), "unikraft", "unikraft", 7, tt.reason, tt.comment)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CloseIssue() error = %v, want %q", err, tt.wantErr)
+				}
+
+				if len(requests) > 0 {
+					t.Errorf("expected no request, got: %v", requests)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CloseIssue() error = %v", err)
+			}
+
+			if strings.Join(requests, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("requests = %q, want %q", requests, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloseIssueWithComment(t *testing.T) {
+	var requests []string
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/unikraft/unikraft")
+		requests = append(requests, r.Method+" "+path+" "+strings.TrimSpace(string(body)))
+		w.Write([]byte(`{}`))
+	})
+
+	client := newTestClient(t, handler)
+
+	if err := client.CloseIssueWithComment(context.Background(), "unikraft", "unikraft", 7, "Closed by #42."); err != nil {
+		t.Fatalf("CloseIssueWithComment() error = %v", err)
+	}
+
+	want := []string{
+		`POST /issues/7/comments {"body":"Closed by #42."}`,
+		`PATCH /issues/7 {"state":"closed","state_reason":"completed"}`,
+	}
+
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// GetRepoLabel returns the label with the provided name in the repository or
+// nil if the repository has no such label.
+func (c *GithubClient) GetRepoLabel(ctx context.Context, org, repo, name string) (*github.Label, error) {
+	label, _, err := c.client.Issues.GetLabel(ctx, org, repo, name)
+	if err != nil {
+		var ghErr *github.ErrorResponse
+		if errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return label, nil
+}
+
+// EditRepoLabel updates the label with the provided name in the repository,
+// e.g. to rename it or to change its color or description.  Issues and pull
+// requests which carry the label keep it under its new name.
+func (c *GithubClient) EditRepoLabel(ctx context.Context, org, repo, name string, label *github.Label) error {
+	_, _, err := c.client.Issues.EditLabel(ctx, org, repo, name, label)
+	return err
+}
+
+// DeleteRepoLabel deletes the label with the provided name from the
+// repository and from every issue and pull request which carries it.
+func (c *GithubClient) DeleteRepoLabel(ctx context.Context, org, repo, name string) error {
+	_, err := c.client.Issues.DeleteLabel(ctx, org, repo, name)
+	return err
+}
+
+// ListOpenIssuesWithLabel returns the numbers of all open issues and pull
+// requests in the repository which carry the provided label.
+func (c *GithubClient) ListOpenIssuesWithLabel(ctx context.Context, org, repo, label string) ([]int, error) {
+	var numbers []int
+	opts := &github.IssueListByRepoOptions{
+		State:  "open",
+		Labels: []string{label},
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	for {
+		issues, resp, err := c.client.Issues.ListByRepo(ctx, org, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			numbers = append(numbers, issue.GetNumber())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return numbers, nil
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// LockReasons are the reasons which GitHub accepts for locking the
+// conversation of an issue or pull request.
+var LockReasons = []string{
+	"off-topic",
+	"too heated",
+	"resolved",
+	"spam",
+}
+
+// LockConversation locks the conversation of the issue or pull request such
+// that only collaborators can comment on it.  The reason is one of
+// LockReasons or empty, in which case none is shown.
+func (c *GithubClient) LockConversation(ctx context.Context, org, repo string, number int, reason string) error {
+	if reason != "" && !contains(LockReasons, reason) {
+		return fmt.Errorf("invalid lock reason '%s': expected one of %s", reason, strings.Join(LockReasons, ", "))
+	}
+
+	if _, err := c.client.Issues.Lock(ctx, org, repo, number, &github.LockIssueOptions{
+		LockReason: reason,
+	}); err != nil {
+		return fmt.Errorf("could not lock conversation of %s/%s#%d: %w", org, repo, number, err)
+	}
+
+	return nil
+}
+
+// UnlockConversation unlocks the conversation of the issue or pull request.
+func (c *GithubClient) UnlockConversation(ctx context.Context, org, repo string, number int) error {
+	if _, err := c.client.Issues.Unlock(ctx, org, repo, number); err != nil {
+		return fmt.Errorf("could not unlock conversation of %s/%s#%d: %w", org, repo, number, err)
+	}
+
+	return nil
+}
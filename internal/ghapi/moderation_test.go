@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLockConversation(t *testing.T) {
+	tests := []struct {
+		name     string
+		reason   string
+		wantBody string
+		wantErr  string
+	}{
+		{
+			name:     "with reason",
+			reason:   "too heated",
+			wantBody: `{"lock_reason":"too heated"}`,
+		},
+		{
+			name:     "without reason",
+			wantBody: `{}`,
+		},
+		{
+			name:    "invalid reason",
+			reason:  "boring",
+			wantErr: "invalid lock reason",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/lock", func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests = append(requests, r.Method+" "+strings.TrimSpace(string(body)))
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			ctx := context.Background()
+			client := newTestClient(t, mux)
+
+			err := client.LockConversation(ctx, "unikraft", "unikraft", 1, tt.reason)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LockConversation() error = %v, want %q", err, tt.wantErr)
+				}
+
+				if len(requests) > 0 {
+					t.Errorf("expected no request, got: %v", requests)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LockConversation() error = %v", err)
+			}
+
+			if err := client.UnlockConversation(ctx, "unikraft", "unikraft", 1); err != nil {
+				t.Fatalf("UnlockConversation() error = %v", err)
+			}
+
+			want := []string{"PUT " + tt.wantBody, "DELETE "}
+			if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+				t.Errorf("requests = %q, want %q", requests, want)
+			}
+		})
+	}
+}
+
+func TestLockConversationReadOnly(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/lock", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s in read-only mode", r.Method)
+	})
+
+	client := newTestClient(t, mux, WithReadOnly(true))
+
+	if err := client.LockConversation(context.Background(), "unikraft", "unikraft", 1, "spam"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("LockConversation() error = %v, want ErrReadOnly", err)
+	}
+
+	if err := client.UnlockConversation(context.Background(), "unikraft", "unikraft", 1); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("UnlockConversation() error = %v, want ErrReadOnly", err)
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v63/github"
+	"kraftkit.sh/log"
+)
+
+// RateLimit returns the current rate limits of the token.  Querying the rate
+// limits does not count towards them.
+func (c *GithubClient) RateLimit(ctx context.Context) (*github.RateLimits, error) {
+	limits, _, err := c.client.RateLimit.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get rate limit: %w", err)
+	}
+
+	return limits, nil
+}
+
+// EnsureBudget checks that at least the provided number of core requests
+// remain of the rate limit, such that an operation does not fail partway
+// through.  If fewer remain, an InsufficientRateLimitError is returned which
+// includes when the rate limit is reset.  GitHub Enterprise instances without
+// rate limiting respond with 404, in which case any budget is available.
+func (c *GithubClient) EnsureBudget(ctx context.Context, needed int) error {
+	limits, err := c.RateLimit(ctx)
+	if isNotFound(err) {
+		log.G(ctx).Debug("rate limiting is not enabled")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	core := limits.GetCore()
+	if core == nil {
+		return nil
+	}
+
+	log.G(ctx).
+		WithField("needed", needed).
+		WithField("remaining", core.Remaining).
+		WithField("reset", core.Reset.Time).
+		Debug("checking rate limit budget")
+
+	if core.Remaining < needed {
+		return &InsufficientRateLimitError{
+			Needed:    needed,
+			Remaining: core.Remaining,
+			Reset:     core.Reset.Time,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnsureBudget(t *testing.T) {
+	reset := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		status    int
+		remaining int
+		needed    int
+		wantErr   error
+		wantInErr string
+	}{
+		{
+			name:      "enough remaining",
+			status:    http.StatusOK,
+			remaining: 100,
+			needed:    100,
+		},
+		{
+			name:      "too few remaining",
+			status:    http.StatusOK,
+			remaining: 10,
+			needed:    100,
+			wantErr:   ErrInsufficientRateLimit,
+			wantInErr: "100 requests needed but only 10 remain until 2024-05-01T12:00:00Z",
+		},
+		{
+			name:   "rate limiting disabled",
+			status: http.StatusNotFound,
+			needed: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v3/rate_limit" {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+
+				w.WriteHeader(tt.status)
+				if tt.status == http.StatusOK {
+					fmt.Fprintf(w, `{"resources":{"core":{"limit":5000,"remaining":%d,"reset":%d}}}`, tt.remaining, reset.Unix())
+				} else {
+					fmt.Fprint(w, `{"message":"Rate limiting is not enabled."}`)
+				}
+			})
+
+			err := newTestClient(t, handler).EnsureBudget(context.Background(), tt.needed)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("EnsureBudget() unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("EnsureBudget() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !strings.Contains(err.Error(), tt.wantInErr) {
+				t.Errorf("EnsureBudget() error = %v, want it to contain %q", err, tt.wantInErr)
+			}
+
+			var rerr *InsufficientRateLimitError
+			if !errors.As(err, &rerr) || !rerr.Reset.Equal(reset) {
+				t.Errorf("EnsureBudget() error = %#v, want reset at %s", err, reset)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// stateCommentMarker starts the hidden block of the comment on a pull request
+// which records the keys of the actions which have been applied to it.
+const stateCommentMarker = "<!-- governctl-state"
+
+// commentMarkerRegex matches the hidden marker which identifies a comment
+// left by an action, as rendered by CommentMarker.
+var commentMarkerRegex = regexp.MustCompile(`<!-- governctl:([a-z0-9:-]+) -->`)
+
+// ActionKey returns a deterministic key for a mutating action of the provided
+// type, e.g. "label", on the provided target, e.g. "unikraft/unikraft#1",
+// which is further identified by its content, e.g. the name of the label.
+func ActionKey(action, target string, content ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(append([]string{target}, content...), "\x00")))
+	return action + ":" + hex.EncodeToString(sum[:8])
+}
+
+// CommentMarker returns the hidden marker which identifies a comment as
+// having been left by the action with the provided key.
+func CommentMarker(key string) string {
+	return fmt.Sprintf("<!-- governctl:%s -->", key)
+}
+
+// ActionState records which actions have already been applied to a pull
+// request such that re-delivered events and re-runs of a workflow do not
+// apply them again, e.g. re-add a label which has since been removed by
+// hand.  Comments are recognised by their marker, all other actions are
+// recorded in a single hidden comment.  A nil ActionState never reports an
+// action as applied and does not record anything.
+type ActionState struct {
+	client    *GithubClient
+	org       string
+	repo      string
+	prID      int
+	force     bool
+	commentID int64
+	applied   map[string]bool
+	recorded  []string
+	pending   []string
+}
+
+// LoadActionState reads the actions which have been applied to the pull
+// request from the comments left on it by the provided user.  An empty user
+// accepts the comments of anyone.  With force, all actions are reported as not
+// applied such that they are re-applied, yet they are still recorded.
+func (c *GithubClient) LoadActionState(ctx context.Context, org, repo string, prID int, user string, force bool) (*ActionState, error) {
+	comments, err := c.ListPullRequestComments(ctx, org, repo, prID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list comments: %w", err)
+	}
+
+	state := &ActionState{
+		client:  c,
+		org:     org,
+		repo:    repo,
+		prID:    prID,
+		force:   force,
+		applied: make(map[string]bool),
+	}
+
+	for _, comment := range comments {
+		if user != "" && comment.GetUser().GetLogin() != user {
+			continue
+		}
+
+		body := comment.GetBody()
+
+		if strings.HasPrefix(body, stateCommentMarker) && state.commentID == 0 {
+			state.commentID = comment.GetID()
+			state.parse(body)
+		}
+
+		for _, m := range commentMarkerRegex.FindAllStringSubmatch(body, -1) {
+			state.applied[m[1]] = true
+		}
+	}
+
+	return state, nil
+}
+
+// parse reads the keys listed in the hidden block of the state comment.
+func (s *ActionState) parse(body string) {
+	block, _, _ := strings.Cut(strings.TrimPrefix(body, stateCommentMarker), "-->")
+
+	for _, line := range strings.Split(block, "\n") {
+		if key := strings.TrimSpace(line); key != "" {
+			s.applied[key] = true
+			s.recorded = append(s.recorded, key)
+		}
+	}
+}
+
+// Applied returns whether the action with the provided key has already been
+// applied and should therefore be skipped.
+func (s *ActionState) Applied(key string) bool {
+	if s == nil || s.force {
+		return false
+	}
+
+	return s.applied[key]
+}
+
+// Record marks the actions with the provided keys as applied.  They are only
+// persisted once Save is called.
+func (s *ActionState) Record(keys ...string) {
+	if s == nil {
+		return
+	}
+
+	for _, key := range keys {
+		s.applied[key] = true
+
+		if !contains(s.recorded, key) && !contains(s.pending, key) {
+			s.pending = append(s.pending, key)
+		}
+	}
+}
+
+// CreateComment leaves a comment on the pull request which is marked as having
+// been left by the action with the provided key.
+func (s *ActionState) CreateComment(ctx context.Context, key, body string) error {
+	if err := s.client.CreatePullRequestComment(ctx, s.org, s.repo, s.prID, CommentMarker(key)+"\n"+body); err != nil {
+		return err
+	}
+
+	s.applied[key] = true
+
+	return nil
+}
+
+// Save persists the actions which have been recorded since the state was
+// loaded by creating or updating the state comment.  Nothing is written if no
+// action has been recorded.
+func (s *ActionState) Save(ctx context.Context) error {
+	if s == nil || len(s.pending) == 0 {
+		return nil
+	}
+
+	body := renderStateComment(append(s.recorded, s.pending...))
+
+	if s.commentID == 0 {
+		comment, _, err := s.client.client.Issues.CreateComment(ctx, s.org, s.repo, s.prID, &github.IssueComment{
+			Body: &body,
+		})
+		if err != nil {
+			return fmt.Errorf("could not create state comment: %w", err)
+		}
+
+		s.commentID = comment.GetID()
+	} else if _, _, err := s.client.client.Issues.EditComment(ctx, s.org, s.repo, s.commentID, &github.IssueComment{
+		Body: &body,
+	}); err != nil {
+		return fmt.Errorf("could not update state comment: %w", err)
+	}
+
+	s.recorded = append(s.recorded, s.pending...)
+	s.pending = nil
+
+	return nil
+}
+
+// renderStateComment returns the body of the state comment which lists the
+// provided keys in a hidden block.
+func renderStateComment(keys []string) string {
+	var b strings.Builder
+
+	b.WriteString(stateCommentMarker)
+	b.WriteString("\n")
+	for _, key := range keys {
+		b.WriteString(key)
+		b.WriteString("\n")
+	}
+	b.WriteString("-->\n")
+	b.WriteString("_This comment records the actions taken by governctl on this pull request such that they are not repeated._\n")
+
+	return b.String()
+}
+
+// contains returns whether the list contains the entry.
+func contains(list []string, entry string) bool {
+	for _, e := range list {
+		if e == entry {
+			return true
+		}
+	}
+
+	return false
+}
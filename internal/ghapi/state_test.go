@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestActionKey(t *testing.T) {
+	key := ActionKey("label", "unikraft/unikraft#1", "area/boot")
+
+	if key != ActionKey("label", "unikraft/unikraft#1", "area/boot") {
+		t.Errorf("ActionKey() is not deterministic")
+	}
+
+	if !strings.HasPrefix(key, "label:") {
+		t.Errorf("ActionKey() = %s, want the action as prefix", key)
+	}
+
+	for _, other := range []string{
+		ActionKey("unlabel", "unikraft/unikraft#1", "area/boot"),
+		ActionKey("label", "unikraft/unikraft#2", "area/boot"),
+		ActionKey("label", "unikraft/unikraft#1", "area/plat"),
+	} {
+		if other == key {
+			t.Errorf("ActionKey() = %s for a different action", other)
+		}
+	}
+}
+
+func TestActionState(t *testing.T) {
+	var writes []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected %s of a new state comment", r.Method)
+			return
+		}
+
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"id": 1, "user": map[string]string{"login": "someone"}, "body": "<!-- governctl-state\nlabel:1\n-->"},
+			{"id": 2, "user": map[string]string{"login": "unikraft-bot"}, "body": "<!-- governctl:welcome -->\nWelcome!"},
+			{"id": 3, "user": map[string]string{"login": "unikraft-bot"}, "body": "<!-- governctl-state\nlabel:2\nassign:3\n-->\ntext"},
+		})
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/comments/3", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		writes = append(writes, r.Method+" "+string(body))
+		w.Write(body)
+	})
+
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	state, err := client.LoadActionState(ctx, "unikraft", "unikraft", 1, "unikraft-bot", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]bool{
+		"welcome":  true,
+		"label:1":  false,
+		"label:2":  true,
+		"assign:3": true,
+		"label:4":  false,
+	} {
+		if got := state.Applied(key); got != want {
+			t.Errorf("Applied(%s) = %v, want %v", key, got, want)
+		}
+	}
+
+	// Nothing has been recorded, so nothing is written.
+	if err := state.Save(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	state.Record("label:2", "label:4")
+
+	if !state.Applied("label:4") {
+		t.Errorf("expected a recorded action to be applied")
+	}
+
+	if err := state.Save(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(writes) != 1 || !strings.HasPrefix(writes[0], "PATCH ") {
+		t.Fatalf("expected the state comment to be updated once, got: %v", writes)
+	}
+
+	var comment struct {
+		Body string `json:"body"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(writes[0], "PATCH ")), &comment); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(comment.Body, "<!-- governctl-state\nlabel:2\nassign:3\nlabel:4\n-->\n") {
+		t.Errorf("unexpected state comment:\n%s", comment.Body)
+	}
+
+	forced, err := client.LoadActionState(ctx, "unikraft", "unikraft", 1, "unikraft-bot", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if forced.Applied("label:2") {
+		t.Errorf("expected no action to be applied with force")
+	}
+
+	var none *ActionState
+	none.Record("label:5")
+	if none.Applied("label:5") || none.Save(ctx) != nil {
+		t.Errorf("expected a nil state to neither apply nor save anything")
+	}
+}
@@ -0,0 +1,404 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/google/go-github/v63/github"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/utils"
+)
+
+// TeamMembershipChange is the set of changes necessary to bring the
+// membership of a team in line with the desired list of members.
+type TeamMembershipChange struct {
+	Org  string `json:"org"`
+	Team string `json:"team"`
+
+	// Add are the desired members which are neither members of the team nor
+	// have a pending invitation to it.
+	Add []string `json:"add,omitempty"`
+
+	// Remove are the current members of the team which are not desired.
+	Remove []string `json:"remove,omitempty"`
+
+	// Pending are the desired members which have already been invited to the
+	// team but have yet to accept.
+	Pending []string `json:"pending,omitempty"`
+}
+
+// Empty returns whether the membership of the team is already as desired.
+func (c *TeamMembershipChange) Empty() bool {
+	return len(c.Add) == 0 && len(c.Remove) == 0
+}
+
+// PlanTeamMembers determines the changes necessary to bring the membership of
+// the team in line with the provided list of members without performing them.
+// A team which does not exist yet is treated as having no members.
+func (c *GithubClient) PlanTeamMembers(ctx context.Context, org, team string, members []string) (*TeamMembershipChange, error) {
+	change := &TeamMembershipChange{
+		Org:  org,
+		Team: team,
+	}
+
+	if _, _, err := c.client.Teams.GetTeamBySlug(ctx, org, team); err != nil {
+		var ghErr *github.ErrorResponse
+		if errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound {
+			change.Add = sortedUnique(members)
+			return change, nil
+		}
+
+		return nil, teamError(org, team, err)
+	}
+
+	var current []string
+	opts := github.ListOptions{}
+
+	for {
+		more, resp, err := c.client.Teams.ListTeamMembersBySlug(
+			ctx,
+			org,
+			team,
+			&github.TeamListTeamMembersOptions{
+				ListOptions: opts,
+			},
+		)
+		if err != nil {
+			return nil, teamError(org, team, err)
+		}
+
+		for _, user := range more {
+			current = append(current, user.GetLogin())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	var invited []string
+	opts = github.ListOptions{}
+
+	for {
+		more, resp, err := c.client.Teams.ListPendingTeamInvitationsBySlug(
+			ctx,
+			org,
+			team,
+			&opts,
+		)
+		if err != nil {
+			return nil, teamError(org, team, err)
+		}
+
+		for _, invitation := range more {
+			invited = append(invited, invitation.GetLogin())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	members = sortedUnique(members)
+
+	change.Remove = sortedUnique(utils.Difference(current, members))
+	change.Pending = sortedUnique(utils.Intersect(utils.Difference(members, current), invited))
+	change.Add = utils.Difference(utils.Difference(members, current), invited)
+
+	return change, nil
+}
+
+// ApplyTeamMembers performs the changes to the membership of the team,
+// adding new members with the provided role.
+func (c *GithubClient) ApplyTeamMembers(ctx context.Context, change *TeamMembershipChange, role string) error {
+	for _, user := range change.Remove {
+		log.G(ctx).
+			WithField("user", user).
+			WithField("team", fmt.Sprintf("@%s/%s", change.Org, change.Team)).
+			Info("removing")
+
+		if _, err := c.client.Teams.RemoveTeamMembershipBySlug(ctx, change.Org, change.Team, user); err != nil {
+			return fmt.Errorf("could not remove user: %s: %w", user, err)
+		}
+	}
+
+	for _, user := range change.Add {
+		log.G(ctx).
+			WithField("user", user).
+			WithField("team", fmt.Sprintf("@%s/%s", change.Org, change.Team)).
+			Info("adding")
+
+		if _, _, err := c.client.Teams.AddTeamMembershipBySlug(ctx, change.Org, change.Team, user, &github.TeamAddTeamMembershipOptions{
+			Role: role,
+		}); err != nil {
+			return fmt.Errorf("could not add user: %s: %w", user, err)
+		}
+	}
+
+	return nil
+}
+
+// ListTeams returns every team of the organisation which is visible to the
+// token, including secret teams if the token may see them.
+func (c *GithubClient) ListTeams(ctx context.Context, org string) ([]*github.Team, error) {
+	var teams []*github.Team
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		more, resp, err := c.client.Teams.ListTeams(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list teams of %s: %w", org, err)
+		}
+
+		teams = append(teams, more...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return teams, nil
+}
+
+// DeleteTeam deletes the team with the provided slug from the organisation.
+// A team which no longer exists, e.g. because it was the child of a team
+// which has been deleted before, is not an error.
+func (c *GithubClient) DeleteTeam(ctx context.Context, org, slug string) error {
+	if _, err := c.client.Teams.DeleteTeamBySlug(ctx, org, slug); err != nil && !isNotFound(err) {
+		return fmt.Errorf("could not delete team: @%s/%s: %w", org, slug, err)
+	}
+
+	return nil
+}
+
+// ListTeamMembersByRole returns the logins of the members of the team with the
+// provided role, which is either "member" or "maintainer".
+func (c *GithubClient) ListTeamMembersByRole(ctx context.Context, org, team, role string) ([]string, error) {
+	var members []string
+	opts := github.ListOptions{PerPage: 100}
+
+	for {
+		more, resp, err := c.client.Teams.ListTeamMembersBySlug(
+			ctx,
+			org,
+			team,
+			&github.TeamListTeamMembersOptions{
+				Role:        role,
+				ListOptions: opts,
+			},
+		)
+		if err != nil {
+			return nil, teamError(org, team, err)
+		}
+
+		for _, user := range more {
+			members = append(members, user.GetLogin())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return sortedUnique(members), nil
+}
+
+// TeamRepository is a repository which a team has been granted access to.
+type TeamRepository struct {
+	Name string `json:"name"`
+
+	// Permission is the highest permission of the team on the repository, i.e.
+	// one of "read", "triage", "write", "maintain" or "admin".
+	Permission string `json:"permission"`
+}
+
+// ListTeamRepos returns the repositories which the team has been granted
+// access to, sorted by name.
+func (c *GithubClient) ListTeamRepos(ctx context.Context, org, team string) ([]TeamRepository, error) {
+	var repos []TeamRepository
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		more, resp, err := c.client.Teams.ListTeamReposBySlug(ctx, org, team, opts)
+		if err != nil {
+			return nil, teamError(org, team, err)
+		}
+
+		for _, repo := range more {
+			repos = append(repos, TeamRepository{
+				Name:       repo.GetName(),
+				Permission: teamPermission(repo),
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Name < repos[j].Name
+	})
+
+	return repos, nil
+}
+
+// teamPermission returns the highest permission of a team on the repository
+// using the names of the permission levels which GitHub shows in its UI.
+func teamPermission(repo *github.Repository) string {
+	if role := repo.GetRoleName(); role != "" {
+		return role
+	}
+
+	for _, level := range []struct{ key, name string }{
+		{"admin", "admin"},
+		{"maintain", "maintain"},
+		{"push", "write"},
+		{"triage", "triage"},
+		{"pull", "read"},
+	} {
+		if repo.GetPermissions()[level.key] {
+			return level.name
+		}
+	}
+
+	return ""
+}
+
+// RepositoryTeam is a team which has been granted access to a repository.
+type RepositoryTeam struct {
+	Team string `json:"team"`
+
+	// Permission is the highest permission of the team on the repository, i.e.
+	// one of "read", "triage", "write", "maintain" or "admin".
+	Permission string `json:"permission"`
+}
+
+// ListRepoTeams returns the teams which have been granted access to the
+// repository, sorted by slug.
+func (c *GithubClient) ListRepoTeams(ctx context.Context, org, repo string) ([]RepositoryTeam, error) {
+	var teams []RepositoryTeam
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		more, resp, err := c.client.Repositories.ListTeams(ctx, org, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list teams of %s/%s: %w", org, repo, err)
+		}
+
+		for _, team := range more {
+			teams = append(teams, RepositoryTeam{
+				Team:       team.GetSlug(),
+				Permission: repoTeamPermission(team),
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].Team < teams[j].Team
+	})
+
+	return teams, nil
+}
+
+// AddTeamRepoPermission grants the team the permission on the repository,
+// replacing any other permission of the team on it.  The permission is one of
+// "read", "triage", "write", "maintain" or "admin", or the name of a custom
+// repository role.
+func (c *GithubClient) AddTeamRepoPermission(ctx context.Context, org, teamSlug, repo, permission string) error {
+	// The API still uses the historical names of the read and write levels.
+	switch permission {
+	case "read":
+		permission = "pull"
+	case "write":
+		permission = "push"
+	}
+
+	if _, err := c.client.Teams.AddTeamRepoBySlug(ctx, org, teamSlug, org, repo, &github.TeamAddTeamRepoOptions{
+		Permission: permission,
+	}); err != nil {
+		return fmt.Errorf("could not grant @%s/%s access to %s: %w", org, teamSlug, repo, err)
+	}
+
+	return nil
+}
+
+// RemoveTeamRepo revokes the access of the team to the repository.  The team
+// may still have access through its parent team.
+func (c *GithubClient) RemoveTeamRepo(ctx context.Context, org, teamSlug, repo string) error {
+	if _, err := c.client.Teams.RemoveTeamRepoBySlug(ctx, org, teamSlug, org, repo); err != nil && !isNotFound(err) {
+		return fmt.Errorf("could not revoke access of @%s/%s to %s: %w", org, teamSlug, repo, err)
+	}
+
+	return nil
+}
+
+// repoTeamPermission returns the highest permission of the team on a
+// repository using the names of the permission levels which GitHub shows in
+// its UI.
+func repoTeamPermission(team *github.Team) string {
+	for _, level := range []struct{ key, name string }{
+		{"admin", "admin"},
+		{"maintain", "maintain"},
+		{"push", "write"},
+		{"triage", "triage"},
+		{"pull", "read"},
+	} {
+		if team.Permissions[level.key] {
+			return level.name
+		}
+	}
+
+	switch permission := team.GetPermission(); permission {
+	case "pull":
+		return "read"
+	case "push":
+		return "write"
+	default:
+		return permission
+	}
+}
+
+// sortedUnique returns the sorted list of distinct, non-empty strings.
+func sortedUnique(list []string) []string {
+	seen := make(map[string]struct{}, len(list))
+	var ret []string
+
+	for _, s := range list {
+		if _, ok := seen[s]; ok || s == "" {
+			continue
+		}
+
+		seen[s] = struct{}{}
+		ret = append(ret, s)
+	}
+
+	sort.Strings(ret)
+
+	return ret
+}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// ListIssueTimeline returns all events on the timeline of the issue or pull
+// request, which can be used to determine whether an action has already been
+// performed, e.g. by a previous run of the same workflow.
+func (c *GithubClient) ListIssueTimeline(ctx context.Context, org, repo string, number int) ([]*github.Timeline, error) {
+	var events []*github.Timeline
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		more, resp, err := c.client.Issues.ListIssueTimeline(ctx, org, repo, number, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, more...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return events, nil
+}
+
+// timelineActor returns the login of the user who performed the event.
+func timelineActor(event *github.Timeline) string {
+	if login := event.GetActor().GetLogin(); login != "" {
+		return login
+	}
+
+	return event.GetUser().GetLogin()
+}
+
+// HasBotLabeled returns whether the provided bot user has applied the label at
+// any point on the timeline, even if it has since been removed.
+func HasBotLabeled(events []*github.Timeline, bot, label string) bool {
+	for _, event := range events {
+		if event.GetEvent() == "labeled" &&
+			timelineActor(event) == bot &&
+			event.GetLabel().GetName() == label {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasBotCommented returns whether the provided bot user has left a comment on
+// the timeline which contains the provided marker.  An empty marker matches
+// any comment by the bot.
+func HasBotCommented(events []*github.Timeline, bot, marker string) bool {
+	for _, event := range events {
+		if event.GetEvent() == "commented" &&
+			timelineActor(event) == bot &&
+			strings.Contains(event.GetBody(), marker) {
+			return true
+		}
+	}
+
+	return false
+}
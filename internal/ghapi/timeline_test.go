@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestListIssueTimeline(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/timeline", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "", "1":
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, r.URL.Path))
+			fmt.Fprint(w, `[
+				{"event":"labeled","actor":{"login":"unikraft-bot"},"label":{"name":"size/S"}},
+				{"event":"labeled","actor":{"login":"jane"},"label":{"name":"kind/bug"}}
+			]`)
+		case "2":
+			fmt.Fprint(w, `[
+				{"event":"unlabeled","actor":{"login":"unikraft-bot"},"label":{"name":"size/S"}},
+				{"event":"commented","actor":{"login":"unikraft-bot"},"body":"<!-- welcome -->\nThanks for your contribution!"},
+				{"event":"commented","user":{"login":"jane"},"body":"<!-- reminder -->"}
+			]`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	})
+
+	client := newTestClient(t, mux)
+
+	events, err := client.ListIssueTimeline(context.Background(), "unikraft", "unikraft", 1)
+	if err != nil {
+		t.Fatalf("ListIssueTimeline() unexpected error: %v", err)
+	}
+
+	if len(events) != 5 {
+		t.Fatalf("ListIssueTimeline() returned %d events, want 5", len(events))
+	}
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"bot labeled, since removed", HasBotLabeled(events, "unikraft-bot", "size/S"), true},
+		{"label applied by someone else", HasBotLabeled(events, "unikraft-bot", "kind/bug"), false},
+		{"label never applied", HasBotLabeled(events, "unikraft-bot", "size/L"), false},
+		{"bot commented with marker", HasBotCommented(events, "unikraft-bot", "<!-- welcome -->"), true},
+		{"bot commented with any body", HasBotCommented(events, "unikraft-bot", ""), true},
+		{"comment by someone else", HasBotCommented(events, "unikraft-bot", "<!-- reminder -->"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
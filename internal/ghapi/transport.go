@@ -0,0 +1,256 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// readOnlyTransport is an http.RoundTripper which only lets through requests
+// which cannot modify state.
+type readOnlyTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return t.base.RoundTrip(req)
+	}
+
+	// The request body must always be closed, even when not sent.
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	return nil, &ReadOnlyError{
+		Method: req.Method,
+		Path:   req.URL.Path,
+	}
+}
+
+// apiVersionHeader is the header with which GitHub selects the version of its
+// REST API.
+const apiVersionHeader = "X-GitHub-Api-Version"
+
+// apiVersionTransport is an http.RoundTripper which pins the version of the
+// GitHub REST API of every request, overriding the one which the client
+// library asks for.
+type apiVersionTransport struct {
+	base    http.RoundTripper
+	version string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *apiVersionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the provided request.
+	req = req.Clone(req.Context())
+	req.Header.Set(apiVersionHeader, t.version)
+
+	return t.base.RoundTrip(req)
+}
+
+const (
+	// DefaultRetryAttempts is the number of times a request which failed
+	// transiently is attempted, including the first, unless otherwise specified
+	// with WithRetry.
+	DefaultRetryAttempts = 4
+
+	// DefaultRetryDelay is the delay before the first retry of a request which
+	// failed transiently, which doubles with every further attempt, unless
+	// otherwise specified with WithRetry.
+	DefaultRetryDelay = time.Second
+)
+
+// retryTransport is an http.RoundTripper which retries requests that failed
+// transiently, i.e. were rejected because of a (secondary) rate limit or
+// failed with a server error.  The delay between attempts grows exponentially
+// and is jittered such that many concurrent clients, e.g. the jobs of a GitHub
+// Actions matrix, do not retry in lockstep.  The timeout, if set, applies to
+// every attempt on its own, such that waiting for a rate limit to reset is not
+// cut short by it.
+type retryTransport struct {
+	base        http.RoundTripper
+	maxAttempts int
+	baseDelay   time.Duration
+	timeout     time.Duration
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.attempt(req)
+		if err != nil {
+			return giveUp(nil, err, attempt)
+		}
+
+		if !isRetryable(req, resp) {
+			return resp, nil
+		}
+
+		if attempt >= t.maxAttempts {
+			return giveUp(resp, nil, attempt)
+		}
+
+		// Requests whose body has already been consumed cannot be replayed.
+		if req.Body != nil && req.GetBody == nil {
+			return giveUp(resp, nil, attempt)
+		}
+
+		delay := jitter(t.baseDelay << (attempt - 1))
+		if after := retryAfter(resp); after > delay {
+			delay = after
+		}
+
+		// Do not wait for longer than the request is allowed to take, but
+		// rather give up straight away.
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < delay {
+			return giveUp(resp, nil, attempt)
+		}
+
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// attempt performs a single attempt of the request, which has to complete,
+// including reading its response, within the timeout of the transport.
+func (t *retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// cancelBody is the body of a response which releases the timeout of its
+// attempt once closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
+
+// giveUp returns the outcome of the last attempt of a request.  Once it has
+// been retried, the outcome is turned into an error which carries the number
+// of attempts, such that callers can tell a persistent failure from a one-off.
+// The error wraps the one go-github would have returned for the response,
+// which is otherwise dropped, as net/http ignores the response of a
+// RoundTripper which also returns an error.
+func giveUp(resp *http.Response, err error, attempts int) (*http.Response, error) {
+	if attempts <= 1 {
+		return resp, err
+	}
+
+	if err == nil {
+		err = github.CheckResponse(resp)
+		resp.Body.Close()
+	}
+
+	return nil, fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+}
+
+// isRetryable returns whether the request failed transiently, i.e. GitHub
+// rejected it because of a primary or secondary rate limit or failed with a
+// server error.  Server errors are not retried for POST requests, which are
+// not idempotent and may have been applied regardless, e.g. creating a
+// comment.
+func isRetryable(req *http.Request, resp *http.Response) bool {
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return req.Method != http.MethodPost
+	}
+
+	return isRateLimited(resp)
+}
+
+// isRateLimited returns whether GitHub rejected the request because of a
+// primary or secondary rate limit.  Every response carries X-RateLimit-Reset,
+// so a 403 without Retry-After is only considered rate limited once the quota
+// is exhausted and not, e.g., when lacking permissions.
+func isRateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get("Retry-After") != "" ||
+			resp.Header.Get("X-RateLimit-Remaining") == "0"
+	}
+
+	return false
+}
+
+// retryAfter returns how long GitHub asked to wait before retrying, either
+// through the Retry-After header or the reset time of the exhausted rate
+// limit.
+func retryAfter(resp *http.Response) time.Duration {
+	if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(s) * time.Second
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0
+	}
+
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		return time.Until(time.Unix(reset, 0))
+	}
+
+	return 0
+}
+
+// jitter returns a random duration in the range [d/2, d).
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+
+	half := d / 2
+
+	return half + time.Duration(rand.Int63n(int64(d-half)))
+}
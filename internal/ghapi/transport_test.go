@@ -0,0 +1,368 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+)
+
+func TestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+		fmt.Fprint(w, `{"number":1}`)
+	})
+
+	client := newTestClient(t, mux, WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	_, err := client.GetPullRequest(context.Background(), "unikraft", "unikraft", 1)
+	if err == nil {
+		t.Fatal("GetPullRequest() expected timeout error")
+	}
+
+	var nerr net.Error
+	if !errors.As(err, &nerr) || !nerr.Timeout() {
+		t.Errorf("expected a timeout error, got: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("request took %s, expected the timeout to fire", elapsed)
+	}
+}
+
+func TestTimeoutPerAttempt(t *testing.T) {
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		fmt.Fprint(w, `{"number":1}`)
+	})
+
+	// Waiting for the rate limit to reset takes longer than the timeout of a
+	// single attempt.
+	client := newTestClient(t, mux, WithTimeout(200*time.Millisecond), WithRetry(2, time.Millisecond))
+
+	pr, err := client.GetPullRequest(context.Background(), "unikraft", "unikraft", 1)
+	if err != nil {
+		t.Fatalf("GetPullRequest() unexpected error: %v", err)
+	}
+
+	if pr.GetNumber() != 1 || calls != 2 {
+		t.Errorf("got pull request %d after %d calls, want 1 after 2", pr.GetNumber(), calls)
+	}
+}
+
+func TestAPIVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []GithubClientOption
+		want string
+	}{
+		{
+			name: "default",
+			want: DefaultAPIVersion,
+		},
+		{
+			name: "pinned",
+			opts: []GithubClientOption{WithAPIVersion("2026-03-10")},
+			want: "2026-03-10",
+		},
+		{
+			name: "empty",
+			opts: []GithubClientOption{WithAPIVersion("")},
+			want: DefaultAPIVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Values(apiVersionHeader)
+				fmt.Fprint(w, `{"number":1}`)
+			})
+
+			client := newTestClient(t, mux, tt.opts...)
+
+			if _, err := client.GetPullRequest(context.Background(), "unikraft", "unikraft", 1); err != nil {
+				t.Fatalf("GetPullRequest() unexpected error: %v", err)
+			}
+
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("%s = %v, want [%s]", apiVersionHeader, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		responses  []int
+		header     map[string]string
+		wantStatus int
+		wantCalls  int
+		wantErr    string
+	}{
+		{
+			name:       "too many requests",
+			responses:  []int{http.StatusTooManyRequests, http.StatusOK},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "secondary rate limit",
+			responses:  []int{http.StatusForbidden, http.StatusForbidden, http.StatusOK},
+			header:     map[string]string{"Retry-After": "0"},
+			wantStatus: http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "primary rate limit",
+			responses:  []int{http.StatusForbidden, http.StatusOK},
+			header:     map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "0"},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "forbidden",
+			responses:  []int{http.StatusForbidden, http.StatusOK},
+			header:     map[string]string{"X-RateLimit-Remaining": "4999", "X-RateLimit-Reset": "0"},
+			wantStatus: http.StatusForbidden,
+			wantCalls:  1,
+		},
+		{
+			name:       "bad gateway",
+			method:     http.MethodPut,
+			responses:  []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantStatus: http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "server error of post",
+			responses:  []int{http.StatusBadGateway, http.StatusOK},
+			wantStatus: http.StatusBadGateway,
+			wantCalls:  1,
+		},
+		{
+			name:      "gives up",
+			responses: []int{429, 429, 429, 429, 429},
+			wantCalls: 3,
+			wantErr:   "giving up after 3 attempts: POST",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != "payload" {
+					t.Errorf("attempt %d: body = %q, want %q", calls, body, "payload")
+				}
+
+				status := tt.responses[calls]
+				calls++
+
+				if status != http.StatusOK {
+					for k, v := range tt.header {
+						w.Header().Set(k, v)
+					}
+				}
+
+				w.WriteHeader(status)
+			}))
+			defer srv.Close()
+
+			client := &http.Client{
+				Transport: &retryTransport{
+					base:        http.DefaultTransport,
+					maxAttempts: 3,
+					baseDelay:   time.Millisecond,
+				},
+			}
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+
+			req, err := http.NewRequest(method, srv.URL, strings.NewReader("payload"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := client.Do(req)
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, `{"message":"Server Error"}`)
+	})
+
+	client := newTestClient(t, mux, WithRetry(2, time.Millisecond))
+
+	_, err := client.GetPullRequest(context.Background(), "unikraft", "unikraft", 1)
+	if err == nil || !strings.Contains(err.Error(), "giving up after 2 attempts") {
+		t.Fatalf("GetPullRequest() error = %v, want it to give up after 2 attempts", err)
+	}
+
+	var gherr *github.ErrorResponse
+	if !errors.As(err, &gherr) || gherr.Response.StatusCode != http.StatusBadGateway {
+		t.Errorf("GetPullRequest() error = %v, want it to wrap the response of the last attempt", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestWithRetrySyncTeamMembers(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/orgs/unikraft/teams/arch/members", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"login":"alice"},{"login":"stale"}]`)
+	})
+	mux.HandleFunc("/api/v3/orgs/unikraft/teams/arch/memberships/stale", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, `{"message":"Server Error"}`)
+	})
+
+	client := newTestClient(t, mux, WithRetry(2, time.Millisecond))
+
+	// go-github returns no response once the transport gave up, which must not
+	// be dereferenced.
+	err := client.SyncTeamMembers(context.Background(), "unikraft", "arch", "member", []string{"alice"})
+	if err == nil || !strings.Contains(err.Error(), "giving up after 2 attempts") {
+		t.Fatalf("SyncTeamMembers() error = %v, want it to give up after 2 attempts", err)
+	}
+}
+
+func TestRetryTransportRespectsCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{
+		Transport: &retryTransport{
+			base:        http.DefaultTransport,
+			maxAttempts: 3,
+			baseDelay:   time.Minute,
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if _, err := client.Do(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want %v", err, context.Canceled)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request took %s, expected cancellation to interrupt the delay", elapsed)
+	}
+}
+
+func TestRetryTransportRespectsDeadline(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{
+		Timeout: time.Second,
+		Transport: &retryTransport{
+			base:        http.DefaultTransport,
+			maxAttempts: 3,
+			baseDelay:   time.Millisecond,
+		},
+	}
+
+	start := time.Now()
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests || calls != 1 {
+		t.Errorf("got status %d after %d calls, want the rate limited response straight away", resp.StatusCode, calls)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("request took %s, expected not to wait beyond the timeout", elapsed)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for _, d := range []time.Duration{0, 1, time.Millisecond, time.Second, time.Minute} {
+		for i := 0; i < 100; i++ {
+			got := jitter(d)
+			if d > 1 && (got < d/2 || got >= d) {
+				t.Fatalf("jitter(%s) = %s, want within [%s, %s)", d, got, d/2, d)
+			}
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// The logical operations which API calls are attributed to with
+// WithOperation.
+const (
+	OperationLabels      = "labels"
+	OperationReviewers   = "reviewers"
+	OperationSize        = "size"
+	OperationMergability = "mergability"
+	OperationMerge       = "merge"
+)
+
+// untagged is the operation and pull request of calls made outside of a
+// tagged context.
+const untagged = "-"
+
+type (
+	usageKey            struct{}
+	operationKey        struct{}
+	usagePullRequestKey struct{}
+)
+
+// APIUsage counts the calls made to the GitHub API per logical operation and
+// pull request.  It is safe for concurrent use.
+type APIUsage struct {
+	mu    sync.Mutex
+	calls map[APIUsageEntry]int
+}
+
+// APIUsageEntry is the number of calls made for an operation on a pull
+// request.  Calls is always zero when used as a key.
+type APIUsageEntry struct {
+	Operation   string
+	PullRequest string
+	Calls       int
+}
+
+// NewAPIUsage returns an empty record of API usage.
+func NewAPIUsage() *APIUsage {
+	return &APIUsage{
+		calls: make(map[APIUsageEntry]int),
+	}
+}
+
+// WithAPIUsage records the calls of every client created with the returned
+// context in the provided usage.  Clients created without it do not record
+// anything and add no overhead.
+func WithAPIUsage(ctx context.Context, usage *APIUsage) context.Context {
+	return context.WithValue(ctx, usageKey{}, usage)
+}
+
+// WithOperation attributes the calls made with the returned context to the
+// provided logical operation, e.g. OperationLabels.
+func WithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+// WithAPIUsagePullRequest attributes the calls made with the returned context
+// to the provided pull request.
+func WithAPIUsagePullRequest(ctx context.Context, org, repo string, prId int) context.Context {
+	return context.WithValue(ctx, usagePullRequestKey{}, fmt.Sprintf("%s/%s#%d", org, repo, prId))
+}
+
+// record counts a single call made with the provided context.
+func (u *APIUsage) record(ctx context.Context) {
+	key := APIUsageEntry{
+		Operation:   untagged,
+		PullRequest: untagged,
+	}
+
+	if op, ok := ctx.Value(operationKey{}).(string); ok {
+		key.Operation = op
+	}
+	if pr, ok := ctx.Value(usagePullRequestKey{}).(string); ok {
+		key.PullRequest = pr
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.calls[key]++
+}
+
+// Entries returns the number of calls of every combination of operation and
+// pull request, sorted by pull request and operation.
+func (u *APIUsage) Entries() []APIUsageEntry {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	entries := make([]APIUsageEntry, 0, len(u.calls))
+	for key, calls := range u.calls {
+		key.Calls = calls
+		entries = append(entries, key)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].PullRequest != entries[j].PullRequest {
+			return entries[i].PullRequest < entries[j].PullRequest
+		}
+
+		return entries[i].Operation < entries[j].Operation
+	})
+
+	return entries
+}
+
+// ByOperation returns the total number of calls of each operation.
+func (u *APIUsage) ByOperation() map[string]int {
+	totals := make(map[string]int)
+	for _, entry := range u.Entries() {
+		totals[entry.Operation] += entry.Calls
+	}
+
+	return totals
+}
+
+// ByPullRequest returns the total number of calls made for each pull request.
+func (u *APIUsage) ByPullRequest() map[string]int {
+	totals := make(map[string]int)
+	for _, entry := range u.Entries() {
+		totals[entry.PullRequest] += entry.Calls
+	}
+
+	return totals
+}
+
+// WriteCSV writes the entries as CSV with a header row.
+func (u *APIUsage) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"pull_request", "operation", "calls"}); err != nil {
+		return err
+	}
+
+	for _, entry := range u.Entries() {
+		if err := cw.Write([]string{entry.PullRequest, entry.Operation, strconv.Itoa(entry.Calls)}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// usageTransport is an http.RoundTripper which records every request in the
+// API usage, attributed to the operation and pull request of its context.
+type usageTransport struct {
+	base  http.RoundTripper
+	usage *APIUsage
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *usageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.usage.record(req.Context())
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestAPIUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number":1,"state":"open"}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	usage := NewAPIUsage()
+
+	client, err := NewGithubClient(WithAPIUsage(context.Background(), usage), "token", false, srv.URL)
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	// Calls are made concurrently from differently tagged contexts.
+	var wg sync.WaitGroup
+	for _, call := range []struct {
+		operation string
+		prId      int
+		n         int
+	}{
+		{OperationLabels, 1, 3},
+		{OperationReviewers, 1, 2},
+		{OperationMergability, 2, 4},
+	} {
+		for i := 0; i < call.n; i++ {
+			wg.Add(1)
+			go func(operation string, prId int) {
+				defer wg.Done()
+
+				ctx := WithOperation(WithAPIUsagePullRequest(context.Background(), "unikraft", "unikraft", prId), operation)
+				if _, err := client.GetPullRequest(ctx, "unikraft", "unikraft", prId); err != nil {
+					t.Errorf("GetPullRequest() unexpected error: %v", err)
+				}
+			}(call.operation, call.prId)
+		}
+	}
+
+	wg.Wait()
+
+	// Calls outside of a tagged context are recorded as untagged.
+	if _, err := client.GetPullRequest(context.Background(), "unikraft", "unikraft", 3); err != nil {
+		t.Fatalf("GetPullRequest() unexpected error: %v", err)
+	}
+
+	wantEntries := []APIUsageEntry{
+		{Operation: untagged, PullRequest: untagged, Calls: 1},
+		{Operation: OperationLabels, PullRequest: "unikraft/unikraft#1", Calls: 3},
+		{Operation: OperationReviewers, PullRequest: "unikraft/unikraft#1", Calls: 2},
+		{Operation: OperationMergability, PullRequest: "unikraft/unikraft#2", Calls: 4},
+	}
+	if got := usage.Entries(); !reflect.DeepEqual(got, wantEntries) {
+		t.Errorf("Entries() = %+v, want %+v", got, wantEntries)
+	}
+
+	wantOperations := map[string]int{untagged: 1, OperationLabels: 3, OperationReviewers: 2, OperationMergability: 4}
+	if got := usage.ByOperation(); !reflect.DeepEqual(got, wantOperations) {
+		t.Errorf("ByOperation() = %v, want %v", got, wantOperations)
+	}
+
+	wantPullRequests := map[string]int{untagged: 1, "unikraft/unikraft#1": 5, "unikraft/unikraft#2": 4}
+	if got := usage.ByPullRequest(); !reflect.DeepEqual(got, wantPullRequests) {
+		t.Errorf("ByPullRequest() = %v, want %v", got, wantPullRequests)
+	}
+
+	var csv bytes.Buffer
+	if err := usage.WriteCSV(&csv); err != nil {
+		t.Fatalf("WriteCSV() unexpected error: %v", err)
+	}
+
+	wantCSV := "pull_request,operation,calls\n" +
+		"-,-,1\n" +
+		"unikraft/unikraft#1,labels,3\n" +
+		"unikraft/unikraft#1,reviewers,2\n" +
+		"unikraft/unikraft#2,mergability,4\n"
+	if csv.String() != wantCSV {
+		t.Errorf("WriteCSV() = %q, want %q", csv.String(), wantCSV)
+	}
+}
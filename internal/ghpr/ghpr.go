@@ -19,27 +19,51 @@ import (
 	gitconfig "github.com/go-git/go-git/v5/config"
 	gitplumbing "github.com/go-git/go-git/v5/plumbing"
 	gitobject "github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/google/go-github/v63/github"
 	"github.com/sirupsen/logrus"
-	kitcfg "kraftkit.sh/config"
 	"kraftkit.sh/log"
 
-	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/cmdutils"
 	"github.com/unikraft/governance/internal/ghapi"
 	"github.com/unikraft/governance/internal/patch"
 )
 
 type PullRequest struct {
-	client     *ghapi.GithubClient
-	pr         *github.PullRequest
-	patches    []*patch.Patch
-	baseBranch string
-	workdir    string
-	localRepo  string
-	ghOrg      string
-	ghRepo     string
-	ghPrId     int
+	client      *ghapi.GithubClient
+	pr          *github.PullRequest
+	patches     []*patch.Patch
+	baseBranch  string
+	workdir     string
+	localRepo   string
+	ghOrg       string
+	ghRepo      string
+	ghPrId      int
+	maxPatches  int
+	gitBinary   string
+	annotations bool
+
+	// gitUser and gitToken authenticate the clone and fetch of the
+	// repository, see WithCredentials.
+	gitUser  string
+	gitToken string
+}
+
+// DefaultMaxPatches is the maximum number of patches generated for a pull
+// request unless otherwise specified with WithMaxPatches.
+const DefaultMaxPatches = 500
+
+// NewPullRequestFromAPI returns a pull request which is only accessed via the
+// GitHub API, e.g. to evaluate its merge requirements with Verdict, such that
+// the repository is neither cloned nor are its patches generated.
+func NewPullRequestFromAPI(client *ghapi.GithubClient, ghOrg, ghRepo string, ghPrId int) *PullRequest {
+	return &PullRequest{
+		client: client,
+		ghOrg:  ghOrg,
+		ghRepo: ghRepo,
+		ghPrId: ghPrId,
+	}
 }
 
 // NewPullRequestFromID fetches information about a pull request via GitHub as
@@ -49,10 +73,11 @@ func NewPullRequestFromID(ctx context.Context, client *ghapi.GithubClient, ghOrg
 	var err error
 
 	pr := PullRequest{
-		client: client,
-		ghOrg:  ghOrg,
-		ghRepo: ghRepo,
-		ghPrId: ghPrId,
+		client:    client,
+		ghOrg:     ghOrg,
+		ghRepo:    ghRepo,
+		ghPrId:    ghPrId,
+		gitBinary: patch.DefaultGitBinary,
 	}
 
 	for _, opt := range opts {
@@ -72,7 +97,7 @@ func NewPullRequestFromID(ctx context.Context, client *ghapi.GithubClient, ghOrg
 
 	pr.localRepo = filepath.Join(pr.workdir, fmt.Sprintf("%s-pr-%d", ghRepo, ghPrId))
 
-	if os.Getenv("GITHUB_ACTIONS") == "yes" {
+	if cmdutils.InGithubActions() {
 		pr.localRepo = os.Getenv("GITHUB_WORKSPACE")
 	}
 
@@ -87,11 +112,8 @@ func NewPullRequestFromID(ctx context.Context, client *ghapi.GithubClient, ghOrg
 			Info("cloning git repository")
 
 		copts := &git.CloneOptions{
-			URL: ghOrigin,
-			Auth: &http.BasicAuth{
-				Username: kitcfg.G[config.Config](ctx).GithubUser,
-				Password: kitcfg.G[config.Config](ctx).GithubToken,
-			},
+			URL:  ghOrigin,
+			Auth: pr.auth(),
 		}
 
 		if pr.BaseBranch() != "" {
@@ -127,16 +149,10 @@ func NewPullRequestFromID(ctx context.Context, client *ghapi.GithubClient, ghOrg
 		}
 	}
 
-	baseBranch, err := repo.Branch(pr.baseBranch)
-	if err != nil {
+	if _, err := repo.Branch(pr.baseBranch); err != nil {
 		return nil, fmt.Errorf("could not get base branch '%s': %w", pr.baseBranch, err)
 	}
 
-	baseRef, err := repo.Reference(baseBranch.Merge, true)
-	if err != nil {
-		return nil, fmt.Errorf("could not base reference: %w", err)
-	}
-
 	refname := fmt.Sprintf("refs/pull/%d/head", ghPrId)
 
 	log.G(ctx).Info("fetching pull request details")
@@ -145,10 +161,7 @@ func NewPullRequestFromID(ctx context.Context, client *ghapi.GithubClient, ghOrg
 		RefSpecs: []gitconfig.RefSpec{
 			gitconfig.RefSpec(fmt.Sprintf("%s:%s", refname, refname)),
 		},
-		Auth: &http.BasicAuth{
-			Username: kitcfg.G[config.Config](ctx).GithubUser,
-			Password: kitcfg.G[config.Config](ctx).GithubToken,
-		},
+		Auth: pr.auth(),
 	}); err != nil && !strings.Contains(err.Error(), "already up-to-date") {
 		return nil, fmt.Errorf("could not fetch pull request '%s': %w", refname, err)
 	}
@@ -168,49 +181,67 @@ func NewPullRequestFromID(ctx context.Context, client *ghapi.GithubClient, ghOrg
 
 	log.G(ctx).Infof("configuring committer name and email")
 
-	// Add commiter name
-	if committerName != "" {
-		args := []string{"-C", pr.localRepo, "config"}
-		if committerGlobal {
-			args = append(args, "--global")
+	_, lookErr := exec.LookPath(pr.gitBinary)
+
+	if lookErr != nil {
+		log.G(ctx).
+			WithField("git", pr.gitBinary).
+			Warn("git executable not found, falling back to native implementation")
+
+		if err := configureCommitterNative(ctx, repo, committerName, committerEmail, committerGlobal); err != nil {
+			return nil, err
 		}
-		args = append(args, "user.name", committerName)
-		cmd := exec.Command("git", args...)
-		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
-		cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
-		if err := cmd.Run(); err != nil {
-			return nil, fmt.Errorf("could not config user: %w", err)
+	} else {
+		// Add commiter name
+		if committerName != "" {
+			args := []string{"-C", pr.localRepo, "config"}
+			if committerGlobal {
+				args = append(args, "--global")
+			}
+			args = append(args, "user.name", committerName)
+			cmd := exec.Command(pr.gitBinary, args...)
+			cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
+			cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
+			if err := cmd.Run(); err != nil {
+				return nil, fmt.Errorf("could not config user: %w", err)
+			}
 		}
-	}
 
-	// Add commiter email
-	if committerEmail != "" {
-		args := []string{"-C", pr.localRepo, "config"}
-		if committerGlobal {
-			args = append(args, "--global")
-		}
-		args = append(args, "user.email", committerEmail)
-		cmd := exec.Command("git", args...)
-		cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
-		cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
-		if err := cmd.Run(); err != nil {
-			return nil, fmt.Errorf("could not config email: %w", err)
+		// Add commiter email
+		if committerEmail != "" {
+			args := []string{"-C", pr.localRepo, "config"}
+			if committerGlobal {
+				args = append(args, "--global")
+			}
+			args = append(args, "user.email", committerEmail)
+			cmd := exec.Command(pr.gitBinary, args...)
+			cmd.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
+			cmd.Stdout = log.G(ctx).WriterLevel(logrus.DebugLevel)
+			if err := cmd.Run(); err != nil {
+				return nil, fmt.Errorf("could not config email: %w", err)
+			}
 		}
 	}
 
 	log.G(ctx).Infof("rebasing pull request's branch on to '%s' branch", pr.baseBranch)
 
-	rebase := exec.CommandContext(
-		ctx,
-		"git",
-		"-C", pr.localRepo,
-		"rebase",
-		fmt.Sprintf("origin/%s", pr.baseBranch),
-	)
-	rebase.Stdout = log.G(ctx).WriterLevel(logrus.ErrorLevel)
-	rebase.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
-	if err := rebase.Run(); err != nil {
-		return nil, fmt.Errorf("could not rebase: %w", err)
+	if lookErr != nil {
+		if err := rebaseNative(repo, pr.baseBranch); err != nil {
+			return nil, err
+		}
+	} else {
+		rebase := exec.CommandContext(
+			ctx,
+			pr.gitBinary,
+			"-C", pr.localRepo,
+			"rebase",
+			fmt.Sprintf("origin/%s", pr.baseBranch),
+		)
+		rebase.Stdout = log.G(ctx).WriterLevel(logrus.ErrorLevel)
+		rebase.Stderr = log.G(ctx).WriterLevel(logrus.ErrorLevel)
+		if err := rebase.Run(); err != nil {
+			return nil, fmt.Errorf("could not rebase: %w", err)
+		}
 	}
 
 	log.G(ctx).Info("generating patch files")
@@ -232,11 +263,46 @@ func NewPullRequestFromID(ctx context.Context, client *ghapi.GithubClient, ghOrg
 		return nil, fmt.Errorf("could not get pull request: %w", err)
 	}
 
+	if err := pr.generatePatches(ctx, itr, pr.pr.GetCommits()); err != nil {
+		return nil, err
+	}
+
+	commits, err := pr.client.GetPullRequestCommits(ctx, ghOrg, ghRepo, ghPrId)
+	if err != nil {
+		return nil, fmt.Errorf("could not get pull request commits: %w", err)
+	}
+
+	if err := verifyPatches(pr.patches, commits); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// generatePatches walks the provided log from the rebased HEAD and generates a
+// patch for exactly the expected number of commits, i.e. the number of commits
+// the pull request reports.  The original base commit cannot be relied upon as
+// a stop condition since it may no longer exist after the rebase, e.g. when the
+// pull request was forked from an outdated copy of the base branch.
+func (pr *PullRequest) generatePatches(ctx context.Context, itr gitobject.CommitIter, expected int) error {
 	stopErr := errors.New("stop")
 	var prevCommit *gitobject.Commit
 
 	totalCommits := 0
 
+	maxPatches := pr.maxPatches
+	if maxPatches <= 0 {
+		maxPatches = DefaultMaxPatches
+	}
+
+	if expected <= 0 {
+		return fmt.Errorf("pull request reports no commits")
+	}
+
+	if expected > maxPatches {
+		return fmt.Errorf("pull request reports %d commits which exceeds the maximum of %d patches", expected, maxPatches)
+	}
+
 	pr.patches = make([]*patch.Patch, 0)
 
 	if err := itr.ForEach(func(commit *gitobject.Commit) error {
@@ -247,32 +313,147 @@ func NewPullRequestFromID(ctx context.Context, client *ghapi.GithubClient, ghOrg
 
 		totalCommits++
 
-		p, err := patch.NewPatchFromCommits(ctx, pr.localRepo, prevCommit, commit)
+		p, err := patch.NewPatchFromCommits(ctx, pr.localRepo, prevCommit, commit,
+			patch.WithGitBinary(pr.gitBinary),
+		)
 		if err != nil {
 			return err
 		}
 
-		p.Filename = strings.ReplaceAll(p.Title, " ", "-")
-		p.Filename = strings.ReplaceAll(p.Filename, ":", "")
-		p.Filename = strings.ReplaceAll(p.Filename, ".", "")
-		p.Filename = strings.ReplaceAll(p.Filename, "/", "-")
-		p.Filename = strings.ReplaceAll(p.Filename, "?", "")
-		p.Filename = strings.ReplaceAll(p.Filename, "`", "")
-		p.Filename = filepath.Join(pr.workdir, fmt.Sprintf("%s-pr-%d-%d-%s.patch", ghRepo, ghPrId, totalCommits, p.Filename))
+		p.Filename = filepath.Join(pr.workdir, fmt.Sprintf("%s-pr-%d-%d-%s.patch", pr.ghRepo, pr.ghPrId, totalCommits, patch.SafeFilename(p.Title)))
 
 		pr.patches = append(pr.patches, p)
 
-		if commit.Hash == baseRef.Hash() || totalCommits >= *pr.pr.Commits {
+		if totalCommits >= expected {
 			return stopErr
 		}
 
 		prevCommit = commit
 		return nil
 	}); err != nil && !errors.Is(err, stopErr) {
-		return nil, fmt.Errorf("could not iterate over log error: %w", err)
+		return fmt.Errorf("could not iterate over log error: %w", err)
 	}
 
-	return &pr, nil
+	if len(pr.patches) != expected {
+		return fmt.Errorf("pull request reports %d commits but only %d could be found after rebasing", expected, len(pr.patches))
+	}
+
+	return nil
+}
+
+// verifyPatches compares the generated series of patches, which is ordered
+// from HEAD, against the pull request's commits as listed by GitHub, which are
+// ordered oldest first.  The title and author of each pair must match such that
+// the series never contains commits which are not part of the pull request.
+func verifyPatches(patches []*patch.Patch, commits []*github.RepositoryCommit) error {
+	if len(patches) != len(commits) {
+		return fmt.Errorf("rebase produced unexpected commits: generated %d patches but the pull request has %d commits", len(patches), len(commits))
+	}
+
+	for i, commit := range commits {
+		p := patches[len(patches)-1-i]
+
+		title, _, _ := strings.Cut(commit.GetCommit().GetMessage(), "\n")
+		title = strings.TrimSpace(title)
+		author := commit.GetCommit().GetAuthor()
+
+		if strings.TrimSpace(p.Title) != title || p.AuthorName != author.GetName() || p.AuthorEmail != author.GetEmail() {
+			return fmt.Errorf(
+				"rebase produced unexpected commits: patch %d '%s' by %s <%s> does not match pull request commit %s '%s' by %s <%s>",
+				i+1,
+				p.Title,
+				p.AuthorName,
+				p.AuthorEmail,
+				commit.GetSHA(),
+				title,
+				author.GetName(),
+				author.GetEmail(),
+			)
+		}
+	}
+
+	return nil
+}
+
+// configureCommitterNative sets the committer's name and email in the
+// repository's configuration without the git executable.  The global
+// configuration cannot be modified natively and is therefore left untouched.
+func configureCommitterNative(ctx context.Context, repo *git.Repository, name, email string, global bool) error {
+	if name == "" && email == "" {
+		return nil
+	}
+
+	if global {
+		log.G(ctx).Warn("cannot set the committer globally without git, setting it for the repository only")
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return fmt.Errorf("could not read repository config: %w", err)
+	}
+
+	if name != "" {
+		cfg.User.Name = name
+	}
+	if email != "" {
+		cfg.User.Email = email
+	}
+
+	if err := repo.SetConfig(cfg); err != nil {
+		return fmt.Errorf("could not set committer: %w", err)
+	}
+
+	return nil
+}
+
+// rebaseNative is the fallback used when the git executable is not
+// available.  go-git cannot replay commits, so only the case where the pull
+// request already sits on top of the base branch is supported, in which case
+// the rebase is a no-op.  In all other cases an error is returned.
+func rebaseNative(repo *git.Repository, baseBranch string) error {
+	baseRef, err := repo.Reference(gitplumbing.NewRemoteReferenceName("origin", baseBranch), true)
+	if err != nil {
+		return fmt.Errorf("could not resolve 'origin/%s': %w", baseBranch, err)
+	}
+
+	headRef, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("could not get HEAD: %w", err)
+	}
+
+	base, err := repo.CommitObject(baseRef.Hash())
+	if err != nil {
+		return fmt.Errorf("could not get base commit: %w", err)
+	}
+
+	head, err := repo.CommitObject(headRef.Hash())
+	if err != nil {
+		return fmt.Errorf("could not get head commit: %w", err)
+	}
+
+	upToDate, err := base.IsAncestor(head)
+	if err != nil {
+		return fmt.Errorf("could not compare head with base: %w", err)
+	}
+
+	if !upToDate {
+		return fmt.Errorf("could not rebase onto 'origin/%s': pull request is not based on the tip of the branch and rebasing requires the git executable", baseBranch)
+	}
+
+	return nil
+}
+
+// auth returns the authentication of the clone and fetch of the repository,
+// or nil when no credentials were provided.
+func (pr *PullRequest) auth() transport.AuthMethod {
+	if pr.gitUser == "" && pr.gitToken == "" {
+		return nil
+	}
+
+	return &http.BasicAuth{
+		Username: pr.gitUser,
+		Password: pr.gitToken,
+	}
 }
 
 // LocalRepo is the path on disk to a copy of the pull request.
@@ -302,3 +483,15 @@ func (pr *PullRequest) Metadata() *github.PullRequest {
 func (pr *PullRequest) BaseBranch() string {
 	return pr.baseBranch
 }
+
+// HeadSHA is the commit at the head of the pull request, or empty if its
+// metadata is not known.
+func (pr *PullRequest) HeadSHA() string {
+	return pr.pr.GetHead().GetSHA()
+}
+
+// BaseSHA is the commit of the base branch which the pull request was
+// compared against, or empty if its metadata is not known.
+func (pr *PullRequest) BaseSHA() string {
+	return pr.pr.GetBase().GetSHA()
+}
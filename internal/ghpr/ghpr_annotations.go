@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Annotation levels of GitHub Actions workflow commands.
+const (
+	AnnotationError   = "error"
+	AnnotationWarning = "warning"
+	AnnotationNotice  = "notice"
+)
+
+// GitHubActionsAnnotations returns whether findings about the pull request
+// are emitted as GitHub Actions annotations, see
+// WithGitHubActionsAnnotations.
+func (pr *PullRequest) GitHubActionsAnnotations() bool {
+	return pr.annotations
+}
+
+// Annotate writes a GitHub Actions workflow command which annotates the
+// provided line of the file with the message, provided annotations are
+// enabled.  A line of zero annotates the file as a whole.
+//
+// See: https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-error-message
+func (pr *PullRequest) Annotate(w io.Writer, level, file string, line int, title, message string) {
+	if !pr.annotations {
+		return
+	}
+
+	var props []string
+	if file != "" {
+		props = append(props, "file="+escapeAnnotationProperty(file))
+	}
+	if line > 0 {
+		props = append(props, fmt.Sprintf("line=%d", line))
+	}
+	if title != "" {
+		props = append(props, "title="+escapeAnnotationProperty(title))
+	}
+
+	fmt.Fprintf(w, "::%s %s::%s\n", level, strings.Join(props, ","), escapeAnnotationData(message))
+}
+
+// escapeAnnotationData escapes the message of a workflow command such that it
+// cannot end the command prematurely.
+func escapeAnnotationData(s string) string {
+	return strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+	).Replace(s)
+}
+
+// escapeAnnotationProperty escapes the value of a property of a workflow
+// command, which additionally cannot contain the separators of properties.
+func escapeAnnotationProperty(s string) string {
+	return strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+		":", "%3A",
+		",", "%2C",
+	).Replace(s)
+}
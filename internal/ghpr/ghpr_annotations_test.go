@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAnnotate(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		file    string
+		line    int
+		title   string
+		message string
+		want    string
+	}{
+		{
+			name:    "disabled",
+			file:    "lib/ukboot/boot.c",
+			line:    10,
+			title:   "LONG_LINE",
+			message: "line length of 120 exceeds 80 columns",
+		},
+		{
+			name:    "enabled",
+			enabled: true,
+			file:    "lib/ukboot/boot.c",
+			line:    10,
+			title:   "LONG_LINE",
+			message: "line length of 120 exceeds 80 columns",
+			want:    "::error file=lib/ukboot/boot.c,line=10,title=LONG_LINE::line length of 120 exceeds 80 columns\n",
+		},
+		{
+			name:    "escaped",
+			enabled: true,
+			file:    "a,b.c",
+			title:   "TYPE: x",
+			message: "100% wrong\nsecond line",
+			want:    "::error file=a%2Cb.c,title=TYPE%3A x::100%25 wrong%0Asecond line\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PullRequest{}
+			if err := WithGitHubActionsAnnotations(tt.enabled)(pr); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := pr.GitHubActionsAnnotations(); got != tt.enabled {
+				t.Errorf("GitHubActionsAnnotations() = %v, want %v", got, tt.enabled)
+			}
+
+			out := &bytes.Buffer{}
+			pr.Annotate(out, AnnotationError, tt.file, tt.line, tt.title, tt.message)
+
+			if got := out.String(); got != tt.want {
+				t.Errorf("Annotate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// DefaultBotLogins are the authors of automated dependency updates which are
+// recognised unless otherwise specified.
+var DefaultBotLogins = []string{
+	"dependabot[bot]",
+	"renovate[bot]",
+}
+
+// DefaultBotLabels are the labels which mark automated dependency updates
+// unless otherwise specified.
+var DefaultBotLabels = []string{
+	"dependencies",
+}
+
+// DefaultIgnoreAuthors are the patterns of the logins whose comments and
+// reviews are not counted as approvals or reviews unless otherwise specified.
+var DefaultIgnoreAuthors = []string{
+	"*[bot]",
+}
+
+const (
+	// BotPolicyReview subjects bot pull requests to the same approval and
+	// review requirements as any other pull request.
+	BotPolicyReview = "review"
+
+	// BotPolicyChecks only requires every status and check run of a bot pull
+	// request to have succeeded.
+	BotPolicyChecks = "checks"
+)
+
+// BotPolicies are all the known policies for bot pull requests.
+var BotPolicies = []string{
+	BotPolicyReview,
+	BotPolicyChecks,
+}
+
+// ValidateBotPolicy returns an error if the policy is not one of BotPolicies.
+// An empty policy is equivalent to BotPolicyReview.
+func ValidateBotPolicy(policy string) error {
+	if policy == "" {
+		return nil
+	}
+
+	for _, p := range BotPolicies {
+		if policy == p {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown bot policy '%s': expected one of %s", policy, strings.Join(BotPolicies, ", "))
+}
+
+// IsBotPullRequest returns whether the pull request is an automated dependency
+// update, either because it is authored by one of the provided logins or
+// because it carries one of the provided labels.  The defaults are used in
+// place of empty lists.
+func IsBotPullRequest(pull *github.PullRequest, logins, labels []string) bool {
+	if len(logins) == 0 {
+		logins = DefaultBotLogins
+	}
+	if len(labels) == 0 {
+		labels = DefaultBotLabels
+	}
+
+	author := pull.GetUser().GetLogin()
+	for _, login := range logins {
+		if strings.EqualFold(author, login) {
+			return true
+		}
+	}
+
+	for _, label := range pull.Labels {
+		for _, l := range labels {
+			if label.GetName() == l {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsIgnoredAuthor returns whether the login matches any of the provided
+// patterns, where "*" matches any sequence of characters, e.g. "*[bot]"
+// matches "dependabot[bot]".  Unlike path.Match, brackets are matched
+// literally as they are part of the logins of GitHub Apps.  Logins are
+// compared case-insensitively.
+func IsIgnoredAuthor(login string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchLogin(strings.ToLower(pattern), strings.ToLower(login)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchLogin returns whether the login matches the pattern, where "*" matches
+// any sequence of characters.
+func matchLogin(pattern, login string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == login
+	}
+
+	if !strings.HasPrefix(login, parts[0]) {
+		return false
+	}
+	login = login[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(login, part)
+		if i < 0 {
+			return false
+		}
+
+		login = login[i+len(part):]
+	}
+
+	return strings.HasSuffix(login, parts[len(parts)-1])
+}
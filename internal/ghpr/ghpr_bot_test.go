@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+)
+
+func TestIsBotPullRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		author string
+		labels []string
+		logins []string
+		want   bool
+	}{
+		{
+			name:   "dependabot",
+			author: "dependabot[bot]",
+			want:   true,
+		},
+		{
+			name:   "renovate",
+			author: "renovate[bot]",
+			want:   true,
+		},
+		{
+			name:   "dependencies label",
+			author: "jane",
+			labels: []string{"area/lib", "dependencies"},
+			want:   true,
+		},
+		{
+			name:   "human",
+			author: "jane",
+			labels: []string{"area/lib"},
+			want:   false,
+		},
+		{
+			name:   "custom logins replace defaults",
+			author: "dependabot[bot]",
+			logins: []string{"unikraft-bot"},
+			want:   false,
+		},
+		{
+			name:   "custom login",
+			author: "unikraft-bot",
+			logins: []string{"unikraft-bot"},
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pull := &github.PullRequest{
+				User: &github.User{Login: github.String(tt.author)},
+			}
+			for _, label := range tt.labels {
+				pull.Labels = append(pull.Labels, &github.Label{Name: github.String(label)})
+			}
+
+			if got := IsBotPullRequest(pull, tt.logins, nil); got != tt.want {
+				t.Errorf("IsBotPullRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsIgnoredAuthor(t *testing.T) {
+	tests := []struct {
+		login    string
+		patterns []string
+		want     bool
+	}{
+		{login: "dependabot[bot]", patterns: DefaultIgnoreAuthors, want: true},
+		{login: "Renovate[Bot]", patterns: DefaultIgnoreAuthors, want: true},
+		{login: "robot", patterns: DefaultIgnoreAuthors, want: false},
+		{login: "jane", patterns: DefaultIgnoreAuthors, want: false},
+		{login: "ci-runner", patterns: []string{"ci-*"}, want: true},
+		{login: "unikraft-ci-bot", patterns: []string{"*-ci-*"}, want: true},
+		{login: "ci", patterns: []string{"ci-*"}, want: false},
+		{login: "jane", patterns: []string{"JANE"}, want: true},
+		{login: "jane", patterns: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.login+"/"+strings.Join(tt.patterns, ","), func(t *testing.T) {
+			if got := IsIgnoredAuthor(tt.login, tt.patterns); got != tt.want {
+				t.Errorf("IsIgnoredAuthor(%q, %v) = %v, want %v", tt.login, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+)
+
+const (
+	// SkippedConflicts is reported when merge conflicts are not checked in a
+	// historical evaluation, as GitHub only knows whether the pull request
+	// can be merged now.
+	SkippedConflicts = "conflicts"
+
+	// SkippedChecks is reported when statuses and check runs are not checked
+	// in a historical evaluation, as they may have been re-run since.
+	SkippedChecks = "checks"
+)
+
+// shaRegex matches an abbreviated or full commit SHA.
+var shaRegex = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// ResolveAt returns the point in time of the provided timestamp or commit,
+// which is used to evaluate the pull request as it was at that point, see
+// WithAt.  Timestamps are either in RFC 3339 format or a date.  A commit is
+// either the merge commit of the pull request, which resolves to the time it
+// was merged, or one of its commits, which resolves to the last moment at
+// which it was the head of the pull request.
+func (pr *PullRequest) ResolveAt(ctx context.Context, at string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, at); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, at); err == nil {
+		return t, nil
+	}
+
+	if !shaRegex.MatchString(at) {
+		return time.Time{}, fmt.Errorf("invalid point in time '%s': expected an RFC 3339 timestamp, a date or a commit SHA", at)
+	}
+
+	pull, err := pr.client.GetPullRequest(ctx, pr.ghOrg, pr.ghRepo, pr.ghPrId)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not get pull request: %w", err)
+	}
+
+	if pull.GetMerged() && strings.HasPrefix(pull.GetMergeCommitSHA(), strings.ToLower(at)) {
+		return pull.GetMergedAt().Time, nil
+	}
+
+	commits, err := pr.client.GetPullRequestCommits(ctx, pr.ghOrg, pr.ghRepo, pr.ghPrId)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not list commits: %w", err)
+	}
+
+	for i, commit := range commits {
+		if !strings.HasPrefix(commit.GetSHA(), strings.ToLower(at)) {
+			continue
+		}
+
+		if i+1 < len(commits) {
+			return commitTime(commits[i+1]).Add(-time.Second), nil
+		}
+
+		if closed := pull.GetClosedAt(); !closed.IsZero() {
+			return closed.Time, nil
+		}
+
+		return time.Now(), nil
+	}
+
+	return time.Time{}, fmt.Errorf("commit %s is neither part of nor the merge commit of the pull request", at)
+}
+
+// commitTime returns when the commit was committed, which is the closest
+// approximation of when it became part of the pull request.
+func commitTime(commit *github.RepositoryCommit) time.Time {
+	return commit.GetCommit().GetCommitter().GetDate().Time
+}
+
+// rewind returns a copy of the pull request with its state, draft state,
+// labels and head reconstructed as they were at the point in time of the
+// options from its timeline and commits.  Assignees and requested reviewers
+// cannot be reconstructed and are kept as they are now.
+func (pr *PullRequest) rewind(ctx context.Context, mopts *mergableOptions, pull *github.PullRequest) (*github.PullRequest, error) {
+	at := mopts.at
+
+	if pull.GetCreatedAt().After(at) {
+		return nil, fmt.Errorf("pull request did not exist at %s", at.Format(time.RFC3339))
+	}
+
+	events, err := mopts.ghClient.ListIssueTimeline(ctx, pr.ghOrg, pr.ghRepo, pr.ghPrId)
+	if err != nil {
+		return nil, fmt.Errorf("could not list timeline: %w", err)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].GetCreatedAt().Before(events[j].GetCreatedAt().Time)
+	})
+
+	rewound := *pull
+	rewound.State = github.String("open")
+	rewound.Labels = nil
+
+	// The initial draft state is only known from the first change to it.
+	draft := pull.GetDraft()
+	for _, event := range events {
+		if event.GetEvent() == "ready_for_review" || event.GetEvent() == "convert_to_draft" {
+			draft = event.GetEvent() == "ready_for_review"
+			break
+		}
+	}
+
+	var labels []string
+	for _, event := range events {
+		if event.GetCreatedAt().After(at) {
+			break
+		}
+
+		switch event.GetEvent() {
+		case "closed", "merged":
+			rewound.State = github.String("closed")
+		case "reopened":
+			rewound.State = github.String("open")
+		case "convert_to_draft":
+			draft = true
+		case "ready_for_review":
+			draft = false
+		case "labeled":
+			if name := event.GetLabel().GetName(); !contains(labels, name) {
+				labels = append(labels, name)
+			}
+		case "unlabeled":
+			for i, name := range labels {
+				if name == event.GetLabel().GetName() {
+					labels = append(labels[:i], labels[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+
+	rewound.Draft = github.Bool(draft)
+
+	for _, name := range labels {
+		rewound.Labels = append(rewound.Labels, &github.Label{Name: github.String(name)})
+	}
+
+	commits, err := mopts.ghClient.GetPullRequestCommits(ctx, pr.ghOrg, pr.ghRepo, pr.ghPrId)
+	if err != nil {
+		return nil, fmt.Errorf("could not list commits: %w", err)
+	}
+
+	mopts.commits = nil
+	for _, commit := range commits {
+		if !commitTime(commit).After(at) {
+			mopts.commits = append(mopts.commits, commit)
+		}
+	}
+
+	if len(mopts.commits) == 0 {
+		return nil, fmt.Errorf("pull request had no commits at %s", at.Format(time.RFC3339))
+	}
+
+	head := *pull.GetHead()
+	head.SHA = mopts.commits[len(mopts.commits)-1].SHA
+	rewound.Head = &head
+
+	return &rewound, nil
+}
+
+// skip records a requirement which is not evaluated as it cannot be
+// reconstructed at the point in time of the options.
+func (mopts *mergableOptions) skip(requirement string) {
+	if !contains(mopts.skipped, requirement) {
+		mopts.skipped = append(mopts.skipped, requirement)
+	}
+}
+
+// before returns whether the attestation was made at or before the point in
+// time of the options, if any.
+func (mopts *mergableOptions) before(t time.Time) bool {
+	return mopts.at.IsZero() || !t.After(mopts.at)
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newHistoryMux serves a pull request which was labeled ci/wait until the
+// 4th, approved on the 2nd, reviewed on the 9th and merged on the 10th, after
+// which changes were requested which must not be considered before then.
+func newHistoryMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"number": 1,
+			"state": "closed",
+			"draft": false,
+			"mergeable": false,
+			"merged": true,
+			"merge_commit_sha": "ffff000000000000000000000000000000000000",
+			"created_at": "2024-03-01T09:00:00Z",
+			"closed_at": "2024-03-10T00:00:00Z",
+			"merged_at": "2024-03-10T00:00:00Z",
+			"head": {"sha": "c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3"},
+			"assignees": [{"login": "jane"}]
+		}`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/commits", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"sha": "c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1", "commit": {"committer": {"date": "2024-03-01T08:00:00Z"}}},
+			{"sha": "c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2", "commit": {"committer": {"date": "2024-03-03T08:00:00Z"}}},
+			{"sha": "c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3", "commit": {"committer": {"date": "2024-03-08T08:00:00Z"}}}
+		]`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/timeline", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"event": "labeled", "label": {"name": "ci/wait"}, "created_at": "2024-03-01T10:00:00Z"},
+			{"event": "unlabeled", "label": {"name": "ci/wait"}, "created_at": "2024-03-04T00:00:00Z"},
+			{"event": "closed", "created_at": "2024-03-10T00:00:00Z"}
+		]`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"body": "Approved-by: Jane Doe <jane@unikraft.io>", "user": {"login": "jane"}, "created_at": "2024-03-02T00:00:00Z"},
+			{"body": "Reviewed-by: Bob <bob@unikraft.io>", "user": {"login": "bob"}, "created_at": "2024-03-09T00:00:00Z"}
+		]`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"state": "CHANGES_REQUESTED", "user": {"login": "jane"}, "submitted_at": "2024-03-11T00:00:00Z"}
+		]`)
+	})
+
+	return mux
+}
+
+func TestVerdictAt(t *testing.T) {
+	tests := []struct {
+		name        string
+		at          string
+		opts        []PullRequestMergableOption
+		wantErr     string
+		wantUnmet   []string
+		wantHead    string
+		wantSkipped []string
+	}{
+		{
+			name:    "before creation",
+			at:      "2024-02-01T00:00:00Z",
+			wantErr: "did not exist",
+		},
+		{
+			name:    "whilst labeled",
+			at:      "2024-03-03T12:00:00Z",
+			opts:    []PullRequestMergableOption{WithIgnoreLabels("ci/wait")},
+			wantErr: "requested labels",
+		},
+		{
+			name:      "approved but not reviewed",
+			at:        "2024-03-05T00:00:00Z",
+			opts:      []PullRequestMergableOption{WithIgnoreLabels("ci/wait")},
+			wantUnmet: []string{"reviews (0/1)"},
+			wantHead:  "c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2",
+		},
+		{
+			name: "mergable before merge",
+			at:   "2024-03-09T12:00:00Z",
+			opts: []PullRequestMergableOption{
+				WithIgnoreLabels("ci/wait"),
+				WithNoConflicts(true),
+				WithRequiredChecks("build"),
+			},
+			wantHead:    "c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3",
+			wantSkipped: []string{SkippedConflicts, SkippedChecks},
+		},
+		{
+			name:    "after merge",
+			at:      "2024-03-10T12:00:00Z",
+			wantErr: "requested state",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := newTestPullRequest(t, newHistoryMux())
+
+			at, err := time.Parse(time.RFC3339, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			verdict, err := pr.Verdict(context.Background(), append(tt.opts, WithAt(at))...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Verdict() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Verdict() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(verdict.Unmet, tt.wantUnmet) {
+				t.Errorf("Unmet = %v, want %v", verdict.Unmet, tt.wantUnmet)
+			}
+
+			if verdict.HeadSHA != tt.wantHead {
+				t.Errorf("HeadSHA = %s, want %s", verdict.HeadSHA, tt.wantHead)
+			}
+
+			if !reflect.DeepEqual(verdict.Skipped, tt.wantSkipped) {
+				t.Errorf("Skipped = %v, want %v", verdict.Skipped, tt.wantSkipped)
+			}
+
+			if verdict.At == nil || !verdict.At.Equal(at) {
+				t.Errorf("At = %v, want %v", verdict.At, at)
+			}
+
+			if !strings.Contains(verdict.Markdown(), "| Evaluated as of |") {
+				t.Errorf("expected the table to state that the evaluation is historical:\n%s", verdict.Markdown())
+			}
+		})
+	}
+}
+
+func TestResolveAt(t *testing.T) {
+	tests := []struct {
+		name    string
+		at      string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "timestamp",
+			at:   "2024-03-05T12:00:00+01:00",
+			want: "2024-03-05T11:00:00Z",
+		},
+		{
+			name: "date",
+			at:   "2024-03-05",
+			want: "2024-03-05T00:00:00Z",
+		},
+		{
+			name: "superseded commit",
+			at:   "c2c2c2c",
+			want: "2024-03-08T07:59:59Z",
+		},
+		{
+			name: "last commit",
+			at:   "c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3",
+			want: "2024-03-10T00:00:00Z",
+		},
+		{
+			name: "merge commit",
+			at:   "ffff000",
+			want: "2024-03-10T00:00:00Z",
+		},
+		{
+			name:    "unrelated commit",
+			at:      "abcdef0",
+			wantErr: "neither part of",
+		},
+		{
+			name:    "invalid",
+			at:      "last tuesday",
+			wantErr: "invalid point in time",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := newTestPullRequest(t, newHistoryMux())
+
+			got, err := pr.ResolveAt(context.Background(), tt.at)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveAt() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ResolveAt() error = %v", err)
+			}
+
+			if got.UTC().Format(time.RFC3339) != tt.want {
+				t.Errorf("ResolveAt() = %s, want %s", got.UTC().Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// issueReferenceRegex matches any of GitHub's issue-closing keywords, e.g.
+// "Closes", "fixed" or "Resolves:", followed by a reference to an issue in one
+// of the forms "#N", "ORG/REPO#N" or "https://github.com/ORG/REPO/issues/N".
+// Keywords which are part of a trailer, e.g. "GitHub-Closes", are not matched.
+var issueReferenceRegex = regexp.MustCompile(
+	`(?i)(?:^|[^\w-])(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?[ \t]+(?:#([0-9]+)|([\w.-]+)/([\w.-]+)#([0-9]+)|https://github\.com/([\w.-]+)/([\w.-]+)/issues/([0-9]+))\b`,
+)
+
+// closeableIssues returns the numbers of all issues in the provided repository
+// which are referenced by an issue-closing keyword in the provided texts.
+// References to issues in other repositories are never closed and are
+// returned separately so that they can be reported.
+func closeableIssues(org, repo string, texts ...string) ([]int, []string) {
+	var issues []int
+	var foreign []string
+	seen := make(map[string]bool)
+
+	for _, text := range texts {
+		for _, m := range issueReferenceRegex.FindAllStringSubmatch(text, -1) {
+			refOrg, refRepo, num := org, repo, m[1]
+			if m[4] != "" {
+				refOrg, refRepo, num = m[2], m[3], m[4]
+			} else if m[7] != "" {
+				refOrg, refRepo, num = m[5], m[6], m[7]
+			}
+
+			id, err := strconv.Atoi(num)
+			if err != nil {
+				continue
+			}
+
+			ref := fmt.Sprintf("%s/%s#%d", refOrg, refRepo, id)
+			if seen[strings.ToLower(ref)] {
+				continue
+			}
+
+			seen[strings.ToLower(ref)] = true
+
+			if !strings.EqualFold(refOrg, org) || !strings.EqualFold(refRepo, repo) {
+				foreign = append(foreign, ref)
+				continue
+			}
+
+			issues = append(issues, id)
+		}
+	}
+
+	return issues, foreign
+}
+
+// closingTexts returns the body of the pull request followed by the message
+// of each of its commits, oldest first.
+func (pr *PullRequest) closingTexts() []string {
+	texts := []string{pr.pr.GetBody()}
+
+	for i := len(pr.patches) - 1; i >= 0; i-- {
+		texts = append(texts, pr.patches[i].CommitMessage())
+	}
+
+	return texts
+}
+
+// ClosesIssues returns the numbers of the issues in the pull request's
+// repository which are closed by it, i.e. which are referenced with an
+// issue-closing keyword in its body or in the message of any of its commits.
+func (pr *PullRequest) ClosesIssues() []int {
+	issues, _ := closeableIssues(pr.ghOrg, pr.ghRepo, pr.closingTexts()...)
+	return issues
+}
+
+// ClosesForeignIssues returns the references, in the form "ORG/REPO#N", to
+// issues in other repositories which the pull request claims to close.  These
+// are never closed on merge and are only reported.
+func (pr *PullRequest) ClosesForeignIssues() []string {
+	_, foreign := closeableIssues(pr.ghOrg, pr.ghRepo, pr.closingTexts()...)
+	return foreign
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+
+	"github.com/unikraft/governance/internal/patch"
+)
+
+func TestCloseableIssues(t *testing.T) {
+	tests := []struct {
+		name        string
+		texts       []string
+		wantIssues  []int
+		wantForeign []string
+	}{
+		{
+			name:       "same repository",
+			texts:      []string{"Closes: #12\nFixes: #13\nResolves: #14"},
+			wantIssues: []int{12, 13, 14},
+		},
+		{
+			name:        "cross repository",
+			texts:       []string{"Closes: unikraft/app-nginx#3\nFixes: https://github.com/unikraft/kraftkit/issues/4"},
+			wantForeign: []string{"unikraft/app-nginx#3", "unikraft/kraftkit#4"},
+		},
+		{
+			name: "mixed body and commits",
+			texts: []string{
+				"This fixes the boot.\n\nCloses: #12\nCloses: unikraft/app-nginx#3\n",
+				"lib/ukboot: Fix boot\n\nFixes: unikraft/unikraft#12\nResolves: https://github.com/unikraft/unikraft/issues/15\n",
+			},
+			wantIssues:  []int{12, 15},
+			wantForeign: []string{"unikraft/app-nginx#3"},
+		},
+		{
+			name:       "repository name is case-insensitive",
+			texts:      []string{"Closes: Unikraft/Unikraft#7"},
+			wantIssues: []int{7},
+		},
+		{
+			name:       "keyword variants",
+			texts:      []string{"fixes #1, closed #2 and Resolve: #3\nFIXED #4\nclose #5"},
+			wantIssues: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:  "trailers and prefixed words",
+			texts: []string{"GitHub-Closes: #7\nGitHub-Fixes: #8\nprefixes #9\nunresolved #10"},
+		},
+		{
+			name:  "no keyword",
+			texts: []string{"See #12 and unikraft/unikraft#13"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, foreign := closeableIssues("unikraft", "unikraft", tt.texts...)
+
+			if !reflect.DeepEqual(issues, tt.wantIssues) {
+				t.Errorf("closeableIssues() issues = %v, want %v", issues, tt.wantIssues)
+			}
+			if !reflect.DeepEqual(foreign, tt.wantForeign) {
+				t.Errorf("closeableIssues() foreign = %v, want %v", foreign, tt.wantForeign)
+			}
+		})
+	}
+}
+
+func TestPullRequestClosesIssues(t *testing.T) {
+	pr := &PullRequest{
+		ghOrg:  "unikraft",
+		ghRepo: "unikraft",
+		pr: &github.PullRequest{
+			Body: github.String("This fixes the boot on arm64.\n\nFixes #12\nCloses unikraft/app-nginx#3\n"),
+		},
+		// Patches are ordered from HEAD.
+		patches: []*patch.Patch{
+			{
+				Title:    "plat/kvm: Fix the boot",
+				Message:  "\nResolves: https://github.com/unikraft/unikraft/issues/15\nCloses: #12",
+				Trailers: []string{"GitHub-Closes: #42"},
+			},
+			{
+				Title:   "lib/ukboot: Fix the boot",
+				Message: "\nCloses: #14\nFixes: unikraft/kraftkit#4",
+			},
+		},
+	}
+
+	if got, want := pr.ClosesIssues(), []int{12, 14, 15}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ClosesIssues() = %v, want %v", got, want)
+	}
+
+	if got, want := pr.ClosesForeignIssues(), []string{"unikraft/app-nginx#3", "unikraft/kraftkit#4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ClosesForeignIssues() = %v, want %v", got, want)
+	}
+}
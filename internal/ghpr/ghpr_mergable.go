@@ -7,19 +7,150 @@ package ghpr
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v63/github"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/ghapi"
 )
 
-// SatisfiesMergeRequirements
-func (pr *PullRequest) SatisfiesMergeRequirements(ctx context.Context, opts ...PullRequestMergableOption) (bool, map[string][]string, error) {
+// MergeVerdict is the outcome of evaluating the approvals and reviews of a
+// pull request against the configured merge requirements.
+type MergeVerdict struct {
+	Approvals    int                 `json:"approvals"`
+	MinApprovals int                 `json:"min_approvals"`
+	Reviews      int                 `json:"reviews"`
+	MinReviews   int                 `json:"min_reviews"`
+	Result       map[string][]string `json:"result"`
+	Unmet        []string            `json:"unmet,omitempty"`
+
+	// ChangesRequested lists the eligible reviewers whose most recent review
+	// requests changes and therefore blocks the pull request.
+	ChangesRequested []string `json:"changes_requested,omitempty"`
+
+	// Bot is set when the pull request is an automated dependency update which
+	// is subject to the bot policy instead of approvals and reviews.
+	Bot bool `json:"bot,omitempty"`
+
+	// FailingChecks lists the statuses and check runs which have not succeeded
+	// when the bot policy requires green checks or which are required.
+	FailingChecks []string `json:"failing_checks,omitempty"`
+
+	// TeamApprovals is the number of approvals attributed to each team which
+	// requires a minimum number of approvals.
+	TeamApprovals map[string]int `json:"team_approvals,omitempty"`
+
+	// ShortTeams lists the teams whose members have not provided their minimum
+	// number of approvals.
+	ShortTeams []string `json:"short_teams,omitempty"`
+
+	// MissingApprovers lists the required approvers who have not approved the
+	// pull request.
+	MissingApprovers []string `json:"missing_approvers,omitempty"`
+
+	// UnsignedCommits lists the commits which lack a sign-off of their author
+	// when a sign-off is required.
+	UnsignedCommits []string `json:"unsigned_commits,omitempty"`
+
+	// HeadSHA is the head of the pull request which was evaluated, i.e. at the
+	// time of a historical evaluation if At is set, in which case Skipped
+	// lists the requirements which could not be evaluated.
+	At      *time.Time `json:"at,omitempty"`
+	HeadSHA string     `json:"head_sha,omitempty"`
+	Skipped []string   `json:"skipped,omitempty"`
+
+	// Attestations lists the comments and reviews which qualified as an
+	// approval or review.
+	Attestations []Attestation `json:"attestations,omitempty"`
+}
+
+// Mergable returns whether all requirements of the verdict are met.
+func (v *MergeVerdict) Mergable() bool {
+	return len(v.Unmet) == 0
+}
+
+// attestation is a single statement of approval or review made by a user,
+// either in a comment or in a pull request review.
+type attestation struct {
+	// id is the ID of the comment or review backing the attestation.
+	id     int64
+	login  string
+	body   string
+	state  string
+	review bool
+
+	// submittedAt is when the comment was created or the review submitted
+	// and is used to determine the most recent attestation of each user.
+	submittedAt time.Time
+
+	// synthetic attestations are not backed by a real comment or review and
+	// are considered to match every approver and reviewer expression.
+	synthetic bool
+}
+
+// stateComment is the state of attestations made through comments on the
+// pull request, which have no review state of their own.
+const stateComment = "comment"
+
+// commentOnly returns whether the attestation neither approves nor requests
+// changes, i.e. it is a comment on the pull request or a review in the
+// COMMENTED state.  Both are evaluated identically, such that an approval
+// written in a COMMENTED review, e.g. by a maintainer who cannot formally
+// approve, counts just like the same approval written in a comment.
+func (a attestation) commentOnly() bool {
+	if a.state == "" {
+		return true
+	}
+
+	state, _ := ParseReviewState(a.state)
+
+	return state == ReviewStateComment || state == ReviewStateCommented
+}
+
+// mergeTally accumulates the qualifying attestations of a pull request.
+type mergeTally struct {
+	result    map[string][]string
+	approvals int
+	reviews   int
+
+	// latestApproval and latestReview hold the index of the most recent
+	// attestation of each user which matches the approver and reviewer
+	// expressions respectively.  Only these are counted, such that a user who
+	// attests repeatedly is counted once.
+	latestApproval map[string]int
+	latestReview   map[string]int
+
+	// approvedBy lists every user who has provided a qualifying approval,
+	// whether through a comment or a review.
+	approvedBy []string
+}
+
+const (
+	// DefaultMergeableAttempts is how often the pull request is retrieved in
+	// total whilst GitHub is still determining whether it has merge conflicts.
+	DefaultMergeableAttempts = 5
+
+	// DefaultMergeableDelay is the delay between retrievals of the pull
+	// request whilst GitHub is still determining whether it has merge
+	// conflicts.
+	DefaultMergeableDelay = 2 * time.Second
+)
+
+// newMergableOptions applies the provided options on top of the defaults.
+func newMergableOptions(pr *PullRequest, opts ...PullRequestMergableOption) *mergableOptions {
 	mopts := mergableOptions{
-		ghClient:     pr.client,
-		minApprovals: 1,
-		minReviews:   1,
+		ghClient:          pr.client,
+		defaultStateOpen:  true,
+		mergeableAttempts: DefaultMergeableAttempts,
+		mergeableDelay:    DefaultMergeableDelay,
+		minApprovals:      1,
+		minReviews:        1,
 	}
 
 	for _, opt := range opts {
@@ -36,33 +167,454 @@ func (pr *PullRequest) SatisfiesMergeRequirements(ctx context.Context, opts ...P
 			"Reviewed-by: (?P<reviewed_by>.*>)",
 		}
 	}
+	if len(mopts.ignoreAuthors) == 0 {
+		mopts.ignoreAuthors = DefaultIgnoreAuthors
+	}
+
+	return &mopts
+}
+
+// SatisfiesMergeRequirements checks whether the pull request has enough
+// qualifying approvals and reviews to be merged given the provided options.
+func (pr *PullRequest) SatisfiesMergeRequirements(ctx context.Context, opts ...PullRequestMergableOption) (bool, map[string][]string, error) {
+	verdict, err := pr.Verdict(ctx, opts...)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return satisfies(ctx, verdict)
+}
+
+// satisfies logs the tally of the verdict and returns its result if it is
+// mergable.
+func satisfies(ctx context.Context, verdict *MergeVerdict) (bool, map[string][]string, error) {
+	log.G(ctx).Infof("approvers (%d/%d) and reviewers (%d/%d)",
+		verdict.Approvals,
+		verdict.MinApprovals,
+		verdict.Reviews,
+		verdict.MinReviews)
+
+	if err := verdict.Err(); err != nil {
+		return false, nil, err
+	}
+
+	return true, verdict.Result, nil
+}
+
+// Verdict evaluates the merge requirements of the pull request given the
+// provided options.  An error is returned if the pull request does not meet
+// the prerequisites, e.g. its state or labels, or it could not be evaluated,
+// whereas unmet requirements are reported by the verdict.
+func (pr *PullRequest) Verdict(ctx context.Context, opts ...PullRequestMergableOption) (*MergeVerdict, error) {
+	mopts := newMergableOptions(pr, opts...)
+
+	pull, err := pr.checkPrerequisites(ctx, mopts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pr.applyBotPolicy(ctx, mopts, pull); err != nil {
+		return nil, err
+	}
+
+	if err := pr.applyRequiredChecks(ctx, mopts, pull); err != nil {
+		return nil, err
+	}
+
+	if err := pr.applySignoff(ctx, mopts); err != nil {
+		return nil, err
+	}
+
+	attestations, err := pr.listAttestations(ctx, mopts)
+	if err != nil {
+		return nil, err
+	}
+
+	return mopts.verdict(ctx, pull, attestations)
+}
+
+// Err returns an error describing the most significant unmet requirement of
+// the verdict, or nil if the pull request is mergable.
+func (v *MergeVerdict) Err() error {
+	if len(v.ChangesRequested) > 0 {
+		return fmt.Errorf(
+			"pull request has changes requested by %s",
+			strings.Join(v.ChangesRequested, ", "),
+		)
+	}
+
+	if len(v.FailingChecks) > 0 {
+		return fmt.Errorf(
+			"pull request has checks which have not succeeded: %s",
+			strings.Join(v.FailingChecks, ", "),
+		)
+	}
+
+	if len(v.ShortTeams) > 0 {
+		return fmt.Errorf(
+			"pull request does not have enough approvals from %s",
+			strings.Join(v.ShortTeams, ", "),
+		)
+	}
+
+	if len(v.MissingApprovers) > 0 {
+		return fmt.Errorf(
+			"pull request has not been approved by the required approvers %s",
+			strings.Join(v.MissingApprovers, ", "),
+		)
+	}
+
+	if len(v.UnsignedCommits) > 0 {
+		return fmt.Errorf(
+			"pull request has commits which are not signed off by their author: %s",
+			strings.Join(v.UnsignedCommits, ", "),
+		)
+	}
+
+	if !v.Mergable() {
+		return fmt.Errorf(
+			"pull request does not meet the minimum number approvers (%d/%d) and reviewers (%d/%d)",
+			v.Approvals,
+			v.MinApprovals,
+			v.Reviews,
+			v.MinReviews,
+		)
+	}
+
+	return nil
+}
+
+// Markdown renders the verdict as a table of each requirement and whether it
+// is met.
+func (v *MergeVerdict) Markdown() string {
+	var b strings.Builder
+
+	met := func(ok bool) string {
+		if ok {
+			return "✅"
+		}
+
+		return "❌"
+	}
+
+	b.WriteString("| Requirement | Status | Details |\n")
+	b.WriteString("| --- | :---: | --- |\n")
+
+	if v.At != nil {
+		fmt.Fprintf(&b, "| Evaluated as of | ⏪ | %s at %s, skipped: %s |\n", v.At.Format(time.RFC3339), v.HeadSHA, strings.Join(v.Skipped, ", "))
+	}
+
+	if v.Bot {
+		b.WriteString("| Bot policy | ✅ | automated dependency update, only checks are required |\n")
+	}
+
+	fmt.Fprintf(&b, "| Approvals | %s | %d/%d |\n", met(v.Approvals >= v.MinApprovals), v.Approvals, v.MinApprovals)
+	fmt.Fprintf(&b, "| Reviews | %s | %d/%d |\n", met(v.Reviews >= v.MinReviews), v.Reviews, v.MinReviews)
+
+	teams := make([]string, 0, len(v.TeamApprovals))
+	for team := range v.TeamApprovals {
+		teams = append(teams, team)
+	}
+
+	sort.Strings(teams)
+
+	for _, team := range teams {
+		short := ""
+		for _, s := range v.ShortTeams {
+			if strings.HasPrefix(s, team+" ") {
+				short = strings.TrimPrefix(s, team+" ")
+			}
+		}
+
+		details := fmt.Sprintf("%d", v.TeamApprovals[team])
+		if short != "" {
+			details = short
+		}
+
+		fmt.Fprintf(&b, "| Approvals from %s | %s | %s |\n", team, met(short == ""), details)
+	}
 
+	if len(v.MissingApprovers) > 0 {
+		fmt.Fprintf(&b, "| Required approvers | ❌ | %s |\n", strings.Join(v.MissingApprovers, ", "))
+	}
+
+	if len(v.ChangesRequested) > 0 {
+		fmt.Fprintf(&b, "| No changes requested | ❌ | %s |\n", strings.Join(v.ChangesRequested, ", "))
+	}
+
+	if len(v.FailingChecks) > 0 {
+		fmt.Fprintf(&b, "| Checks | ❌ | %s |\n", strings.Join(v.FailingChecks, ", "))
+	}
+
+	if len(v.UnsignedCommits) > 0 {
+		fmt.Fprintf(&b, "| Sign-off | ❌ | %s |\n", strings.Join(v.UnsignedCommits, ", "))
+	}
+
+	return b.String()
+}
+
+// SimulateAttestation evaluates the merge requirements of the pull request as
+// they are now and as they would be if the provided user were to additionally
+// approve and review it with the provided review state.  The simulated
+// attestation is subject to the same eligibility checks as real ones.  No
+// changes are made to the pull request.
+func (pr *PullRequest) SimulateAttestation(ctx context.Context, login, state string, opts ...PullRequestMergableOption) (*MergeVerdict, *MergeVerdict, error) {
+	mopts := newMergableOptions(pr, opts...)
+
+	pull, err := pr.checkPrerequisites(ctx, mopts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := pr.applyBotPolicy(ctx, mopts, pull); err != nil {
+		return nil, nil, err
+	}
+
+	if err := pr.applyRequiredChecks(ctx, mopts, pull); err != nil {
+		return nil, nil, err
+	}
+
+	if err := pr.applySignoff(ctx, mopts); err != nil {
+		return nil, nil, err
+	}
+
+	attestations, err := pr.listAttestations(ctx, mopts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current, err := mopts.verdict(ctx, pull, attestations)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	simulated, err := mopts.verdict(ctx, pull, append(attestations, attestation{
+		login:       login,
+		state:       state,
+		review:      true,
+		synthetic:   true,
+		submittedAt: time.Now(),
+	}))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return current, simulated, nil
+}
+
+// checkPrerequisites retrieves the pull request and checks the requirements
+// which do not depend on approvals or reviews.
+func (pr *PullRequest) checkPrerequisites(ctx context.Context, mopts *mergableOptions) (*github.PullRequest, error) {
 	pull, err := mopts.ghClient.GetPullRequest(ctx, pr.ghOrg, pr.ghRepo, pr.ghPrId)
 	if err != nil || pull == nil {
-		return false, nil, fmt.Errorf("could not get pull request: %w", err)
+		return nil, fmt.Errorf("could not get pull request: %w", err)
+	}
+
+	if !mopts.at.IsZero() {
+		if pull, err = pr.rewind(ctx, mopts, pull); err != nil {
+			return nil, err
+		}
 	}
 
 	// Ignore if state not requested
-	if !mopts.requestsState(*pull.State) {
-		return false, nil, fmt.Errorf("pull request does not match requested state: got '%s' want '%s'", *pull.State, mopts.states)
+	if !mopts.requestsState(pull.GetState()) {
+		return nil, fmt.Errorf("pull request does not match requested state: got '%s' want '%s'", pull.GetState(), mopts.states)
+	}
+
+	if err := CheckBaseBranch(mopts.allowedBaseBranches, pull.GetBase().GetRef()); err != nil {
+		return nil, err
 	}
 
 	// Ignore if labels not requested
 	if !mopts.requestsLabels(pull.Labels) {
-		return false, nil, fmt.Errorf("pull request does not have requested labels: got '%s' want '%s'", pull.Labels, mopts.labels)
+		return nil, fmt.Errorf("pull request does not have requested labels: got '%s' want '%s'", pull.Labels, mopts.labels)
 	}
 
 	// Ignore if only mergeables requested
-	if mopts.noConflicts && !*pull.Mergeable {
-		return false, nil, fmt.Errorf("pull request has merge conflicts")
+	if mopts.noConflicts && !mopts.at.IsZero() {
+		mopts.skip(SkippedConflicts)
+	} else if mopts.noConflicts {
+		if pull, err = pr.awaitMergeable(ctx, mopts, pull); err != nil {
+			return nil, err
+		}
+
+		if !pull.GetMergeable() {
+			return nil, fmt.Errorf("pull request has merge conflicts")
+		}
 	}
 
 	// Ignore drafts
-	if *pull.Draft {
-		return false, nil, fmt.Errorf("pull request is in draft state")
+	if pull.GetDraft() {
+		return nil, fmt.Errorf("pull request is in draft state")
+	}
+
+	return pull, nil
+}
+
+// CheckBaseBranch returns an error if the base branch is not amongst the
+// allowed branches.  An empty list allows every branch.
+func CheckBaseBranch(allowed []string, base string) error {
+	if len(allowed) == 0 || contains(allowed, base) {
+		return nil
 	}
 
-	// Iterate through all the comments for this PR
+	return fmt.Errorf("pull request is based on '%s' which is not an allowed base branch: %s", base, strings.Join(allowed, ", "))
+}
+
+// awaitMergeable returns the pull request once GitHub has determined whether
+// it has merge conflicts.  GitHub computes this in the background and responds
+// with `mergeable: null` until it is done, in which case the pull request is
+// retrieved again after a short delay.
+func (pr *PullRequest) awaitMergeable(ctx context.Context, mopts *mergableOptions, pull *github.PullRequest) (*github.PullRequest, error) {
+	for attempt := 1; pull.Mergeable == nil; attempt++ {
+		if attempt >= mopts.mergeableAttempts {
+			return nil, fmt.Errorf("pull request has not been checked for merge conflicts by GitHub after %d attempts, try again later", attempt)
+		}
+
+		log.G(ctx).
+			WithField("attempt", attempt).
+			WithField("delay", mopts.mergeableDelay).
+			Debug("waiting for GitHub to check the pull request for merge conflicts")
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(mopts.mergeableDelay):
+		}
+
+		var err error
+		if pull, err = mopts.ghClient.GetPullRequest(ctx, pr.ghOrg, pr.ghRepo, pr.ghPrId); err != nil || pull == nil {
+			return nil, fmt.Errorf("could not get pull request: %w", err)
+		}
+	}
+
+	return pull, nil
+}
+
+// applyBotPolicy relaxes the approval and review requirements of automated
+// dependency updates when the bot policy only requires green checks, in which
+// case the statuses and check runs of the head of the pull request are
+// retrieved instead.
+func (pr *PullRequest) applyBotPolicy(ctx context.Context, mopts *mergableOptions, pull *github.PullRequest) error {
+	if err := ValidateBotPolicy(mopts.botPolicy); err != nil {
+		return err
+	}
+
+	if mopts.botPolicy != BotPolicyChecks || !IsBotPullRequest(pull, mopts.botLogins, mopts.botLabels) {
+		return nil
+	}
+
+	log.G(ctx).
+		WithField("author", pull.GetUser().GetLogin()).
+		Info("applying bot policy: requiring green checks only")
+
+	mopts.bot = true
+	mopts.minApprovals = 0
+	mopts.minReviews = 0
+
+	if !mopts.at.IsZero() {
+		mopts.skip(SkippedChecks)
+		return nil
+	}
+
+	failing, err := mopts.ghClient.ListFailingChecks(ctx, pr.ghOrg, pr.ghRepo, pull.GetHead().GetSHA())
+	if err != nil {
+		return fmt.Errorf("could not check bot pull request: %w", err)
+	}
+
+	mopts.failingChecks = failing
+
+	return nil
+}
+
+// applyRequiredChecks retrieves the statuses and check runs of the head of the
+// pull request and records every required one which has not succeeded or is
+// missing altogether.
+func (pr *PullRequest) applyRequiredChecks(ctx context.Context, mopts *mergableOptions, pull *github.PullRequest) error {
+	if len(mopts.requiredChecks) == 0 {
+		return nil
+	}
+
+	if !mopts.at.IsZero() {
+		mopts.skip(SkippedChecks)
+		return nil
+	}
+
+	states, err := mopts.ghClient.ListCheckStates(ctx, pr.ghOrg, pr.ghRepo, pull.GetHead().GetSHA())
+	if err != nil {
+		return fmt.Errorf("could not list checks: %w", err)
+	}
+
+	for _, name := range mopts.requiredChecks {
+		state, ok := states[name]
+		if !ok {
+			state = "missing"
+		} else if state == "success" {
+			continue
+		}
+
+		// The check may have already been recorded by the bot policy.
+		failing := fmt.Sprintf("%s (%s)", name, state)
+		if !contains(mopts.failingChecks, failing) {
+			mopts.failingChecks = append(mopts.failingChecks, failing)
+		}
+	}
+
+	return nil
+}
+
+// applySignoff records every commit of the pull request which does not carry
+// a Signed-off-by trailer with the email address of its author.
+func (pr *PullRequest) applySignoff(ctx context.Context, mopts *mergableOptions) error {
+	if !mopts.requireSignoff {
+		return nil
+	}
+
+	// A historical evaluation has already retrieved the commits until then.
+	commits := mopts.commits
+	if mopts.at.IsZero() {
+		var err error
+		commits, err = mopts.ghClient.GetPullRequestCommits(ctx, pr.ghOrg, pr.ghRepo, pr.ghPrId)
+		if err != nil {
+			return fmt.Errorf("could not list commits: %w", err)
+		}
+	}
+
+	for _, commit := range commits {
+		if !signedOffBy(commit.GetCommit().GetMessage(), commit.GetCommit().GetAuthor().GetEmail()) {
+			sha := commit.GetSHA()
+			if len(sha) > 12 {
+				sha = sha[:12]
+			}
+
+			mopts.unsignedCommits = append(mopts.unsignedCommits, sha)
+		}
+	}
+
+	return nil
+}
+
+// signedOffBy checks whether the commit message carries a Signed-off-by
+// trailer with the provided email address.
+func signedOffBy(message, email string) bool {
+	for _, line := range strings.Split(message, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || !strings.EqualFold(key, "Signed-off-by") {
+			continue
+		}
+
+		if email != "" && strings.Contains(strings.ToLower(value), "<"+strings.ToLower(email)+">") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listAttestations returns all comments followed by all reviews of the pull
+// request as attestations, limited to those made until the point in time of a
+// historical evaluation.
+func (pr *PullRequest) listAttestations(ctx context.Context, mopts *mergableOptions) ([]attestation, error) {
 	comments, err := mopts.ghClient.ListPullRequestComments(
 		ctx,
 		pr.ghOrg,
@@ -70,130 +622,422 @@ func (pr *PullRequest) SatisfiesMergeRequirements(ctx context.Context, opts ...P
 		pr.ghPrId,
 	)
 	if err != nil {
-		return false, nil, fmt.Errorf("could not get pull request comments: %w", err)
+		return nil, fmt.Errorf("could not get pull request comments: %w", err)
 	}
 
-	res := make(map[string][]string)
-	prApprovals := 0
-	prReviews := 0
+	reviews, err := mopts.ghClient.ListPullRequestReviews(ctx, pr.ghOrg, pr.ghRepo, pr.ghPrId)
+	if err != nil {
+		return nil, fmt.Errorf("could not list pull request reviews: %w", err)
+	}
+
+	var attestations []attestation
 
 	for _, c := range comments {
-		if ok, matches := mopts.requestsApproverRegex(*c.Body); ok {
-			if mopts.requestsApproverTeam(ctx, *pull, *c.User.Login) {
-				for k, v := range matches {
-					if _, ok := res[k]; !ok {
-						res[k] = make([]string, 0)
-					}
-					res[k] = append(res[k], v)
-					prApprovals++
+		// Comments without an author, e.g. of deleted users, cannot be
+		// attributed to anyone.
+		if c.GetUser().GetLogin() == "" || !mopts.before(c.GetCreatedAt().Time) {
+			continue
+		}
+
+		if IsIgnoredAuthor(c.GetUser().GetLogin(), mopts.ignoreAuthors) {
+			log.G(ctx).
+				WithField("author", c.GetUser().GetLogin()).
+				Debug("skipping comment of ignored author")
+			continue
+		}
+
+		attestations = append(attestations, attestation{
+			id:          c.GetID(),
+			login:       c.GetUser().GetLogin(),
+			body:        c.GetBody(),
+			state:       stateComment,
+			submittedAt: c.GetCreatedAt().Time,
+		})
+	}
+
+	for _, r := range reviews {
+		// Reviews without an author or state cannot be attributed or evaluated
+		// and pending reviews have not been submitted yet, so none of them
+		// count regardless of the accepted review states.
+		if r.GetUser().GetLogin() == "" || r.GetState() == "" || strings.EqualFold(r.GetState(), ReviewStatePending) {
+			continue
+		}
+
+		if !mopts.before(r.GetSubmittedAt().Time) {
+			continue
+		}
+
+		if IsIgnoredAuthor(r.GetUser().GetLogin(), mopts.ignoreAuthors) {
+			log.G(ctx).
+				WithField("author", r.GetUser().GetLogin()).
+				Debug("skipping review of ignored author")
+			continue
+		}
+
+		attestations = append(attestations, attestation{
+			id:          r.GetID(),
+			login:       r.GetUser().GetLogin(),
+			body:        r.GetBody(),
+			state:       r.GetState(),
+			review:      true,
+			submittedAt: r.GetSubmittedAt().Time,
+		})
+	}
+
+	return attestations, nil
+}
+
+// verdict tallies the provided attestations and compares the result against
+// the minimum number of approvals and reviews.
+func (mopts *mergableOptions) verdict(ctx context.Context, pull *github.PullRequest, attestations []attestation) (*MergeVerdict, error) {
+	tally := mergeTally{
+		result: make(map[string][]string),
+	}
+
+	tally.latestApproval, tally.latestReview = mopts.latest(attestations)
+
+	var qualified []Attestation
+
+	for i, a := range attestations {
+		counted := tally.approvals + tally.reviews
+
+		if err := mopts.qualify(ctx, pull, i, a, &tally); err != nil {
+			return nil, err
+		}
+
+		if !a.synthetic && tally.approvals+tally.reviews > counted {
+			qualified = append(qualified, a.export())
+		}
+	}
+
+	verdict := MergeVerdict{
+		Approvals:    tally.approvals,
+		MinApprovals: mopts.minApprovals,
+		Reviews:      tally.reviews,
+		MinReviews:   mopts.minReviews,
+		Result:       tally.result,
+		Bot:          mopts.bot,
+		HeadSHA:      pull.GetHead().GetSHA(),
+		Attestations: qualified,
+	}
+
+	if !mopts.at.IsZero() {
+		verdict.At = &mopts.at
+		verdict.Skipped = mopts.skipped
+	}
+
+	if len(mopts.failingChecks) > 0 {
+		verdict.FailingChecks = mopts.failingChecks
+		verdict.Unmet = append(verdict.Unmet, fmt.Sprintf("checks (%s)", strings.Join(mopts.failingChecks, ", ")))
+	}
+
+	if len(mopts.unsignedCommits) > 0 {
+		verdict.UnsignedCommits = mopts.unsignedCommits
+		verdict.Unmet = append(verdict.Unmet, fmt.Sprintf("signoff (%s)", strings.Join(mopts.unsignedCommits, ", ")))
+	}
+
+	if tally.approvals < mopts.minApprovals {
+		verdict.Unmet = append(verdict.Unmet, fmt.Sprintf("approvals (%d/%d)", tally.approvals, mopts.minApprovals))
+	}
+	if tally.reviews < mopts.minReviews {
+		verdict.Unmet = append(verdict.Unmet, fmt.Sprintf("reviews (%d/%d)", tally.reviews, mopts.minReviews))
+	}
+
+	if err := mopts.perTeamApprovals(ctx, &verdict, tally.approvedBy); err != nil {
+		return nil, err
+	}
+
+	if missing := mopts.missingApprovers(attestations, tally.latestApproval); len(missing) > 0 {
+		verdict.MissingApprovers = missing
+		verdict.Unmet = append(verdict.Unmet, fmt.Sprintf("approvals from required approvers (%s)", strings.Join(missing, ", ")))
+	}
+
+	if !mopts.ignoreChangesRequested || mopts.blockOnChangesRequested {
+		blocking, err := mopts.changesRequested(ctx, pull, attestations)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(blocking) > 0 {
+			verdict.ChangesRequested = blocking
+			verdict.Unmet = append(verdict.Unmet, fmt.Sprintf("changes requested by %s", strings.Join(blocking, ", ")))
+		}
+	}
+
+	return &verdict, nil
+}
+
+// perTeamApprovals attributes the approvals of the provided users to each
+// team which requires a minimum number of approvals and records the teams
+// which fall short in the verdict.  Users who are members of multiple teams
+// count towards each of them.
+func (mopts *mergableOptions) perTeamApprovals(ctx context.Context, verdict *MergeVerdict, approvedBy []string) error {
+	if len(mopts.perTeamMinApprovals) == 0 {
+		return nil
+	}
+
+	teams := make([]string, 0, len(mopts.perTeamMinApprovals))
+	for team := range mopts.perTeamMinApprovals {
+		teams = append(teams, team)
+	}
+
+	sort.Strings(teams)
+
+	verdict.TeamApprovals = make(map[string]int)
+
+	for _, team := range teams {
+		verdict.TeamApprovals[team] = 0
+
+		members, err := mopts.membersOfTeam(ctx, team)
+		if err != nil {
+			return fmt.Errorf("could not check team approver: %w", err)
+		}
+
+		for _, login := range approvedBy {
+			for _, member := range members {
+				if strings.EqualFold(member, login) {
+					verdict.TeamApprovals[team]++
+					break
 				}
 			}
 		}
 
-		if ok, matches := mopts.requestsReviewerRegex(*c.Body); ok {
-			if mopts.requestsReviewerTeam(ctx, *pull, *c.User.Login) {
-				for k, v := range matches {
-					if _, ok := res[k]; !ok {
-						res[k] = make([]string, 0)
-					}
-					res[k] = append(res[k], v)
-					prReviews++
-				}
+		if min := mopts.perTeamMinApprovals[team]; verdict.TeamApprovals[team] < min {
+			short := fmt.Sprintf("%s (%d/%d)", team, verdict.TeamApprovals[team], min)
+			verdict.ShortTeams = append(verdict.ShortTeams, short)
+			verdict.Unmet = append(verdict.Unmet, "approvals from "+short)
+		}
+	}
+
+	return nil
+}
+
+// missingApprovers returns the required approvers whose most recent
+// attestation matching the approver expressions, given the index of the latest
+// one of each user, does not approve the pull request, i.e. it neither only
+// comments nor has an accepted approve state.  Required approvers are named
+// explicitly and therefore do not need to be eligible approvers otherwise.
+func (mopts *mergableOptions) missingApprovers(attestations []attestation, latest map[string]int) []string {
+	var missing []string
+
+	for _, required := range mopts.requiredApprovers {
+		approved := false
+
+		for login, i := range latest {
+			if !strings.EqualFold(login, required) {
+				continue
+			}
+
+			a := attestations[i]
+			if a.commentOnly() || mopts.requestsApproveState(a.state) {
+				approved = true
 			}
 		}
+
+		if !approved && !contains(missing, required) {
+			missing = append(missing, required)
+		}
 	}
 
-	// Iterate through all the reviews for this PR
-	reviews, err := mopts.ghClient.ListPullRequestReviews(ctx, pr.ghOrg, pr.ghRepo, pr.ghPrId)
-	if err != nil {
-		return false, nil, fmt.Errorf("could not list pull request reviews: %w", err)
+	return missing
+}
+
+// changesRequested returns the eligible reviewers, or any user if changes
+// requested by anyone block the pull request, whose most recent review
+// requests changes.  Only reviews which approve, request changes or have been
+// dismissed are considered, such that a later comment does not lift a request
+// for changes but a later approval from the same user does.
+func (mopts *mergableOptions) changesRequested(ctx context.Context, pull *github.PullRequest, attestations []attestation) ([]string, error) {
+	var reviews []attestation
+	for _, a := range attestations {
+		if a.review {
+			reviews = append(reviews, a)
+		}
 	}
 
-	var existingReviewers []string
-	var existingApprovers []string
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return reviews[i].submittedAt.Before(reviews[j].submittedAt)
+	})
+
+	latest := make(map[string]string)
+	var logins []string
 
 	for _, r := range reviews {
-		if ok, matches := mopts.requestsApproverRegex(*r.Body); ok {
-			if mopts.requestsApproverTeam(ctx, *pull, *r.User.Login) {
-				if !mopts.requestsApproveState(*r.State) {
-					continue
-				}
+		state, _ := ParseReviewState(r.state)
 
-				var alreadyApproved bool
-				for _, approver := range existingApprovers {
-					if strings.Contains(approver, *r.User.Login) {
-						alreadyApproved = true
-						break
-					}
-				}
+		switch state {
+		case ReviewStateApproved, ReviewStateChangesRequested, ReviewStateDismissed:
+		default:
+			continue
+		}
 
-				if !alreadyApproved {
-					for k, v := range matches {
-						if _, ok := res[k]; !ok {
-							res[k] = make([]string, 0)
-						}
-						res[k] = append(res[k], v)
-						prApprovals++
-						existingApprovers = append(existingApprovers, *r.User.Login)
-					}
-				}
+		if _, ok := latest[r.login]; !ok {
+			logins = append(logins, r.login)
+		}
+
+		latest[r.login] = state
+	}
+
+	var blocking []string
+
+	for _, login := range logins {
+		if latest[login] != ReviewStateChangesRequested {
+			continue
+		}
+
+		if mopts.blockOnChangesRequested {
+			blocking = append(blocking, login)
+			continue
+		}
+
+		isApprover, err := mopts.requestsApproverTeam(ctx, *pull, login)
+		if err != nil {
+			return nil, fmt.Errorf("could not check approver: %w", err)
+		}
+
+		isReviewer, err := mopts.requestsReviewerTeam(ctx, *pull, login)
+		if err != nil {
+			return nil, fmt.Errorf("could not check reviewer: %w", err)
+		}
+
+		if isApprover || isReviewer {
+			blocking = append(blocking, login)
+		}
+	}
+
+	return blocking, nil
+}
+
+// latest returns the index of the most recent attestation of each user which
+// matches the approver expressions and of the one which matches the reviewer
+// expressions.  Synthetic attestations match both.
+func (mopts *mergableOptions) latest(attestations []attestation) (approvals map[string]int, reviews map[string]int) {
+	approvals = make(map[string]int)
+	reviews = make(map[string]int)
+
+	record := func(latest map[string]int, i int) {
+		a := attestations[i]
+		if j, ok := latest[a.login]; ok && attestations[j].submittedAt.After(a.submittedAt) {
+			return
+		}
+
+		latest[a.login] = i
+	}
+
+	for i, a := range attestations {
+		if ok, matches := mopts.requestsApproverRegex(a.body); a.synthetic || (ok && len(matches) > 0) {
+			record(approvals, i)
+		}
+
+		if ok, matches := mopts.requestsReviewerRegex(a.body); a.synthetic || (ok && len(matches) > 0) {
+			record(reviews, i)
+		}
+	}
+
+	return approvals, reviews
+}
+
+// isLatest returns whether the attestation with the provided index is the most
+// recent one of the user amongst the latest attestations.
+func isLatest(latest map[string]int, login string, i int) bool {
+	j, ok := latest[login]
+	return ok && j == i
+}
+
+// qualify determines whether the attestation with the provided index counts as
+// an approval and/or a review and records it in the tally if so.  An
+// attestation must match the approver or reviewer expressions, be the most
+// recent one of its user to do so, be made by an eligible user and, unless it
+// only comments, have an accepted review state.
+func (mopts *mergableOptions) qualify(ctx context.Context, pull *github.PullRequest, i int, a attestation, tally *mergeTally) error {
+	ok, matches := mopts.requestsApproverRegex(a.body)
+	if a.synthetic {
+		ok, matches = true, syntheticParams(mopts.approverComments, a.login)
+	}
+
+	if ok && isLatest(tally.latestApproval, a.login, i) {
+		isApprover, err := mopts.requestsApproverTeam(ctx, *pull, a.login)
+		if err != nil {
+			return fmt.Errorf("could not check approver: %w", err)
+		}
+
+		if isApprover {
+			if !a.commentOnly() && !mopts.requestsApproveState(a.state) {
+				return nil
+			}
+
+			for k, v := range matches {
+				tally.result[k] = append(tally.result[k], v)
+				tally.approvals++
+			}
+
+			if len(matches) > 0 && !contains(tally.approvedBy, a.login) {
+				tally.approvedBy = append(tally.approvedBy, a.login)
 			}
 		}
+	}
 
-		if ok, matches := mopts.requestsReviewerRegex(*r.Body); ok {
-			if mopts.requestsReviewerTeam(ctx, *pull, *r.User.Login) {
-				if !mopts.requestsReviewState(*r.State) {
-					continue
-				}
+	ok, matches = mopts.requestsReviewerRegex(a.body)
+	if a.synthetic {
+		ok, matches = true, syntheticParams(mopts.reviewerComments, a.login)
+	}
 
-				var alreadyReviewed bool
-				for _, reviewer := range existingReviewers {
-					if strings.Contains(reviewer, *r.User.Login) {
-						alreadyReviewed = true
-						break
-					}
-				}
+	if ok && isLatest(tally.latestReview, a.login, i) {
+		isReviewer, err := mopts.requestsReviewerTeam(ctx, *pull, a.login)
+		if err != nil {
+			return fmt.Errorf("could not check reviewer: %w", err)
+		}
 
-				if !alreadyReviewed {
-					for k, v := range matches {
-						if _, ok := res[k]; !ok {
-							res[k] = make([]string, 0)
-						}
-						res[k] = append(res[k], v)
-						prReviews++
-						existingReviewers = append(existingReviewers, *r.User.Login)
-					}
-				}
+		if isReviewer {
+			if !a.commentOnly() && !mopts.requestsReviewState(a.state) {
+				return nil
+			}
+
+			for k, v := range matches {
+				tally.result[k] = append(tally.result[k], v)
+				tally.reviews++
 			}
 		}
 	}
 
-	fmt.Printf("approvers (%d/%d) and reviewers (%d/%d)\n",
-		prApprovals,
-		mopts.minApprovals,
-		prReviews,
-		mopts.minReviews)
+	return nil
+}
 
-	if prApprovals < mopts.minApprovals || prReviews < mopts.minReviews {
-		return false, nil, fmt.Errorf(
-			"pull request does not meet the minimum number approvers (%d/%d) and reviewers (%d/%d)",
-			prApprovals,
-			mopts.minApprovals,
-			prReviews,
-			mopts.minReviews,
-		)
+// contains checks whether the list contains exactly the provided entry.
+func contains(list []string, entry string) bool {
+	for _, e := range list {
+		if e == entry {
+			return true
+		}
+	}
+
+	return false
+}
+
+// syntheticParams returns the matches of a synthetic attestation, where every
+// named group of the provided expressions is set to the login.
+func syntheticParams(regExs []string, login string) map[string]string {
+	params := make(map[string]string)
+
+	for _, regEx := range regExs {
+		for i, name := range regexp.MustCompile(regEx).SubexpNames() {
+			if i > 0 && name != "" {
+				params[name] = login
+			}
+		}
 	}
 
-	return true, res, nil
+	return params
 }
 
-// requestsState checks whether the source requests this particular state
+// requestsState checks whether the source requests this particular state.  If
+// no states are set, only "open" is requested unless the default has been
+// disabled with WithDefaultStateOpen, in which case any state is.
 func (opts *mergableOptions) requestsState(state string) bool {
 	ret := false
 
-	// if there are no set states, assume only "open" states
 	if len(opts.states) == 0 {
-		ret = state == "open"
+		ret = !opts.defaultStateOpen || state == "open"
 	} else {
 		for _, s := range opts.states {
 			if s == state {
@@ -216,34 +1060,12 @@ func (opts *mergableOptions) requestsState(state string) bool {
 
 // requestsApproveState checks whether the PR approver matches the desired state
 func (opts *mergableOptions) requestsApproveState(state string) bool {
-	if len(opts.approveStates) == 0 {
-		return true
-	}
-
-	state = strings.ToLower(state)
-	for _, s := range opts.approveStates {
-		if state == strings.ToLower(s) {
-			return true
-		}
-	}
-
-	return false
+	return matchesReviewState(opts.approveStates, state)
 }
 
 // requestsReviewState checks whether the PR review matches the desired state
 func (opts *mergableOptions) requestsReviewState(state string) bool {
-	if len(opts.reviewStates) == 0 {
-		return true
-	}
-
-	state = strings.ToLower(state)
-	for _, s := range opts.reviewStates {
-		if state == strings.ToLower(s) {
-			return true
-		}
-	}
-
-	return false
+	return matchesReviewState(opts.reviewStates, state)
 }
 
 // requestsLabels checks whether the source requests these set of labels
@@ -295,19 +1117,22 @@ func (opts *mergableOptions) requestsReviewerRegex(comment string) (bool, map[st
 }
 
 // requestsReviewerTeam determines if the source requests this reviewer team
-func (opts *mergableOptions) requestsReviewerTeam(ctx context.Context, pr github.PullRequest, username string) bool {
+func (opts *mergableOptions) requestsReviewerTeam(ctx context.Context, pr github.PullRequest, username string) (bool, error) {
 	if !opts.noRespectReviewers {
-		return true
+		return true, nil
 	}
 
 	// Check the named approver teams part of the input to this resource
 	for _, t := range opts.reviewerTeams {
-		if ok, _ := opts.ghClient.UserMemberOfTeam(ctx, username, t); ok {
-			return true
+		ok, err := opts.userMemberOfTeam(ctx, username, t)
+		if err != nil {
+			return false, err
+		} else if ok {
+			return true, nil
 		}
 	}
 
-	return false
+	return false, nil
 }
 
 // requestsApproverRegex determines if the source requests this approver regex
@@ -327,23 +1152,68 @@ func (opts *mergableOptions) requestsApproverRegex(comment string) (bool, map[st
 }
 
 // requestsApproverTeam determines if the source requests this approver team
-func (opts *mergableOptions) requestsApproverTeam(ctx context.Context, pr github.PullRequest, username string) bool {
+func (opts *mergableOptions) requestsApproverTeam(ctx context.Context, pr github.PullRequest, username string) (bool, error) {
 	if !opts.noRespectAssignees {
 		for _, assignee := range pr.Assignees {
-			if username == *assignee.Login {
-				return true
+			if username == assignee.GetLogin() {
+				return true, nil
 			}
 		}
 	}
 
 	// Check the named approver teams part of the input to this resource
 	for _, t := range opts.approverTeams {
-		if ok, _ := opts.ghClient.UserMemberOfTeam(ctx, username, t); ok {
-			return true
+		ok, err := opts.userMemberOfTeam(ctx, username, t)
+		if err != nil {
+			return false, err
+		} else if ok {
+			return true, nil
 		}
 	}
 
-	return false
+	return false, nil
+}
+
+// membersOfTeam returns the members of the provided team.  Teams which are
+// not visible to the token result in an error unless unreadable teams should be
+// ignored, in which case a warning is logged once per team and the team is
+// considered to have no members.
+func (opts *mergableOptions) membersOfTeam(ctx context.Context, team string) ([]string, error) {
+	members, err := opts.ghClient.MembersOfTeam(ctx, team)
+	if errors.Is(err, ghapi.ErrTeamNotVisible) && opts.ignoreUnreadableTeams {
+		if opts.unreadableTeams == nil {
+			opts.unreadableTeams = make(map[string]struct{})
+		}
+
+		if _, ok := opts.unreadableTeams[team]; !ok {
+			log.G(ctx).
+				WithField("team", team).
+				Warnf("ignoring unreadable team: %s", err)
+			opts.unreadableTeams[team] = struct{}{}
+		}
+
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// userMemberOfTeam checks whether the user is a member of the provided team.
+func (opts *mergableOptions) userMemberOfTeam(ctx context.Context, username, team string) (bool, error) {
+	members, err := opts.membersOfTeam(ctx, team)
+	if err != nil {
+		return false, err
+	}
+
+	for _, member := range members {
+		if strings.EqualFold(member, username) {
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
 
 // getParams parses the provided regular expression which has identifiers and
@@ -5,31 +5,87 @@
 
 package ghpr
 
-import "github.com/unikraft/governance/internal/ghapi"
+import (
+	"time"
+
+	"github.com/google/go-github/v63/github"
+
+	"github.com/unikraft/governance/internal/ghapi"
+)
 
 type mergableOptions struct {
-	approverComments   []string
-	approverTeams      []string
-	approveStates      []string
-	ignoreLabels       []string
-	ignoreStates       []string
-	labels             []string
-	minApprovals       int
-	minReviews         int
-	noConflicts        bool
-	noDraft            bool
-	noRespectAssignees bool
-	noRespectReviewers bool
-	reviewerComments   []string
-	reviewerTeams      []string
-	reviewStates       []string
-	states             []string
-
-	ghClient *ghapi.GithubClient
+	at                      time.Time
+	allowedBaseBranches     []string
+	approverComments        []string
+	approverTeams           []string
+	approveStates           []string
+	blockOnChangesRequested bool
+	botLabels               []string
+	botLogins               []string
+	botPolicy               string
+	defaultStateOpen        bool
+	ignoreAuthors           []string
+	ignoreChangesRequested  bool
+	ignoreLabels            []string
+	ignoreStates            []string
+	ignoreUnreadableTeams   bool
+	labels                  []string
+	minApprovals            int
+	mergeableAttempts       int
+	mergeableDelay          time.Duration
+	minReviews              int
+	noConflicts             bool
+	noDraft                 bool
+	noRespectAssignees      bool
+	noRespectReviewers      bool
+	perTeamMinApprovals     map[string]int
+	requireSignoff          bool
+	requiredApprovers       []string
+	requiredChecks          []string
+	reviewerComments        []string
+	reviewerTeams           []string
+	reviewStates            []string
+	states                  []string
+
+	ghClient        *ghapi.GithubClient
+	unreadableTeams map[string]struct{}
+
+	// bot and failingChecks are set once the bot policy has been applied to
+	// the pull request.
+	bot           bool
+	failingChecks []string
+
+	// unsignedCommits is set once the commits of the pull request have been
+	// checked for their sign-off.
+	unsignedCommits []string
+
+	// commits and skipped are set once the pull request has been rewound to
+	// the point in time of a historical evaluation.
+	commits []*github.RepositoryCommit
+	skipped []string
 }
 
 type PullRequestMergableOption func(*mergableOptions)
 
+// WithAt evaluates the pull request as it was at the provided point in time:
+// only comments and reviews made until then are considered and its state,
+// labels and head are reconstructed from its timeline and commits.  Merge
+// conflicts and checks cannot be reconstructed and are skipped.
+func WithAt(at time.Time) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		opts.at = at
+	}
+}
+
+// WithAllowedBaseBranches sets the branches which the pull request may be
+// merged into.  A pull request based on any other branch is not mergable.  By
+// default, every branch is allowed.
+func WithAllowedBaseBranches(branches ...string) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		opts.allowedBaseBranches = branches
+	}
+}
+
 // WithApproverComments sets the regular expression that an approver writes.
 func WithApproverComments(approverComments ...string) PullRequestMergableOption {
 	return func(opts *mergableOptions) {
@@ -65,6 +121,80 @@ func WithApproveStates(approveStates ...string) PullRequestMergableOption {
 	}
 }
 
+// WithBlockOnChangesRequested blocks the pull request whilst the most recent
+// review of any user requests changes, regardless of whether the user is an
+// eligible approver or reviewer.  It takes precedence over
+// WithIgnoreChangesRequested.
+func WithBlockOnChangesRequested(blockOnChangesRequested bool) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		opts.blockOnChangesRequested = blockOnChangesRequested
+	}
+}
+
+// WithBotLabels sets the labels which mark a pull request as an automated
+// dependency update.
+func WithBotLabels(botLabels ...string) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		if opts.botLabels == nil {
+			opts.botLabels = []string{}
+		}
+
+		opts.botLabels = append(opts.botLabels, botLabels...)
+	}
+}
+
+// WithBotLogins sets the authors whose pull requests are automated dependency
+// updates.
+func WithBotLogins(botLogins ...string) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		if opts.botLogins == nil {
+			opts.botLogins = []string{}
+		}
+
+		opts.botLogins = append(opts.botLogins, botLogins...)
+	}
+}
+
+// WithBotPolicy sets the policy which applies to automated dependency updates
+// instead of the approval and review requirements, see BotPolicies.
+func WithBotPolicy(botPolicy string) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		opts.botPolicy = botPolicy
+	}
+}
+
+// WithDefaultStateOpen sets whether only open pull requests are considered
+// when no states are provided with WithStates, which is the default.  When
+// disabled, pull requests in any state are considered instead, such that
+// callers which only read do not have to enumerate every state.  States
+// provided with WithIgnoreStates are excluded in either case.
+func WithDefaultStateOpen(defaultStateOpen bool) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		opts.defaultStateOpen = defaultStateOpen
+	}
+}
+
+// WithIgnoreAuthors sets the patterns of the logins whose comments and
+// reviews are skipped, see IsIgnoredAuthor.  Without any, DefaultIgnoreAuthors
+// applies such that bots cannot approve or review a pull request.
+func WithIgnoreAuthors(logins ...string) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		if opts.ignoreAuthors == nil {
+			opts.ignoreAuthors = []string{}
+		}
+
+		opts.ignoreAuthors = append(opts.ignoreAuthors, logins...)
+	}
+}
+
+// WithIgnoreChangesRequested disables the rule which blocks the pull request
+// whilst an eligible reviewer's most recent review requests changes.
+func WithIgnoreChangesRequested(ignoreChangesRequested bool) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		opts.ignoreChangesRequested = ignoreChangesRequested
+	}
+}
+
 // WithIgnoreLabels sets the ignore the PR if it has any of these labels.
 func WithIgnoreLabels(ignoreLabels ...string) PullRequestMergableOption {
 	return func(opts *mergableOptions) {
@@ -87,6 +217,15 @@ func WithIgnoreStates(ignoreStates ...string) PullRequestMergableOption {
 	}
 }
 
+// WithIgnoreUnreadableTeams sets whether approver and reviewer teams which
+// are not visible to the token should be skipped rather than failing the
+// check.
+func WithIgnoreUnreadableTeams(ignoreUnreadableTeams bool) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		opts.ignoreUnreadableTeams = ignoreUnreadableTeams
+	}
+}
+
 // WithLabels sets the the PR must have these labels to be considered
 // mergable.
 func WithLabels(labels ...string) PullRequestMergableOption {
@@ -115,6 +254,16 @@ func WithMinReviews(minReviews int) PullRequestMergableOption {
 	}
 }
 
+// WithMergeableRetry sets how often and how long apart the pull request is
+// retrieved whilst GitHub is still determining whether it has merge conflicts,
+// which only matters with WithNoConflicts.
+func WithMergeableRetry(attempts int, delay time.Duration) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		opts.mergeableAttempts = attempts
+		opts.mergeableDelay = delay
+	}
+}
+
 // WithNoConflicts sets the pull request must not have any conflicts.
 func WithNoConflicts(noConflicts bool) PullRequestMergableOption {
 	return func(opts *mergableOptions) {
@@ -145,6 +294,57 @@ func WithNoRespectReviewers(noRespectReviewers bool) PullRequestMergableOption {
 	}
 }
 
+// WithPerTeamMinApprovals sets the minimum number of approvals which must
+// come from members of each of the provided teams, e.g. "@unikraft/sig-net",
+// in addition to the overall minimum number of approvals.
+func WithPerTeamMinApprovals(perTeamMinApprovals map[string]int) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		if opts.perTeamMinApprovals == nil {
+			opts.perTeamMinApprovals = make(map[string]int)
+		}
+
+		for team, min := range perTeamMinApprovals {
+			opts.perTeamMinApprovals[team] = min
+		}
+	}
+}
+
+// WithRequireSignoff sets whether every commit of the pull request must carry
+// a Signed-off-by trailer of its author as per the Developer Certificate of
+// Origin (DCO).
+func WithRequireSignoff(requireSignoff bool) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		opts.requireSignoff = requireSignoff
+	}
+}
+
+// WithRequiredApprovers sets the users who must each approve the pull request
+// in addition to the minimum number of approvals, e.g. a security lead.  The
+// most recent attestation of each user which matches the approver comments
+// must approve the pull request, regardless of whether the user is otherwise
+// an eligible approver.
+func WithRequiredApprovers(logins ...string) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		if opts.requiredApprovers == nil {
+			opts.requiredApprovers = []string{}
+		}
+
+		opts.requiredApprovers = append(opts.requiredApprovers, logins...)
+	}
+}
+
+// WithRequiredChecks sets the names of the commit statuses and check runs
+// which must have succeeded on the head of the pull request.
+func WithRequiredChecks(requiredChecks ...string) PullRequestMergableOption {
+	return func(opts *mergableOptions) {
+		if opts.requiredChecks == nil {
+			opts.requiredChecks = []string{}
+		}
+
+		opts.requiredChecks = append(opts.requiredChecks, requiredChecks...)
+	}
+}
+
 // WithReviewerComments sets the regular expression that a reviewer writes.
 func WithReviewerComments(reviewerComments ...string) PullRequestMergableOption {
 	return func(opts *mergableOptions) {
@@ -180,7 +380,7 @@ func WithReviewStates(reviewStates ...string) PullRequestMergableOption {
 }
 
 // WithStates sets the consider the PR mergable if it has one of these supplied
-// states.
+// states.  Without any, the default of WithDefaultStateOpen applies.
 func WithStates(states ...string) PullRequestMergableOption {
 	return func(opts *mergableOptions) {
 		if opts.states == nil {
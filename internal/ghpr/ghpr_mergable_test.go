@@ -0,0 +1,1165 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+	"github.com/sirupsen/logrus"
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+// newTestPullRequest returns a PullRequest which is backed by a fake GitHub
+// API server serving the provided handler.
+func newTestPullRequest(t *testing.T, handler http.Handler) *PullRequest {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := ghapi.NewGithubClient(context.Background(), "token", false, srv.URL)
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	return &PullRequest{
+		client: client,
+		ghOrg:  "unikraft",
+		ghRepo: "unikraft",
+		ghPrId: 1,
+	}
+}
+
+func TestSatisfiesMergeRequirementsUnreadableTeams(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number":1,"state":"open","draft":false}`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"body":"Approved-by: Jane Doe <jane@unikraft.io>\nReviewed-by: Jane Doe <jane@unikraft.io>","user":{"login":"jane"}}]`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/api/v3/orgs/unikraft/teams/secret/members", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Not Found"}`)
+	})
+	mux.HandleFunc("/api/v3/orgs/unikraft/teams/maintainers/members", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"login":"jane"}]`)
+	})
+
+	tests := []struct {
+		name   string
+		ignore bool
+		wantOk bool
+	}{
+		{
+			name:   "unreadable team fails",
+			ignore: false,
+			wantOk: false,
+		},
+		{
+			name:   "unreadable team ignored",
+			ignore: true,
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := newTestPullRequest(t, mux)
+
+			ok, _, err := pr.SatisfiesMergeRequirements(context.Background(),
+				WithApproverTeams("@unikraft/secret", "@unikraft/maintainers"),
+				WithNoRespectAssignees(true),
+				WithIgnoreUnreadableTeams(tt.ignore),
+			)
+			if ok != tt.wantOk {
+				t.Fatalf("SatisfiesMergeRequirements() = %v, want %v (err: %v)", ok, tt.wantOk, err)
+			}
+
+			if !tt.wantOk && !errors.Is(err, ghapi.ErrTeamNotVisible) {
+				t.Errorf("expected team not visible error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestSimulateAttestation(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number":1,"state":"open","draft":false,"assignees":[{"login":"alice"}]}`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"body":"Reviewed-by: Bob <bob@unikraft.io>","user":{"login":"bob"}}]`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/api/v3/orgs/unikraft/teams/approvers/members", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"login":"jane"}]`)
+	})
+
+	tests := []struct {
+		name              string
+		as                string
+		noRespectAssignee bool
+		wantSimulated     bool
+		wantUnmet         []string
+	}{
+		{
+			name:          "member of approver team",
+			as:            "jane",
+			wantSimulated: true,
+		},
+		{
+			name:          "assignee",
+			as:            "alice",
+			wantSimulated: true,
+		},
+		{
+			name:              "assignee not respected",
+			as:                "alice",
+			noRespectAssignee: true,
+			wantSimulated:     false,
+			wantUnmet:         []string{"approvals (0/1)"},
+		},
+		{
+			name:          "not eligible",
+			as:            "mallory",
+			wantSimulated: false,
+			wantUnmet:     []string{"approvals (0/1)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := newTestPullRequest(t, mux)
+
+			current, simulated, err := pr.SimulateAttestation(context.Background(), tt.as, "approve",
+				WithApproverTeams("@unikraft/approvers"),
+				WithNoRespectAssignees(tt.noRespectAssignee),
+				WithApproveStates("approve"),
+			)
+			if err != nil {
+				t.Fatalf("SimulateAttestation() unexpected error: %v", err)
+			}
+
+			if current.Mergable() {
+				t.Errorf("expected current verdict to not be mergable")
+			}
+			if current.Reviews != 1 {
+				t.Errorf("expected current verdict to have 1 review, got %d", current.Reviews)
+			}
+
+			if simulated.Mergable() != tt.wantSimulated {
+				t.Errorf("simulated.Mergable() = %v, want %v (unmet: %v)", simulated.Mergable(), tt.wantSimulated, simulated.Unmet)
+			}
+			if fmt.Sprint(simulated.Unmet) != fmt.Sprint(tt.wantUnmet) {
+				t.Errorf("simulated.Unmet = %v, want %v", simulated.Unmet, tt.wantUnmet)
+			}
+		})
+	}
+}
+
+func TestSimulateAttestationState(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number":1,"state":"open","draft":false,"assignees":[{"login":"alice"}]}`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+
+	tests := []struct {
+		state         string
+		wantSimulated bool
+	}{
+		{
+			// A review which is neither requested nor only comments.
+			state:         "dismissed",
+			wantSimulated: false,
+		},
+		{
+			// An approval written in a comment counts regardless of the
+			// approve states, cf. TestApprovalInCommentedReview.
+			state:         "comment",
+			wantSimulated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			pr := newTestPullRequest(t, mux)
+
+			_, simulated, err := pr.SimulateAttestation(context.Background(), "alice", tt.state,
+				WithApproveStates("approve"),
+				WithMinReviews(0),
+			)
+			if err != nil {
+				t.Fatalf("SimulateAttestation() unexpected error: %v", err)
+			}
+
+			if simulated.Mergable() != tt.wantSimulated {
+				t.Errorf("simulated.Mergable() = %v, want %v (unmet: %v)", simulated.Mergable(), tt.wantSimulated, simulated.Unmet)
+			}
+		})
+	}
+}
+
+// TestApprovalInCommentedReview checks that an approval written in a review
+// in the COMMENTED state is evaluated exactly like the same approval written
+// in a comment on the pull request.
+func TestApprovalInCommentedReview(t *testing.T) {
+	const body = "Approved-by: Jane Doe <jane@unikraft.io>"
+
+	tests := []struct {
+		name          string
+		approveStates []string
+		reviewStates  []string
+		want          int
+	}{
+		{
+			name: "no states",
+			want: 1,
+		},
+		{
+			name:          "default approve state",
+			approveStates: []string{"approve"},
+			want:          1,
+		},
+		{
+			name:          "formal approvals only",
+			approveStates: []string{"APPROVED"},
+			reviewStates:  []string{"APPROVED"},
+			want:          1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			approvals := make(map[string]int)
+
+			for source, handler := range map[string]func(w http.ResponseWriter, r *http.Request){
+				"comment": func(w http.ResponseWriter, r *http.Request) {
+					if strings.HasSuffix(r.URL.Path, "/comments") {
+						fmt.Fprintf(w, `[{"body":%q,"user":{"login":"jane"}}]`, body)
+						return
+					}
+					fmt.Fprint(w, `[]`)
+				},
+				"commented review": func(w http.ResponseWriter, r *http.Request) {
+					if strings.HasSuffix(r.URL.Path, "/reviews") {
+						fmt.Fprintf(w, `[{"body":%q,"state":"COMMENTED","user":{"login":"jane"}}]`, body)
+						return
+					}
+					fmt.Fprint(w, `[]`)
+				},
+			} {
+				mux := http.NewServeMux()
+				mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(w, `{"number":1,"state":"open","draft":false,"assignees":[{"login":"jane"}]}`)
+				})
+				mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", handler)
+				mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", handler)
+
+				pr := newTestPullRequest(t, mux)
+
+				verdict, err := pr.Verdict(context.Background(),
+					WithApproveStates(tt.approveStates...),
+					WithReviewStates(tt.reviewStates...),
+					WithMinReviews(0),
+				)
+				if err != nil {
+					t.Fatalf("Verdict() from %s unexpected error: %v", source, err)
+				}
+
+				approvals[source] = verdict.Approvals
+			}
+
+			if approvals["comment"] != approvals["commented review"] {
+				t.Errorf("approval in a comment counted %d times but in a COMMENTED review %d times", approvals["comment"], approvals["commented review"])
+			}
+
+			if approvals["comment"] != tt.want {
+				t.Errorf("Approvals = %d, want %d", approvals["comment"], tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestsState(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []PullRequestMergableOption
+		state string
+		want  bool
+	}{
+		{name: "default open", state: "open", want: true},
+		{name: "default closed", state: "closed", want: false},
+		{name: "any state open", opts: []PullRequestMergableOption{WithDefaultStateOpen(false)}, state: "open", want: true},
+		{name: "any state closed", opts: []PullRequestMergableOption{WithDefaultStateOpen(false)}, state: "closed", want: true},
+		{
+			name:  "any state ignored",
+			opts:  []PullRequestMergableOption{WithDefaultStateOpen(false), WithIgnoreStates("closed")},
+			state: "closed",
+			want:  false,
+		},
+		{name: "explicit states", opts: []PullRequestMergableOption{WithStates("closed")}, state: "closed", want: true},
+		{
+			name:  "explicit states override default",
+			opts:  []PullRequestMergableOption{WithDefaultStateOpen(false), WithStates("closed")},
+			state: "open",
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mopts := newMergableOptions(&PullRequest{}, tt.opts...)
+
+			if got := mopts.requestsState(tt.state); got != tt.want {
+				t.Errorf("requestsState(%q) = %v, want %v", tt.state, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSatisfiesMergeRequirementsChangesRequested(t *testing.T) {
+	review := func(login, state, at string) string {
+		return fmt.Sprintf(`{"user":{"login":%q},"state":%q,"submitted_at":%q}`, login, state, at)
+	}
+
+	tests := []struct {
+		name    string
+		reviews []string
+		ignore  bool
+		block   bool
+
+		// assigneesOnly makes only the assignee jane eligible such that
+		// the reviews of anyone else are not considered.
+		assigneesOnly bool
+
+		wantOk  bool
+		wantErr string
+	}{
+		{
+			name: "approve then request changes",
+			reviews: []string{
+				review("bob", "APPROVED", "2024-01-01T00:00:00Z"),
+				review("bob", "CHANGES_REQUESTED", "2024-01-02T00:00:00Z"),
+			},
+			wantErr: "pull request has changes requested by bob",
+		},
+		{
+			name: "request changes then approve",
+			reviews: []string{
+				review("bob", "CHANGES_REQUESTED", "2024-01-01T00:00:00Z"),
+				review("bob", "APPROVED", "2024-01-02T00:00:00Z"),
+			},
+			wantOk: true,
+		},
+		{
+			name: "ordered by submission time",
+			reviews: []string{
+				review("bob", "CHANGES_REQUESTED", "2024-01-02T00:00:00Z"),
+				review("bob", "APPROVED", "2024-01-01T00:00:00Z"),
+			},
+			wantErr: "pull request has changes requested by bob",
+		},
+		{
+			name: "request changes then dismissed",
+			reviews: []string{
+				review("bob", "CHANGES_REQUESTED", "2024-01-01T00:00:00Z"),
+				review("bob", "DISMISSED", "2024-01-02T00:00:00Z"),
+			},
+			wantOk: true,
+		},
+		{
+			name: "request changes then comment",
+			reviews: []string{
+				review("bob", "CHANGES_REQUESTED", "2024-01-01T00:00:00Z"),
+				review("bob", "COMMENTED", "2024-01-02T00:00:00Z"),
+			},
+			wantErr: "pull request has changes requested by bob",
+		},
+		{
+			name: "approval from someone else does not supersede",
+			reviews: []string{
+				review("bob", "CHANGES_REQUESTED", "2024-01-01T00:00:00Z"),
+				review("carol", "CHANGES_REQUESTED", "2024-01-01T00:00:00Z"),
+				review("jane", "APPROVED", "2024-01-02T00:00:00Z"),
+			},
+			wantErr: "pull request has changes requested by bob, carol",
+		},
+		{
+			name: "ignored",
+			reviews: []string{
+				review("bob", "CHANGES_REQUESTED", "2024-01-01T00:00:00Z"),
+			},
+			ignore: true,
+			wantOk: true,
+		},
+		{
+			name: "ineligible reviewer",
+			reviews: []string{
+				review("bob", "CHANGES_REQUESTED", "2024-01-01T00:00:00Z"),
+			},
+			assigneesOnly: true,
+			wantOk:        true,
+		},
+		{
+			name: "ineligible reviewer blocks",
+			reviews: []string{
+				review("bob", "CHANGES_REQUESTED", "2024-01-01T00:00:00Z"),
+				review("carol", "CHANGES_REQUESTED", "2024-01-01T00:00:00Z"),
+				review("carol", "APPROVED", "2024-01-02T00:00:00Z"),
+			},
+			block:         true,
+			assigneesOnly: true,
+			wantErr:       "pull request has changes requested by bob",
+		},
+		{
+			name: "blocking takes precedence over ignoring",
+			reviews: []string{
+				review("bob", "CHANGES_REQUESTED", "2024-01-01T00:00:00Z"),
+			},
+			ignore:  true,
+			block:   true,
+			wantErr: "pull request has changes requested by bob",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"number":1,"state":"open","draft":false,"assignees":[{"login":"jane"}]}`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[{"body":"Approved-by: Jane Doe <jane@unikraft.io>\nReviewed-by: Jane Doe <jane@unikraft.io>","user":{"login":"jane"}}]`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "["+strings.Join(tt.reviews, ",")+"]")
+			})
+
+			pr := newTestPullRequest(t, mux)
+
+			opts := []PullRequestMergableOption{
+				WithIgnoreChangesRequested(tt.ignore),
+				WithBlockOnChangesRequested(tt.block),
+			}
+			if tt.assigneesOnly {
+				opts = append(opts, WithNoRespectReviewers(true), WithMinReviews(0))
+			}
+
+			ok, _, err := pr.SatisfiesMergeRequirements(context.Background(), opts...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("SatisfiesMergeRequirements() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("SatisfiesMergeRequirements() unexpected error: %v", err)
+			}
+
+			if ok != tt.wantOk {
+				t.Errorf("SatisfiesMergeRequirements() = %v, want %v", ok, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestSimulateAttestationChangesRequested(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number":1,"state":"open","draft":false,"assignees":[{"login":"bob"}]}`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"user":{"login":"bob"},"state":"CHANGES_REQUESTED","submitted_at":"2024-01-01T00:00:00Z"}]`)
+	})
+
+	pr := newTestPullRequest(t, mux)
+
+	current, simulated, err := pr.SimulateAttestation(context.Background(), "bob", "approve",
+		WithMinReviews(0),
+	)
+	if err != nil {
+		t.Fatalf("SimulateAttestation() unexpected error: %v", err)
+	}
+
+	if fmt.Sprint(current.ChangesRequested) != "[bob]" {
+		t.Errorf("current.ChangesRequested = %v, want [bob]", current.ChangesRequested)
+	}
+
+	if !simulated.Mergable() {
+		t.Errorf("expected a later approval to lift the request for changes (unmet: %v)", simulated.Unmet)
+	}
+}
+
+func TestSatisfiesMergeRequirementsBotPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		author    string
+		policy    string
+		checks    string
+		wantOk    bool
+		wantInErr string
+	}{
+		{
+			name:   "bot with green checks",
+			author: "dependabot[bot]",
+			policy: BotPolicyChecks,
+			checks: "success",
+			wantOk: true,
+		},
+		{
+			name:      "bot with failing checks",
+			author:    "dependabot[bot]",
+			policy:    BotPolicyChecks,
+			checks:    "failure",
+			wantInErr: "build (failure)",
+		},
+		{
+			name:      "bot under review policy",
+			author:    "dependabot[bot]",
+			policy:    BotPolicyReview,
+			checks:    "success",
+			wantInErr: "minimum number approvers",
+		},
+		{
+			name:      "human under checks policy",
+			author:    "jane",
+			policy:    BotPolicyChecks,
+			checks:    "success",
+			wantInErr: "minimum number approvers",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"number":1,"state":"open","draft":false,"user":{"login":%q},"head":{"sha":"abc123"}}`, tt.author)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[]`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[]`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/commits/abc123/status", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"state":"success","statuses":[{"context":"ci/lint","state":"success"}]}`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/commits/abc123/check-runs", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"total_count":1,"check_runs":[{"name":"build","status":"completed","conclusion":%q}]}`, tt.checks)
+			})
+
+			pr := newTestPullRequest(t, mux)
+
+			ok, _, err := pr.SatisfiesMergeRequirements(context.Background(),
+				WithBotPolicy(tt.policy),
+			)
+			if ok != tt.wantOk {
+				t.Fatalf("SatisfiesMergeRequirements() = %v, want %v (err: %v)", ok, tt.wantOk, err)
+			}
+
+			if tt.wantInErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantInErr)) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantInErr, err)
+			}
+		})
+	}
+}
+
+func TestSatisfiesMergeRequirementsMergeableUnknown(t *testing.T) {
+	tests := []struct {
+		name      string
+		mergeable []string
+		wantOk    bool
+		wantGets  int
+		wantInErr string
+	}{
+		{
+			name:      "determined after retrying",
+			mergeable: []string{"null", "null", "true"},
+			wantOk:    true,
+			wantGets:  3,
+		},
+		{
+			name:      "conflicts determined after retrying",
+			mergeable: []string{"null", "false"},
+			wantGets:  2,
+			wantInErr: "pull request has merge conflicts",
+		},
+		{
+			name:      "never determined",
+			mergeable: []string{"null"},
+			wantGets:  3,
+			wantInErr: "not been checked for merge conflicts by GitHub after 3 attempts",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gets := 0
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+				mergeable := tt.mergeable[len(tt.mergeable)-1]
+				if gets < len(tt.mergeable) {
+					mergeable = tt.mergeable[gets]
+				}
+				gets++
+
+				fmt.Fprintf(w, `{"number":1,"state":"open","draft":false,"mergeable":%s,"assignees":[{"login":"jane"}]}`, mergeable)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[{"body":"Approved-by: Jane Doe <jane@unikraft.io>\nReviewed-by: Jane Doe <jane@unikraft.io>","user":{"login":"jane"}}]`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[]`)
+			})
+
+			pr := newTestPullRequest(t, mux)
+
+			ok, _, err := pr.SatisfiesMergeRequirements(context.Background(),
+				WithNoConflicts(true),
+				WithMergeableRetry(3, time.Millisecond),
+			)
+			if ok != tt.wantOk {
+				t.Fatalf("SatisfiesMergeRequirements() = %v, want %v (err: %v)", ok, tt.wantOk, err)
+			}
+
+			if tt.wantInErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantInErr)) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantInErr, err)
+			}
+
+			if gets != tt.wantGets {
+				t.Errorf("retrieved pull request %d times, want %d", gets, tt.wantGets)
+			}
+		})
+	}
+}
+
+func TestVerdictLatestAttestation(t *testing.T) {
+	const body = "Approved-by: Jane Doe <jane@unikraft.io>\nReviewed-by: Jane Doe <jane@unikraft.io>"
+
+	tests := []struct {
+		name         string
+		comments     []string
+		reviews      []string
+		wantApproved []string
+	}{
+		{
+			name: "multiple approving reviews",
+			reviews: []string{
+				fmt.Sprintf(`{"user":{"login":"jane"},"state":"APPROVED","body":%q,"submitted_at":"2024-01-01T00:00:00Z"}`, body),
+				fmt.Sprintf(`{"user":{"login":"jane"},"state":"APPROVED","body":%q,"submitted_at":"2024-01-02T00:00:00Z"}`, body),
+				fmt.Sprintf(`{"user":{"login":"jane"},"state":"APPROVED","body":%q,"submitted_at":"2024-01-03T00:00:00Z"}`, body),
+			},
+			wantApproved: []string{"Jane Doe <jane@unikraft.io>"},
+		},
+		{
+			name: "multiple comments",
+			comments: []string{
+				fmt.Sprintf(`{"user":{"login":"jane"},"body":%q,"created_at":"2024-01-01T00:00:00Z"}`, body),
+				fmt.Sprintf(`{"user":{"login":"jane"},"body":%q,"created_at":"2024-01-02T00:00:00Z"}`, "Reviewed-by: Jane Doe <jane@unikraft.org>"),
+			},
+			wantApproved: []string{"Jane Doe <jane@unikraft.io>"},
+		},
+		{
+			name: "most recent of comments and reviews",
+			comments: []string{
+				fmt.Sprintf(`{"user":{"login":"jane"},"body":%q,"created_at":"2024-01-03T00:00:00Z"}`, "Approved-by: Jane Doe <jane@unikraft.org>"),
+			},
+			reviews: []string{
+				fmt.Sprintf(`{"user":{"login":"jane"},"state":"APPROVED","body":%q,"submitted_at":"2024-01-01T00:00:00Z"}`, body),
+			},
+			wantApproved: []string{"Jane Doe <jane@unikraft.org>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"number":1,"state":"open","draft":false,"assignees":[{"login":"jane"}]}`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "["+strings.Join(tt.comments, ",")+"]")
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "["+strings.Join(tt.reviews, ",")+"]")
+			})
+
+			pr := newTestPullRequest(t, mux)
+
+			verdict, err := pr.Verdict(context.Background(),
+				WithMinApprovals(2),
+				WithMinReviews(2),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if verdict.Approvals != 1 || verdict.Reviews != 1 {
+				t.Errorf("approvals, reviews = %d, %d, want 1, 1", verdict.Approvals, verdict.Reviews)
+			}
+
+			if got := verdict.Result["approved_by"]; !reflect.DeepEqual(got, tt.wantApproved) {
+				t.Errorf("approved_by = %v, want %v", got, tt.wantApproved)
+			}
+
+			if verdict.Mergable() {
+				t.Error("Mergable() = true, want false with a single user")
+			}
+		})
+	}
+}
+
+func TestSatisfiesMergeRequirementsAllowedBaseBranches(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		wantOk  bool
+		wantErr string
+	}{
+		{
+			name:   "any base by default",
+			wantOk: true,
+		},
+		{
+			name:    "allowed base",
+			allowed: []string{"stable", "staging"},
+			wantOk:  true,
+		},
+		{
+			name:    "disallowed base",
+			allowed: []string{"stable"},
+			wantErr: "pull request is based on 'staging' which is not an allowed base branch: stable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"number":1,"state":"open","draft":false,"base":{"ref":"staging"},"assignees":[{"login":"jane"}]}`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[{"body":"Approved-by: Jane Doe <jane@unikraft.io>\nReviewed-by: Jane Doe <jane@unikraft.io>","user":{"login":"jane"}}]`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[]`)
+			})
+
+			pr := newTestPullRequest(t, mux)
+
+			ok, _, err := pr.SatisfiesMergeRequirements(context.Background(),
+				WithAllowedBaseBranches(tt.allowed...),
+			)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("SatisfiesMergeRequirements() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("SatisfiesMergeRequirements() unexpected error: %v", err)
+			}
+
+			if ok != tt.wantOk {
+				t.Errorf("SatisfiesMergeRequirements() = %v, want %v", ok, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestSatisfiesMergeRequirementsRequiredApprovers(t *testing.T) {
+	const approval = `{"user":{"login":"alice"},"state":%q,"body":"Approved-by: Alice <alice@unikraft.io>","submitted_at":"2024-01-01T00:00:00Z"}`
+
+	tests := []struct {
+		name     string
+		required []string
+		reviews  []string
+		opts     []PullRequestMergableOption
+		wantOk   bool
+		wantErr  string
+	}{
+		{
+			name:     "eligible approver",
+			required: []string{"Jane"},
+			wantOk:   true,
+		},
+		{
+			name:     "ineligible approver",
+			required: []string{"alice"},
+			reviews:  []string{fmt.Sprintf(approval, "APPROVED")},
+			wantOk:   true,
+		},
+		{
+			name:     "missing approver",
+			required: []string{"alice", "carol"},
+			reviews:  []string{fmt.Sprintf(approval, "APPROVED")},
+			wantErr:  "pull request has not been approved by the required approvers carol",
+		},
+		{
+			name:     "approval state not accepted",
+			required: []string{"alice"},
+			reviews:  []string{fmt.Sprintf(approval, "DISMISSED")},
+			opts:     []PullRequestMergableOption{WithApproveStates("approve")},
+			wantErr:  "pull request has not been approved by the required approvers alice",
+		},
+		{
+			name:     "composes with minimum approvals",
+			required: []string{"alice"},
+			reviews:  []string{fmt.Sprintf(approval, "APPROVED")},
+			opts:     []PullRequestMergableOption{WithMinApprovals(2)},
+			wantErr:  "pull request does not meet the minimum number approvers (1/2) and reviewers (1/1)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"number":1,"state":"open","draft":false,"assignees":[{"login":"jane"}]}`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[{"body":"Approved-by: Jane Doe <jane@unikraft.io>\nReviewed-by: Jane Doe <jane@unikraft.io>","user":{"login":"jane"}}]`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "["+strings.Join(tt.reviews, ",")+"]")
+			})
+
+			pr := newTestPullRequest(t, mux)
+
+			ok, _, err := pr.SatisfiesMergeRequirements(context.Background(),
+				append(tt.opts, WithRequiredApprovers(tt.required...))...,
+			)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("SatisfiesMergeRequirements() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("SatisfiesMergeRequirements() unexpected error: %v", err)
+			}
+
+			if ok != tt.wantOk {
+				t.Errorf("SatisfiesMergeRequirements() = %v, want %v", ok, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestSatisfiesMergeRequirementsIgnoreAuthors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number":1,"state":"open","draft":false,"assignees":[{"login":"dependabot[bot]"},{"login":"jane"}]}`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"body":"Approved-by: Dependabot <support@github.com>","user":{"login":"dependabot[bot]"}}]`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id":1,"body":"Approved-by: Dependabot <support@github.com>","state":"APPROVED","user":{"login":"dependabot[bot]"},"submitted_at":"2024-05-01T12:00:00Z"}]`)
+	})
+
+	tests := []struct {
+		name   string
+		ignore []string
+		wantOk bool
+	}{
+		{
+			name:   "bots ignored by default",
+			wantOk: false,
+		},
+		{
+			name:   "other authors ignored",
+			ignore: []string{"ci-*"},
+			wantOk: true,
+		},
+		{
+			name:   "bot ignored by login",
+			ignore: []string{"dependabot[bot]"},
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := newTestPullRequest(t, mux)
+
+			ok, _, err := pr.SatisfiesMergeRequirements(context.Background(),
+				WithIgnoreAuthors(tt.ignore...),
+				WithMinReviews(0),
+			)
+			if ok != tt.wantOk {
+				t.Fatalf("SatisfiesMergeRequirements() = %v, want %v (err: %v)", ok, tt.wantOk, err)
+			}
+
+			if !tt.wantOk && (err == nil || !strings.Contains(err.Error(), "minimum number approvers")) {
+				t.Errorf("expected error about approvers, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestSatisfiesMergeRequirementsPerTeamApprovals(t *testing.T) {
+	tests := []struct {
+		name      string
+		approvers []string
+		perTeam   map[string]int
+		wantOk    bool
+		wantInErr string
+	}{
+		{
+			name:      "team minimum met",
+			approvers: []string{"alice", "bob"},
+			perTeam:   map[string]int{"@unikraft/sig-net": 1},
+			wantOk:    true,
+		},
+		{
+			name:      "team minimum short",
+			approvers: []string{"alice", "bob"},
+			perTeam:   map[string]int{"@unikraft/sig-net": 2},
+			wantInErr: "@unikraft/sig-net (1/2)",
+		},
+		{
+			name:      "approver counts towards each of their teams",
+			approvers: []string{"alice", "bob"},
+			perTeam:   map[string]int{"@unikraft/sig-net": 1, "@unikraft/sig-core": 2},
+			wantOk:    true,
+		},
+		{
+			name:      "overall minimum met but team missing",
+			approvers: []string{"bob", "carol"},
+			perTeam:   map[string]int{"@unikraft/sig-net": 1, "@unikraft/sig-core": 1},
+			wantInErr: "approvals from @unikraft/sig-net (0/1)",
+		},
+		{
+			name:      "repeated approvals of one user count once",
+			approvers: []string{"alice", "alice"},
+			perTeam:   map[string]int{"@unikraft/sig-core": 2},
+			wantInErr: "@unikraft/sig-core (1/2)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var comments []string
+			for _, login := range tt.approvers {
+				comments = append(comments, fmt.Sprintf(`{"body":"Approved-by: %s <%s@unikraft.io>\nReviewed-by: %s <%s@unikraft.io>","user":{"login":%q}}`, login, login, login, login, login))
+			}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"number":1,"state":"open","draft":false,"assignees":[{"login":"alice"},{"login":"bob"},{"login":"carol"}]}`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, "[%s]", strings.Join(comments, ","))
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[]`)
+			})
+			mux.HandleFunc("/api/v3/orgs/unikraft/teams/sig-net/members", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[{"login":"alice"},{"login":"dave"}]`)
+			})
+			mux.HandleFunc("/api/v3/orgs/unikraft/teams/sig-core/members", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[{"login":"alice"},{"login":"bob"}]`)
+			})
+
+			pr := newTestPullRequest(t, mux)
+
+			ok, _, err := pr.SatisfiesMergeRequirements(context.Background(),
+				WithPerTeamMinApprovals(tt.perTeam),
+			)
+			if ok != tt.wantOk {
+				t.Fatalf("SatisfiesMergeRequirements() = %v, want %v (err: %v)", ok, tt.wantOk, err)
+			}
+
+			if tt.wantInErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantInErr)) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantInErr, err)
+			}
+		})
+	}
+}
+
+func TestVerdictNilFields(t *testing.T) {
+	approval := "Approved-by: Jane Doe <jane@unikraft.io>"
+	jane := &github.User{Login: github.String("jane")}
+
+	tests := []struct {
+		name          string
+		comments      []*github.IssueComment
+		reviews       []*github.PullRequestReview
+		wantApprovals int
+	}{
+		{
+			name: "approving review",
+			reviews: []*github.PullRequestReview{
+				{User: jane, Body: github.String(approval), State: github.String("APPROVED")},
+			},
+			wantApprovals: 1,
+		},
+		{
+			name: "review without body",
+			reviews: []*github.PullRequestReview{
+				{User: jane, State: github.String("APPROVED")},
+			},
+		},
+		{
+			name: "review without user",
+			reviews: []*github.PullRequestReview{
+				{Body: github.String(approval), State: github.String("APPROVED")},
+			},
+		},
+		{
+			name: "review without state",
+			reviews: []*github.PullRequestReview{
+				{User: jane, Body: github.String(approval)},
+			},
+		},
+		{
+			name: "pending review",
+			reviews: []*github.PullRequestReview{
+				{User: jane, Body: github.String(approval), State: github.String("PENDING")},
+			},
+		},
+		{
+			name: "empty review",
+			reviews: []*github.PullRequestReview{
+				{},
+			},
+		},
+		{
+			name: "comment without user",
+			comments: []*github.IssueComment{
+				{Body: github.String(approval)},
+			},
+		},
+		{
+			name: "comment without body",
+			comments: []*github.IssueComment{
+				{User: jane},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"number":1,"state":"open"}`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(append([]*github.IssueComment{}, tt.comments...))
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(append([]*github.PullRequestReview{}, tt.reviews...))
+			})
+			mux.HandleFunc("/api/v3/orgs/unikraft/teams/maintainers/members", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[{"login":"jane"}]`)
+			})
+
+			verdict, err := newTestPullRequest(t, mux).Verdict(context.Background(),
+				WithApproverTeams("@unikraft/maintainers"),
+				WithApproveStates(ReviewStates...),
+				WithNoRespectAssignees(true),
+				WithMinReviews(0),
+			)
+			if err != nil {
+				t.Fatalf("Verdict() unexpected error: %v", err)
+			}
+
+			if verdict.Approvals != tt.wantApprovals {
+				t.Errorf("Verdict() approvals = %d, want %d", verdict.Approvals, tt.wantApprovals)
+			}
+		})
+	}
+}
+
+func TestMergeVerdictMarkdown(t *testing.T) {
+	verdict := &MergeVerdict{
+		Approvals:     2,
+		MinApprovals:  1,
+		Reviews:       0,
+		MinReviews:    1,
+		TeamApprovals: map[string]int{"@unikraft/sig-net": 0, "@unikraft/sig-core": 2},
+		ShortTeams:    []string{"@unikraft/sig-net (0/1)"},
+		FailingChecks: []string{"build (failure)"},
+
+		MissingApprovers: []string{"alice"},
+	}
+
+	got := verdict.Markdown()
+
+	for _, want := range []string{
+		"| Approvals | ✅ | 2/1 |",
+		"| Reviews | ❌ | 0/1 |",
+		"| Approvals from @unikraft/sig-core | ✅ | 2 |",
+		"| Approvals from @unikraft/sig-net | ❌ | (0/1) |",
+		"| Required approvers | ❌ | alice |",
+		"| Checks | ❌ | build (failure) |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Markdown() does not contain %q:\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(got, "Sign-off") || strings.Contains(got, "changes requested") {
+		t.Errorf("Markdown() contains requirements which were not configured:\n%s", got)
+	}
+}
+
+func TestSatisfiesKeepsStdoutClean(t *testing.T) {
+	var logged bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logged)
+	ctx := log.WithLogger(context.Background(), logger)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	t.Cleanup(func() { os.Stdout = stdout })
+
+	ok, _, err := satisfies(ctx, &MergeVerdict{
+		Approvals:    1,
+		MinApprovals: 1,
+		Reviews:      0,
+		MinReviews:   1,
+		Unmet:        []string{"reviews"},
+	})
+
+	os.Stdout = stdout
+	w.Close()
+
+	printed, rerr := io.ReadAll(r)
+	if rerr != nil {
+		t.Fatal(rerr)
+	}
+
+	if ok || err == nil {
+		t.Errorf("satisfies() = %v, %v, want unmet", ok, err)
+	}
+	if len(printed) > 0 {
+		t.Errorf("satisfies() wrote %q to stdout", printed)
+	}
+	if !strings.Contains(logged.String(), "approvers (1/1) and reviewers (0/1)") {
+		t.Errorf("satisfies() logged %q, want the tally", logged.String())
+	}
+}
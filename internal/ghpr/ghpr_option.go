@@ -24,3 +24,47 @@ func WithBaseBranch(name string) PullRequestOption {
 		return nil
 	}
 }
+
+// WithMaxPatches sets the maximum number of patches which are generated for
+// the pull request before giving up.  A value of zero or less uses
+// DefaultMaxPatches.
+func WithMaxPatches(max int) PullRequestOption {
+	return func(pr *PullRequest) error {
+		pr.maxPatches = max
+		return nil
+	}
+}
+
+// WithGitBinary sets the path to the git executable which is used to rebase
+// the pull request and to generate its patches.
+func WithGitBinary(path string) PullRequestOption {
+	return func(pr *PullRequest) error {
+		if path != "" {
+			pr.gitBinary = path
+		}
+
+		return nil
+	}
+}
+
+// WithGitHubActionsAnnotations sets whether findings about the pull request
+// are emitted as GitHub Actions annotations with Annotate.  It is disabled by
+// default, such that callers decide explicitly, e.g. with
+// cmdutils.InGithubActions.
+func WithGitHubActionsAnnotations(enabled bool) PullRequestOption {
+	return func(pr *PullRequest) error {
+		pr.annotations = enabled
+		return nil
+	}
+}
+
+// WithCredentials sets the user and token which authenticate the clone and
+// fetch of the repository of the pull request.  Without credentials, the
+// repository is accessed anonymously.
+func WithCredentials(user, token string) PullRequestOption {
+	return func(pr *PullRequest) error {
+		pr.gitUser = user
+		pr.gitToken = token
+		return nil
+	}
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"kraftkit.sh/log"
+
+	"github.com/unikraft/governance/internal/ownership"
+	"github.com/unikraft/governance/internal/team"
+)
+
+// OwningTeams returns the teams named in the repository's CODEOWNERS for each
+// file changed by the pull request.  For renamed files, both the previous and
+// the new path are considered.  Files which are not owned by any of the
+// provided teams are omitted and, if the repository has no CODEOWNERS, the
+// returned map is empty.  A CODEOWNERS file which cannot be parsed is an
+// error.
+func (pr *PullRequest) OwningTeams(ctx context.Context, teams []*team.Team) (map[string][]*team.Team, error) {
+	changed, err := pr.client.ListPullRequestFiles(ctx, pr.ghOrg, pr.ghRepo, pr.ghPrId)
+	if err != nil {
+		return nil, fmt.Errorf("could not list pull request files: %w", err)
+	}
+
+	var files []string
+	for _, f := range changed {
+		if f.GetPreviousFilename() != "" {
+			files = append(files, f.GetPreviousFilename())
+		}
+		if f.GetFilename() != "" {
+			files = append(files, f.GetFilename())
+		}
+	}
+
+	co, location, err := ownership.FindCodeowners(pr.localRepo)
+	if errors.Is(err, ownership.ErrNoCodeowners) {
+		log.G(ctx).
+			WithField("repo", pr.ghRepo).
+			Debug("not using CODEOWNERS: none found")
+		return map[string][]*team.Team{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	log.G(ctx).
+		WithField("repo", pr.ghRepo).
+		WithField("path", location).
+		Debug("using CODEOWNERS")
+
+	idx, err := ownership.NewIndex(pr.ghRepo, teams, ownership.WithCodeowners(co))
+	if err != nil {
+		return nil, fmt.Errorf("could not build ownership index: %w", err)
+	}
+
+	return idx.FileOwners(files), nil
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/unikraft/governance/internal/team"
+)
+
+func TestOwningTeams(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/files", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"filename":"arch/x86/x86_64/include/uk/asm.h"},
+			{"filename":"lib/vfscore/main.c"},
+			{"filename":"lib/vfscore/file.c","previous_filename":"lib/ukdebug/file.c"},
+			{"filename":"README.md"}
+		]`)
+	})
+
+	teams := []*team.Team{
+		{Name: "arch", Type: team.SIGTeam},
+		{Name: "fs", Type: team.SIGTeam},
+		{Name: "debug", Type: team.SIGTeam},
+	}
+
+	tests := []struct {
+		name       string
+		codeowners string
+		want       map[string][]string
+	}{
+		{
+			name: "codeowners",
+			codeowners: `/arch/ @unikraft/sig-arch
+/lib/vfscore/ @unikraft/sig-fs @unikraft/sig-arch jane@unikraft.io
+/lib/ukdebug/ @unikraft/sig-debug @unikraft/sig-unknown
+`,
+			want: map[string][]string{
+				"arch/x86/x86_64/include/uk/asm.h": {"arch"},
+				"lib/vfscore/main.c":               {"fs", "arch"},
+				"lib/vfscore/file.c":               {"fs", "arch"},
+				"lib/ukdebug/file.c":               {"debug"},
+			},
+		},
+		{
+			name: "no codeowners",
+			want: map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := newTestPullRequest(t, mux)
+			pr.localRepo = t.TempDir()
+
+			if tt.codeowners != "" {
+				if err := os.MkdirAll(filepath.Join(pr.localRepo, ".github"), 0o755); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(filepath.Join(pr.localRepo, ".github", "CODEOWNERS"), []byte(tt.codeowners), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			owners, err := pr.OwningTeams(context.Background(), teams)
+			if err != nil {
+				t.Fatalf("OwningTeams() unexpected error: %v", err)
+			}
+
+			got := make(map[string][]string)
+			for file, teams := range owners {
+				for _, t := range teams {
+					got[file] = append(got[file], t.Name)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OwningTeams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
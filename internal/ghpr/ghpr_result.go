@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+	"kraftkit.sh/log"
+)
+
+// MergeResultVersion is the version of the format of merge results which is
+// written by and understood by this version of the tool.
+const MergeResultVersion = 1
+
+// The kinds of attestations recorded in a verdict.
+const (
+	AttestationKindComment = "comment"
+	AttestationKindReview  = "review"
+)
+
+// Attestation is a comment or review which qualified as an approval or review
+// when the verdict was evaluated.  Its body is only recorded as a digest such
+// that later edits can be detected.
+type Attestation struct {
+	Kind       string `json:"kind"`
+	ID         int64  `json:"id"`
+	Login      string `json:"login"`
+	BodySHA256 string `json:"body_sha256"`
+	State      string `json:"state"`
+}
+
+// export returns the attestation as recorded in a verdict.
+func (a attestation) export() Attestation {
+	kind := AttestationKindComment
+	if a.review {
+		kind = AttestationKindReview
+	}
+
+	return Attestation{
+		Kind:       kind,
+		ID:         a.id,
+		Login:      a.login,
+		BodySHA256: bodySHA256(a.body),
+		State:      a.state,
+	}
+}
+
+// bodySHA256 returns the hex encoded SHA-256 digest of the body of a comment
+// or review.
+func bodySHA256(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// MergeResult is the verdict of a pull request as exported by `pr check
+// mergable` such that `pr merge` can reuse it instead of evaluating the merge
+// requirements again.
+type MergeResult struct {
+	Version     int           `json:"version"`
+	Org         string        `json:"org"`
+	Repo        string        `json:"repo"`
+	PullRequest int           `json:"pull_request"`
+	HeadSHA     string        `json:"head_sha"`
+	EvaluatedAt time.Time     `json:"evaluated_at"`
+	Verdict     *MergeVerdict `json:"verdict"`
+}
+
+// NewMergeResult returns the merge result of the pull request with the
+// provided verdict, evaluated at the provided time.
+func (pr *PullRequest) NewMergeResult(verdict *MergeVerdict, evaluatedAt time.Time) *MergeResult {
+	return &MergeResult{
+		Version:     MergeResultVersion,
+		Org:         pr.ghOrg,
+		Repo:        pr.ghRepo,
+		PullRequest: pr.ghPrId,
+		HeadSHA:     verdict.HeadSHA,
+		EvaluatedAt: evaluatedAt.UTC(),
+		Verdict:     verdict,
+	}
+}
+
+// WriteFile writes the merge result as JSON to the provided path.
+func (r *MergeResult) WriteFile(path string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal merge result: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("could not write merge result: %w", err)
+	}
+
+	return nil
+}
+
+// ReadMergeResultFile reads a merge result previously written with WriteFile.
+// The result is not verified, see VerifyMergeResult.
+func ReadMergeResultFile(path string) (*MergeResult, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read merge result: %w", err)
+	}
+
+	var result MergeResult
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, fmt.Errorf("could not parse merge result %s: %w", path, err)
+	}
+
+	return &result, nil
+}
+
+// VerifyMergeResult checks that the merge result still applies to the pull
+// request, i.e. it is of a known version, its verdict is mergable, the head of
+// the pull request has not moved and every attestation it relied on still
+// exists unchanged.
+func (pr *PullRequest) VerifyMergeResult(ctx context.Context, result *MergeResult) error {
+	pull, err := pr.client.GetPullRequest(ctx, pr.ghOrg, pr.ghRepo, pr.ghPrId)
+	if err != nil {
+		return fmt.Errorf("could not get pull request: %w", err)
+	}
+
+	return pr.verifyMergeResult(ctx, pull, result)
+}
+
+// verifyMergeResult checks the merge result against the provided metadata of
+// the pull request.
+func (pr *PullRequest) verifyMergeResult(ctx context.Context, pull *github.PullRequest, result *MergeResult) error {
+	if result.Version != MergeResultVersion {
+		return fmt.Errorf("unsupported merge result version %d: expected %d", result.Version, MergeResultVersion)
+	}
+
+	if result.Org != pr.ghOrg || result.Repo != pr.ghRepo || result.PullRequest != pr.ghPrId {
+		return fmt.Errorf("merge result is for %s/%s#%d", result.Org, result.Repo, result.PullRequest)
+	}
+
+	if result.Verdict == nil || !result.Verdict.Mergable() {
+		return fmt.Errorf("merge result is not mergable")
+	}
+
+	if head := pull.GetHead().GetSHA(); head != result.HeadSHA {
+		return fmt.Errorf("head has moved from %s to %s", result.HeadSHA, head)
+	}
+
+	for _, a := range result.Verdict.Attestations {
+		if err := pr.verifyAttestation(ctx, a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyAttestation checks that the comment or review backing the attestation
+// still exists and has neither been edited nor changed state.
+func (pr *PullRequest) verifyAttestation(ctx context.Context, a Attestation) error {
+	var login, body, state string
+
+	switch a.Kind {
+	case AttestationKindComment:
+		comment, err := pr.client.GetPullRequestComment(ctx, pr.ghOrg, pr.ghRepo, a.ID)
+		if err != nil {
+			return fmt.Errorf("could not get comment %d: %w", a.ID, err)
+		}
+
+		login, body, state = comment.GetUser().GetLogin(), comment.GetBody(), stateComment
+
+	case AttestationKindReview:
+		review, err := pr.client.GetPullRequestReview(ctx, pr.ghOrg, pr.ghRepo, pr.ghPrId, a.ID)
+		if err != nil {
+			return fmt.Errorf("could not get review %d: %w", a.ID, err)
+		}
+
+		login, body, state = review.GetUser().GetLogin(), review.GetBody(), review.GetState()
+
+	default:
+		return fmt.Errorf("unknown attestation kind '%s': expected one of %s, %s", a.Kind, AttestationKindComment, AttestationKindReview)
+	}
+
+	if login != a.Login || bodySHA256(body) != a.BodySHA256 || state != a.State {
+		return fmt.Errorf("%s %d by %s has changed", a.Kind, a.ID, a.Login)
+	}
+
+	return nil
+}
+
+// VerdictFromResult returns the verdict of the merge result if it still
+// applies to the pull request.  Otherwise, the reason is logged as a warning
+// and the merge requirements are evaluated again with the provided options.
+// The prerequisites of the options, e.g. the state and labels of the pull
+// request, are always checked again.
+func (pr *PullRequest) VerdictFromResult(ctx context.Context, result *MergeResult, opts ...PullRequestMergableOption) (*MergeVerdict, error) {
+	pull, err := pr.checkPrerequisites(ctx, newMergableOptions(pr, opts...))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pr.verifyMergeResult(ctx, pull, result); err != nil {
+		log.G(ctx).
+			WithField("reason", err).
+			Warn("recorded merge result no longer applies, re-evaluating")
+
+		return pr.Verdict(ctx, opts...)
+	}
+
+	return result.Verdict, nil
+}
+
+// SatisfiesMergeResult is like SatisfiesMergeRequirements but reuses the
+// verdict of the merge result if it still applies to the pull request.
+func (pr *PullRequest) SatisfiesMergeResult(ctx context.Context, result *MergeResult, opts ...PullRequestMergableOption) (bool, map[string][]string, error) {
+	verdict, err := pr.VerdictFromResult(ctx, result, opts...)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return satisfies(ctx, verdict)
+}
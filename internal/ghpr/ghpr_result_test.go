@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resultServer is a fake GitHub API which serves a pull request with an
+// approving comment and an approving review, both of which can be changed
+// after the result was recorded.
+type resultServer struct {
+	head        string
+	commentBody string
+	reviewState string
+	reviewGone  bool
+
+	// listed counts the requests which list the comments of the pull request,
+	// i.e. the full evaluations.
+	listed int
+}
+
+func (s *resultServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"number":1,"state":"open","draft":false,"head":{"sha":"%s"}}`, s.head)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		s.listed++
+		fmt.Fprintf(w, `[{"id":10,"body":%q,"user":{"login":"jane"}}]`, s.commentBody)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/comments/10", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":10,"body":%q,"user":{"login":"jane"}}`, s.commentBody)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		if s.reviewGone {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		fmt.Fprintf(w, `[{"id":20,"body":"Reviewed-by: Bob <bob@unikraft.io>","state":"%s","user":{"login":"bob"}}]`, s.reviewState)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews/20", func(w http.ResponseWriter, r *http.Request) {
+		if s.reviewGone {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"id":20,"body":"Reviewed-by: Bob <bob@unikraft.io>","state":"%s","user":{"login":"bob"}}`, s.reviewState)
+	})
+	mux.HandleFunc("/api/v3/orgs/unikraft/teams/maintainers/members", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"login":"jane"},{"login":"bob"}]`)
+	})
+
+	return mux
+}
+
+func TestVerdictFromResult(t *testing.T) {
+	opts := []PullRequestMergableOption{
+		WithApproverTeams("@unikraft/maintainers"),
+		WithReviewerTeams("@unikraft/maintainers"),
+		WithReviewStates(ReviewStateApproved),
+		WithNoRespectAssignees(true),
+		WithNoRespectReviewers(true),
+	}
+
+	tests := []struct {
+		name         string
+		change       func(*resultServer, *MergeResult)
+		wantFallback bool
+		wantErr      string
+	}{
+		{
+			name:   "unchanged",
+			change: func(*resultServer, *MergeResult) {},
+		},
+		{
+			name: "head moved",
+			change: func(s *resultServer, _ *MergeResult) {
+				s.head = "def456"
+			},
+			wantFallback: true,
+		},
+		{
+			name: "comment edited",
+			change: func(s *resultServer, _ *MergeResult) {
+				s.commentBody = "Approved-by: Jane Doe <jane.doe@unikraft.io>"
+			},
+			wantFallback: true,
+		},
+		{
+			name: "review dismissed",
+			change: func(s *resultServer, _ *MergeResult) {
+				s.reviewState = "DISMISSED"
+			},
+			wantFallback: true,
+			wantErr:      "reviewers (0/1)",
+		},
+		{
+			name: "review deleted",
+			change: func(s *resultServer, _ *MergeResult) {
+				s.reviewGone = true
+			},
+			wantFallback: true,
+			wantErr:      "reviewers (0/1)",
+		},
+		{
+			name: "unknown version",
+			change: func(_ *resultServer, r *MergeResult) {
+				r.Version = MergeResultVersion + 1
+			},
+			wantFallback: true,
+		},
+		{
+			name: "not mergable",
+			change: func(_ *resultServer, r *MergeResult) {
+				r.Verdict.Unmet = []string{"approvals (0/1)"}
+			},
+			wantFallback: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			srv := &resultServer{
+				head:        "abc123",
+				commentBody: "Approved-by: Jane Doe <jane@unikraft.io>",
+				reviewState: "APPROVED",
+			}
+			pr := newTestPullRequest(t, srv.handler())
+
+			verdict, err := pr.Verdict(ctx, opts...)
+			if err != nil {
+				t.Fatalf("Verdict() unexpected error: %v", err)
+			}
+			if !verdict.Mergable() || len(verdict.Attestations) != 2 {
+				t.Fatalf("Verdict() = %+v, want mergable with 2 attestations", verdict)
+			}
+
+			// Round-trip the result through a file as between the commands.
+			path := filepath.Join(t.TempDir(), "result.json")
+			if err := pr.NewMergeResult(verdict, time.Now()).WriteFile(path); err != nil {
+				t.Fatalf("WriteFile() unexpected error: %v", err)
+			}
+			result, err := ReadMergeResultFile(path)
+			if err != nil {
+				t.Fatalf("ReadMergeResultFile() unexpected error: %v", err)
+			}
+
+			tt.change(srv, result)
+			srv.listed = 0
+
+			got, err := pr.VerdictFromResult(ctx, result, opts...)
+			if err != nil {
+				t.Fatalf("VerdictFromResult() unexpected error: %v", err)
+			}
+
+			if fallback := srv.listed > 0; fallback != tt.wantFallback {
+				t.Errorf("VerdictFromResult() re-evaluated = %v, want %v", fallback, tt.wantFallback)
+			}
+
+			if err := got.Err(); tt.wantErr == "" && err != nil {
+				t.Errorf("VerdictFromResult() unexpected unmet requirements: %v", err)
+			} else if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("VerdictFromResult() unmet = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
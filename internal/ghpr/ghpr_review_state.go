@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The canonical review states which are accepted by WithApproveStates and
+// WithReviewStates.  All but ReviewStateComment are the states of pull request
+// reviews as reported by GitHub, in lowercase.
+const (
+	ReviewStateApproved         = "approved"
+	ReviewStateChangesRequested = "changes_requested"
+	ReviewStateCommented        = "commented"
+	ReviewStateDismissed        = "dismissed"
+	ReviewStatePending          = "pending"
+
+	// ReviewStateComment is the state of attestations made through comments on
+	// the pull request rather than through reviews.
+	ReviewStateComment = stateComment
+)
+
+// ReviewStates are the canonical review states.
+var ReviewStates = []string{
+	ReviewStateApproved,
+	ReviewStateChangesRequested,
+	ReviewStateCommented,
+	ReviewStateDismissed,
+	ReviewStatePending,
+	ReviewStateComment,
+}
+
+// reviewStateSynonyms maps the common spellings of the review states, after
+// they have been lowercased and their dashes and spaces replaced with
+// underscores, to their canonical state.
+var reviewStateSynonyms = map[string]string{
+	"approve":           ReviewStateApproved,
+	"approved":          ReviewStateApproved,
+	"approval":          ReviewStateApproved,
+	"changes_requested": ReviewStateChangesRequested,
+	"request_changes":   ReviewStateChangesRequested,
+	"changes":           ReviewStateChangesRequested,
+	"commented":         ReviewStateCommented,
+	"dismissed":         ReviewStateDismissed,
+	"dismiss":           ReviewStateDismissed,
+	"pending":           ReviewStatePending,
+	"comment":           ReviewStateComment,
+}
+
+// ParseReviewState returns the canonical review state of the provided state,
+// which is matched regardless of its case and may be one of its synonyms, e.g.
+// "approve" and "APPROVED" are both ReviewStateApproved.
+func ParseReviewState(state string) (string, error) {
+	key := strings.ToLower(strings.TrimSpace(state))
+	key = strings.NewReplacer("-", "_", " ", "_").Replace(key)
+
+	if canonical, ok := reviewStateSynonyms[key]; ok {
+		return canonical, nil
+	}
+
+	return "", fmt.Errorf("unknown review state '%s': expected one of %s", state, strings.Join(ReviewStates, ", "))
+}
+
+// ValidateReviewStates checks that every provided state is a known review
+// state or one of its synonyms.
+func ValidateReviewStates(states []string) error {
+	for _, state := range states {
+		if _, err := ParseReviewState(state); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchesReviewState returns whether the state of an attestation is one of the
+// provided states, comparing their canonical forms.  An empty list matches
+// every state.  Unknown states, which are rejected when the options are
+// validated, match nothing.
+func matchesReviewState(states []string, state string) bool {
+	if len(states) == 0 {
+		return true
+	}
+
+	canonical, err := ParseReviewState(state)
+	if err != nil {
+		return false
+	}
+
+	for _, s := range states {
+		if c, err := ParseReviewState(s); err == nil && c == canonical {
+			return true
+		}
+	}
+
+	return false
+}
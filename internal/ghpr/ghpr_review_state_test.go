@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"testing"
+)
+
+func TestParseReviewState(t *testing.T) {
+	tests := []struct {
+		state   string
+		want    string
+		wantErr string
+	}{
+		{state: "approve", want: ReviewStateApproved},
+		{state: "approved", want: ReviewStateApproved},
+		{state: "APPROVED", want: ReviewStateApproved},
+		{state: "Approval", want: ReviewStateApproved},
+		{state: "changes_requested", want: ReviewStateChangesRequested},
+		{state: "CHANGES_REQUESTED", want: ReviewStateChangesRequested},
+		{state: "changes-requested", want: ReviewStateChangesRequested},
+		{state: "request_changes", want: ReviewStateChangesRequested},
+		{state: "request changes", want: ReviewStateChangesRequested},
+		{state: "changes", want: ReviewStateChangesRequested},
+		{state: "commented", want: ReviewStateCommented},
+		{state: "COMMENTED", want: ReviewStateCommented},
+		{state: "dismissed", want: ReviewStateDismissed},
+		{state: "DISMISSED", want: ReviewStateDismissed},
+		{state: "dismiss", want: ReviewStateDismissed},
+		{state: "pending", want: ReviewStatePending},
+		{state: "PENDING", want: ReviewStatePending},
+		{state: "comment", want: ReviewStateComment},
+		{state: " Comment ", want: ReviewStateComment},
+		{
+			state:   "lgtm",
+			wantErr: "unknown review state 'lgtm': expected one of approved, changes_requested, commented, dismissed, pending, comment",
+		},
+		{
+			state:   "",
+			wantErr: "unknown review state '': expected one of approved, changes_requested, commented, dismissed, pending, comment",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			got, err := ParseReviewState(tt.state)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ParseReviewState() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseReviewState() unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseReviewState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchesReviewState(t *testing.T) {
+	tests := []struct {
+		name   string
+		states []string
+		state  string
+		want   bool
+	}{
+		{
+			name:  "no states",
+			state: "DISMISSED",
+			want:  true,
+		},
+		{
+			name:   "synonym matches github state",
+			states: []string{"approve"},
+			state:  "APPROVED",
+			want:   true,
+		},
+		{
+			name:   "github state matches synonym",
+			states: []string{"APPROVED"},
+			state:  "approve",
+			want:   true,
+		},
+		{
+			name:   "comment is not commented",
+			states: []string{"comment"},
+			state:  "COMMENTED",
+		},
+		{
+			name:   "other state",
+			states: []string{"approved"},
+			state:  "CHANGES_REQUESTED",
+		},
+		{
+			name:   "unknown state",
+			states: []string{"lgtm"},
+			state:  "APPROVED",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesReviewState(tt.states, tt.state); got != tt.want {
+				t.Errorf("matchesReviewState(%v, %q) = %v, want %v", tt.states, tt.state, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/unikraft/governance/internal/hook"
+)
+
+// Ruleset describes the full set of merge requirements of a repository such
+// that it can be kept in a single file rather than encoded as flags.  Each
+// field corresponds to one of the mergable options, e.g. ApproverTeams to
+// WithApproverTeams.  Unset fields leave the respective option at its default.
+type Ruleset struct {
+	AllowedBaseBranches     []string       `yaml:"allowed_base_branches"`
+	ApproverComments        []string       `yaml:"approver_comments"`
+	ApproverTeams           []string       `yaml:"approver_teams"`
+	ApproveStates           []string       `yaml:"approve_states"`
+	BlockOnChangesRequested bool           `yaml:"block_on_changes_requested"`
+	BotLabels               []string       `yaml:"bot_labels"`
+	BotLogins               []string       `yaml:"bot_logins"`
+	BotPolicy               string         `yaml:"bot_policy"`
+	IgnoreAuthors           []string       `yaml:"ignore_authors"`
+	IgnoreChangesRequested  bool           `yaml:"ignore_changes_requested"`
+	IgnoreLabels            []string       `yaml:"ignore_labels"`
+	IgnoreStates            []string       `yaml:"ignore_states"`
+	IgnoreUnreadableTeams   bool           `yaml:"ignore_unreadable_teams"`
+	Labels                  []string       `yaml:"labels"`
+	MinApprovals            *int           `yaml:"min_approvals"`
+	MinReviews              *int           `yaml:"min_reviews"`
+	NoConflicts             bool           `yaml:"no_conflicts"`
+	NoDraft                 bool           `yaml:"no_draft"`
+	NoRespectAssignees      bool           `yaml:"no_respect_assignees"`
+	NoRespectReviewers      bool           `yaml:"no_respect_reviewers"`
+	RequireSignoff          bool           `yaml:"require_signoff"`
+	RequiredApprovers       []string       `yaml:"required_approvers"`
+	RequiredChecks          []string       `yaml:"required_checks"`
+	ReviewerComments        []string       `yaml:"reviewer_comments"`
+	ReviewerTeams           []string       `yaml:"reviewer_teams"`
+	ReviewStates            []string       `yaml:"review_states"`
+	States                  []string       `yaml:"states"`
+	TeamMinApprovals        map[string]int `yaml:"team_min_approvals"`
+
+	// Hooks are not part of the ruleset but are accepted such that the
+	// ruleset can share its file with --hooks, see hook.NewListOfHooksFromFile.
+	Hooks []hook.Hook `yaml:"hooks"`
+}
+
+// NewRulesetFromFile reads and validates the ruleset in the provided YAML
+// file.  Unknown keys are rejected such that a misspelt requirement is not
+// silently ignored.
+func NewRulesetFromFile(file string) (*Ruleset, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read ruleset: %w", err)
+	}
+
+	rules := &Ruleset{}
+	if err := yaml.UnmarshalStrict(raw, rules); err != nil {
+		return nil, fmt.Errorf("could not unmarshal ruleset %s: %w", file, err)
+	}
+
+	if err := rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ruleset %s: %w", file, err)
+	}
+
+	return rules, nil
+}
+
+// Validate checks that the ruleset only contains known policies, valid
+// regular expressions, non-negative minimums and does not both block on and
+// ignore requested changes.
+func (rules *Ruleset) Validate() error {
+	if err := ValidateBotPolicy(rules.BotPolicy); err != nil {
+		return err
+	}
+
+	if err := ValidateReviewStates(rules.ApproveStates); err != nil {
+		return fmt.Errorf("invalid approve_states: %w", err)
+	}
+
+	if err := ValidateReviewStates(rules.ReviewStates); err != nil {
+		return fmt.Errorf("invalid review_states: %w", err)
+	}
+
+	if rules.BlockOnChangesRequested && rules.IgnoreChangesRequested {
+		return fmt.Errorf("block_on_changes_requested and ignore_changes_requested cannot be used together")
+	}
+
+	if rules.MinApprovals != nil && *rules.MinApprovals < 0 {
+		return fmt.Errorf("min_approvals must not be negative: %d", *rules.MinApprovals)
+	}
+
+	if rules.MinReviews != nil && *rules.MinReviews < 0 {
+		return fmt.Errorf("min_reviews must not be negative: %d", *rules.MinReviews)
+	}
+
+	for team, min := range rules.TeamMinApprovals {
+		if min < 0 {
+			return fmt.Errorf("team_min_approvals of %s must not be negative: %d", team, min)
+		}
+	}
+
+	return ValidateCommentExpressions(append(rules.ApproverComments, rules.ReviewerComments...))
+}
+
+// Options returns the mergable options which are equivalent to the ruleset.
+func (rules *Ruleset) Options() []PullRequestMergableOption {
+	opts := []PullRequestMergableOption{
+		WithAllowedBaseBranches(rules.AllowedBaseBranches...),
+		WithApproverComments(rules.ApproverComments...),
+		WithApproverTeams(rules.ApproverTeams...),
+		WithApproveStates(rules.ApproveStates...),
+		WithBlockOnChangesRequested(rules.BlockOnChangesRequested),
+		WithBotLabels(rules.BotLabels...),
+		WithBotLogins(rules.BotLogins...),
+		WithBotPolicy(rules.BotPolicy),
+		WithIgnoreAuthors(rules.IgnoreAuthors...),
+		WithIgnoreChangesRequested(rules.IgnoreChangesRequested),
+		WithIgnoreLabels(rules.IgnoreLabels...),
+		WithIgnoreStates(rules.IgnoreStates...),
+		WithIgnoreUnreadableTeams(rules.IgnoreUnreadableTeams),
+		WithLabels(rules.Labels...),
+		WithNoConflicts(rules.NoConflicts),
+		WithNoDraft(rules.NoDraft),
+		WithNoRespectAssignees(rules.NoRespectAssignees),
+		WithNoRespectReviewers(rules.NoRespectReviewers),
+		WithRequireSignoff(rules.RequireSignoff),
+		WithRequiredApprovers(rules.RequiredApprovers...),
+		WithRequiredChecks(rules.RequiredChecks...),
+		WithReviewerComments(rules.ReviewerComments...),
+		WithReviewerTeams(rules.ReviewerTeams...),
+		WithReviewStates(rules.ReviewStates...),
+		WithStates(rules.States...),
+	}
+
+	if rules.MinApprovals != nil {
+		opts = append(opts, WithMinApprovals(*rules.MinApprovals))
+	}
+
+	if rules.MinReviews != nil {
+		opts = append(opts, WithMinReviews(*rules.MinReviews))
+	}
+
+	if len(rules.TeamMinApprovals) > 0 {
+		opts = append(opts, WithPerTeamMinApprovals(rules.TeamMinApprovals))
+	}
+
+	return opts
+}
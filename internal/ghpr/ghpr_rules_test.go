@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRulesetSatisfiesMergeRequirements(t *testing.T) {
+	rules, err := NewRulesetFromFile(filepath.Join("testdata", "rules.yaml"))
+	if err != nil {
+		t.Fatalf("NewRulesetFromFile() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		comments  string
+		build     string
+		signoff   string
+		wantOk    bool
+		wantInErr string
+	}{
+		{
+			name:     "all rules met",
+			comments: `[{"body":"Acked-by: Alice <alice@unikraft.io>","user":{"login":"alice"}},{"body":"Acked-by: Bob <bob@unikraft.io>","user":{"login":"bob"}}]`,
+			build:    "success",
+			signoff:  "Signed-off-by: Jane Doe <jane@unikraft.io>",
+			wantOk:   true,
+		},
+		{
+			name:      "approval from outside the team",
+			comments:  `[{"body":"Acked-by: Alice <alice@unikraft.io>","user":{"login":"alice"}},{"body":"Acked-by: Eve <eve@unikraft.io>","user":{"login":"eve"}}]`,
+			build:     "success",
+			signoff:   "Signed-off-by: Jane Doe <jane@unikraft.io>",
+			wantInErr: "approvers (1/2)",
+		},
+		{
+			name:      "approved-by is not recognised",
+			comments:  `[{"body":"Approved-by: Alice <alice@unikraft.io>","user":{"login":"alice"}},{"body":"Approved-by: Bob <bob@unikraft.io>","user":{"login":"bob"}}]`,
+			build:     "success",
+			signoff:   "Signed-off-by: Jane Doe <jane@unikraft.io>",
+			wantInErr: "approvers (0/2)",
+		},
+		{
+			name:      "required check failing",
+			comments:  `[{"body":"Acked-by: Alice <alice@unikraft.io>","user":{"login":"alice"}},{"body":"Acked-by: Bob <bob@unikraft.io>","user":{"login":"bob"}}]`,
+			build:     "failure",
+			signoff:   "Signed-off-by: Jane Doe <jane@unikraft.io>",
+			wantInErr: "build (failure)",
+		},
+		{
+			name:      "sign-off of someone else",
+			comments:  `[{"body":"Acked-by: Alice <alice@unikraft.io>","user":{"login":"alice"}},{"body":"Acked-by: Bob <bob@unikraft.io>","user":{"login":"bob"}}]`,
+			build:     "success",
+			signoff:   "Signed-off-by: Alice <alice@unikraft.io>",
+			wantInErr: "not signed off by their author: 0123456789ab",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"number":1,"state":"open","draft":false,"user":{"login":"jane"},"head":{"sha":"abc123"}}`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.comments)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[]`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/commits", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `[{"sha":"0123456789abcdef","commit":{"message":"lib/ukboot: Fix boot\n\n%s","author":{"email":"jane@unikraft.io"}}}]`, tt.signoff)
+			})
+			mux.HandleFunc("/api/v3/orgs/unikraft/teams/maintainers/members", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[{"login":"alice"},{"login":"bob"}]`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/commits/abc123/status", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"state":"success","statuses":[]}`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/commits/abc123/check-runs", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"total_count":1,"check_runs":[{"name":"build","status":"completed","conclusion":%q}]}`, tt.build)
+			})
+
+			pr := newTestPullRequest(t, mux)
+
+			ok, _, err := pr.SatisfiesMergeRequirements(context.Background(), rules.Options()...)
+			if ok != tt.wantOk {
+				t.Fatalf("SatisfiesMergeRequirements() = %v, want %v (err: %v)", ok, tt.wantOk, err)
+			}
+
+			if tt.wantInErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantInErr)) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantInErr, err)
+			}
+		})
+	}
+}
+
+func TestNewRulesetFromFileInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantInErr string
+	}{
+		{
+			name:      "unknown key",
+			content:   "min_aprovals: 2\n",
+			wantInErr: "min_aprovals",
+		},
+		{
+			name:      "negative minimum",
+			content:   "min_reviews: -1\n",
+			wantInErr: "min_reviews must not be negative",
+		},
+		{
+			name:      "invalid expression",
+			content:   "reviewer_comments: [\"Reviewed-by: (\"]\n",
+			wantInErr: "invalid comment expression",
+		},
+		{
+			name:      "blocking on and ignoring requested changes",
+			content:   "block_on_changes_requested: true\nignore_changes_requested: true\n",
+			wantInErr: "cannot be used together",
+		},
+		{
+			name:      "unknown bot policy",
+			content:   "bot_policy: never\n",
+			wantInErr: "unknown bot policy",
+		},
+		{
+			name:      "unknown review state",
+			content:   "approve_states: [lgtm]\n",
+			wantInErr: "invalid approve_states: unknown review state 'lgtm'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "rules.yaml")
+			if err := os.WriteFile(file, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := NewRulesetFromFile(file)
+			if err == nil || !strings.Contains(err.Error(), tt.wantInErr) {
+				t.Errorf("NewRulesetFromFile() error = %v, want error containing %q", err, tt.wantInErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,499 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	git "github.com/go-git/go-git/v5"
+	gitplumbing "github.com/go-git/go-git/v5/plumbing"
+	"github.com/google/go-github/v63/github"
+	kitcfg "kraftkit.sh/config"
+
+	"github.com/unikraft/governance/internal/config"
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/patch"
+)
+
+// newTestRepo creates a local git repository with the provided number of
+// commits and returns its path along with the commit hashes, oldest first.
+func newTestRepo(t *testing.T, commits int) (string, []gitplumbing.Hash) {
+	t.Helper()
+
+	subjects := make([]string, commits)
+	for i := range subjects {
+		subjects[i] = fmt.Sprintf("lib/test: Add file %d", i)
+	}
+
+	return newTestRepoWithSubjects(t, subjects)
+}
+
+// newTestRepoWithSubjects creates a local git repository with one commit per
+// provided subject and returns its path along with the commit hashes, oldest
+// first.
+func newTestRepoWithSubjects(t *testing.T, subjects []string) (string, []gitplumbing.Hash) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Doe",
+			"GIT_AUTHOR_EMAIL=jane@unikraft.io",
+			"GIT_COMMITTER_NAME=Jane Doe",
+			"GIT_COMMITTER_EMAIL=jane@unikraft.io",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	run("init", "-q")
+
+	for i, subject := range subjects {
+		name := filepath.Join(dir, fmt.Sprintf("file%d.c", i))
+		if err := os.WriteFile(name, []byte(fmt.Sprintf("int x%d;\n", i)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		run("add", ".")
+		run("commit", "-q", "-m", subject)
+	}
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	itr, err := repo.Log(&git.LogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hashes []gitplumbing.Hash
+	for {
+		c, err := itr.Next()
+		if err != nil {
+			break
+		}
+		hashes = append([]gitplumbing.Hash{c.Hash}, hashes...)
+	}
+
+	return dir, hashes
+}
+
+func TestGeneratePatches(t *testing.T) {
+	dir, _ := newTestRepo(t, 12)
+
+	tests := []struct {
+		name       string
+		expected   int
+		maxPatches int
+		wantCount  int
+		wantErr    bool
+	}{
+		{
+			name:      "stops at expected commit count",
+			expected:  2,
+			wantCount: 2,
+		},
+		{
+			name:    "no commits reported",
+			wantErr: true,
+		},
+		{
+			name:       "commit count exceeds cap",
+			expected:   6,
+			maxPatches: 5,
+			wantErr:    true,
+		},
+		{
+			name:       "within cap",
+			expected:   3,
+			maxPatches: 5,
+			wantCount:  3,
+		},
+		{
+			name:     "history shorter than commit count",
+			expected: 100,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := git.PlainOpen(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			itr, err := repo.Log(&git.LogOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pr := &PullRequest{
+				localRepo:  dir,
+				workdir:    t.TempDir(),
+				ghRepo:     "unikraft",
+				ghPrId:     1,
+				maxPatches: tt.maxPatches,
+			}
+
+			err = pr.generatePatches(context.Background(), itr, tt.expected)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("generatePatches() expected error, generated %d patches", len(pr.Patches()))
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("generatePatches() unexpected error: %v", err)
+			}
+
+			if got := len(pr.Patches()); got != tt.wantCount {
+				t.Errorf("generatePatches() generated %d patches, want %d", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestGeneratePatchesUnicodeTitles(t *testing.T) {
+	long := "lib/test: " + strings.Repeat("Add a very long subject ", 12) + "done"
+
+	subjects := []string{
+		"lib/test: Initial commit",
+		"🐛 lib/test: Fix 🔥 boot ✨",
+		"🐛 lib/test: Fix 🔥 boot 🚀",
+		"docs: 添加中文文档",
+		long + " 1",
+		long + " 2",
+	}
+
+	dir, _ := newTestRepoWithSubjects(t, subjects)
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	itr, err := repo.Log(&git.LogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workdir := t.TempDir()
+	pr := &PullRequest{
+		localRepo: dir,
+		workdir:   workdir,
+		ghRepo:    "unikraft",
+		ghPrId:    1,
+	}
+
+	// The log is walked newest first and the initial commit has no parent to
+	// diff against, so every other subject produces a patch.
+	if err := pr.generatePatches(context.Background(), itr, len(subjects)-1); err != nil {
+		t.Fatalf("generatePatches() unexpected error: %v", err)
+	}
+
+	want := make(map[string]bool)
+	for _, subject := range subjects {
+		want[subject] = true
+	}
+
+	seen := make(map[string]string)
+	for _, p := range pr.Patches() {
+		if !want[p.Title] {
+			t.Errorf("patch title %q does not match any commit subject", p.Title)
+		}
+
+		if !strings.Contains(p.Diff, "int x") {
+			t.Errorf("patch %q lost its diff: %q", p.Title, p.Diff)
+		}
+
+		if filepath.Dir(p.Filename) != workdir {
+			t.Errorf("patch %q is written outside the workdir: %s", p.Title, p.Filename)
+		}
+
+		base := filepath.Base(p.Filename)
+		if len(base) > 255 {
+			t.Errorf("patch %q has a %d byte filename", p.Title, len(base))
+		}
+
+		if other, ok := seen[base]; ok {
+			t.Errorf("patches %q and %q share the filename %s", p.Title, other, base)
+		}
+
+		seen[base] = p.Title
+
+		if err := os.WriteFile(p.Filename, p.Bytes(), 0o644); err != nil {
+			t.Errorf("could not write patch %q: %v", p.Title, err)
+		}
+	}
+}
+
+func TestVerifyPatches(t *testing.T) {
+	patches := []*patch.Patch{
+		{Title: "lib/test: Add file 2", AuthorName: "Jane Doe", AuthorEmail: "jane@unikraft.io"},
+		{Title: "lib/test: Add file 1", AuthorName: "Jane Doe", AuthorEmail: "jane@unikraft.io"},
+	}
+
+	commit := func(message, name, email string) *github.RepositoryCommit {
+		return &github.RepositoryCommit{
+			SHA: github.String("abc"),
+			Commit: &github.Commit{
+				Message: github.String(message),
+				Author: &github.CommitAuthor{
+					Name:  github.String(name),
+					Email: github.String(email),
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		commits []*github.RepositoryCommit
+		wantErr bool
+	}{
+		{
+			name: "matching series",
+			commits: []*github.RepositoryCommit{
+				commit("lib/test: Add file 1\n\nSigned-off-by: Jane Doe <jane@unikraft.io>", "Jane Doe", "jane@unikraft.io"),
+				commit("lib/test: Add file 2", "Jane Doe", "jane@unikraft.io"),
+			},
+		},
+		{
+			name: "reversed series",
+			commits: []*github.RepositoryCommit{
+				commit("lib/test: Add file 2", "Jane Doe", "jane@unikraft.io"),
+				commit("lib/test: Add file 1", "Jane Doe", "jane@unikraft.io"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "different author",
+			commits: []*github.RepositoryCommit{
+				commit("lib/test: Add file 1", "John Doe", "john@unikraft.io"),
+				commit("lib/test: Add file 2", "Jane Doe", "jane@unikraft.io"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "different length",
+			commits: []*github.RepositoryCommit{
+				commit("lib/test: Add file 2", "Jane Doe", "jane@unikraft.io"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyPatches(patches, tt.commits)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "rebase produced unexpected commits") {
+					t.Fatalf("verifyPatches() error = %v, want unexpected commits error", err)
+				}
+			} else if err != nil {
+				t.Fatalf("verifyPatches() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// newOutdatedForkFixture creates an upstream repository whose 'staging' branch
+// is many commits ahead of the ancient commit from which the pull request's
+// branch was forked, and clones it into the location used for pull request 1
+// of unikraft/unikraft within workdir.
+func newOutdatedForkFixture(t *testing.T, workdir string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	upstream := t.TempDir()
+
+	run := func(dir string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Doe",
+			"GIT_AUTHOR_EMAIL=jane@unikraft.io",
+			"GIT_COMMITTER_NAME=Jane Doe",
+			"GIT_COMMITTER_EMAIL=jane@unikraft.io",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	commit := func(name, message string) {
+		if err := os.WriteFile(filepath.Join(upstream, name), []byte(message+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		run(upstream, "add", ".")
+		run(upstream, "commit", "-q", "-m", message)
+	}
+
+	run(upstream, "init", "-q", "-b", "staging")
+	commit("ancient.c", "lib/test: Add ancient file")
+	run(upstream, "branch", "fork")
+
+	for i := 0; i < 50; i++ {
+		commit(fmt.Sprintf("staging%d.c", i), fmt.Sprintf("lib/test: Add staging file %d", i))
+	}
+
+	run(upstream, "checkout", "-q", "fork")
+	commit("pr0.c", "lib/test: Add pull request file 0")
+	commit("pr1.c", "lib/test: Add pull request file 1")
+	run(upstream, "update-ref", "refs/pull/1/head", "fork")
+	run(upstream, "checkout", "-q", "staging")
+
+	run(workdir, "clone", "-q", "-b", "staging", upstream, "unikraft-pr-1")
+}
+
+func TestNewPullRequestFromIDOutdatedFork(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "")
+
+	commitsJSON := func(titles ...string) string {
+		var commits []string
+		for _, title := range titles {
+			commits = append(commits, fmt.Sprintf(`{"sha":"abc","commit":{"message":%q,"author":{"name":"Jane Doe","email":"jane@unikraft.io"}}}`, title))
+		}
+		return "[" + strings.Join(commits, ",") + "]"
+	}
+
+	tests := []struct {
+		name      string
+		commits   string
+		wantTitle []string
+		wantErr   string
+	}{
+		{
+			name:      "only the pull request's commits",
+			commits:   commitsJSON("lib/test: Add pull request file 0", "lib/test: Add pull request file 1"),
+			wantTitle: []string{"lib/test: Add pull request file 1", "lib/test: Add pull request file 0"},
+		},
+		{
+			name:    "series does not match the pull request",
+			commits: commitsJSON("lib/test: Add staging file 49", "lib/test: Add pull request file 1"),
+			wantErr: "rebase produced unexpected commits",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workdir := t.TempDir()
+			newOutdatedForkFixture(t, workdir)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"number":1,"state":"open","commits":2}`)
+			})
+			mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/commits", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.commits)
+			})
+
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			cfgm, err := kitcfg.NewConfigManager(&config.Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := kitcfg.WithConfigManager(context.Background(), cfgm)
+
+			client, err := ghapi.NewGithubClient(ctx, "token", false, srv.URL)
+			if err != nil {
+				t.Fatalf("could not create client: %v", err)
+			}
+
+			pr, err := NewPullRequestFromID(ctx, client, "unikraft", "unikraft", "Jane Doe", "jane@unikraft.io", 1, false,
+				WithWorkdir(workdir),
+				WithBaseBranch("staging"),
+			)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewPullRequestFromID() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("NewPullRequestFromID() unexpected error: %v", err)
+			}
+
+			var titles []string
+			for _, p := range pr.Patches() {
+				titles = append(titles, p.Title)
+			}
+
+			if strings.Join(titles, "\n") != strings.Join(tt.wantTitle, "\n") {
+				t.Errorf("NewPullRequestFromID() generated patches %q, want %q", titles, tt.wantTitle)
+			}
+		})
+	}
+}
+
+func TestHeadAndBaseSHA(t *testing.T) {
+	tests := []struct {
+		name     string
+		pr       *github.PullRequest
+		wantHead string
+		wantBase string
+	}{
+		{
+			name: "populated",
+			pr: &github.PullRequest{
+				Head: &github.PullRequestBranch{SHA: github.String("abc123")},
+				Base: &github.PullRequestBranch{SHA: github.String("def456")},
+			},
+			wantHead: "abc123",
+			wantBase: "def456",
+		},
+		{
+			name: "no base",
+			pr: &github.PullRequest{
+				Head: &github.PullRequestBranch{SHA: github.String("abc123")},
+			},
+			wantHead: "abc123",
+		},
+		{
+			name: "no head SHA",
+			pr: &github.PullRequest{
+				Head: &github.PullRequestBranch{Ref: github.String("feature")},
+				Base: &github.PullRequestBranch{SHA: github.String("def456")},
+			},
+			wantBase: "def456",
+		},
+		{
+			name: "no metadata",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PullRequest{pr: tt.pr}
+
+			if got := pr.HeadSHA(); got != tt.wantHead {
+				t.Errorf("HeadSHA() = %q, want %q", got, tt.wantHead)
+			}
+
+			if got := pr.BaseSHA(); got != tt.wantBase {
+				t.Errorf("BaseSHA() = %q, want %q", got, tt.wantBase)
+			}
+		})
+	}
+}
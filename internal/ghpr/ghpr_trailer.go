@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// trailerGroupPattern matches the names of the groups of comment expressions,
+// which are turned into the names of trailers.  Only ASCII is accepted such
+// that the trailer is understood by git and every other tool parsing it.
+var trailerGroupPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// TrailerName returns the name of the trailer of the provided named group of
+// a comment expression, where the first letter is capitalised and
+// underscores become dashes, e.g. "approved_by" becomes "Approved-by".
+func TrailerName(group string) (string, error) {
+	if !trailerGroupPattern.MatchString(group) {
+		return "", fmt.Errorf("invalid trailer name '%s': expected an ASCII letter followed by ASCII letters, digits or underscores", group)
+	}
+
+	return strings.ToUpper(group[:1]) + strings.ReplaceAll(group[1:], "_", "-"), nil
+}
+
+// ValidateCommentExpressions checks that every provided comment expression
+// compiles and that each of its named groups can be turned into a trailer.
+func ValidateCommentExpressions(regExs []string) error {
+	for _, regEx := range regExs {
+		compiled, err := regexp.Compile(regEx)
+		if err != nil {
+			return fmt.Errorf("invalid comment expression '%s': %w", regEx, err)
+		}
+
+		for _, name := range compiled.SubexpNames() {
+			if name == "" {
+				continue
+			}
+
+			if _, err := TrailerName(name); err != nil {
+				return fmt.Errorf("invalid comment expression '%s': %w", regEx, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ghpr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrailerName(t *testing.T) {
+	tests := []struct {
+		group   string
+		want    string
+		wantErr bool
+	}{
+		{group: "approved_by", want: "Approved-by"},
+		{group: "reviewed_by", want: "Reviewed-by"},
+		{group: "Acked_by", want: "Acked-by"},
+		{group: "tested_by2", want: "Tested-by2"},
+		{group: "x", want: "X"},
+		{group: "", wantErr: true},
+		{group: "_by", wantErr: true},
+		{group: "2nd_reviewer", wantErr: true},
+		{group: "ëpproved_by", wantErr: true},
+		{group: "ǆ_by", wantErr: true},
+		{group: "approved-by", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.group, func(t *testing.T) {
+			got, err := TrailerName(tt.group)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("TrailerName(%q) = %q, want error", tt.group, got)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("TrailerName(%q) unexpected error: %v", tt.group, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("TrailerName(%q) = %q, want %q", tt.group, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCommentExpressions(t *testing.T) {
+	tests := []struct {
+		name      string
+		regExs    []string
+		wantInErr string
+	}{
+		{
+			name:   "default expressions",
+			regExs: []string{`(?i)^Approved-by: (?P<approved_by>.*)$`, `(?i)^Reviewed-by: (?P<reviewed_by>.*)$`},
+		},
+		{
+			name:   "no named groups",
+			regExs: []string{`^LGTM$`},
+		},
+		{
+			name:      "does not compile",
+			regExs:    []string{`(?P<approved_by>`},
+			wantInErr: "invalid comment expression",
+		},
+		{
+			name:      "group starting with a digit",
+			regExs:    []string{`^Approved-by: (?P<1approved>.*)$`},
+			wantInErr: "invalid trailer name '1approved'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCommentExpressions(tt.regExs)
+			if tt.wantInErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateCommentExpressions() unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantInErr) {
+				t.Errorf("ValidateCommentExpressions() error = %v, want it to contain %q", err, tt.wantInErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+// Package hook runs external executables at defined points of evaluating and
+// merging a pull request, such that organisations can enforce policies which
+// do not belong in governctl itself, e.g. a release freeze.
+package hook
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+	"gopkg.in/yaml.v2"
+)
+
+// Point is a point of evaluating or merging a pull request at which hooks are
+// run.  Hooks at a pre-point can fail the operation, whereas hooks at a
+// post-point are only informed of its outcome.
+type Point string
+
+const (
+	// PointPreMergability is before the merge requirements are evaluated.
+	PointPreMergability = Point("pre_mergability")
+
+	// PointPostMergability is after the merge requirements have been
+	// evaluated, whatever the outcome.
+	PointPostMergability = Point("post_mergability")
+
+	// PointPreMergePush is before the merged commits are pushed to the base
+	// branch.
+	PointPreMergePush = Point("pre_merge_push")
+
+	// PointPostMerge is after the merged commits have been pushed.
+	PointPostMerge = Point("post_merge")
+)
+
+// Points are all points at which hooks can be run.
+var Points = []Point{
+	PointPreMergability,
+	PointPostMergability,
+	PointPreMergePush,
+	PointPostMerge,
+}
+
+// Pre returns whether hooks at the point can fail the operation.
+func (p Point) Pre() bool {
+	return strings.HasPrefix(string(p), "pre_")
+}
+
+// DefaultTimeout is the maximum duration of a hook which does not set one.
+const DefaultTimeout = 30 * time.Second
+
+// Hook is an executable which is run at a point with the description of the
+// pull request, see Payload, on its standard input.
+type Hook struct {
+	// Name identifies the hook in logs and errors.  It defaults to the command.
+	Name string `yaml:"name"`
+
+	// Point is when the hook is run.
+	Point Point `yaml:"point"`
+
+	// Command is the executable, which is looked up in the PATH unless it
+	// contains a slash.  Relative paths are relative to the hooks file.
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+
+	// Timeout is the maximum duration of the hook, e.g. 10s.  It defaults to
+	// DefaultTimeout.
+	Timeout string `yaml:"timeout"`
+
+	// PassToken provides the GitHub token to the hook as GITHUB_TOKEN.  The
+	// environment of hooks is otherwise stripped of credentials.
+	PassToken bool `yaml:"pass_token"`
+}
+
+// Validate rejects hooks which cannot be run.
+func (h *Hook) Validate() error {
+	if h.Command == "" {
+		return fmt.Errorf("hook '%s' does not set a command", h.Name)
+	}
+
+	known := false
+	for _, point := range Points {
+		known = known || h.Point == point
+	}
+
+	if !known {
+		return fmt.Errorf("hook '%s' has unknown point '%s': expected one of pre_mergability, post_mergability, pre_merge_push or post_merge", h.Name, h.Point)
+	}
+
+	if _, err := h.timeout(); err != nil {
+		return fmt.Errorf("hook '%s' has invalid timeout '%s': %w", h.Name, h.Timeout, err)
+	}
+
+	return nil
+}
+
+// timeout returns the maximum duration of the hook.
+func (h *Hook) timeout() (time.Duration, error) {
+	if h.Timeout == "" {
+		return DefaultTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(h.Timeout)
+	if err != nil {
+		return 0, err
+	} else if timeout <= 0 {
+		return 0, fmt.Errorf("must be positive")
+	}
+
+	return timeout, nil
+}
+
+// NewListOfHooksFromFile reads the hooks: section of the provided YAML file,
+// which may be shared with other settings, e.g. a ruleset.
+func NewListOfHooksFromFile(file string) ([]Hook, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read hooks: %w", err)
+	}
+
+	var doc struct {
+		Hooks []Hook `yaml:"hooks"`
+	}
+
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("could not unmarshal hooks %s: %w", file, err)
+	}
+
+	for i := range doc.Hooks {
+		h := &doc.Hooks[i]
+
+		if h.Name == "" {
+			h.Name = h.Command
+		}
+
+		if err := h.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid hooks %s: %w", file, err)
+		}
+
+		if strings.Contains(h.Command, "/") && !filepath.IsAbs(h.Command) {
+			h.Command = filepath.Join(filepath.Dir(file), h.Command)
+		}
+	}
+
+	return doc.Hooks, nil
+}
+
+// Payload is the description of the pull request and of the state of its
+// evaluation which hooks receive as JSON on their standard input.
+type Payload struct {
+	Point       Point    `json:"point"`
+	Org         string   `json:"org"`
+	Repo        string   `json:"repo"`
+	PullRequest int      `json:"pull_request"`
+	Title       string   `json:"title"`
+	Author      string   `json:"author"`
+	Base        string   `json:"base"`
+	Head        string   `json:"head"`
+	HeadSHA     string   `json:"head_sha"`
+	Labels      []string `json:"labels"`
+	DryRun      bool     `json:"dry_run"`
+
+	// Mergable and Results are the outcome of evaluating the merge
+	// requirements, which are only known from PointPostMergability onwards.
+	// Error is why the pull request is not mergable.
+	Mergable *bool               `json:"mergable,omitempty"`
+	Results  map[string][]string `json:"results,omitempty"`
+	Error    string              `json:"error,omitempty"`
+
+	// Commits and Trailers are the titles of the merged commits and the
+	// trailers appended to them, which are only known when merging.
+	Commits  []string `json:"commits,omitempty"`
+	Trailers []string `json:"trailers,omitempty"`
+}
+
+// NewPayload returns the description of the provided pull request.
+func NewPayload(org, repo string, pr *github.PullRequest, dryRun bool) Payload {
+	payload := Payload{
+		Org:         org,
+		Repo:        repo,
+		PullRequest: pr.GetNumber(),
+		Title:       pr.GetTitle(),
+		Author:      pr.GetUser().GetLogin(),
+		Base:        pr.GetBase().GetRef(),
+		Head:        pr.GetHead().GetRef(),
+		HeadSHA:     pr.GetHead().GetSHA(),
+		Labels:      []string{},
+		DryRun:      dryRun,
+	}
+
+	for _, label := range pr.Labels {
+		payload.Labels = append(payload.Labels, label.GetName())
+	}
+
+	return payload
+}
+
+// WithMergability returns a copy of the payload with the outcome of
+// evaluating the merge requirements.
+func (p Payload) WithMergability(mergable bool, results map[string][]string, err error) Payload {
+	p.Mergable = &mergable
+	p.Results = results
+
+	if err != nil {
+		p.Error = err.Error()
+	}
+
+	return p
+}
@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package hook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+)
+
+func testPayload(base string, labels ...string) Payload {
+	pr := &github.PullRequest{
+		Number: github.Int(42),
+		Title:  github.String("lib/ukboot: Fix boot"),
+		User:   &github.User{Login: github.String("alice")},
+		Base:   &github.PullRequestBranch{Ref: github.String(base)},
+		Head:   &github.PullRequestBranch{Ref: github.String("fix-boot"), SHA: github.String("abc123")},
+	}
+
+	for _, label := range labels {
+		pr.Labels = append(pr.Labels, &github.Label{Name: github.String(label)})
+	}
+
+	payload := NewPayload("unikraft", "unikraft", pr, false)
+	payload.Point = PointPreMergability
+
+	return payload
+}
+
+func TestNewListOfHooksFromFile(t *testing.T) {
+	hooks, err := NewListOfHooksFromFile(filepath.Join("testdata", "hooks.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hooks) != 1 {
+		t.Fatalf("got %d hooks, want 1", len(hooks))
+	}
+
+	if want := filepath.Join("testdata", "freeze.sh"); hooks[0].Command != want {
+		t.Errorf("Command = %q, want %q", hooks[0].Command, want)
+	}
+}
+
+func TestHookValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    Hook
+		wantErr string
+	}{
+		{
+			name: "valid",
+			hook: Hook{Name: "a", Point: PointPostMerge, Command: "true", Timeout: "1s"},
+		},
+		{
+			name:    "no command",
+			hook:    Hook{Name: "a", Point: PointPostMerge},
+			wantErr: "does not set a command",
+		},
+		{
+			name:    "unknown point",
+			hook:    Hook{Name: "a", Point: "pre_push", Command: "true"},
+			wantErr: "unknown point 'pre_push'",
+		},
+		{
+			name:    "invalid timeout",
+			hook:    Hook{Name: "a", Point: PointPostMerge, Command: "true", Timeout: "-1s"},
+			wantErr: "invalid timeout",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.hook.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Validate() unexpected error: %v", err)
+			} else if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunnerRun(t *testing.T) {
+	hooks, err := NewListOfHooksFromFile(filepath.Join("testdata", "hooks.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		hooks   []Hook
+		payload Payload
+		wantErr string
+	}{
+		{
+			name:    "allow",
+			hooks:   hooks,
+			payload: testPayload("staging"),
+		},
+		{
+			name:    "allow with exception",
+			hooks:   hooks,
+			payload: testPayload("stable", "freeze-exception"),
+		},
+		{
+			name:    "deny",
+			hooks:   hooks,
+			payload: testPayload("stable"),
+			wantErr: "pre_mergability hook 'freeze' failed: branch 'stable' is frozen for the release",
+		},
+		{
+			name: "timeout",
+			hooks: []Hook{{
+				Name:    "slow",
+				Point:   PointPreMergability,
+				Command: "sleep",
+				Args:    []string{"10"},
+				Timeout: "100ms",
+			}},
+			payload: testPayload("staging"),
+			wantErr: "pre_mergability hook 'slow' timed out after 100ms",
+		},
+		{
+			name: "other point",
+			hooks: []Hook{{
+				Name:    "fail",
+				Point:   PointPreMergePush,
+				Command: "false",
+			}},
+			payload: testPayload("staging"),
+		},
+		{
+			name: "post hook failure ignored",
+			hooks: []Hook{{
+				Name:    "fail",
+				Point:   PointPostMerge,
+				Command: "false",
+			}},
+			payload: func() Payload {
+				payload := testPayload("staging")
+				payload.Point = PointPostMerge
+				return payload
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewRunner(tt.hooks).Run(context.Background(), tt.payload)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Run() unexpected error: %v", err)
+			} else if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("Run() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunnerNil(t *testing.T) {
+	var r *Runner
+	if err := r.Run(context.Background(), testPayload("stable")); err != nil {
+		t.Errorf("Run() unexpected error: %v", err)
+	}
+}
+
+func TestRunnerEnv(t *testing.T) {
+	environ := []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=/home/alice",
+		"GITHUB_REPOSITORY=unikraft/unikraft",
+		"GITHUB_TOKEN=ghp_leaked",
+		"GOVERN_GITHUB_TOKEN=ghp_leaked",
+		"AWS_SECRET_ACCESS_KEY=leaked",
+		"EDITOR=vim",
+	}
+
+	tests := []struct {
+		name      string
+		passToken bool
+		want      []string
+	}{
+		{
+			name: "sanitized",
+			want: []string{
+				"PATH=" + os.Getenv("PATH"),
+				"HOME=/home/alice",
+				"GITHUB_REPOSITORY=unikraft/unikraft",
+				"GOVERN_HOOK_NAME=env",
+				"GOVERN_HOOK_POINT=pre_merge_push",
+			},
+		},
+		{
+			name:      "pass token",
+			passToken: true,
+			want: []string{
+				"PATH=" + os.Getenv("PATH"),
+				"HOME=/home/alice",
+				"GITHUB_REPOSITORY=unikraft/unikraft",
+				"GOVERN_HOOK_NAME=env",
+				"GOVERN_HOOK_POINT=pre_merge_push",
+				"GITHUB_TOKEN=ghp_passed",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "env")
+
+			r := NewRunner([]Hook{{
+				Name:      "env",
+				Point:     PointPreMergePush,
+				Command:   "sh",
+				Args:      []string{"-c", "env > " + out},
+				PassToken: tt.passToken,
+			}}, WithToken("ghp_passed"), WithEnviron(environ))
+
+			payload := testPayload("staging")
+			payload.Point = PointPreMergePush
+
+			if err := r.Run(context.Background(), payload); err != nil {
+				t.Fatal(err)
+			}
+
+			raw, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := map[string]bool{}
+			for _, kv := range strings.Split(strings.TrimSpace(string(raw)), "\n") {
+				got[kv] = true
+			}
+
+			for _, kv := range tt.want {
+				if !got[kv] {
+					t.Errorf("env does not contain %q", kv)
+				}
+			}
+
+			for kv := range got {
+				if strings.Contains(kv, "leaked") || strings.HasPrefix(kv, "EDITOR=") {
+					t.Errorf("env contains %q", kv)
+				}
+			}
+		})
+	}
+}
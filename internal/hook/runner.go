@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"kraftkit.sh/log"
+)
+
+// waitDelay is how long a hook's output is awaited after it has been killed,
+// e.g. when a child process it spawned still holds it open.
+const waitDelay = time.Second
+
+// passedVariables are the variables of governctl's environment which hooks
+// receive, besides those with one of passedPrefixes.  Everything else, in
+// particular credentials, is stripped.
+var passedVariables = []string{
+	"HOME",
+	"LANG",
+	"LOGNAME",
+	"PATH",
+	"SHELL",
+	"TMPDIR",
+	"TZ",
+	"USER",
+}
+
+// passedPrefixes are the prefixes of the variables which hooks receive, such
+// as the context provided by GitHub Actions.
+var passedPrefixes = []string{
+	"GITHUB_",
+	"LC_",
+	"RUNNER_",
+}
+
+// sensitiveWords mark variables which are stripped even if they would
+// otherwise be passed, e.g. GITHUB_TOKEN.
+var sensitiveWords = []string{
+	"KEY",
+	"PASSWORD",
+	"SECRET",
+	"TOKEN",
+}
+
+// Runner runs the hooks of each point.  A nil runner runs no hooks, e.g. when
+// hooks are disabled.
+type Runner struct {
+	hooks   []Hook
+	token   string
+	environ []string
+}
+
+// RunnerOption is used to customize how hooks are run.
+type RunnerOption func(*Runner)
+
+// WithToken sets the GitHub token which is passed to hooks which set
+// PassToken.
+func WithToken(token string) RunnerOption {
+	return func(r *Runner) {
+		r.token = token
+	}
+}
+
+// WithEnviron sets the environment which is sanitized for hooks instead of
+// that of governctl.
+func WithEnviron(environ []string) RunnerOption {
+	return func(r *Runner) {
+		r.environ = environ
+	}
+}
+
+// NewRunner returns a runner of the provided hooks.
+func NewRunner(hooks []Hook, opts ...RunnerOption) *Runner {
+	r := &Runner{
+		hooks:   hooks,
+		environ: os.Environ(),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Run runs every hook at the point of the payload in order.  The first hook
+// at a pre-point which fails, i.e. exits non-zero or times out, fails the
+// operation with its standard error.  Failures of hooks at a post-point are
+// only logged.
+func (r *Runner) Run(ctx context.Context, payload Payload) error {
+	if r == nil {
+		return nil
+	}
+
+	stdin, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("could not marshal hook payload: %w", err)
+	}
+
+	for _, h := range r.hooks {
+		if h.Point != payload.Point {
+			continue
+		}
+
+		log.G(ctx).
+			WithField("hook", h.Name).
+			WithField("point", h.Point).
+			Info("running hook")
+
+		if err := r.run(ctx, h, stdin); err != nil {
+			if payload.Point.Pre() {
+				return err
+			}
+
+			log.G(ctx).
+				WithField("hook", h.Name).
+				WithField("point", h.Point).
+				Warn(err)
+		}
+	}
+
+	return nil
+}
+
+// run runs a single hook with the payload on its standard input.
+func (r *Runner) run(ctx context.Context, h Hook, stdin []byte) error {
+	timeout, err := h.timeout()
+	if err != nil {
+		return fmt.Errorf("%s hook '%s' has invalid timeout: %w", h.Point, h.Name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	cmd := exec.CommandContext(ctx, h.Command, h.Args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Env = r.env(h)
+	cmd.WaitDelay = waitDelay
+
+	err = cmd.Run()
+
+	if out := strings.TrimSpace(stdout.String()); out != "" {
+		log.G(ctx).WithField("hook", h.Name).Debug(out)
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s hook '%s' timed out after %s", h.Point, h.Name, timeout)
+	} else if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s hook '%s' failed: %s", h.Point, h.Name, msg)
+		}
+
+		return fmt.Errorf("%s hook '%s' failed: %w", h.Point, h.Name, err)
+	}
+
+	return nil
+}
+
+// env returns the sanitized environment of the hook.
+func (r *Runner) env(h Hook) []string {
+	var env []string
+
+	for _, kv := range r.environ {
+		name, _, _ := strings.Cut(kv, "=")
+		if passed(name) {
+			env = append(env, kv)
+		}
+	}
+
+	env = append(env,
+		"GOVERN_HOOK_NAME="+h.Name,
+		"GOVERN_HOOK_POINT="+string(h.Point),
+	)
+
+	if h.PassToken && r.token != "" {
+		env = append(env, "GITHUB_TOKEN="+r.token)
+	}
+
+	return env
+}
+
+// passed returns whether the variable is passed to hooks.
+func passed(name string) bool {
+	for _, word := range sensitiveWords {
+		if strings.Contains(strings.ToUpper(name), word) {
+			return false
+		}
+	}
+
+	for _, prefix := range passedPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	for _, variable := range passedVariables {
+		if name == variable {
+			return true
+		}
+	}
+
+	return false
+}
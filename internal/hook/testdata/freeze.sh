@@ -0,0 +1,32 @@
+#!/bin/sh
+# SPDX-License-Identifier: BSD-3-Clause
+# Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+# Licensed under the BSD-3-Clause License (the "License").
+# You may not use this file except in compliance with the License.
+#
+# Example pre_mergability hook which enforces a release freeze: pull requests
+# into any of the branches provided as arguments are rejected unless they are
+# labelled "freeze-exception".  The payload is read from standard input and
+# anything written to standard error is reported as the reason.
+#
+#   hooks:
+#     - name: freeze
+#       point: pre_mergability
+#       command: ./freeze.sh
+#       args: [stable, staging]
+#       timeout: 5s
+
+payload=$(cat)
+
+if echo "$payload" | grep -q '"freeze-exception"'; then
+  exit 0
+fi
+
+for branch in "$@"; do
+  if echo "$payload" | grep -q "\"base\":\"$branch\""; then
+    echo "branch '$branch' is frozen for the release" >&2
+    exit 1
+  fi
+done
+
+exit 0
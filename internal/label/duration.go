@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package label
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// days matches a duration which starts with a number of days, e.g. "3d" or
+// "1d12h", which time.ParseDuration does not support.
+var days = regexp.MustCompile(`^(\d+)d(.*)$`)
+
+// Duration is a time.Duration which is written in YAML as a Go duration
+// string, e.g. "72h", or additionally with a number of days, e.g. "3d".
+type Duration time.Duration
+
+// ParseDuration parses a Go duration string which may start with a number of
+// days, e.g. "3d" or "1d12h".  Negative durations are rejected.
+func ParseDuration(s string) (Duration, error) {
+	var d time.Duration
+
+	if m := days.FindStringSubmatch(s); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s': %w", s, err)
+		}
+
+		d = time.Duration(n) * 24 * time.Hour
+		s = m[2]
+
+		if s == "" {
+			return Duration(d), nil
+		}
+	}
+
+	rest, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %w", err)
+	}
+
+	if rest < 0 {
+		return 0, fmt.Errorf("invalid duration '%s': must not be negative", s)
+	}
+
+	return Duration(d + rest), nil
+}
+
+// UnmarshalYAML parses the duration from a Go duration string or a number of
+// days.  Bare numbers other than zero are rejected since their unit would be
+// ambiguous.
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		if n != 0 {
+			return fmt.Errorf("invalid duration '%s': missing unit, e.g. '%sh' or '%sd'", s, s, s)
+		}
+
+		*d = 0
+		return nil
+	}
+
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// MarshalYAML writes the duration as a Go duration string.
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).String(), nil
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package label
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestDurationUnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		yaml    string
+		want    time.Duration
+		wantErr bool
+	}{
+		{yaml: `72h`, want: 72 * time.Hour},
+		{yaml: `"72h"`, want: 72 * time.Hour},
+		{yaml: `90m`, want: 90 * time.Minute},
+		{yaml: `3d`, want: 72 * time.Hour},
+		{yaml: `1d12h`, want: 36 * time.Hour},
+		{yaml: `0s`, want: 0},
+		{yaml: `0`, want: 0},
+		{yaml: `72`, wantErr: true},
+		{yaml: `1.5`, wantErr: true},
+		{yaml: `-1h`, wantErr: true},
+		{yaml: `3w`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.yaml, func(t *testing.T) {
+			var got struct {
+				After Duration `yaml:"after"`
+			}
+
+			err := yaml.Unmarshal([]byte("after: "+tt.yaml), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Unmarshal() = %s, want error", time.Duration(got.After))
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Unmarshal() unexpected error: %v", err)
+			}
+
+			if time.Duration(got.After) != tt.want {
+				t.Errorf("Unmarshal() = %s, want %s", time.Duration(got.After), tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationMarshalYAML(t *testing.T) {
+	out, err := yaml.Marshal(struct {
+		After Duration `yaml:"after"`
+	}{After: Duration(72 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(out), "after: 72h0m0s\n"; got != want {
+		t.Errorf("Marshal() = %q, want %q", got, want)
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path"
-	"time"
+	"regexp"
 
 	"github.com/bmatcuk/doublestar"
 	"github.com/unikraft/governance/internal/ghapi"
@@ -17,14 +17,19 @@ import (
 
 type Label struct {
 	ghApi                    *ghapi.GithubClient
-	Name                     string        `yaml:"name"`
-	Description              string        `yaml:"description"`
-	Color                    string        `yaml:"color"`
-	ApplyOnPrMatchRepos      []string      `yaml:"apply_on_pr_match_repos"`
-	ApplyOnPrMatchPaths      []string      `yaml:"apply_on_pr_match_paths"`
-	ApplyAfter               time.Duration `yaml:"apply_after"`
-	RemoveAfter              time.Duration `yaml:"remove_after"`
-	DoNotRemoveIfLabelsExist []string      `yaml:"do_not_remove_if_labels_exist"`
+	Name                     string   `yaml:"name"`
+	Description              string   `yaml:"description"`
+	Color                    string   `yaml:"color"`
+	ApplyOnPrMatchRepos      []string `yaml:"apply_on_pr_match_repos"`
+	ApplyOnPrMatchPaths      []string `yaml:"apply_on_pr_match_paths"`
+	ApplyOnTitleMatch        []string `yaml:"apply_on_title_match"`
+	ApplyOnBaseBranchMatch   []string `yaml:"apply_on_base_branch_match"`
+	ApplyOnHeadBranchMatch   []string `yaml:"apply_on_head_branch_match"`
+	ApplyAfter               Duration `yaml:"apply_after"`
+	ApplyAfterEvent          Event    `yaml:"apply_after_event"`
+	RemoveAfter              Duration `yaml:"remove_after"`
+	RemoveAfterEvent         Event    `yaml:"remove_after_event"`
+	DoNotRemoveIfLabelsExist []string `yaml:"do_not_remove_if_labels_exist"`
 }
 
 type Labels struct {
@@ -52,6 +57,14 @@ func NewListOfLabelsFromYAML(ghApi *ghapi.GithubClient, githubOrg, labelsFile st
 			return nil, fmt.Errorf("label name not provided for %s", labelsFile)
 		}
 
+		if err := ValidateEvent(label.ApplyAfterEvent); err != nil {
+			return nil, fmt.Errorf("invalid apply_after_event of label %s: %w", label.Name, err)
+		}
+
+		if err := ValidateEvent(label.RemoveAfterEvent); err != nil {
+			return nil, fmt.Errorf("invalid remove_after_event of label %s: %w", label.Name, err)
+		}
+
 		label.ghApi = ghApi
 		labels = append(labels, label)
 	}
@@ -84,21 +97,67 @@ func NewListOfLabelsFromPath(ghApi *ghapi.GithubClient, githubOrg, labelsDir str
 	return labels, nil
 }
 
+// AppliesTo determines whether the label should be applied to a pull request
+// in the provided repository which modifies the provided file.
 func (l *Label) AppliesTo(repo, file string) bool {
-	if l.ApplyOnPrMatchRepos != nil && len(l.ApplyOnPrMatchRepos) > 0 {
-		for _, c := range l.ApplyOnPrMatchRepos {
-			if c == repo {
-				goto checkMatchPaths
-			}
+	if !l.appliesToRepo(repo) {
+		return false
+	}
+
+	for _, p := range l.ApplyOnPrMatchPaths {
+		if ok, _ := doublestar.Match(p, file); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AppliesToTitle determines whether the label should be applied to a pull
+// request in the provided repository based on its title matching any of the
+// regular expressions.
+func (l *Label) AppliesToTitle(repo, title string) bool {
+	if !l.appliesToRepo(repo) || len(title) == 0 {
+		return false
+	}
+
+	for _, p := range l.ApplyOnTitleMatch {
+		if ok, _ := regexp.MatchString(p, title); ok {
+			return true
 		}
+	}
 
+	return false
+}
+
+// IsAutomatic returns whether the label has any matchers which allow it to be
+// applied automatically to a pull request.
+func (l *Label) IsAutomatic() bool {
+	return len(l.ApplyOnPrMatchPaths) > 0 ||
+		len(l.ApplyOnTitleMatch) > 0 ||
+		len(l.ApplyOnBaseBranchMatch) > 0 ||
+		len(l.ApplyOnHeadBranchMatch) > 0
+}
+
+// AppliesToPullRequest determines whether the label should be applied to a
+// pull request in the provided repository based on the name of the branch it
+// intends to merge into (base) or the branch it originates from (head).
+func (l *Label) AppliesToPullRequest(repo, base, head string) bool {
+	if !l.appliesToRepo(repo) {
 		return false
 	}
 
-checkMatchPaths:
-	if l.ApplyOnPrMatchPaths != nil && len(l.ApplyOnPrMatchPaths) > 0 {
-		for _, p := range l.ApplyOnPrMatchPaths {
-			if ok, _ := doublestar.Match(p, file); ok {
+	if len(base) > 0 {
+		for _, p := range l.ApplyOnBaseBranchMatch {
+			if ok, _ := doublestar.Match(p, base); ok {
+				return true
+			}
+		}
+	}
+
+	if len(head) > 0 {
+		for _, p := range l.ApplyOnHeadBranchMatch {
+			if ok, _ := doublestar.Match(p, head); ok {
 				return true
 			}
 		}
@@ -106,3 +165,19 @@ checkMatchPaths:
 
 	return false
 }
+
+// appliesToRepo checks whether the label is restricted to a set of
+// repositories and, if so, whether the provided repository is one of them.
+func (l *Label) appliesToRepo(repo string) bool {
+	if len(l.ApplyOnPrMatchRepos) == 0 {
+		return true
+	}
+
+	for _, c := range l.ApplyOnPrMatchRepos {
+		if c == repo {
+			return true
+		}
+	}
+
+	return false
+}
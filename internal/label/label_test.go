@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+package label
+
+import "testing"
+
+func TestAppliesToPullRequest(t *testing.T) {
+	tests := []struct {
+		name  string
+		label Label
+		repo  string
+		base  string
+		head  string
+		want  bool
+	}{
+		{
+			name: "base matches stable glob",
+			label: Label{
+				ApplyOnBaseBranchMatch: []string{"stable/*"},
+			},
+			repo: "unikraft",
+			base: "stable/v0.17",
+			head: "feature",
+			want: true,
+		},
+		{
+			name: "base does not match stable glob",
+			label: Label{
+				ApplyOnBaseBranchMatch: []string{"stable/*"},
+			},
+			repo: "unikraft",
+			base: "staging",
+			head: "stable/v0.17",
+			want: false,
+		},
+		{
+			name: "head matches dependabot glob",
+			label: Label{
+				ApplyOnHeadBranchMatch: []string{"dependabot/*"},
+			},
+			repo: "unikraft",
+			base: "staging",
+			head: "dependabot/github_actions",
+			want: true,
+		},
+		{
+			name: "head matches nested dependabot glob",
+			label: Label{
+				ApplyOnHeadBranchMatch: []string{"dependabot/**"},
+			},
+			repo: "unikraft",
+			base: "staging",
+			head: "dependabot/go_modules/golang.org/x/net-0.23.0",
+			want: true,
+		},
+		{
+			name: "head does not match dependabot glob",
+			label: Label{
+				ApplyOnHeadBranchMatch: []string{"dependabot/*"},
+			},
+			repo: "unikraft",
+			base: "dependabot/github_actions",
+			head: "feature",
+			want: false,
+		},
+		{
+			name: "restricted to another repository",
+			label: Label{
+				ApplyOnPrMatchRepos:    []string{"lib-lwip"},
+				ApplyOnBaseBranchMatch: []string{"stable/*"},
+			},
+			repo: "unikraft",
+			base: "stable/v0.17",
+			want: false,
+		},
+		{
+			name: "no branch matchers",
+			label: Label{
+				ApplyOnPrMatchPaths: []string{"arch/**"},
+			},
+			repo: "unikraft",
+			base: "stable/v0.17",
+			head: "dependabot/github_actions",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.label.AppliesToPullRequest(tt.repo, tt.base, tt.head); got != tt.want {
+				t.Errorf("AppliesToPullRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppliesTo(t *testing.T) {
+	l := Label{
+		ApplyOnPrMatchRepos: []string{"unikraft"},
+		ApplyOnPrMatchPaths: []string{"arch/**"},
+	}
+
+	if !l.AppliesTo("unikraft", "arch/x86/x86_64/include/uk/asm.h") {
+		t.Errorf("AppliesTo() = false, want true")
+	}
+	if l.AppliesTo("lib-lwip", "arch/x86/x86_64/include/uk/asm.h") {
+		t.Errorf("AppliesTo() = true, want false")
+	}
+	if l.AppliesTo("unikraft", "lib/ukboot/boot.c") {
+		t.Errorf("AppliesTo() = true, want false")
+	}
+}
+
+func TestRenameDefinition(t *testing.T) {
+	src := `labels:
+  - name: bug
+    description: Something is broken
+    color: "#ed5139"
+
+  - name: bug/fix
+    description: This PR fixes a bug
+`
+
+	tests := []struct {
+		name        string
+		color       string
+		description string
+		want        string
+	}{
+		{
+			name: "preserves color and description",
+			want: `labels:
+  - name: kind/bug
+    description: Something is broken
+    color: "#ed5139"
+
+  - name: bug/fix
+    description: This PR fixes a bug
+`,
+		},
+		{
+			name:        "overrides color and description",
+			color:       "#000000",
+			description: "A bug",
+			want: `labels:
+  - name: kind/bug
+    description: A bug
+    color: "#000000"
+
+  - name: bug/fix
+    description: This PR fixes a bug
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(RenameDefinition([]byte(src), "bug", "kind/bug", tt.color, tt.description)); got != tt.want {
+				t.Errorf("RenameDefinition() =\n%s\nwant:\n%s", got, tt.want)
+			}
+
+			if got := Defines([]byte(tt.want), "bug"); got != 0 {
+				t.Errorf("Defines() = %d, want 0", got)
+			}
+		})
+	}
+}
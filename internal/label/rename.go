@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+package label
+
+import (
+	"github.com/unikraft/governance/internal/yamledit"
+)
+
+// Defines returns the number of definitions of the named label in the YAML
+// document.
+func Defines(src []byte, name string) int {
+	return len(yamledit.FindScalars(src, "name", name))
+}
+
+// RenameDefinition renames every definition of the label in the YAML document
+// from one name to another, leaving the rest of the document untouched.  The
+// color and description of the label are only changed if they are non-empty.
+func RenameDefinition(src []byte, from, to, color, description string) []byte {
+	found := yamledit.FindScalars(src, "name", from)
+
+	// Edit from the bottom up since adding a missing color or description shifts
+	// all subsequent lines.
+	for i := len(found) - 1; i >= 0; i-- {
+		src = yamledit.ReplaceScalar(src, found[i], to)
+
+		if description != "" {
+			src = yamledit.SetSibling(src, found[i], "description", description)
+		}
+
+		if color != "" {
+			src = yamledit.SetSibling(src, found[i], "color", color)
+		}
+	}
+
+	return src
+}
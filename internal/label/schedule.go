@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package label
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// Event is an event of a pull request which the timers of a label, see
+// ApplyAfter and RemoveAfter, are anchored to.
+type Event string
+
+const (
+	// EventCreated is when the pull request was opened.  It is the anchor of
+	// timers which do not set an event.
+	EventCreated = Event("created")
+
+	// EventLastCommit is when the most recent commit was committed.
+	EventLastCommit = Event("last_commit")
+
+	// EventChangesRequested is when changes were last requested in a review,
+	// provided no commit has been pushed since.
+	EventChangesRequested = Event("changes_requested")
+
+	// EventConflictDetected is when the pull request was first seen to have
+	// merge conflicts, provided it still has them.
+	EventConflictDetected = Event("conflict_detected")
+
+	// eventLabelPrefix prefixes the name of a label which anchors a timer to
+	// when that label was last applied, provided it is still applied, e.g.
+	// "label:ci/wait".
+	eventLabelPrefix = "label:"
+)
+
+// ValidateEvent returns an error if the provided event cannot anchor a timer.
+// An empty event is valid and anchors the timer to EventCreated.
+func ValidateEvent(event Event) error {
+	switch event {
+	case "", EventCreated, EventLastCommit, EventChangesRequested, EventConflictDetected:
+		return nil
+	}
+
+	if name, ok := strings.CutPrefix(string(event), eventLabelPrefix); ok && name != "" {
+		return nil
+	}
+
+	return fmt.Errorf("unknown event '%s': expected one of created, last_commit, changes_requested, conflict_detected or label:NAME", event)
+}
+
+// Snapshot is the state and history of a pull request at a point in time
+// against which the timers of labels are evaluated.
+type Snapshot struct {
+	CreatedAt time.Time
+
+	// Labels are the names of the labels currently applied.
+	Labels []string
+
+	// Commits are the times at which the commits were committed.
+	Commits []time.Time
+
+	// ChangesRequested are the times at which reviews requesting changes were
+	// submitted.
+	ChangesRequested []time.Time
+
+	// Labeled and Unlabeled are the times at which each label was applied and
+	// removed, respectively.
+	Labeled   map[string][]time.Time
+	Unlabeled map[string][]time.Time
+
+	// Conflicting is set when the pull request has merge conflicts.  GitHub
+	// does not record when conflicts appear, so ConflictDetectedAt is when
+	// they were first observed by the caller.  If unknown, the most recent
+	// commit is used as the earliest point at which they could have appeared.
+	Conflicting        bool
+	ConflictDetectedAt time.Time
+}
+
+// NewSnapshot returns the snapshot of the provided pull request from its
+// commits, reviews and issue timeline.
+func NewSnapshot(pull *github.PullRequest, commits []*github.RepositoryCommit, reviews []*github.PullRequestReview, timeline []*github.Timeline) *Snapshot {
+	s := &Snapshot{
+		CreatedAt:   pull.GetCreatedAt().Time,
+		Labeled:     make(map[string][]time.Time),
+		Unlabeled:   make(map[string][]time.Time),
+		Conflicting: pull.GetMergeableState() == "dirty",
+	}
+
+	for _, label := range pull.Labels {
+		s.Labels = append(s.Labels, label.GetName())
+	}
+
+	for _, commit := range commits {
+		s.Commits = append(s.Commits, commit.GetCommit().GetCommitter().GetDate().Time)
+	}
+
+	for _, review := range reviews {
+		if strings.EqualFold(review.GetState(), "CHANGES_REQUESTED") {
+			s.ChangesRequested = append(s.ChangesRequested, review.GetSubmittedAt().Time)
+		}
+	}
+
+	for _, event := range timeline {
+		switch event.GetEvent() {
+		case "labeled":
+			name := event.GetLabel().GetName()
+			s.Labeled[name] = append(s.Labeled[name], event.GetCreatedAt().Time)
+		case "unlabeled":
+			name := event.GetLabel().GetName()
+			s.Unlabeled[name] = append(s.Unlabeled[name], event.GetCreatedAt().Time)
+		}
+	}
+
+	return s
+}
+
+// latest returns the most recent of the provided times.
+func latest(times []time.Time) (time.Time, bool) {
+	var ret time.Time
+	for _, t := range times {
+		if t.After(ret) {
+			ret = t
+		}
+	}
+
+	return ret, !ret.IsZero()
+}
+
+// Anchor returns when the provided event happened, or false if it has not
+// happened or no longer applies, e.g. the conflicts have been resolved.
+func (s *Snapshot) Anchor(event Event) (time.Time, bool) {
+	switch event {
+	case "", EventCreated:
+		return s.CreatedAt, !s.CreatedAt.IsZero()
+
+	case EventLastCommit:
+		return latest(s.Commits)
+
+	case EventChangesRequested:
+		requested, ok := latest(s.ChangesRequested)
+		if !ok {
+			return time.Time{}, false
+		}
+
+		if commit, ok := latest(s.Commits); ok && commit.After(requested) {
+			return time.Time{}, false
+		}
+
+		return requested, true
+
+	case EventConflictDetected:
+		if !s.Conflicting {
+			return time.Time{}, false
+		}
+
+		if !s.ConflictDetectedAt.IsZero() {
+			return s.ConflictDetectedAt, true
+		}
+
+		return latest(s.Commits)
+	}
+
+	name, ok := strings.CutPrefix(string(event), eventLabelPrefix)
+	if !ok || !contains(s.Labels, name) {
+		return time.Time{}, false
+	}
+
+	labeled, ok := latest(s.Labeled[name])
+	if !ok {
+		return time.Time{}, false
+	}
+
+	if unlabeled, ok := latest(s.Unlabeled[name]); ok && unlabeled.After(labeled) {
+		return time.Time{}, false
+	}
+
+	return labeled, true
+}
+
+// ApplyDue returns whether the label is due to be applied at the provided
+// time, which is once ApplyAfter has elapsed since ApplyAfterEvent.  Labels
+// without a timer are due immediately.
+func (l *Label) ApplyDue(s *Snapshot, now time.Time) bool {
+	if l.ApplyAfter == 0 && l.ApplyAfterEvent == "" {
+		return true
+	}
+
+	anchor, ok := s.Anchor(l.ApplyAfterEvent)
+	if !ok {
+		return false
+	}
+
+	return !now.Before(anchor.Add(time.Duration(l.ApplyAfter)))
+}
+
+// RemoveDue returns whether the label is due to be removed at the provided
+// time, which is once RemoveAfter has elapsed since RemoveAfterEvent unless
+// any of DoNotRemoveIfLabelsExist is applied.  Labels without a timer are
+// never due.
+func (l *Label) RemoveDue(s *Snapshot, now time.Time) bool {
+	if l.RemoveAfter == 0 && l.RemoveAfterEvent == "" {
+		return false
+	}
+
+	for _, name := range l.DoNotRemoveIfLabelsExist {
+		if contains(s.Labels, name) {
+			return false
+		}
+	}
+
+	anchor, ok := s.Anchor(l.RemoveAfterEvent)
+	if !ok {
+		return false
+	}
+
+	return !now.Before(anchor.Add(time.Duration(l.RemoveAfter)))
+}
+
+// contains returns whether the list contains the entry.
+func contains(list []string, entry string) bool {
+	for _, e := range list {
+		if e == entry {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+package label
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// snapshotFixture is a pull request created on the 1st with commits on the
+// 1st and 3rd, changes requested on the 4th, labeled ci/wait on the 2nd and
+// needs-rebase on the 5th, the latter of which was removed on the 6th.
+const snapshotFixture = `{
+	"pull": {
+		"created_at": "2024-03-01T00:00:00Z",
+		"mergeable_state": "dirty",
+		"labels": [{"name": "ci/wait"}]
+	},
+	"commits": [
+		{"commit": {"committer": {"date": "2024-03-01T12:00:00Z"}}},
+		{"commit": {"committer": {"date": "2024-03-03T00:00:00Z"}}}
+	],
+	"reviews": [
+		{"state": "COMMENTED", "submitted_at": "2024-03-02T00:00:00Z"},
+		{"state": "CHANGES_REQUESTED", "submitted_at": "2024-03-04T00:00:00Z"}
+	],
+	"timeline": [
+		{"event": "labeled", "label": {"name": "ci/wait"}, "created_at": "2024-03-02T00:00:00Z"},
+		{"event": "labeled", "label": {"name": "needs-rebase"}, "created_at": "2024-03-05T00:00:00Z"},
+		{"event": "unlabeled", "label": {"name": "needs-rebase"}, "created_at": "2024-03-06T00:00:00Z"}
+	]
+}`
+
+func newTestSnapshot(t *testing.T) *Snapshot {
+	t.Helper()
+
+	var fixture struct {
+		Pull     *github.PullRequest         `json:"pull"`
+		Commits  []*github.RepositoryCommit  `json:"commits"`
+		Reviews  []*github.PullRequestReview `json:"reviews"`
+		Timeline []*github.Timeline          `json:"timeline"`
+	}
+
+	if err := json.Unmarshal([]byte(snapshotFixture), &fixture); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewSnapshot(fixture.Pull, fixture.Commits, fixture.Reviews, fixture.Timeline)
+}
+
+func day(d int) time.Time {
+	return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSnapshotAnchor(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    Event
+		modify   func(*Snapshot)
+		want     time.Time
+		wantNone bool
+	}{
+		{
+			name:  "default",
+			event: "",
+			want:  day(1),
+		},
+		{
+			name:  "created",
+			event: EventCreated,
+			want:  day(1),
+		},
+		{
+			name:  "last commit",
+			event: EventLastCommit,
+			want:  day(3),
+		},
+		{
+			name:  "changes requested",
+			event: EventChangesRequested,
+			want:  day(4),
+		},
+		{
+			name:  "changes requested addressed by a later commit",
+			event: EventChangesRequested,
+			modify: func(s *Snapshot) {
+				s.Commits = append(s.Commits, day(5))
+			},
+			wantNone: true,
+		},
+		{
+			name:  "conflict detected",
+			event: EventConflictDetected,
+			modify: func(s *Snapshot) {
+				s.ConflictDetectedAt = day(7)
+			},
+			want: day(7),
+		},
+		{
+			name:  "conflict detected at an unknown time",
+			event: EventConflictDetected,
+			want:  day(3),
+		},
+		{
+			name:  "conflict resolved",
+			event: EventConflictDetected,
+			modify: func(s *Snapshot) {
+				s.Conflicting = false
+			},
+			wantNone: true,
+		},
+		{
+			name:  "label applied",
+			event: "label:ci/wait",
+			want:  day(2),
+		},
+		{
+			name:     "label removed",
+			event:    "label:needs-rebase",
+			wantNone: true,
+		},
+		{
+			name:     "label never applied",
+			event:    "label:area/boot",
+			wantNone: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSnapshot(t)
+			if tt.modify != nil {
+				tt.modify(s)
+			}
+
+			got, ok := s.Anchor(tt.event)
+			if ok == tt.wantNone {
+				t.Fatalf("Anchor(%s) = %v, %v, want an anchor: %v", tt.event, got, ok, !tt.wantNone)
+			}
+
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("Anchor(%s) = %v, want %v", tt.event, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelDue(t *testing.T) {
+	tests := []struct {
+		name       string
+		label      Label
+		now        time.Time
+		wantApply  bool
+		wantRemove bool
+	}{
+		{
+			name:      "no timers",
+			now:       day(1),
+			wantApply: true,
+		},
+		{
+			name:  "before apply timer since creation",
+			label: Label{ApplyAfter: Duration(72 * time.Hour)},
+			now:   day(3),
+		},
+		{
+			name:      "apply timer since creation elapsed",
+			label:     Label{ApplyAfter: Duration(72 * time.Hour)},
+			now:       day(4),
+			wantApply: true,
+		},
+		{
+			name:  "before apply timer since changes requested",
+			label: Label{ApplyAfter: Duration(48 * time.Hour), ApplyAfterEvent: EventChangesRequested},
+			now:   day(5),
+		},
+		{
+			name:      "apply timer since changes requested elapsed",
+			label:     Label{ApplyAfter: Duration(48 * time.Hour), ApplyAfterEvent: EventChangesRequested},
+			now:       day(6),
+			wantApply: true,
+		},
+		{
+			name:      "apply on event without delay",
+			label:     Label{ApplyAfterEvent: EventConflictDetected},
+			now:       day(3),
+			wantApply: true,
+		},
+		{
+			name:  "apply on event which has not happened",
+			label: Label{ApplyAfterEvent: "label:area/boot"},
+			now:   day(30),
+		},
+		{
+			name:       "remove timer since label applied elapsed",
+			label:      Label{RemoveAfter: Duration(24 * time.Hour), RemoveAfterEvent: "label:ci/wait"},
+			now:        day(3),
+			wantApply:  true,
+			wantRemove: true,
+		},
+		{
+			name: "remove timer blocked by label",
+			label: Label{
+				RemoveAfter:              Duration(24 * time.Hour),
+				RemoveAfterEvent:         EventLastCommit,
+				DoNotRemoveIfLabelsExist: []string{"ci/wait"},
+			},
+			now:       day(30),
+			wantApply: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSnapshot(t)
+
+			if got := tt.label.ApplyDue(s, tt.now); got != tt.wantApply {
+				t.Errorf("ApplyDue() = %v, want %v", got, tt.wantApply)
+			}
+
+			if got := tt.label.RemoveDue(s, tt.now); got != tt.wantRemove {
+				t.Errorf("RemoveDue() = %v, want %v", got, tt.wantRemove)
+			}
+		})
+	}
+}
+
+func TestLabelEventValidation(t *testing.T) {
+	for _, event := range []Event{"", "created", "last_commit", "changes_requested", "conflict_detected", "label:ci/wait"} {
+		if err := ValidateEvent(event); err != nil {
+			t.Errorf("ValidateEvent(%q) = %v, want nil", event, err)
+		}
+	}
+
+	for _, event := range []Event{"merged", "label:"} {
+		if err := ValidateEvent(event); err == nil {
+			t.Errorf("ValidateEvent(%q) expected an error", event)
+		}
+	}
+
+	file := filepath.Join(t.TempDir(), "labels.yaml")
+	if err := os.WriteFile(file, []byte("labels:\n  - name: needs-rebase\n    apply_after: 72h\n    apply_after_event: conflict\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewListOfLabelsFromYAML(nil, "unikraft", file); err == nil || !strings.Contains(err.Error(), "apply_after_event") {
+		t.Errorf("NewListOfLabelsFromYAML() error = %v, want an invalid apply_after_event", err)
+	}
+}
@@ -0,0 +1,271 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+// Package license checks that the files added by a patch carry the expected
+// SPDX identifier and copyright header.
+package license
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/unikraft/governance/internal/patch"
+)
+
+const (
+	// DefaultHeaderLines is the number of leading lines of a file in which the
+	// header is expected.
+	DefaultHeaderLines = 10
+
+	// DefaultCopyrightPattern matches the copyright line of the header, e.g.
+	// "Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors."
+	DefaultCopyrightPattern = `(?i)Copyright \(c\) [0-9]{4}(-[0-9]{4})?, \S`
+
+	// spdxTag precedes the license expression of a file.
+	spdxTag = "SPDX-License-Identifier:"
+)
+
+// DefaultAllowedLicenses are the SPDX identifiers which new files may carry.
+var DefaultAllowedLicenses = []string{"BSD-3-Clause"}
+
+// DefaultExclude are the globs of files which are never checked, namely
+// vendored code, binary assets and data files which cannot carry a header.
+// Globs without a slash match the name of the file, those ending in a slash
+// match every file within the directory, and all others match the full path.
+var DefaultExclude = []string{
+	"vendor/",
+	"third_party/",
+	"*.yaml",
+	"*.yml",
+	"*.json",
+	"*.md",
+	"*.rst",
+	"*.txt",
+	"*.png",
+	"*.jpg",
+	"*.jpeg",
+	"*.gif",
+	"*.svg",
+	"*.ico",
+	"*.pdf",
+}
+
+// AddedFile is a file which was added by a patch along with its leading lines.
+type AddedFile struct {
+	Path  string
+	Lines []string
+}
+
+// AddedFiles returns the text files added by the provided unified diff along
+// with at most the provided number of their leading lines, or all lines if
+// zero.  Binary files are omitted since their content is not part of the diff.
+func AddedFiles(diff string, lines int) []AddedFile {
+	var files []AddedFile
+	var current *AddedFile
+	added, inHunk := false, false
+
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			current, added, inHunk = nil, false, false
+			continue
+		}
+
+		if inHunk {
+			if current != nil && strings.HasPrefix(line, "+") && (lines == 0 || len(current.Lines) < lines) {
+				current.Lines = append(current.Lines, strings.TrimSuffix(line[1:], "\r"))
+			}
+
+			continue
+		}
+
+		switch {
+		case line == "--- /dev/null":
+			added = true
+		case strings.HasPrefix(line, "+++ b/") && added:
+			files = append(files, AddedFile{Path: strings.TrimPrefix(line, "+++ b/")})
+			current = &files[len(files)-1]
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		}
+	}
+
+	return files
+}
+
+// Violation is a file added by a patch whose header is not as expected.
+type Violation struct {
+	Patch  string `json:"patch"`
+	Title  string `json:"title"`
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s (%s): %s", v.File, shortHash(v.Patch), v.Reason)
+}
+
+// Files returns the unique files of the provided violations in the order in
+// which they were found.
+func Files(violations []Violation) []string {
+	var files []string
+	seen := make(map[string]bool)
+
+	for _, violation := range violations {
+		if !seen[violation.File] {
+			seen[violation.File] = true
+			files = append(files, violation.File)
+		}
+	}
+
+	return files
+}
+
+// Checker checks the headers of the files added by patches.
+type Checker struct {
+	allowed   []string
+	copyright *regexp.Regexp
+	lines     int
+	exclude   []string
+}
+
+// NewChecker returns a checker which, unless otherwise specified, expects the
+// default licenses and copyright line within the default number of lines and
+// skips the files matching the default exclusions.
+func NewChecker(opts ...CheckerOption) (*Checker, error) {
+	c := &Checker{
+		allowed:   DefaultAllowedLicenses,
+		copyright: regexp.MustCompile(DefaultCopyrightPattern),
+		lines:     DefaultHeaderLines,
+		exclude:   DefaultExclude,
+	}
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Check returns the violations of the files added by the provided patches.
+func (c *Checker) Check(patches ...*patch.Patch) []Violation {
+	var violations []Violation
+
+	for _, p := range patches {
+		for _, file := range AddedFiles(p.Diff, c.lines) {
+			if len(file.Lines) == 0 || c.Excluded(file.Path) {
+				continue
+			}
+
+			for _, reason := range c.checkHeader(file.Lines) {
+				violations = append(violations, Violation{
+					Patch:  p.Hash,
+					Title:  p.Title,
+					File:   file.Path,
+					Reason: reason,
+				})
+			}
+		}
+	}
+
+	return violations
+}
+
+// checkHeader returns why the provided leading lines of a file are not an
+// acceptable header.
+func (c *Checker) checkHeader(lines []string) []string {
+	var reasons []string
+
+	if expr, ok := spdxExpression(lines); !ok {
+		reasons = append(reasons, fmt.Sprintf("missing %s in the first %d lines", spdxTag, c.lines))
+	} else if !c.allowedExpression(expr) {
+		reasons = append(reasons, fmt.Sprintf("license '%s' is not one of: %s", expr, strings.Join(c.allowed, ", ")))
+	}
+
+	if c.copyright != nil && !matchesAny(c.copyright, lines) {
+		reasons = append(reasons, fmt.Sprintf("missing copyright line matching '%s' in the first %d lines", c.copyright, c.lines))
+	}
+
+	return reasons
+}
+
+// allowedExpression returns whether the SPDX expression is one of the allowed
+// identifiers, which like SPDX itself is case-insensitive.
+func (c *Checker) allowedExpression(expr string) bool {
+	for _, allowed := range c.allowed {
+		if strings.EqualFold(expr, allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Excluded returns whether the file at the provided path is never checked.
+func (c *Checker) Excluded(file string) bool {
+	for _, pattern := range c.exclude {
+		if dir, ok := strings.CutSuffix(pattern, "/"); ok {
+			if strings.HasPrefix(file, dir+"/") || strings.Contains(file, "/"+dir+"/") {
+				return true
+			}
+
+			continue
+		}
+
+		name := file
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(file)
+		}
+
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// spdxExpression returns the license expression following the SPDX tag in
+// the provided lines, stripped of the comment which surrounds it.
+func spdxExpression(lines []string) (string, bool) {
+	for _, line := range lines {
+		_, expr, ok := strings.Cut(line, spdxTag)
+		if !ok {
+			continue
+		}
+
+		expr = strings.TrimSpace(expr)
+		for _, end := range []string{"*/", "-->"} {
+			expr = strings.TrimSpace(strings.TrimSuffix(expr, end))
+		}
+
+		return expr, true
+	}
+
+	return "", false
+}
+
+// matchesAny returns whether any of the lines matches the expression.
+func matchesAny(re *regexp.Regexp, lines []string) bool {
+	for _, line := range lines {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shortHash abbreviates the hash of a commit.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+
+	return hash
+}
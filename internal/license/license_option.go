@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package license
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// CheckerOption is used to customize the checks of a Checker.
+type CheckerOption func(*Checker) error
+
+// WithAllowedLicenses sets the SPDX identifiers which new files may carry.
+// When empty, DefaultAllowedLicenses are used.
+func WithAllowedLicenses(licenses ...string) CheckerOption {
+	return func(c *Checker) error {
+		if len(licenses) > 0 {
+			c.allowed = licenses
+		}
+
+		return nil
+	}
+}
+
+// WithCopyrightPattern sets the regular expression which one of the leading
+// lines must match.  When empty, DefaultCopyrightPattern is used.
+func WithCopyrightPattern(pattern string) CheckerOption {
+	return func(c *Checker) error {
+		if pattern == "" {
+			return nil
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid copyright pattern '%s': %w", pattern, err)
+		}
+
+		c.copyright = re
+
+		return nil
+	}
+}
+
+// WithHeaderLines sets the number of leading lines in which the header is
+// expected.  When zero, DefaultHeaderLines is used.
+func WithHeaderLines(lines int) CheckerOption {
+	return func(c *Checker) error {
+		if lines < 0 {
+			return fmt.Errorf("number of header lines cannot be negative: %d", lines)
+		}
+
+		if lines > 0 {
+			c.lines = lines
+		}
+
+		return nil
+	}
+}
+
+// WithExclude skips the files matching the provided globs in addition to
+// DefaultExclude.
+func WithExclude(globs ...string) CheckerOption {
+	return func(c *Checker) error {
+		for _, glob := range globs {
+			if _, err := path.Match(glob, ""); err != nil {
+				return fmt.Errorf("invalid exclude glob '%s': %w", glob, err)
+			}
+		}
+
+		c.exclude = append(append([]string{}, c.exclude...), globs...)
+
+		return nil
+	}
+}
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package license
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/unikraft/governance/internal/patch"
+)
+
+const licenseDiff = `diff --git a/lib/foo/foo.c b/lib/foo/foo.c
+new file mode 100644
+index 0000000..1111111
+--- /dev/null
++++ b/lib/foo/foo.c
+@@ -0,0 +1,4 @@
++/* SPDX-License-Identifier: BSD-3-Clause */
++/* Copyright (c) 2024, Unikraft GmbH and The Unikraft Authors. */
++
++int foo;
+diff --git a/lib/foo/bar.c b/lib/foo/bar.c
+new file mode 100644
+index 0000000..2222222
+--- /dev/null
++++ b/lib/foo/bar.c
+@@ -0,0 +1,2 @@
++// SPDX-License-Identifier: GPL-2.0-only
++int bar;
+diff --git a/lib/foo/Makefile.uk b/lib/foo/Makefile.uk
+index 3333333..4444444 100644
+--- a/lib/foo/Makefile.uk
++++ b/lib/foo/Makefile.uk
+@@ -1,1 +1,2 @@
+ $(eval $(call addlib,libfoo))
++LIBFOO_SRCS-y += $(LIBFOO_BASE)/bar.c
+diff --git a/lib/foo/config.yaml b/lib/foo/config.yaml
+new file mode 100644
+index 0000000..5555555
+--- /dev/null
++++ b/lib/foo/config.yaml
+@@ -0,0 +1 @@
++foo: bar
+diff --git a/lib/foo/logo.bin b/lib/foo/logo.bin
+new file mode 100644
+index 0000000..6666666
+Binary files /dev/null and b/lib/foo/logo.bin differ
+diff --git a/vendor/baz/baz.c b/vendor/baz/baz.c
+new file mode 100644
+index 0000000..7777777
+--- /dev/null
++++ b/vendor/baz/baz.c
+@@ -0,0 +1 @@
++int baz;
+`
+
+func TestAddedFiles(t *testing.T) {
+	files := AddedFiles(licenseDiff, 2)
+
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+
+	if got, want := strings.Join(paths, " "), "lib/foo/foo.c lib/foo/bar.c lib/foo/config.yaml vendor/baz/baz.c"; got != want {
+		t.Fatalf("AddedFiles() = %s, want %s", got, want)
+	}
+
+	if len(files[0].Lines) != 2 || !strings.Contains(files[0].Lines[1], "Copyright") {
+		t.Errorf("AddedFiles() lines of %s = %q, want the first 2 lines", files[0].Path, files[0].Lines)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []CheckerOption
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: []string{
+				"lib/foo/bar.c: license 'GPL-2.0-only'",
+				"lib/foo/bar.c: missing copyright line",
+			},
+		},
+		{
+			name: "allowed license",
+			opts: []CheckerOption{
+				WithAllowedLicenses("BSD-3-Clause", "gpl-2.0-only"),
+			},
+			want: []string{
+				"lib/foo/bar.c: missing copyright line",
+			},
+		},
+		{
+			name: "header beyond the first line",
+			opts: []CheckerOption{
+				WithHeaderLines(1),
+				WithCopyrightPattern("Copyright"),
+			},
+			want: []string{
+				"lib/foo/foo.c: missing copyright line matching 'Copyright' in the first 1 lines",
+				"lib/foo/bar.c: license 'GPL-2.0-only'",
+				"lib/foo/bar.c: missing copyright line",
+			},
+		},
+		{
+			name: "excluded",
+			opts: []CheckerOption{
+				WithExclude("lib/foo/bar.*"),
+			},
+		},
+		{
+			name:    "invalid copyright pattern",
+			opts:    []CheckerOption{WithCopyrightPattern("(")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid exclude glob",
+			opts:    []CheckerOption{WithExclude("[")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker, err := NewChecker(tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewChecker() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil {
+				return
+			}
+
+			violations := checker.Check(&patch.Patch{Hash: "0123456789abcdef", Diff: licenseDiff})
+			if len(violations) != len(tt.want) {
+				t.Fatalf("Check() = %v, want %d violations", violations, len(tt.want))
+			}
+
+			for i, violation := range violations {
+				if got := violation.File + ": " + violation.Reason; !strings.HasPrefix(got, tt.want[i]) {
+					t.Errorf("Check()[%d] = %s, want %s", i, got, tt.want[i])
+				}
+
+				if violation.Patch != "0123456789abcdef" {
+					t.Errorf("Check()[%d].Patch = %s, want the hash of the patch", i, violation.Patch)
+				}
+			}
+		})
+	}
+}
+
+func TestExcluded(t *testing.T) {
+	checker, err := NewChecker()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for file, want := range map[string]bool{
+		"vendor/foo.c":        true,
+		"lib/vendor/foo.c":    true,
+		"docs/README.md":      true,
+		".github/ci.yml":      true,
+		"lib/vendored/foo.c":  false,
+		"lib/foo/Makefile.uk": false,
+	} {
+		if got := checker.Excluded(file); got != want {
+			t.Errorf("Excluded(%s) = %v, want %v", file, got, want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ownership
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hairyhenderson/go-codeowners"
+)
+
+// CodeownersLocations are the paths, relative to the root of a repository, at
+// which GitHub looks for a CODEOWNERS file, in order of precedence.
+var CodeownersLocations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// ErrNoCodeowners is returned when a repository has no CODEOWNERS file at any
+// of the CodeownersLocations.
+var ErrNoCodeowners = errors.New("no CODEOWNERS found")
+
+// codeownerRe matches the owners which GitHub accepts: users, teams and email
+// addresses.
+var codeownerRe = regexp.MustCompile(`^(@[\w.-]+(/[\w.-]+)?|[^@\s]+@[^@\s]+)$`)
+
+// FindCodeowners parses the CODEOWNERS file of the repository checked out at
+// the provided directory and returns it along with its path relative to the
+// directory.  Only the first file found at the CodeownersLocations is used,
+// such that a file which cannot be parsed is an error rather than falling
+// through to the next location.  ErrNoCodeowners is returned if there is no
+// such file.
+func FindCodeowners(dir string) (*codeowners.Codeowners, string, error) {
+	for _, location := range CodeownersLocations {
+		b, err := os.ReadFile(filepath.Join(dir, location))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, location, fmt.Errorf("could not read %s: %w", location, err)
+		}
+
+		if err := validateCodeowners(b); err != nil {
+			return nil, location, fmt.Errorf("could not parse %s: %w", location, err)
+		}
+
+		co, err := codeowners.FromReader(bytes.NewReader(b), "")
+		if err != nil {
+			return nil, location, fmt.Errorf("could not parse %s: %w", location, err)
+		}
+
+		return co, location, nil
+	}
+
+	return nil, "", ErrNoCodeowners
+}
+
+// validateCodeowners checks that every owner in the CODEOWNERS file is a
+// user, team or email address.  The underlying parser silently accepts any
+// owner, which would otherwise let a typo go unnoticed.
+func validateCodeowners(b []byte) error {
+	s := bufio.NewScanner(bytes.NewReader(b))
+
+	for line := 1; s.Scan(); line++ {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		// Escaped spaces in the pattern are joined with the following field.
+		i := 0
+		for i < len(fields)-1 && strings.HasSuffix(fields[i], `\`) {
+			i++
+		}
+
+		for _, owner := range fields[i+1:] {
+			// Owners may be followed by a comment.
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+
+			if !codeownerRe.MatchString(owner) {
+				return fmt.Errorf("line %d: invalid owner '%s': expected @user, @org/team or an email address", line, owner)
+			}
+		}
+	}
+
+	return s.Err()
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ownership
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindCodeowners(t *testing.T) {
+	tests := []struct {
+		name         string
+		dir          string
+		wantLocation string
+		wantOwners   map[string][]string
+		wantErr      string
+	}{
+		{
+			name:         ".github",
+			dir:          "github",
+			wantLocation: ".github/CODEOWNERS",
+			wantOwners: map[string][]string{
+				"arch/x86/entry.S": {"@unikraft/sig-arch"},
+			},
+		},
+		{
+			name:         "root",
+			dir:          "root",
+			wantLocation: "CODEOWNERS",
+			wantOwners: map[string][]string{
+				"lib/ukalloc/alloc.c": {"@unikraft/sig-lib"},
+			},
+		},
+		{
+			name:         "docs",
+			dir:          "docs",
+			wantLocation: "docs/CODEOWNERS",
+			wantOwners: map[string][]string{
+				"drivers/virtio/net.c": {"@unikraft/sig-drivers"},
+			},
+		},
+		{
+			name:         "precedence",
+			dir:          "all",
+			wantLocation: ".github/CODEOWNERS",
+			wantOwners: map[string][]string{
+				"arch/x86/entry.S":    {"@unikraft/sig-arch"},
+				"lib/ukalloc/alloc.c": nil,
+			},
+		},
+		{
+			name:         "invalid does not fall through",
+			dir:          "invalid",
+			wantLocation: ".github/CODEOWNERS",
+			wantErr:      "could not parse .github/CODEOWNERS: line 1: invalid owner 'unikraft/sig-arch'",
+		},
+		{
+			name:    "none",
+			dir:     "none",
+			wantErr: ErrNoCodeowners.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			co, location, err := FindCodeowners(filepath.Join("testdata", tt.dir))
+			if location != tt.wantLocation {
+				t.Errorf("FindCodeowners() location = %q, want %q", location, tt.wantLocation)
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FindCodeowners() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("FindCodeowners() unexpected error: %v", err)
+			}
+
+			for file, want := range tt.wantOwners {
+				if got := co.Owners(file); !reflect.DeepEqual(got, want) {
+					t.Errorf("Owners(%q) = %v, want %v", file, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchesCodeowners(t *testing.T) {
+	teams, co, _ := syntheticOwnership(2, 0)
+	idx := newSyntheticIndex(t, teams, co)
+
+	if !idx.HasCodeowners() {
+		t.Fatal("HasCodeowners() = false, want true")
+	}
+
+	if !idx.MatchesCodeowners([]string{"README.md", "lib/lib1/file.c"}) {
+		t.Error("MatchesCodeowners() = false, want true")
+	}
+
+	if idx.MatchesCodeowners([]string{"README.md"}) {
+		t.Error("MatchesCodeowners() = true, want false")
+	}
+}
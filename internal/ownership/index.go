@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+// Package ownership determines which teams own the changes made to a
+// repository.
+package ownership
+
+import (
+	"strings"
+
+	"github.com/hairyhenderson/go-codeowners"
+
+	"github.com/unikraft/governance/internal/team"
+)
+
+// Index is a pre-computed view of the teams which own a single repository,
+// either because the repository is listed in their definition or because
+// they are named in the repository's CODEOWNERS file.  An Index is immutable
+// once built and is therefore safe for concurrent use.  It should be built
+// once per repository and re-used for every pull request of that repository.
+type Index struct {
+	repo       string
+	repoTeams  []*team.Team
+	codeowners *codeowners.Codeowners
+	owners     map[string]*team.Team
+}
+
+// IndexOption is used to customize the construction of an Index.
+type IndexOption func(*Index) error
+
+// WithCodeowners sets the parsed CODEOWNERS of the repository which is used to
+// determine additional owning teams based on the changed files.
+func WithCodeowners(co *codeowners.Codeowners) IndexOption {
+	return func(idx *Index) error {
+		idx.codeowners = co
+		return nil
+	}
+}
+
+// NewIndex builds an ownership index for the provided repository from the
+// list of known teams.
+func NewIndex(repo string, teams []*team.Team, opts ...IndexOption) (*Index, error) {
+	idx := &Index{
+		repo:   repo,
+		owners: make(map[string]*team.Team),
+	}
+
+	for _, opt := range opts {
+		if err := opt(idx); err != nil {
+			return nil, err
+		}
+	}
+
+	// Select the teams which are responsible for the repository.
+	for _, t := range teams {
+		for _, r := range t.Repositories {
+			if r.NameEquals(repo) {
+				idx.repoTeams = append(idx.repoTeams, t)
+				break
+			}
+		}
+	}
+
+	// Resolve every owner mentioned in CODEOWNERS to a known team up-front so
+	// that lookups are only map accesses.
+	if idx.codeowners != nil {
+		for _, pattern := range idx.codeowners.Patterns {
+			for _, owner := range pattern.Owners {
+				if _, ok := idx.owners[owner]; ok {
+					continue
+				}
+
+				// Only teams (i.e. "@org/team") can be resolved, individual users
+				// and email addresses are skipped.
+				var t *team.Team
+				if strings.HasPrefix(owner, "@") && strings.Contains(owner, "/") {
+					t = team.FindTeamByName(owner, teams)
+				}
+
+				idx.owners[owner] = t
+			}
+		}
+	}
+
+	return idx, nil
+}
+
+// Repo returns the name of the repository the index was built for.
+func (idx *Index) Repo() string {
+	return idx.repo
+}
+
+// OwningTeams returns the teams which own the provided list of changed files.
+// This always includes the teams responsible for the repository followed by
+// any team named in CODEOWNERS for the changed files, without duplicates.
+func (idx *Index) OwningTeams(changedFiles []string) []*team.Team {
+	ret := make([]*team.Team, 0, len(idx.repoTeams))
+	seen := make(map[*team.Team]struct{}, len(idx.repoTeams))
+
+	for _, t := range idx.repoTeams {
+		ret = append(ret, t)
+		seen[t] = struct{}{}
+	}
+
+	if idx.codeowners == nil {
+		return ret
+	}
+
+	for _, f := range changedFiles {
+		for _, owner := range idx.codeowners.Owners(f) {
+			t := idx.owners[owner]
+			if t == nil {
+				continue
+			}
+
+			if _, ok := seen[t]; ok {
+				continue
+			}
+
+			ret = append(ret, t)
+			seen[t] = struct{}{}
+		}
+	}
+
+	return ret
+}
+
+// HasCodeowners returns whether the index was built with a CODEOWNERS file.
+func (idx *Index) HasCodeowners() bool {
+	return idx.codeowners != nil
+}
+
+// MatchesCodeowners returns whether any rule of CODEOWNERS matches any of the
+// provided changed files, regardless of whether its owners are known teams.
+func (idx *Index) MatchesCodeowners(changedFiles []string) bool {
+	if idx.codeowners == nil {
+		return false
+	}
+
+	for _, f := range changedFiles {
+		if len(idx.codeowners.Owners(f)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FileOwners returns the teams named in CODEOWNERS for each of the provided
+// changed files.  Files which are not owned by any known team are omitted and
+// the teams responsible for the whole repository are not included.
+func (idx *Index) FileOwners(changedFiles []string) map[string][]*team.Team {
+	ret := make(map[string][]*team.Team)
+
+	if idx.codeowners == nil {
+		return ret
+	}
+
+	for _, f := range changedFiles {
+		for _, owner := range idx.codeowners.Owners(f) {
+			t := idx.owners[owner]
+			if t == nil || containsTeam(ret[f], t) {
+				continue
+			}
+
+			ret[f] = append(ret[f], t)
+		}
+	}
+
+	return ret
+}
+
+// containsTeam returns whether the team is in the list of teams.
+func containsTeam(teams []*team.Team, t *team.Team) bool {
+	for _, c := range teams {
+		if c == t {
+			return true
+		}
+	}
+
+	return false
+}
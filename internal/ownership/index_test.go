@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package ownership
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hairyhenderson/go-codeowners"
+
+	"github.com/unikraft/governance/internal/repo"
+	"github.com/unikraft/governance/internal/team"
+)
+
+// syntheticOwnership returns numTeams teams, a CODEOWNERS file assigning one
+// library directory to each team and numFiles changed files spread across the
+// library directories.
+func syntheticOwnership(numTeams, numFiles int) ([]*team.Team, string, []string) {
+	var teams []*team.Team
+	var co strings.Builder
+	var files []string
+
+	for i := 0; i < numTeams; i++ {
+		teams = append(teams, &team.Team{
+			Name: fmt.Sprintf("lib%d", i),
+			Type: team.SIGTeam,
+			Repositories: []repo.Repository{
+				{Name: fmt.Sprintf("lib-%d", i)},
+			},
+		})
+
+		fmt.Fprintf(&co, "/lib/lib%d/ @unikraft/sig-lib%d\n", i, i)
+	}
+
+	for i := 0; i < numFiles; i++ {
+		files = append(files, fmt.Sprintf("lib/lib%d/file%d.c", i%numTeams, i))
+	}
+
+	return teams, co.String(), files
+}
+
+func newSyntheticIndex(b testing.TB, teams []*team.Team, co string) *Index {
+	parsed, err := codeowners.FromReader(strings.NewReader(co), "")
+	if err != nil {
+		b.Fatalf("could not parse CODEOWNERS: %v", err)
+	}
+
+	idx, err := NewIndex("unikraft", teams, WithCodeowners(parsed))
+	if err != nil {
+		b.Fatalf("could not build index: %v", err)
+	}
+
+	return idx
+}
+
+func teamNames(teams []*team.Team) []string {
+	var names []string
+	for _, t := range teams {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func TestOwningTeams(t *testing.T) {
+	teams := []*team.Team{
+		{Name: "core", Repositories: []repo.Repository{{Name: "unikraft"}}},
+		{Name: "arch", Type: team.SIGTeam},
+		{Name: "fs", Type: team.SIGTeam},
+		{Name: "lwip", Repositories: []repo.Repository{{Name: "lib-lwip"}}},
+	}
+
+	co := strings.Join([]string{
+		"/arch/ @unikraft/sig-arch",
+		"/lib/vfscore/ @unikraft/sig-fs jane@unikraft.io",
+		"/lib/ukdebug/ @unikraft/sig-unknown @john",
+	}, "\n")
+
+	idx := newSyntheticIndex(t, teams, co)
+
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{
+			name:  "no changed files",
+			files: nil,
+			want:  []string{"core"},
+		},
+		{
+			name:  "codeowners team",
+			files: []string{"arch/x86/x86_64/include/uk/asm.h"},
+			want:  []string{"core", "arch"},
+		},
+		{
+			name:  "deduplicated teams",
+			files: []string{"lib/vfscore/main.c", "arch/arm/arm64/cache.S", "lib/vfscore/file.c"},
+			want:  []string{"core", "fs", "arch"},
+		},
+		{
+			name:  "unknown owners",
+			files: []string{"lib/ukdebug/print.c"},
+			want:  []string{"core"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := teamNames(idx.OwningTeams(tt.files)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OwningTeams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwningTeamsConcurrent(t *testing.T) {
+	teams, co, files := syntheticOwnership(50, 500)
+	idx := newSyntheticIndex(t, teams, co)
+	want := len(idx.OwningTeams(files))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := len(idx.OwningTeams(files)); got != want {
+				t.Errorf("OwningTeams() returned %d teams, want %d", got, want)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// BenchmarkOwningTeamsRebuildPerPR measures the cost of re-parsing CODEOWNERS
+// and re-resolving teams for every pull request.
+func BenchmarkOwningTeamsRebuildPerPR(b *testing.B) {
+	teams, co, files := syntheticOwnership(50, 500)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx := newSyntheticIndex(b, teams, co)
+		_ = idx.OwningTeams(files)
+	}
+}
+
+// BenchmarkOwningTeamsSharedIndex measures the per pull request cost when the
+// index is built once per repository.
+func BenchmarkOwningTeamsSharedIndex(b *testing.B) {
+	teams, co, files := syntheticOwnership(50, 500)
+	idx := newSyntheticIndex(b, teams, co)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = idx.OwningTeams(files)
+	}
+}
@@ -12,7 +12,7 @@ import (
 
 type Pair struct {
 	Key   string
-	Value int
+	Value float64
 }
 
 type PairList []Pair
@@ -29,7 +29,7 @@ func (p PairList) Swap(i, j int) {
 	p[i], p[j] = p[j], p[i]
 }
 
-func RankByWorkload(users map[string]int) PairList {
+func RankByWorkload(users map[string]float64) PairList {
 	pl := make(PairList, len(users))
 	i := 0
 
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package patch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+const (
+	// safeFilenameFallback is used when a title does not contain any character
+	// which can be used in a filename.
+	safeFilenameFallback = "patch"
+
+	// safeFilenameMaxLen is the maximum length in bytes of a filename returned
+	// by SafeFilename, leaving room for any prefix or extension well within the
+	// 255 byte limit of most filesystems.
+	safeFilenameMaxLen = 128
+
+	// safeFilenameHashLen is the number of hexadecimal digits of the hash of
+	// the title which is appended to every filename.
+	safeFilenameHashLen = 8
+)
+
+// transliterations are the ASCII equivalents of common non-ASCII letters.
+// Letters without an equivalent are removed.
+var transliterations = map[rune]string{
+	'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Ä': "A", 'Å': "A", 'Ā': "A", 'Ă': "A", 'Ą': "A",
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'Æ': "AE", 'æ': "ae", 'Œ': "OE", 'œ': "oe", 'ß': "ss", 'Þ': "Th", 'þ': "th", 'Ð': "D", 'ð': "d",
+	'Ç': "C", 'Ć': "C", 'Č': "C", 'ç': "c", 'ć': "c", 'č': "c", 'Ď': "D", 'Đ': "D", 'ď': "d", 'đ': "d",
+	'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E", 'Ē': "E", 'Ė': "E", 'Ę': "E", 'Ě': "E",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'Ğ': "G", 'ğ': "g", 'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I", 'Ī': "I", 'İ': "I",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i", 'Ł': "L", 'ł': "l",
+	'Ñ': "N", 'Ń': "N", 'Ň': "N", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ö': "O", 'Ø': "O", 'Ō': "O", 'Ő': "O",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'Ř': "R", 'ř': "r", 'Ś': "S", 'Ş': "S", 'Š': "S", 'ś': "s", 'ş': "s", 'š': "s",
+	'Ţ': "T", 'Ť': "T", 'ţ': "t", 'ť': "t",
+	'Ù': "U", 'Ú': "U", 'Û': "U", 'Ü': "U", 'Ū': "U", 'Ů': "U", 'Ű': "U",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'Ý': "Y", 'Ÿ': "Y", 'ý': "y", 'ÿ': "y", 'Ź': "Z", 'Ż': "Z", 'Ž': "Z", 'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// SafeFilename converts a patch title into a slug which can be used as part
+// of a filename on any filesystem.  Common accented letters are transliterated
+// to ASCII, whitespace and path separators are turned into dashes, ASCII
+// letters, digits and the characters "-", "_" and "+" are kept and everything
+// else, e.g. emoji or CJK characters, is removed.  The slug is capped in
+// length and suffixed with a short hash of the title, such that distinct
+// titles which are shortened to the same slug still result in distinct
+// filenames.  If nothing remains, a generic fallback is used as the slug.
+func SafeFilename(title string) string {
+	sum := sha256.Sum256([]byte(title))
+	suffix := "-" + hex.EncodeToString(sum[:])[:safeFilenameHashLen]
+	maxLen := safeFilenameMaxLen - len(suffix)
+
+	var b strings.Builder
+
+	for _, r := range title {
+		var next string
+
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '+'):
+			next = string(r)
+		case unicode.IsSpace(r), r == '-', r == '/', r == '\\':
+			next = "-"
+		default:
+			next = transliterations[r]
+		}
+
+		if next == "" {
+			continue
+		}
+
+		// Collapse consecutive dashes and never start with one.
+		if next == "-" && (b.Len() == 0 || strings.HasSuffix(b.String(), "-")) {
+			continue
+		}
+
+		if b.Len()+len(next) > maxLen {
+			break
+		}
+
+		b.WriteString(next)
+	}
+
+	slug := strings.TrimRight(b.String(), "-")
+	if slug == "" {
+		slug = safeFilenameFallback
+	}
+
+	return slug + suffix
+}
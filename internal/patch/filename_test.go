@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package patch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// safeFilenamePattern matches every filename returned by SafeFilename.
+var safeFilenamePattern = regexp.MustCompile(`^[A-Za-z0-9_+][A-Za-z0-9_+-]*-[0-9a-f]{8}$`)
+
+// slug returns the filename without the hash suffix.
+func slug(filename string) string {
+	return filename[:len(filename)-safeFilenameHashLen-1]
+}
+
+func TestSafeFilename(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{
+			name:  "conventional title",
+			title: "lib/ukboot: Fix boot order",
+			want:  "lib-ukboot-Fix-boot-order",
+		},
+		{
+			name:  "dots and backticks",
+			title: "plat/kvm: Use `ukplat_bootinfo` in v0.17.0",
+			want:  "plat-kvm-Use-ukplat_bootinfo-in-v0170",
+		},
+		{
+			name:  "characters illegal on windows",
+			title: `a*b"c<d>e|f?g:h\i`,
+			want:  "abcdefgh-i",
+		},
+		{
+			name:  "repeated separators",
+			title: "  lib//ukalloc --  Fix   leak  ",
+			want:  "lib-ukalloc-Fix-leak",
+		},
+		{
+			name:  "control characters",
+			title: "fix\x00\x1b[31mred\ttab\nnewline",
+			want:  "fix31mred-tab-newline",
+		},
+		{
+			name:  "non-ascii letters",
+			title: "docs: Übersetzung hinzufügen",
+			want:  "docs-Ubersetzung-hinzufugen",
+		},
+		{
+			name:  "emoji",
+			title: "🐛 lib/ukboot: Fix 🔥 boot ✨",
+			want:  "lib-ukboot-Fix-boot",
+		},
+		{
+			name:  "cjk",
+			title: "docs: 添加中文文档",
+			want:  "docs",
+		},
+		{
+			name:  "path traversal",
+			title: "../../etc/passwd",
+			want:  "etc-passwd",
+		},
+		{
+			name:  "only punctuation",
+			title: ".:?*<>|",
+			want:  "patch",
+		},
+		{
+			name:  "empty",
+			title: "",
+			want:  "patch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SafeFilename(tt.title)
+			if !safeFilenamePattern.MatchString(got) {
+				t.Fatalf("SafeFilename(%q) = %q, want it to match %s", tt.title, got, safeFilenamePattern)
+			}
+
+			if slug(got) != tt.want {
+				t.Errorf("SafeFilename(%q) = %q, want %q with a hash suffix", tt.title, got, tt.want)
+			}
+
+			if again := SafeFilename(tt.title); again != got {
+				t.Errorf("SafeFilename(%q) is not stable: %q and %q", tt.title, got, again)
+			}
+		})
+	}
+}
+
+func TestSafeFilenameLength(t *testing.T) {
+	for _, title := range []string{
+		strings.Repeat("ü", 200),
+		strings.Repeat("a", 300),
+		strings.Repeat("lib/ukboot: Fix 🔥 ", 20),
+	} {
+		got := SafeFilename(title)
+		if len(got) > safeFilenameMaxLen {
+			t.Errorf("SafeFilename() returned %d bytes, want at most %d", len(got), safeFilenameMaxLen)
+		}
+
+		if !safeFilenamePattern.MatchString(got) {
+			t.Errorf("SafeFilename() = %q, want it to match %s", got, safeFilenamePattern)
+		}
+	}
+}
+
+func TestSafeFilenameUnique(t *testing.T) {
+	long := strings.Repeat("x", 300)
+
+	titles := []string{
+		// Collide once emoji are removed.
+		"lib/ukboot: Fix boot",
+		"lib/ukboot: Fix boot 🔥",
+		"lib/ukboot: Fix boot ✨",
+		// Collide once transliterated.
+		"docs: Über",
+		"docs: Uber",
+		// Collide once capped.
+		long + "a",
+		long + "b",
+		// Collide on the fallback.
+		"🔥",
+		"✨",
+	}
+
+	seen := make(map[string]string)
+	for _, title := range titles {
+		got := SafeFilename(title)
+		if other, ok := seen[got]; ok {
+			t.Errorf("SafeFilename(%q) = SafeFilename(%q) = %q", title, other, got)
+		}
+
+		seen[got] = title
+	}
+
+	if got := len(seen); got != len(titles) {
+		t.Errorf("got %d distinct filenames, want %d", got, len(titles))
+	}
+
+	for i := 0; i < 3; i++ {
+		if got, want := SafeFilename(fmt.Sprintf("%s%d", long, i)), SafeFilename(fmt.Sprintf("%s%d", long, i)); got != want {
+			t.Errorf("SafeFilename() is not stable: %q and %q", got, want)
+		}
+	}
+}
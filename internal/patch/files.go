@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package patch
+
+import (
+	"strings"
+)
+
+// FileStat is the number of lines added to and deleted from a single file by
+// a patch.
+type FileStat struct {
+	Path      string `json:"path"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// Files returns the files changed by the patch in the order in which they
+// appear in its diff.  Deleted files are reported by their previous path and
+// binary files are reported without any changed lines.
+func (p *Patch) Files() []FileStat {
+	return DiffFiles(p.Diff)
+}
+
+// DiffFiles returns the files changed by the provided unified diff, see Files.
+func DiffFiles(diff string) []FileStat {
+	var files []FileStat
+	var current *FileStat
+	inHunk := false
+
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			files = append(files, FileStat{Path: diffGitPath(line)})
+			current, inHunk = &files[len(files)-1], false
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		if inHunk {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				current.Additions++
+			case strings.HasPrefix(line, "-"):
+				current.Deletions++
+			}
+
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "+++ b/"):
+			current.Path = strings.TrimPrefix(line, "+++ b/")
+		case strings.HasPrefix(line, "--- a/") && current.Path == "":
+			current.Path = strings.TrimPrefix(line, "--- a/")
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		}
+	}
+
+	return files
+}
+
+// diffGitPath returns the new path of the file in the header of a git diff,
+// e.g. "diff --git a/old.c b/new.c", which is later superseded by the "+++"
+// line where present.  Paths containing " b/" cannot be told apart and are
+// left empty until then.
+func diffGitPath(line string) string {
+	header := strings.TrimPrefix(line, "diff --git ")
+	if strings.Count(header, " b/") != 1 {
+		return ""
+	}
+
+	_, path, _ := strings.Cut(header, " b/")
+
+	return path
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package patch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffFiles(t *testing.T) {
+	diff := `diff --git a/main.c b/main.c
+index 1111111..2222222 100644
+--- a/main.c
++++ b/main.c
+@@ -1,2 +1,2 @@
+-int x;
++int y;
+--- a comment which looks like a header
+diff --git a/old.c b/new.c
+similarity index 90%
+rename from old.c
+rename to new.c
+--- a/old.c
++++ b/new.c
+@@ -1 +1,2 @@
+ int z;
++int w;
+diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..3333333
+Binary files /dev/null and b/logo.png differ
+diff --git a/gone.c b/gone.c
+deleted file mode 100644
+index 4444444..0000000
+--- a/gone.c
++++ /dev/null
+@@ -1 +0,0 @@
+-int v;
+`
+
+	want := []FileStat{
+		{Path: "main.c", Additions: 1, Deletions: 2},
+		{Path: "new.c", Additions: 1},
+		{Path: "logo.png"},
+		{Path: "gone.c", Deletions: 1},
+	}
+
+	if got := (&Patch{Diff: diff}).Files(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Files() = %+v, want %+v", got, want)
+	}
+}
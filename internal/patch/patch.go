@@ -35,10 +35,15 @@ type Patch struct {
 	Diff        string
 
 	// patch *gitobject.Patch
+	gitBinary string
 }
 
+// DefaultGitBinary is the git executable which is used unless otherwise
+// specified.
+const DefaultGitBinary = "git"
+
 // NewPatchFromCommits accepts two commits which are used to generate a patch.
-func NewPatchFromCommits(ctx context.Context, repoPath string, commit, diff *gitobject.Commit) (*Patch, error) {
+func NewPatchFromCommits(ctx context.Context, repoPath string, commit, diff *gitobject.Commit, opts ...PatchOption) (*Patch, error) {
 	if commit == nil {
 		return nil, fmt.Errorf("cannot create patchfile without commit")
 	} else if diff == nil {
@@ -53,6 +58,13 @@ func NewPatchFromCommits(ctx context.Context, repoPath string, commit, diff *git
 		AuthorEmail: commit.Author.Email,
 		AuthorDate:  commit.Author.When.Format(time.RFC1123Z),
 		Hash:        commit.Hash.String(),
+		gitBinary:   DefaultGitBinary,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&patch); err != nil {
+			return nil, fmt.Errorf("could not apply option: %w", err)
+		}
 	}
 
 	var message []string
@@ -79,10 +91,28 @@ func NewPatchFromCommits(ctx context.Context, repoPath string, commit, diff *git
 
 	patch.Message = strings.Join(message, "\n")
 
+	// Without a git executable, fall back to generating the stat and diff
+	// natively.  The output may differ slightly in formatting from git's.
+	if _, err := exec.LookPath(patch.gitBinary); err != nil {
+		log.G(ctx).
+			WithField("git", patch.gitBinary).
+			Debug("git executable not found, generating patch natively")
+
+		p, err := diff.PatchContext(ctx, commit)
+		if err != nil {
+			return nil, fmt.Errorf("could not generate patch: %w", err)
+		}
+
+		patch.Stat = p.Stats().String()
+		patch.Diff = p.String()
+
+		return &patch, nil
+	}
+
 	var buf bytes.Buffer
 
 	gitShow := exec.CommandContext(ctx,
-		"git",
+		patch.gitBinary,
 		"-C", repoPath,
 		"show",
 		"--stat",
@@ -103,7 +133,7 @@ func NewPatchFromCommits(ctx context.Context, repoPath string, commit, diff *git
 	buf = *bytes.NewBuffer(nil)
 
 	gitDiff := exec.CommandContext(ctx,
-		"git",
+		patch.gitBinary,
 		"-C",
 		repoPath,
 		"diff",
@@ -157,6 +187,24 @@ func (p *Patch) message() *bytes.Buffer {
 	return &b
 }
 
+// CommitMessage returns the full commit message of the patch, i.e. its title,
+// message and trailers, exactly as it would be recorded by git.
+func (p *Patch) CommitMessage() string {
+	var b strings.Builder
+
+	b.WriteString(p.Title)
+	b.WriteString("\n")
+	b.WriteString(p.Message)
+	b.WriteString("\n")
+
+	if len(p.Trailers) > 0 {
+		b.WriteString(strings.Join(p.Trailers, "\n"))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
 func (p *Patch) String() string {
 	return p.message().String()
 }
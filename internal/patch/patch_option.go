@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package patch
+
+// PatchOption is used to customize the generation of a patch.
+type PatchOption func(*Patch) error
+
+// WithGitBinary sets the path to the git executable which is used to generate
+// the patch.  When unset, "git" is looked up in the PATH.  If no executable
+// can be found, the patch is generated natively.
+func WithGitBinary(path string) PatchOption {
+	return func(p *Patch) error {
+		if path != "" {
+			p.gitBinary = path
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package patch
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	git "github.com/go-git/go-git/v5"
+	gitobject "github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// newTestCommits creates a repository with two commits and returns its path
+// together with the head commit and its parent.
+func newTestCommits(t *testing.T) (string, *gitobject.Commit, *gitobject.Commit) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Doe",
+			"GIT_AUTHOR_EMAIL=jane@unikraft.io",
+			"GIT_COMMITTER_NAME=Jane Doe",
+			"GIT_COMMITTER_EMAIL=jane@unikraft.io",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	run("init", "-q")
+
+	for _, content := range []string{"int x;\n", "int y;\n"} {
+		if err := os.WriteFile(filepath.Join(dir, "main.c"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		run("add", ".")
+		run("commit", "-q", "-m", "lib/test: Update main\n\nSigned-off-by: Jane Doe <jane@unikraft.io>\n")
+	}
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent, err := head.Parent(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dir, head, parent
+}
+
+func TestNewPatchFromCommitsGitBinary(t *testing.T) {
+	dir, head, parent := newTestCommits(t)
+
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git not available")
+	}
+
+	// Wrap git with a script which records each invocation.
+	bin := t.TempDir()
+	marker := filepath.Join(bin, "invocations")
+	wrapper := filepath.Join(bin, "my-git")
+	script := "#!/bin/sh\necho \"$@\" >> " + marker + "\nexec " + gitPath + " \"$@\"\n"
+	if err := os.WriteFile(wrapper, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewPatchFromCommits(context.Background(), dir, head, parent, WithGitBinary(wrapper))
+	if err != nil {
+		t.Fatalf("NewPatchFromCommits() unexpected error: %v", err)
+	}
+
+	invocations, err := os.ReadFile(marker)
+	if err != nil {
+		t.Fatalf("expected custom git binary to be used: %v", err)
+	}
+
+	for _, sub := range []string{"show", "diff"} {
+		if !strings.Contains(string(invocations), sub) {
+			t.Errorf("expected 'git %s' to use the custom binary, got: %s", sub, invocations)
+		}
+	}
+
+	if !strings.Contains(p.Diff, "main.c") {
+		t.Errorf("expected diff to mention main.c, got: %s", p.Diff)
+	}
+}
+
+func TestNewPatchFromCommitsNative(t *testing.T) {
+	dir, head, parent := newTestCommits(t)
+
+	p, err := NewPatchFromCommits(context.Background(), dir, head, parent,
+		WithGitBinary(filepath.Join(t.TempDir(), "does-not-exist")),
+	)
+	if err != nil {
+		t.Fatalf("NewPatchFromCommits() unexpected error: %v", err)
+	}
+
+	if !strings.Contains(p.Diff, "main.c") {
+		t.Errorf("expected diff to mention main.c, got: %s", p.Diff)
+	}
+	if !strings.Contains(p.Stat, "main.c") {
+		t.Errorf("expected stat to mention main.c, got: %s", p.Stat)
+	}
+}
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package repo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/unikraft/governance/internal/yamledit"
+)
+
+// FreezeDateFormat is the format of the dates which bound a freeze.
+const FreezeDateFormat = "2006-01-02"
+
+// Freeze puts a repository into maintenance mode, e.g. during the
+// stabilisation of a release, in which only pull requests carrying one of the
+// allowed labels are merged.
+type Freeze struct {
+	// Active freezes the repository until the freeze is lifted or, if set,
+	// until Until.
+	Active bool `yaml:"active,omitempty"`
+
+	// From and Until are the first and the last day of the freeze, inclusive,
+	// in the form YYYY-MM-DD.  Either may be omitted for an open range.
+	From  string `yaml:"from,omitempty"`
+	Until string `yaml:"until,omitempty"`
+
+	// AllowedLabels exempt the pull requests which carry any of them, e.g.
+	// "release-blocker".
+	AllowedLabels []string `yaml:"allowed_labels,omitempty"`
+
+	// Message explains the freeze to contributors.
+	Message string `yaml:"message,omitempty"`
+}
+
+// Validate rejects freezes whose dates cannot be parsed.
+func (f *Freeze) Validate() error {
+	from, until, err := f.bounds()
+	if err != nil {
+		return err
+	}
+
+	if !from.IsZero() && !until.IsZero() && !until.After(from) {
+		return fmt.Errorf("freeze ends on %s before it starts on %s", f.Until, f.From)
+	}
+
+	return nil
+}
+
+// bounds returns the start of the first day and the end of the last day of the
+// freeze, which are zero if unset.
+func (f *Freeze) bounds() (from time.Time, until time.Time, err error) {
+	if f.From != "" {
+		if from, err = time.Parse(FreezeDateFormat, f.From); err != nil {
+			return from, until, fmt.Errorf("invalid freeze start '%s': expected YYYY-MM-DD", f.From)
+		}
+	}
+
+	if f.Until != "" {
+		if until, err = time.Parse(FreezeDateFormat, f.Until); err != nil {
+			return from, until, fmt.Errorf("invalid freeze end '%s': expected YYYY-MM-DD", f.Until)
+		}
+
+		until = until.AddDate(0, 0, 1)
+	}
+
+	return from, until, nil
+}
+
+// ActiveAt returns whether the repository is frozen at the provided time.  A
+// freeze is active if it is marked as active or bounded by dates, and the time
+// lies within its bounds.  A nil freeze is never active.
+func (f *Freeze) ActiveAt(t time.Time) bool {
+	if f == nil || (!f.Active && f.From == "" && f.Until == "") {
+		return false
+	}
+
+	from, until, err := f.bounds()
+	if err != nil {
+		// An invalid freeze is rejected when it is loaded, see Validate.
+		return false
+	}
+
+	if !from.IsZero() && t.Before(from) {
+		return false
+	}
+
+	if !until.IsZero() && !t.Before(until) {
+		return false
+	}
+
+	return true
+}
+
+// Exemption returns the first of the labels which is allowed during the
+// freeze, or an empty string if none is.
+func (f *Freeze) Exemption(labels []string) string {
+	if f == nil {
+		return ""
+	}
+
+	for _, label := range labels {
+		for _, allowed := range f.AllowedLabels {
+			if strings.EqualFold(label, allowed) {
+				return label
+			}
+		}
+	}
+
+	return ""
+}
+
+// Notice returns the message which explains the freeze to contributors,
+// falling back to a description of its bounds and exemptions.
+func (f *Freeze) Notice() string {
+	if f.Message != "" {
+		return f.Message
+	}
+
+	notice := "This repository is frozen"
+	if f.Until != "" {
+		notice += " until " + f.Until
+	}
+
+	if len(f.AllowedLabels) == 0 {
+		return notice + " and pull requests are not merged."
+	}
+
+	return fmt.Sprintf("%s and only pull requests labelled %s are merged.", notice, strings.Join(f.AllowedLabels, " or "))
+}
+
+// FindDefinition returns the (zero-indexed) line of the name of the repository
+// in the YAML document, which may contain multiple repositories, or -1 if the
+// repository is not defined in it.  Names are compared with and without their
+// type prefix, e.g. "lib-lwip" matches "lwip".
+func FindDefinition(src []byte, name string) int {
+	bare := stripType(name)
+
+	candidates := []string{name, bare}
+	for _, t := range RepoTypes {
+		candidates = append(candidates, fmt.Sprintf("%s-%s", t, bare))
+	}
+
+	ls := strings.Split(string(src), "\n")
+
+	// Exact matches take precedence over those of another type.
+	for _, candidate := range candidates {
+		for _, line := range yamledit.FindScalars(src, "name", candidate) {
+			// Only the names of repositories, which are top-level entries.
+			if strings.HasPrefix(ls[line], "name:") {
+				return line
+			}
+		}
+	}
+
+	return -1
+}
+
+// stripType returns the name of a repository without its type prefix, e.g.
+// "lwip" for "lib-lwip".
+func stripType(name string) string {
+	for _, t := range RepoTypes {
+		if n, ok := strings.CutPrefix(name, string(t)+"-"); ok {
+			return n
+		}
+	}
+
+	return name
+}
+
+// SetFreeze sets the freeze of the repository whose name is on the provided
+// line of the YAML document, replacing any existing one whilst leaving the
+// rest of the document untouched.
+func SetFreeze(src []byte, line int, freeze *Freeze) ([]byte, error) {
+	block, err := yaml.Marshal(freeze)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal freeze: %w", err)
+	}
+
+	return yamledit.SetBlock(src, line, "freeze", block), nil
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package repo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFreezeActiveAt(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return d
+	}
+
+	tests := []struct {
+		name   string
+		freeze *Freeze
+		at     string
+		want   bool
+	}{
+		{
+			name: "none",
+			at:   "2024-01-15T12:00:00Z",
+		},
+		{
+			name:   "inactive",
+			freeze: &Freeze{AllowedLabels: []string{"release-blocker"}},
+			at:     "2024-01-15T12:00:00Z",
+		},
+		{
+			name:   "active",
+			freeze: &Freeze{Active: true},
+			at:     "2024-01-15T12:00:00Z",
+			want:   true,
+		},
+		{
+			name:   "within range",
+			freeze: &Freeze{From: "2024-01-10", Until: "2024-01-31"},
+			at:     "2024-01-15T12:00:00Z",
+			want:   true,
+		},
+		{
+			name:   "last day",
+			freeze: &Freeze{From: "2024-01-10", Until: "2024-01-31"},
+			at:     "2024-01-31T23:59:59Z",
+			want:   true,
+		},
+		{
+			name:   "before range",
+			freeze: &Freeze{From: "2024-01-10", Until: "2024-01-31"},
+			at:     "2024-01-09T23:59:59Z",
+		},
+		{
+			name:   "after range",
+			freeze: &Freeze{Until: "2024-01-31"},
+			at:     "2024-02-01T00:00:00Z",
+		},
+		{
+			name:   "active until",
+			freeze: &Freeze{Active: true, Until: "2024-01-31"},
+			at:     "2024-02-01T00:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.freeze.ActiveAt(day(tt.at)); got != tt.want {
+				t.Errorf("ActiveAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFreezeValidate(t *testing.T) {
+	for _, invalid := range []*Freeze{
+		{Until: "31/01/2024"},
+		{From: "2024-02-01", Until: "2024-01-31"},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("Validate(%+v) expected an error", invalid)
+		}
+	}
+}
+
+func TestFreezeExemption(t *testing.T) {
+	freeze := &Freeze{AllowedLabels: []string{"release-blocker"}}
+
+	if got := freeze.Exemption([]string{"kind/bug", "Release-Blocker"}); got != "Release-Blocker" {
+		t.Errorf("Exemption() = %q, want %q", got, "Release-Blocker")
+	}
+
+	if got := freeze.Exemption([]string{"kind/bug"}); got != "" {
+		t.Errorf("Exemption() = %q, want none", got)
+	}
+}
+
+func TestSetFreeze(t *testing.T) {
+	src := []byte(`---
+name: unikraft
+type: core
+---
+# The network stack
+name: lib-lwip
+num_shadow_maintainers: 1
+`)
+
+	line := FindDefinition(src, "lwip")
+	if line != 5 {
+		t.Fatalf("FindDefinition() = %d, want 5", line)
+	}
+
+	got, err := SetFreeze(src, line, &Freeze{
+		Until:         "2024-01-31",
+		AllowedLabels: []string{"release-blocker"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `---
+name: unikraft
+type: core
+---
+# The network stack
+name: lib-lwip
+num_shadow_maintainers: 1
+freeze:
+  until: "2024-01-31"
+  allowed_labels:
+  - release-blocker
+`
+	if string(got) != want {
+		t.Errorf("SetFreeze() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if line := FindDefinition(src, "app-helloworld"); line != -1 {
+		t.Errorf("FindDefinition() = %d, want -1", line)
+	}
+}
@@ -6,10 +6,15 @@
 package repo
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
 	"path"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 
@@ -53,6 +58,24 @@ type Repository struct {
 	fullname        string
 	Name            string              `yaml:"name,omitempty"`
 	PermissionLevel RepoPermissionLevel `yaml:"permission,omitempty"`
+
+	// NumShadowMaintainers is the number of secondary maintainers assigned to
+	// each pull request in addition to its primary maintainers.
+	NumShadowMaintainers int `yaml:"num_shadow_maintainers,omitempty"`
+
+	// Language is the language of the comments left on pull requests of the
+	// repository, e.g. "de".  Comments fall back to English if unset or if a
+	// comment has not been translated.
+	Language string `yaml:"language,omitempty"`
+
+	// Freeze restricts which pull requests are merged, e.g. during the
+	// stabilisation of a release.
+	Freeze *Freeze `yaml:"freeze,omitempty"`
+}
+
+// FrozenAt returns whether the repository is frozen at the provided time.
+func (r *Repository) FrozenAt(t time.Time) bool {
+	return r.Freeze.ActiveAt(t)
 }
 
 func (r *Repository) NameEquals(name string) bool {
@@ -125,21 +148,68 @@ func NewTeamFromYAML(ghApi *ghapi.GithubClient, githubOrg, reposFile string) (*R
 		return nil, fmt.Errorf("could not open yaml file: %s", err)
 	}
 
-	repo := &Repository{
-		ghApi: ghApi,
-	}
+	repo := &Repository{}
 
 	err = yaml.Unmarshal(yamlFile, repo)
 	if err != nil {
 		return nil, fmt.Errorf("could not unmarshal yaml file: %s", err)
 	}
 
+	return newRepo(ghApi, githubOrg, repo, reposFile)
+}
+
+// NewListOfReposFromYAML returns every repo defined in the provided file,
+// which may contain multiple YAML documents separated by "---".  Empty
+// documents are skipped.
+func NewListOfReposFromYAML(ghApi *ghapi.GithubClient, githubOrg, reposFile string) ([]*Repository, error) {
+	yamlFile, err := ioutil.ReadFile(reposFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not open yaml file: %s", err)
+	}
+
+	var repos []*Repository
+
+	decoder := yaml.NewDecoder(bytes.NewReader(yamlFile))
+	for {
+		var repo *Repository
+		if err := decoder.Decode(&repo); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("could not unmarshal yaml file: %s", err)
+		}
+
+		if repo == nil {
+			continue
+		}
+
+		r, err := newRepo(ghApi, githubOrg, repo, reposFile)
+		if err != nil {
+			return nil, err
+		}
+
+		repos = append(repos, r)
+	}
+
+	return repos, nil
+}
+
+// newRepo checks the sanity of a repo decoded from the provided file and sets
+// its origin.
+func newRepo(ghApi *ghapi.GithubClient, githubOrg string, repo *Repository, reposFile string) (*Repository, error) {
+	repo.ghApi = ghApi
+
 	// Let's perform a sanity check and check if we have at least the name of the
 	// repo.
 	if repo.Name == "" {
 		return nil, fmt.Errorf("repo name not provided for %s", reposFile)
 	}
 
+	if repo.Freeze != nil {
+		if err := repo.Freeze.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid freeze of repo %s in %s: %w", repo.Name, reposFile, err)
+		}
+	}
+
 	// Let's set the remote path to this repository
 	repo.Origin = fmt.Sprintf(
 		"https://github.com/%s/%s.git",
@@ -150,26 +220,43 @@ func NewTeamFromYAML(ghApi *ghapi.GithubClient, githubOrg, reposFile string) (*R
 	return repo, nil
 }
 
+// NewListOfReposFromPath returns every repo defined at the provided path,
+// which is either a directory with one or more files per repo or a single file
+// with one YAML document per repo.
 func NewListOfReposFromPath(ghApi *ghapi.GithubClient, githubOrg, reposDir string) ([]*Repository, error) {
 	repos := make([]*Repository, 0)
 
-	files, err := ioutil.ReadDir(reposDir)
+	fi, err := os.Stat(reposDir)
 	if err != nil {
 		return nil, fmt.Errorf("could not read directory: %s", err)
 	}
 
+	var files []string
+	if fi.IsDir() {
+		entries, err := ioutil.ReadDir(reposDir)
+		if err != nil {
+			return nil, fmt.Errorf("could not read directory: %s", err)
+		}
+
+		for _, entry := range entries {
+			files = append(files, path.Join(reposDir, entry.Name()))
+		}
+	} else {
+		files = append(files, reposDir)
+	}
+
 	// Iterate through all files and populate a list of known repos.
 	for _, file := range files {
-		r, err := NewTeamFromYAML(
+		r, err := NewListOfReposFromYAML(
 			ghApi,
 			githubOrg,
-			path.Join(reposDir, file.Name()),
+			file,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("could not parse repos file: %s", err)
 		}
 
-		repos = append(repos, r)
+		repos = append(repos, r...)
 	}
 
 	return repos, nil
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package repo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const (
+	testRepoLwip = `name: lib-lwip
+num_shadow_maintainers: 1
+`
+	testRepoUnikraft = `name: unikraft
+type: core
+`
+)
+
+func TestNewListOfReposFromPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "directory",
+			files: map[string]string{
+				"lib-lwip.yaml": testRepoLwip,
+				"unikraft.yaml": testRepoUnikraft,
+			},
+			want: []string{
+				"https://github.com/unikraft/lib-lwip.git",
+				"https://github.com/unikraft/unikraft.git",
+			},
+		},
+		{
+			name: "multi-document file",
+			files: map[string]string{
+				"repos.yaml": "---\n" + testRepoUnikraft + "---\n" + testRepoLwip,
+			},
+			path: "repos.yaml",
+			want: []string{
+				"https://github.com/unikraft/unikraft.git",
+				"https://github.com/unikraft/lib-lwip.git",
+			},
+		},
+		{
+			name: "multi-document file with empty documents",
+			files: map[string]string{
+				"repos.yaml": testRepoUnikraft + "---\n---\n" + testRepoLwip + "---\n",
+			},
+			path: "repos.yaml",
+			want: []string{
+				"https://github.com/unikraft/unikraft.git",
+				"https://github.com/unikraft/lib-lwip.git",
+			},
+		},
+		{
+			name: "document without name",
+			files: map[string]string{
+				"repos.yaml": testRepoUnikraft + "---\ntype: lib\n",
+			},
+			path:    "repos.yaml",
+			wantErr: true,
+		},
+		{
+			name:    "missing path",
+			path:    "repos.yaml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			repos, err := NewListOfReposFromPath(nil, "unikraft", filepath.Join(dir, tt.path))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewListOfReposFromPath() expected error, got %d repos", len(repos))
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("NewListOfReposFromPath() unexpected error: %v", err)
+			}
+
+			var origins []string
+			for _, repo := range repos {
+				origins = append(origins, repo.Origin)
+			}
+
+			if !reflect.DeepEqual(origins, tt.want) {
+				t.Errorf("NewListOfReposFromPath() = %v, want %v", origins, tt.want)
+			}
+
+			if lwip := FindRepoByName("lib-lwip", repos); lwip == nil || lwip.NumShadowMaintainers != 1 {
+				t.Errorf("expected lib-lwip to be found with one shadow maintainer")
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file expect in compliance with the License.
+package tableprinter
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+func (printer *TablePrinter) renderCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	for _, row := range printer.rows {
+		if len(row) == 0 {
+			continue
+		}
+
+		// Colors are deliberately not applied such that the output can be read
+		// by spreadsheets.
+		record := make([]string, 0, len(row))
+		for _, field := range row {
+			record = append(record, field.text)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package tableprinter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// markdownEscaper escapes the characters which would otherwise end a cell of
+// a GitHub-flavoured markdown table or the row it is in.
+var markdownEscaper = strings.NewReplacer(
+	`|`, `\|`,
+	"\r\n", "<br>",
+	"\n", "<br>",
+)
+
+func (printer *TablePrinter) renderMarkdown(w io.Writer) error {
+	for i, row := range printer.rows {
+		if len(row) == 0 {
+			continue
+		}
+
+		// Colors are deliberately not applied such that the output can be
+		// posted as is, e.g. as a comment.
+		cells := make([]string, 0, len(row))
+		for _, field := range row {
+			cells = append(cells, markdownEscaper.Replace(field.text))
+		}
+
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+			return err
+		}
+
+		if i == 0 {
+			if _, err := fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(row))); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
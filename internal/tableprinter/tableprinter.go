@@ -25,6 +25,7 @@ package tableprinter
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"sort"
 	"strings"
@@ -38,6 +39,10 @@ const (
 	OutputFormatTable = TableOutputFormat("table")
 	OutputFormatJSON  = TableOutputFormat("json")
 	OutputFormatYAML  = TableOutputFormat("yaml")
+	OutputFormatCSV   = TableOutputFormat("csv")
+
+	// OutputFormatMarkdown renders a GitHub-flavoured markdown table.
+	OutputFormatMarkdown = TableOutputFormat("markdown")
 
 	DefaultDelimeter = "  "
 )
@@ -57,6 +62,7 @@ type TablePrinter struct {
 	maxWidth     int
 	delimeter    string
 	truncateFunc func(int, string) string
+	columns      []string
 }
 
 // NewTablePrinter returns a pointer instance of TablePrinter struct.
@@ -93,6 +99,17 @@ func (printer *TablePrinter) AddField(s string, colorFunc func(string) string) {
 	printer.rows[rowI] = append(printer.rows[rowI], field)
 }
 
+// AddHeader adds the row of the names of the fields of the table, which
+// identify the columns selected with WithColumns and the keys of the JSON and
+// YAML output.
+func (printer *TablePrinter) AddHeader(colorFunc func(string) string, names ...string) {
+	for _, name := range names {
+		printer.AddField(name, colorFunc)
+	}
+
+	printer.EndRow()
+}
+
 // EndRow ends the current row.
 func (printer *TablePrinter) EndRow() {
 	printer.rows = append(printer.rows, []TableField{})
@@ -103,16 +120,90 @@ func (printer *TablePrinter) Render(w io.Writer) error {
 		return nil
 	}
 
+	if len(printer.columns) > 0 {
+		if err := printer.selectColumns(); err != nil {
+			return err
+		}
+	}
+
 	switch printer.format {
+	case OutputFormatCSV:
+		return printer.renderCSV(w)
 	case OutputFormatJSON:
 		return printer.renderJSON(w)
 	case OutputFormatYAML:
 		return printer.renderYAML(w)
+	case OutputFormatMarkdown:
+		return printer.renderMarkdown(w)
 	default:
 		return printer.renderTable(w)
 	}
 }
 
+// ValidateColumns checks that every selected column is one of the available
+// columns, which are compared case-insensitively.
+func ValidateColumns(available, selected []string) error {
+	_, err := columnIndexes(available, selected)
+	return err
+}
+
+// columnIndexes returns the index of each selected column amongst the
+// available ones.
+func columnIndexes(available, selected []string) ([]int, error) {
+	indexes := make([]int, 0, len(selected))
+
+	for _, name := range selected {
+		index := -1
+		for i, column := range available {
+			if strings.EqualFold(strings.TrimSpace(name), column) {
+				index = i
+				break
+			}
+		}
+
+		if index < 0 {
+			return nil, fmt.Errorf("unknown column '%s': expected one of %s", name, strings.ToLower(strings.Join(available, ", ")))
+		}
+
+		indexes = append(indexes, index)
+	}
+
+	return indexes, nil
+}
+
+// selectColumns filters and reorders the fields of every row by the columns
+// selected with WithColumns.
+func (printer *TablePrinter) selectColumns() error {
+	var header []string
+	for _, field := range printer.rows[0] {
+		header = append(header, field.text)
+	}
+
+	indexes, err := columnIndexes(header, printer.columns)
+	if err != nil {
+		return err
+	}
+
+	for i, row := range printer.rows {
+		if len(row) == 0 {
+			continue
+		}
+
+		selected := make([]TableField, 0, len(indexes))
+		for _, index := range indexes {
+			if index < len(row) {
+				selected = append(selected, row[index])
+			} else {
+				selected = append(selected, TableField{})
+			}
+		}
+
+		printer.rows[i] = selected
+	}
+
+	return nil
+}
+
 func (printer *TablePrinter) calculateColumnWidths(delimSize int) []int {
 	numCols := len(printer.rows[0])
 	allColWidths := make([][]int, numCols)
@@ -52,3 +52,13 @@ func WithMaxWidth(maxWidth int) TablePrinterOption {
 		return nil
 	}
 }
+
+// WithColumns returns a function func(opts *TablePrinter)
+// that sets `columns` in TablePrinter pointer instance, which filter and
+// reorder the fields of the table by their (case-insensitive) header names.
+func WithColumns(names ...string) TablePrinterOption {
+	return func(opts *TablePrinter) error {
+		opts.columns = names
+		return nil
+	}
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package tableprinter
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	red := func(s string) string { return "\x1b[31m" + s + "\x1b[0m" }
+
+	tests := []struct {
+		name    string
+		opts    []TablePrinterOption
+		want    string
+		wantErr string
+	}{
+		{
+			name: "table",
+			want: "COMMIT   FILE              REASON\n" +
+				"abc1234  lib/foo.c         \x1b[31mmissing SPDX identifier\x1b[0m\n" +
+				"def5678  \"quoted\", file.c  \x1b[31mno copyright\x1b[0m\n",
+		},
+		{
+			name: "csv",
+			opts: []TablePrinterOption{WithOutputFormat(OutputFormatCSV)},
+			want: "COMMIT,FILE,REASON\n" +
+				"abc1234,lib/foo.c,missing SPDX identifier\n" +
+				"def5678,\"\"\"quoted\"\", file.c\",no copyright\n",
+		},
+		{
+			name: "markdown",
+			opts: []TablePrinterOption{WithOutputFormat(OutputFormatMarkdown)},
+			want: "| COMMIT | FILE | REASON |\n" +
+				"| --- | --- | --- |\n" +
+				"| abc1234 | lib/foo.c | missing SPDX identifier |\n" +
+				"| def5678 | \"quoted\", file.c | no copyright |\n",
+		},
+		{
+			name: "columns",
+			opts: []TablePrinterOption{WithOutputFormat(OutputFormatCSV), WithColumns("Reason", "commit")},
+			want: "REASON,COMMIT\n" +
+				"missing SPDX identifier,abc1234\n" +
+				"no copyright,def5678\n",
+		},
+		{
+			name: "columns json",
+			opts: []TablePrinterOption{WithOutputFormat(OutputFormatJSON), WithColumns("file")},
+			want: `[{"file":"lib/foo.c"},{"file":"\"quoted\", file.c"}]`,
+		},
+		{
+			name:    "unknown column",
+			opts:    []TablePrinterOption{WithColumns("commit", "author")},
+			wantErr: "unknown column 'author': expected one of commit, file, reason",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := NewTablePrinter(context.Background(), append([]TablePrinterOption{WithMaxWidth(80)}, tt.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			table.AddHeader(nil, "COMMIT", "FILE", "REASON")
+			table.AddField("abc1234", nil)
+			table.AddField("lib/foo.c", nil)
+			table.AddField("missing SPDX identifier", red)
+			table.EndRow()
+			table.AddField("def5678", nil)
+			table.AddField(`"quoted", file.c`, nil)
+			table.AddField("no copyright", red)
+			table.EndRow()
+
+			var out bytes.Buffer
+			err = table.Render(&out)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Render() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Render() unexpected error: %v", err)
+			}
+
+			if got := out.String(); got != tt.want {
+				t.Errorf("Render() =\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownEscapes(t *testing.T) {
+	table, err := NewTablePrinter(context.Background(), WithOutputFormat(OutputFormatMarkdown))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table.AddHeader(nil, "NAME", "DETAILS")
+	table.AddField("a|b", nil)
+	table.AddField("first\nsecond", nil)
+	table.EndRow()
+
+	var out bytes.Buffer
+	if err := table.Render(&out); err != nil {
+		t.Fatalf("Render() unexpected error: %v", err)
+	}
+
+	want := "| NAME | DETAILS |\n" +
+		"| --- | --- |\n" +
+		"| a\\|b | first<br>second |\n"
+	if got := out.String(); got != want {
+		t.Errorf("Render() =\n%q\nwant:\n%q", got, want)
+	}
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"sort"
+
+	"github.com/unikraft/governance/internal/user"
+)
+
+// Aggregate returns the sorted GitHub usernames of all maintainers and of all
+// reviewers across the provided teams, each listed once.
+func Aggregate(teams []*Team) (maintainers []string, reviewers []string) {
+	maintainers = aggregate(teams, func(t *Team) []user.User {
+		return t.Maintainers
+	})
+	reviewers = aggregate(teams, func(t *Team) []user.User {
+		return t.Reviewers
+	})
+
+	return maintainers, reviewers
+}
+
+// aggregate returns the sorted union of the GitHub usernames of the users
+// selected from each team.
+func aggregate(teams []*Team, users func(*Team) []user.User) []string {
+	seen := make(map[string]struct{})
+	ret := make([]string, 0)
+
+	for _, t := range teams {
+		for _, u := range users(t) {
+			if _, ok := seen[u.Github]; ok {
+				continue
+			}
+
+			seen[u.Github] = struct{}{}
+			ret = append(ret, u.Github)
+		}
+	}
+
+	sort.Strings(ret)
+
+	return ret
+}
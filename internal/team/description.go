@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DescriptionData is the metadata of a team which is available to a templated
+// description, e.g.:
+//
+//	description: "{{ .NumMaintainers }} maintainers, {{ .NumRepos }} repositories"
+type DescriptionData struct {
+	Name           string
+	Org            string
+	Type           TeamType
+	NumMaintainers int
+	NumReviewers   int
+
+	// NumMembers is the number of distinct members of the team, which include
+	// its maintainers and reviewers.
+	NumMembers int
+	NumRepos   int
+}
+
+// descriptionData returns the metadata of the team which is available to its
+// description.
+func (t *Team) descriptionData() DescriptionData {
+	maintainers, reviewers, members := t.usernames()
+
+	unique := map[string]bool{}
+	for _, member := range members {
+		unique[strings.ToLower(member)] = true
+	}
+
+	return DescriptionData{
+		Name:           t.Name,
+		Org:            t.Org,
+		Type:           t.Type,
+		NumMaintainers: len(maintainers),
+		NumReviewers:   len(reviewers),
+		NumMembers:     len(unique),
+		NumRepos:       len(t.Repositories),
+	}
+}
+
+// parseDescription parses the description of the team as a template.
+func (t *Team) parseDescription() (*template.Template, error) {
+	return template.New("description").
+		Option("missingkey=error").
+		Parse(t.Description)
+}
+
+// RenderDescription returns the description of the team with its template, if
+// any, rendered against the metadata of the team.  Plain descriptions are
+// returned as is.
+func (t *Team) RenderDescription() (string, error) {
+	if !strings.Contains(t.Description, "{{") {
+		return t.Description, nil
+	}
+
+	tmpl, err := t.parseDescription()
+	if err != nil {
+		return "", fmt.Errorf("could not parse description of team %s: %w", t.Name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, t.descriptionData()); err != nil {
+		return "", fmt.Errorf("could not render description of team %s: %w", t.Name, err)
+	}
+
+	return buf.String(), nil
+}
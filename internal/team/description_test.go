@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/unikraft/governance/internal/repo"
+	"github.com/unikraft/governance/internal/user"
+)
+
+func TestRenderDescription(t *testing.T) {
+	team := func(description string) *Team {
+		return &Team{
+			Org:         "unikraft",
+			Name:        "arch",
+			Type:        SIGTeam,
+			Description: description,
+			Maintainers: []user.User{{Github: "jane"}},
+			Reviewers:   []user.User{{Github: "john"}, {Github: "alice"}},
+			Members: []user.User{
+				// Maintainers and reviewers who are also listed as members are
+				// only counted once.
+				{Github: "Jane"},
+				{Github: "bob"},
+			},
+			Repositories: []repo.Repository{{Name: "unikraft"}, {Name: "lib-lwip"}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		description string
+		want        string
+		wantErr     string
+	}{
+		{
+			name:        "plain",
+			description: "Architecture SIG",
+			want:        "Architecture SIG",
+		},
+		{
+			name:        "templated",
+			description: "SIG {{ .Name }}: {{ .NumMaintainers }} maintainers, {{ .NumReviewers }} reviewers, {{ .NumMembers }} members across {{ .NumRepos }} repositories",
+			want:        "SIG arch: 1 maintainers, 2 reviewers, 4 members across 2 repositories",
+		},
+		{
+			name:        "unknown field",
+			description: "{{ .NumOwners }} owners",
+			wantErr:     "could not render description of team arch",
+		},
+		{
+			name:        "malformed",
+			description: "{{ .NumMembers members",
+			wantErr:     "could not parse description of team arch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := team(tt.description).RenderDescription()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("RenderDescription() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("RenderDescription() unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("RenderDescription() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTeamInvalidDescription(t *testing.T) {
+	_, err := newTeam(nil, "unikraft", &Team{
+		Name:        "sig-arch",
+		Description: "{{ .NumMembers",
+	}, "sig-arch.yaml")
+	if err == nil || !strings.Contains(err.Error(), "invalid description of team arch") {
+		t.Errorf("newTeam() error = %v, want invalid description", err)
+	}
+}
@@ -0,0 +1,393 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gh "github.com/google/go-github/v63/github"
+	"gopkg.in/yaml.v2"
+
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/repo"
+	"github.com/unikraft/governance/internal/user"
+	"github.com/unikraft/governance/utils"
+)
+
+// ImportOptions controls which teams are imported from GitHub.
+type ImportOptions struct {
+	// SkipSecret skips teams which are only visible to their members.
+	SkipSecret bool
+
+	// Exclude are glob patterns, see path.Match, of the names of teams which
+	// are skipped, e.g. "bots-*".
+	Exclude []string
+}
+
+// ImportedTeam is a team definition generated from the current state of
+// GitHub.
+type ImportedTeam struct {
+	Team *Team
+
+	// TODOs describe mappings which could not be made unambiguously and which
+	// should be reviewed before the definition is synchronised.
+	TODOs []string
+}
+
+// ImportedRepo is a repository definition generated for a repository which
+// at least one imported team has been granted access to.
+type ImportedRepo struct {
+	Repo  *repo.Repository
+	TODOs []string
+}
+
+// Imported is the result of importing the teams of an organisation.
+type Imported struct {
+	Teams []*ImportedTeam
+	Repos []*ImportedRepo
+
+	// Skipped are the names of the teams which were excluded.
+	Skipped []string
+}
+
+// ImportFromGithub generates the definitions of the teams of the organisation
+// and of every repository granted to them.  The maintainers and reviewers
+// sub-teams which Sync manages are folded into their parent team, such that
+// synchronising the definitions results in no changes.
+func ImportFromGithub(ctx context.Context, ghApi *ghapi.GithubClient, org string, opts ImportOptions) (*Imported, error) {
+	ghTeams, err := ghApi.ListTeams(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(ghTeams, func(i, j int) bool {
+		return ghTeams[i].GetName() < ghTeams[j].GetName()
+	})
+
+	byName := make(map[string]*gh.Team, len(ghTeams))
+	for _, t := range ghTeams {
+		byName[t.GetName()] = t
+	}
+
+	imported := &Imported{}
+	skipped := make(map[string]bool)
+	repos := make(map[string]bool)
+
+	for _, t := range ghTeams {
+		if isSubTeam(t) {
+			continue
+		}
+
+		if skip, err := opts.skip(t); err != nil {
+			return nil, err
+		} else if skip {
+			imported.Skipped = append(imported.Skipped, t.GetName())
+			skipped[t.GetName()] = true
+			continue
+		}
+
+		it, err := importTeam(ctx, ghApi, org, t, byName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range it.Team.Repositories {
+			repos[r.Name] = true
+		}
+
+		imported.Teams = append(imported.Teams, it)
+	}
+
+	for _, it := range imported.Teams {
+		if skipped[it.Team.Parent] {
+			it.TODOs = append(it.TODOs, fmt.Sprintf("the parent team %s was skipped and is not defined", it.Team.Parent))
+		}
+	}
+
+	var names []string
+	for name := range repos {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		imported.Repos = append(imported.Repos, importRepo(name))
+	}
+
+	return imported, nil
+}
+
+// skip returns whether the team is excluded from the import.
+func (opts ImportOptions) skip(t *gh.Team) (bool, error) {
+	if opts.SkipSecret && t.GetPrivacy() == string(TeamSecret) {
+		return true, nil
+	}
+
+	for _, pattern := range opts.Exclude {
+		ok, err := path.Match(pattern, t.GetName())
+		if err != nil {
+			return false, fmt.Errorf("invalid exclude pattern '%s': %w", pattern, err)
+		}
+
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// subTeamName returns the name of the sub-team of the team which Sync manages
+// for the provided role.
+func subTeamName(role user.UserRole, name string) string {
+	return fmt.Sprintf("%ss-%s", role, name)
+}
+
+// isSubTeam returns whether the team is the maintainers or reviewers sub-team
+// of its parent.
+func isSubTeam(t *gh.Team) bool {
+	if t.Parent == nil {
+		return false
+	}
+
+	for _, role := range []user.UserRole{user.Maintainer, user.Reviewer} {
+		if t.GetName() == subTeamName(role, t.Parent.GetName()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// importTeam generates the definition of the team from its members, its
+// sub-teams and its repositories.
+func importTeam(ctx context.Context, ghApi *ghapi.GithubClient, org string, t *gh.Team, byName map[string]*gh.Team) (*ImportedTeam, error) {
+	name := t.GetName()
+
+	it := &ImportedTeam{
+		Team: &Team{
+			Org:         org,
+			Name:        name,
+			Privacy:     TeamPrivacy(t.GetPrivacy()),
+			Description: t.GetDescription(),
+		},
+	}
+
+	if t.Parent != nil {
+		it.Team.Parent = t.Parent.GetName()
+	}
+
+	if name != t.GetSlug() {
+		it.TODOs = append(it.TODOs, fmt.Sprintf("the name of the team differs from its slug %s, rename the team such that both are equal", t.GetSlug()))
+	}
+
+	for _, prefix := range TeamTypes {
+		if strings.HasPrefix(name, string(prefix)+"-") {
+			it.TODOs = append(it.TODOs, fmt.Sprintf("the name of the team starts with its type '%s', which is removed when the definition is loaded, such that @%s/%s would be managed instead", prefix, org, strings.TrimPrefix(name, string(prefix)+"-")))
+			break
+		}
+	}
+
+	all, err := ghApi.ListTeamMembersByRole(ctx, org, t.GetSlug(), "all")
+	if err != nil {
+		return nil, err
+	}
+
+	withMaintainerRole, err := ghApi.ListTeamMembersByRole(ctx, org, t.GetSlug(), "maintainer")
+	if err != nil {
+		return nil, err
+	}
+
+	var maintainers, reviewers []string
+
+	if sub, ok := byName[subTeamName(user.Maintainer, name)]; ok && isSubTeam(sub) {
+		if maintainers, err = ghApi.ListTeamMembersByRole(ctx, org, sub.GetSlug(), "all"); err != nil {
+			return nil, err
+		}
+
+		for _, login := range utils.Difference(withMaintainerRole, maintainers) {
+			it.TODOs = append(it.TODOs, fmt.Sprintf("@%s has the maintainer role but is not a member of %s and is listed as a member", login, sub.GetName()))
+		}
+	} else {
+		maintainers = withMaintainerRole
+
+		if len(maintainers) > 0 {
+			it.TODOs = append(it.TODOs, fmt.Sprintf("the maintainers were derived from their role, synchronising will create the sub-team %s", subTeamName(user.Maintainer, name)))
+		}
+	}
+
+	if sub, ok := byName[subTeamName(user.Reviewer, name)]; ok && isSubTeam(sub) {
+		if reviewers, err = ghApi.ListTeamMembersByRole(ctx, org, sub.GetSlug(), "all"); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, login := range utils.Intersect(maintainers, reviewers) {
+		it.TODOs = append(it.TODOs, fmt.Sprintf("@%s is both a maintainer and a reviewer, keep them in only one of the lists", login))
+	}
+
+	for _, login := range utils.Difference(append(append([]string{}, maintainers...), reviewers...), all) {
+		it.TODOs = append(it.TODOs, fmt.Sprintf("@%s is a member of a sub-team but not of the team itself, synchronising will add them", login))
+	}
+
+	for _, login := range maintainers {
+		it.Team.Maintainers = append(it.Team.Maintainers, user.User{Github: login})
+	}
+
+	for _, login := range reviewers {
+		it.Team.Reviewers = append(it.Team.Reviewers, user.User{Github: login})
+	}
+
+	for _, login := range utils.Difference(utils.Difference(all, maintainers), reviewers) {
+		it.Team.Members = append(it.Team.Members, user.User{Github: login})
+	}
+
+	repos, err := ghApi.ListTeamRepos(ctx, org, t.GetSlug())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range repos {
+		it.Team.Repositories = append(it.Team.Repositories, repo.Repository{
+			Name:            r.Name,
+			PermissionLevel: repo.RepoPermissionLevel(r.Permission),
+		})
+	}
+
+	return it, nil
+}
+
+// importRepo generates the definition of the repository with the provided
+// name such that its full name is preserved when the definition is loaded.
+func importRepo(name string) *ImportedRepo {
+	for _, candidate := range []repo.Repository{
+		{Name: name},
+		{Name: name, Type: repo.RepoTypeMisc},
+	} {
+		loaded := candidate
+		if loaded.Fullname() == name {
+			return &ImportedRepo{Repo: &candidate}
+		}
+	}
+
+	return &ImportedRepo{
+		Repo: &repo.Repository{Name: name, Type: repo.RepoTypeMisc},
+		TODOs: []string{
+			"the name of the repository starts with a type which is removed when the definition is loaded, adjust the name and type such that it refers to " + name,
+		},
+	}
+}
+
+// teamDocument is the definition of a team as it is written to a file, which
+// omits the fields that are derived when the definition is loaded.
+type teamDocument struct {
+	Name         string            `yaml:"name"`
+	Privacy      TeamPrivacy       `yaml:"privacy,omitempty"`
+	Parent       string            `yaml:"parent,omitempty"`
+	Description  string            `yaml:"description,omitempty"`
+	Maintainers  []user.User       `yaml:"maintainers,omitempty"`
+	Reviewers    []user.User       `yaml:"reviewers,omitempty"`
+	Members      []user.User       `yaml:"members,omitempty"`
+	Repositories []repo.Repository `yaml:"repos,omitempty"`
+}
+
+// marshalWithTODOs returns the YAML document of the value preceded by a
+// comment for each TODO.
+func marshalWithTODOs(v any, todos []string) ([]byte, error) {
+	doc, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	for _, todo := range todos {
+		fmt.Fprintf(&b, "# TODO: %s\n", todo)
+	}
+
+	b.Write(doc)
+
+	return []byte(b.String()), nil
+}
+
+// ImportedFile is a generated definition and the path it is written to.
+type ImportedFile struct {
+	Path    string
+	Content []byte
+}
+
+// Files returns the definitions of the import with one file per team in
+// teamsDir and one file per repository in reposDir.
+func (i *Imported) Files(teamsDir, reposDir string) ([]ImportedFile, error) {
+	var files []ImportedFile
+
+	for _, it := range i.Teams {
+		doc, err := marshalWithTODOs(teamDocument{
+			Name:         it.Team.Name,
+			Privacy:      it.Team.Privacy,
+			Parent:       it.Team.Parent,
+			Description:  it.Team.Description,
+			Maintainers:  it.Team.Maintainers,
+			Reviewers:    it.Team.Reviewers,
+			Members:      it.Team.Members,
+			Repositories: it.Team.Repositories,
+		}, it.TODOs)
+		if err != nil {
+			return nil, fmt.Errorf("could not marshal team %s: %w", it.Team.Name, err)
+		}
+
+		files = append(files, ImportedFile{
+			Path:    filepath.Join(teamsDir, it.Team.Name+".yaml"),
+			Content: doc,
+		})
+	}
+
+	for _, ir := range i.Repos {
+		doc, err := marshalWithTODOs(ir.Repo, ir.TODOs)
+		if err != nil {
+			return nil, fmt.Errorf("could not marshal repository %s: %w", ir.Repo.Name, err)
+		}
+
+		files = append(files, ImportedFile{
+			Path:    filepath.Join(reposDir, ir.Repo.Name+".yaml"),
+			Content: doc,
+		})
+	}
+
+	return files, nil
+}
+
+// WriteImportedFiles writes the files of an import.  Unless overwrite is set,
+// no file is written at all if any of them already exists.
+func WriteImportedFiles(files []ImportedFile, overwrite bool) error {
+	// Check every file before writing any such that an import is either
+	// written completely or not at all.
+	if !overwrite {
+		for _, file := range files {
+			if _, err := os.Stat(file.Path); err == nil {
+				return fmt.Errorf("refusing to overwrite existing file '%s'", file.Path)
+			}
+		}
+	}
+
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0o755); err != nil {
+			return fmt.Errorf("could not create directory: %w", err)
+		}
+
+		if err := os.WriteFile(file.Path, file.Content, 0o644); err != nil {
+			return fmt.Errorf("could not write file: %w", err)
+		}
+	}
+
+	return nil
+}
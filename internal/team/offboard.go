@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"github.com/unikraft/governance/internal/yamledit"
+)
+
+// RemoveUser removes every entry of the user with the provided GitHub login,
+// e.g. from the maintainers, reviewers, members and never_assign lists, from
+// the team definition whilst leaving the rest of the document untouched.  It
+// returns the resulting document and the lists which the user was removed
+// from.
+func RemoveUser(src []byte, login string) ([]byte, []string) {
+	found := yamledit.FindScalarsFold(src, "github", login)
+
+	var sections []string
+	for _, line := range found {
+		if section := yamledit.ParentKey(src, line); section != "" {
+			sections = append(sections, section)
+		}
+	}
+
+	// Edit from the bottom up since removing an entry shifts all subsequent
+	// lines.
+	for i := len(found) - 1; i >= 0; i-- {
+		src = yamledit.RemoveItem(src, found[i])
+	}
+
+	return src, sections
+}
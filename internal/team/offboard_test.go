@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestRemoveUser(t *testing.T) {
+	src := []byte(`name: maintainers-boot
+maintainers:
+  - github: jane
+  - github: bob
+members:
+  - name: Jane Doe
+    github: JANE
+`)
+
+	got, sections := RemoveUser(src, "jane")
+
+	if want := []string{"maintainers", "members"}; !reflect.DeepEqual(sections, want) {
+		t.Errorf("RemoveUser() sections = %v, want %v", sections, want)
+	}
+
+	var team Team
+	if err := yaml.Unmarshal(got, &team); err != nil {
+		t.Fatalf("RemoveUser() produced invalid YAML: %v\n%s", err, got)
+	}
+
+	if len(team.Maintainers) != 1 || team.Maintainers[0].Github != "bob" || len(team.Members) != 0 {
+		t.Errorf("RemoveUser() = %+v", team)
+	}
+
+	if _, sections := RemoveUser(src, "alice"); len(sections) != 0 {
+		t.Errorf("RemoveUser() of unknown user removed from %v", sections)
+	}
+}
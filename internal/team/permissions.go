@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/repo"
+)
+
+// RepoGrant is the permission of a team on a repository.
+type RepoGrant struct {
+	Team       string `json:"team"`
+	Permission string `json:"permission"`
+
+	// Current is the permission which the team has on the repository before
+	// the grant, if any.
+	Current string `json:"current,omitempty"`
+}
+
+// RepoPermissionChange is the set of changes necessary to bring the
+// permissions of teams on a repository in line with the definitions.
+type RepoPermissionChange struct {
+	Org  string `json:"org"`
+	Repo string `json:"repo"`
+
+	// Grant are the teams which do not have the declared permission on the
+	// repository yet.
+	Grant []RepoGrant `json:"grant,omitempty"`
+
+	// Undeclared are the teams which have access to the repository although
+	// their definitions do not reference it, or which are not defined at all.
+	Undeclared []RepoGrant `json:"undeclared,omitempty"`
+}
+
+// Empty returns whether the permissions on the repository are already as
+// declared.
+func (c *RepoPermissionChange) Empty() bool {
+	return len(c.Grant) == 0 && len(c.Undeclared) == 0
+}
+
+// referencesRepo returns whether the reference of a team to a repository by
+// its name refers to the provided repository definition.
+func referencesRepo(ref string, r *repo.Repository) bool {
+	return strings.EqualFold(ref, r.Fullname()) || r.NameEquals(ref)
+}
+
+// DeclaredRepoPermissions returns the permission which each team referencing
+// the repository declares on it, sorted by team.  The permission of the
+// reference takes precedence over the one of the repository definition.  If
+// neither is set, the team is declared with an empty permission such that its
+// existing access is left untouched.  The maintainers and reviewers sub-teams
+// of a team are granted the same permission as the team if they are
+// populated.
+func DeclaredRepoPermissions(teams []*Team, r *repo.Repository) []RepoGrant {
+	declared := make(map[string]string)
+
+	for _, t := range teams {
+		for _, ref := range t.Repositories {
+			if !referencesRepo(ref.Name, r) {
+				continue
+			}
+
+			permission := string(ref.PermissionLevel)
+			if permission == "" {
+				permission = string(r.PermissionLevel)
+			}
+
+			t.prepare()
+			maintainers, reviewers, _ := t.usernames()
+
+			names := []string{t.Name}
+			if len(maintainers) > 0 {
+				names = append(names, t.maintainersTeamName())
+			}
+			if len(reviewers) > 0 {
+				names = append(names, t.reviewersTeamName())
+			}
+
+			for _, name := range names {
+				declared[strings.ToLower(name)] = permission
+			}
+		}
+	}
+
+	grants := make([]RepoGrant, 0, len(declared))
+	for name, permission := range declared {
+		grants = append(grants, RepoGrant{Team: name, Permission: permission})
+	}
+
+	sort.Slice(grants, func(i, j int) bool {
+		return grants[i].Team < grants[j].Team
+	})
+
+	return grants
+}
+
+// PlanRepoPermissions determines the changes necessary to bring the
+// permissions of the teams on the repository in line with the permissions
+// declared by the teams, given the teams which currently have access to it.
+func PlanRepoPermissions(org string, r *repo.Repository, teams []*Team, existing []ghapi.RepositoryTeam) *RepoPermissionChange {
+	change := &RepoPermissionChange{
+		Org:  org,
+		Repo: r.Fullname(),
+	}
+
+	current := make(map[string]string, len(existing))
+	for _, e := range existing {
+		current[strings.ToLower(e.Team)] = e.Permission
+	}
+
+	declared := make(map[string]struct{})
+
+	for _, grant := range DeclaredRepoPermissions(teams, r) {
+		declared[grant.Team] = struct{}{}
+
+		if grant.Permission == "" {
+			continue
+		}
+
+		if have, ok := current[grant.Team]; ok && strings.EqualFold(have, grant.Permission) {
+			continue
+		}
+
+		grant.Current = current[grant.Team]
+		change.Grant = append(change.Grant, grant)
+	}
+
+	for _, e := range existing {
+		if _, ok := declared[strings.ToLower(e.Team)]; ok {
+			continue
+		}
+
+		change.Undeclared = append(change.Undeclared, RepoGrant{
+			Team:       e.Team,
+			Permission: e.Permission,
+		})
+	}
+
+	return change
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/repo"
+	"github.com/unikraft/governance/internal/user"
+)
+
+func TestPlanRepoPermissions(t *testing.T) {
+	teams := []*Team{
+		{
+			Name:         "arch",
+			Maintainers:  []user.User{{Github: "alice"}},
+			Repositories: []repo.Repository{{Name: "unikraft", PermissionLevel: repo.RepoPermissionMaintain}},
+		},
+		{
+			Name:         "net",
+			Reviewers:    []user.User{{Github: "bob"}},
+			Repositories: []repo.Repository{{Name: "lib-lwip"}, {Name: "Unikraft"}},
+		},
+		{
+			Name:         "docs",
+			Repositories: []repo.Repository{{Name: "docs"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		repo     *repo.Repository
+		existing []ghapi.RepositoryTeam
+		want     *RepoPermissionChange
+	}{
+		{
+			name: "missing and outdated grants",
+			repo: &repo.Repository{Name: "unikraft", Type: repo.RepoTypeCore, PermissionLevel: repo.RepoPermissionWrite},
+			existing: []ghapi.RepositoryTeam{
+				{Team: "arch", Permission: "read"},
+				{Team: "net", Permission: "write"},
+				{Team: "old", Permission: "admin"},
+			},
+			want: &RepoPermissionChange{
+				Org:  "unikraft",
+				Repo: "unikraft",
+				Grant: []RepoGrant{
+					{Team: "arch", Permission: "maintain", Current: "read"},
+					{Team: "maintainers-arch", Permission: "maintain"},
+					{Team: "reviewers-net", Permission: "write"},
+				},
+				Undeclared: []RepoGrant{
+					{Team: "old", Permission: "admin"},
+				},
+			},
+		},
+		{
+			name: "type-prefixed reference",
+			repo: &repo.Repository{Name: "lwip", Type: repo.RepoTypeLib, PermissionLevel: repo.RepoPermissionTriage},
+			existing: []ghapi.RepositoryTeam{
+				{Team: "net", Permission: "triage"},
+				{Team: "reviewers-net", Permission: "triage"},
+			},
+			want: &RepoPermissionChange{
+				Org:  "unikraft",
+				Repo: "lib-lwip",
+			},
+		},
+		{
+			name: "undeclared permission",
+			repo: &repo.Repository{Name: "docs", Type: repo.RepoTypeMisc},
+			existing: []ghapi.RepositoryTeam{
+				{Team: "docs", Permission: "admin"},
+			},
+			want: &RepoPermissionChange{
+				Org:  "unikraft",
+				Repo: "docs",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PlanRepoPermissions("unikraft", tt.repo, teams, tt.existing)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlanRepoPermissions() = %+v, want %+v", got, tt.want)
+			}
+
+			if got.Empty() != (tt.want.Grant == nil && tt.want.Undeclared == nil) {
+				t.Errorf("Empty() = %v", got.Empty())
+			}
+		})
+	}
+}
+
+func TestDeclaredRepoPermissionsOfManyRepos(t *testing.T) {
+	teams := []*Team{
+		{
+			Name:        "sig-arch",
+			Maintainers: []user.User{{Github: "alice"}},
+			Reviewers:   []user.User{{Github: "bob"}},
+			Repositories: []repo.Repository{
+				{Name: "unikraft"},
+				{Name: "lib-lwip", PermissionLevel: repo.RepoPermissionTriage},
+			},
+		},
+	}
+
+	tests := []struct {
+		repo *repo.Repository
+		want []RepoGrant
+	}{
+		{
+			repo: &repo.Repository{Name: "unikraft", Type: repo.RepoTypeCore, PermissionLevel: repo.RepoPermissionWrite},
+			want: []RepoGrant{
+				{Team: "maintainers-arch", Permission: "write"},
+				{Team: "reviewers-arch", Permission: "write"},
+				{Team: "sig-arch", Permission: "write"},
+			},
+		},
+		{
+			repo: &repo.Repository{Name: "lwip", Type: repo.RepoTypeLib},
+			want: []RepoGrant{
+				{Team: "maintainers-arch", Permission: "triage"},
+				{Team: "reviewers-arch", Permission: "triage"},
+				{Team: "sig-arch", Permission: "triage"},
+			},
+		},
+	}
+
+	// The sub-teams must keep their names from the second repository onwards,
+	// once the type of the team has been determined.
+	for _, tt := range tests {
+		t.Run(tt.repo.Fullname(), func(t *testing.T) {
+			if got := DeclaredRepoPermissions(teams, tt.repo); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DeclaredRepoPermissions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
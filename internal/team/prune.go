@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	gh "github.com/google/go-github/v63/github"
+
+	"github.com/unikraft/governance/internal/user"
+)
+
+// subTeamOf returns the short name of the team whose maintainers or reviewers
+// sub-team has the provided slug, e.g. "arch" for "maintainers-arch".
+func subTeamOf(slug string) (string, bool) {
+	for _, role := range []user.UserRole{user.Maintainer, user.Reviewer} {
+		if short, ok := strings.CutPrefix(strings.ToLower(slug), fmt.Sprintf("%ss-", role)); ok && short != "" {
+			return short, true
+		}
+	}
+
+	return "", false
+}
+
+// StaleTeams returns the slugs of the existing teams which follow the naming
+// scheme of the governance but are no longer defined by any of the teams, in
+// alphabetical order.  Only the maintainers and reviewers sub-teams, e.g.
+// "maintainers-arch", and the team which is the parent of such a sub-team and
+// carries its name, e.g. "arch", follow the naming scheme.  Any other team is
+// never considered stale.
+func StaleTeams(teams []*Team, existing []*gh.Team) []string {
+	defined := make(map[string]struct{})
+	for _, t := range teams {
+		for _, name := range t.Names() {
+			defined[strings.ToLower(name)] = struct{}{}
+		}
+	}
+
+	managed := make(map[string]struct{})
+	for _, t := range existing {
+		short, ok := subTeamOf(t.GetSlug())
+		if !ok {
+			continue
+		}
+
+		managed[strings.ToLower(t.GetSlug())] = struct{}{}
+
+		if parent := t.GetParent().GetSlug(); strings.EqualFold(parent, short) {
+			managed[strings.ToLower(parent)] = struct{}{}
+		}
+	}
+
+	var stale []string
+
+	for slug := range managed {
+		if _, ok := defined[slug]; !ok {
+			stale = append(stale, slug)
+		}
+	}
+
+	sort.Strings(stale)
+
+	return stale
+}
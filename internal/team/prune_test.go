@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"reflect"
+	"testing"
+
+	gh "github.com/google/go-github/v63/github"
+)
+
+func TestStaleTeams(t *testing.T) {
+	teams := []*Team{
+		{Name: "arch"},
+		{Name: "net"},
+	}
+
+	existing := func(slugs ...string) []*gh.Team {
+		var teams []*gh.Team
+		for _, slug := range slugs {
+			teams = append(teams, &gh.Team{Slug: gh.String(slug)})
+		}
+		return teams
+	}
+
+	child := func(slug, parent string) *gh.Team {
+		return &gh.Team{
+			Slug:   gh.String(slug),
+			Parent: &gh.Team{Slug: gh.String(parent)},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		existing []*gh.Team
+		want     []string
+	}{
+		{
+			name: "defined",
+			existing: append(existing("arch", "net", "Reviewers-Net"),
+				child("maintainers-arch", "arch"),
+				child("reviewers-arch", "arch"),
+			),
+		},
+		{
+			name: "dissolved team and its sub-teams",
+			existing: append(existing("arch", "old"),
+				child("maintainers-old", "old"),
+				child("reviewers-old", "old"),
+			),
+			want: []string{"maintainers-old", "old", "reviewers-old"},
+		},
+		{
+			name:     "sub-team of a differently named parent",
+			existing: append(existing("core"), child("maintainers-old", "core")),
+			want:     []string{"maintainers-old"},
+		},
+		{
+			name:     "not following the naming scheme",
+			existing: existing("old", "maintainers", "reviewers", "security", "sig-old", "maintainers-"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StaleTeams(teams, tt.existing); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StaleTeams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/unikraft/governance/internal/text"
+)
+
+// UnknownRepository is a repository referenced by a team which does not exist
+// in the organisation of the team.
+type UnknownRepository struct {
+	File       string `json:"file"`
+	Team       string `json:"team"`
+	Repository string `json:"repository"`
+
+	// Suggestion is the name of the existing repository which is nearest to
+	// the unknown one, if any.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// String returns a human-readable description of the unknown reference.
+func (u UnknownRepository) String() string {
+	msg := fmt.Sprintf("%s: team %s references unknown repository %s", u.File, u.Team, u.Repository)
+	if u.Suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %s?)", u.Suggestion)
+	}
+
+	return msg
+}
+
+// UnknownRepositories returns every repository referenced by the teams which
+// is not one of the provided known repositories.  GitHub silently ignores such
+// references when granting a team access, so a typo would otherwise go
+// unnoticed.
+func UnknownRepositories(teams []*Team, known []string) []UnknownRepository {
+	exists := make(map[string]struct{}, len(known))
+	for _, name := range known {
+		exists[strings.ToLower(name)] = struct{}{}
+	}
+
+	var unknown []UnknownRepository
+
+	for _, t := range teams {
+		for _, repo := range t.Repositories {
+			if _, ok := exists[strings.ToLower(repo.Name)]; ok {
+				continue
+			}
+
+			unknown = append(unknown, UnknownRepository{
+				File:       t.File(),
+				Team:       t.Name,
+				Repository: repo.Name,
+				Suggestion: text.Nearest(repo.Name, known),
+			})
+		}
+	}
+
+	return unknown
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/unikraft/governance/internal/repo"
+)
+
+func TestUnknownRepositories(t *testing.T) {
+	teams := []*Team{
+		{
+			Name:         "net",
+			file:         "teams/sig-net.yaml",
+			Repositories: []repo.Repository{{Name: "lib-lwpi"}, {Name: "Unikraft"}},
+		},
+		{
+			Name:         "arch",
+			file:         "teams/sig-arch.yaml",
+			Repositories: []repo.Repository{{Name: "kraftkit"}},
+		},
+	}
+
+	got := UnknownRepositories(teams, []string{"unikraft", "lib-lwip", "lib-musl"})
+	want := []UnknownRepository{
+		{File: "teams/sig-net.yaml", Team: "net", Repository: "lib-lwpi", Suggestion: "lib-lwip"},
+		{File: "teams/sig-arch.yaml", Team: "arch", Repository: "kraftkit"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnknownRepositories() = %+v, want %+v", got, want)
+	}
+
+	if s := got[0].String(); s != "teams/sig-net.yaml: team net references unknown repository lib-lwpi (did you mean lib-lwip?)" {
+		t.Errorf("String() = %q", s)
+	}
+}
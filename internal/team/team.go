@@ -75,7 +75,45 @@ type Team struct {
 
 	ghApi     *ghapi.GithubClient
 	hasSynced bool
+	prepared  bool
 	shortName string
+	file      string
+
+	// deprecatedRepositories is set if the definition lists its repositories
+	// under the deprecated "repositories" key rather than "repos".
+	deprecatedRepositories bool
+}
+
+// UnmarshalYAML decodes the definition of a team whose repositories are listed
+// either under "repos" or under the deprecated "repositories" key.  If both
+// are set, "repos" takes precedence.
+func (t *Team) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Team
+	if err := unmarshal((*plain)(t)); err != nil {
+		return err
+	}
+
+	var deprecated struct {
+		Repositories []repo.Repository `yaml:"repositories"`
+	}
+	if err := unmarshal(&deprecated); err != nil {
+		return err
+	}
+
+	if deprecated.Repositories != nil {
+		t.deprecatedRepositories = true
+
+		if t.Repositories == nil {
+			t.Repositories = deprecated.Repositories
+		}
+	}
+
+	return nil
+}
+
+// File returns the path of the file which defines the team, if any.
+func (t *Team) File() string {
+	return t.file
 }
 
 func (r *Team) Fullname() string {
@@ -105,16 +143,17 @@ func (r *Team) Fullname() string {
 	return r.fullname
 }
 
-func (t *Team) Sync(ctx context.Context) error {
-	if t.hasSynced {
-		return nil
+// prepare determines the type of the team, if unset, and its name without the
+// type prefix.  Only the first call has an effect, since the type which it
+// determines must not change the name on later calls.
+func (t *Team) prepare() {
+	if t.prepared {
+		return
 	}
 
+	t.prepared = true
 	t.shortName = t.Name
 
-	var err error
-	t.hasSynced = false
-
 	// Determine the team type if unset
 	if t.Type == "" {
 		for _, prefix := range []TeamType{SIGTeam, MaintainersTeam, ReviewersTeam} {
@@ -130,6 +169,91 @@ func (t *Team) Sync(ctx context.Context) error {
 			t.Type = MiscTeam
 		}
 	}
+}
+
+// usernames returns the GitHub usernames of the maintainers, the reviewers and
+// all members of the team, which include its maintainers and reviewers.
+func (t *Team) usernames() (maintainers, reviewers, members []string) {
+	for _, maintainer := range t.Maintainers {
+		maintainers = append(maintainers, maintainer.Github)
+		members = append(members, maintainer.Github)
+	}
+
+	for _, reviewer := range t.Reviewers {
+		reviewers = append(reviewers, reviewer.Github)
+		members = append(members, reviewer.Github)
+	}
+
+	for _, member := range t.Members {
+		members = append(members, member.Github)
+	}
+
+	return maintainers, reviewers, members
+}
+
+// maintainersTeamName returns the name of the sub-team of the maintainers.
+func (t *Team) maintainersTeamName() string {
+	return fmt.Sprintf("%ss-%s", string(user.Maintainer), t.shortName)
+}
+
+// reviewersTeamName returns the name of the sub-team of the reviewers.
+func (t *Team) reviewersTeamName() string {
+	return fmt.Sprintf("%ss-%s", string(user.Reviewer), t.shortName)
+}
+
+// Names returns the names of the team and of its maintainers and reviewers
+// sub-teams, regardless of whether the sub-teams are populated.
+func (t *Team) Names() []string {
+	t.prepare()
+
+	return []string{t.Name, t.maintainersTeamName(), t.reviewersTeamName()}
+}
+
+// Plan determines the changes to the membership of the team and of its
+// maintainers and reviewers sub-teams which Sync would perform, without
+// performing them.
+func (t *Team) Plan(ctx context.Context) ([]*ghapi.TeamMembershipChange, error) {
+	t.prepare()
+
+	maintainers, reviewers, members := t.usernames()
+
+	change, err := t.ghApi.PlanTeamMembers(ctx, t.Org, t.Name, members)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []*ghapi.TeamMembershipChange{change}
+
+	if len(maintainers) > 0 {
+		change, err := t.ghApi.PlanTeamMembers(ctx, t.Org, t.maintainersTeamName(), maintainers)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, change)
+	}
+
+	if len(reviewers) > 0 {
+		change, err := t.ghApi.PlanTeamMembers(ctx, t.Org, t.reviewersTeamName(), reviewers)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+func (t *Team) Sync(ctx context.Context) error {
+	if t.hasSynced {
+		return nil
+	}
+
+	t.prepare()
+
+	var err error
+	t.hasSynced = false
 
 	var githubTeam *gh.Team
 	var parentGithubTeam *gh.Team
@@ -141,7 +265,7 @@ func (t *Team) Sync(ctx context.Context) error {
 			// and up-to-date.
 			err = t.ParentTeam.Sync(ctx)
 			if err != nil {
-				return fmt.Errorf("could not synchronize parent: %s", err)
+				return fmt.Errorf("could not synchronize parent: %w", err)
 			}
 		}
 
@@ -153,34 +277,24 @@ func (t *Team) Sync(ctx context.Context) error {
 
 	log.G(ctx).Infof("synchronising @%s/%s...", t.Org, t.Name)
 
-	var maintainers []string
-	var reviewers []string
-	var members []string
+	maintainers, reviewers, members := t.usernames()
 	var repos []string
 
-	for _, maintainer := range t.Maintainers {
-		maintainers = append(maintainers, maintainer.Github)
-		members = append(members, maintainer.Github)
-	}
-
-	for _, reviewer := range t.Reviewers {
-		reviewers = append(reviewers, reviewer.Github)
-		members = append(members, reviewer.Github)
-	}
-
-	for _, member := range t.Members {
-		members = append(members, member.Github)
-	}
-
 	for _, repo := range t.Repositories {
 		repos = append(repos, repo.Name)
 	}
 
+	description, err := t.RenderDescription()
+	if err != nil {
+		return err
+	}
+
 	// Github's Go API is a bit stupid... There is a type mis-match in their
 	// Golang SDK when it comes to the "privacy" attribute (either 'closed' or
 	// 'private') and so we must pass a pointer to a string, rather than the
-	// actual string.
-	p := string(t.Privacy)
+	// actual string.  An unset privacy is omitted such that the existing one
+	// is preserved.
+	p := ghapi.OptionalString(string(t.Privacy))
 	var parentTeamID int64
 
 	if parentGithubTeam != nil {
@@ -195,14 +309,14 @@ func (t *Team) Sync(ctx context.Context) error {
 		ctx,
 		t.Org,
 		t.Name,
-		t.Description,
+		ghapi.OptionalString(description),
 		parentTeamID,
-		&p,
+		p,
 		maintainers,
 		repos,
 	)
 	if err != nil {
-		return fmt.Errorf("could not create or update team: %s", err)
+		return fmt.Errorf("could not create or update team: %w", err)
 	}
 
 	log.G(ctx).Infof("synchronising team members...")
@@ -214,11 +328,11 @@ func (t *Team) Sync(ctx context.Context) error {
 		members,
 	)
 	if err != nil {
-		return fmt.Errorf("could not synchronise team members: %s", err)
+		return fmt.Errorf("could not synchronise team members: %w", err)
 	}
 
 	if len(maintainers) > 0 {
-		maintainersTeamName := fmt.Sprintf("%ss-%s", string(user.Maintainer), t.shortName)
+		maintainersTeamName := t.maintainersTeamName()
 
 		log.G(ctx).Infof("Synchronising @%s/%s...", t.Org, maintainersTeamName)
 
@@ -228,14 +342,14 @@ func (t *Team) Sync(ctx context.Context) error {
 			ctx,
 			t.Org,
 			maintainersTeamName,
-			fmt.Sprintf("%s maintainers", t.Name),
+			ghapi.OptionalString(fmt.Sprintf("%s maintainers", t.Name)),
 			*githubTeam.ID,
-			&p,
+			p,
 			maintainers,
 			repos,
 		)
 		if err != nil {
-			return fmt.Errorf("could not create or update team: %s", err)
+			return fmt.Errorf("could not create or update team: %w", err)
 		}
 
 		// Add and remove these usernames from the second-level `maintainers-` group
@@ -248,12 +362,12 @@ func (t *Team) Sync(ctx context.Context) error {
 			maintainers,
 		)
 		if err != nil {
-			return fmt.Errorf("could not synchronize team members: %s", err)
+			return fmt.Errorf("could not synchronize team members: %w", err)
 		}
 	}
 
 	if len(reviewers) > 0 {
-		reviewersTeamName := fmt.Sprintf("%ss-%s", string(user.Reviewer), t.shortName)
+		reviewersTeamName := t.reviewersTeamName()
 		log.G(ctx).Infof("Synchronising @%s/%s...", t.Org, reviewersTeamName)
 
 		// Create or update a sub-team with list of reviewers
@@ -262,14 +376,14 @@ func (t *Team) Sync(ctx context.Context) error {
 			ctx,
 			t.Org,
 			reviewersTeamName,
-			fmt.Sprintf("%s reviewers", t.Name),
+			ghapi.OptionalString(fmt.Sprintf("%s reviewers", t.Name)),
 			*githubTeam.ID,
-			&p,
+			p,
 			nil,
 			repos,
 		)
 		if err != nil {
-			return fmt.Errorf("could not create or update team: %s", err)
+			return fmt.Errorf("could not create or update team: %w", err)
 		}
 
 		// Add and remove these usernames from the second-level `reviewers-` group
@@ -282,7 +396,7 @@ func (t *Team) Sync(ctx context.Context) error {
 			reviewers,
 		)
 		if err != nil {
-			return fmt.Errorf("could not synchronize team members: %s", err)
+			return fmt.Errorf("could not synchronize team members: %w", err)
 		}
 	}
 
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		name string
+		team Team
+		want []string
+	}{
+		{
+			name: "type from prefix",
+			team: Team{Name: "sig-arch"},
+			want: []string{"sig-arch", "maintainers-arch", "reviewers-arch"},
+		},
+		{
+			name: "explicit type",
+			team: Team{Name: "arch", Type: SIGTeam},
+			want: []string{"arch", "maintainers-arch", "reviewers-arch"},
+		},
+		{
+			name: "misc",
+			team: Team{Name: "security"},
+			want: []string{"security", "maintainers-security", "reviewers-security"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The type determined by the first call must not change the names
+			// returned by later calls, e.g. of Plan followed by Names.
+			for i := 0; i < 2; i++ {
+				if got := tt.team.Names(); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("call %d: Names() = %v, want %v", i+1, got, tt.want)
+				}
+			}
+		})
+	}
+}
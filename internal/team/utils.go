@@ -6,8 +6,12 @@
 package team
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
 	"path"
 	"strings"
 
@@ -45,15 +49,62 @@ func NewTeamFromYAML(ghApi *ghapi.GithubClient, githubOrg, teamsFile string) (*T
 		return nil, fmt.Errorf("could not open yaml file: %s", err)
 	}
 
-	team := &Team{
-		ghApi: ghApi,
-	}
+	team := &Team{}
 
 	err = yaml.Unmarshal(yamlFile, team)
 	if err != nil {
 		return nil, fmt.Errorf("could not unmarshal yaml file: %s", err)
 	}
 
+	return newTeam(ghApi, githubOrg, team, teamsFile)
+}
+
+// NewListOfTeamsFromYAML returns every team defined in the provided file,
+// which may contain multiple YAML documents separated by "---".  Empty
+// documents are skipped.
+func NewListOfTeamsFromYAML(ghApi *ghapi.GithubClient, githubOrg, teamsFile string) ([]*Team, error) {
+	yamlFile, err := ioutil.ReadFile(teamsFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not open yaml file: %s", err)
+	}
+
+	var teams []*Team
+
+	decoder := yaml.NewDecoder(bytes.NewReader(yamlFile))
+	for {
+		var team *Team
+		if err := decoder.Decode(&team); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("could not unmarshal yaml file: %s", err)
+		}
+
+		if team == nil {
+			continue
+		}
+
+		t, err := newTeam(ghApi, githubOrg, team, teamsFile)
+		if err != nil {
+			return nil, err
+		}
+
+		teams = append(teams, t)
+	}
+
+	return teams, nil
+}
+
+// newTeam checks the sanity of a team decoded from the provided file and
+// normalises its name and type.  Teams which do not set their organisation
+// belong to the provided one.
+func newTeam(ghApi *ghapi.GithubClient, githubOrg string, team *Team, teamsFile string) (*Team, error) {
+	team.ghApi = ghApi
+	team.file = teamsFile
+
+	if team.Org == "" {
+		team.Org = githubOrg
+	}
+
 	// Let's perform a sanity check and check if we have at least the name of the
 	// team.
 	if team.Name == "" {
@@ -73,6 +124,18 @@ func NewTeamFromYAML(ghApi *ghapi.GithubClient, githubOrg, teamsFile string) (*T
 		}
 	}
 
+	if team.deprecatedRepositories {
+		log.Warnf("%s: team %s lists its repositories under the deprecated key 'repositories', use 'repos' instead", teamsFile, team.Name)
+	}
+
+	// Reject descriptions whose template is malformed before any team is
+	// synchronised.
+	if strings.Contains(team.Description, "{{") {
+		if _, err := team.parseDescription(); err != nil {
+			return nil, fmt.Errorf("invalid description of team %s: %w", team.Name, err)
+		}
+	}
+
 	// Now let's check if all maintainers, reviewers and members have at least
 	// their Github username provided.
 	users := append(team.Maintainers, team.Reviewers...)
@@ -86,14 +149,31 @@ func NewTeamFromYAML(ghApi *ghapi.GithubClient, githubOrg, teamsFile string) (*T
 	return team, nil
 }
 
+// NewListOfTeamsFromPath returns every team defined at the provided path,
+// which is either a directory with one or more files per team or a single file
+// with one YAML document per team.
 func NewListOfTeamsFromPath(ghApi *ghapi.GithubClient, githubOrg, teamsDir string) ([]*Team, error) {
 	teams := make([]*Team, 0)
 
-	files, err := ioutil.ReadDir(teamsDir)
+	fi, err := os.Stat(teamsDir)
 	if err != nil {
 		return nil, fmt.Errorf("could not read directory: %s", err)
 	}
 
+	var files []string
+	if fi.IsDir() {
+		entries, err := ioutil.ReadDir(teamsDir)
+		if err != nil {
+			return nil, fmt.Errorf("could not read directory: %s", err)
+		}
+
+		for _, entry := range entries {
+			files = append(files, path.Join(teamsDir, entry.Name()))
+		}
+	} else {
+		files = append(files, teamsDir)
+	}
+
 	// To solve a potential dependency problem where teams are dependent on teams
 	// which do not exist, we are going to populate a list "processed" teams first
 	// and then check if any of the teams has a parent which does not exist in the
@@ -101,16 +181,16 @@ func NewListOfTeamsFromPath(ghApi *ghapi.GithubClient, githubOrg, teamsDir strin
 
 	// Iterate through all files and populate a list of known teams.
 	for _, file := range files {
-		t, err := NewTeamFromYAML(
+		t, err := NewListOfTeamsFromYAML(
 			ghApi,
 			githubOrg,
-			path.Join(teamsDir, file.Name()),
+			file,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("could not parse teams file: %s", err)
 		}
 
-		teams = append(teams, t)
+		teams = append(teams, t...)
 	}
 
 	// Now iterate through known teams and match parents
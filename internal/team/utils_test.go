@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package team
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const (
+	testTeamArch = `name: sig-arch
+maintainers:
+  - name: Jane Doe
+    github: jane
+`
+	testTeamArm = `name: sig-arch-arm
+parent: sig-arch
+reviewers:
+  - name: John Doe
+    github: john
+`
+)
+
+func TestNewListOfTeamsFromPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "directory",
+			files: map[string]string{
+				"sig-arch.yaml":     testTeamArch,
+				"sig-arch-arm.yaml": testTeamArm,
+			},
+			want: []string{"arch-arm", "arch"},
+		},
+		{
+			name: "multi-document file",
+			files: map[string]string{
+				"teams.yaml": "---\n" + testTeamArch + "---\n" + testTeamArm,
+			},
+			path: "teams.yaml",
+			want: []string{"arch", "arch-arm"},
+		},
+		{
+			name: "multi-document file with empty documents",
+			files: map[string]string{
+				"teams.yaml": testTeamArch + "---\n---\n" + testTeamArm + "---\n",
+			},
+			path: "teams.yaml",
+			want: []string{"arch", "arch-arm"},
+		},
+		{
+			name: "document without name",
+			files: map[string]string{
+				"teams.yaml": testTeamArch + "---\ndescription: nameless\n",
+			},
+			path:    "teams.yaml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			teams, err := NewListOfTeamsFromPath(nil, "unikraft", filepath.Join(dir, tt.path))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewListOfTeamsFromPath() expected error, got %d teams", len(teams))
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("NewListOfTeamsFromPath() unexpected error: %v", err)
+			}
+
+			var names []string
+			for _, team := range teams {
+				names = append(names, team.Name)
+
+				if team.Type != SIGTeam {
+					t.Errorf("team %s has type %q, want %q", team.Name, team.Type, SIGTeam)
+				}
+			}
+
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("NewListOfTeamsFromPath() = %v, want %v", names, tt.want)
+			}
+
+			arm := FindTeamByName("sig-arch-arm", teams)
+			if arm == nil || arm.ParentTeam == nil || arm.ParentTeam.Name != "arch" {
+				t.Errorf("expected parent of sig-arch-arm to be resolved to sig-arch")
+			}
+		})
+	}
+}
+
+func TestNewListOfTeamsFromYAMLRepositories(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		want           []string
+		wantDeprecated bool
+	}{
+		{
+			name:    "repos",
+			content: testTeamArch + "repos:\n  - name: unikraft\n",
+			want:    []string{"unikraft"},
+		},
+		{
+			name:           "repositories",
+			content:        testTeamArch + "repositories:\n  - name: unikraft\n  - name: lib-lwip\n",
+			want:           []string{"unikraft", "lib-lwip"},
+			wantDeprecated: true,
+		},
+		{
+			name:           "both prefer repos",
+			content:        testTeamArch + "repos:\n  - name: unikraft\nrepositories:\n  - name: lib-lwip\n",
+			want:           []string{"unikraft"},
+			wantDeprecated: true,
+		},
+		{
+			name:    "neither",
+			content: testTeamArch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "teams.yaml")
+			if err := os.WriteFile(file, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			teams, err := NewListOfTeamsFromYAML(nil, "unikraft", file)
+			if err != nil {
+				t.Fatalf("NewListOfTeamsFromYAML() unexpected error: %v", err)
+			}
+
+			if len(teams) != 1 {
+				t.Fatalf("NewListOfTeamsFromYAML() = %d teams, want 1", len(teams))
+			}
+
+			var repos []string
+			for _, r := range teams[0].Repositories {
+				repos = append(repos, r.Name)
+			}
+
+			if !reflect.DeepEqual(repos, tt.want) {
+				t.Errorf("Repositories = %v, want %v", repos, tt.want)
+			}
+
+			if teams[0].deprecatedRepositories != tt.wantDeprecated {
+				t.Errorf("deprecatedRepositories = %v, want %v", teams[0].deprecatedRepositories, tt.wantDeprecated)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package text
+
+import "strings"
+
+// Levenshtein returns the minimum number of single-character insertions,
+// deletions and substitutions necessary to turn a into b.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// Nearest returns the candidate which is closest to s, ignoring case, or an
+// empty string if no candidate is within half the length of s.
+func Nearest(s string, candidates []string) string {
+	nearest := ""
+	best := len([]rune(s))/2 + 1
+
+	for _, candidate := range candidates {
+		if d := Levenshtein(strings.ToLower(s), strings.ToLower(candidate)); d < best {
+			nearest = candidate
+			best = d
+		}
+	}
+
+	return nearest
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package text
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "", b: "abc", want: 3},
+		{a: "lib-lwip", b: "lib-lwip", want: 0},
+		{a: "lib-lwpi", b: "lib-lwip", want: 2},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "app-nginx", b: "app-ngnix", want: 2},
+	}
+
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	candidates := []string{"unikraft", "lib-lwip", "lib-musl", "app-nginx"}
+
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{s: "lib-lwpi", want: "lib-lwip"},
+		{s: "LIB-MUSL", want: "lib-musl"},
+		{s: "unikraf", want: "unikraft"},
+		{s: "kraftkit", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := Nearest(tt.s, candidates); got != tt.want {
+			t.Errorf("Nearest(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
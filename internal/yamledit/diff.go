@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package yamledit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+type op struct {
+	kind opKind
+	line string
+	// a and b are the (zero-indexed) line numbers in the old and the new
+	// document respectively.
+	a, b int
+}
+
+// diffOps computes the shortest edit script between the two lists of lines
+// via their longest common subsequence.
+func diffOps(a, b []string) []op {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []op
+	i, j := 0, 0
+
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, op{opEqual, a[i], i, j})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, op{opInsert, b[j], i, j})
+			j++
+		default:
+			ops = append(ops, op{opDelete, a[i], i, j})
+			i++
+		}
+	}
+
+	return ops
+}
+
+// diffLines splits the document into lines, disregarding the empty line
+// following the final line ending.
+func diffLines(src []byte) []string {
+	return lines([]byte(strings.TrimSuffix(string(src), "\n")))
+}
+
+// Diff returns a unified diff between the old and the new version of the
+// named document, or an empty string if they are identical.
+func Diff(name string, old, new []byte) string {
+	if string(old) == string(new) {
+		return ""
+	}
+
+	ops := diffOps(diffLines(old), diffLines(new))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", name, name)
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == opEqual {
+			i++
+			continue
+		}
+
+		// Extend the hunk for as long as changes are separated by no more than
+		// twice the context.
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+
+			next := end
+			for next < len(ops) && ops[next].kind == opEqual {
+				next++
+			}
+
+			if next == len(ops) || next-end > 2*diffContext {
+				break
+			}
+
+			end = next
+		}
+
+		stop := end + diffContext
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		var oldLen, newLen int
+		for _, o := range ops[start:stop] {
+			if o.kind != opInsert {
+				oldLen++
+			}
+			if o.kind != opDelete {
+				newLen++
+			}
+		}
+
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", ops[start].a+1, oldLen, ops[start].b+1, newLen)
+
+		for _, o := range ops[start:stop] {
+			b.WriteByte(byte(o.kind))
+			b.WriteString(o.line)
+			b.WriteByte('\n')
+		}
+
+		i = stop
+	}
+
+	return b.String()
+}
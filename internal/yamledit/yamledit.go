@@ -0,0 +1,401 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+// Package yamledit performs targeted, line-based edits of YAML documents.
+// Unlike a round-trip through a YAML library, comments, quoting, indentation
+// and blank lines of the original document are preserved such that the
+// resulting change can be reviewed as a minimal diff.
+package yamledit
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// entryRe matches a block mapping entry with a scalar value, optionally as the
+// first entry of a sequence item, e.g. "  - name: bug".
+var entryRe = regexp.MustCompile(`^(\s*)(-\s+)?([A-Za-z0-9_\-]+):(\s*)(.*)$`)
+
+// entry is a single "key: value" line of a YAML document.
+type entry struct {
+	// column is the position of the key on the line.
+	column int
+	// item is set when the entry is the first of a sequence item.
+	item  bool
+	key   string
+	value string
+	// quote is the quoting character of the value, if any.
+	quote byte
+	// prefix is everything on the line up to and including the separator
+	// between the key and the value.
+	prefix string
+	// trailer is everything on the line after the value, e.g. a comment.
+	trailer string
+}
+
+// parseEntry parses a line of a YAML document into an entry.
+func parseEntry(line string) (entry, bool) {
+	m := entryRe.FindStringSubmatch(line)
+	if m == nil {
+		return entry{}, false
+	}
+
+	e := entry{
+		column: len(m[1]) + len(m[2]),
+		item:   m[2] != "",
+		key:    m[3],
+		prefix: m[1] + m[2] + m[3] + ":" + m[4],
+	}
+
+	rest := m[5]
+
+	switch {
+	case strings.HasPrefix(rest, `"`):
+		if end := strings.Index(rest[1:], `"`); end >= 0 {
+			if v, err := strconv.Unquote(rest[:end+2]); err == nil {
+				e.value = v
+			} else {
+				e.value = rest[1 : end+1]
+			}
+			e.quote = '"'
+			e.trailer = rest[end+2:]
+			return e, true
+		}
+	case strings.HasPrefix(rest, "'"):
+		if end := strings.Index(rest[1:], "'"); end >= 0 {
+			e.value = rest[1 : end+1]
+			e.quote = '\''
+			e.trailer = rest[end+2:]
+			return e, true
+		}
+	}
+
+	if i := strings.Index(rest, " #"); i >= 0 {
+		e.value = strings.TrimSpace(rest[:i])
+		e.trailer = rest[len(strings.TrimRight(rest[:i], " ")):]
+	} else {
+		e.value = strings.TrimSpace(rest)
+		e.trailer = rest[len(strings.TrimRight(rest, " ")):]
+	}
+
+	return e, true
+}
+
+// needsQuote returns whether the value must be quoted to be read back as the
+// same plain string.
+func needsQuote(value string) bool {
+	if value == "" || strings.TrimSpace(value) != value {
+		return true
+	}
+
+	if strings.ContainsAny(value[:1], "#&*!|>'\"%@`{}[],?:-") {
+		return true
+	}
+
+	switch strings.ToLower(value) {
+	case "~", "null", "true", "false", "yes", "no", "on", "off":
+		return true
+	}
+
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return true
+	}
+
+	return strings.Contains(value, ": ") || strings.Contains(value, " #")
+}
+
+// format returns the value quoted with the provided quoting character, or
+// double-quoted if it is unquoted but would otherwise be misinterpreted.
+func format(value string, quote byte) string {
+	switch {
+	case quote == '\'':
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case quote == '"' || needsQuote(value):
+		return strconv.Quote(value)
+	}
+
+	return value
+}
+
+// lines splits the document into lines without their line endings.
+func lines(src []byte) []string {
+	return strings.Split(string(src), "\n")
+}
+
+// indentation returns the number of leading spaces of the line.
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// isBlank returns whether the line contains neither content nor a comment.
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// isDocumentMarker returns whether the line separates or ends documents of a
+// multi-document stream.
+func isDocumentMarker(line string) bool {
+	return strings.HasPrefix(line, "---") || strings.HasPrefix(line, "...")
+}
+
+// FindScalars returns the (zero-indexed) line numbers of all entries in the
+// document with the provided key and value, regardless of how the value is
+// quoted.
+func FindScalars(src []byte, key, value string) []int {
+	var found []int
+
+	for i, line := range lines(src) {
+		if e, ok := parseEntry(line); ok && e.key == key && e.value == value {
+			found = append(found, i)
+		}
+	}
+
+	return found
+}
+
+// ReplaceScalar replaces the value of the entry on the provided line whilst
+// retaining its quoting style and any trailing comment.
+func ReplaceScalar(src []byte, line int, value string) []byte {
+	ls := lines(src)
+	if line < 0 || line >= len(ls) {
+		return src
+	}
+
+	e, ok := parseEntry(ls[line])
+	if !ok {
+		return src
+	}
+
+	ls[line] = e.prefix + format(value, e.quote) + e.trailer
+
+	return []byte(strings.Join(ls, "\n"))
+}
+
+// mappingBounds returns the range of lines [start, end) of the block mapping
+// which contains the entry on the provided line.
+func mappingBounds(ls []string, line int, column int) (int, int) {
+	start := line
+	for start > 0 {
+		if e, ok := parseEntry(ls[start]); ok && e.column == column && e.item {
+			break
+		}
+
+		prev := ls[start-1]
+		if isDocumentMarker(prev) {
+			break
+		}
+
+		if !isBlank(prev) && indentation(prev) < column {
+			if e, ok := parseEntry(prev); !ok || e.column != column || !e.item {
+				break
+			}
+		}
+
+		start--
+	}
+
+	end := line + 1
+	for end < len(ls) {
+		if isDocumentMarker(ls[end]) || (!isBlank(ls[end]) && indentation(ls[end]) < column) {
+			break
+		}
+
+		end++
+	}
+
+	// Trailing blank lines belong to whatever follows the mapping.
+	for end > line+1 && isBlank(ls[end-1]) {
+		end--
+	}
+
+	return start, end
+}
+
+// SetSibling sets the value of the entry with the provided key in the same
+// block mapping as the entry on the provided line.  If the mapping has no such
+// entry, it is appended to the end of the mapping.
+func SetSibling(src []byte, line int, key, value string) []byte {
+	ls := lines(src)
+	if line < 0 || line >= len(ls) {
+		return src
+	}
+
+	e, ok := parseEntry(ls[line])
+	if !ok {
+		return src
+	}
+
+	start, end := mappingBounds(ls, line, e.column)
+
+	for i := start; i < end; i++ {
+		if s, ok := parseEntry(ls[i]); ok && s.column == e.column && s.key == key {
+			return ReplaceScalar(src, i, value)
+		}
+	}
+
+	entry := strings.Repeat(" ", e.column) + key + ": " + format(value, 0)
+
+	ls = append(ls[:end], append([]string{entry}, ls[end:]...)...)
+
+	return []byte(strings.Join(ls, "\n"))
+}
+
+// SetBlock sets the entry with the provided key in the same block mapping as
+// the entry on the provided line to the nested block, e.g. a mapping rendered
+// by a YAML library, which is indented below the key.  An existing entry with
+// the key is replaced as a whole, including its comments.  If the mapping has
+// no such entry, it is appended to the end of the mapping.
+func SetBlock(src []byte, line int, key string, block []byte) []byte {
+	ls := lines(src)
+	if line < 0 || line >= len(ls) {
+		return src
+	}
+
+	e, ok := parseEntry(ls[line])
+	if !ok {
+		return src
+	}
+
+	entry := []string{strings.Repeat(" ", e.column) + key + ":"}
+	for _, l := range lines([]byte(strings.TrimRight(string(block), "\n"))) {
+		if isBlank(l) {
+			entry = append(entry, "")
+		} else {
+			entry = append(entry, strings.Repeat(" ", e.column+2)+l)
+		}
+	}
+
+	start, end := mappingBounds(ls, line, e.column)
+
+	for i := start; i < end; i++ {
+		s, ok := parseEntry(ls[i])
+		if !ok || s.column != e.column || s.key != key {
+			continue
+		}
+
+		// The existing entry spans all following lines which are indented
+		// deeper than its key, or, for sequences, at the same column.
+		j := i + 1
+		for j < end && (isBlank(ls[j]) || indentation(ls[j]) > e.column || (indentation(ls[j]) == e.column && strings.HasPrefix(strings.TrimSpace(ls[j]), "- "))) {
+			j++
+		}
+
+		for j > i+1 && isBlank(ls[j-1]) {
+			j--
+		}
+
+		ls = append(ls[:i], append(entry, ls[j:]...)...)
+
+		return []byte(strings.Join(ls, "\n"))
+	}
+
+	ls = append(ls[:end], append(entry, ls[end:]...)...)
+
+	return []byte(strings.Join(ls, "\n"))
+}
+
+// FindScalarsFold is like FindScalars but compares the values without regard
+// to case, e.g. to find GitHub logins.
+func FindScalarsFold(src []byte, key, value string) []int {
+	var found []int
+
+	for i, line := range lines(src) {
+		if e, ok := parseEntry(line); ok && e.key == key && strings.EqualFold(e.value, value) {
+			found = append(found, i)
+		}
+	}
+
+	return found
+}
+
+// itemBounds returns the range of lines [start, end) of the sequence item
+// which contains the entry on the provided line, or false if the entry is not
+// part of a sequence item.
+func itemBounds(ls []string, line int) (int, int, bool) {
+	if line < 0 || line >= len(ls) {
+		return 0, 0, false
+	}
+
+	e, ok := parseEntry(ls[line])
+	if !ok {
+		return 0, 0, false
+	}
+
+	start, end := mappingBounds(ls, line, e.column)
+	if s, ok := parseEntry(ls[start]); !ok || !s.item {
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
+
+// ParentKey returns the key of the sequence which the item containing the
+// entry on the provided line is part of, e.g. "maintainers", or an empty
+// string if the entry is not part of a sequence item.
+func ParentKey(src []byte, line int) string {
+	ls := lines(src)
+
+	start, _, ok := itemBounds(ls, line)
+	if !ok {
+		return ""
+	}
+
+	dash := indentation(ls[start])
+
+	for i := start - 1; i >= 0; i-- {
+		if isBlank(ls[i]) || strings.HasPrefix(strings.TrimSpace(ls[i]), "#") {
+			continue
+		}
+
+		// Skip preceding items of the same sequence and their contents.
+		if indentation(ls[i]) > dash {
+			continue
+		}
+
+		e, ok := parseEntry(ls[i])
+		if !ok {
+			return ""
+		}
+
+		if e.item && indentation(ls[i]) == dash {
+			continue
+		}
+
+		return e.key
+	}
+
+	return ""
+}
+
+// RemoveItem removes the sequence item which contains the entry on the
+// provided line, e.g. a user of a team, leaving the rest of the document
+// untouched.
+func RemoveItem(src []byte, line int) []byte {
+	ls := lines(src)
+
+	start, end, ok := itemBounds(ls, line)
+	if !ok {
+		return src
+	}
+
+	// Do not leave two blank lines behind when the item was separated from
+	// its neighbours by blank lines, nor any at the end of the document.
+	if start > 0 && isBlank(ls[start-1]) {
+		for end < len(ls)-1 && isBlank(ls[end]) {
+			end++
+		}
+
+		if end >= len(ls)-1 {
+			for start > 0 && isBlank(ls[start-1]) {
+				start--
+			}
+		}
+	}
+
+	ls = append(ls[:start], ls[end:]...)
+
+	return []byte(strings.Join(ls, "\n"))
+}
@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package yamledit
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testDoc = `# Labels describing bugs
+labels:
+  - name: bug # the generic one
+    description: Something is broken
+    color: "#ed5139"
+
+  - name: 'bug/fix'
+    description: This PR fixes a bug
+    apply_on_title_match:
+      - "^fix"
+`
+
+func TestFindScalars(t *testing.T) {
+	if got, want := FindScalars([]byte(testDoc), "name", "bug"), []int{2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FindScalars() = %v, want %v", got, want)
+	}
+
+	if got, want := FindScalars([]byte(testDoc), "name", "bug/fix"), []int{6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FindScalars() = %v, want %v", got, want)
+	}
+
+	if got := FindScalars([]byte(testDoc), "name", "kind/bug"); len(got) != 0 {
+		t.Errorf("FindScalars() = %v, want none", got)
+	}
+}
+
+func TestReplaceScalar(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  int
+		value string
+		want  string
+	}{
+		{
+			name:  "plain value keeps comment",
+			line:  2,
+			value: "kind/bug",
+			want:  "  - name: kind/bug # the generic one",
+		},
+		{
+			name:  "single-quoted value keeps quotes",
+			line:  6,
+			value: "kind/fix",
+			want:  "  - name: 'kind/fix'",
+		},
+		{
+			name:  "double-quoted value keeps quotes",
+			line:  4,
+			value: "#000000",
+			want:  `    color: "#000000"`,
+		},
+		{
+			name:  "plain value is quoted when necessary",
+			line:  3,
+			value: "yes",
+			want:  `    description: "yes"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lines(ReplaceScalar([]byte(testDoc), tt.line, tt.value))
+			if got[tt.line] != tt.want {
+				t.Errorf("ReplaceScalar() line = %q, want %q", got[tt.line], tt.want)
+			}
+
+			if len(got) != len(lines([]byte(testDoc))) {
+				t.Errorf("ReplaceScalar() changed the number of lines")
+			}
+		})
+	}
+}
+
+func TestSetSibling(t *testing.T) {
+	t.Run("replaces existing entry", func(t *testing.T) {
+		got := string(SetSibling([]byte(testDoc), 2, "color", "#000000"))
+		want := `# Labels describing bugs
+labels:
+  - name: bug # the generic one
+    description: Something is broken
+    color: "#000000"
+
+  - name: 'bug/fix'
+    description: This PR fixes a bug
+    apply_on_title_match:
+      - "^fix"
+`
+		if got != want {
+			t.Errorf("SetSibling() =\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("appends missing entry", func(t *testing.T) {
+		got := string(SetSibling([]byte(testDoc), 6, "color", "#4bcc7a"))
+		want := `# Labels describing bugs
+labels:
+  - name: bug # the generic one
+    description: Something is broken
+    color: "#ed5139"
+
+  - name: 'bug/fix'
+    description: This PR fixes a bug
+    apply_on_title_match:
+      - "^fix"
+    color: "#4bcc7a"
+`
+		if got != want {
+			t.Errorf("SetSibling() =\n%s\nwant:\n%s", got, want)
+		}
+	})
+}
+
+func TestSetBlock(t *testing.T) {
+	const repos = `name: unikraft
+type: core
+---
+name: lib-lwip # the network stack
+freeze:
+  # frozen for the last release
+  until: "2024-01-31"
+  allowed_labels:
+  - release-blocker
+language: de
+`
+
+	block := []byte("until: \"2024-06-30\"\nmessage: Frozen\n")
+
+	t.Run("appends missing entry", func(t *testing.T) {
+		got := string(SetBlock([]byte(repos), 0, "freeze", block))
+		want := `name: unikraft
+type: core
+freeze:
+  until: "2024-06-30"
+  message: Frozen
+---
+name: lib-lwip # the network stack
+freeze:
+  # frozen for the last release
+  until: "2024-01-31"
+  allowed_labels:
+  - release-blocker
+language: de
+`
+		if got != want {
+			t.Errorf("SetBlock() =\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("replaces existing entry", func(t *testing.T) {
+		got := string(SetBlock([]byte(repos), 3, "freeze", block))
+		want := `name: unikraft
+type: core
+---
+name: lib-lwip # the network stack
+freeze:
+  until: "2024-06-30"
+  message: Frozen
+language: de
+`
+		if got != want {
+			t.Errorf("SetBlock() =\n%s\nwant:\n%s", got, want)
+		}
+	})
+}
+
+func TestDiff(t *testing.T) {
+	old := []byte("a\nb\nc\nd\ne\nf\ng\nh\n")
+	new := []byte("a\nb\nc\nd\nE\nf\ng\nh\n")
+
+	want := `--- a/labels.yaml
++++ b/labels.yaml
+@@ -2,7 +2,7 @@
+ b
+ c
+ d
+-e
++E
+ f
+ g
+ h
+`
+
+	if got := Diff("labels.yaml", old, new); got != want {
+		t.Errorf("Diff() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := Diff("labels.yaml", old, old); got != "" {
+		t.Errorf("Diff() of identical documents = %q, want empty", got)
+	}
+}
+
+const testTeam = `name: maintainers-boot
+maintainers:
+  - name: Jane Doe
+    github: Jane
+  - github: bob # lead
+
+reviewers:
+- github: jane
+  discord: jane#1234
+code_review:
+  never_assign:
+    - github: alice
+
+    - github: jane
+`
+
+func TestParentKey(t *testing.T) {
+	var got []string
+	for _, line := range FindScalarsFold([]byte(testTeam), "github", "jane") {
+		got = append(got, ParentKey([]byte(testTeam), line))
+	}
+
+	if want := []string{"maintainers", "reviewers", "never_assign"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParentKey() = %v, want %v", got, want)
+	}
+
+	if got := ParentKey([]byte(testTeam), 0); got != "" {
+		t.Errorf("ParentKey() of a plain entry = %q, want none", got)
+	}
+}
+
+func TestRemoveItem(t *testing.T) {
+	src := []byte(testTeam)
+
+	found := FindScalarsFold(src, "github", "jane")
+	for i := len(found) - 1; i >= 0; i-- {
+		src = RemoveItem(src, found[i])
+	}
+
+	want := `name: maintainers-boot
+maintainers:
+  - github: bob # lead
+
+reviewers:
+code_review:
+  never_assign:
+    - github: alice
+`
+	if string(src) != want {
+		t.Errorf("RemoveItem() =\n%s\nwant:\n%s", src, want)
+	}
+
+	if got := RemoveItem([]byte(testTeam), 0); string(got) != testTeam {
+		t.Errorf("RemoveItem() of a plain entry changed the document")
+	}
+}
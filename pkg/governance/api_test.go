@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package governance
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the listing of the API in testdata/api.txt")
+
+// internalType matches the qualified name of a type of an internal package.
+var internalType = regexp.MustCompile(`github\.com/unikraft/governance/internal/[\w/]+\.\w+`)
+
+// api returns a listing of every exported identifier of the package in the
+// current directory, one per line, including the exported fields and methods
+// of its types such that changes to re-exported internal types are detected.
+// Internal types are listed by the name of their alias in the package.
+func api(t *testing.T) string {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var files []*ast.File
+	for _, file := range pkgs["governance"].Files {
+		files = append(files, file)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("github.com/unikraft/governance/pkg/governance", fset, files, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	qualifier := types.RelativeTo(pkg)
+
+	aliases := map[string]string{}
+	for _, name := range pkg.Scope().Names() {
+		obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok || !obj.Exported() || !obj.IsAlias() {
+			continue
+		}
+
+		aliases[types.TypeString(types.Unalias(obj.Type()), qualifier)] = name
+	}
+
+	public := func(listing string) string {
+		return internalType.ReplaceAllStringFunc(listing, func(name string) string {
+			if alias, ok := aliases[name]; ok {
+				return alias
+			}
+
+			return name
+		})
+	}
+
+	typeString := func(typ types.Type) string {
+		return public(types.TypeString(typ, qualifier))
+	}
+
+	var lines []string
+	for _, name := range pkg.Scope().Names() {
+		obj := pkg.Scope().Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+
+		if _, ok := obj.(*types.TypeName); !ok {
+			lines = append(lines, public(types.ObjectString(obj, qualifier)))
+			continue
+		}
+
+		// The fields and methods of structs are listed separately.
+		switch underlying := obj.Type().Underlying().(type) {
+		case *types.Struct:
+			lines = append(lines, fmt.Sprintf("type %s struct", name))
+		case *types.Signature:
+			lines = append(lines, fmt.Sprintf("type %s func", name))
+		default:
+			lines = append(lines, fmt.Sprintf("type %s %s", name, typeString(underlying)))
+		}
+
+		if st, ok := obj.Type().Underlying().(*types.Struct); ok {
+			for i := 0; i < st.NumFields(); i++ {
+				if field := st.Field(i); field.Exported() {
+					lines = append(lines, fmt.Sprintf("field %s.%s %s", name, field.Name(), typeString(field.Type())))
+				}
+			}
+		}
+
+		mset := types.NewMethodSet(types.NewPointer(obj.Type()))
+		for i := 0; i < mset.Len(); i++ {
+			if method := mset.At(i).Obj(); method.Exported() {
+				sig := typeString(method.Type())
+				lines = append(lines, fmt.Sprintf("method %s.%s%s", name, method.Name(), strings.TrimPrefix(sig, "func")))
+			}
+		}
+	}
+
+	sort.Strings(lines)
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// TestAPICompatibility guards the stability of the public API by comparing it
+// against testdata/api.txt and ensures that it does not expose internal types.
+// Intended changes are recorded with -update and must follow semantic
+// versioning.
+func TestAPICompatibility(t *testing.T) {
+	golden := filepath.Join("testdata", "api.txt")
+
+	got := api(t)
+
+	// Internal types cannot be named by users of the package unless they are
+	// aliased by it.
+	for _, line := range strings.Split(got, "\n") {
+		if internalType.MatchString(line) {
+			t.Errorf("public API exposes an internal type without an alias: %s", line)
+		}
+	}
+
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != string(want) {
+		t.Errorf("public API differs from %s, run with -update if the change is intended and compatible:\n%s", golden, diff(string(want), got))
+	}
+}
+
+// diff returns the lines which were removed from or added to the listing.
+func diff(want, got string) string {
+	wantLines := map[string]bool{}
+	for _, line := range strings.Split(want, "\n") {
+		wantLines[line] = true
+	}
+
+	gotLines := map[string]bool{}
+	for _, line := range strings.Split(got, "\n") {
+		gotLines[line] = true
+	}
+
+	var b strings.Builder
+	for _, line := range strings.Split(want, "\n") {
+		if !gotLines[line] {
+			fmt.Fprintf(&b, "- %s\n", line)
+		}
+	}
+	for _, line := range strings.Split(got, "\n") {
+		if !wantLines[line] {
+			fmt.Fprintf(&b, "+ %s\n", line)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package governance
+
+import (
+	"context"
+	"time"
+
+	"github.com/unikraft/governance/internal/ghapi"
+)
+
+var (
+	// ErrReadOnly is returned by any request of a read-only client which would
+	// modify state on GitHub.
+	ErrReadOnly = ghapi.ErrReadOnly
+
+	// ErrInsufficientRateLimit is returned when the rate limit of the client
+	// does not allow the requests of an operation.
+	ErrInsufficientRateLimit = ghapi.ErrInsufficientRateLimit
+
+	// ErrTeamNotVisible is returned when a team cannot be read with the
+	// credentials of the client.
+	ErrTeamNotVisible = ghapi.ErrTeamNotVisible
+
+	// ErrAppRequired is returned by operations which are only available when
+	// the client authenticates as a GitHub App.
+	ErrAppRequired = ghapi.ErrAppRequired
+)
+
+// ClientConfig holds the settings of a Client.  The zero value of every field
+// other than Token leaves the respective default in use.
+type ClientConfig struct {
+	// Token is the personal access token of the client.
+	Token string
+
+	// Endpoint is the URL of a GitHub Enterprise Server, or empty for
+	// github.com.
+	Endpoint string
+
+	// SkipSSL disables the verification of the certificate of the endpoint.
+	SkipSSL bool
+
+	// ReadOnly rejects every request which could modify state on GitHub.
+	ReadOnly bool
+
+	// Timeout is the maximum duration of a single request.
+	Timeout time.Duration
+
+	// MaxAttempts is how often a request which failed transiently is
+	// attempted before giving up, and RetryDelay the delay before its first
+	// retry.
+	MaxAttempts int
+	RetryDelay  time.Duration
+
+	// APIVersion is the version of the GitHub REST API, e.g. "2022-11-28".
+	APIVersion string
+
+	// AppID, AppInstallationID and AppPrivateKey, the path to the PEM-encoded
+	// key of the app, authenticate as the installation of a GitHub App instead
+	// of with Token.
+	AppID             int64
+	AppInstallationID int64
+	AppPrivateKey     string
+}
+
+// Client accesses the GitHub API on behalf of the other functions of this
+// package.
+type Client struct {
+	client *ghapi.GithubClient
+}
+
+// NewClient returns a client configured by the provided settings.
+func NewClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
+	opts := []ghapi.GithubClientOption{
+		ghapi.WithReadOnly(cfg.ReadOnly),
+		ghapi.WithAPIVersion(cfg.APIVersion),
+		ghapi.WithApp(cfg.AppID, cfg.AppInstallationID, cfg.AppPrivateKey),
+	}
+
+	if cfg.Timeout > 0 {
+		opts = append(opts, ghapi.WithTimeout(cfg.Timeout))
+	}
+
+	if cfg.MaxAttempts > 0 || cfg.RetryDelay > 0 {
+		attempts, delay := ghapi.DefaultRetryAttempts, ghapi.DefaultRetryDelay
+		if cfg.MaxAttempts > 0 {
+			attempts = cfg.MaxAttempts
+		}
+		if cfg.RetryDelay > 0 {
+			delay = cfg.RetryDelay
+		}
+
+		opts = append(opts, ghapi.WithRetry(attempts, delay))
+	}
+
+	client, err := ghapi.NewGithubClient(ctx, cfg.Token, cfg.SkipSSL, cfg.Endpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{client: client}, nil
+}
+
+// github returns the underlying client, or nil if the client is nil such
+// that functions which accept a nil client do not need to check it.
+func (c *Client) github() *ghapi.GithubClient {
+	if c == nil {
+		return nil
+	}
+
+	return c.client
+}
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+// Package governance is the public Go API of the Unikraft governance tooling
+// which governctl is built upon.  It allows other programs, e.g. bots or CI
+// services, to load the definitions of teams, repositories and labels and to
+// evaluate the merge requirements of a pull request without running governctl.
+//
+// Unlike the packages under internal/, which may change at any time, the
+// identifiers of this package are covered by semantic versioning: every
+// exported identifier listed in testdata/api.txt, including the exported
+// fields and methods of the types it re-exports, is stable.  Removing or
+// changing any of them requires a new major version of the module, whereas
+// additions are made in minor versions.  The listing is checked by
+// TestAPICompatibility and regenerated with:
+//
+//	go test ./pkg/governance -run TestAPICompatibility -update
+//
+// Nothing in this package reads the configuration of governctl, e.g. from the
+// environment or from its context; every setting is passed explicitly.
+package governance
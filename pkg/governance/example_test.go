@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package governance_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/unikraft/governance/pkg/governance"
+)
+
+func ExampleLoadTeams() {
+	// The teams are only read, such that no client is needed.
+	teams, err := governance.LoadTeams(nil, "unikraft", "testdata/teams.yaml")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, team := range teams {
+		fmt.Println(team.Fullname(), len(team.Maintainers), len(team.Reviewers))
+	}
+
+	// Output:
+	// sig-arch 1 0
+	// sig-arch-arm 0 1
+}
+
+func ExampleEvaluate() {
+	ctx := context.Background()
+
+	client, err := governance.NewClient(ctx, governance.ClientConfig{
+		Token:    os.Getenv("GITHUB_TOKEN"),
+		ReadOnly: true,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	rules, err := governance.LoadRuleset("testdata/rules.yaml")
+	if err != nil {
+		panic(err)
+	}
+
+	verdict, err := governance.Evaluate(ctx, client, "unikraft", "unikraft", 1000, rules)
+	if err != nil {
+		fmt.Println("not mergable:", err)
+		return
+	}
+
+	fmt.Println("mergable:", verdict.Mergable(), verdict.Unmet)
+}
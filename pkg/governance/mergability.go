@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package governance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/unikraft/governance/internal/ghpr"
+	"github.com/unikraft/governance/internal/hook"
+)
+
+type (
+	// Verdict is the outcome of evaluating the merge requirements of a pull
+	// request, see Evaluate.
+	Verdict = ghpr.MergeVerdict
+
+	// Attestation is a comment or review which qualified as an approval or
+	// review of a pull request.
+	Attestation = ghpr.Attestation
+
+	// Ruleset is the full set of merge requirements of a repository, as read
+	// from the file passed to `governctl pr check mergable --rules`.
+	Ruleset = ghpr.Ruleset
+
+	// Option customises an evaluation beyond its ruleset.
+	Option = ghpr.PullRequestMergableOption
+
+	// Hook is an executable which a ruleset runs at a point of the merge.
+	Hook = hook.Hook
+
+	// HookPoint is when a hook is run.
+	HookPoint = hook.Point
+)
+
+// LoadRuleset reads and validates the ruleset in the provided YAML file.
+func LoadRuleset(file string) (*Ruleset, error) {
+	return ghpr.NewRulesetFromFile(file)
+}
+
+// WithAt evaluates the pull request as it was at the provided time instead of
+// now.
+func WithAt(at time.Time) Option {
+	return ghpr.WithAt(at)
+}
+
+// WithMergeableRetry sets how often and how long apart the pull request is
+// fetched again whilst GitHub has not yet determined whether it has
+// conflicts.
+func WithMergeableRetry(attempts int, delay time.Duration) Option {
+	return ghpr.WithMergeableRetry(attempts, delay)
+}
+
+// Evaluate evaluates the merge requirements of the ruleset, or the defaults if
+// it is nil, against the pull request with the provided number.  An error is
+// returned if the pull request does not meet the prerequisites of the ruleset,
+// e.g. its state or labels, or could not be evaluated, whereas unmet
+// requirements are reported by the verdict.  The repository is not cloned.
+func Evaluate(ctx context.Context, client *Client, org, repo string, number int, rules *Ruleset, opts ...Option) (*Verdict, error) {
+	if client == nil {
+		return nil, fmt.Errorf("cannot evaluate pull request without a client")
+	}
+
+	var mopts []Option
+	if rules != nil {
+		if err := rules.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid ruleset: %w", err)
+		}
+
+		mopts = rules.Options()
+	}
+
+	return ghpr.NewPullRequestFromAPI(client.github(), org, repo, number).Verdict(ctx, append(mopts, opts...)...)
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package governance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number":1,"state":"open","draft":false,"assignees":[{"login":"jane"}]}`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"body":"Approved-by: Jane Doe <jane@unikraft.io>","user":{"login":"jane"}}]`)
+	})
+	mux.HandleFunc("/api/v3/repos/unikraft/unikraft/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(context.Background(), ClientConfig{
+		Token:       "token",
+		Endpoint:    srv.URL,
+		ReadOnly:    true,
+		MaxAttempts: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := LoadRuleset("testdata/rules.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verdict, err := Evaluate(context.Background(), client, "unikraft", "unikraft", 1, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verdict.Mergable() {
+		t.Errorf("Mergable() = true, want false without a review")
+	}
+
+	if verdict.Approvals != 1 || verdict.Reviews != 0 {
+		t.Errorf("approvals, reviews = %d, %d, want 1, 0", verdict.Approvals, verdict.Reviews)
+	}
+
+	if _, err := Evaluate(context.Background(), nil, "unikraft", "unikraft", 1, nil); err == nil {
+		t.Error("Evaluate() without client expected error")
+	}
+
+	if _, err := Evaluate(context.Background(), client, "unikraft", "unikraft", 1, &Ruleset{BotPolicy: "always"}); err == nil {
+		t.Error("Evaluate() with invalid ruleset expected error")
+	}
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022, Unikraft GmbH and The Unikraft Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package governance
+
+import (
+	"github.com/unikraft/governance/internal/ghapi"
+	"github.com/unikraft/governance/internal/label"
+	"github.com/unikraft/governance/internal/repo"
+	"github.com/unikraft/governance/internal/team"
+	"github.com/unikraft/governance/internal/user"
+)
+
+type (
+	// Team is a team of the organisation as defined in the teams directory,
+	// consisting of its maintainers, reviewers and members.
+	Team = team.Team
+
+	// TeamType is the kind of a team, e.g. a team of a library.
+	TeamType = team.TeamType
+
+	// TeamPrivacy is the visibility of a team on GitHub.
+	TeamPrivacy = team.TeamPrivacy
+
+	// TeamMembershipChange lists the members which synchronising a team with
+	// GitHub adds to or removes from it, see Team.Plan.
+	TeamMembershipChange = ghapi.TeamMembershipChange
+
+	// CodeReview configures how reviewers of a team are assigned to pull
+	// requests.
+	CodeReview = team.CodeReview
+
+	// CodeReviewAlgorithm is how reviewers are picked from a team.
+	CodeReviewAlgorithm = team.CodeReviewAlgorithm
+
+	// Repository is a repository of the organisation as defined in the repos
+	// directory.
+	Repository = repo.Repository
+
+	// RepoType is the kind of a repository, e.g. a library.
+	RepoType = repo.RepoType
+
+	// PermissionLevel is the permission which teams are granted on a
+	// repository.
+	PermissionLevel = repo.RepoPermissionLevel
+
+	// Freeze restricts which pull requests of a repository are merged.
+	Freeze = repo.Freeze
+
+	// Label is a label of the organisation as defined in the labels directory,
+	// together with the rules which apply it to pull requests.
+	Label = label.Label
+
+	// Duration is the delay after which a label is applied or removed, which
+	// is written in YAML as a Go duration string or a number of days, e.g.
+	// "3d".
+	Duration = label.Duration
+
+	// LabelEvent is the event of a pull request from which the delay of a
+	// label is counted.
+	LabelEvent = label.Event
+
+	// LabelSnapshot is the state of a pull request against which the delays
+	// of a label are evaluated, see Label.ApplyDue.
+	LabelSnapshot = label.Snapshot
+
+	// User is a person which is part of a team.
+	User = user.User
+
+	// UserRole is the role of a user within a team.
+	UserRole = user.UserRole
+)
+
+// The roles of a user within a team.
+const (
+	RoleAdmin      = user.Admin
+	RoleMaintainer = user.Maintainer
+	RoleReviewer   = user.Reviewer
+	RoleMember     = user.Member
+)
+
+// LoadTeams returns every team of the organisation defined at the provided
+// path, which is either a directory with one or more files per team or a
+// single file with one YAML document per team.  The client is only used when
+// synchronising the teams with GitHub and may be nil.
+func LoadTeams(client *Client, org, path string) ([]*Team, error) {
+	return team.NewListOfTeamsFromPath(client.github(), org, path)
+}
+
+// LoadRepositories returns every repository of the organisation defined at
+// the provided path.  The client may be nil.
+func LoadRepositories(client *Client, org, path string) ([]*Repository, error) {
+	return repo.NewListOfReposFromPath(client.github(), org, path)
+}
+
+// LoadLabels returns every label of the organisation defined at the provided
+// path.  The client may be nil.
+func LoadLabels(client *Client, org, path string) ([]Label, error) {
+	return label.NewListOfLabelsFromPath(client.github(), org, path)
+}